@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gliderlabs/ssh"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,6 +30,7 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"tailscale.com/net/speedtest"
 	"tailscale.com/tailcfg"
@@ -37,8 +39,10 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/clistat"
+	"github.com/coder/coder/v2/agent/agentactivity"
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentrecording"
 	"github.com/coder/coder/v2/agent/agentscripts"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
@@ -95,8 +99,8 @@ type Options struct {
 }
 
 type Client interface {
-	ConnectRPC24(ctx context.Context) (
-		proto.DRPCAgentClient24, tailnetproto.DRPCTailnetClient24, error,
+	ConnectRPC25(ctx context.Context) (
+		proto.DRPCAgentClient25, tailnetproto.DRPCTailnetClient25, error,
 	)
 	RewriteDERPMap(derpMap *tailcfg.DERPMap)
 }
@@ -177,6 +181,8 @@ func New(options Options) Agent {
 		lifecycleReported:                  make(chan codersdk.WorkspaceAgentLifecycle, 1),
 		lifecycleStates:                    []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
 		reportConnectionsUpdate:            make(chan struct{}, 1),
+		sessionRecordingsUpdate:            make(chan struct{}, 1),
+		devcontainerAppsUpdate:             make(chan struct{}, 1),
 		ignorePorts:                        options.IgnorePorts,
 		portCacheDuration:                  options.PortCacheDuration,
 		reportMetadataInterval:             options.ReportMetadataInterval,
@@ -253,6 +259,8 @@ type agent struct {
 	sshServer                          *agentssh.Server
 	sshMaxTimeout                      time.Duration
 	blockFileTransfer                  bool
+	// activity tracks real user activity, which is reported with the stats.
+	activity *agentactivity.Tracker
 
 	lifecycleUpdate            chan struct{}
 	lifecycleReported          chan codersdk.WorkspaceAgentLifecycle
@@ -264,6 +272,13 @@ type agent struct {
 	reportConnectionsMu     sync.Mutex
 	reportConnections       []*proto.ReportConnectionRequest
 
+	sessionRecordingsUpdate chan struct{}
+	sessionRecordingsMu     sync.Mutex
+	sessionRecordings       []*sessionRecording
+
+	devcontainerAppsUpdate chan struct{}
+	devcontainerApps       atomic.Pointer[proto.UpdateDevcontainerAppsRequest]
+
 	logSender *agentsdk.LogSender
 
 	prometheusRegistry *prometheus.Registry
@@ -285,6 +300,7 @@ func (a *agent) TailnetConn() *tailnet.Conn {
 
 func (a *agent) init() {
 	// pass the "hard" context because we explicitly close the SSH server as part of graceful shutdown.
+	a.activity = agentactivity.NewTracker(nil)
 	sshSrv, err := agentssh.NewServer(a.hardCtx, a.logger.Named("ssh-server"), a.prometheusRegistry, a.filesystem, a.execer, &agentssh.Config{
 		MaxTimeout:          a.sshMaxTimeout,
 		MOTDFile:            func() string { return a.manifest.Load().MOTDFile },
@@ -293,22 +309,14 @@ func (a *agent) init() {
 		WorkingDirectory:    func() string { return a.manifest.Load().Directory },
 		BlockFileTransfer:   a.blockFileTransfer,
 		ReportConnection: func(id uuid.UUID, magicType agentssh.MagicSessionType, ip string) func(code int, reason string) {
-			var connectionType proto.Connection_Type
-			switch magicType {
-			case agentssh.MagicSessionTypeSSH:
-				connectionType = proto.Connection_SSH
-			case agentssh.MagicSessionTypeVSCode:
-				connectionType = proto.Connection_VSCODE
-			case agentssh.MagicSessionTypeJetBrains:
-				connectionType = proto.Connection_JETBRAINS
-			case agentssh.MagicSessionTypeUnknown:
-				connectionType = proto.Connection_TYPE_UNSPECIFIED
-			default:
-				a.logger.Error(a.hardCtx, "unhandled magic session type when reporting connection", slog.F("magic_type", magicType))
-				connectionType = proto.Connection_TYPE_UNSPECIFIED
-			}
-
-			return a.reportConnection(id, connectionType, ip)
+			return a.reportConnection(id, a.sshConnectionType(magicType), ip)
+		},
+		RecordSession: func(id uuid.UUID, magicType agentssh.MagicSessionType, header agentrecording.Header) *agentrecording.Recorder {
+			return a.recordSession(id, a.sshConnectionType(magicType), header)
+		},
+		ReportActivity: a.activity.Touch,
+		AllowForward: func(ctx ssh.Context, network, address string) bool {
+			return a.allowForward(network, address, ctx.RemoteAddr().String())
 		},
 
 		ExperimentalDevContainersEnabled: a.experimentalDevcontainersEnabled,
@@ -341,11 +349,33 @@ func (a *agent) init() {
 		a.reconnectingPTYTimeout,
 		func(s *reconnectingpty.Server) {
 			s.ExperimentalDevcontainersEnabled = a.experimentalDevcontainersEnabled
+			s.RecordSession = func(id uuid.UUID, header agentrecording.Header) *agentrecording.Recorder {
+				return a.recordSession(id, proto.Connection_RECONNECTING_PTY, header)
+			}
+			s.ReportActivity = a.activity.Touch
 		},
 	)
 	go a.runLoop()
 }
 
+// sshConnectionType returns the connection type to report for an SSH
+// session.
+func (a *agent) sshConnectionType(magicType agentssh.MagicSessionType) proto.Connection_Type {
+	switch magicType {
+	case agentssh.MagicSessionTypeSSH:
+		return proto.Connection_SSH
+	case agentssh.MagicSessionTypeVSCode:
+		return proto.Connection_VSCODE
+	case agentssh.MagicSessionTypeJetBrains:
+		return proto.Connection_JETBRAINS
+	case agentssh.MagicSessionTypeUnknown:
+		return proto.Connection_TYPE_UNSPECIFIED
+	default:
+		a.logger.Error(a.hardCtx, "unhandled magic session type when reporting connection", slog.F("magic_type", magicType))
+		return proto.Connection_TYPE_UNSPECIFIED
+	}
+}
+
 // runLoop attempts to start the agent in a retry loop.
 // Coder may be offline temporarily, a connection issue
 // may be happening, but regardless after the intermittent
@@ -456,7 +486,7 @@ func (t *trySingleflight) Do(key string, fn func()) {
 	fn()
 }
 
-func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 	tickerDone := make(chan struct{})
 	collectDone := make(chan struct{})
 	ctx, cancel := context.WithCancel(ctx)
@@ -672,7 +702,7 @@ func (a *agent) reportMetadata(ctx context.Context, aAPI proto.DRPCAgentClient24
 
 // reportLifecycle reports the current lifecycle state once. All state
 // changes are reported in order.
-func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+func (a *agent) reportLifecycle(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 	for {
 		select {
 		case <-a.lifecycleUpdate:
@@ -752,7 +782,7 @@ func (a *agent) setLifecycle(state codersdk.WorkspaceAgentLifecycle) {
 }
 
 // reportConnectionsLoop reports connections to the agent for auditing.
-func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+func (a *agent) reportConnectionsLoop(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 	for {
 		select {
 		case <-a.reportConnectionsUpdate:
@@ -869,10 +899,93 @@ func (a *agent) reportConnection(id uuid.UUID, connectionType proto.Connection_T
 	}
 }
 
+// allowForward returns whether the port forwarding policy in the manifest
+// allows a client at ip to connect to the TCP ("tcp") or UDP ("udp") address
+// or Unix socket ("unix"). Blocked attempts are reported for auditing.
+func (a *agent) allowForward(network, address, ip string) bool {
+	manifest := a.manifest.Load()
+	if manifest == nil || manifest.PortForwardingPolicy == nil {
+		return true
+	}
+	policy := manifest.PortForwardingPolicy
+
+	var allowed bool
+	reason := fmt.Sprintf("forwarding to %s %s is not allowed by the port forwarding policy", network, address)
+	switch network {
+	case "tcp", "udp":
+		_, portStr, err := net.SplitHostPort(address)
+		if err != nil {
+			break
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			break
+		}
+		allowed = policy.AllowsPort(uint16(port))
+	case "unix":
+		// The policy matches absolute paths with symlinks resolved, a
+		// relative path would be resolved against the working directory of
+		// the agent rather than anything the client knows about.
+		if !filepath.IsAbs(address) {
+			reason = fmt.Sprintf("forwarding to unix socket %s is not allowed, the path must be absolute", address)
+			break
+		}
+		allowed = policy.AllowsUnixSocket(address)
+	}
+	if allowed {
+		return true
+	}
+
+	a.logger.Info(a.hardCtx, "blocked port forward",
+		slog.F("network", network),
+		slog.F("address", address),
+		slog.F("ip", ip),
+	)
+	a.reportBlockedForward(ip, reason)
+	return false
+}
+
+// reportBlockedForward reports a rejected port forward. Unlike connections
+// reported by reportConnection, there is no matching disconnect.
+func (a *agent) reportBlockedForward(ip string, reason string) {
+	id := uuid.New()
+	// Remove the port from the IP because ports are not supported in coderd.
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	a.reportConnectionsMu.Lock()
+	defer a.reportConnectionsMu.Unlock()
+
+	if len(a.reportConnections) >= reportConnectionBufferLimit {
+		a.logger.Warn(a.hardCtx, "connection report buffer limit reached, dropping blocked port forward",
+			slog.F("limit", reportConnectionBufferLimit),
+			slog.F("connection_id", id),
+			slog.F("ip", ip),
+		)
+		return
+	}
+	a.reportConnections = append(a.reportConnections, &proto.ReportConnectionRequest{
+		Connection: &proto.Connection{
+			Id:         id[:],
+			Action:     proto.Connection_CONNECT,
+			Type:       proto.Connection_PORT_FORWARDING,
+			Timestamp:  timestamppb.New(time.Now()),
+			Ip:         ip,
+			StatusCode: http.StatusForbidden,
+			Reason:     &reason,
+		},
+	})
+	select {
+	case a.reportConnectionsUpdate <- struct{}{}:
+	default:
+	}
+}
+
 // fetchServiceBannerLoop fetches the service banner on an interval.  It will
 // not be fetched immediately; the expectation is that it is primed elsewhere
 // (and must be done before the session actually starts).
-func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+func (a *agent) fetchServiceBannerLoop(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 	ticker := time.NewTicker(a.announcementBannersRefreshInterval)
 	defer ticker.Stop()
 	for {
@@ -908,7 +1021,7 @@ func (a *agent) run() (retErr error) {
 	a.sessionToken.Store(&sessionToken)
 
 	// ConnectRPC returns the dRPC connection we use for the Agent and Tailnet v2+ APIs
-	aAPI, tAPI, err := a.client.ConnectRPC24(a.hardCtx)
+	aAPI, tAPI, err := a.client.ConnectRPC25(a.hardCtx)
 	if err != nil {
 		return err
 	}
@@ -925,7 +1038,7 @@ func (a *agent) run() (retErr error) {
 	connMan := newAPIConnRoutineManager(a.gracefulCtx, a.hardCtx, a.logger, aAPI, tAPI)
 
 	connMan.startAgentAPI("init notification banners", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 			bannersProto, err := aAPI.GetAnnouncementBanners(ctx, &proto.GetAnnouncementBannersRequest{})
 			if err != nil {
 				return xerrors.Errorf("fetch service banner: %w", err)
@@ -942,7 +1055,7 @@ func (a *agent) run() (retErr error) {
 	// sending logs gets gracefulShutdownBehaviorRemain because we want to send logs generated by
 	// shutdown scripts.
 	connMan.startAgentAPI("send logs", gracefulShutdownBehaviorRemain,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 			err := a.logSender.SendLoop(ctx, aAPI)
 			if xerrors.Is(err, agentsdk.ErrLogLimitExceeded) {
 				// we don't want this error to tear down the API connection and propagate to the
@@ -961,7 +1074,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("report metadata", gracefulShutdownBehaviorStop, a.reportMetadata)
 
 	// resources monitor can cease as soon as we start gracefully shutting down.
-	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+	connMan.startAgentAPI("resources monitor", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 		logger := a.logger.Named("resources_monitor")
 		clk := quartz.NewReal()
 		config, err := aAPI.GetResourcesMonitoringConfiguration(ctx, &proto.GetResourcesMonitoringConfigurationRequest{})
@@ -986,6 +1099,14 @@ func (a *agent) run() (retErr error) {
 	// gracefulShutdownBehaviorRemain.
 	connMan.startAgentAPI("report connections", gracefulShutdownBehaviorRemain, a.reportConnectionsLoop)
 
+	// Session recordings are finished when sessions are closed during
+	// graceful shutdown, so keep uploading them.
+	connMan.startAgentAPI("upload session recordings", gracefulShutdownBehaviorRemain, a.uploadSessionRecordingsLoop)
+
+	if a.experimentalDevcontainersEnabled {
+		connMan.startAgentAPI("update devcontainer apps", gracefulShutdownBehaviorStop, a.updateDevcontainerAppsLoop)
+	}
+
 	// channels to sync goroutines below
 	//  handle manifest
 	//       |
@@ -1008,7 +1129,7 @@ func (a *agent) run() (retErr error) {
 	connMan.startAgentAPI("handle manifest", gracefulShutdownBehaviorStop, a.handleManifest(manifestOK))
 
 	connMan.startAgentAPI("app health reporter", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+		func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 			if err := manifestOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no manifest: %w", err)
 			}
@@ -1023,7 +1144,7 @@ func (a *agent) run() (retErr error) {
 		a.createOrUpdateNetwork(manifestOK, networkOK))
 
 	connMan.startTailnetAPI("coordination", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient24) error {
+		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient25) error {
 			if err := networkOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no network: %w", err)
 			}
@@ -1032,7 +1153,7 @@ func (a *agent) run() (retErr error) {
 	)
 
 	connMan.startTailnetAPI("derp map subscriber", gracefulShutdownBehaviorStop,
-		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient24) error {
+		func(ctx context.Context, tAPI tailnetproto.DRPCTailnetClient25) error {
 			if err := networkOK.wait(ctx); err != nil {
 				return xerrors.Errorf("no network: %w", err)
 			}
@@ -1041,7 +1162,7 @@ func (a *agent) run() (retErr error) {
 
 	connMan.startAgentAPI("fetch service banner loop", gracefulShutdownBehaviorStop, a.fetchServiceBannerLoop)
 
-	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+	connMan.startAgentAPI("stats report loop", gracefulShutdownBehaviorStop, func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 		if err := networkOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no network: %w", err)
 		}
@@ -1056,8 +1177,8 @@ func (a *agent) run() (retErr error) {
 }
 
 // handleManifest returns a function that fetches and processes the manifest
-func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
-	return func(ctx context.Context, aAPI proto.DRPCAgentClient24) error {
+func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
+	return func(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
 		var (
 			sentResult = false
 			err        error
@@ -1193,8 +1314,8 @@ func (a *agent) handleManifest(manifestOK *checkpoint) func(ctx context.Context,
 
 // createOrUpdateNetwork waits for the manifest to be set using manifestOK, then creates or updates
 // the tailnet using the information in the manifest
-func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient24) error {
-	return func(ctx context.Context, _ proto.DRPCAgentClient24) (retErr error) {
+func (a *agent) createOrUpdateNetwork(manifestOK, networkOK *checkpoint) func(context.Context, proto.DRPCAgentClient25) error {
+	return func(ctx context.Context, _ proto.DRPCAgentClient25) (retErr error) {
 		if err := manifestOK.wait(ctx); err != nil {
 			return xerrors.Errorf("no manifest: %w", err)
 		}
@@ -1382,6 +1503,9 @@ func (a *agent) createTailnet(
 		Logger:              a.logger.Named("net.tailnet"),
 		ListenPort:          a.tailnetListenPort,
 		BlockEndpoints:      disableDirectConnections,
+		ForwardFilter: func(network string, src, dst netip.AddrPort) bool {
+			return a.allowForward(network, dst.String(), src.String())
+		},
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -1527,7 +1651,7 @@ func (a *agent) createTailnet(
 
 // runCoordinator runs a coordinator and returns whether a reconnect
 // should occur.
-func (a *agent) runCoordinator(ctx context.Context, tClient tailnetproto.DRPCTailnetClient24, network *tailnet.Conn) error {
+func (a *agent) runCoordinator(ctx context.Context, tClient tailnetproto.DRPCTailnetClient25, network *tailnet.Conn) error {
 	defer a.logger.Debug(ctx, "disconnected from coordination RPC")
 	// we run the RPC on the hardCtx so that we have a chance to send the disconnect message if we
 	// gracefully shut down.
@@ -1582,7 +1706,7 @@ func (a *agent) setCoordDisconnected() chan struct{} {
 }
 
 // runDERPMapSubscriber runs a coordinator and returns if a reconnect should occur.
-func (a *agent) runDERPMapSubscriber(ctx context.Context, tClient tailnetproto.DRPCTailnetClient24, network *tailnet.Conn) error {
+func (a *agent) runDERPMapSubscriber(ctx context.Context, tClient tailnetproto.DRPCTailnetClient25, network *tailnet.Conn) error {
 	defer a.logger.Debug(ctx, "disconnected from derp map RPC")
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -1635,6 +1759,15 @@ func (a *agent) Collect(ctx context.Context, networkStats map[netlogtype.Connect
 
 	stats.SessionCountReconnectingPty = a.reconnectingPTYServer.ConnCount()
 
+	// Commands running in the foreground of a terminal are activity even
+	// when nothing is typed, e.g. a long build.
+	if err := a.activity.CheckForegroundProcesses(ctx); err != nil {
+		a.logger.Debug(ctx, "check foreground processes", slog.Error(err))
+	}
+	if since, ok := a.activity.TimeSinceLastActivity(); ok {
+		stats.TimeSinceLastActivity = durationpb.New(since)
+	}
+
 	// Compute the median connection latency!
 	a.logger.Debug(ctx, "starting peer latency measurement for stats")
 	var wg sync.WaitGroup
@@ -1966,8 +2099,8 @@ const (
 
 type apiConnRoutineManager struct {
 	logger    slog.Logger
-	aAPI      proto.DRPCAgentClient24
-	tAPI      tailnetproto.DRPCTailnetClient24
+	aAPI      proto.DRPCAgentClient25
+	tAPI      tailnetproto.DRPCTailnetClient25
 	eg        *errgroup.Group
 	stopCtx   context.Context
 	remainCtx context.Context
@@ -1975,7 +2108,7 @@ type apiConnRoutineManager struct {
 
 func newAPIConnRoutineManager(
 	gracefulCtx, hardCtx context.Context, logger slog.Logger,
-	aAPI proto.DRPCAgentClient24, tAPI tailnetproto.DRPCTailnetClient24,
+	aAPI proto.DRPCAgentClient25, tAPI tailnetproto.DRPCTailnetClient25,
 ) *apiConnRoutineManager {
 	// routines that remain in operation during graceful shutdown use the remainCtx.  They'll still
 	// exit if the errgroup hits an error, which usually means a problem with the conn.
@@ -2008,7 +2141,7 @@ func newAPIConnRoutineManager(
 // but for Tailnet.
 func (a *apiConnRoutineManager) startAgentAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, proto.DRPCAgentClient24) error,
+	f func(context.Context, proto.DRPCAgentClient25) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
@@ -2045,7 +2178,7 @@ func (a *apiConnRoutineManager) startAgentAPI(
 // but for the Agent API.
 func (a *apiConnRoutineManager) startTailnetAPI(
 	name string, behavior gracefulShutdownBehavior,
-	f func(context.Context, tailnetproto.DRPCTailnetClient24) error,
+	f func(context.Context, tailnetproto.DRPCTailnetClient25) error,
 ) {
 	logger := a.logger.With(slog.F("name", name))
 	var ctx context.Context
@@ -48,6 +48,7 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentrecording"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/agent/proto"
@@ -182,12 +183,51 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	require.Eventuallyf(t, func() bool {
 		var ok bool
 		s, ok = <-stats
-		return ok && s.ConnectionCount > 0 && s.RxBytes > 0 && s.TxBytes > 0 && s.SessionCountReconnectingPty == 1
+		return ok && s.ConnectionCount > 0 && s.RxBytes > 0 && s.TxBytes > 0 && s.SessionCountReconnectingPty == 1 && s.TimeSinceLastActivity != nil
 	}, testutil.WaitLong, testutil.IntervalFast,
 		"never saw stats: %+v", s,
 	)
 }
 
+func TestAgent_Stats_LastActivity(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	//nolint:dogsled
+	conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.RequestPty("xterm", 128, 128, ssh.TerminalModes{})
+	require.NoError(t, err)
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+	err = session.Shell()
+	require.NoError(t, err)
+
+	typedAt := time.Now()
+	_, err = stdin.Write([]byte("echo test\r"))
+	require.NoError(t, err)
+
+	var s *proto.Stats
+	require.Eventuallyf(t, func() bool {
+		var ok bool
+		s, ok = <-stats
+		// The activity is reported relative to when the stats were
+		// collected, which is before they're received.
+		return ok && s.TimeSinceLastActivity != nil && s.TimeSinceLastActivity.AsDuration() <= time.Since(typedAt)
+	}, testutil.WaitLong, testutil.IntervalFast,
+		"never saw stats: %+v", s,
+	)
+	_ = stdin.Close()
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -993,6 +1033,172 @@ func TestAgent_UnixRemoteForwarding(t *testing.T) {
 	requireEcho(t, conn)
 }
 
+func TestAgent_PortForwardingPolicy(t *testing.T) {
+	t.Parallel()
+
+	listen := func(t *testing.T) (net.Listener, uint16) {
+		t.Helper()
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+		tcpAddr, valid := l.Addr().(*net.TCPAddr)
+		require.True(t, valid)
+		return l, uint16(tcpAddr.Port) //nolint:gosec // Ports fit in a uint16.
+	}
+
+	requireBlockedReport := func(t *testing.T, agentClient *agenttest.Client, address string) {
+		t.Helper()
+		var reports []*proto.ReportConnectionRequest
+		require.Eventually(t, func() bool {
+			reports = agentClient.GetConnectionReports()
+			return len(reports) > 0
+		}, testutil.WaitMedium, testutil.IntervalFast)
+		require.Len(t, reports, 1)
+		conn := reports[0].GetConnection()
+		assert.Equal(t, proto.Connection_CONNECT, conn.GetAction())
+		assert.Equal(t, proto.Connection_PORT_FORWARDING, conn.GetType())
+		assert.Equal(t, http.StatusForbidden, int(conn.GetStatusCode()))
+		assert.NotEmpty(t, conn.GetIp())
+		assert.Contains(t, conn.GetReason(), address)
+	}
+
+	t.Run("SSHTCP", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		allowed, allowedPort := listen(t)
+		go echoOnce(t, allowed)
+		_, deniedPort := listen(t)
+
+		//nolint:dogsled
+		agentConn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			PortForwardingPolicy: &agentsdk.PortForwardingPolicy{
+				AllowedPorts: []string{fmt.Sprint(allowedPort)},
+			},
+		}, 0)
+		sshClient, err := agentConn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+
+		conn, err := sshClient.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", allowedPort))
+		require.NoError(t, err)
+		defer conn.Close()
+		requireEcho(t, conn)
+
+		deniedAddr := fmt.Sprintf("127.0.0.1:%d", deniedPort)
+		_, err = sshClient.Dial("tcp", deniedAddr)
+		require.Error(t, err)
+		requireBlockedReport(t, agentClient, deniedAddr)
+	})
+
+	t.Run("SSHUnix", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("unix domain sockets are not fully supported on Windows")
+		}
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		remoteSocketPath := filepath.Join(tempDirUnixSocket(t), "remote-socket")
+		l, err := net.Listen("unix", remoteSocketPath)
+		require.NoError(t, err)
+		defer l.Close()
+
+		//nolint:dogsled
+		agentConn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			PortForwardingPolicy: &agentsdk.PortForwardingPolicy{
+				DeniedUnixSockets: []string{filepath.Join(filepath.Dir(remoteSocketPath), "*")},
+			},
+		}, 0)
+		sshClient, err := agentConn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+
+		_, err = sshClient.Dial("unix", remoteSocketPath)
+		require.Error(t, err)
+		requireBlockedReport(t, agentClient, remoteSocketPath)
+	})
+
+	t.Run("Tailnet", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		allowed, allowedPort := listen(t)
+		go echoOnce(t, allowed)
+		_, deniedPort := listen(t)
+
+		//nolint:dogsled
+		agentConn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			PortForwardingPolicy: &agentsdk.PortForwardingPolicy{
+				DeniedPorts: []string{fmt.Sprint(deniedPort)},
+			},
+		}, 0)
+		require.True(t, agentConn.AwaitReachable(ctx))
+
+		conn, err := agentConn.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", allowedPort))
+		require.NoError(t, err)
+		defer conn.Close()
+		requireEcho(t, conn)
+
+		_, err = agentConn.DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", deniedPort))
+		require.Error(t, err)
+		requireBlockedReport(t, agentClient, fmt.Sprintf(":%d", deniedPort))
+	})
+
+	t.Run("TailnetUDP", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		listenUDP := func(t *testing.T) (net.Listener, uint16) {
+			t.Helper()
+			l, err := udp.Listen("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = l.Close() })
+			udpAddr, valid := l.Addr().(*net.UDPAddr)
+			require.True(t, valid)
+			return l, uint16(udpAddr.Port) //nolint:gosec // Ports fit in a uint16.
+		}
+		allowed, allowedPort := listenUDP(t)
+		go echoOnce(t, allowed)
+		denied, deniedPort := listenUDP(t)
+		deniedAccepted := make(chan struct{})
+		go func() {
+			conn, err := denied.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+			close(deniedAccepted)
+		}()
+
+		//nolint:dogsled
+		agentConn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			PortForwardingPolicy: &agentsdk.PortForwardingPolicy{
+				DeniedPorts: []string{fmt.Sprint(deniedPort)},
+			},
+		}, 0)
+		require.True(t, agentConn.AwaitReachable(ctx))
+
+		conn, err := agentConn.DialContext(ctx, "udp", fmt.Sprintf("127.0.0.1:%d", allowedPort))
+		require.NoError(t, err)
+		defer conn.Close()
+		requireEcho(t, conn)
+
+		// Datagrams are sent without a handshake, so the flow is only
+		// rejected in the agent.
+		conn, err = agentConn.DialContext(ctx, "udp", fmt.Sprintf("127.0.0.1:%d", deniedPort))
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("test"))
+		require.NoError(t, err)
+		requireBlockedReport(t, agentClient, fmt.Sprintf(":%d", deniedPort))
+		select {
+		case <-deniedAccepted:
+			t.Fatal("denied UDP flow was forwarded")
+		default:
+		}
+	})
+}
+
 func TestAgent_SFTP(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
@@ -1156,6 +1362,65 @@ func TestAgent_FileTransferBlocked(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("File API", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.BlockFileTransfer = true
+		})
+		dir := t.TempDir()
+		filePath := filepath.Join(dir, "file.txt")
+		err := os.WriteFile(filePath, []byte("hello world"), 0o600)
+		require.NoError(t, err)
+
+		requests := map[string]func() error{
+			"stat-file": func() error {
+				_, err := conn.StatFile(ctx, filePath)
+				return err
+			},
+			"read-file": func() error {
+				_, err := conn.ReadFile(ctx, filePath, 0, 0)
+				return err
+			},
+			"write-file": func() error {
+				return conn.WriteFile(ctx, filepath.Join(dir, "new.txt"), strings.NewReader("hello"))
+			},
+			"make-directory": func() error {
+				return conn.MakeDirectory(ctx, workspacesdk.MakeDirectoryRequest{Path: filepath.Join(dir, "sub")})
+			},
+			"move-file": func() error {
+				return conn.MoveFile(ctx, workspacesdk.MoveFileRequest{Source: filePath, Destination: filepath.Join(dir, "moved.txt")})
+			},
+			"delete-file": func() error {
+				return conn.DeleteFile(ctx, workspacesdk.DeleteFileRequest{Path: filePath})
+			},
+			"download-archive": func() error {
+				_, err := conn.DownloadArchive(ctx, dir)
+				return err
+			},
+			"upload-archive": func() error {
+				return conn.UploadArchive(ctx, filepath.Join(dir, "upload"), strings.NewReader(""))
+			},
+		}
+		for name, request := range requests {
+			err := request()
+			var sdkErr *codersdk.Error
+			require.ErrorAs(t, err, &sdkErr, name)
+			require.Equal(t, http.StatusForbidden, sdkErr.StatusCode(), name)
+			require.Equal(t, agentssh.BlockedFileTransferErrorMessage, sdkErr.Message, name)
+		}
+
+		// Nothing was changed on disk.
+		b, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(b))
+		require.NoFileExists(t, filepath.Join(dir, "new.txt"))
+		require.NoDirExists(t, filepath.Join(dir, "sub"))
+	})
 }
 
 func TestAgent_EnvironmentVariables(t *testing.T) {
@@ -1919,6 +2184,202 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYSharing(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	buffered := func(arp *workspacesdk.AgentReconnectingPTYInit) {
+		arp.BackendType = "buffered"
+	}
+
+	// Read-only connections cannot start a session.
+	missing, err := conn.ReconnectingPTY(ctx, uuid.New(), 24, 80, "", workspacesdk.AgentReconnectingPTYInitWithReadOnly())
+	require.NoError(t, err)
+	out, _ := io.ReadAll(missing)
+	require.Empty(t, out)
+	res, err := conn.ListReconnectingPTYs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, res.Sessions)
+
+	id := uuid.New()
+	rw, err := conn.ReconnectingPTY(ctx, id, 24, 80, "bash --norc", buffered)
+	require.NoError(t, err)
+	defer rw.Close()
+	rwReader := testutil.NewTerminalReader(t, rw)
+	matchPrompt := func(line string) bool {
+		return strings.Contains(line, "$ ") || strings.Contains(line, "# ")
+	}
+	require.NoError(t, rwReader.ReadUntil(ctx, matchPrompt), "find prompt")
+
+	// Watching the session does not resize it.
+	ro, err := conn.ReconnectingPTY(ctx, id, 10, 10, "", workspacesdk.AgentReconnectingPTYInitWithReadOnly())
+	require.NoError(t, err)
+	defer ro.Close()
+	roReader := testutil.NewTerminalReader(t, ro)
+	require.NoError(t, roReader.ReadUntil(ctx, matchPrompt), "find prompt")
+
+	var sess workspacesdk.ReconnectingPTYSession
+	require.Eventually(t, func() bool {
+		res, err := conn.ListReconnectingPTYs(ctx)
+		if !assert.NoError(t, err) || len(res.Sessions) != 1 {
+			return false
+		}
+		sess = res.Sessions[0]
+		return len(sess.Connections) == 2
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, id, sess.ID)
+	require.Equal(t, "bash --norc", sess.Command)
+	require.EqualValues(t, 24, sess.Height)
+	require.EqualValues(t, 80, sess.Width)
+	require.False(t, sess.Connections[0].ReadOnly)
+	require.True(t, sess.Connections[1].ReadOnly)
+
+	// Input from the read-only connection is dropped, while input from the
+	// read-write connection is seen by both.
+	for _, c := range []struct {
+		conn net.Conn
+		data string
+	}{{ro, "echo readonly\r"}, {rw, "echo shared\r"}} {
+		data, err := json.Marshal(workspacesdk.ReconnectingPTYRequest{Data: c.data})
+		require.NoError(t, err)
+		_, err = c.conn.Write(data)
+		require.NoError(t, err)
+	}
+	for _, tr := range []*testutil.TerminalReader{rwReader, roReader} {
+		require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+			assert.NotContains(t, line, "readonly")
+			return strings.Contains(line, "shared") && !strings.Contains(line, "echo")
+		}), "find echo output")
+	}
+
+	_ = ro.Close()
+	require.Eventually(t, func() bool {
+		res, err := conn.ListReconnectingPTYs(ctx)
+		return assert.NoError(t, err) && len(res.Sessions) == 1 && len(res.Sessions[0].Connections) == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	// recordedData returns the output and the number of input events of the
+	// recording of the only reported connection.
+	recordedData := func(t *testing.T, agentClient *agenttest.Client, connectionType proto.Connection_Type) (output string, inputs int) {
+		t.Helper()
+
+		var recordings []*proto.UploadSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings = agentClient.GetSessionRecordings()
+			return len(recordings) > 0
+		}, testutil.WaitMedium, testutil.IntervalFast)
+		require.Len(t, recordings, 1)
+		reports := agentClient.GetConnectionReports()
+		require.NotEmpty(t, reports)
+		require.Equal(t, reports[0].GetConnection().GetId(), recordings[0].GetConnectionId(), "recording should be linked to the connection")
+		require.Equal(t, connectionType, recordings[0].GetConnectionType())
+		require.False(t, recordings[0].GetEndedAt().AsTime().Before(recordings[0].GetStartedAt().AsTime()))
+
+		dec, header, err := agentrecording.NewDecoder(bytes.NewReader(recordings[0].GetData()))
+		require.NoError(t, err)
+		require.Equal(t, 80, header.Width)
+		for {
+			event, err := dec.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			switch event.Type {
+			case agentrecording.EventTypeOutput:
+				output += event.Data
+			case agentrecording.EventTypeInput:
+				require.Empty(t, event.Data, "input must not be recorded")
+				inputs++
+			}
+		}
+		return output, inputs
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{SessionRecording: true}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		var stdout bytes.Buffer
+		session.Stdout = &stdout
+		err = session.Run("echo recorded")
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "recorded")
+
+		output, _ := recordedData(t, agentClient, proto.Connection_SSH)
+		require.Contains(t, output, "recorded")
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{SessionRecording: true}, 0)
+		netConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 24, 80, "bash --norc", func(arp *workspacesdk.AgentReconnectingPTYInit) {
+			arp.BackendType = "buffered"
+		})
+		require.NoError(t, err)
+		tr := testutil.NewTerminalReader(t, netConn)
+		data, err := json.Marshal(workspacesdk.ReconnectingPTYRequest{
+			Data: "echo recorded\r",
+		})
+		require.NoError(t, err)
+		_, err = netConn.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+			return strings.Contains(line, "recorded") && !strings.Contains(line, "echo")
+		}), "find echo output")
+		_ = netConn.Close()
+
+		output, inputs := recordedData(t, agentClient, proto.Connection_RECONNECTING_PTY)
+		require.Contains(t, output, "recorded")
+		require.NotZero(t, inputs)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, agentClient, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		err = session.Run("echo recorded")
+		require.NoError(t, err)
+
+		assertConnectionReport(t, agentClient, proto.Connection_SSH, 0, "")
+		require.Empty(t, agentClient.GetSessionRecordings())
+	})
+}
+
 // This tests end-to-end functionality of connecting to a running container
 // and executing a command. It creates a real Docker container and runs a
 // command. As such, it does not run by default in CI.
@@ -2515,6 +2976,154 @@ func TestAgent_UpdatedDERP(t *testing.T) {
 	t.Log("conn1 reached agent with new DERP")
 }
 
+func TestAgent_Files(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	dir := t.TempDir()
+
+	// Write creates missing parent directories.
+	filePath := filepath.Join(dir, "sub", "hello.txt")
+	err := conn.WriteFile(ctx, filePath, strings.NewReader("hello world"))
+	require.NoError(t, err)
+
+	info, err := conn.StatFile(ctx, filePath)
+	require.NoError(t, err)
+	require.Equal(t, "hello.txt", info.Name)
+	require.Equal(t, filePath, info.AbsolutePathString)
+	require.EqualValues(t, len("hello world"), info.Size)
+	require.False(t, info.IsDir)
+
+	read := func(offset, limit int64) string {
+		rc, err := conn.ReadFile(ctx, filePath, offset, limit)
+		require.NoError(t, err)
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return string(b)
+	}
+	require.Equal(t, "hello world", read(0, 0))
+	require.Equal(t, "world", read(6, 0))
+	require.Equal(t, "lo w", read(3, 4))
+	require.Empty(t, read(100, 0))
+
+	_, err = conn.ReadFile(ctx, dir, 0, 0)
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	movedPath := filepath.Join(dir, "moved", "hello.txt")
+	err = conn.MoveFile(ctx, workspacesdk.MoveFileRequest{Source: filePath, Destination: movedPath})
+	require.NoError(t, err)
+	_, err = conn.StatFile(ctx, filePath)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+	err = conn.MakeDirectory(ctx, workspacesdk.MakeDirectoryRequest{Path: filepath.Join(dir, "a", "b")})
+	require.NoError(t, err)
+	info, err = conn.StatFile(ctx, filepath.Join(dir, "a", "b"))
+	require.NoError(t, err)
+	require.True(t, info.IsDir)
+
+	// Non-empty directories are only deleted recursively.
+	err = conn.DeleteFile(ctx, workspacesdk.DeleteFileRequest{Path: filepath.Join(dir, "moved")})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusConflict, sdkErr.StatusCode())
+	err = conn.DeleteFile(ctx, workspacesdk.DeleteFileRequest{Path: filepath.Join(dir, "moved"), Recursive: true})
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(dir, "moved"))
+
+	// Archives round trip directories.
+	err = conn.WriteFile(ctx, filepath.Join(dir, "a", "b", "c.txt"), strings.NewReader("c"))
+	require.NoError(t, err)
+	archive, err := conn.DownloadArchive(ctx, filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	defer archive.Close()
+	err = conn.UploadArchive(ctx, filepath.Join(dir, "copy"), archive)
+	require.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(dir, "copy", "a", "b", "c.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "c", string(b))
+}
+
+func TestAgent_Processes(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Signals other than KILL are not supported on Windows")
+	}
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	appCommand := "sleep 3600 # app"
+	appID := uuid.New()
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{
+		Apps: []codersdk.WorkspaceApp{{
+			ID:           appID,
+			Slug:         "sleeper",
+			Command:      appCommand,
+			SharingLevel: codersdk.WorkspaceAppSharingLevelOwner,
+			Health:       codersdk.WorkspaceAppHealthDisabled,
+		}},
+	}, 0)
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	err = session.Start("sleep 3600 # ssh")
+	require.NoError(t, err)
+
+	ptyConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, appCommand)
+	require.NoError(t, err)
+	defer ptyConn.Close()
+
+	// Processes are attributed to the session that started them.
+	var sshProc, appProc workspacesdk.Process
+	require.Eventually(t, func() bool {
+		res, err := conn.ListProcesses(ctx)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		for _, proc := range res.Processes {
+			if proc.Owner == nil || !strings.HasPrefix(proc.Cmdline, "sleep 3600") {
+				continue
+			}
+			switch proc.Owner.Type {
+			case workspacesdk.ProcessOwnerTypeSSH:
+				sshProc = proc
+			case workspacesdk.ProcessOwnerTypeApp:
+				appProc = proc
+			}
+		}
+		return sshProc.PID != 0 && appProc.PID != 0
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, "ssh", sshProc.Owner.Name)
+	require.Equal(t, appID.String(), appProc.Owner.ID)
+	require.Equal(t, "sleeper", appProc.Owner.Name)
+
+	err = conn.SignalProcess(ctx, workspacesdk.SignalProcessRequest{PID: sshProc.PID})
+	require.NoError(t, err)
+	var exitErr *ssh.ExitError
+	require.ErrorAs(t, session.Wait(), &exitErr)
+
+	var sdkErr *codersdk.Error
+	err = conn.SignalProcess(ctx, workspacesdk.SignalProcessRequest{PID: sshProc.PID})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+	err = conn.SignalProcess(ctx, workspacesdk.SignalProcessRequest{PID: int32(os.Getpid())})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+	err = conn.SignalProcess(ctx, workspacesdk.SignalProcessRequest{PID: appProc.PID, Signal: "STOP"})
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+}
+
 func TestAgent_Speedtest(t *testing.T) {
 	t.Parallel()
 	t.Skip("This test is relatively flakey because of Tailscale's speedtest code...")
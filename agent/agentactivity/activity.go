@@ -0,0 +1,68 @@
+// Package agentactivity tracks real user activity in a workspace, as opposed
+// to open connections. A forgotten SSH session or browser tab keeps a
+// connection open, but doesn't type into a terminal or keep a command
+// running in the foreground.
+package agentactivity
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/quartz"
+)
+
+// Tracker records the last time of user activity.
+type Tracker struct {
+	clock quartz.Clock
+	// last is the time of the last activity in Unix nanoseconds, or zero if
+	// there was none.
+	last atomic.Int64
+}
+
+// NewTracker returns a tracker that has seen no activity.
+func NewTracker(clock quartz.Clock) *Tracker {
+	if clock == nil {
+		clock = quartz.NewReal()
+	}
+	return &Tracker{clock: clock}
+}
+
+// Touch records user activity, e.g. input to a terminal.
+func (t *Tracker) Touch() {
+	t.last.Store(t.clock.Now().UnixNano())
+}
+
+// LastActivity returns the time of the last activity, or the zero time if
+// there was none.
+func (t *Tracker) LastActivity() time.Time {
+	last := t.last.Load()
+	if last == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, last)
+}
+
+// TimeSinceLastActivity returns the time since the last activity, and false
+// if there was none.
+func (t *Tracker) TimeSinceLastActivity() (time.Duration, bool) {
+	last := t.LastActivity()
+	if last.IsZero() {
+		return 0, false
+	}
+	return t.clock.Since(last), true
+}
+
+// CheckForegroundProcesses records activity when a command runs in the
+// foreground of a terminal, e.g. a build started in an SSH session. A shell
+// waiting for input at its prompt doesn't count.
+func (t *Tracker) CheckForegroundProcesses(ctx context.Context) error {
+	ok, err := hasForegroundProcess(ctx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		t.Touch()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package agentactivity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentactivity"
+	"github.com/coder/quartz"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	clock := quartz.NewMock(t)
+	tracker := agentactivity.NewTracker(clock)
+	require.True(t, tracker.LastActivity().IsZero())
+	_, ok := tracker.TimeSinceLastActivity()
+	require.False(t, ok)
+
+	tracker.Touch()
+	first := clock.Now()
+	require.True(t, first.Equal(tracker.LastActivity()))
+
+	clock.Advance(time.Minute)
+	require.True(t, first.Equal(tracker.LastActivity()), "activity must not advance with time")
+	since, ok := tracker.TimeSinceLastActivity()
+	require.True(t, ok)
+	require.Equal(t, time.Minute, since)
+
+	tracker.Touch()
+	require.True(t, clock.Now().Equal(tracker.LastActivity()))
+}
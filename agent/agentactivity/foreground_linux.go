@@ -0,0 +1,94 @@
+package agentactivity
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/xerrors"
+)
+
+// The major device numbers of pseudo-terminals, see
+// https://www.kernel.org/doc/Documentation/admin-guide/devices.txt.
+const (
+	ptsMajorMin = 136
+	ptsMajorMax = 143
+)
+
+// procStat holds the fields of /proc/<pid>/stat used to find foreground
+// processes, see proc_pid_stat(5).
+type procStat struct {
+	state   byte
+	pgrp    int
+	session int
+	ttyNr   int
+	tpgid   int
+}
+
+// foreground reports whether the process is in the foreground process group
+// of a pseudo-terminal, and that group isn't the one of the session leader,
+// i.e. the shell.
+func (s procStat) foreground() bool {
+	if s.state == 'Z' || s.tpgid <= 0 {
+		return false
+	}
+	major := (s.ttyNr >> 8) & 0xfff
+	if major < ptsMajorMin || major > ptsMajorMax {
+		return false
+	}
+	return s.pgrp == s.tpgid && s.pgrp != s.session
+}
+
+// parseProcStat parses the contents of /proc/<pid>/stat.
+func parseProcStat(data []byte) (procStat, error) {
+	// The command name is in parentheses and may contain spaces and
+	// parentheses itself, so the fields are split after the last one.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, xerrors.New("missing command name")
+	}
+	// state ppid pgrp session tty_nr tpgid ...
+	fields := bytes.Fields(data[end+1:])
+	if len(fields) < 6 || len(fields[0]) != 1 {
+		return procStat{}, xerrors.Errorf("unexpected number of fields: %d", len(fields))
+	}
+	s := procStat{state: fields[0][0]}
+	for i, v := range map[int]*int{2: &s.pgrp, 3: &s.session, 4: &s.ttyNr, 5: &s.tpgid} {
+		n, err := strconv.Atoi(string(fields[i]))
+		if err != nil {
+			return procStat{}, xerrors.Errorf("parse field %d: %w", i, err)
+		}
+		*v = n
+	}
+	return s, nil
+}
+
+func hasForegroundProcess(ctx context.Context) (bool, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return false, xerrors.Errorf("read /proc: %w", err)
+	}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			// The process most likely exited.
+			continue
+		}
+		stat, err := parseProcStat(data)
+		if err != nil {
+			continue
+		}
+		if stat.foreground() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,62 @@
+package agentactivity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStat(t *testing.T) {
+	t.Parallel()
+
+	// The terminal 34819 is /dev/pts/3, major 136 and minor 3, and 1025 is
+	// /dev/tty1.
+	for _, tt := range []struct {
+		name       string
+		stat       string
+		foreground bool
+	}{
+		{
+			name:       "ForegroundJob",
+			stat:       "4242 (make) R 4200 4242 4200 34819 4242 4194304 0 0 0 0",
+			foreground: true,
+		},
+		{
+			name: "ShellAtPrompt",
+			stat: "4200 (bash) S 4199 4200 4200 34819 4200 4194304 0 0 0 0",
+		},
+		{
+			name: "BackgroundJob",
+			stat: "4243 (sleep) S 4200 4243 4200 34819 4200 4194304 0 0 0 0",
+		},
+		{
+			name: "NoTerminal",
+			stat: "1 (init) S 0 1 1 0 -1 4194560 0 0 0 0",
+		},
+		{
+			name: "Console",
+			stat: "4242 (make) R 4200 4242 4200 1025 4242 4194304 0 0 0 0",
+		},
+		{
+			name: "Zombie",
+			stat: "4242 (make) Z 4200 4242 4200 34819 4242 4194304 0 0 0 0",
+		},
+		{
+			name:       "CommandWithParentheses",
+			stat:       "4242 (a) (b) R 4200 4242 4200 34819 4242 4194304 0 0 0 0",
+			foreground: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stat, err := parseProcStat([]byte(tt.stat))
+			require.NoError(t, err)
+			require.Equal(t, tt.foreground, stat.foreground())
+		})
+	}
+
+	_, err := parseProcStat([]byte("4242 make R"))
+	require.Error(t, err)
+	_, err = parseProcStat([]byte("4242 (make) R 4200"))
+	require.Error(t, err)
+}
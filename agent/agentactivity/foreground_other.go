@@ -0,0 +1,11 @@
+//go:build !linux
+
+package agentactivity
+
+import "context"
+
+// hasForegroundProcess is only implemented on Linux, where the terminal of
+// a process can be read from /proc.
+func hasForegroundProcess(context.Context) (bool, error) {
+	return false, nil
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -28,6 +29,7 @@ const (
 	defaultGetContainersCacheDuration = 10 * time.Second
 	dockerCreatedAtTimeFormat         = "2006-01-02 15:04:05 -0700 MST"
 	getContainersTimeout              = 5 * time.Second
+	readConfigTimeout                 = time.Minute
 )
 
 // API is responsible for container-related operations in the agent.
@@ -45,6 +47,10 @@ type API struct {
 	dccli         DevcontainerCLI
 	clock         quartz.Clock
 	scriptLogger  func(logSourceID uuid.UUID) ScriptLogger
+	forwarder     *portForwarder
+	containerIP   func(ctx context.Context, containerID string) (string, error)
+	registerApps  func(devcontainers []codersdk.WorkspaceAgentDevcontainer)
+	asyncWg       sync.WaitGroup
 
 	// lockCh protects the below fields. We use a channel instead of a
 	// mutex so we can handle cancellation properly.
@@ -54,6 +60,8 @@ type API struct {
 	devcontainerNames       map[string]struct{}                   // Track devcontainer names to avoid duplicates.
 	knownDevcontainers      []codersdk.WorkspaceAgentDevcontainer // Track predefined and runtime-detected devcontainers.
 	configFileModifiedTimes map[string]time.Time                  // Track when config files were last modified.
+	configContainerIDs      map[string]string                     // Track the containers whose devcontainer config has been read, by workspace folder.
+	closed                  bool
 
 	devcontainerLogSourceIDs map[string]uuid.UUID // Track devcontainer log source IDs.
 }
@@ -154,6 +162,16 @@ func WithScriptLogger(scriptLogger func(logSourceID uuid.UUID) ScriptLogger) Opt
 	}
 }
 
+// WithAppRegisterer sets the function that registers the labeled ports of
+// devcontainers as workspace apps. It's called with all devcontainers that
+// have forwarded ports whenever the forwarded ports change, with the lock
+// held, so it must not block.
+func WithAppRegisterer(registerApps func(devcontainers []codersdk.WorkspaceAgentDevcontainer)) Option {
+	return func(api *API) {
+		api.registerApps = registerApps
+	}
+}
+
 // NewAPI returns a new API with the given options applied.
 func NewAPI(logger slog.Logger, options ...Option) *API {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -169,6 +187,8 @@ func NewAPI(logger slog.Logger, options ...Option) *API {
 		devcontainerNames:       make(map[string]struct{}),
 		knownDevcontainers:      []codersdk.WorkspaceAgentDevcontainer{},
 		configFileModifiedTimes: make(map[string]time.Time),
+		configContainerIDs:      make(map[string]string),
+		forwarder:               newPortForwarder(logger.Named("port-forwarder")),
 		scriptLogger:            func(uuid.UUID) ScriptLogger { return noopScriptLogger{} },
 	}
 	// The ctx and logger must be set before applying options to avoid
@@ -177,11 +197,20 @@ func NewAPI(logger slog.Logger, options ...Option) *API {
 		opt(api)
 	}
 	if api.cl == nil {
-		api.cl = NewDocker(api.execer)
+		api.cl = NewLister(api.execer)
 	}
 	if api.dccli == nil {
 		api.dccli = NewDevcontainerCLI(logger.Named("devcontainer-cli"), api.execer)
 	}
+	if api.containerIP == nil {
+		api.containerIP = func(ctx context.Context, containerID string) (string, error) {
+			_, in, err := findContainer(ctx, api.execer, containerID)
+			if err != nil {
+				return "", err
+			}
+			return in.NetworkSettings.ipAddress(), nil
+		}
+	}
 	if api.watcher == nil {
 		var err error
 		api.watcher, err = watcher.NewFSNotify()
@@ -201,8 +230,10 @@ func NewAPI(logger slog.Logger, options ...Option) *API {
 // of containers and to start watching the devcontainer config files for
 // changes. It should be called after the agent ready.
 func (api *API) SignalReady() {
-	// Prime the cache with the current list of containers.
-	_, _ = api.cl.List(api.ctx)
+	// Prime the cache with the current list of containers, this also
+	// applies the configuration of devcontainers that were started by the
+	// startup scripts.
+	_, _ = api.getContainers(api.ctx)
 
 	// Make sure we watch the devcontainer config files for changes.
 	for _, devcontainer := range api.knownDevcontainers {
@@ -290,6 +321,12 @@ func (api *API) handleList(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if !api.doLockedHandler(rw, r, func() {
+			ct.Devcontainers = slices.Clone(api.knownDevcontainers)
+		}) {
+			return
+		}
+
 		httpapi.Write(r.Context(), rw, http.StatusOK, ct)
 	}
 }
@@ -420,9 +457,128 @@ func (api *API) getContainers(ctx context.Context) (codersdk.WorkspaceAgentListC
 		})
 	}
 
+	api.updateDevcontainerConfigsLocked()
+
 	return copyListContainersResponse(api.containers), nil
 }
 
+// updateDevcontainerConfigsLocked reads the configuration of devcontainers
+// whose container has started since it was last read, and stops forwarding
+// the ports of devcontainers that have stopped. It must be called with the
+// lock held.
+func (api *API) updateDevcontainerConfigsLocked() {
+	var stopped bool
+	defer func() {
+		if stopped {
+			api.registerAppsLocked()
+		}
+	}()
+	for i := range api.knownDevcontainers {
+		dc := &api.knownDevcontainers[i]
+		if dc.Container == nil || !dc.Container.Running {
+			if _, ok := api.configContainerIDs[dc.WorkspaceFolder]; ok {
+				delete(api.configContainerIDs, dc.WorkspaceFolder)
+				api.forwarder.stop(dc.WorkspaceFolder)
+				stopped = stopped || len(dc.ForwardedPorts) > 0
+				dc.ForwardedPorts = nil
+				dc.Extensions = nil
+			}
+			continue
+		}
+		if api.closed || api.configContainerIDs[dc.WorkspaceFolder] == dc.Container.ID {
+			continue
+		}
+		api.configContainerIDs[dc.WorkspaceFolder] = dc.Container.ID
+		api.asyncWg.Add(1)
+		go api.applyDevcontainerConfig(*dc)
+	}
+}
+
+// applyDevcontainerConfig reads the configuration of the running
+// devcontainer dc, forwards its ports, registers its labeled ports as apps
+// and surfaces its VS Code extensions.
+func (api *API) applyDevcontainerConfig(dc codersdk.WorkspaceAgentDevcontainer) {
+	defer api.asyncWg.Done()
+
+	containerID := dc.Container.ID
+	logger := api.logger.With(
+		slog.F("devcontainer", dc.Name),
+		slog.F("workspace_folder", dc.WorkspaceFolder),
+		slog.F("config_path", dc.ConfigPath),
+		slog.F("container", containerID),
+	)
+	ctx, cancel := context.WithTimeout(api.ctx, readConfigTimeout)
+	defer cancel()
+
+	config, err := api.dccli.ReadConfig(ctx, dc.WorkspaceFolder, dc.ConfigPath)
+	if err != nil {
+		logger.Warn(ctx, "read devcontainer config failed", slog.Error(err))
+		return
+	}
+	ports := devcontainerForwardedPorts(config.MergedConfiguration)
+	extensions := devcontainerVSCodeExtensions(config.MergedConfiguration)
+
+	var ip string
+	if len(ports) > 0 {
+		ip, err = api.containerIP(ctx, containerID)
+		if err != nil {
+			logger.Warn(ctx, "get devcontainer IP address failed, ports will not be forwarded", slog.Error(err))
+		}
+	}
+
+	api.doLocked(func() {
+		// The container may have been stopped or recreated while the
+		// configuration was read.
+		if api.closed || api.configContainerIDs[dc.WorkspaceFolder] != containerID {
+			return
+		}
+		i := slices.IndexFunc(api.knownDevcontainers, func(known codersdk.WorkspaceAgentDevcontainer) bool {
+			return known.WorkspaceFolder == dc.WorkspaceFolder
+		})
+		if i == -1 {
+			return
+		}
+		switch {
+		case err != nil:
+		case ip == "":
+			// Containers that use the network of the host listen in the
+			// workspace already.
+			for j := range ports {
+				ports[j].Forwarded = true
+			}
+		default:
+			ports = api.forwarder.forward(ctx, dc.WorkspaceFolder, dc.Name, ip, ports)
+		}
+		api.knownDevcontainers[i].ForwardedPorts = ports
+		api.knownDevcontainers[i].Extensions = extensions
+		logger.Debug(ctx, "applied devcontainer config", slog.F("ports", ports), slog.F("extensions", extensions))
+		api.registerAppsLocked()
+	})
+}
+
+// registerAppsLocked passes the forwarded ports of all devcontainers to the
+// app registerer. It must be called with the lock held.
+func (api *API) registerAppsLocked() {
+	if api.registerApps == nil {
+		return
+	}
+	var devcontainers []codersdk.WorkspaceAgentDevcontainer
+	for _, dc := range api.knownDevcontainers {
+		if len(dc.ForwardedPorts) == 0 {
+			continue
+		}
+		dc.ForwardedPorts = slices.Clone(dc.ForwardedPorts)
+		devcontainers = append(devcontainers, dc)
+	}
+	api.registerApps(devcontainers)
+}
+
+// ForwardedPorts returns the ports that are forwarded to devcontainers, with
+// the label of the port or the name of the devcontainer.
+func (api *API) ForwardedPorts() map[uint16]string {
+	return api.forwarder.names()
+}
+
 // handleDevcontainerRecreate handles the HTTP request to recreate a
 // devcontainer by referencing the container.
 func (api *API) handleDevcontainerRecreate(w http.ResponseWriter, r *http.Request) {
@@ -620,8 +776,16 @@ func (api *API) doLocked(f func()) bool {
 }
 
 func (api *API) Close() error {
+	// Prevent new devcontainer configs from being applied, the lock may be
+	// held by a request that is being canceled.
+	api.lockCh <- struct{}{}
+	api.closed = true
+	<-api.lockCh
+
 	api.cancel()
 	<-api.done
+	api.asyncWg.Wait()
+	api.forwarder.close()
 	err := api.watcher.Close()
 	if err != nil {
 		return err
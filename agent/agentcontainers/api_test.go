@@ -18,7 +18,9 @@ import (
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentcontainers/dcspec"
 	"github.com/coder/coder/v2/agent/agentcontainers/watcher"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 	"github.com/coder/quartz"
@@ -38,14 +40,20 @@ func (f *fakeLister) List(_ context.Context) (codersdk.WorkspaceAgentListContain
 // fakeDevcontainerCLI implements the agentcontainers.DevcontainerCLI
 // interface for testing.
 type fakeDevcontainerCLI struct {
-	id  string
-	err error
+	id            string
+	err           error
+	config        agentcontainers.DevcontainerConfig
+	readConfigErr error
 }
 
 func (f *fakeDevcontainerCLI) Up(_ context.Context, _, _ string, _ ...agentcontainers.DevcontainerCLIUpOptions) (string, error) {
 	return f.id, f.err
 }
 
+func (f *fakeDevcontainerCLI) ReadConfig(_ context.Context, _, _ string) (agentcontainers.DevcontainerConfig, error) {
+	return f.config, f.readConfigErr
+}
+
 // fakeWatcher implements the watcher.Watcher interface for testing.
 // It allows controlling what events are sent and when.
 type fakeWatcher struct {
@@ -605,6 +613,99 @@ func TestAPI(t *testing.T) {
 		}
 	})
 
+	t.Run("Devcontainer config", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+		fLister := &fakeLister{
+			containers: codersdk.WorkspaceAgentListContainersResponse{
+				Containers: []codersdk.WorkspaceAgentContainer{
+					{
+						ID:           "container-id",
+						FriendlyName: "container-name",
+						Running:      true,
+						Labels: map[string]string{
+							agentcontainers.DevcontainerLocalFolderLabel: "/workspace/project",
+							agentcontainers.DevcontainerConfigFileLabel:  "/workspace/project/.devcontainer/devcontainer.json",
+						},
+					},
+				},
+			},
+		}
+		fDCCLI := &fakeDevcontainerCLI{
+			config: agentcontainers.DevcontainerConfig{
+				MergedConfiguration: agentcontainers.DevcontainerMergedConfiguration{
+					ForwardPorts: []dcspec.ForwardPort{
+						{Integer: ptr.Ref[int64](8080)},
+						{String: ptr.Ref("3000")},
+						{String: ptr.Ref("db:5432")},
+						{Integer: ptr.Ref[int64](3000)},
+					},
+					PortsAttributes: map[string]agentcontainers.DevcontainerPortAttributes{
+						"3000":      {Label: "Web", Protocol: "https"},
+						"8000-8100": {Label: "API"},
+					},
+					Customizations: agentcontainers.DevcontainerMergedCustomizations{
+						VSCode: []agentcontainers.DevcontainerVSCodeCustomizations{
+							{Extensions: []string{"dbaeumer.vscode-eslint", "golang.go"}},
+							{Extensions: []string{"-dbaeumer.vscode-eslint", "GoLang.Go", "ms-python.python"}},
+						},
+					},
+				},
+			},
+		}
+
+		registered := make(chan []codersdk.WorkspaceAgentDevcontainer, 1)
+		api := agentcontainers.NewAPI(logger,
+			agentcontainers.WithLister(fLister),
+			agentcontainers.WithDevcontainerCLI(fDCCLI),
+			agentcontainers.WithWatcher(watcher.NewNoop()),
+			agentcontainers.WithAppRegisterer(func(devcontainers []codersdk.WorkspaceAgentDevcontainer) {
+				select {
+				case registered <- devcontainers:
+				default:
+				}
+			}),
+		)
+		defer api.Close()
+		r := chi.NewRouter()
+		r.Mount("/", api.Routes())
+
+		// The config is read in the background once the container is
+		// detected.
+		var response codersdk.WorkspaceAgentListContainersResponse
+		require.Eventually(t, func() bool {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if !assert.Equal(t, http.StatusOK, rec.Code) {
+				return false
+			}
+			response = codersdk.WorkspaceAgentListContainersResponse{}
+			if !assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response)) {
+				return false
+			}
+			return len(response.Devcontainers) == 1 && response.Devcontainers[0].ForwardedPorts != nil
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		dc := response.Devcontainers[0]
+		// The container doesn't exist, so its ports can't be forwarded.
+		assert.Equal(t, []codersdk.WorkspaceAgentDevcontainerPort{
+			{Port: 3000, Label: "Web", Protocol: "https"},
+			{Port: 8080, Label: "API"},
+		}, dc.ForwardedPorts)
+		assert.Equal(t, []string{"golang.go", "ms-python.python"}, dc.Extensions)
+		assert.Empty(t, api.ForwardedPorts())
+
+		// The ports of all devcontainers are passed to the app registerer.
+		devcontainers := testutil.RequireReceive(ctx, t, registered)
+		require.Len(t, devcontainers, 1)
+		assert.Equal(t, dc.Name, devcontainers[0].Name)
+		assert.Equal(t, dc.ForwardedPorts, devcontainers[0].ForwardedPorts)
+	})
+
 	t.Run("FileWatcher", func(t *testing.T) {
 		t.Parallel()
 
@@ -25,9 +25,10 @@ import (
 )
 
 // DockerEnvInfoer is an implementation of agentssh.EnvInfoer that returns
-// information about a container.
+// information about a container of any of the supported container runtimes.
 type DockerEnvInfoer struct {
 	usershell.SystemEnvInfo
+	runtime   ContainerRuntime
 	container string
 	user      *user.User
 	userShell string
@@ -36,13 +37,20 @@ type DockerEnvInfoer struct {
 
 // EnvInfo returns information about the environment of a container.
 func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerUser string) (*DockerEnvInfoer, error) {
+	// Find the runtime that runs the container, the inspect output is needed
+	// for the devcontainer environment below anyway.
+	runtime, in, err := findContainer(ctx, execer, container)
+	if err != nil {
+		return nil, xerrors.Errorf("get container: %w", err)
+	}
+
 	var dei DockerEnvInfoer
+	dei.runtime = runtime
 	dei.container = container
 
 	if containerUser == "" {
 		// Get the "default" user of the container if no user is specified.
-		// TODO: handle different container runtimes.
-		cmd, args := wrapDockerExec(container, "", "whoami")
+		cmd, args := wrapDockerExec(runtime, container, "", "whoami")
 		stdout, stderr, err := run(ctx, execer, cmd, args...)
 		if err != nil {
 			return nil, xerrors.Errorf("get container user: run whoami: %w: %s", err, stderr)
@@ -54,7 +62,7 @@ func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerU
 	}
 	// Now that we know the username, get the required info from the container.
 	// We can't assume the presence of `getent` so we'll just have to sniff /etc/passwd.
-	cmd, args := wrapDockerExec(container, containerUser, "cat", "/etc/passwd")
+	cmd, args := wrapDockerExec(runtime, container, containerUser, "cat", "/etc/passwd")
 	stdout, stderr, err := run(ctx, execer, cmd, args...)
 	if err != nil {
 		return nil, xerrors.Errorf("get container user: read /etc/passwd: %w: %q", err, stderr)
@@ -104,7 +112,7 @@ func EnvInfo(ctx context.Context, execer agentexec.Execer, container, containerU
 	// We need to inspect the container labels for remoteEnv and append these to
 	// the resulting docker exec command.
 	// ref: https://code.visualstudio.com/docs/devcontainers/attach-container
-	env, err := devcontainerEnv(ctx, execer, container)
+	env, err := devcontainerEnv(in)
 	if err != nil { // best effort.
 		return nil, xerrors.Errorf("read devcontainer remoteEnv: %w", err)
 	}
@@ -124,7 +132,7 @@ func (dei *DockerEnvInfoer) Shell(string) (string, error) {
 }
 
 func (dei *DockerEnvInfoer) ModifyCommand(cmd string, args ...string) (string, []string) {
-	// Wrap the command with `docker exec` and run it as the container user.
+	// Wrap the command with `<runtime> exec` and run it as the container user.
 	// There is some additional munging here regarding the container user and environment.
 	dockerArgs := []string{
 		"exec",
@@ -146,34 +154,19 @@ func (dei *DockerEnvInfoer) ModifyCommand(cmd string, args ...string) (string, [
 
 	// Append the container name and the command.
 	dockerArgs = append(dockerArgs, dei.container, cmd)
-	return "docker", append(dockerArgs, args...)
+	return string(dei.runtime), append(dockerArgs, args...)
 }
 
 // devcontainerEnv is a helper function that inspects the container labels to
 // find the required environment variables for running a command in the container.
-func devcontainerEnv(ctx context.Context, execer agentexec.Execer, container string) ([]string, error) {
-	stdout, stderr, err := runDockerInspect(ctx, execer, container)
-	if err != nil {
-		return nil, xerrors.Errorf("inspect container: %w: %q", err, stderr)
-	}
-
-	ins, _, err := convertDockerInspect(stdout)
-	if err != nil {
-		return nil, xerrors.Errorf("inspect container: %w", err)
-	}
-
-	if len(ins) != 1 {
-		return nil, xerrors.Errorf("inspect container: expected 1 container, got %d", len(ins))
-	}
-
-	in := ins[0]
-	if in.Labels == nil {
+func devcontainerEnv(in dockerInspect) ([]string, error) {
+	if in.Config.Labels == nil {
 		return nil, nil
 	}
 
 	// We want to look for the devcontainer metadata, which is in the
 	// value of the label `devcontainer.metadata`.
-	rawMeta, ok := in.Labels["devcontainer.metadata"]
+	rawMeta, ok := in.Config.Labels["devcontainer.metadata"]
 	if !ok {
 		return nil, nil
 	}
@@ -201,16 +194,16 @@ func devcontainerEnv(ctx context.Context, execer agentexec.Execer, container str
 }
 
 // wrapDockerExec is a helper function that wraps the given command and arguments
-// with a docker exec command that runs as the given user in the given
-// container. This is used to fetch information about a container prior to
-// running the actual command.
-func wrapDockerExec(containerName, userName, cmd string, args ...string) (string, []string) {
+// with an exec command of the container runtime that runs as the given user in
+// the given container. This is used to fetch information about a container
+// prior to running the actual command.
+func wrapDockerExec(runtime ContainerRuntime, containerName, userName, cmd string, args ...string) (string, []string) {
 	dockerArgs := []string{"exec", "--interactive"}
 	if userName != "" {
 		dockerArgs = append(dockerArgs, "--user", userName)
 	}
 	dockerArgs = append(dockerArgs, containerName, cmd)
-	return "docker", append(dockerArgs, args...)
+	return string(runtime), append(dockerArgs, args...)
 }
 
 // Helper function to run a command and return its stdout and stderr.
@@ -228,23 +221,26 @@ func run(ctx context.Context, execer agentexec.Execer, cmd string, args ...strin
 	return stdout, stderr, err
 }
 
-// DockerCLILister is a ContainerLister that lists containers using the docker CLI
+// DockerCLILister is a ContainerLister that lists containers using the docker
+// CLI, or the Docker-compatible CLI of another container runtime.
 type DockerCLILister struct {
-	execer agentexec.Execer
+	execer  agentexec.Execer
+	runtime ContainerRuntime
 }
 
 var _ Lister = &DockerCLILister{}
 
 func NewDocker(execer agentexec.Execer) Lister {
 	return &DockerCLILister{
-		execer: agentexec.DefaultExecer,
+		execer:  execer,
+		runtime: ContainerRuntimeDocker,
 	}
 }
 
 func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentListContainersResponse, error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
 	// List all container IDs, one per line, with no truncation
-	cmd := dcl.execer.CommandContext(ctx, "docker", "ps", "--all", "--quiet", "--no-trunc")
+	cmd := dcl.execer.CommandContext(ctx, string(dcl.runtime), "ps", "--all", "--quiet", "--no-trunc")
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
 	if err := cmd.Run(); err != nil {
@@ -252,7 +248,7 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 		// - docker not installed
 		// - docker not running
 		// - no permissions to talk to docker
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run docker ps: %w: %q", err, strings.TrimSpace(stderrBuf.String()))
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run %s ps: %w: %q", dcl.runtime, err, strings.TrimSpace(stderrBuf.String()))
 	}
 
 	ids := make([]string, 0)
@@ -265,7 +261,7 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 		ids = append(ids, tmp)
 	}
 	if err := scanner.Err(); err != nil {
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("scan docker ps output: %w", err)
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("scan %s ps output: %w", dcl.runtime, err)
 	}
 
 	res := codersdk.WorkspaceAgentListContainersResponse{
@@ -288,9 +284,9 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 	// will still contain valid JSON. We will just end up missing
 	// information about the removed container. We could potentially
 	// log this error, but I'm not sure it's worth it.
-	dockerInspectStdout, dockerInspectStderr, err := runDockerInspect(ctx, dcl.execer, ids...)
+	dockerInspectStdout, dockerInspectStderr, err := runDockerInspect(ctx, dcl.execer, dcl.runtime, ids...)
 	if err != nil {
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run docker inspect: %w: %s", err, dockerInspectStderr)
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("run %s inspect: %w: %s", dcl.runtime, err, dockerInspectStderr)
 	}
 
 	if len(dockerInspectStderr) > 0 {
@@ -299,7 +295,7 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 
 	outs, warns, err := convertDockerInspect(dockerInspectStdout)
 	if err != nil {
-		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("convert docker inspect output: %w", err)
+		return codersdk.WorkspaceAgentListContainersResponse{}, xerrors.Errorf("convert %s inspect output: %w", dcl.runtime, err)
 	}
 	res.Warnings = append(res.Warnings, warns...)
 	res.Containers = append(res.Containers, outs...)
@@ -307,19 +303,20 @@ func (dcl *DockerCLILister) List(ctx context.Context) (codersdk.WorkspaceAgentLi
 	return res, nil
 }
 
-// runDockerInspect is a helper function that runs `docker inspect` on the given
-// container IDs and returns the parsed output.
+// runDockerInspect is a helper function that runs `<runtime> inspect` on the
+// given container IDs and returns the parsed output.
 // The stderr output is also returned for logging purposes.
-func runDockerInspect(ctx context.Context, execer agentexec.Execer, ids ...string) (stdout, stderr []byte, err error) {
+func runDockerInspect(ctx context.Context, execer agentexec.Execer, runtime ContainerRuntime, ids ...string) (stdout, stderr []byte, err error) {
 	var stdoutBuf, stderrBuf bytes.Buffer
-	cmd := execer.CommandContext(ctx, "docker", append([]string{"inspect"}, ids...)...)
+	cmd := execer.CommandContext(ctx, string(runtime), append([]string{"inspect"}, ids...)...)
 	cmd.Stdout = &stdoutBuf
 	cmd.Stderr = &stderrBuf
 	err = cmd.Run()
 	stdout = bytes.TrimSpace(stdoutBuf.Bytes())
 	stderr = bytes.TrimSpace(stderrBuf.Bytes())
 	if err != nil {
-		if bytes.Contains(stderr, []byte("No such object:")) {
+		// Podman and nerdctl don't capitalize the error.
+		if bytes.Contains(bytes.ToLower(stderr), []byte("no such object:")) {
 			// This can happen if a container is deleted between the time we check for its existence and the time we inspect it.
 			return stdout, stderr, nil
 		}
@@ -363,7 +360,30 @@ type dockerInspectState struct {
 }
 
 type dockerInspectNetworkSettings struct {
-	Ports map[string][]dockerInspectPort `json:"Ports"`
+	Ports     map[string][]dockerInspectPort  `json:"Ports"`
+	IPAddress string                          `json:"IPAddress"`
+	Networks  map[string]dockerInspectNetwork `json:"Networks"`
+}
+
+type dockerInspectNetwork struct {
+	IPAddress string `json:"IPAddress"`
+}
+
+// ipAddress returns the IP address of the container on the default network,
+// or the first of its other networks by name. It's empty for containers
+// that use the network of the host.
+func (dins dockerInspectNetworkSettings) ipAddress() string {
+	if dins.IPAddress != "" {
+		return dins.IPAddress
+	}
+	names := maps.Keys(dins.Networks)
+	sort.Strings(names)
+	for _, name := range names {
+		if ip := dins.Networks[name].IPAddress; ip != "" {
+			return ip
+		}
+	}
+	return ""
 }
 
 func (dis dockerInspectState) String() string {
@@ -383,11 +403,24 @@ func (dis dockerInspectState) String() string {
 	return sb.String()
 }
 
-func convertDockerInspect(raw []byte) ([]codersdk.WorkspaceAgentContainer, []string, error) {
-	var warns []string
+// decodeDockerInspect decodes the output of `<runtime> inspect`. Empty output,
+// which runtimes may print when no container matches, contains no containers.
+func decodeDockerInspect(raw []byte) ([]dockerInspect, error) {
 	var ins []dockerInspect
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return ins, nil
+	}
 	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&ins); err != nil {
-		return nil, nil, xerrors.Errorf("decode docker inspect output: %w", err)
+		return nil, xerrors.Errorf("decode docker inspect output: %w", err)
+	}
+	return ins, nil
+}
+
+func convertDockerInspect(raw []byte) ([]codersdk.WorkspaceAgentContainer, []string, error) {
+	var warns []string
+	ins, err := decodeDockerInspect(raw)
+	if err != nil {
+		return nil, nil, err
 	}
 	outs := make([]codersdk.WorkspaceAgentContainer, 0, len(ins))
 
@@ -17,6 +17,7 @@ func TestWrapDockerExec(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
 		name          string
+		runtime       ContainerRuntime
 		containerUser string
 		cmdArgs       []string
 		wantCmd       []string
@@ -39,12 +40,30 @@ func TestWrapDockerExec(t *testing.T) {
 			cmdArgs:       []string{"my-cmd"},
 			wantCmd:       []string{"docker", "exec", "--interactive", "my-container", "my-cmd"},
 		},
+		{
+			name:          "podman",
+			runtime:       ContainerRuntimePodman,
+			containerUser: "my-user",
+			cmdArgs:       []string{"my-cmd", "arg1"},
+			wantCmd:       []string{"podman", "exec", "--interactive", "--user", "my-user", "my-container", "my-cmd", "arg1"},
+		},
+		{
+			name:          "nerdctl",
+			runtime:       ContainerRuntimeNerdctl,
+			containerUser: "my-user",
+			cmdArgs:       []string{"my-cmd", "arg1"},
+			wantCmd:       []string{"nerdctl", "exec", "--interactive", "--user", "my-user", "my-container", "my-cmd", "arg1"},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt // appease the linter even though this isn't needed anymore
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			actualCmd, actualArgs := wrapDockerExec("my-container", tt.containerUser, tt.cmdArgs[0], tt.cmdArgs[1:]...)
+			runtime := tt.runtime
+			if runtime == "" {
+				runtime = ContainerRuntimeDocker
+			}
+			actualCmd, actualArgs := wrapDockerExec(runtime, "my-container", tt.containerUser, tt.cmdArgs[0], tt.cmdArgs[1:]...)
 			assert.Equal(t, tt.wantCmd[0], actualCmd)
 			assert.Equal(t, tt.wantCmd[1:], actualArgs)
 		})
@@ -391,6 +410,36 @@ func TestConvertDockerInspect(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Podman prints the container name without a leading slash, and
+			// no host IP for ports bound to all interfaces.
+			name: "container_podman",
+			expect: []codersdk.WorkspaceAgentContainer{
+				{
+					CreatedAt:    time.Date(2025, 5, 2, 9, 14, 7, 522415862, time.UTC),
+					ID:           "4f1a1c7b6d2e1c1f0b7b0f1d2e3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e5f4",
+					FriendlyName: "festive_hopper",
+					Image:        "docker.io/library/debian:bookworm",
+					Labels: map[string]string{
+						"devcontainer.local_folder": "/home/coder/src",
+						"devcontainer.metadata":     `[{"remoteEnv": {"FOO": "bar"}}]`,
+					},
+					Running: true,
+					Status:  "running",
+					Ports: []codersdk.WorkspaceAgentContainerPort{
+						{
+							Network:  "tcp",
+							Port:     8080,
+							HostPort: 8080,
+							HostIP:   "",
+						},
+					},
+					Volumes: map[string]string{
+						"/home/coder/src": "/workspaces/src",
+					},
+				},
+			},
+		},
 	} {
 		// nolint:paralleltest // variable recapture no longer required
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,124 @@
+package agentcontainers
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ContainerRuntime is the CLI of a container runtime. All supported runtimes
+// provide a Docker-compatible CLI, so the same commands and output parsing are
+// used for each of them.
+type ContainerRuntime string
+
+const (
+	ContainerRuntimeDocker ContainerRuntime = "docker"
+	// ContainerRuntimePodman also covers rootless Podman, which needs no
+	// daemon.
+	ContainerRuntimePodman ContainerRuntime = "podman"
+	// ContainerRuntimeNerdctl is the Docker-compatible CLI for containerd.
+	ContainerRuntimeNerdctl ContainerRuntime = "nerdctl"
+)
+
+// containerRuntimes are the supported runtimes in the order they are probed.
+// Docker comes first to keep the behavior of existing workspaces, where a
+// Docker-compatible shim for Podman may also be installed.
+var containerRuntimes = []ContainerRuntime{
+	ContainerRuntimeDocker,
+	ContainerRuntimePodman,
+	ContainerRuntimeNerdctl,
+}
+
+// DetectContainerRuntime returns the first container runtime that is usable
+// in the workspace. A runtime is usable when `<cli> version` succeeds, which
+// requires both the CLI and, for Docker and containerd, a reachable daemon
+// socket.
+func DetectContainerRuntime(ctx context.Context, execer agentexec.Execer) (ContainerRuntime, bool) {
+	for _, runtime := range containerRuntimes {
+		_, _, err := run(ctx, execer, string(runtime), "version")
+		if err == nil {
+			return runtime, true
+		}
+	}
+	return "", false
+}
+
+// NewPodman returns a Lister that lists containers using the podman CLI.
+func NewPodman(execer agentexec.Execer) Lister {
+	return &DockerCLILister{
+		execer:  execer,
+		runtime: ContainerRuntimePodman,
+	}
+}
+
+// NewNerdctl returns a Lister that lists containers using the nerdctl CLI
+// for containerd.
+func NewNerdctl(execer agentexec.Execer) Lister {
+	return &DockerCLILister{
+		execer:  execer,
+		runtime: ContainerRuntimeNerdctl,
+	}
+}
+
+// NewLister returns a Lister for the first usable container runtime, see
+// DetectContainerRuntime. Runtimes are probed on the first call to List, and
+// on later calls until one is found, as the daemon may start after the agent.
+// Docker is used while no runtime is found.
+func NewLister(execer agentexec.Execer) Lister {
+	return &detectingLister{execer: execer}
+}
+
+type detectingLister struct {
+	execer agentexec.Execer
+
+	mu sync.Mutex
+	cl Lister
+}
+
+var _ Lister = &detectingLister{}
+
+func (l *detectingLister) List(ctx context.Context) (codersdk.WorkspaceAgentListContainersResponse, error) {
+	l.mu.Lock()
+	cl := l.cl
+	if cl == nil {
+		if runtime, ok := DetectContainerRuntime(ctx, l.execer); ok {
+			l.cl = &DockerCLILister{execer: l.execer, runtime: runtime}
+			cl = l.cl
+		}
+	}
+	l.mu.Unlock()
+	if cl == nil {
+		cl = NewDocker(l.execer)
+	}
+	return cl.List(ctx)
+}
+
+// findContainer inspects the container with the given ID or name using each
+// container runtime in turn, and returns the runtime that knows about it.
+func findContainer(ctx context.Context, execer agentexec.Execer, container string) (ContainerRuntime, dockerInspect, error) {
+	var firstErr error
+	for _, runtime := range containerRuntimes {
+		stdout, stderr, err := runDockerInspect(ctx, execer, runtime, container)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = xerrors.Errorf("%s inspect: %w: %q", runtime, err, stderr)
+			}
+			continue
+		}
+		ins, err := decodeDockerInspect(stdout)
+		if err != nil {
+			return "", dockerInspect{}, xerrors.Errorf("%s inspect: %w", runtime, err)
+		}
+		if len(ins) == 1 {
+			return runtime, ins[0], nil
+		}
+	}
+	if firstErr != nil {
+		return "", dockerInspect{}, xerrors.Errorf("container %q not found: %w", container, firstErr)
+	}
+	return "", dockerInspect{}, xerrors.Errorf("container %q not found", container)
+}
@@ -0,0 +1,205 @@
+package agentcontainers_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/pty"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestContainerRuntimes(t *testing.T) {
+	t.Parallel()
+
+	testExePath, err := os.Executable()
+	require.NoError(t, err, "get test executable path")
+	inspectFile, err := filepath.Abs(filepath.Join("testdata", "container_podman", "docker_inspect.json"))
+	require.NoError(t, err)
+	const containerID = "4f1a1c7b6d2e1c1f0b7b0f1d2e3c4b5a69788796a5b4c3d2e1f0a9b8c7d6e5f4"
+
+	for _, runtime := range []agentcontainers.ContainerRuntime{
+		agentcontainers.ContainerRuntimeDocker,
+		agentcontainers.ContainerRuntimePodman,
+		agentcontainers.ContainerRuntimeNerdctl,
+	} {
+		t.Run(string(runtime), func(t *testing.T) {
+			t.Parallel()
+
+			execer := &testContainerRuntimeExecer{
+				testExePath: testExePath,
+				runtime:     runtime,
+				inspectFile: inspectFile,
+			}
+
+			t.Run("Detect", func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitShort)
+
+				got, ok := agentcontainers.DetectContainerRuntime(ctx, execer)
+				require.True(t, ok)
+				assert.Equal(t, runtime, got)
+			})
+
+			t.Run("List", func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitShort)
+
+				res, err := agentcontainers.NewLister(execer).List(ctx)
+				require.NoError(t, err)
+				require.Len(t, res.Containers, 1)
+				assert.Equal(t, containerID, res.Containers[0].ID)
+				assert.Equal(t, "festive_hopper", res.Containers[0].FriendlyName)
+			})
+
+			t.Run("EnvInfo", func(t *testing.T) {
+				t.Parallel()
+				ctx := testutil.Context(t, testutil.WaitShort)
+
+				dei, err := agentcontainers.EnvInfo(ctx, execer, "festive_hopper", "")
+				require.NoError(t, err)
+				u, err := dei.User()
+				require.NoError(t, err)
+				assert.Equal(t, "coder", u.Username)
+				assert.Equal(t, "/home/coder", u.HomeDir)
+
+				cmd, args := dei.ModifyCommand("bash", "-l")
+				assert.Equal(t, string(runtime), cmd)
+				assert.Equal(t, []string{
+					"exec", "--interactive", "--tty",
+					"--user", "coder",
+					"--workdir", "/home/coder",
+					"--env", "FOO=bar",
+					"festive_hopper", "bash", "-l",
+				}, args)
+			})
+		})
+	}
+
+	t.Run("NoRuntime", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		execer := &testContainerRuntimeExecer{testExePath: testExePath}
+		_, ok := agentcontainers.DetectContainerRuntime(ctx, execer)
+		require.False(t, ok)
+		_, err := agentcontainers.EnvInfo(ctx, execer, "festive_hopper", "")
+		require.ErrorContains(t, err, "not found")
+	})
+}
+
+// testContainerRuntimeExecer implements the agentexec.Execer interface for
+// testing. Only the CLI of the given container runtime is available.
+type testContainerRuntimeExecer struct {
+	testExePath string
+	runtime     agentcontainers.ContainerRuntime
+	inspectFile string
+}
+
+// CommandContext returns a test binary command that simulates a container
+// runtime CLI.
+func (e *testContainerRuntimeExecer) CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	testArgs := []string{
+		"-test.run=TestContainerRuntimeHelperProcess",
+		"--",
+		name,
+	}
+	testArgs = append(testArgs, args...)
+
+	//nolint:gosec // This is a test binary, so we don't need to worry about command injection.
+	cmd := exec.CommandContext(ctx, e.testExePath, testArgs...)
+	// Set this environment variable so the child process knows it's the helper.
+	cmd.Env = append(os.Environ(),
+		"TEST_CONTAINER_RUNTIME_WANT_HELPER_PROCESS=1",
+		"TEST_CONTAINER_RUNTIME="+string(e.runtime),
+		"TEST_CONTAINER_RUNTIME_INSPECT_FILE="+e.inspectFile,
+	)
+	return cmd
+}
+
+// PTYCommandContext returns a PTY command.
+func (*testContainerRuntimeExecer) PTYCommandContext(_ context.Context, _ string, _ ...string) *pty.Cmd {
+	// This method shouldn't be called for our container runtime tests.
+	panic("PTYCommandContext not expected in container runtime tests")
+}
+
+// This is a special test helper that is executed as a subprocess.
+// It simulates the behavior of a Docker-compatible container runtime CLI with
+// a single container.
+//
+//nolint:revive,paralleltest // This is a test helper function.
+func TestContainerRuntimeHelperProcess(t *testing.T) {
+	// If not called by the test as a helper process, do nothing.
+	if os.Getenv("TEST_CONTAINER_RUNTIME_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	helperArgs := flag.Args()
+	if len(helperArgs) < 2 {
+		fmt.Fprintf(os.Stderr, "No command\n")
+		os.Exit(2)
+	}
+	if helperArgs[0] != os.Getenv("TEST_CONTAINER_RUNTIME") {
+		fmt.Fprintf(os.Stderr, "%s: command not found\n", helperArgs[0])
+		os.Exit(127)
+	}
+
+	inspect, err := os.ReadFile(os.Getenv("TEST_CONTAINER_RUNTIME_INSPECT_FILE"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Reading inspect file failed: %v\n", err)
+		os.Exit(2)
+	}
+	var containers []struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	}
+	if err := json.Unmarshal(inspect, &containers); err != nil || len(containers) != 1 {
+		fmt.Fprintf(os.Stderr, "Invalid inspect file: %v\n", err)
+		os.Exit(2)
+	}
+	known := []string{containers[0].ID, containers[0].Name}
+
+	args := helperArgs[1:]
+	switch args[0] {
+	case "version":
+		_, _ = fmt.Fprintln(os.Stdout, "Version: 1.0.0")
+	case "ps":
+		_, _ = fmt.Fprintln(os.Stdout, containers[0].ID)
+	case "inspect":
+		for _, id := range args[1:] {
+			if !slices.Contains(known, id) {
+				_, _ = fmt.Fprintln(os.Stdout, "[]")
+				fmt.Fprintf(os.Stderr, "Error: no such object: %q\n", id)
+				os.Exit(125)
+			}
+		}
+		_, _ = os.Stdout.Write(inspect)
+	case "exec":
+		command := strings.Join(args, " ")
+		switch {
+		case strings.HasSuffix(command, " whoami"):
+			_, _ = fmt.Fprintln(os.Stdout, "coder")
+		case strings.HasSuffix(command, " cat /etc/passwd"):
+			_, _ = fmt.Fprintln(os.Stdout, "root:x:0:0:root:/root:/bin/bash")
+			_, _ = fmt.Fprintln(os.Stdout, "coder:x:1000:1000:Coder,,,:/home/coder:/bin/bash")
+		default:
+			fmt.Fprintf(os.Stderr, "Unexpected exec: %s\n", command)
+			os.Exit(2)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
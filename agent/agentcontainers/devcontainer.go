@@ -1,10 +1,13 @@
 package agentcontainers
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"cdr.dev/slog"
@@ -117,3 +120,85 @@ func relativePathToAbs(workdir, path string) string {
 	}
 	return path
 }
+
+// devcontainerForwardedPorts returns the ports in forwardPorts of the
+// configuration, with their attributes in portsAttributes. Ports of other
+// hosts, e.g. "db:5432" for a Docker Compose service, are not supported.
+func devcontainerForwardedPorts(config DevcontainerMergedConfiguration) []codersdk.WorkspaceAgentDevcontainerPort {
+	var ports []codersdk.WorkspaceAgentDevcontainerPort
+	for _, fp := range config.ForwardPorts {
+		var port int64
+		switch {
+		case fp.Integer != nil:
+			port = *fp.Integer
+		case fp.String != nil:
+			var err error
+			port, err = strconv.ParseInt(*fp.String, 10, 32)
+			if err != nil {
+				continue
+			}
+		}
+		if port < 1 || port > 65535 {
+			continue
+		}
+		if slices.ContainsFunc(ports, func(p codersdk.WorkspaceAgentDevcontainerPort) bool {
+			return int64(p.Port) == port
+		}) {
+			continue
+		}
+		attrs := devcontainerPortAttributes(config.PortsAttributes, port)
+		ports = append(ports, codersdk.WorkspaceAgentDevcontainerPort{
+			Port:     uint16(port), // #nosec G115 - Checked above.
+			Label:    attrs.Label,
+			Protocol: attrs.Protocol,
+		})
+	}
+	slices.SortFunc(ports, func(a, b codersdk.WorkspaceAgentDevcontainerPort) int {
+		return cmp.Compare(a.Port, b.Port)
+	})
+	return ports
+}
+
+// devcontainerPortAttributes returns the attributes of port, which are keyed
+// by the port or a range of ports, e.g. "3000-3010". Attributes keyed by a
+// regular expression for the name of the process aren't supported.
+func devcontainerPortAttributes(attrs map[string]DevcontainerPortAttributes, port int64) DevcontainerPortAttributes {
+	if a, ok := attrs[strconv.FormatInt(port, 10)]; ok {
+		return a
+	}
+	for key, a := range attrs {
+		lo, hi, ok := strings.Cut(key, "-")
+		if !ok {
+			continue
+		}
+		start, err1 := strconv.ParseInt(strings.TrimSpace(lo), 10, 32)
+		end, err2 := strconv.ParseInt(strings.TrimSpace(hi), 10, 32)
+		if err1 == nil && err2 == nil && start <= port && port <= end {
+			return a
+		}
+	}
+	return DevcontainerPortAttributes{}
+}
+
+// devcontainerVSCodeExtensions returns the VS Code extensions in the
+// customizations of the configuration. As with VS Code, an extension that is
+// prefixed with "-" is removed from the extensions declared before it.
+func devcontainerVSCodeExtensions(config DevcontainerMergedConfiguration) []string {
+	var extensions []string
+	for _, vscode := range config.Customizations.VSCode {
+		for _, ext := range vscode.Extensions {
+			if removed, ok := strings.CutPrefix(ext, "-"); ok {
+				extensions = slices.DeleteFunc(extensions, func(e string) bool {
+					return strings.EqualFold(e, removed)
+				})
+				continue
+			}
+			if !slices.ContainsFunc(extensions, func(e string) bool {
+				return strings.EqualFold(e, ext)
+			}) {
+				extensions = append(extensions, ext)
+			}
+		}
+	}
+	return extensions
+}
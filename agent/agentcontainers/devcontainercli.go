@@ -11,12 +11,52 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentcontainers/dcspec"
 	"github.com/coder/coder/v2/agent/agentexec"
 )
 
 // DevcontainerCLI is an interface for the devcontainer CLI.
 type DevcontainerCLI interface {
 	Up(ctx context.Context, workspaceFolder, configPath string, opts ...DevcontainerCLIUpOptions) (id string, err error)
+	ReadConfig(ctx context.Context, workspaceFolder, configPath string) (DevcontainerConfig, error)
+}
+
+// DevcontainerConfig is the configuration of a devcontainer as returned by
+// the devcontainer CLI read-configuration command. Only the fields used by
+// the agent are decoded.
+type DevcontainerConfig struct {
+	// MergedConfiguration is the devcontainer.json merged with the
+	// metadata of the image and the features of the devcontainer.
+	MergedConfiguration DevcontainerMergedConfiguration `json:"mergedConfiguration"`
+}
+
+// DevcontainerMergedConfiguration is the merged configuration of a
+// devcontainer, see DevcontainerConfig.
+type DevcontainerMergedConfiguration struct {
+	ForwardPorts    []dcspec.ForwardPort                  `json:"forwardPorts"`
+	PortsAttributes map[string]DevcontainerPortAttributes `json:"portsAttributes"`
+	// Customizations are merged into a list per tool, in the order of the
+	// image, the features and devcontainer.json.
+	Customizations DevcontainerMergedCustomizations `json:"customizations"`
+}
+
+// DevcontainerPortAttributes are the attributes of a port, or a range of
+// ports, in portsAttributes.
+type DevcontainerPortAttributes struct {
+	Label    string `json:"label"`
+	Protocol string `json:"protocol"`
+}
+
+// DevcontainerMergedCustomizations are the customizations of a devcontainer
+// that the agent supports.
+type DevcontainerMergedCustomizations struct {
+	VSCode []DevcontainerVSCodeCustomizations `json:"vscode"`
+}
+
+// DevcontainerVSCodeCustomizations are the VS Code customizations of a
+// devcontainer.
+type DevcontainerVSCodeCustomizations struct {
+	Extensions []string `json:"extensions"`
 }
 
 // DevcontainerCLIUpOptions are options for the devcontainer CLI up
@@ -117,6 +157,62 @@ func (d *devcontainerCLI) Up(ctx context.Context, workspaceFolder, configPath st
 	return result.ContainerID, nil
 }
 
+func (d *devcontainerCLI) ReadConfig(ctx context.Context, workspaceFolder, configPath string) (DevcontainerConfig, error) {
+	logger := d.logger.With(slog.F("workspace_folder", workspaceFolder), slog.F("config_path", configPath))
+
+	args := []string{
+		"read-configuration",
+		"--include-merged-configuration",
+		"--workspace-folder", workspaceFolder,
+	}
+	if configPath != "" {
+		args = append(args, "--config", configPath)
+	}
+	cmd := d.execer.CommandContext(ctx, "devcontainer", args...)
+
+	var stdoutBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &devcontainerCLILogWriter{ctx: ctx, logger: logger.With(slog.F("stderr", true))}
+
+	err := cmd.Run()
+	config, err2 := parseDevcontainerCLIReadConfig(stdoutBuf.Bytes())
+	if err != nil {
+		if err2 != nil {
+			err = errors.Join(err, err2)
+		}
+		return DevcontainerConfig{}, err
+	}
+	if err2 != nil {
+		logger.Error(ctx, "parse devcontainer configuration failed", slog.Error(err2))
+		return DevcontainerConfig{}, err2
+	}
+	return config, nil
+}
+
+// parseDevcontainerCLIReadConfig parses the output of the read-configuration
+// command, the last line of which is a JSON object. Errors are reported in
+// the same format as the result of the up command.
+func parseDevcontainerCLIReadConfig(p []byte) (DevcontainerConfig, error) {
+	// The configuration may be longer than the maximum line length of a
+	// bufio.Scanner.
+	lines := bytes.Split(bytes.TrimSpace(p), []byte("\n"))
+	lastLine := bytes.TrimSpace(lines[len(lines)-1])
+	if len(lastLine) == 0 || lastLine[0] != '{' {
+		return DevcontainerConfig{}, xerrors.Errorf("devcontainer configuration is not json: %q", string(lastLine))
+	}
+	var result struct {
+		devcontainerCLIResult
+		DevcontainerConfig
+	}
+	if err := json.Unmarshal(lastLine, &result); err != nil {
+		return DevcontainerConfig{}, xerrors.Errorf("parse devcontainer configuration: %w", err)
+	}
+	if result.Outcome != "" {
+		return DevcontainerConfig{}, xerrors.Errorf("devcontainer read-configuration failed: %s (description: %s, message: %s)", result.Outcome, result.Description, result.Message)
+	}
+	return result.DevcontainerConfig, nil
+}
+
 // parseDevcontainerCLILastLine parses the last line of the devcontainer CLI output
 // which is a JSON object.
 func parseDevcontainerCLILastLine(ctx context.Context, logger slog.Logger, p []byte) (result devcontainerCLIResult, err error) {
@@ -126,6 +126,79 @@ func TestDevcontainerCLI_ArgsAndParsing(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("ReadConfig", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			logFile   string
+			workspace string
+			config    string
+			wantArgs  string
+			wantError bool
+		}{
+			{
+				name:      "success",
+				logFile:   "read-configuration.log",
+				workspace: "/test/workspace",
+				wantArgs:  "read-configuration --include-merged-configuration --workspace-folder /test/workspace",
+				wantError: false,
+			},
+			{
+				name:      "success with config",
+				logFile:   "read-configuration.log",
+				workspace: "/test/workspace",
+				config:    "/test/config.json",
+				wantArgs:  "read-configuration --include-merged-configuration --workspace-folder /test/workspace --config /test/config.json",
+				wantError: false,
+			},
+			{
+				name:      "does not exist",
+				logFile:   "read-configuration-error.log",
+				workspace: "/test/workspace",
+				wantArgs:  "read-configuration --include-merged-configuration --workspace-folder /test/workspace",
+				wantError: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				ctx := testutil.Context(t, testutil.WaitMedium)
+
+				testExecer := &testDevcontainerExecer{
+					testExePath: testExePath,
+					wantArgs:    tt.wantArgs,
+					wantError:   tt.wantError,
+					logFile:     filepath.Join("testdata", "devcontainercli", "parse", tt.logFile),
+				}
+
+				dccli := agentcontainers.NewDevcontainerCLI(logger, testExecer)
+				config, err := dccli.ReadConfig(ctx, tt.workspace, tt.config)
+				if tt.wantError {
+					assert.Error(t, err, "want error")
+					assert.Empty(t, config.MergedConfiguration.ForwardPorts, "expected no forwarded ports")
+					return
+				}
+				require.NoError(t, err, "want no error")
+				merged := config.MergedConfiguration
+				require.Len(t, merged.ForwardPorts, 3, "forwarded ports")
+				assert.EqualValues(t, 3000, *merged.ForwardPorts[0].Integer)
+				assert.Equal(t, "8080", *merged.ForwardPorts[1].String)
+				assert.Equal(t, "db:5432", *merged.ForwardPorts[2].String)
+				assert.Equal(t, map[string]agentcontainers.DevcontainerPortAttributes{
+					"3000":      {Label: "Web", Protocol: "https"},
+					"8000-8100": {Label: "API"},
+				}, merged.PortsAttributes)
+				assert.Equal(t, []agentcontainers.DevcontainerVSCodeCustomizations{
+					{Extensions: []string{"dbaeumer.vscode-eslint"}},
+					{Extensions: []string{"-dbaeumer.vscode-eslint", "golang.go"}},
+				}, merged.Customizations.VSCode)
+			})
+		}
+	})
 }
 
 // TestDevcontainerCLI_WithOutput tests that WithOutput captures CLI
@@ -0,0 +1,193 @@
+package agentcontainers
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const portForwardDialTimeout = 10 * time.Second
+
+// portForwarder forwards ports on the loopback interface of the workspace to
+// devcontainers. This makes the forwardPorts of a devcontainer reachable
+// like the ports of processes that run in the workspace, e.g. through port
+// forwarding and the listening ports of the agent.
+type portForwarder struct {
+	logger slog.Logger
+
+	mu       sync.Mutex
+	closed   bool
+	forwards map[uint16]*portForward
+	wg       sync.WaitGroup
+}
+
+type portForward struct {
+	workspaceFolder string
+	// name is shown as the process of the port in the listening ports.
+	name     string
+	target   string
+	listener net.Listener
+	cancel   context.CancelFunc
+}
+
+func newPortForwarder(logger slog.Logger) *portForwarder {
+	return &portForwarder{
+		logger:   logger,
+		forwards: make(map[uint16]*portForward),
+	}
+}
+
+// forward replaces the ports forwarded to the devcontainer in workspaceFolder
+// with ports, forwarded to the container at ip. The returned ports are
+// marked as forwarded when they could be listened on, ports that are in use
+// in the workspace are skipped.
+func (f *portForwarder) forward(ctx context.Context, workspaceFolder, name, ip string, ports []codersdk.WorkspaceAgentDevcontainerPort) []codersdk.WorkspaceAgentDevcontainerPort {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopLocked(workspaceFolder)
+	if f.closed {
+		return ports
+	}
+
+	forwarded := make([]codersdk.WorkspaceAgentDevcontainerPort, 0, len(ports))
+	for _, port := range ports {
+		port.Forwarded = false
+		logger := f.logger.With(slog.F("workspace_folder", workspaceFolder), slog.F("port", port.Port))
+		if other, ok := f.forwards[port.Port]; ok {
+			logger.Warn(ctx, "port is already forwarded to another devcontainer", slog.F("other_workspace_folder", other.workspaceFolder))
+			forwarded = append(forwarded, port)
+			continue
+		}
+		portStr := strconv.Itoa(int(port.Port))
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", portStr))
+		if err != nil {
+			// The port is most likely in use, e.g. published by the
+			// container or used by a process in the workspace.
+			logger.Warn(ctx, "listen for devcontainer port failed", slog.Error(err))
+			forwarded = append(forwarded, port)
+			continue
+		}
+
+		fwdName := name
+		if port.Label != "" {
+			fwdName = port.Label
+		}
+		fwdCtx, cancel := context.WithCancel(context.Background())
+		fwd := &portForward{
+			workspaceFolder: workspaceFolder,
+			name:            fwdName,
+			target:          net.JoinHostPort(ip, portStr),
+			listener:        l,
+			cancel:          cancel,
+		}
+		f.forwards[port.Port] = fwd
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			f.serve(fwdCtx, logger, fwd)
+		}()
+
+		port.Forwarded = true
+		forwarded = append(forwarded, port)
+	}
+	return forwarded
+}
+
+func (f *portForwarder) serve(ctx context.Context, logger slog.Logger, fwd *portForward) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := fwd.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn(ctx, "accept devcontainer port connection failed", slog.Error(err))
+			}
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+
+			d := net.Dialer{Timeout: portForwardDialTimeout}
+			target, err := d.DialContext(ctx, "tcp", fwd.target)
+			if err != nil {
+				logger.Debug(ctx, "dial devcontainer port failed", slog.F("target", fwd.target), slog.Error(err))
+				return
+			}
+			defer target.Close()
+			bicopy(ctx, conn, target)
+		}()
+	}
+}
+
+// stop stops forwarding the ports of the devcontainer in workspaceFolder.
+func (f *portForwarder) stop(workspaceFolder string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopLocked(workspaceFolder)
+}
+
+func (f *portForwarder) stopLocked(workspaceFolder string) {
+	for port, fwd := range f.forwards {
+		if fwd.workspaceFolder != workspaceFolder {
+			continue
+		}
+		fwd.cancel()
+		_ = fwd.listener.Close()
+		delete(f.forwards, port)
+	}
+}
+
+// names returns the forwarded ports with the name of the devcontainer, or
+// the label of the port.
+func (f *portForwarder) names() map[uint16]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make(map[uint16]string, len(f.forwards))
+	for port, fwd := range f.forwards {
+		names[port] = fwd.name
+	}
+	return names
+}
+
+// close stops all forwards and waits for their connections to close.
+func (f *portForwarder) close() {
+	f.mu.Lock()
+	f.closed = true
+	for port, fwd := range f.forwards {
+		fwd.cancel()
+		_ = fwd.listener.Close()
+		delete(f.forwards, port)
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+}
+
+// bicopy copies data between c1 and c2 until either side is done, or the
+// context is canceled.
+func bicopy(ctx context.Context, c1, c2 io.ReadWriteCloser) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	copyFunc := func(dst io.Writer, src io.Reader) {
+		// If one side of the copy fails, ensure the other one exits as
+		// well.
+		defer cancel()
+		_, _ = io.Copy(dst, src)
+	}
+	go copyFunc(c1, c2)
+	go copyFunc(c2, c1)
+
+	<-ctx.Done()
+	// Closing the connections unblocks the copies.
+	_ = c1.Close()
+	_ = c2.Close()
+}
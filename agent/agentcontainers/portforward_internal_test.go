@@ -0,0 +1,82 @@
+package agentcontainers
+
+import (
+	"io"
+	"net"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPortForwarder(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		// The container is simulated by a listener on another loopback
+		// address, only Linux routes all of 127.0.0.0/8 by default.
+		t.Skip("Only Linux routes 127.0.0.0/8 to the loopback interface")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	// An echo server in place of the container.
+	target, err := net.Listen("tcp", "127.0.0.2:0")
+	require.NoError(t, err)
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	port := uint16(target.Addr().(*net.TCPAddr).Port) // #nosec G115 - A TCP port.
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
+
+	f := newPortForwarder(logger)
+	defer f.close()
+
+	ports := f.forward(ctx, "/workspace/one", "one", "127.0.0.2", []codersdk.WorkspaceAgentDevcontainerPort{
+		{Port: port, Label: "Web"},
+	})
+	require.Equal(t, []codersdk.WorkspaceAgentDevcontainerPort{
+		{Port: port, Label: "Web", Forwarded: true},
+	}, ports)
+	assert.Equal(t, map[uint16]string{port: "Web"}, f.names())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+	_ = conn.Close()
+
+	// A port can only be forwarded to one devcontainer.
+	ports = f.forward(ctx, "/workspace/two", "two", "127.0.0.2", []codersdk.WorkspaceAgentDevcontainerPort{
+		{Port: port},
+	})
+	require.Equal(t, []codersdk.WorkspaceAgentDevcontainerPort{
+		{Port: port, Forwarded: false},
+	}, ports)
+
+	f.stop("/workspace/one")
+	assert.Empty(t, f.names())
+	_, err = net.Dial("tcp", addr)
+	require.Error(t, err)
+}
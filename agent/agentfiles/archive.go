@@ -0,0 +1,198 @@
+// Package agentfiles streams files and directories to and from workspaces as
+// tar archives.
+package agentfiles
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// WriteTar writes src, a file or a directory, to w as a tar archive.
+// Directories are archived recursively. Entries are rooted at name, or at the
+// base name of src when name is empty, so archiving /home/coder/repo produces
+// the entries repo, repo/README.md and so on.
+//
+// Symbolic links are archived as links and are not followed. Files that are
+// neither regular files, directories nor links are skipped.
+func WriteTar(ctx context.Context, w io.Writer, src, name string) error {
+	if name == "" {
+		name = filepath.Base(src)
+	}
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		switch {
+		case info.Mode().IsRegular(), info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err = os.Readlink(p)
+			if err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return xerrors.Errorf("tar header for %q: %w", p, err)
+		}
+		hdr.Name = entryName
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		// Owners are not preserved, the extracted files belong to the user
+		// running the extraction.
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		// codeql[go/path-injection] - The intent is to allow the user to copy any file in their workspace.
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.CopyN(tw, f, hdr.Size)
+		return err
+	})
+	if err != nil {
+		return xerrors.Errorf("archive %q: %w", src, err)
+	}
+	return tw.Close()
+}
+
+// ExtractTar extracts the tar archive read from r into the directory dst,
+// which is created if it doesn't exist. When name isn't empty, the first
+// element of every entry is replaced with it, which copies an archived file or
+// directory to a different name.
+//
+// Entries that would be extracted outside of dst are rejected.
+func ExtractTar(ctx context.Context, r io.Reader, dst, name string) error {
+	err := os.MkdirAll(dst, 0o755)
+	if err != nil {
+		return xerrors.Errorf("create directory %q: %w", dst, err)
+	}
+
+	// Links extracted from the archive, entries below them would be written
+	// to wherever they point to.
+	links := map[string]struct{}{}
+	tr := tar.NewReader(r)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		hdr, err := tr.Next()
+		if xerrors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read archive: %w", err)
+		}
+
+		entryName, err := entryPath(hdr.Name, name)
+		if err != nil {
+			return err
+		}
+		for dir := path.Dir(entryName); dir != "."; dir = path.Dir(dir) {
+			if _, ok := links[dir]; ok {
+				return xerrors.Errorf("invalid archive entry %q: parent %q is a link", hdr.Name, dir)
+			}
+		}
+		target := filepath.Join(dst, filepath.FromSlash(entryName))
+		mode := hdr.FileInfo().Mode().Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+			if err == nil {
+				err = os.Chmod(target, mode)
+			}
+		case tar.TypeReg:
+			err = extractFile(tr, target, mode)
+		case tar.TypeSymlink:
+			err = os.MkdirAll(filepath.Dir(target), 0o755)
+			if err == nil {
+				_ = os.Remove(target)
+				err = os.Symlink(hdr.Linkname, target)
+			}
+			links[entryName] = struct{}{}
+		default:
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("extract %q: %w", hdr.Name, err)
+		}
+		if hdr.Typeflag != tar.TypeSymlink {
+			_ = os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		}
+	}
+}
+
+// entryPath cleans the name of a tar entry and replaces its first element
+// with root, if set.
+func entryPath(entryName, root string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(entryName, "./"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", xerrors.Errorf("invalid archive entry %q", entryName)
+	}
+	if root == "" {
+		return cleaned, nil
+	}
+	if i := strings.IndexByte(cleaned, '/'); i >= 0 {
+		return path.Join(root, cleaned[i+1:]), nil
+	}
+	return root, nil
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return err
+	}
+	// codeql[go/path-injection] - The intent is to allow the user to write any file in their workspace.
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	// OpenFile doesn't change the mode of existing files.
+	return os.Chmod(target, mode)
+}
@@ -0,0 +1,114 @@
+package agentfiles_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		src := filepath.Join(t.TempDir(), "repo")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("readme"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("#!/bin/sh"), 0o755))
+		if runtime.GOOS != "windows" {
+			require.NoError(t, os.Symlink("README.md", filepath.Join(src, "link")))
+		}
+
+		var buf bytes.Buffer
+		err := agentfiles.WriteTar(ctx, &buf, src, "")
+		require.NoError(t, err)
+
+		dst := t.TempDir()
+		err = agentfiles.ExtractTar(ctx, &buf, dst, "")
+		require.NoError(t, err)
+
+		b, err := os.ReadFile(filepath.Join(dst, "repo", "README.md"))
+		require.NoError(t, err)
+		require.Equal(t, "readme", string(b))
+		b, err = os.ReadFile(filepath.Join(dst, "repo", "sub", "run.sh"))
+		require.NoError(t, err)
+		require.Equal(t, "#!/bin/sh", string(b))
+		if runtime.GOOS != "windows" {
+			stat, err := os.Stat(filepath.Join(dst, "repo", "sub", "run.sh"))
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0o755), stat.Mode().Perm())
+			link, err := os.Readlink(filepath.Join(dst, "repo", "link"))
+			require.NoError(t, err)
+			require.Equal(t, "README.md", link)
+		}
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		src := filepath.Join(t.TempDir(), "hello.txt")
+		require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+		var buf bytes.Buffer
+		err := agentfiles.WriteTar(ctx, &buf, src, "")
+		require.NoError(t, err)
+
+		dst := t.TempDir()
+		err = agentfiles.ExtractTar(ctx, &buf, dst, "world.txt")
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(dst, "world.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(b))
+	})
+
+	t.Run("Traversal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		for _, name := range []string{"../escape", "/etc/escape", "a/../../escape"} {
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: 1}))
+			_, err := tw.Write([]byte("x"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+
+			dst := filepath.Join(t.TempDir(), "dst")
+			err = agentfiles.ExtractTar(ctx, &buf, dst, "")
+			require.ErrorContains(t, err, "invalid archive entry", name)
+			require.NoFileExists(t, filepath.Join(filepath.Dir(dst), "escape"))
+		}
+	})
+
+	t.Run("ThroughLink", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require elevated privileges on Windows")
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		outside := t.TempDir()
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside}))
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "link/escape", Typeflag: tar.TypeReg, Mode: 0o644, Size: 1}))
+		_, err := tw.Write([]byte("x"))
+		require.NoError(t, err)
+		require.NoError(t, tw.Close())
+
+		err = agentfiles.ExtractTar(ctx, &buf, t.TempDir(), "")
+		require.ErrorContains(t, err, "is a link")
+		require.NoFileExists(t, filepath.Join(outside, "escape"))
+	})
+}
@@ -0,0 +1,199 @@
+// Package agentproc lists and signals the processes running in a workspace,
+// and attributes them to the session of the agent that started them.
+package agentproc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// EnvOwner is set in the environment of the processes started by a session
+// of the agent. It's inherited by the processes they start, which attributes
+// them to the same session.
+const EnvOwner = "CODER_AGENT_PROCESS_OWNER"
+
+var (
+	// ErrNotFound is returned when the process to signal does not exist.
+	ErrNotFound = xerrors.New("process not found")
+	// ErrProtectedProcess is returned when signaling the agent itself, or
+	// the init process of the workspace.
+	ErrProtectedProcess = xerrors.New("cannot signal the agent or init process")
+	// ErrUnsupportedSignal is returned for signals that aren't in signals.
+	ErrUnsupportedSignal = xerrors.New("unsupported signal")
+)
+
+// cpuSampleInterval is the interval the CPU usage of processes is measured
+// over. The usage over the lifetime of a process hides processes that only
+// recently started to spin.
+const cpuSampleInterval = 500 * time.Millisecond
+
+// signals are the signals that can be sent to a process, by name. These are
+// available on all platforms, although only KILL is supported on Windows.
+var signals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"TERM": syscall.SIGTERM,
+}
+
+// OwnerEnv returns the environment variable that attributes a process to
+// owner, see EnvOwner.
+func OwnerEnv(owner workspacesdk.ProcessOwner) string {
+	return EnvOwner + "=" + string(owner.Type) + ":" + owner.ID + ":" + owner.Name
+}
+
+// parseOwnerEnv returns the owner set in the environment env, if any.
+func parseOwnerEnv(env []string) *workspacesdk.ProcessOwner {
+	// The last value wins, like it does for exec.Cmd.
+	for _, kv := range slices.Backward(env) {
+		value, ok := strings.CutPrefix(kv, EnvOwner+"=")
+		if !ok {
+			continue
+		}
+		parts := strings.SplitN(value, ":", 3)
+		if len(parts) != 3 {
+			return nil
+		}
+		return &workspacesdk.ProcessOwner{
+			Type: workspacesdk.ProcessOwnerType(parts[0]),
+			ID:   parts[1],
+			Name: parts[2],
+		}
+	}
+	return nil
+}
+
+// List returns the processes running in the workspace, ordered by PID.
+// Processes that exit while they are listed are skipped. The CPU usage is
+// sampled over cpuSampleInterval, so List takes at least that long.
+//
+// A process is owned by the session set in its environment or, if that's
+// not set or can't be read, the owner of its closest ancestor. Processes that
+// were reparented, e.g. daemons, are only attributed if their environment
+// can be read.
+func List(ctx context.Context) ([]workspacesdk.Process, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("list processes: %w", err)
+	}
+
+	cpuTimes := make(map[int32]float64, len(procs))
+	for _, p := range procs {
+		if times, err := p.TimesWithContext(ctx); err == nil {
+			cpuTimes[p.Pid] = times.User + times.System
+		}
+	}
+	sampleStart := time.Now()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(cpuSampleInterval):
+	}
+
+	list := make([]workspacesdk.Process, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			// The process most likely exited.
+			continue
+		}
+		proc := workspacesdk.Process{
+			PID:  p.Pid,
+			Name: name,
+		}
+		// Any of these may fail for processes of other users, or ones that
+		// just exited. What could be read is still useful.
+		proc.PPID, _ = p.PpidWithContext(ctx)
+		proc.Username, _ = p.UsernameWithContext(ctx)
+		proc.Cmdline, _ = p.CmdlineWithContext(ctx)
+		if before, ok := cpuTimes[p.Pid]; ok {
+			if times, err := p.TimesWithContext(ctx); err == nil {
+				elapsed := time.Since(sampleStart).Seconds()
+				proc.CPUPercent = max(times.User+times.System-before, 0) / elapsed * 100
+			}
+		}
+		proc.MemoryPercent, _ = p.MemoryPercentWithContext(ctx)
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			proc.MemoryRSS = mem.RSS
+		}
+		if created, err := p.CreateTimeWithContext(ctx); err == nil {
+			proc.CreatedAt = time.UnixMilli(created)
+		}
+		if env, err := p.EnvironWithContext(ctx); err == nil {
+			proc.Owner = parseOwnerEnv(env)
+		}
+		list = append(list, proc)
+	}
+	slices.SortFunc(list, func(a, b workspacesdk.Process) int {
+		return int(a.PID - b.PID)
+	})
+
+	byPID := make(map[int32]*workspacesdk.Process, len(list))
+	for i := range list {
+		byPID[list[i].PID] = &list[i]
+	}
+	for i := range list {
+		if list[i].Owner != nil {
+			continue
+		}
+		// PIDs may be reused while processes are listed, so the walk is
+		// bounded in case the parents form a cycle.
+		parent := byPID[list[i].PPID]
+		for range len(list) {
+			if parent == nil || parent.PID == parent.PPID {
+				break
+			}
+			if parent.Owner != nil {
+				owner := *parent.Owner
+				list[i].Owner = &owner
+				break
+			}
+			parent = byPID[parent.PPID]
+		}
+	}
+	return list, nil
+}
+
+// Signal sends the signal with the given name, e.g. "TERM", to the process
+// with the given PID. The signal defaults to TERM. The agent and the init
+// process, which would take the workspace down, can't be signaled.
+func Signal(ctx context.Context, pid int32, signal string) error {
+	if signal == "" {
+		signal = "TERM"
+	}
+	sig, ok := signals[strings.TrimPrefix(strings.ToUpper(signal), "SIG")]
+	if !ok {
+		return xerrors.Errorf("%w %q", ErrUnsupportedSignal, signal)
+	}
+	if pid <= 1 || int(pid) == os.Getpid() {
+		return ErrProtectedProcess
+	}
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		if errors.Is(err, process.ErrorProcessNotRunning) {
+			return ErrNotFound
+		}
+		return xerrors.Errorf("find process: %w", err)
+	}
+	if sig == syscall.SIGKILL {
+		// Kill is supported on all platforms, unlike other signals.
+		err = p.KillWithContext(ctx)
+	} else {
+		err = p.SendSignalWithContext(ctx, sig)
+	}
+	if errors.Is(err, os.ErrProcessDone) {
+		return ErrNotFound
+	}
+	return err
+}
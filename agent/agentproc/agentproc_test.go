@@ -0,0 +1,106 @@
+package agentproc_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("The environment of processes can only be read on Linux")
+	}
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	owner := workspacesdk.ProcessOwner{
+		Type: workspacesdk.ProcessOwnerTypeScript,
+		ID:   "c5d5a3a3-2bd6-4d6b-a1b4-2f1b4e0d8f2c",
+		Name: "Start: the server",
+	}
+	// The shell waits for sleep, which inherits the owner of the shell.
+	cmd := exec.Command("sh", "-c", "sleep 3600 & wait")
+	cmd.Env = append(os.Environ(), agentproc.OwnerEnv(owner))
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	var sleepPID int32
+	t.Cleanup(func() {
+		if sleepPID != 0 {
+			_ = agentproc.Signal(ctx, sleepPID, "KILL")
+		}
+	})
+	require.Eventually(t, func() bool {
+		procs, err := agentproc.List(ctx)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		for _, proc := range procs {
+			if proc.PPID == int32(cmd.Process.Pid) && proc.Name == "sleep" {
+				assert.Equal(t, &owner, proc.Owner)
+				assert.Equal(t, "sleep 3600", proc.Cmdline)
+				assert.NotZero(t, proc.MemoryRSS)
+				sleepPID = proc.PID
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestSignal(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Signals other than KILL are not supported on Windows")
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		cmd := exec.Command("sleep", "3600")
+		require.NoError(t, cmd.Start())
+		err := agentproc.Signal(ctx, int32(cmd.Process.Pid), "SIGTERM")
+		require.NoError(t, err)
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, cmd.Wait(), &exitErr)
+
+		err = agentproc.Signal(ctx, int32(cmd.Process.Pid), "")
+		require.ErrorIs(t, err, agentproc.ErrNotFound)
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := agentproc.Signal(ctx, int32(os.Getpid()), "KILL")
+		require.ErrorIs(t, err, agentproc.ErrProtectedProcess)
+	})
+
+	t.Run("Init", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := agentproc.Signal(ctx, 1, "KILL")
+		require.ErrorIs(t, err, agentproc.ErrProtectedProcess)
+	})
+
+	t.Run("UnsupportedSignal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		err := agentproc.Signal(ctx, int32(os.Getpid()), "STOP")
+		require.ErrorIs(t, err, agentproc.ErrUnsupportedSignal)
+	})
+}
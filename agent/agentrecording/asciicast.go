@@ -0,0 +1,297 @@
+// Package agentrecording records interactive terminal sessions in the
+// asciicast v2 format, see https://docs.asciinema.org/manual/asciicast/v2/.
+package agentrecording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/quartz"
+)
+
+// Version is the asciicast version written by the Recorder.
+const Version = 2
+
+// EventType is the type of an event in a recording.
+type EventType string
+
+const (
+	// EventTypeOutput is data written to the terminal.
+	EventTypeOutput EventType = "o"
+	// EventTypeInput marks when data was read from the terminal, e.g. key
+	// presses. The data is always empty, input is not recorded since it may
+	// contain passwords typed at a prompt that does not echo them.
+	EventTypeInput EventType = "i"
+	// EventTypeResize is a change of the terminal size, the data is
+	// formatted as "{width}x{height}".
+	EventTypeResize EventType = "r"
+	// EventTypeMarker marks a point in the recording, the data is a label.
+	EventTypeMarker EventType = "m"
+)
+
+// Header is the first line of a recording.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event is a line of a recording following the header.
+type Event struct {
+	// Time is the time elapsed since the start of the recording.
+	Time time.Duration
+	Type EventType
+	Data string
+}
+
+// MarshalJSON encodes the event as a [time, type, data] array.
+func (e Event) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode([]any{float64(e.Time.Microseconds()) / 1e6, e.Type, e.Data})
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalJSON decodes an event from a [time, type, data] array.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return xerrors.Errorf("expected 3 elements in event, got %d", len(raw))
+	}
+	var seconds float64
+	if err := json.Unmarshal(raw[0], &seconds); err != nil {
+		return xerrors.Errorf("decode event time: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return xerrors.Errorf("decode event type: %w", err)
+	}
+	if err := json.Unmarshal(raw[2], &e.Data); err != nil {
+		return xerrors.Errorf("decode event data: %w", err)
+	}
+	e.Time = time.Duration(math.Round(seconds * float64(time.Second)))
+	return nil
+}
+
+// Options configures a Recorder.
+type Options struct {
+	// Clock is used to time events, defaults to the real clock.
+	Clock quartz.Clock
+	// MaxSize is the number of bytes after which the recording is
+	// truncated. Zero means no limit.
+	MaxSize int64
+}
+
+// TruncatedMarker is the label of the marker added when a recording
+// exceeds its maximum size.
+const TruncatedMarker = "recording truncated: size limit reached"
+
+// Recorder writes the output, input and size changes of a terminal
+// session as a recording. It is safe for concurrent use.
+type Recorder struct {
+	clock   quartz.Clock
+	maxSize int64
+
+	mu        sync.Mutex
+	closer    io.Closer
+	buf       *bufio.Writer
+	enc       *json.Encoder
+	start     time.Time
+	size      int64
+	truncated bool
+	closed    bool
+	err       error
+	// pending holds incomplete UTF-8 sequences per event type, so that
+	// multi-byte characters split over two writes are not mangled.
+	pending map[EventType][]byte
+}
+
+// New starts a recording written to w. The header version and timestamp
+// are set by New. w is closed when the recorder is closed.
+func New(w io.WriteCloser, header Header, opts Options) (*Recorder, error) {
+	if opts.Clock == nil {
+		opts.Clock = quartz.NewReal()
+	}
+	r := &Recorder{
+		clock:   opts.Clock,
+		maxSize: opts.MaxSize,
+		closer:  w,
+		start:   opts.Clock.Now(),
+		pending: make(map[EventType][]byte),
+	}
+	r.buf = bufio.NewWriter(&countingWriter{w: w, n: &r.size})
+	r.enc = json.NewEncoder(r.buf)
+	r.enc.SetEscapeHTML(false)
+
+	header.Version = Version
+	header.Timestamp = r.start.Unix()
+	if err := r.enc.Encode(header); err != nil {
+		_ = w.Close()
+		return nil, xerrors.Errorf("write header: %w", err)
+	}
+	return r, nil
+}
+
+// Output returns a writer that records the data written to it as output.
+func (r *Recorder) Output() io.Writer {
+	return eventWriter{r: r, typ: EventTypeOutput}
+}
+
+// Input returns a writer that records when data is written to it as input.
+// The data itself is discarded.
+func (r *Recorder) Input() io.Writer {
+	return inputWriter{r: r}
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.writeEvent(EventTypeResize, []byte(fmt.Sprintf("%dx%d", width, height)))
+}
+
+// Close flushes the recording and closes the underlying writer.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return r.err
+	}
+	if data := r.pending[EventTypeOutput]; len(data) > 0 {
+		r.pending[EventTypeOutput] = nil
+		r.writeEvent(EventTypeOutput, data)
+	}
+	r.closed = true
+	if err := r.buf.Flush(); err != nil && r.err == nil {
+		r.err = xerrors.Errorf("flush recording: %w", err)
+	}
+	if err := r.closer.Close(); err != nil && r.err == nil {
+		r.err = xerrors.Errorf("close recording: %w", err)
+	}
+	return r.err
+}
+
+func (r *Recorder) write(typ EventType, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data := append(r.pending[typ], p...)
+	data, r.pending[typ] = splitIncompleteRune(data)
+	if len(data) > 0 {
+		r.writeEvent(typ, data)
+	}
+}
+
+// writeEvent writes an event, r.mu must be held.
+func (r *Recorder) writeEvent(typ EventType, data []byte) {
+	if r.closed || r.truncated || r.err != nil {
+		return
+	}
+	elapsed := r.clock.Since(r.start)
+	if r.maxSize > 0 && r.size+int64(r.buf.Buffered())+int64(len(data)) > r.maxSize {
+		r.truncated = true
+		typ, data = EventTypeMarker, []byte(TruncatedMarker)
+	}
+	err := r.enc.Encode(Event{Time: elapsed, Type: typ, Data: string(data)})
+	if err != nil {
+		r.err = xerrors.Errorf("write event: %w", err)
+	}
+}
+
+type eventWriter struct {
+	r   *Recorder
+	typ EventType
+}
+
+// Write never fails, so that recording can't interrupt the session.
+func (w eventWriter) Write(p []byte) (int, error) {
+	w.r.write(w.typ, p)
+	return len(p), nil
+}
+
+type inputWriter struct {
+	r *Recorder
+}
+
+// Write never fails, so that recording can't interrupt the session.
+func (w inputWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		w.r.mu.Lock()
+		w.r.writeEvent(EventTypeInput, nil)
+		w.r.mu.Unlock()
+	}
+	return len(p), nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
+
+// splitIncompleteRune splits an incomplete UTF-8 sequence off the end of p.
+func splitIncompleteRune(p []byte) (complete, rest []byte) {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(p[i]) {
+			continue
+		}
+		if utf8.FullRune(p[i:]) {
+			return p, nil
+		}
+		return p[:i], append([]byte(nil), p[i:]...)
+	}
+	return p, nil
+}
+
+// Decoder reads the events of a recording.
+type Decoder struct {
+	dec *json.Decoder
+}
+
+// NewDecoder reads the header of the recording from r.
+func NewDecoder(r io.Reader) (*Decoder, Header, error) {
+	dec := json.NewDecoder(r)
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return nil, Header{}, xerrors.Errorf("decode header: %w", err)
+	}
+	if header.Version != Version {
+		return nil, Header{}, xerrors.Errorf("unsupported asciicast version %d", header.Version)
+	}
+	return &Decoder{dec: dec}, header, nil
+}
+
+// Next returns the next event of the recording, or io.EOF at the end.
+func (d *Decoder) Next() (Event, error) {
+	var event Event
+	err := d.dec.Decode(&event)
+	if err != nil {
+		if xerrors.Is(err, io.EOF) {
+			return Event{}, io.EOF
+		}
+		return Event{}, xerrors.Errorf("decode event: %w", err)
+	}
+	return event, nil
+}
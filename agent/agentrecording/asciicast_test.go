@@ -0,0 +1,110 @@
+package agentrecording_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentrecording"
+	"github.com/coder/quartz"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *nopCloser) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		clock := quartz.NewMock(t)
+		start := clock.Now()
+		var buf nopCloser
+		rec, err := agentrecording.New(&buf, agentrecording.Header{
+			Width:  80,
+			Height: 24,
+			Env:    map[string]string{"TERM": "xterm"},
+		}, agentrecording.Options{Clock: clock})
+		require.NoError(t, err)
+
+		_, _ = rec.Output().Write([]byte("$ "))
+		clock.Advance(1500 * time.Millisecond)
+		_, _ = rec.Input().Write([]byte("ls\r"))
+		clock.Advance(250 * time.Millisecond)
+		rec.Resize(120, 40)
+		// A multi-byte character split over two writes must be kept intact.
+		_, _ = rec.Output().Write([]byte("caf\xc3"))
+		_, _ = rec.Output().Write([]byte("\xa9 <b>\r\n"))
+		require.NoError(t, rec.Close())
+		require.True(t, buf.closed)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 6)
+		require.Equal(t, fmt.Sprintf(`{"version":2,"width":80,"height":24,"timestamp":%d,"env":{"TERM":"xterm"}}`, start.Unix()), lines[0])
+		require.Equal(t, `[0,"o","$ "]`, lines[1])
+		require.Equal(t, `[1.5,"i",""]`, lines[2])
+		require.Equal(t, `[1.75,"r","120x40"]`, lines[3])
+		require.Equal(t, `[1.75,"o","caf"]`, lines[4])
+		require.Equal(t, `[1.75,"o","é <b>\r\n"]`, lines[5])
+
+		dec, header, err := agentrecording.NewDecoder(strings.NewReader(strings.Join(lines, "\n")))
+		require.NoError(t, err)
+		require.Equal(t, 80, header.Width)
+		require.Equal(t, 24, header.Height)
+		var events []agentrecording.Event
+		for {
+			event, err := dec.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			events = append(events, event)
+		}
+		require.Equal(t, []agentrecording.Event{
+			{Time: 0, Type: agentrecording.EventTypeOutput, Data: "$ "},
+			{Time: 1500 * time.Millisecond, Type: agentrecording.EventTypeInput},
+			{Time: 1750 * time.Millisecond, Type: agentrecording.EventTypeResize, Data: "120x40"},
+			{Time: 1750 * time.Millisecond, Type: agentrecording.EventTypeOutput, Data: "caf"},
+			{Time: 1750 * time.Millisecond, Type: agentrecording.EventTypeOutput, Data: "é <b>\r\n"},
+		}, events)
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		t.Parallel()
+
+		var buf nopCloser
+		rec, err := agentrecording.New(&buf, agentrecording.Header{Width: 80, Height: 24}, agentrecording.Options{
+			Clock:   quartz.NewMock(t),
+			MaxSize: 128,
+		})
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			_, _ = rec.Output().Write([]byte("0123456789"))
+		}
+		require.NoError(t, rec.Close())
+
+		out := strings.TrimSpace(buf.String())
+		require.True(t, strings.HasSuffix(out, `[0,"m","`+agentrecording.TruncatedMarker+`"]`), out)
+		require.Less(t, len(out), 128+len(agentrecording.TruncatedMarker)+16)
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := agentrecording.NewDecoder(strings.NewReader(`{"version":1,"width":80,"height":24}`))
+		require.ErrorContains(t, err, "unsupported asciicast version 1")
+	})
+}
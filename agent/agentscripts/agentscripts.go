@@ -22,11 +22,13 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 )
 
 var (
@@ -179,7 +181,7 @@ func (r *Runner) Init(scripts []codersdk.WorkspaceAgentScript, scriptCompleted S
 		}
 		script := script
 		_, err := r.cron.AddFunc(script.Cron, func() {
-			err := r.trackRun(r.cronCtx, script.WorkspaceAgentScript, ExecuteCronScripts)
+			err := r.trackRun(r.cronCtx, script.WorkspaceAgentScript, ExecuteCronScripts, time.Time{})
 			if err != nil {
 				r.Logger.Warn(context.Background(), "run agent script on schedule", slog.Error(err))
 			}
@@ -228,7 +230,9 @@ const (
 	ExecuteCronScripts
 )
 
-// Execute runs a set of scripts according to a filter.
+// Execute runs a set of scripts according to a filter. Scripts are started
+// once the scripts they depend on are ready, dependencies on scripts that
+// don't match the filter are ignored.
 func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 	initErr := func() error {
 		r.initMutex.Lock()
@@ -242,7 +246,7 @@ func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 		return initErr
 	}
 
-	var eg errgroup.Group
+	var scripts []codersdk.WorkspaceAgentScript
 	for _, script := range r.scripts {
 		runScript := (option == ExecuteStartScripts && script.RunOnStart) ||
 			(option == ExecuteStopScripts && script.RunOnStop) ||
@@ -250,15 +254,28 @@ func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 			(option == ExecuteCronScripts && script.Cron != "") ||
 			option == ExecuteAllScripts
 
-		if !runScript {
-			continue
+		if runScript {
+			scripts = append(scripts, script.WorkspaceAgentScript)
 		}
+	}
 
-		script := script
+	nodes, err := r.scriptGraph(scripts)
+	if err != nil {
+		// None of the scripts are run, as the order they are meant to run in
+		// is unknown.
+		for _, script := range scripts {
+			r.sendScriptError(ctx, script, err)
+		}
+		return err
+	}
+
+	queued := dbtime.Now()
+	var eg errgroup.Group
+	for _, node := range nodes {
 		eg.Go(func() error {
-			err := r.trackRun(ctx, script.WorkspaceAgentScript, option)
+			err := r.runNode(ctx, node, option, queued)
 			if err != nil {
-				return xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err)
+				return xerrors.Errorf("run agent script %q: %w", node.script.LogSourceID, err)
 			}
 			return nil
 		})
@@ -267,8 +284,8 @@ func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 }
 
 // trackRun wraps "run" with metrics.
-func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScript, option ExecuteOption) error {
-	err := r.run(ctx, script, option)
+func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScript, option ExecuteOption, queued time.Time) error {
+	err := r.run(ctx, script, option, queued)
 	if err != nil {
 		r.scriptsExecuted.WithLabelValues("false").Add(1)
 	} else {
@@ -281,7 +298,9 @@ func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScr
 // If the timeout is exceeded, the process is sent an interrupt signal.
 // If the process does not exit after a few seconds, it is forcefully killed.
 // This function immediately returns after a timeout, and does not wait for the process to exit.
-func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript, option ExecuteOption) error {
+// The queued time is reported in the script timing, it is the start time if
+// zero.
+func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript, option ExecuteOption, queued time.Time) error {
 	logPath := script.LogPath
 	if logPath == "" {
 		logPath = fmt.Sprintf("coder-script-%s.log", script.LogSourceID)
@@ -349,6 +368,11 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 	// storage.
 	cmd.Env = append(cmd.Env, "CODER_SCRIPT_DATA_DIR="+scriptDataDir)
 	cmd.Env = append(cmd.Env, "CODER_SCRIPT_BIN_DIR="+r.ScriptBinDir())
+	cmd.Env = append(cmd.Env, agentproc.OwnerEnv(workspacesdk.ProcessOwner{
+		Type: workspacesdk.ProcessOwnerTypeScript,
+		ID:   script.LogSourceID.String(),
+		Name: script.DisplayName,
+	}))
 
 	scriptLogger := r.GetScriptLogger(script.LogSourceID)
 	// If ctx is canceled here (or in a writer below), we may be
@@ -369,6 +393,9 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 	cmd.Stderr = io.MultiWriter(fileWriter, errW)
 
 	start := dbtime.Now()
+	if queued.IsZero() {
+		queued = start
+	}
 	defer func() {
 		end := dbtime.Now()
 		execTime := end.Sub(start)
@@ -423,6 +450,7 @@ func (r *Runner) run(ctx context.Context, script codersdk.WorkspaceAgentScript,
 			_, err := r.scriptCompleted(reportCtx, &proto.WorkspaceAgentScriptCompletedRequest{
 				Timing: &proto.Timing{
 					ScriptId: script.ID[:],
+					Queued:   timestamppb.New(queued),
 					Start:    timestamppb.New(start),
 					End:      timestamppb.New(end),
 					ExitCode: int32(exitCode),
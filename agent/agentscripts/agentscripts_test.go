@@ -1,10 +1,14 @@
 package agentscripts_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"net"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -147,6 +151,201 @@ func TestScriptReportsTiming(t *testing.T) {
 	require.GreaterOrEqual(t, timing.End.AsTime(), timing.Start.AsTime())
 }
 
+func TestDependsOn(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the scripts use a POSIX shell")
+	}
+
+	t.Run("WaitsForDependency", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := setup(t, nil)
+		dir := t.TempDir()
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		installID := uuid.New()
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			ID:          uuid.New(),
+			DisplayName: "Start server",
+			LogSourceID: uuid.New(),
+			Script:      fmt.Sprintf("test -f %s/installed", dir),
+			DependsOn:   []string{"Install tools"},
+		}, {
+			ID:          installID,
+			DisplayName: "Install tools",
+			LogSourceID: uuid.New(),
+			Script:      fmt.Sprintf("sleep 0.2 && touch %s/installed", dir),
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		require.NoError(t, runner.Execute(ctx, agentscripts.ExecuteAllScripts))
+		require.NoError(t, runner.Close())
+
+		timings := aAPI.GetTimings()
+		require.Len(t, timings, 2)
+		install, start := timings[0], timings[1]
+		if !bytes.Equal(install.ScriptId, installID[:]) {
+			install, start = start, install
+		}
+		require.Equal(t, int32(0), start.ExitCode)
+		require.False(t, start.Start.AsTime().Before(install.End.AsTime()), "script started before its dependency completed")
+		// The wait for the dependency is reported in the timing.
+		require.True(t, start.Queued.AsTime().Before(start.Start.AsTime()))
+		require.Equal(t, install.Queued.AsTime(), start.Queued.AsTime())
+	})
+
+	t.Run("ReadinessProbe", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := setup(t, nil)
+		dir := t.TempDir()
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			DisplayName: "Server",
+			LogSourceID: uuid.New(),
+			// The server keeps running once it is ready.
+			Script: fmt.Sprintf("sleep 0.2 && touch %[1]s/ready && sleep 2 && touch %[1]s/exited", dir),
+			ReadinessProbe: &codersdk.WorkspaceAgentScriptReadinessProbe{
+				Command:  fmt.Sprintf("test -f %s/ready", dir),
+				Interval: 50 * time.Millisecond,
+			},
+		}, {
+			DisplayName: "Client",
+			LogSourceID: uuid.New(),
+			Script:      fmt.Sprintf("test -f %[1]s/ready && test ! -f %[1]s/exited", dir),
+			DependsOn:   []string{"Server"},
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		require.NoError(t, runner.Execute(ctx, agentscripts.ExecuteAllScripts))
+		// Execute returns once the server is ready, not once it exits.
+		require.NoFileExists(t, filepath.Join(dir, "exited"))
+		require.NoError(t, runner.Close())
+		require.FileExists(t, filepath.Join(dir, "exited"))
+
+		timings := aAPI.GetTimings()
+		require.Len(t, timings, 2)
+		for _, timing := range timings {
+			require.Equal(t, int32(0), timing.ExitCode)
+		}
+	})
+
+	t.Run("ReadinessProbeTimeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		fLogger := newFakeScriptLogger()
+		runner := setup(t, func(uuid.UUID) agentscripts.ScriptLogger {
+			return fLogger
+		})
+
+		// Nothing listens on the address once the listener is closed.
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		require.NoError(t, l.Close())
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		err = runner.Init([]codersdk.WorkspaceAgentScript{{
+			DisplayName: "Server",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			ReadinessProbe: &codersdk.WorkspaceAgentScriptReadinessProbe{
+				TCP:      l.Addr().String(),
+				Interval: 50 * time.Millisecond,
+				Timeout:  200 * time.Millisecond,
+			},
+		}, {
+			DisplayName: "Client",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			DependsOn:   []string{"Server"},
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		err = runner.Execute(ctx, agentscripts.ExecuteAllScripts)
+		require.ErrorContains(t, err, "readiness probe failed")
+		require.NoError(t, runner.Close())
+
+		// Only the server ran.
+		require.Len(t, aAPI.GetTimings(), 1)
+		var outputs []string
+		for len(fLogger.logs) > 0 {
+			outputs = append(outputs, (<-fLogger.logs).Output)
+		}
+		require.Contains(t, strings.Join(outputs, "\n"), `dependency "Server" is not ready`)
+	})
+
+	t.Run("DependencyFailed", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		fLogger := newFakeScriptLogger()
+		runner := setup(t, func(uuid.UUID) agentscripts.ScriptLogger {
+			return fLogger
+		})
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			DisplayName: "Install tools",
+			LogSourceID: uuid.New(),
+			Script:      "exit 1",
+		}, {
+			DisplayName: "Start server",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			DependsOn:   []string{"Install tools"},
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		require.Error(t, runner.Execute(ctx, agentscripts.ExecuteAllScripts))
+		require.NoError(t, runner.Close())
+
+		// Only the failed dependency ran.
+		require.Len(t, aAPI.GetTimings(), 1)
+		log := testutil.TryReceive(ctx, t, fLogger.logs)
+		require.Contains(t, log.Output, `dependency "Install tools" is not ready`)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := setup(t, nil)
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			DisplayName: "a",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			DependsOn:   []string{"b"},
+		}, {
+			DisplayName: "b",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			DependsOn:   []string{"a"},
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		err = runner.Execute(ctx, agentscripts.ExecuteAllScripts)
+		require.ErrorIs(t, err, agentscripts.ErrDependencyCycle)
+		require.ErrorContains(t, err, `"a" -> "b" -> "a"`)
+		require.NoError(t, runner.Close())
+		require.Empty(t, aAPI.GetTimings())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		runner := setup(t, nil)
+
+		aAPI := agenttest.NewFakeAgentAPI(t, testutil.Logger(t), nil, nil)
+		err := runner.Init([]codersdk.WorkspaceAgentScript{{
+			DisplayName: "a",
+			LogSourceID: uuid.New(),
+			Script:      "true",
+			DependsOn:   []string{"missing"},
+		}}, aAPI.ScriptCompleted)
+		require.NoError(t, err)
+		err = runner.Execute(ctx, agentscripts.ExecuteAllScripts)
+		require.ErrorContains(t, err, `depends on unknown script "missing"`)
+		require.NoError(t, runner.Close())
+	})
+}
+
 // TestCronClose exists because cron.Run() can happen after cron.Close().
 // If this happens, there used to be a deadlock.
 func TestCronClose(t *testing.T) {
@@ -0,0 +1,273 @@
+package agentscripts
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// ErrDependencyCycle is returned when the dependencies of scripts form a
+// cycle.
+var ErrDependencyCycle = xerrors.New("script dependency cycle")
+
+const (
+	defaultReadinessProbeInterval = time.Second
+	defaultReadinessProbeTimeout  = 5 * time.Minute
+	// readinessProbeAttemptTimeout bounds a single run of a readiness probe,
+	// e.g. an HTTP request to a server that accepts but never responds.
+	readinessProbeAttemptTimeout = 10 * time.Second
+)
+
+// scriptNode is a script in the dependency graph of an execution.
+type scriptNode struct {
+	script       codersdk.WorkspaceAgentScript
+	dependencies []*scriptNode
+
+	readyOnce sync.Once
+	ready     chan struct{}
+	// readyErr is set before ready is closed. It is nil if the script is
+	// ready, and the reason it will never be ready otherwise.
+	readyErr error
+}
+
+func (n *scriptNode) setReady(err error) {
+	n.readyOnce.Do(func() {
+		n.readyErr = err
+		close(n.ready)
+	})
+}
+
+// scriptGraph returns the dependency graph of scripts. Dependencies on
+// scripts of the runner that are not part of scripts are ignored, as they
+// don't run in the same stage, e.g. a stop script that depends on a start
+// script.
+func (r *Runner) scriptGraph(scripts []codersdk.WorkspaceAgentScript) ([]*scriptNode, error) {
+	known := make(map[string]bool, len(r.scripts))
+	for _, script := range r.scripts {
+		known[script.DisplayName] = true
+	}
+	nodes := make([]*scriptNode, len(scripts))
+	byName := make(map[string][]*scriptNode, len(scripts))
+	for i, script := range scripts {
+		nodes[i] = &scriptNode{
+			script: script,
+			ready:  make(chan struct{}),
+		}
+		byName[script.DisplayName] = append(byName[script.DisplayName], nodes[i])
+	}
+	for _, node := range nodes {
+		for _, name := range node.script.DependsOn {
+			if !known[name] {
+				return nil, xerrors.Errorf("script %q depends on unknown script %q", node.script.DisplayName, name)
+			}
+			node.dependencies = append(node.dependencies, byName[name]...)
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[*scriptNode]int, len(nodes))
+	var visit func(node *scriptNode, path []*scriptNode) error
+	visit = func(node *scriptNode, path []*scriptNode) error {
+		switch state[node] {
+		case visiting:
+			var names []string
+			for _, n := range append(path[slices.Index(path, node):], node) {
+				names = append(names, fmt.Sprintf("%q", n.script.DisplayName))
+			}
+			return xerrors.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		case visited:
+			return nil
+		}
+		state[node] = visiting
+		for _, dep := range node.dependencies {
+			err := visit(dep, append(path, node))
+			if err != nil {
+				return err
+			}
+		}
+		state[node] = visited
+		return nil
+	}
+	for _, node := range nodes {
+		err := visit(node, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// runNode waits for the dependencies of the script to be ready and runs it.
+// The script is ready once it exits successfully or, if it has a readiness
+// probe, once the probe succeeds. Scripts with a probe may keep running once
+// they are ready, e.g. to serve an application, and runNode returns without
+// waiting for them to exit.
+func (r *Runner) runNode(ctx context.Context, node *scriptNode, option ExecuteOption, queued time.Time) error {
+	for _, dep := range node.dependencies {
+		select {
+		case <-ctx.Done():
+			node.setReady(ctx.Err())
+			return ctx.Err()
+		case <-dep.ready:
+		}
+		if dep.readyErr != nil {
+			err := xerrors.Errorf("dependency %q is not ready: %w", dep.script.DisplayName, dep.readyErr)
+			node.setReady(err)
+			r.sendScriptError(ctx, node.script, err)
+			return err
+		}
+	}
+
+	if node.script.ReadinessProbe == nil {
+		err := r.trackRun(ctx, node.script, option, queued)
+		node.setReady(err)
+		return err
+	}
+
+	// The script runs in the background, so that its dependents and the
+	// start of the workspace are released as soon as the probe succeeds
+	// rather than once the script exits.
+	runDone := make(chan error, 1)
+	err := r.trackCommandGoroutine(func() {
+		runDone <- r.trackRun(ctx, node.script, option, queued)
+	})
+	if err != nil {
+		node.setReady(err)
+		return err
+	}
+
+	probeCtx, cancelProbe := context.WithCancel(ctx)
+	defer cancelProbe()
+	probeDone := make(chan error, 1)
+	go func() {
+		probeDone <- r.waitReady(probeCtx, node.script)
+	}()
+
+	select {
+	case err = <-probeDone:
+	case err = <-runDone:
+		if err != nil {
+			// A script that failed before it was ready will never be ready.
+			node.setReady(err)
+			cancelProbe()
+			<-probeDone
+			return err
+		}
+		// The script may have started a daemon that is not ready yet.
+		err = <-probeDone
+	}
+	node.setReady(err)
+	if err != nil {
+		r.sendScriptError(ctx, node.script, err)
+		return err
+	}
+	return nil
+}
+
+// waitReady runs the readiness probe of the script until it succeeds or
+// times out.
+func (r *Runner) waitReady(ctx context.Context, script codersdk.WorkspaceAgentScript) error {
+	probe := *script.ReadinessProbe
+	interval := cmp.Or(probe.Interval, defaultReadinessProbeInterval)
+	timeout := cmp.Or(probe.Timeout, defaultReadinessProbeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := r.probe(ctx, probe)
+		if err == nil {
+			r.Logger.Info(ctx, "agent script is ready", slog.F("log_source_id", script.LogSourceID))
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return xerrors.Errorf("readiness probe failed for %s: %w", timeout, err)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe runs a readiness probe once.
+func (r *Runner) probe(ctx context.Context, probe codersdk.WorkspaceAgentScriptReadinessProbe) error {
+	ctx, cancel := context.WithTimeout(ctx, readinessProbeAttemptTimeout)
+	defer cancel()
+
+	switch {
+	case probe.Command != "":
+		cmdPty, err := r.SSHServer.CreateCommand(ctx, probe.Command, nil, nil)
+		if err != nil {
+			return xerrors.Errorf("create command: %w", err)
+		}
+		cmd := cmdPty.AsExec()
+		cmd.SysProcAttr = cmdSysProcAttr()
+		cmd.WaitDelay = time.Second
+		cmd.Cancel = cmdCancel(ctx, r.Logger, cmd)
+		return cmd.Run()
+	case probe.TCP != "":
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", probe.TCP)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case probe.HTTP != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.HTTP, nil)
+		if err != nil {
+			return xerrors.Errorf("create request: %w", err)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+		if res.StatusCode >= http.StatusBadRequest {
+			return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// sendScriptError reports why a script was not run, or did not become ready,
+// in the logs of the script.
+func (r *Runner) sendScriptError(ctx context.Context, script codersdk.WorkspaceAgentScript, err error) {
+	logger := r.Logger.With(slog.F("log_source_id", script.LogSourceID))
+	logger.Warn(ctx, "agent script dependencies", slog.Error(err))
+
+	scriptLogger := r.GetScriptLogger(script.LogSourceID)
+	sendErr := scriptLogger.Send(ctx, agentsdk.Log{
+		CreatedAt: dbtime.Now(),
+		Output:    fmt.Sprintf("ERROR: %s", err),
+		Level:     codersdk.LogLevelError,
+	})
+	if sendErr == nil {
+		sendErr = scriptLogger.Flush(ctx)
+	}
+	if sendErr != nil {
+		logger.Warn(ctx, "send script error log failed", slog.Error(sendErr))
+	}
+}
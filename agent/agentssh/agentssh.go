@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,9 +32,12 @@ import (
 
 	"github.com/coder/coder/v2/agent/agentcontainers"
 	"github.com/coder/coder/v2/agent/agentexec"
+	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/agent/agentrecording"
 	"github.com/coder/coder/v2/agent/agentrsa"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/pty"
 )
 
@@ -113,6 +117,17 @@ type Config struct {
 	BlockFileTransfer bool
 	// ReportConnection.
 	ReportConnection reportConnectionFunc
+	// RecordSession returns a recorder for the PTY session of the
+	// connection with the given ID, or nil if it should not be recorded.
+	// The recorder is closed when the session ends.
+	RecordSession func(id uuid.UUID, sessionType MagicSessionType, header agentrecording.Header) *agentrecording.Recorder
+	// ReportActivity is called when input is received on the PTY of a
+	// session, i.e. the user is typing.
+	ReportActivity func()
+	// AllowForward is called before a client connects to a TCP address
+	// ("tcp") or Unix socket ("unix") through a direct-tcpip or
+	// direct-streamlocal channel. The channel is rejected if it returns false.
+	AllowForward func(ctx ssh.Context, network, address string) bool
 	// Experimental: allow connecting to running containers if
 	// CODER_AGENT_DEVCONTAINERS_ENABLE=true.
 	ExperimentalDevContainersEnabled bool
@@ -171,6 +186,15 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 	if config.ReportConnection == nil {
 		config.ReportConnection = func(uuid.UUID, MagicSessionType, string) func(int, string) { return func(int, string) {} }
 	}
+	if config.RecordSession == nil {
+		config.RecordSession = func(uuid.UUID, MagicSessionType, agentrecording.Header) *agentrecording.Recorder { return nil }
+	}
+	if config.ReportActivity == nil {
+		config.ReportActivity = func() {}
+	}
+	if config.AllowForward == nil {
+		config.AllowForward = func(ssh.Context, string, string) bool { return true }
+	}
 
 	forwardHandler := &ssh.ForwardedTCPHandler{}
 	unixForwardHandler := newForwardedUnixHandler(logger)
@@ -196,7 +220,7 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 				wrapped := NewJetbrainsChannelWatcher(ctx, s.logger, s.config.ReportConnection, newChan, &s.connCountJetBrains)
 				ssh.DirectTCPIPHandler(srv, conn, wrapped, ctx)
 			},
-			"direct-streamlocal@openssh.com": directStreamLocalHandler,
+			"direct-streamlocal@openssh.com": s.directStreamLocalHandler,
 			"session":                        ssh.DefaultSessionHandler,
 		},
 		ConnectionFailedCallback: func(conn net.Conn, err error) {
@@ -217,11 +241,10 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 		// be set before we start listening.
 		HostSigners: []ssh.Signer{},
 		LocalPortForwardingCallback: func(ctx ssh.Context, destinationHost string, destinationPort uint32) bool {
-			// Allow local port forwarding all!
 			s.logger.Debug(ctx, "local port forward",
 				slog.F("destination_host", destinationHost),
 				slog.F("destination_port", destinationPort))
-			return true
+			return s.config.AllowForward(ctx, "tcp", net.JoinHostPort(destinationHost, strconv.FormatUint(uint64(destinationPort), 10)))
 		},
 		PtyCallback: func(_ ssh.Context, _ ssh.Pty) bool {
 			return true
@@ -336,6 +359,20 @@ func (s *sessionCloseTracker) Close() error {
 	return s.Session.Close()
 }
 
+// activityReader reports activity for every read of input from the user.
+type activityReader struct {
+	r      io.Reader
+	report func()
+}
+
+func (a *activityReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	if n > 0 {
+		a.report()
+	}
+	return n, err
+}
+
 func extractContainerInfo(env []string) (container, containerUser string, filteredEnv []string) {
 	for _, kv := range env {
 		if strings.HasPrefix(kv, ContainerEnvironmentVariable+"=") {
@@ -464,7 +501,7 @@ func (s *Server) sessionHandler(session ssh.Session) {
 		env = append(env, fmt.Sprintf("DISPLAY=localhost:%d.%d", display, x11.ScreenNumber))
 	}
 
-	err := s.sessionStart(logger, session, env, magicType, container, containerUser)
+	err := s.sessionStart(logger, session, id, env, magicType, container, containerUser)
 	var exitError *exec.ExitError
 	if xerrors.As(err, &exitError) {
 		code := exitError.ExitCode()
@@ -537,7 +574,7 @@ func (s *Server) fileTransferBlocked(session ssh.Session) bool {
 	return false
 }
 
-func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, env []string, magicType MagicSessionType, container, containerUser string) (retErr error) {
+func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, id uuid.UUID, env []string, magicType MagicSessionType, container, containerUser string) (retErr error) {
 	ctx := session.Context()
 
 	magicTypeLabel := magicTypeMetricLabel(magicType)
@@ -561,6 +598,11 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, env []str
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, ptyLabel, "create_command").Add(1)
 		return err
 	}
+	cmd.Env = append(cmd.Env, agentproc.OwnerEnv(workspacesdk.ProcessOwner{
+		Type: workspacesdk.ProcessOwnerTypeSSH,
+		ID:   id.String(),
+		Name: string(magicType),
+	}))
 
 	if ssh.AgentRequested(session) {
 		l, err := ssh.NewAgentListener()
@@ -574,7 +616,12 @@ func (s *Server) sessionStart(logger slog.Logger, session ssh.Session, env []str
 	}
 
 	if isPty {
-		return s.startPTYSession(logger, session, magicTypeLabel, cmd, sshPty, windowSize)
+		rec := s.config.RecordSession(id, magicType, agentrecording.Header{
+			Width:  sshPty.Window.Width,
+			Height: sshPty.Window.Height,
+			Env:    map[string]string{"TERM": sshPty.Term},
+		})
+		return s.startPTYSession(logger, session, magicTypeLabel, cmd, sshPty, windowSize, rec)
 	}
 	return s.startNonPTYSession(logger, session, magicTypeLabel, cmd.AsExec())
 }
@@ -633,10 +680,26 @@ type ptySession interface {
 	Signals(chan<- ssh.Signal)
 }
 
-func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTypeLabel string, cmd *pty.Cmd, sshPty ssh.Pty, windowSize <-chan ssh.Window) (retErr error) {
+func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTypeLabel string, cmd *pty.Cmd, sshPty ssh.Pty, windowSize <-chan ssh.Window, rec *agentrecording.Recorder) (retErr error) {
 	s.metrics.sessionsTotal.WithLabelValues(magicTypeLabel, "yes").Add(1)
 
 	ctx := session.Context()
+	var (
+		input  io.Reader = session
+		output io.Writer = session
+	)
+	if rec != nil {
+		defer func() {
+			err := rec.Close()
+			if err != nil {
+				logger.Warn(ctx, "failed to close session recording", slog.Error(err))
+				s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "recording").Add(1)
+			}
+		}()
+		// The recorder only keeps the timing of input, not its contents.
+		input = io.TeeReader(session, rec.Input())
+		output = io.MultiWriter(session, rec.Output())
+	}
 	// Disable minimal PTY emulation set by gliderlabs/ssh (NL-to-CRNL).
 	// See https://github.com/coder/coder/issues/3371.
 	session.DisablePTYEmulation()
@@ -708,6 +771,9 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 					windowSize = nil
 					continue
 				}
+				if rec != nil {
+					rec.Resize(win.Width, win.Height)
+				}
 				// #nosec G115 - Safe conversions for terminal dimensions which are expected to be within uint16 range
 				resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 				// If the pty is closed, then command has exited, no need to log.
@@ -720,7 +786,7 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	}()
 
 	go func() {
-		_, err := io.Copy(ptty.InputWriter(), session)
+		_, err := io.Copy(ptty.InputWriter(), &activityReader{r: input, report: s.config.ReportActivity})
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "input_io_copy").Add(1)
 		}
@@ -734,7 +800,7 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	n, err := io.Copy(output, ptty.OutputReader())
 	logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
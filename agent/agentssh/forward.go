@@ -214,13 +214,17 @@ type directStreamLocalPayload struct {
 	Reserved2 uint32
 }
 
-func directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+func (s *Server) directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
 	var reqPayload directStreamLocalPayload
 	err := gossh.Unmarshal(newChan.ExtraData(), &reqPayload)
 	if err != nil {
 		_ = newChan.Reject(gossh.ConnectionFailed, "could not parse direct-streamlocal@openssh.com channel payload")
 		return
 	}
+	if !s.config.AllowForward(ctx, "unix", reqPayload.SocketPath) {
+		_ = newChan.Reject(gossh.Prohibited, fmt.Sprintf("forwarding to unix socket %q is not allowed", reqPayload.SocketPath))
+		return
+	}
 
 	var dialer net.Dialer
 	dconn, err := dialer.DialContext(ctx, "unix", reqPayload.SocketPath)
@@ -1,6 +1,7 @@
 package agenttest
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"slices"
@@ -98,8 +99,8 @@ func (c *Client) Close() {
 	c.derpMapOnce.Do(func() { close(c.derpMapUpdates) })
 }
 
-func (c *Client) ConnectRPC24(ctx context.Context) (
-	agentproto.DRPCAgentClient24, proto.DRPCTailnetClient24, error,
+func (c *Client) ConnectRPC25(ctx context.Context) (
+	agentproto.DRPCAgentClient25, proto.DRPCTailnetClient25, error,
 ) {
 	conn, lis := drpcsdk.MemTransportPipe()
 	c.LastWorkspaceAgent = func() {
@@ -163,6 +164,14 @@ func (c *Client) GetConnectionReports() []*agentproto.ReportConnectionRequest {
 	return c.fakeAgentAPI.GetConnectionReports()
 }
 
+func (c *Client) GetSessionRecordings() []*agentproto.UploadSessionRecordingRequest {
+	return c.fakeAgentAPI.GetSessionRecordings()
+}
+
+func (c *Client) GetDevcontainerApps() []*agentproto.UpdateDevcontainerAppsRequest {
+	return c.fakeAgentAPI.GetDevcontainerApps()
+}
+
 type FakeAgentAPI struct {
 	sync.Mutex
 	t      testing.TB
@@ -177,6 +186,8 @@ type FakeAgentAPI struct {
 	metadata          map[string]agentsdk.Metadata
 	timings           []*agentproto.Timing
 	connectionReports []*agentproto.ReportConnectionRequest
+	sessionRecordings []*agentproto.UploadSessionRecordingRequest
+	devcontainerApps  []*agentproto.UpdateDevcontainerAppsRequest
 
 	getAnnouncementBannersFunc              func() ([]codersdk.BannerConfig, error)
 	getResourcesMonitoringConfigurationFunc func() (*agentproto.GetResourcesMonitoringConfigurationResponse, error)
@@ -365,6 +376,52 @@ func (f *FakeAgentAPI) GetConnectionReports() []*agentproto.ReportConnectionRequ
 	return slices.Clone(f.connectionReports)
 }
 
+func (f *FakeAgentAPI) UploadSessionRecording(_ context.Context, req *agentproto.UploadSessionRecordingRequest) (*emptypb.Empty, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	for i, rec := range f.sessionRecordings {
+		if !bytes.Equal(rec.GetId(), req.GetId()) {
+			continue
+		}
+		if req.GetOffset() == 0 {
+			f.sessionRecordings[i] = req
+			return &emptypb.Empty{}, nil
+		}
+		if req.GetOffset() != int64(len(rec.Data)) {
+			return nil, xerrors.Errorf("unexpected offset %d, recording has %d bytes", req.GetOffset(), len(rec.Data))
+		}
+		rec.Data = append(rec.Data, req.GetData()...)
+		return &emptypb.Empty{}, nil
+	}
+	if req.GetOffset() != 0 {
+		return nil, xerrors.Errorf("unknown recording with offset %d", req.GetOffset())
+	}
+	f.sessionRecordings = append(f.sessionRecordings, req)
+	return &emptypb.Empty{}, nil
+}
+
+// GetSessionRecordings returns the uploaded session recordings, with the
+// chunks of each recording joined.
+func (f *FakeAgentAPI) GetSessionRecordings() []*agentproto.UploadSessionRecordingRequest {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.sessionRecordings)
+}
+
+func (f *FakeAgentAPI) UpdateDevcontainerApps(_ context.Context, req *agentproto.UpdateDevcontainerAppsRequest) (*emptypb.Empty, error) {
+	f.Lock()
+	defer f.Unlock()
+	f.devcontainerApps = append(f.devcontainerApps, req)
+	return &emptypb.Empty{}, nil
+}
+
+func (f *FakeAgentAPI) GetDevcontainerApps() []*agentproto.UpdateDevcontainerAppsRequest {
+	f.Lock()
+	defer f.Unlock()
+	return slices.Clone(f.devcontainerApps)
+}
+
 func NewFakeAgentAPI(t testing.TB, logger slog.Logger, manifest *agentproto.Manifest, statsCh chan *agentproto.Stats) *FakeAgentAPI {
 	return &FakeAgentAPI{
 		t:           t,
@@ -45,6 +45,7 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 			agentcontainers.WithScriptLogger(func(logSourceID uuid.UUID) agentcontainers.ScriptLogger {
 				return a.logSender.GetScriptLogger(logSourceID)
 			}),
+			agentcontainers.WithAppRegisterer(a.setDevcontainerApps),
 		}
 		manifest := a.manifest.Load()
 		if manifest != nil && len(manifest.Devcontainers) > 0 {
@@ -59,6 +60,7 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 
 		containerAPI := agentcontainers.NewAPI(a.logger.Named("containers"), containerAPIOpts...)
 		r.Mount("/api/v0/containers", containerAPI.Routes())
+		lp.forwardedPorts = containerAPI.ForwardedPorts
 		a.containerAPI.Store(containerAPI)
 	} else {
 		r.HandleFunc("/api/v0/containers", func(w http.ResponseWriter, r *http.Request) {
@@ -74,6 +76,20 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 	r.Get("/api/v0/listening-ports", lp.handler)
 	r.Get("/api/v0/netcheck", a.HandleNetcheck)
 	r.Post("/api/v0/list-directory", a.HandleLS)
+	r.Group(func(r chi.Router) {
+		r.Use(a.blockFileTransferMiddleware)
+		r.Get("/api/v0/stat-file", a.HandleStatFile)
+		r.Get("/api/v0/read-file", a.HandleReadFile)
+		r.Post("/api/v0/write-file", a.HandleWriteFile)
+		r.Post("/api/v0/make-directory", a.HandleMakeDirectory)
+		r.Post("/api/v0/move-file", a.HandleMoveFile)
+		r.Post("/api/v0/delete-file", a.HandleDeleteFile)
+		r.Get("/api/v0/download-archive", a.HandleDownloadArchive)
+		r.Post("/api/v0/upload-archive", a.HandleUploadArchive)
+	})
+	r.Get("/api/v0/processes", a.HandleListProcesses)
+	r.Post("/api/v0/signal-process", a.HandleSignalProcess)
+	r.Get("/api/v0/reconnecting-ptys", a.HandleListReconnectingPTYs)
 	r.Get("/debug/logs", a.HandleHTTPDebugLogs)
 	r.Get("/debug/magicsock", a.HandleHTTPDebugMagicsock)
 	r.Get("/debug/magicsock/debug-logging/{state}", a.HandleHTTPMagicsockDebugLoggingState)
@@ -91,6 +107,9 @@ func (a *agent) apiHandler() (http.Handler, func() error) {
 type listeningPortsHandler struct {
 	ignorePorts   map[int]string
 	cacheDuration time.Duration
+	// forwardedPorts returns the ports the agent forwards to devcontainers
+	// with their names, these are shown instead of the agent process.
+	forwardedPorts func() map[uint16]string
 
 	//nolint: unused  // used on some but not all platforms
 	mut sync.Mutex
@@ -111,6 +130,14 @@ func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request
 		})
 		return
 	}
+	if lp.forwardedPorts != nil {
+		names := lp.forwardedPorts()
+		for i := range ports {
+			if name, ok := names[ports[i].Port]; ok {
+				ports[i].ProcessName = name
+			}
+		}
+	}
 
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentListeningPortsResponse{
 		Ports: ports,
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// setDevcontainerApps sets the labeled ports of the devcontainers to
+// register as workspace apps. They are sent by updateDevcontainerAppsLoop.
+func (a *agent) setDevcontainerApps(devcontainers []codersdk.WorkspaceAgentDevcontainer) {
+	req := &proto.UpdateDevcontainerAppsRequest{}
+	for _, dc := range devcontainers {
+		for _, port := range dc.ForwardedPorts {
+			// Only labeled ports are apps, and only forwarded ports are
+			// reachable through the agent.
+			if port.Label == "" || !port.Forwarded {
+				continue
+			}
+			req.Ports = append(req.Ports, &proto.UpdateDevcontainerAppsRequest_Port{
+				Devcontainer: dc.Name,
+				Port:         uint32(port.Port),
+				Label:        port.Label,
+				Protocol:     port.Protocol,
+			})
+		}
+	}
+	a.devcontainerApps.Store(req)
+
+	select {
+	case a.devcontainerAppsUpdate <- struct{}{}:
+	default:
+	}
+}
+
+// updateDevcontainerAppsLoop sends the labeled ports of the devcontainers to
+// coderd whenever they change. The full set is sent every time, and again
+// after reconnecting, so that coderd can remove the apps of ports that are
+// gone.
+func (a *agent) updateDevcontainerAppsLoop(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
+	var sent *proto.UpdateDevcontainerAppsRequest
+	for {
+		if req := a.devcontainerApps.Load(); req != nil && req != sent {
+			_, err := aAPI.UpdateDevcontainerApps(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("update devcontainer apps: %w", err)
+			}
+			sent = req
+		}
+
+		select {
+		case <-a.devcontainerAppsUpdate:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package agent
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/agent/agentssh"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// blockFileTransferMiddleware rejects requests to the file API when file
+// transfer is blocked, like the SSH server does for file transfer commands.
+func (a *agent) blockFileTransferMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if a.blockFileTransfer {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: agentssh.BlockedFileTransferErrorMessage,
+			})
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (*agent) HandleStatFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := parseFilePath(rw, r)
+	if !ok {
+		return
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.FileInfo{
+		Name:               stat.Name(),
+		AbsolutePathString: path,
+		Size:               stat.Size(),
+		Mode:               stat.Mode(),
+		ModTime:            stat.ModTime(),
+		IsDir:              stat.IsDir(),
+	})
+}
+
+// HandleReadFile streams the contents of a file. The offset and limit query
+// parameters select a section of the file, and range requests are supported
+// within that section.
+func (*agent) HandleReadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := parseFilePath(rw, r)
+	if !ok {
+		return
+	}
+	parser := httpapi.NewQueryParamParser()
+	offset := parser.Int64(r.URL.Query(), 0, "offset")
+	limit := parser.Int64(r.URL.Query(), 0, "limit")
+	if len(parser.Errors) > 0 || offset < 0 || limit < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters offset and limit must be positive integers.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	// codeql[go/path-injection] - The intent is to allow the user to read any file in their workspace.
+	f, err := os.Open(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	if stat.IsDir() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: xerrors.Errorf("path %q is a directory", path).Error(),
+		})
+		return
+	}
+
+	size := max(stat.Size()-offset, 0)
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(rw, r, stat.Name(), stat.ModTime(), io.NewSectionReader(f, offset, size))
+}
+
+// HandleWriteFile writes the request body to a file, creating the file and
+// its missing parent directories.
+func (*agent) HandleWriteFile(rw http.ResponseWriter, r *http.Request) {
+	path, ok := parseFilePath(rw, r)
+	if !ok {
+		return
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	// codeql[go/path-injection] - The intent is to allow the user to write any file in their workspace.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	_, err = io.Copy(f, r.Body)
+	if err != nil {
+		_ = f.Close()
+		writeFileError(rw, r, xerrors.Errorf("write file %q: %w", path, err))
+		return
+	}
+	err = f.Close()
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (*agent) HandleMakeDirectory(rw http.ResponseWriter, r *http.Request) {
+	var req workspacesdk.MakeDirectoryRequest
+	if !httpapi.Read(r.Context(), rw, r, &req) {
+		return
+	}
+	path, err := resolveFilePath(req.Path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	err = os.MkdirAll(path, 0o755)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (*agent) HandleMoveFile(rw http.ResponseWriter, r *http.Request) {
+	var req workspacesdk.MoveFileRequest
+	if !httpapi.Read(r.Context(), rw, r, &req) {
+		return
+	}
+	source, err := resolveFilePath(req.Source)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	destination, err := resolveFilePath(req.Destination)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(destination), 0o755)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+	err = os.Rename(source, destination)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (*agent) HandleDeleteFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req workspacesdk.DeleteFileRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	path, err := resolveFilePath(req.Path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	if req.Recursive {
+		// Guard against wiping the whole file system or home directory
+		// because of an empty or mistyped path.
+		home, _ := os.UserHomeDir()
+		if path == filepath.Dir(path) || path == home {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: xerrors.Errorf("refusing to recursively delete %q", path).Error(),
+			})
+			return
+		}
+		_, err = os.Lstat(path)
+		if err == nil {
+			err = os.RemoveAll(path)
+		}
+	} else {
+		err = os.Remove(path)
+	}
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDownloadArchive streams a file or a directory as a tar archive.
+func (a *agent) HandleDownloadArchive(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := parseFilePath(rw, r)
+	if !ok {
+		return
+	}
+	// Follow links to the archived file or directory, the link itself is
+	// not archived.
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-tar")
+	rw.WriteHeader(http.StatusOK)
+	err = agentfiles.WriteTar(ctx, rw, resolved, filepath.Base(path))
+	if err != nil {
+		// The status has been written already, the client detects the
+		// truncated archive.
+		a.logger.Warn(ctx, "failed to stream archive", slog.F("path", path), slog.Error(err))
+	}
+}
+
+// HandleUploadArchive extracts the tar archive in the request body into a
+// directory.
+func (*agent) HandleUploadArchive(rw http.ResponseWriter, r *http.Request) {
+	path, ok := parseFilePath(rw, r)
+	if !ok {
+		return
+	}
+
+	err := agentfiles.ExtractTar(r.Context(), r.Body, path, "")
+	if err != nil {
+		writeFileError(rw, r, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// parseFilePath parses and resolves the required path query parameter.
+func parseFilePath(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	parser := httpapi.NewQueryParamParser().RequiredNotEmpty("path")
+	path := parser.String(r.URL.Query(), "", "path")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return "", false
+	}
+	resolved, err := resolveFilePath(path)
+	if err != nil {
+		writeFileError(rw, r, err)
+		return "", false
+	}
+	return resolved, true
+}
+
+// resolveFilePath returns the absolute path of p. Paths that are relative or
+// start with ~ are relative to the user's home directory.
+func resolveFilePath(p string) (string, error) {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", xerrors.Errorf("failed to get user home directory: %w", err)
+	}
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		p = p[1:]
+	}
+	return filepath.Join(home, p), nil
+}
+
+func writeFileError(rw http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, os.ErrPermission):
+		status = http.StatusForbidden
+	case errors.Is(err, os.ErrExist):
+		status = http.StatusConflict
+	default:
+	}
+	httpapi.Write(r.Context(), rw, status, codersdk.Response{
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleListProcesses lists the processes running in the workspace with the
+// session that owns them.
+func (a *agent) HandleListProcesses(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	procs, err := agentproc.List(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Could not list processes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Command apps are opened as reconnecting PTYs that run the command of
+	// the app, so attribute those to the app instead.
+	if manifest := a.manifest.Load(); manifest != nil {
+		for _, proc := range procs {
+			owner := proc.Owner
+			if owner == nil || owner.Type != workspacesdk.ProcessOwnerTypeReconnectingPTY || owner.Name == "" {
+				continue
+			}
+			for _, app := range manifest.Apps {
+				if app.Command == owner.Name {
+					*owner = workspacesdk.ProcessOwner{
+						Type: workspacesdk.ProcessOwnerTypeApp,
+						ID:   app.ID.String(),
+						Name: app.Slug,
+					}
+					break
+				}
+			}
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, workspacesdk.ListProcessesResponse{
+		Processes: procs,
+	})
+}
+
+// HandleSignalProcess sends a signal to a process in the workspace.
+func (*agent) HandleSignalProcess(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req workspacesdk.SignalProcessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	err := agentproc.Signal(ctx, req.PID, req.Signal)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, agentproc.ErrNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, os.ErrPermission):
+			status = http.StatusForbidden
+		case errors.Is(err, agentproc.ErrProtectedProcess), errors.Is(err, agentproc.ErrUnsupportedSignal):
+			status = http.StatusBadRequest
+		}
+		httpapi.Write(ctx, rw, status, codersdk.Response{
+			Message: "Could not signal process.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
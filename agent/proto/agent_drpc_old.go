@@ -50,3 +50,11 @@ type DRPCAgentClient24 interface {
 	PushResourcesMonitoringUsage(ctx context.Context, in *PushResourcesMonitoringUsageRequest) (*PushResourcesMonitoringUsageResponse, error)
 	ReportConnection(ctx context.Context, in *ReportConnectionRequest) (*emptypb.Empty, error)
 }
+
+// DRPCAgentClient25 is the Agent API at v2.5. It adds the UploadSessionRecording and
+// UpdateDevcontainerApps RPCs.
+type DRPCAgentClient25 interface {
+	DRPCAgentClient24
+	UploadSessionRecording(ctx context.Context, in *UploadSessionRecordingRequest) (*emptypb.Empty, error)
+	UpdateDevcontainerApps(ctx context.Context, in *UpdateDevcontainerAppsRequest) (*emptypb.Empty, error)
+}
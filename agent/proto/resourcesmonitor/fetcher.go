@@ -1,6 +1,14 @@
 package resourcesmonitor
 
 import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/spf13/afero"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/clistat"
@@ -11,26 +19,53 @@ type Statter interface {
 	ContainerMemory(p clistat.Prefix) (*clistat.Result, error)
 	HostMemory(p clistat.Prefix) (*clistat.Result, error)
 	Disk(p clistat.Prefix, path string) (*clistat.Result, error)
+	ContainerCPU() (*clistat.Result, error)
+	HostCPU() (*clistat.Result, error)
 }
 
 type Fetcher interface {
 	FetchMemory() (total int64, used int64, err error)
 	FetchVolume(volume string) (total int64, used int64, err error)
+	// FetchCPU returns the CPU usage in millicores.
+	FetchCPU() (total int64, used int64, err error)
+	// FetchNetwork returns the number of bytes received and transmitted
+	// on all network interfaces except loopback. The values are counters.
+	FetchNetwork() (received int64, transmitted int64, err error)
+	FetchFileDescriptors() (total int64, used int64, err error)
+	FetchProcesses() (total int64, used int64, err error)
 }
 
 type fetcher struct {
 	Statter
 	isContainerized bool
+	fs              afero.Fs
+}
+
+type FetcherOption func(*fetcher)
+
+// WithFS sets the file system used to read /proc and /sys.
+func WithFS(fs afero.Fs) FetcherOption {
+	return func(f *fetcher) {
+		f.fs = fs
+	}
 }
 
 //nolint:revive
-func NewFetcher(f Statter) (*fetcher, error) {
+func NewFetcher(f Statter, opts ...FetcherOption) (*fetcher, error) {
 	isContainerized, err := f.IsContainerized()
 	if err != nil {
 		return nil, xerrors.Errorf("check is containerized: %w", err)
 	}
 
-	return &fetcher{f, isContainerized}, nil
+	res := &fetcher{
+		Statter:         f,
+		isContainerized: isContainerized,
+		fs:              afero.NewReadOnlyFs(afero.NewOsFs()),
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+	return res, nil
 }
 
 func (f *fetcher) FetchMemory() (total int64, used int64, err error) {
@@ -79,3 +114,169 @@ func (f *fetcher) FetchVolume(volume string) (total int64, used int64, err error
 
 	return int64(*vol.Total), int64(vol.Used), nil
 }
+
+func (f *fetcher) FetchCPU() (total int64, used int64, err error) {
+	var cpu *clistat.Result
+
+	if f.isContainerized {
+		cpu, err = f.ContainerCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch container cpu: %w", err)
+		}
+	}
+
+	// Fall back to the host if the agent is not containerized, or if
+	// the container does not have a CPU limit.
+	if cpu == nil || cpu.Total == nil {
+		hostCPU, err := f.HostCPU()
+		if err != nil {
+			return 0, 0, xerrors.Errorf("fetch host cpu: %w", err)
+		}
+		if cpu == nil {
+			cpu = hostCPU
+		} else {
+			cpu.Total = hostCPU.Total
+		}
+	}
+
+	if cpu.Total == nil {
+		return 0, 0, xerrors.New("cpu total is nil - can not fetch cpu")
+	}
+
+	return int64(*cpu.Total * 1000), int64(cpu.Used * 1000), nil
+}
+
+func (*fetcher) FetchNetwork() (received int64, transmitted int64, err error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch network counters: %w", err)
+	}
+
+	for _, counter := range counters {
+		if counter.Name == "lo" || counter.Name == "lo0" {
+			continue
+		}
+		// #nosec G115 - Byte counters do not overflow int64 in practice.
+		received += int64(counter.BytesRecv)
+		// #nosec G115 - Byte counters do not overflow int64 in practice.
+		transmitted += int64(counter.BytesSent)
+	}
+
+	return received, transmitted, nil
+}
+
+// FetchFileDescriptors returns the number of allocated file handles and the
+// maximum of the kernel. File handles are not namespaced, so these are the
+// values of the host for containerized agents too.
+func (f *fetcher) FetchFileDescriptors() (total int64, used int64, err error) {
+	fields, err := readFields(f.fs, "/proc/sys/fs/file-nr")
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch file descriptors: %w", err)
+	}
+	if len(fields) != 3 {
+		return 0, 0, xerrors.Errorf("fetch file descriptors: unexpected format %q", fields)
+	}
+
+	allocated, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("parse allocated file descriptors: %w", err)
+	}
+	unused, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("parse unused file descriptors: %w", err)
+	}
+	maximum, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("parse maximum file descriptors: %w", err)
+	}
+
+	return maximum, allocated - unused, nil
+}
+
+// FetchProcesses returns the number of tasks, i.e. processes and threads, and
+// the maximum number of tasks. The pids cgroup controller is used for
+// containers that have a limit, otherwise it is the kernel limit of the host.
+// Monitors compare the number of tasks to their threshold, the maximum is
+// only reported for context.
+func (f *fetcher) FetchProcesses() (total int64, used int64, err error) {
+	if f.isContainerized {
+		for _, dir := range []string{"/sys/fs/cgroup", "/sys/fs/cgroup/pids"} {
+			total, used, err := f.cgroupPids(dir)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return 0, 0, err
+			}
+			if total > 0 {
+				return total, used, nil
+			}
+			break
+		}
+	}
+
+	// The fourth field of /proc/loadavg is the number of currently
+	// runnable and existing tasks, e.g. 1/234.
+	fields, err := readFields(f.fs, "/proc/loadavg")
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch processes: %w", err)
+	}
+	if len(fields) < 4 {
+		return 0, 0, xerrors.Errorf("fetch processes: unexpected format %q", fields)
+	}
+	_, tasks, ok := strings.Cut(fields[3], "/")
+	if !ok {
+		return 0, 0, xerrors.Errorf("fetch processes: unexpected format %q", fields)
+	}
+	used, err = strconv.ParseInt(tasks, 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("parse processes: %w", err)
+	}
+	total, err = readInt64(f.fs, "/proc/sys/kernel/pid_max")
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch maximum processes: %w", err)
+	}
+
+	return total, used, nil
+}
+
+// cgroupPids returns the limit and the number of tasks of the pids cgroup
+// controller in dir. The limit is zero if there is none.
+func (f *fetcher) cgroupPids(dir string) (total int64, used int64, err error) {
+	fields, err := readFields(f.fs, dir+"/pids.max")
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch cgroup pids limit: %w", err)
+	}
+	if len(fields) != 1 || fields[0] == "max" {
+		return 0, 0, nil
+	}
+	total, err = strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("parse cgroup pids limit: %w", err)
+	}
+	used, err = readInt64(f.fs, dir+"/pids.current")
+	if err != nil {
+		return 0, 0, xerrors.Errorf("fetch cgroup pids: %w", err)
+	}
+
+	return total, used, nil
+}
+
+func readFields(afs afero.Fs, path string) ([]string, error) {
+	data, err := afero.ReadFile(afs, path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(bytes.TrimSpace(data))), nil
+}
+
+func readInt64(afs afero.Fs, path string) (int64, error) {
+	fields, err := readFields(afs, path)
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) != 1 {
+		return 0, xerrors.Errorf("unexpected format of %s: %q", path, fields)
+	}
+	return strconv.ParseInt(fields[0], 10, 64)
+}
@@ -3,6 +3,7 @@ package resourcesmonitor_test
 import (
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -16,6 +17,8 @@ type mockStatter struct {
 	containerMemory clistat.Result
 	hostMemory      clistat.Result
 	disk            map[string]clistat.Result
+	containerCPU    clistat.Result
+	hostCPU         clistat.Result
 }
 
 func (s *mockStatter) IsContainerized() (bool, error) {
@@ -38,6 +41,14 @@ func (s *mockStatter) Disk(_ clistat.Prefix, path string) (*clistat.Result, erro
 	return &disk, nil
 }
 
+func (s *mockStatter) ContainerCPU() (*clistat.Result, error) {
+	return &s.containerCPU, nil
+}
+
+func (s *mockStatter) HostCPU() (*clistat.Result, error) {
+	return &s.hostCPU, nil
+}
+
 func TestFetchMemory(t *testing.T) {
 	t.Parallel()
 
@@ -107,3 +118,160 @@ func TestFetchMemory(t *testing.T) {
 		require.Equal(t, int64(30), total)
 	})
 }
+
+func TestFetchCPU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IsContainerized", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("WithCPULimit", func(t *testing.T) {
+			t.Parallel()
+
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+				isContainerized: true,
+				containerCPU: clistat.Result{
+					Used:  0.5,
+					Total: ptr.Ref(2.0),
+				},
+				hostCPU: clistat.Result{
+					Used:  4.0,
+					Total: ptr.Ref(8.0),
+				},
+			})
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchCPU()
+			require.NoError(t, err)
+			require.Equal(t, int64(500), used)
+			require.Equal(t, int64(2000), total)
+		})
+
+		t.Run("WithoutCPULimit", func(t *testing.T) {
+			t.Parallel()
+
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+				isContainerized: true,
+				containerCPU: clistat.Result{
+					Used:  0.5,
+					Total: nil,
+				},
+				hostCPU: clistat.Result{
+					Used:  4.0,
+					Total: ptr.Ref(8.0),
+				},
+			})
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchCPU()
+			require.NoError(t, err)
+			require.Equal(t, int64(500), used)
+			require.Equal(t, int64(8000), total)
+		})
+	})
+
+	t.Run("IsHost", func(t *testing.T) {
+		t.Parallel()
+
+		fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{
+			isContainerized: false,
+			hostCPU: clistat.Result{
+				Used:  4.0,
+				Total: ptr.Ref(8.0),
+			},
+		})
+		require.NoError(t, err)
+
+		total, used, err := fetcher.FetchCPU()
+		require.NoError(t, err)
+		require.Equal(t, int64(4000), used)
+		require.Equal(t, int64(8000), total)
+	})
+}
+
+func TestFetchFileDescriptors(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/proc/sys/fs/file-nr", []byte("1184\t16\t9223372036854775807\n"), 0o444))
+	fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{}, resourcesmonitor.WithFS(fs))
+	require.NoError(t, err)
+
+	total, used, err := fetcher.FetchFileDescriptors()
+	require.NoError(t, err)
+	require.Equal(t, int64(1168), used)
+	require.Equal(t, int64(9223372036854775807), total)
+}
+
+func TestFetchProcesses(t *testing.T) {
+	t.Parallel()
+
+	hostFS := func(t *testing.T) afero.Fs {
+		t.Helper()
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, "/proc/loadavg", []byte("0.52 0.58 0.59 3/1234 56789\n"), 0o444))
+		require.NoError(t, afero.WriteFile(fs, "/proc/sys/kernel/pid_max", []byte("4194304\n"), 0o444))
+		return fs
+	}
+
+	t.Run("IsHost", func(t *testing.T) {
+		t.Parallel()
+
+		fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{}, resourcesmonitor.WithFS(hostFS(t)))
+		require.NoError(t, err)
+
+		total, used, err := fetcher.FetchProcesses()
+		require.NoError(t, err)
+		require.Equal(t, int64(1234), used)
+		require.Equal(t, int64(4194304), total)
+	})
+
+	t.Run("IsContainerized", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("WithPidsLimit", func(t *testing.T) {
+			t.Parallel()
+
+			fs := hostFS(t)
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids.max", []byte("4096\n"), 0o444))
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids.current", []byte("42\n"), 0o444))
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{isContainerized: true}, resourcesmonitor.WithFS(fs))
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchProcesses()
+			require.NoError(t, err)
+			require.Equal(t, int64(42), used)
+			require.Equal(t, int64(4096), total)
+		})
+
+		t.Run("WithPidsLimitCGroupV1", func(t *testing.T) {
+			t.Parallel()
+
+			fs := hostFS(t)
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids/pids.max", []byte("4096\n"), 0o444))
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids/pids.current", []byte("42\n"), 0o444))
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{isContainerized: true}, resourcesmonitor.WithFS(fs))
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchProcesses()
+			require.NoError(t, err)
+			require.Equal(t, int64(42), used)
+			require.Equal(t, int64(4096), total)
+		})
+
+		t.Run("WithoutPidsLimit", func(t *testing.T) {
+			t.Parallel()
+
+			fs := hostFS(t)
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids.max", []byte("max\n"), 0o444))
+			require.NoError(t, afero.WriteFile(fs, "/sys/fs/cgroup/pids.current", []byte("42\n"), 0o444))
+			fetcher, err := resourcesmonitor.NewFetcher(&mockStatter{isContainerized: true}, resourcesmonitor.WithFS(fs))
+			require.NoError(t, err)
+
+			total, used, err := fetcher.FetchProcesses()
+			require.NoError(t, err)
+			require.Equal(t, int64(1234), used)
+			require.Equal(t, int64(4194304), total)
+		})
+	})
+}
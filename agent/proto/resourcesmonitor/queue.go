@@ -9,9 +9,13 @@ import (
 )
 
 type Datapoint struct {
-	CollectedAt time.Time
-	Memory      *MemoryDatapoint
-	Volumes     []*VolumeDatapoint
+	CollectedAt     time.Time
+	Memory          *MemoryDatapoint
+	Volumes         []*VolumeDatapoint
+	CPU             *CPUDatapoint
+	Network         *NetworkDatapoint
+	FileDescriptors *FileDescriptorsDatapoint
+	Processes       *ProcessesDatapoint
 }
 
 type MemoryDatapoint struct {
@@ -25,6 +29,27 @@ type VolumeDatapoint struct {
 	Used  int64
 }
 
+// CPUDatapoint is in millicores.
+type CPUDatapoint struct {
+	Total int64
+	Used  int64
+}
+
+type NetworkDatapoint struct {
+	ReceivedBytesPerSecond    int64
+	TransmittedBytesPerSecond int64
+}
+
+type FileDescriptorsDatapoint struct {
+	Total int64
+	Used  int64
+}
+
+type ProcessesDatapoint struct {
+	Total int64
+	Used  int64
+}
+
 // Queue represents a FIFO queue with a fixed size
 type Queue struct {
 	items []Datapoint
@@ -78,6 +103,34 @@ func (q *Queue) ItemsAsProto() []*proto.PushResourcesMonitoringUsageRequest_Data
 			})
 		}
 
+		if item.CPU != nil {
+			protoItem.Cpu = &proto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{
+				Total: item.CPU.Total,
+				Used:  item.CPU.Used,
+			}
+		}
+
+		if item.Network != nil {
+			protoItem.Network = &proto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage{
+				ReceivedBytesPerSecond:    item.Network.ReceivedBytesPerSecond,
+				TransmittedBytesPerSecond: item.Network.TransmittedBytesPerSecond,
+			}
+		}
+
+		if item.FileDescriptors != nil {
+			protoItem.FileDescriptors = &proto.PushResourcesMonitoringUsageRequest_Datapoint_FileDescriptorsUsage{
+				Total: item.FileDescriptors.Total,
+				Used:  item.FileDescriptors.Used,
+			}
+		}
+
+		if item.Processes != nil {
+			protoItem.Processes = &proto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{
+				Total: item.Processes.Total,
+				Used:  item.Processes.Used,
+			}
+		}
+
 		items = append(items, protoItem)
 	}
 
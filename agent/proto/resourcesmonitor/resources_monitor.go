@@ -16,6 +16,16 @@ type monitor struct {
 	resourcesFetcher Fetcher
 	datapointsPusher datapointsPusher
 	queue            *Queue
+
+	// The network counters of the previous collection, throughput is
+	// computed from the difference to the current counters.
+	lastNetwork *networkCounters
+}
+
+type networkCounters struct {
+	collectedAt time.Time
+	received    int64
+	transmitted int64
 }
 
 //nolint:revive
@@ -71,6 +81,46 @@ func (m *monitor) Start(ctx context.Context) error {
 			})
 		}
 
+		if m.config.Cpu != nil && m.config.Cpu.Enabled {
+			cpuTotal, cpuUsed, err := m.resourcesFetcher.FetchCPU()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch cpu", slog.Error(err))
+			} else {
+				datapoint.CPU = &CPUDatapoint{
+					Total: cpuTotal,
+					Used:  cpuUsed,
+				}
+			}
+		}
+
+		if m.config.Network != nil && m.config.Network.Enabled {
+			datapoint.Network = m.fetchNetwork(ctx, datapoint.CollectedAt)
+		}
+
+		if m.config.FileDescriptors != nil && m.config.FileDescriptors.Enabled {
+			fdTotal, fdUsed, err := m.resourcesFetcher.FetchFileDescriptors()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch file descriptors", slog.Error(err))
+			} else {
+				datapoint.FileDescriptors = &FileDescriptorsDatapoint{
+					Total: fdTotal,
+					Used:  fdUsed,
+				}
+			}
+		}
+
+		if m.config.Processes != nil && m.config.Processes.Enabled {
+			procTotal, procUsed, err := m.resourcesFetcher.FetchProcesses()
+			if err != nil {
+				m.logger.Error(ctx, "failed to fetch processes", slog.Error(err))
+			} else {
+				datapoint.Processes = &ProcessesDatapoint{
+					Total: procTotal,
+					Used:  procUsed,
+				}
+			}
+		}
+
 		m.queue.Push(datapoint)
 
 		if m.queue.IsFull() {
@@ -91,3 +141,33 @@ func (m *monitor) Start(ctx context.Context) error {
 
 	return nil
 }
+
+// fetchNetwork returns the network throughput since the previous collection.
+// It returns nil on the first collection, and when the counters were reset.
+func (m *monitor) fetchNetwork(ctx context.Context, now time.Time) *NetworkDatapoint {
+	received, transmitted, err := m.resourcesFetcher.FetchNetwork()
+	if err != nil {
+		m.logger.Error(ctx, "failed to fetch network", slog.Error(err))
+		m.lastNetwork = nil
+		return nil
+	}
+
+	last := m.lastNetwork
+	m.lastNetwork = &networkCounters{
+		collectedAt: now,
+		received:    received,
+		transmitted: transmitted,
+	}
+	if last == nil || received < last.received || transmitted < last.transmitted {
+		return nil
+	}
+	elapsed := now.Sub(last.collectedAt).Seconds()
+	if elapsed <= 0 {
+		return nil
+	}
+
+	return &NetworkDatapoint{
+		ReceivedBytesPerSecond:    int64(float64(received-last.received) / elapsed),
+		TransmittedBytesPerSecond: int64(float64(transmitted-last.transmitted) / elapsed),
+	}
+}
@@ -31,6 +31,10 @@ type fetcher struct {
 
 	errMemory error
 	errVolume error
+
+	// networkBytesPerCall is added to the network counters on each call.
+	networkBytesPerCall int64
+	networkBytes        int64
 }
 
 func (r *fetcher) FetchMemory() (total int64, used int64, err error) {
@@ -41,6 +45,23 @@ func (r *fetcher) FetchVolume(_ string) (total int64, used int64, err error) {
 	return r.totalVolume, r.usedVolume, r.errVolume
 }
 
+func (*fetcher) FetchCPU() (total int64, used int64, err error) {
+	return 4000, 1500, nil
+}
+
+func (r *fetcher) FetchNetwork() (received int64, transmitted int64, err error) {
+	r.networkBytes += r.networkBytesPerCall
+	return r.networkBytes, r.networkBytes / 2, nil
+}
+
+func (*fetcher) FetchFileDescriptors() (total int64, used int64, err error) {
+	return 1000, 100, nil
+}
+
+func (*fetcher) FetchProcesses() (total int64, used int64, err error) {
+	return 4096, 50, nil
+}
+
 func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -126,6 +147,41 @@ func TestPushResourcesMonitoringWithConfig(t *testing.T) {
 			},
 			numTicks: 60,
 		},
+		{
+			name: "SuccessfulMonitoringAllResources",
+			config: &proto.GetResourcesMonitoringConfigurationResponse{
+				Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
+					NumDatapoints:             20,
+					CollectionIntervalSeconds: 1,
+				},
+				Cpu:             &proto.GetResourcesMonitoringConfigurationResponse_CPU{Enabled: true},
+				Network:         &proto.GetResourcesMonitoringConfigurationResponse_Network{Enabled: true},
+				FileDescriptors: &proto.GetResourcesMonitoringConfigurationResponse_FileDescriptors{Enabled: true},
+				Processes:       &proto.GetResourcesMonitoringConfigurationResponse_Processes{Enabled: true},
+			},
+			datapointsPusher: func(_ context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
+				require.Len(t, req.Datapoints, 20)
+				for _, datapoint := range req.Datapoints {
+					require.Nil(t, datapoint.Memory)
+					require.Equal(t, int64(1500), datapoint.GetCpu().GetUsed())
+					require.Equal(t, int64(4000), datapoint.GetCpu().GetTotal())
+					require.Equal(t, int64(100), datapoint.GetFileDescriptors().GetUsed())
+					require.Equal(t, int64(4096), datapoint.GetProcesses().GetTotal())
+				}
+				// The throughput is only known from the second collection.
+				require.Nil(t, req.Datapoints[0].Network)
+				require.Equal(t, &proto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage{
+					ReceivedBytesPerSecond:    2000,
+					TransmittedBytesPerSecond: 1000,
+				}, req.Datapoints[1].Network)
+
+				return &proto.PushResourcesMonitoringUsageResponse{}, nil
+			},
+			fetcher: &fetcher{
+				networkBytesPerCall: 2000,
+			},
+			numTicks: 20,
+		},
 		{
 			// If one of the resources fails to be fetched, the datapoints still should be pushed with the other resources.
 			name: "ErrorFetchingMemory",
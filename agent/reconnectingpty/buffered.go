@@ -30,6 +30,10 @@ type bufferedReconnectingPTY struct {
 	process pty.Process
 
 	metrics *prometheus.CounterVec
+	// reportActivity is called when input is written to the pty.
+	reportActivity func()
+	// reportResize is called when a connection resizes the pty.
+	reportResize func(height, width uint16)
 
 	state *ptyState
 	// timer will close the reconnecting pty when it expires.  The timer will be
@@ -42,11 +46,13 @@ type bufferedReconnectingPTY struct {
 // killed.
 func newBuffered(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *pty.Cmd, options *Options) *bufferedReconnectingPTY {
 	rpty := &bufferedReconnectingPTY{
-		activeConns: map[string]net.Conn{},
-		command:     cmd,
-		metrics:     options.Metrics,
-		state:       newState(),
-		timeout:     options.Timeout,
+		activeConns:    map[string]net.Conn{},
+		command:        cmd,
+		metrics:        options.Metrics,
+		reportActivity: options.ReportActivity,
+		reportResize:   options.ReportResize,
+		state:          newState(),
+		timeout:        options.Timeout,
 	}
 
 	// Default to buffer 64KiB.
@@ -207,7 +213,7 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 	}
 
 	// Pipe conn -> pty and block.  pty -> conn is handled in newBuffered().
-	readConnLoop(ctx, conn, rpty.ptty, rpty.metrics, logger)
+	readConnLoop(ctx, conn, rpty.ptty, rpty.metrics, rpty.reportActivity, rpty.reportResize, logger)
 	return nil
 }
 
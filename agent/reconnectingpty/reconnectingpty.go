@@ -34,6 +34,10 @@ type Options struct {
 	Metrics *prometheus.CounterVec
 	// BackendType specifies the ReconnectingPTY backend to use.
 	BackendType string
+	// ReportActivity is called when input is written to the pty.
+	ReportActivity func()
+	// ReportResize is called when a connection resizes the pty.
+	ReportResize func(height, width uint16)
 }
 
 // ReconnectingPTY is a pty that can be reconnected within a timeout and to
@@ -62,6 +66,12 @@ func New(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *
 	if options.Timeout == 0 {
 		options.Timeout = 5 * time.Minute
 	}
+	if options.ReportActivity == nil {
+		options.ReportActivity = func() {}
+	}
+	if options.ReportResize == nil {
+		options.ReportResize = func(uint16, uint16) {}
+	}
 	// Screen seems flaky on Darwin.  Locally the tests pass 100% of the time (100
 	// runs) but in CI screen often incorrectly claims the session name does not
 	// exist even though screen -list shows it.  For now, restrict screen to
@@ -203,7 +213,7 @@ func (s *ptyState) waitForStateOrContext(ctx context.Context, state State) (Stat
 
 // readConnLoop reads messages from conn and writes to ptty as needed.  Blocks
 // until EOF or an error writing to ptty or reading from conn.
-func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *prometheus.CounterVec, logger slog.Logger) {
+func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *prometheus.CounterVec, reportActivity func(), reportResize func(height, width uint16), logger slog.Logger) {
 	decoder := json.NewDecoder(conn)
 	for {
 		var req workspacesdk.ReconnectingPTYRequest
@@ -221,6 +231,9 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			metrics.WithLabelValues("input_writer").Add(1)
 			return
 		}
+		if req.Data != "" {
+			reportActivity()
+		}
 		// Check if a resize needs to happen!
 		if req.Height == 0 || req.Width == 0 {
 			continue
@@ -230,6 +243,8 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			// We can continue after this, it's not fatal!
 			logger.Warn(ctx, "reconnecting pty resize failed, but will continue", slog.Error(err))
 			metrics.WithLabelValues("resize").Add(1)
+			continue
 		}
+		reportResize(req.Height, req.Width)
 	}
 }
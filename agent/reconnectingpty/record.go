@@ -0,0 +1,137 @@
+package reconnectingpty
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+
+	"github.com/coder/coder/v2/agent/agentrecording"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+const (
+	// recordedInputChunks is the number of reads that may be queued for the
+	// recording before further reads are dropped.
+	recordedInputChunks = 64
+	// maxRecordedRequestSize is the size after which a partial request is
+	// discarded.
+	maxRecordedRequestSize = 1 << 20
+)
+
+// recordedConn records the output written to a reconnecting pty connection,
+// and the input and resizes read from it.
+type recordedConn struct {
+	net.Conn
+	rec    *agentrecording.Recorder
+	output io.Writer
+	// input receives copies of what is read from the connection, so that
+	// the JSON-encoded requests can be decoded and recorded. Reads are
+	// dropped when it is full, recording must never slow down the session.
+	input   chan []byte
+	dropped atomic.Bool
+	closing chan struct{}
+	done    chan struct{}
+}
+
+func newRecordedConn(conn net.Conn, rec *agentrecording.Recorder) *recordedConn {
+	c := &recordedConn{
+		Conn:    conn,
+		rec:     rec,
+		output:  rec.Output(),
+		input:   make(chan []byte, recordedInputChunks),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go c.recordInput()
+	return c
+}
+
+func (c *recordedConn) recordInput() {
+	defer close(c.done)
+
+	var buf []byte
+	handle := func(chunk []byte) {
+		if c.dropped.Swap(false) {
+			// Part of the stream is missing, so the buffered request can't
+			// be completed.
+			buf = nil
+		}
+		buf = append(buf, chunk...)
+		buf = c.decodeRequests(buf)
+		if len(buf) > maxRecordedRequestSize {
+			buf = nil
+		}
+	}
+	for {
+		select {
+		case chunk := <-c.input:
+			handle(chunk)
+		case <-c.closing:
+			for {
+				select {
+				case chunk := <-c.input:
+					handle(chunk)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// decodeRequests records the complete requests in buf and returns what is
+// left of it. If buf does not start with a valid request, e.g. because a
+// read was dropped, it is discarded until the next read.
+func (c *recordedConn) decodeRequests(buf []byte) []byte {
+	for len(buf) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(buf))
+		var req workspacesdk.ReconnectingPTYRequest
+		err := decoder.Decode(&req)
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return buf
+		}
+		if err != nil {
+			return nil
+		}
+		buf = buf[decoder.InputOffset():]
+
+		if req.Data != "" {
+			_, _ = c.rec.Input().Write([]byte(req.Data))
+		}
+		if req.Height != 0 && req.Width != 0 {
+			c.rec.Resize(int(req.Width), int(req.Height))
+		}
+	}
+	return buf
+}
+
+func (c *recordedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		select {
+		case c.input <- bytes.Clone(p[:n]):
+		default:
+			c.dropped.Store(true)
+		}
+	}
+	return n, err
+}
+
+func (c *recordedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		_, _ = c.output.Write(p[:n])
+	}
+	return n, err
+}
+
+// closeRecording records what is left of the input and closes the
+// recorder.
+func (c *recordedConn) closeRecording() error {
+	close(c.closing)
+	<-c.done
+	return c.rec.Close()
+}
@@ -46,6 +46,10 @@ type screenReconnectingPTY struct {
 	configFile string
 
 	metrics *prometheus.CounterVec
+	// reportActivity is called when input is written to the pty.
+	reportActivity func()
+	// reportResize is called when a connection resizes the pty.
+	reportResize func(height, width uint16)
 
 	state *ptyState
 	// timer will close the reconnecting pty when it expires.  The timer will be
@@ -62,11 +66,13 @@ type screenReconnectingPTY struct {
 // own which causes it to spawn with the specified size.
 func newScreen(ctx context.Context, logger slog.Logger, execer agentexec.Execer, cmd *pty.Cmd, options *Options) *screenReconnectingPTY {
 	rpty := &screenReconnectingPTY{
-		execer:  execer,
-		command: cmd,
-		metrics: options.Metrics,
-		state:   newState(),
-		timeout: options.Timeout,
+		execer:         execer,
+		command:        cmd,
+		metrics:        options.Metrics,
+		reportActivity: options.ReportActivity,
+		reportResize:   options.ReportResize,
+		state:          newState(),
+		timeout:        options.Timeout,
 	}
 
 	// Socket paths are limited to around 100 characters on Linux and macOS which
@@ -197,7 +203,7 @@ func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn ne
 	}()
 
 	// Pipe conn -> pty and block.
-	readConnLoop(ctx, conn, ptty, rpty.metrics, logger)
+	readConnLoop(ctx, conn, ptty, rpty.metrics, rpty.reportActivity, rpty.reportResize, logger)
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"net"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +16,8 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/agent/agentcontainers"
+	"github.com/coder/coder/v2/agent/agentproc"
+	"github.com/coder/coder/v2/agent/agentrecording"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
@@ -32,7 +35,15 @@ type Server struct {
 	reconnectingPTYs sync.Map
 	timeout          time.Duration
 
+	sessionsMu sync.Mutex
+	sessions   map[uuid.UUID]*session
+
 	ExperimentalDevcontainersEnabled bool
+	// RecordSession returns a recorder for the connection with the given
+	// ID, or nil if it should not be recorded.
+	RecordSession func(id uuid.UUID, header agentrecording.Header) *agentrecording.Recorder
+	// ReportActivity is called when input is written to a pty.
+	ReportActivity func()
 }
 
 // NewServer returns a new ReconnectingPTY server
@@ -52,6 +63,7 @@ func NewServer(logger slog.Logger, commandCreator *agentssh.Server, reportConnec
 		connectionsTotal: connectionsTotal,
 		errorsTotal:      errorsTotal,
 		timeout:          timeout,
+		sessions:         make(map[uuid.UUID]*session),
 	}
 	for _, o := range opts {
 		o(s)
@@ -76,7 +88,8 @@ func (s *Server) Serve(ctx, hardCtx context.Context, l net.Listener) (retErr err
 			slog.F("local", conn.LocalAddr().String()))
 		clog.Info(ctx, "accepted conn")
 		wg.Add(1)
-		disconnected := s.reportConnection(uuid.New(), conn.RemoteAddr().String())
+		id := uuid.New()
+		disconnected := s.reportConnection(id, conn.RemoteAddr().String())
 		closed := make(chan struct{})
 		go func() {
 			defer wg.Done()
@@ -91,7 +104,7 @@ func (s *Server) Serve(ctx, hardCtx context.Context, l net.Listener) (retErr err
 		go func() {
 			defer close(closed)
 			defer wg.Done()
-			err := s.handleConn(ctx, clog, conn)
+			err := s.handleConn(ctx, clog, id, conn)
 			if err != nil {
 				if ctx.Err() != nil {
 					disconnected(1, "server shutting down")
@@ -111,7 +124,27 @@ func (s *Server) ConnCount() int64 {
 	return s.connCount.Load()
 }
 
-func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Conn) (retErr error) {
+// Sessions returns the live reconnecting pty sessions, oldest first.
+func (s *Server) Sessions() []workspacesdk.ReconnectingPTYSession {
+	s.sessionsMu.Lock()
+	sessions := make([]workspacesdk.ReconnectingPTYSession, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess.info())
+	}
+	s.sessionsMu.Unlock()
+	slices.SortFunc(sessions, func(a, b workspacesdk.ReconnectingPTYSession) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return sessions
+}
+
+func (s *Server) session(id uuid.UUID) *session {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+	return s.sessions[id]
+}
+
+func (s *Server) handleConn(ctx context.Context, logger slog.Logger, id uuid.UUID, conn net.Conn) (retErr error) {
 	defer conn.Close()
 	s.connectionsTotal.Add(1)
 	s.connCount.Add(1)
@@ -144,9 +177,31 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 	}
 
 	connectionID := uuid.NewString()
-	connLogger := logger.With(slog.F("message_id", msg.ID), slog.F("connection_id", connectionID), slog.F("container", msg.Container), slog.F("container_user", msg.ContainerUser))
+	connLogger := logger.With(slog.F("message_id", msg.ID), slog.F("connection_id", connectionID), slog.F("container", msg.Container), slog.F("container_user", msg.ContainerUser), slog.F("read_only", msg.ReadOnly))
 	connLogger.Debug(ctx, "starting handler")
 
+	if s.RecordSession != nil {
+		rec := s.RecordSession(id, agentrecording.Header{
+			Width:  int(msg.Width),
+			Height: int(msg.Height),
+			Env:    map[string]string{"TERM": "xterm-256color"},
+		})
+		if rec != nil {
+			rconn := newRecordedConn(conn, rec)
+			defer func() {
+				// Close the connection first, so that nothing is read
+				// from it while the recording is closed.
+				_ = conn.Close()
+				err := rconn.closeRecording()
+				if err != nil {
+					s.errorsTotal.WithLabelValues("recording").Add(1)
+					connLogger.Warn(ctx, "failed to close session recording", slog.Error(err))
+				}
+			}()
+			conn = rconn
+		}
+	}
+
 	defer func() {
 		if err := retErr; err != nil {
 			// If the context is done, we don't want to log this as an error since it's expected.
@@ -159,10 +214,25 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 		connLogger.Info(ctx, "reconnecting pty connection closed")
 	}()
 
-	var rpty ReconnectingPTY
-	sendConnected := make(chan ReconnectingPTY, 1)
-	// On store, reserve this ID to prevent multiple concurrent new connections.
-	waitReady, ok := s.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	var (
+		rpty          ReconnectingPTY
+		sess          *session
+		waitReady     any
+		ok            bool
+		sendConnected = make(chan ReconnectingPTY, 1)
+	)
+	if msg.ReadOnly {
+		// Read-only connections can only watch an existing session.
+		waitReady, ok = s.reconnectingPTYs.Load(msg.ID)
+		if !ok {
+			close(sendConnected) // Unused.
+			return xerrors.Errorf("reconnecting pty %s not found", msg.ID)
+		}
+		conn = readOnlyConn{Conn: conn}
+	} else {
+		// On store, reserve this ID to prevent multiple concurrent new connections.
+		waitReady, ok = s.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	}
 	if ok {
 		close(sendConnected) // Unused.
 		connLogger.Debug(ctx, "connecting to existing reconnecting pty")
@@ -175,6 +245,10 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 			return xerrors.Errorf("reconnecting pty closed before connection")
 		}
 		c <- rpty // Put it back for the next reconnect.
+		sess = s.session(msg.ID)
+		if sess == nil {
+			return xerrors.Errorf("reconnecting pty closed before connection")
+		}
 	} else {
 		connLogger.Debug(ctx, "creating new reconnecting pty")
 
@@ -201,15 +275,23 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 			s.errorsTotal.WithLabelValues("create_command").Add(1)
 			return xerrors.Errorf("create command: %w", err)
 		}
+		cmd.Env = append(cmd.Env, agentproc.OwnerEnv(workspacesdk.ProcessOwner{
+			Type: workspacesdk.ProcessOwnerTypeReconnectingPTY,
+			ID:   msg.ID.String(),
+			Name: msg.Command,
+		}))
 
+		sess = newSession(msg, time.Now())
 		rpty = New(ctx,
 			logger.With(slog.F("message_id", msg.ID)),
 			s.commandCreator.Execer,
 			cmd,
 			&Options{
-				Timeout:     s.timeout,
-				Metrics:     s.errorsTotal,
-				BackendType: msg.BackendType,
+				Timeout:        s.timeout,
+				Metrics:        s.errorsTotal,
+				BackendType:    msg.BackendType,
+				ReportActivity: s.ReportActivity,
+				ReportResize:   sess.resize,
 			},
 		)
 
@@ -225,10 +307,29 @@ func (s *Server) handleConn(ctx context.Context, logger slog.Logger, conn net.Co
 		go func() {
 			rpty.Wait()
 			s.reconnectingPTYs.Delete(msg.ID)
+			s.sessionsMu.Lock()
+			if s.sessions[msg.ID] == sess {
+				delete(s.sessions, msg.ID)
+			}
+			s.sessionsMu.Unlock()
 		}()
 
+		s.sessionsMu.Lock()
+		s.sessions[msg.ID] = sess
+		s.sessionsMu.Unlock()
+
 		connected = true
 		sendConnected <- rpty
 	}
-	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
+
+	height, width := msg.Height, msg.Width
+	if msg.ReadOnly {
+		// Attach at the size of the session so that watching it does not
+		// resize the terminal of the other clients.
+		height, width = sess.size()
+	} else {
+		sess.resize(height, width)
+	}
+	defer sess.attach(connectionID, conn, msg.ReadOnly)()
+	return rpty.Attach(ctx, connectionID, conn, height, width, connLogger)
 }
@@ -0,0 +1,109 @@
+package reconnectingpty
+
+import (
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// session tracks what clients can see of a reconnecting pty: the command it
+// runs, its size and the connections attached to it.
+type session struct {
+	id        uuid.UUID
+	command   string
+	container string
+	createdAt time.Time
+
+	mu     sync.Mutex
+	height uint16
+	width  uint16
+	conns  map[string]workspacesdk.ReconnectingPTYConnection
+}
+
+func newSession(init workspacesdk.AgentReconnectingPTYInit, createdAt time.Time) *session {
+	return &session{
+		id:        init.ID,
+		command:   init.Command,
+		container: init.Container,
+		createdAt: createdAt,
+		height:    init.Height,
+		width:     init.Width,
+		conns:     make(map[string]workspacesdk.ReconnectingPTYConnection),
+	}
+}
+
+// resize records the size of the pty.  Sizes with a zero dimension are
+// ignored, like they are by the pty.
+func (s *session) resize(height, width uint16) {
+	if height == 0 || width == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.height, s.width = height, width
+}
+
+func (s *session) size() (height, width uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.height, s.width
+}
+
+// attach records a connection to the session until detach is called.
+func (s *session) attach(id string, conn net.Conn, readOnly bool) (detach func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[id] = workspacesdk.ReconnectingPTYConnection{
+		ID:          id,
+		RemoteAddr:  conn.RemoteAddr().String(),
+		ReadOnly:    readOnly,
+		ConnectedAt: time.Now(),
+	}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.conns, id)
+	}
+}
+
+func (s *session) info() workspacesdk.ReconnectingPTYSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conns := make([]workspacesdk.ReconnectingPTYConnection, 0, len(s.conns))
+	for _, conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	slices.SortFunc(conns, func(a, b workspacesdk.ReconnectingPTYConnection) int {
+		return a.ConnectedAt.Compare(b.ConnectedAt)
+	})
+	return workspacesdk.ReconnectingPTYSession{
+		ID:          s.id,
+		Command:     s.command,
+		Container:   s.container,
+		Height:      s.height,
+		Width:       s.width,
+		CreatedAt:   s.createdAt,
+		Connections: conns,
+	}
+}
+
+// readOnlyConn discards everything read from the connection, so that the
+// input and resizes of a read-only client never reach the pty.  Reads block
+// until the connection is closed.
+type readOnlyConn struct {
+	net.Conn
+}
+
+func (c readOnlyConn) Read(p []byte) (int, error) {
+	for {
+		_, err := c.Conn.Read(p)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+)
+
+// HandleListReconnectingPTYs lists the live reconnecting PTY sessions with the
+// clients attached to them.
+func (a *agent) HandleListReconnectingPTYs(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, workspacesdk.ListReconnectingPTYsResponse{
+		Sessions: a.reconnectingPTYServer.Sessions(),
+	})
+}
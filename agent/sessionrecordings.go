@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/agent/agentrecording"
+	"github.com/coder/coder/v2/agent/proto"
+)
+
+const (
+	// sessionRecordingMaxSize is the size after which a session recording
+	// is truncated, to avoid filling up the disk with a runaway process.
+	sessionRecordingMaxSize = 256 << 20
+	// sessionRecordingChunkSize is the size of the chunks that session
+	// recordings are uploaded in, it must be below the maximum dRPC
+	// message size.
+	sessionRecordingChunkSize = 1 << 20
+)
+
+// sessionRecording is a finished session recording waiting to be uploaded.
+type sessionRecording struct {
+	id             uuid.UUID
+	connectionID   uuid.UUID
+	connectionType proto.Connection_Type
+	path           string
+	startedAt      time.Time
+	endedAt        time.Time
+}
+
+// recordSession starts recording the session of the given connection if
+// the manifest requires it. The recording is queued for upload when the
+// returned recorder is closed.
+func (a *agent) recordSession(connectionID uuid.UUID, connectionType proto.Connection_Type, header agentrecording.Header) *agentrecording.Recorder {
+	manifest := a.manifest.Load()
+	if manifest == nil || !manifest.SessionRecording {
+		return nil
+	}
+	logger := a.logger.With(slog.F("connection_id", connectionID), slog.F("connection_type", connectionType))
+
+	dir := filepath.Join(a.tempDir, "coder-session-recordings")
+	err := a.filesystem.MkdirAll(dir, 0o700)
+	if err != nil {
+		logger.Error(a.hardCtx, "create session recordings directory failed, session will not be recorded", slog.Error(err))
+		return nil
+	}
+	f, err := afero.TempFile(a.filesystem, dir, "*.cast")
+	if err != nil {
+		logger.Error(a.hardCtx, "create session recording file failed, session will not be recorded", slog.Error(err))
+		return nil
+	}
+
+	recording := &sessionRecording{
+		id:             uuid.New(),
+		connectionID:   connectionID,
+		connectionType: connectionType,
+		path:           f.Name(),
+		startedAt:      time.Now(),
+	}
+	rec, err := agentrecording.New(&sessionRecordingFile{
+		File: f,
+		onClose: func() {
+			recording.endedAt = time.Now()
+			a.queueSessionRecording(recording)
+		},
+	}, header, agentrecording.Options{MaxSize: sessionRecordingMaxSize})
+	if err != nil {
+		logger.Error(a.hardCtx, "start session recording failed, session will not be recorded", slog.Error(err))
+		_ = a.filesystem.Remove(f.Name())
+		return nil
+	}
+	logger.Debug(a.hardCtx, "recording session", slog.F("path", recording.path))
+	return rec
+}
+
+// sessionRecordingFile queues the recording for upload once it is closed.
+type sessionRecordingFile struct {
+	afero.File
+	onClose func()
+}
+
+func (f *sessionRecordingFile) Close() error {
+	err := f.File.Close()
+	f.onClose()
+	return err
+}
+
+func (a *agent) queueSessionRecording(recording *sessionRecording) {
+	a.sessionRecordingsMu.Lock()
+	a.sessionRecordings = append(a.sessionRecordings, recording)
+	a.sessionRecordingsMu.Unlock()
+
+	select {
+	case a.sessionRecordingsUpdate <- struct{}{}:
+	default:
+	}
+}
+
+// uploadSessionRecordingsLoop uploads finished session recordings to coderd
+// and removes them from disk.
+func (a *agent) uploadSessionRecordingsLoop(ctx context.Context, aAPI proto.DRPCAgentClient25) error {
+	for {
+		for {
+			a.sessionRecordingsMu.Lock()
+			if len(a.sessionRecordings) == 0 {
+				a.sessionRecordingsMu.Unlock()
+				break
+			}
+			recording := a.sessionRecordings[0]
+			// Release lock while we upload, this is safe since we only
+			// append to the slice.
+			a.sessionRecordingsMu.Unlock()
+
+			logger := a.logger.With(slog.F("recording_id", recording.id), slog.F("connection_id", recording.connectionID))
+			err := a.uploadSessionRecording(ctx, aAPI, recording)
+			if errors.Is(err, os.ErrNotExist) {
+				logger.Error(ctx, "session recording file is missing, dropping recording", slog.F("path", recording.path))
+			} else if err != nil {
+				return xerrors.Errorf("upload session recording: %w", err)
+			} else {
+				logger.Debug(ctx, "uploaded session recording")
+				if err := a.filesystem.Remove(recording.path); err != nil {
+					logger.Warn(ctx, "remove uploaded session recording failed", slog.F("path", recording.path), slog.Error(err))
+				}
+			}
+
+			a.sessionRecordingsMu.Lock()
+			a.sessionRecordings[0] = nil // Release the pointer from the underlying array.
+			a.sessionRecordings = a.sessionRecordings[1:]
+			a.sessionRecordingsMu.Unlock()
+		}
+
+		select {
+		case <-a.sessionRecordingsUpdate:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// uploadSessionRecording uploads a recording in chunks. The upload always
+// starts from the beginning, so that it can be retried after an error.
+func (a *agent) uploadSessionRecording(ctx context.Context, aAPI proto.DRPCAgentClient25, recording *sessionRecording) error {
+	f, err := a.filesystem.Open(recording.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, sessionRecordingChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 || offset == 0 {
+			_, uploadErr := aAPI.UploadSessionRecording(ctx, &proto.UploadSessionRecordingRequest{
+				Id:             recording.id[:],
+				ConnectionId:   recording.connectionID[:],
+				ConnectionType: recording.connectionType,
+				StartedAt:      timestamppb.New(recording.startedAt),
+				EndedAt:        timestamppb.New(recording.endedAt),
+				Offset:         offset,
+				Data:           buf[:n],
+			})
+			if uploadErr != nil {
+				return uploadErr
+			}
+			offset += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read session recording: %w", err)
+		}
+	}
+}
@@ -47,6 +47,13 @@ func (r Root) Organization() File {
 	return File(filepath.Join(string(r), "organization"))
 }
 
+// ProxyRegion caches the workspace proxy region that was last selected by
+// latency, so that commands don't probe regions on every run.
+func (r Root) ProxyRegion() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "proxy_region"))
+}
+
 func (r Root) DotfilesURL() File {
 	r.mustNotEmpty()
 	return File(filepath.Join(string(r), "dotfilesurl"))
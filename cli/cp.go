@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/agent/agentfiles"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) cp() *serpent.Command {
+	var appearanceConfig codersdk.AppearanceConfig
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files and directories to and from a workspace",
+		Long: "Exactly one of the source and the destination must be in a workspace, " +
+			"written as <workspace>[.<agent>]:<path>. Paths in a workspace are relative to the home directory " +
+			"of the workspace user. Directories are copied recursively. Prefix local paths that contain a colon with ./.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "Copy a file into the home directory of a workspace",
+					Command:     "coder cp ./notes.txt my-workspace:",
+				},
+				Example{
+					Description: "Copy a directory from a workspace",
+					Command:     "coder cp my-workspace:repos/coder/site ./site",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			srcWorkspace, src := parseCopyArg(inv.Args[0])
+			dstWorkspace, dst := parseCopyArg(inv.Args[1])
+			if (srcWorkspace == "") == (dstWorkspace == "") {
+				return xerrors.New("exactly one of the source and the destination must be in a workspace, e.g. my-workspace:path")
+			}
+			workspaceName := srcWorkspace
+			if workspaceName == "" {
+				workspaceName = dstWorkspace
+			}
+
+			conn, err := r.dialWorkspaceAgent(ctx, inv, client, appearanceConfig, workspaceName)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			if srcWorkspace != "" {
+				return copyFromWorkspace(ctx, conn, src, dst)
+			}
+			return copyToWorkspace(ctx, conn, src, dst)
+		},
+	}
+	return cmd
+}
+
+// dialWorkspaceAgent waits for the agent of the workspace to connect and
+// dials it. The caller must close the returned connection.
+func (r *RootCmd) dialWorkspaceAgent(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, appearanceConfig codersdk.AppearanceConfig, workspaceName string) (*workspacesdk.AgentConn, error) {
+	_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, workspaceName)
+	if err != nil {
+		return nil, err
+	}
+	err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+		Fetch:   client.WorkspaceAgent,
+		Wait:    false,
+		DocsURL: appearanceConfig.DocsURL,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("await agent: %w", err)
+	}
+
+	opts := &workspacesdk.DialAgentOptions{}
+	if r.verbose {
+		opts.Logger = inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr)).Leveled(slog.LevelDebug)
+	}
+	if r.disableDirect {
+		_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+		opts.BlockEndpoints = true
+	}
+	return workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+}
+
+// parseCopyArg splits a `coder cp` argument into its workspace and path. The
+// workspace is empty for local paths.
+func parseCopyArg(arg string) (workspace string, p string) {
+	i := strings.Index(arg, ":")
+	if i <= 0 ||
+		strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "~") || filepath.IsAbs(arg) ||
+		// Drive letters on Windows, e.g. C:\Users.
+		(runtime.GOOS == "windows" && i == 1) {
+		return "", arg
+	}
+	return arg[:i], arg[i+1:]
+}
+
+// copyToWorkspace copies the local file or directory src to dst in the
+// workspace. Like cp, src is copied into dst if dst is an existing directory.
+func copyToWorkspace(ctx context.Context, conn *workspacesdk.AgentConn, localSrc, dst string) error {
+	src, err := filepath.Abs(localSrc)
+	if err != nil {
+		return xerrors.Errorf("resolve source: %w", err)
+	}
+	// Follow links to the source, the link itself is not copied.
+	resolved, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return xerrors.Errorf("stat source: %w", err)
+	}
+	srcInfo, err := os.Stat(resolved)
+	if err != nil {
+		return xerrors.Errorf("stat source: %w", err)
+	}
+	if dst == "" {
+		dst = "~"
+	}
+
+	var dir, name string
+	dstInfo, err := conn.StatFile(ctx, dst)
+	cerr, ok := codersdk.AsError(err)
+	notFound := ok && cerr.StatusCode() == http.StatusNotFound
+	switch {
+	case err == nil && dstInfo.IsDir:
+		dir, name = dstInfo.AbsolutePathString, filepath.Base(src)
+	case err == nil && srcInfo.IsDir():
+		return xerrors.Errorf("cannot overwrite non-directory %q with directory %q", dst, src)
+	case err == nil:
+		dir, name = path.Dir(dstInfo.AbsolutePathString), path.Base(dstInfo.AbsolutePathString)
+	case notFound && strings.HasSuffix(dst, "/"):
+		dir, name = dst, filepath.Base(src)
+	case notFound:
+		dir, name = path.Dir(dst), path.Base(dst)
+	default:
+		return xerrors.Errorf("stat destination: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(agentfiles.WriteTar(ctx, pw, resolved, name))
+	}()
+	err = conn.UploadArchive(ctx, dir, pr)
+	_ = pr.Close()
+	if err != nil {
+		return xerrors.Errorf("copy to workspace: %w", err)
+	}
+	return nil
+}
+
+// copyFromWorkspace copies the file or directory src in the workspace to the
+// local dst. Like cp, src is copied into dst if dst is an existing directory.
+func copyFromWorkspace(ctx context.Context, conn *workspacesdk.AgentConn, src, dst string) error {
+	if src == "" {
+		return xerrors.New("source path in the workspace is required")
+	}
+	srcInfo, err := conn.StatFile(ctx, src)
+	if err != nil {
+		return xerrors.Errorf("stat source: %w", err)
+	}
+	if dst == "" {
+		dst = "."
+	}
+
+	var dir, name string
+	dstInfo, err := os.Stat(dst)
+	switch {
+	case err == nil && dstInfo.IsDir():
+		dir, name = dst, srcInfo.Name
+	case err == nil && srcInfo.IsDir:
+		return xerrors.Errorf("cannot overwrite non-directory %q with directory %q", dst, src)
+	case err == nil:
+		dir, name = filepath.Dir(dst), filepath.Base(dst)
+	case os.IsNotExist(err) && os.IsPathSeparator(dst[len(dst)-1]):
+		dir, name = dst, srcInfo.Name
+	case os.IsNotExist(err):
+		dir, name = filepath.Dir(dst), filepath.Base(dst)
+	default:
+		return xerrors.Errorf("stat destination: %w", err)
+	}
+
+	archive, err := conn.DownloadArchive(ctx, srcInfo.AbsolutePathString)
+	if err != nil {
+		return xerrors.Errorf("copy from workspace: %w", err)
+	}
+	defer archive.Close()
+	err = agentfiles.ExtractTar(ctx, archive, dir, name)
+	if err != nil {
+		return xerrors.Errorf("copy from workspace: %w", err)
+	}
+	return nil
+}
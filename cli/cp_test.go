@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	// The agent runs in this process, so "remote" paths are local paths
+	// too. Absolute paths are used as the home directory is shared.
+	t.Run("File", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		dir := t.TempDir()
+		src := filepath.Join(dir, "hello.txt")
+		require.NoError(t, os.WriteFile(src, []byte("hello"), 0o600))
+
+		// Copy to a new name.
+		inv, root := clitest.New(t, "cp", src, workspace.Name+":"+filepath.Join(dir, "world.txt"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		b, err := os.ReadFile(filepath.Join(dir, "world.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(b))
+
+		// Copy into an existing directory.
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "into"), 0o755))
+		inv, root = clitest.New(t, "cp", workspace.Name+":"+src, filepath.Join(dir, "into"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		b, err = os.ReadFile(filepath.Join(dir, "into", "hello.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(b))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		dir := t.TempDir()
+		src := filepath.Join(dir, "repo")
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "main.go"), []byte("package main"), 0o600))
+
+		inv, root := clitest.New(t, "cp", src, workspace.Name+":"+filepath.Join(dir, "uploaded"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		b, err := os.ReadFile(filepath.Join(dir, "uploaded", "sub", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(b))
+
+		inv, root = clitest.New(t, "cp", workspace.Name+":"+src, filepath.Join(dir, "downloaded"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		b, err = os.ReadFile(filepath.Join(dir, "downloaded", "sub", "main.go"))
+		require.NoError(t, err)
+		require.Equal(t, "package main", string(b))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "cp", workspace.Name+":"+filepath.Join(t.TempDir(), "missing"), t.TempDir())
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "stat source")
+	})
+
+	t.Run("BothLocal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "cp", t.TempDir(), t.TempDir())
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "exactly one of the source and the destination")
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mattn/go-isatty"
@@ -66,20 +68,121 @@ func (r *RootCmd) rptyCommand() *serpent.Command {
 				Default:       "",
 				Value:         serpent.StringOf(&args.ReconnectID),
 			},
+			{
+				Name:        "read-only",
+				Description: "Watch the existing session given by --reconnect without sending input to it.",
+				Flag:        "read-only",
+				Value:       serpent.BoolOf(&args.ReadOnly),
+			},
 		},
 		Short: "Establish an RPTY session with a workspace/agent.",
 		Use:   "rpty",
+		Children: []*serpent.Command{
+			r.rptyListCommand(),
+		},
 	}
 
 	return cmd
 }
 
+type rptySessionRow struct {
+	ID          string    `json:"id" table:"id"`
+	Command     string    `json:"command" table:"command"`
+	Container   string    `json:"container" table:"container"`
+	Size        string    `json:"size" table:"size"`
+	CreatedAt   time.Time `json:"created_at" table:"created at,default_sort"`
+	Connections string    `json:"connections" table:"connections"`
+}
+
+func (r *RootCmd) rptyListCommand() *serpent.Command {
+	var (
+		appearanceConfig codersdk.AppearanceConfig
+		formatter        = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(
+				cliui.TableFormat([]rptySessionRow{}, []string{"id", "command", "size", "created at", "connections"}),
+				func(data any) (any, error) {
+					sessions, ok := data.([]workspacesdk.ReconnectingPTYSession)
+					if !ok {
+						return nil, xerrors.Errorf("expected type %T, got %T", []workspacesdk.ReconnectingPTYSession{}, data)
+					}
+					rows := make([]rptySessionRow, 0, len(sessions))
+					for _, sess := range sessions {
+						command := sess.Command
+						if command == "" {
+							command = "(shell)"
+						}
+						readOnly := 0
+						for _, conn := range sess.Connections {
+							if conn.ReadOnly {
+								readOnly++
+							}
+						}
+						rows = append(rows, rptySessionRow{
+							ID:          sess.ID.String(),
+							Command:     command,
+							Container:   sess.Container,
+							Size:        fmt.Sprintf("%dx%d", sess.Width, sess.Height),
+							CreatedAt:   sess.CreatedAt,
+							Connections: fmt.Sprintf("%d (%d read-only)", len(sess.Connections), readOnly),
+						})
+					}
+					return rows, nil
+				},
+			),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "list <workspace>",
+		Short: "List the live RPTY sessions of a workspace/agent.",
+		Long: "Sessions can be watched with \"coder exp rpty <workspace> --reconnect <id> --read-only\", " +
+			"or taken over by attaching without --read-only.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			conn, err := r.dialWorkspaceAgent(ctx, inv, client, appearanceConfig, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			res, err := conn.ListReconnectingPTYs(ctx)
+			if err != nil {
+				return xerrors.Errorf("list rpty sessions: %w", err)
+			}
+
+			out, err := formatter.Format(ctx, res.Sessions)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	// -c is taken by the --container flag of the parent command.
+	for i := range cmd.Options {
+		if cmd.Options[i].FlagShorthand == "c" {
+			cmd.Options[i].FlagShorthand = ""
+		}
+	}
+	return cmd
+}
+
 type handleRPTYArgs struct {
 	Command        []string
 	Container      string
 	ContainerUser  string
 	NamedWorkspace string
 	ReconnectID    string
+	ReadOnly       bool
 }
 
 func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPTYArgs) error {
@@ -93,6 +196,8 @@ func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPT
 			return xerrors.Errorf("invalid reconnect ID: %w", err)
 		}
 		reconnectID = rid
+	} else if args.ReadOnly {
+		return xerrors.New("--read-only requires the ID of an existing session to be set with --reconnect")
 	} else {
 		reconnectID = uuid.New()
 	}
@@ -130,9 +235,10 @@ func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPT
 		}
 	}
 
-	// Set stdin to raw mode so that control characters work.
+	// Set stdin to raw mode so that control characters work. Read-only
+	// sessions leave it as is, so that ^C exits.
 	stdinFile, validIn := inv.Stdin.(*os.File)
-	if validIn && isatty.IsTerminal(stdinFile.Fd()) {
+	if !args.ReadOnly && validIn && isatty.IsTerminal(stdinFile.Fd()) {
 		inState, err := pty.MakeInputRaw(stdinFile.Fd())
 		if err != nil {
 			return xerrors.Errorf("failed to set input terminal to raw mode: %w", err)
@@ -160,6 +266,7 @@ func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPT
 		Width:         termWidth,
 		Height:        termHeight,
 		BackendType:   backend,
+		ReadOnly:      args.ReadOnly,
 	})
 	if err != nil {
 		return xerrors.Errorf("open reconnecting PTY: %w", err)
@@ -172,6 +279,11 @@ func handleRPTY(inv *serpent.Invocation, client *codersdk.Client, args handleRPT
 	})
 	defer closeUsage()
 
+	if args.ReadOnly {
+		_, _ = io.Copy(inv.Stdout, conn)
+		return nil
+	}
+
 	br := bufio.NewScanner(inv.Stdin)
 	// Split on bytes, otherwise you have to send a newline to flush the buffer.
 	br.Split(bufio.ScanBytes)
@@ -1,7 +1,9 @@
 package cli_test
 
 import (
+	"encoding/json"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -12,6 +14,7 @@ import (
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 
@@ -79,6 +82,77 @@ func TestExpRpty(t *testing.T) {
 		require.ErrorContains(t, err, "not found")
 	})
 
+	t.Run("ListAndWatch", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_ = agenttest.New(t, client.URL, agentToken)
+		resources := coderdtest.NewWorkspaceAgentWaiter(t, client, workspace.ID).Wait()
+
+		id := uuid.New()
+		conn, err := workspacesdk.New(client).AgentReconnectingPTY(ctx, workspacesdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:     resources[0].Agents[0].ID,
+			Reconnect:   id,
+			Width:       80,
+			Height:      24,
+			Command:     "bash --norc",
+			BackendType: "buffered",
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		tr := testutil.NewTerminalReader(t, conn)
+		require.NoError(t, tr.ReadUntil(ctx, func(line string) bool {
+			return strings.Contains(line, "$ ") || strings.Contains(line, "# ")
+		}), "find prompt")
+
+		inv, root := clitest.New(t, "exp", "rpty", "list", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		clitest.Run(t, inv.WithContext(ctx))
+		pty.ExpectMatch(id.String())
+		pty.ExpectMatch("bash --norc")
+
+		inv, root = clitest.New(t, "exp", "rpty", workspace.Name, "--reconnect", id.String(), "--read-only")
+		clitest.SetupConfig(t, client, root)
+		pty = ptytest.New(t).Attach(inv)
+		cmdDone := tGo(t, func() {
+			err := inv.WithContext(ctx).Run()
+			assert.NoError(t, err)
+		})
+
+		randStr := uuid.NewString()
+		data, err := json.Marshal(workspacesdk.ReconnectingPTYRequest{
+			Data: "echo " + randStr + "\r",
+		})
+		require.NoError(t, err)
+		_, err = conn.Write(data)
+		require.NoError(t, err)
+		pty.ExpectMatch(randStr)
+
+		// Ending the session ends the read-only connection.
+		data, err = json.Marshal(workspacesdk.ReconnectingPTYRequest{
+			Data: "exit\r",
+		})
+		require.NoError(t, err)
+		_, err = conn.Write(data)
+		require.NoError(t, err)
+		<-cmdDone
+	})
+
+	t.Run("ReadOnlyRequiresReconnect", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, _ := setupWorkspaceForAgent(t)
+		inv, root := clitest.New(t, "exp", "rpty", workspace.Name, "--read-only")
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "--reconnect")
+	})
+
 	t.Run("Container", func(t *testing.T) {
 		t.Parallel()
 		// Skip this test on non-Linux platforms since it requires Docker
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) expose() *serpent.Command {
+	var (
+		level    string
+		protocol string
+		ttl      time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "expose <workspace> <port>",
+		Short:       "Share a workspace port through a URL until the command exits",
+		Long: "The port is shared through the wildcard app hostname of the deployment. " +
+			"The share is revoked when the command exits or the TTL expires, whichever comes first.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "Share port 8080 with any signed-in user for two hours",
+					Command:     "coder expose my-workspace 8080",
+				},
+				Example{
+					Description: "Share port 3000 of the agent named main publicly, for example to receive webhooks",
+					Command:     "coder expose my-workspace.main 3000 --level public --ttl 30m",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			parsedPort, err := strconv.ParseUint(inv.Args[1], 10, 16)
+			if err != nil {
+				return xerrors.Errorf("invalid port %q: %w", inv.Args[1], err)
+			}
+			port := int32(parsedPort) // #nosec G115 - Parsed as a 16-bit integer.
+			if ttl <= 0 {
+				return xerrors.New("--ttl must be greater than zero")
+			}
+
+			appHost, err := client.AppHost(ctx)
+			if err != nil {
+				return xerrors.Errorf("get app host: %w", err)
+			}
+			if appHost.Host == "" {
+				return xerrors.New("the deployment has no wildcard access URL, which is required to share ports")
+			}
+
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, inv.Args[0])
+			if err != nil {
+				return err
+			}
+
+			// Don't clobber an existing share, as revoking ours on exit
+			// would remove it.
+			shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get port shares: %w", err)
+			}
+			for _, share := range shares.Shares {
+				if share.AgentName == workspaceAgent.Name && share.Port == port {
+					return xerrors.Errorf("port %d of agent %q is already shared with %s access", port, workspaceAgent.Name, share.ShareLevel)
+				}
+			}
+
+			expiresAt := time.Now().Add(ttl)
+			share, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+				AgentName:  workspaceAgent.Name,
+				Port:       port,
+				ShareLevel: codersdk.WorkspaceAgentPortShareLevel(level),
+				Protocol:   codersdk.WorkspaceAgentPortShareProtocol(protocol),
+				ExpiresAt:  &expiresAt,
+			})
+			if err != nil {
+				return xerrors.Errorf("share port: %w", err)
+			}
+			defer func() {
+				// The command context may already be canceled, but the share
+				// should still be revoked.
+				revokeCtx, revokeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer revokeCancel()
+				err := client.DeleteWorkspaceAgentPortShare(revokeCtx, workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+					AgentName: share.AgentName,
+					Port:      share.Port,
+				})
+				var sdkErr *codersdk.Error
+				if err != nil && !(xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound) {
+					cliui.Warnf(inv.Stderr, "Failed to revoke the share of port %d: %s", share.Port, err)
+					return
+				}
+				_, _ = fmt.Fprintf(inv.Stderr, "Stopped sharing port %d.\n", share.Port)
+			}()
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Sharing port %d of %s.%s with %s access until %s. Press Ctrl+C to stop.\n",
+				share.Port, workspace.Name, workspaceAgent.Name,
+				pretty.Sprint(cliui.DefaultStyles.Keyword, string(share.ShareLevel)),
+				expiresAt.Format(time.Stamp),
+			)
+			_, _ = fmt.Fprintln(inv.Stdout, portShareURL(client.URL, appHost.Host, workspace, workspaceAgent, share))
+
+			notifyCtx, notifyCancel := inv.SignalNotifyContext(ctx, StopSignals...)
+			defer notifyCancel()
+
+			timer := time.NewTimer(ttl)
+			defer timer.Stop()
+			select {
+			case <-notifyCtx.Done():
+				// Only report cancellation of the parent context, signals
+				// are the expected way to stop sharing.
+				return ctx.Err()
+			case <-timer.C:
+				_, _ = fmt.Fprintln(inv.Stderr, "The share expired.")
+				return nil
+			}
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "level",
+			Env:         "CODER_EXPOSE_LEVEL",
+			Description: "Who can access the port. The template may restrict the allowed levels.",
+			Default:     string(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+			Value: serpent.EnumOf(&level,
+				string(codersdk.WorkspaceAgentPortShareLevelAuthenticated),
+				string(codersdk.WorkspaceAgentPortShareLevelPublic),
+			),
+		},
+		{
+			Flag:        "protocol",
+			Env:         "CODER_EXPOSE_PROTOCOL",
+			Description: "The protocol the port is served with in the workspace.",
+			Default:     string(codersdk.WorkspaceAgentPortShareProtocolHTTP),
+			Value: serpent.EnumOf(&protocol,
+				string(codersdk.WorkspaceAgentPortShareProtocolHTTP),
+				string(codersdk.WorkspaceAgentPortShareProtocolHTTPS),
+			),
+		},
+		{
+			Flag:        "ttl",
+			Env:         "CODER_EXPOSE_TTL",
+			Description: "How long to share the port for.",
+			Default:     "2h",
+			Value:       serpent.DurationOf(&ttl),
+		},
+	}
+	return cmd
+}
+
+// portShareURL returns the URL of a shared port, which matches the one the
+// dashboard links to.
+func portShareURL(accessURL *url.URL, appHost string, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent, share codersdk.WorkspaceAgentPortShare) string {
+	appSlugOrPort := strconv.Itoa(int(share.Port))
+	if share.Protocol == codersdk.WorkspaceAgentPortShareProtocolHTTPS {
+		appSlugOrPort += "s"
+	}
+	subdomain := appurl.ApplicationURL{
+		AppSlugOrPort: appSlugOrPort,
+		AgentName:     agent.Name,
+		WorkspaceName: workspace.Name,
+		Username:      workspace.OwnerName,
+	}.String()
+	u := url.URL{
+		Scheme: accessURL.Scheme,
+		Host:   strings.Replace(appHost, "*", subdomain, 1),
+		Path:   "/",
+	}
+	return u.String()
+}
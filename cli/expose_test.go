@@ -0,0 +1,120 @@
+package cli_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExpose(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, database.WorkspaceTable) {
+		t.Helper()
+		client, store := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			AppHostname: "*.apps.coder.test",
+		})
+		first := coderdtest.CreateFirstUser(t, client)
+		member, user := coderdtest.CreateAnotherUserMutators(t, client, first.OrganizationID, nil, func(r *codersdk.CreateUserRequestWithOrgs) {
+			r.Username = "myuser"
+		})
+		r := dbfake.WorkspaceBuild(t, store, database.WorkspaceTable{
+			Name:           "myworkspace",
+			OrganizationID: first.OrganizationID,
+			OwnerID:        user.ID,
+		}).WithAgent().Do()
+		return member, r.Workspace
+	}
+
+	t.Run("RevokeOnExit", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace := setup(t)
+		inv, root := clitest.New(t, "expose", workspace.Name, "8080", "--level", "public")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cmdCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		errC := make(chan error, 1)
+		go func() {
+			errC <- inv.WithContext(cmdCtx).Run()
+		}()
+		pty.ExpectMatchContext(ctx, "http://8080--dev--myworkspace--myuser.apps.coder.test:")
+
+		shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares.Shares, 1)
+		share := shares.Shares[0]
+		assert.EqualValues(t, 8080, share.Port)
+		assert.Equal(t, codersdk.WorkspaceAgentPortShareLevelPublic, share.ShareLevel)
+		require.NotNil(t, share.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), *share.ExpiresAt, time.Minute)
+
+		cancel()
+		require.ErrorIs(t, testutil.TryReceive(ctx, t, errC), context.Canceled)
+
+		shares, err = client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+	})
+
+	t.Run("RevokeOnExpiry", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace := setup(t)
+		inv, root := clitest.New(t, "expose", workspace.Name, "8080", "--protocol", "https", "--ttl", "2s")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		errC := make(chan error, 1)
+		go func() {
+			errC <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatchContext(ctx, "http://8080s--dev--myworkspace--myuser.apps.coder.test:")
+		pty.ExpectMatchContext(ctx, "The share expired.")
+		require.NoError(t, testutil.TryReceive(ctx, t, errC))
+
+		shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares.Shares)
+	})
+
+	t.Run("AlreadyShared", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  "dev",
+			Port:       8080,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+			Protocol:   codersdk.WorkspaceAgentPortShareProtocolHTTP,
+		})
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "expose", workspace.Name, "8080")
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "already shared")
+
+		// The existing share is left alone.
+		shares, err := client.GetWorkspaceAgentPortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares.Shares, 1)
+		require.Nil(t, shares.Shares[0].ExpiresAt)
+	})
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) kill() *serpent.Command {
+	var (
+		appearanceConfig codersdk.AppearanceConfig
+		signal           string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "kill <workspace> <pid>",
+		Short:       "Send a signal to a process running in a workspace",
+		Long: "Use `coder ps` to find the PID of a process. Only KILL is supported on Windows.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "Terminate a process",
+					Command:     "coder kill my-workspace 4242",
+				},
+				Example{
+					Description: "Kill a process that doesn't terminate",
+					Command:     "coder kill my-workspace 4242 --signal KILL",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			pid, err := strconv.ParseInt(inv.Args[1], 10, 32)
+			if err != nil || pid <= 0 {
+				return xerrors.Errorf("invalid pid %q", inv.Args[1])
+			}
+
+			conn, err := r.dialWorkspaceAgent(ctx, inv, client, appearanceConfig, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			err = conn.SignalProcess(ctx, workspacesdk.SignalProcessRequest{
+				PID:    int32(pid),
+				Signal: signal,
+			})
+			if err != nil {
+				return xerrors.Errorf("signal process: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Sent %s to process %d\n", cliui.Code(signal), pid)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "signal",
+			FlagShorthand: "s",
+			Description:   "The signal to send, one of HUP, INT, QUIT, KILL or TERM.",
+			Default:       "TERM",
+			Value:         serpent.EnumOf(&signal, "HUP", "INT", "QUIT", "KILL", "TERM"),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,58 @@
+package cli_test
+
+import (
+	"os/exec"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestKill(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Signals other than KILL are not supported on Windows")
+	}
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	_ = agenttest.New(t, client.URL, agentToken)
+	_ = coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	// The agent runs in this process, so it can signal processes started by
+	// the test.
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		cmd := exec.Command("sleep", "3600")
+		require.NoError(t, cmd.Start())
+		pid := strconv.Itoa(cmd.Process.Pid)
+
+		inv, root := clitest.New(t, "kill", workspace.Name, pid)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.WithContext(ctx).Run())
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, cmd.Wait(), &exitErr)
+
+		inv, root = clitest.New(t, "kill", workspace.Name, pid, "--signal", "KILL")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "process not found")
+	})
+
+	t.Run("InvalidPID", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "kill", workspace.Name, "nope")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, `invalid pid "nope"`)
+	})
+}
@@ -268,6 +268,7 @@ func (r *RootCmd) openVSCode() *serpent.Command {
 func (r *RootCmd) openApp() *serpent.Command {
 	var (
 		regionArg     string
+		proxyArg      string
 		testOpenError bool
 	)
 
@@ -325,23 +326,14 @@ func (r *RootCmd) openApp() *serpent.Command {
 
 			// To build the app URL, we need to know the wildcard hostname
 			// and path app URL for the region.
-			regions, err := client.Regions(ctx)
-			if err != nil {
-				return xerrors.Errorf("failed to fetch regions: %w", err)
+			if regionArg != "" {
+				proxyArg = regionArg
 			}
-			var region codersdk.Region
-			preferredIdx := slices.IndexFunc(regions, func(r codersdk.Region) bool {
-				return r.Name == regionArg
-			})
-			if preferredIdx == -1 {
-				allRegions := make([]string, len(regions))
-				for i, r := range regions {
-					allRegions[i] = r.Name
-				}
-				cliui.Errorf(inv.Stderr, "Preferred region %q not found!\nAvailable regions: %v", regionArg, allRegions)
-				return xerrors.Errorf("region not found")
+			region, err := selectRegion(ctx, client, proxyArg)
+			if err != nil {
+				cliui.Errorf(inv.Stderr, "Failed to select region: %s", err)
+				return err
 			}
-			region = regions[preferredIdx]
 
 			baseURL, err := url.Parse(region.PathAppURL)
 			if err != nil {
@@ -376,13 +368,13 @@ func (r *RootCmd) openApp() *serpent.Command {
 
 	cmd.Options = serpent.OptionSet{
 		{
-			Flag: "region",
-			Env:  "CODER_OPEN_APP_REGION",
-			Description: fmt.Sprintf("Region to use when opening the app." +
-				" By default, the app will be opened using the main Coder deployment (a.k.a. \"primary\")."),
-			Value:   serpent.StringOf(&regionArg),
-			Default: "primary",
+			Flag:        "region",
+			Env:         "CODER_OPEN_APP_REGION",
+			Description: "Region to use when opening the app.",
+			UseInstead:  []serpent.Option{{Flag: "proxy"}},
+			Value:       serpent.StringOf(&regionArg),
 		},
+		proxyOption(&proxyArg),
 		{
 			Flag:        "test.open-error",
 			Description: "Don't run the open command.",
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/netip"
@@ -11,15 +12,18 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/google/uuid"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/sloghuman"
 
 	"github.com/coder/coder/v2/agent/agentssh"
-	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/workspacesdk"
 	"github.com/coder/serpent"
@@ -37,6 +41,10 @@ func (r *RootCmd) portForward() *serpent.Command {
 	var (
 		tcpForwards      []string // <port>:<port>
 		udpForwards      []string // <port>:<port>
+		unixForwards     []string // <socket|port>:<socket|port>
+		profilePath      string
+		proxyArg         string
+		udpIdleTimeout   time.Duration
 		disableAutostart bool
 		appearanceConfig codersdk.AppearanceConfig
 	)
@@ -66,9 +74,21 @@ func (r *RootCmd) portForward() *serpent.Command {
 				Description: "Port forward specifying the local address to bind to",
 				Command:     "coder port-forward <workspace> --tcp 1.2.3.4:8080:8080",
 			},
+			Example{
+				Description: "Forward the Docker socket in the workspace to a local Unix socket",
+				Command:     "coder port-forward <workspace> --unix ./docker.sock:/var/run/docker.sock",
+			},
+			Example{
+				Description: "Forward a Postgres Unix socket in the workspace to local TCP port 5432",
+				Command:     "coder port-forward <workspace> --unix 5432:/var/run/postgresql/.s.PGSQL.5432",
+			},
+			Example{
+				Description: "Forward ports from several workspaces listed in a profile",
+				Command:     "coder port-forward --profile dev.yaml",
+			},
 		),
 		Middleware: serpent.Chain(
-			serpent.RequireNArgs(1),
+			serpent.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 			initAppearance(client, &appearanceConfig),
 		),
@@ -76,35 +96,37 @@ func (r *RootCmd) portForward() *serpent.Command {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
-			specs, err := parsePortForwards(tcpForwards, udpForwards)
-			if err != nil {
-				return xerrors.Errorf("parse port-forward specs: %w", err)
-			}
-			if len(specs) == 0 {
-				return xerrors.New("no port-forwards requested")
-			}
-
-			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, inv.Args[0])
-			if err != nil {
-				return err
-			}
-			if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
-				return xerrors.New("workspace must be in start transition to port-forward")
-			}
-			if workspace.LatestBuild.Job.CompletedAt == nil {
-				err = cliui.WorkspaceBuild(ctx, inv.Stderr, client, workspace.LatestBuild.ID)
+			var entries []portForwardProfileEntry
+			if profilePath != "" {
+				if len(inv.Args) > 0 || len(tcpForwards) > 0 || len(udpForwards) > 0 || len(unixForwards) > 0 {
+					return xerrors.New("a workspace and --tcp, --udp or --unix can't be used with --profile, add them to the profile instead")
+				}
+				profile, err := readPortForwardProfile(profilePath)
 				if err != nil {
 					return err
 				}
+				entries = profile.Forwards
+			} else {
+				if len(inv.Args) != 1 {
+					return xerrors.New("expected a workspace, or a profile with --profile")
+				}
+				specs, err := parsePortForwards(tcpForwards, udpForwards, unixForwards)
+				if err != nil {
+					return xerrors.Errorf("parse port-forward specs: %w", err)
+				}
+				if len(specs) == 0 {
+					return xerrors.New("no port-forwards requested")
+				}
+				entries = []portForwardProfileEntry{{Workspace: inv.Args[0], specs: specs}}
 			}
 
-			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
-				Fetch:   client.WorkspaceAgent,
-				Wait:    false,
-				DocsURL: appearanceConfig.DocsURL,
-			})
-			if err != nil {
-				return xerrors.Errorf("await agent: %w", err)
+			targets := make([]*portForwardTarget, 0, len(entries))
+			for _, entry := range entries {
+				target, err := resolvePortForwardTarget(ctx, inv, client, entry, !disableAutostart, appearanceConfig.DocsURL)
+				if err != nil {
+					return err
+				}
+				targets = append(targets, target)
 			}
 
 			opts := &workspacesdk.DialAgentOptions{}
@@ -121,16 +143,46 @@ func (r *RootCmd) portForward() *serpent.Command {
 			if !r.disableNetworkTelemetry {
 				opts.EnableTelemetry = true
 			}
-			conn, err := workspacesdk.New(client).DialAgent(ctx, workspaceAgent.ID, opts)
+			region, err := selectRegionOrPrimary(ctx, inv, client, r.createConfig().ProxyRegion(), proxyArg)
 			if err != nil {
 				return err
 			}
-			defer conn.Close()
+			logger.Debug(ctx, "selected proxy region", slog.F("region", region.Name))
+			opts.PreferredProxy = preferredDERPProxy(proxyArg, region)
+
+			// A profile may forward to several workspaces, so a single
+			// tailnet connection is shared by all of their agents.
+			var multiConn *workspacesdk.MultiAgentConn
+			if profilePath != "" {
+				var err error
+				multiConn, err = workspacesdk.New(client).DialAgents(ctx, opts)
+				if err != nil {
+					return err
+				}
+				defer multiConn.Close()
+			}
+			for _, target := range targets {
+				var (
+					conn *workspacesdk.AgentConn
+					err  error
+				)
+				if multiConn != nil {
+					conn, err = multiConn.AgentConn(ctx, target.agent.ID)
+				} else {
+					conn, err = workspacesdk.New(client).DialAgent(ctx, target.agent.ID, opts)
+				}
+				if err != nil {
+					return xerrors.Errorf("connect to %s: %w", target.name, err)
+				}
+				target.dialer = &workspaceDialer{}
+				target.dialer.setConn(target.agent.ID, conn)
+				defer target.dialer.Close()
+			}
 
 			// Start all listeners.
 			var (
 				wg                = new(sync.WaitGroup)
-				listeners         = make([]net.Listener, 0, len(specs)*2)
+				listeners         = make([]net.Listener, 0, len(targets)*2)
 				closeAllListeners = func() {
 					logger.Debug(ctx, "closing all listeners")
 					for _, l := range listeners {
@@ -143,28 +195,33 @@ func (r *RootCmd) portForward() *serpent.Command {
 			)
 			defer closeAllListeners()
 
-			for _, spec := range specs {
-				if spec.listenHost == noAddr {
-					// first, opportunistically try to listen on IPv6
-					spec6 := spec
-					spec6.listenHost = ipv6Loopback
-					l6, err6 := listenAndPortForward(ctx, inv, conn, wg, spec6, logger)
-					if err6 != nil {
-						logger.Info(ctx, "failed to opportunistically listen on IPv6", slog.F("spec", spec), slog.Error(err6))
-					} else {
-						listeners = append(listeners, l6)
+			for _, target := range targets {
+				for _, spec := range target.specs {
+					if spec.listenSocket == "" && spec.listenHost == noAddr {
+						// first, opportunistically try to listen on IPv6
+						spec6 := spec
+						spec6.listenHost = ipv6Loopback
+						l6, err6 := listenAndPortForward(ctx, inv, target.dialer, wg, spec6, udpIdleTimeout, logger)
+						if err6 != nil {
+							logger.Info(ctx, "failed to opportunistically listen on IPv6", slog.F("spec", spec), slog.Error(err6))
+						} else {
+							listeners = append(listeners, l6)
+						}
+						spec.listenHost = ipv4Loopback
 					}
-					spec.listenHost = ipv4Loopback
-				}
-				l, err := listenAndPortForward(ctx, inv, conn, wg, spec, logger)
-				if err != nil {
-					logger.Error(ctx, "failed to listen", slog.F("spec", spec), slog.Error(err))
-					return err
+					l, err := listenAndPortForward(ctx, inv, target.dialer, wg, spec, udpIdleTimeout, logger)
+					if err != nil {
+						logger.Error(ctx, "failed to listen", slog.F("spec", spec), slog.Error(err))
+						return err
+					}
+					listeners = append(listeners, l)
 				}
-				listeners = append(listeners, l)
 			}
 
-			stopUpdating := client.UpdateWorkspaceUsageContext(ctx, workspace.ID)
+			stopUpdating := make([]func(), 0, len(targets))
+			for _, target := range targets {
+				stopUpdating = append(stopUpdating, client.UpdateWorkspaceUsageContext(ctx, target.workspace.ID))
+			}
 
 			// Wait for the context to be canceled or for a signal and close
 			// all listeners.
@@ -186,11 +243,24 @@ func (r *RootCmd) portForward() *serpent.Command {
 				}
 
 				cancel()
-				stopUpdating()
+				for _, stop := range stopUpdating {
+					stop()
+				}
 				closeAllListeners()
 			}()
 
-			conn.AwaitReachable(ctx)
+			if multiConn != nil {
+				// Follow the workspaces in the profile so that forwards
+				// resume when they are restarted.
+				for _, target := range targets {
+					wg.Add(1)
+					go func(target *portForwardTarget) {
+						defer wg.Done()
+						target.watch(ctx, inv, client, multiConn, logger)
+					}(target)
+				}
+			}
+
 			logger.Debug(ctx, "read to accept connections to forward")
 			_, _ = fmt.Fprintln(inv.Stderr, "Ready!")
 			wg.Wait()
@@ -212,7 +282,27 @@ func (r *RootCmd) portForward() *serpent.Command {
 			Description: "Forward UDP port(s) from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols.",
 			Value:       serpent.StringArrayOf(&udpForwards),
 		},
+		{
+			Flag:        "udp-idle-timeout",
+			Env:         "CODER_PORT_FORWARD_UDP_IDLE_TIMEOUT",
+			Description: "Close forwarded UDP sessions after no datagrams have been sent in either direction for this long.",
+			Default:     "5m",
+			Value:       serpent.DurationOf(&udpIdleTimeout),
+		},
+		{
+			Flag:        "unix",
+			Env:         "CODER_PORT_FORWARD_UNIX",
+			Description: "Forward Unix socket(s) from the workspace to the local machine, in the form <local>:<remote>. Either side may be a TCP port instead of a socket path, to forward between the two.",
+			Value:       serpent.StringArrayOf(&unixForwards),
+		},
+		{
+			Flag:        "profile",
+			Env:         "CODER_PORT_FORWARD_PROFILE",
+			Description: "Path to a YAML file listing forwards to one or more workspaces, which are all opened over a single connection. Forwards resume when a workspace is restarted.",
+			Value:       serpent.StringOf(&profilePath),
+		},
 		sshDisableAutostartOption(serpent.BoolOf(&disableAutostart)),
+		proxyOption(&proxyArg),
 	}
 
 	return cmd
@@ -221,27 +311,30 @@ func (r *RootCmd) portForward() *serpent.Command {
 func listenAndPortForward(
 	ctx context.Context,
 	inv *serpent.Invocation,
-	conn *workspacesdk.AgentConn,
+	dialer *workspaceDialer,
 	wg *sync.WaitGroup,
 	spec portForwardSpec,
+	udpIdleTimeout time.Duration,
 	logger slog.Logger,
 ) (net.Listener, error) {
+	listenNetwork, listenAddress := spec.listenAddress()
+	dialNetwork, dialAddress := spec.dialAddress()
 	logger = logger.With(
-		slog.F("network", spec.network),
-		slog.F("listen_host", spec.listenHost),
-		slog.F("listen_port", spec.listenPort),
+		slog.F("network", listenNetwork),
+		slog.F("listen_address", listenAddress),
+		slog.F("dial_network", dialNetwork),
+		slog.F("dial_address", dialAddress),
 	)
-	listenAddress := netip.AddrPortFrom(spec.listenHost, spec.listenPort)
-	dialAddress := fmt.Sprintf("127.0.0.1:%d", spec.dialPort)
 	_, _ = fmt.Fprintf(inv.Stderr, "Forwarding '%s://%s' locally to '%s://%s' in the workspace\n",
-		spec.network, listenAddress, spec.network, dialAddress)
+		listenNetwork, listenAddress, dialNetwork, dialAddress)
 
-	l, err := inv.Net.Listen(spec.network, listenAddress.String())
+	l, err := inv.Net.Listen(listenNetwork, listenAddress)
 	if err != nil {
-		return nil, xerrors.Errorf("listen '%s://%s': %w", spec.network, listenAddress.String(), err)
+		return nil, xerrors.Errorf("listen '%s://%s': %w", listenNetwork, listenAddress, err)
 	}
 	logger.Debug(ctx, "listening")
 
+	var udpSessions atomic.Int64
 	wg.Add(1)
 	go func(spec portForwardSpec) {
 		defer wg.Done()
@@ -255,7 +348,7 @@ func listenAndPortForward(
 				}
 				_, _ = fmt.Fprintf(inv.Stderr,
 					"Error accepting connection from '%s://%s': %v\n",
-					spec.network, listenAddress.String(), err)
+					listenNetwork, listenAddress, err)
 				_, _ = fmt.Fprintln(inv.Stderr, "Killing listener")
 				return
 			}
@@ -264,17 +357,33 @@ func listenAndPortForward(
 
 			go func(netConn net.Conn) {
 				defer netConn.Close()
-				remoteConn, err := conn.DialContext(ctx, spec.network, dialAddress)
+				remoteConn, err := dialer.DialContext(ctx, dialNetwork, dialAddress)
 				if err != nil {
 					_, _ = fmt.Fprintf(inv.Stderr,
 						"Failed to dial '%s://%s' in workspace: %s\n",
-						spec.network, dialAddress, err)
+						dialNetwork, dialAddress, err)
 					return
 				}
 				defer remoteConn.Close()
 				logger.Debug(ctx,
 					"dialed remote", slog.F("remote_addr", netConn.RemoteAddr()))
 
+				if listenNetwork == "udp" {
+					// The listener hands out a connection per client
+					// address, which lives until it is closed. Close
+					// sessions that have gone idle so that clients that
+					// come and go don't leak connections in the workspace.
+					logger.Debug(ctx, "udp session opened",
+						slog.F("remote_addr", netConn.RemoteAddr()),
+						slog.F("active_sessions", udpSessions.Add(1)))
+					idle := forwardUDP(ctx, netConn, remoteConn, udpIdleTimeout)
+					logger.Debug(ctx, "udp session closed",
+						slog.F("remote_addr", netConn.RemoteAddr()),
+						slog.F("idle", idle),
+						slog.F("active_sessions", udpSessions.Add(-1)))
+					return
+				}
+
 				agentssh.Bicopy(ctx, netConn, remoteConn)
 				logger.Debug(ctx,
 					"connection closing", slog.F("remote_addr", netConn.RemoteAddr()))
@@ -285,13 +394,168 @@ func listenAndPortForward(
 	return l, nil
 }
 
+// forwardUDP copies datagrams between the local and workspace ends of a UDP
+// session until the context is canceled, either end fails, or no datagram has
+// been sent in either direction for idleTimeout. It closes both ends and
+// returns whether the session timed out.
+func forwardUDP(ctx context.Context, local, remote net.Conn, idleTimeout time.Duration) bool {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer local.Close()
+	defer remote.Close()
+
+	var lastActivity atomic.Int64
+	lastActivity.Store(time.Now().UnixNano())
+	copyDatagrams := func(dst, src net.Conn) {
+		defer cancel()
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := src.Read(buf)
+			if err != nil {
+				return
+			}
+			lastActivity.Store(time.Now().UnixNano())
+			_, err = dst.Write(buf[:n])
+			if err != nil {
+				return
+			}
+		}
+	}
+	go copyDatagrams(remote, local)
+	go copyDatagrams(local, remote)
+
+	timer := time.NewTimer(idleTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			idle := time.Since(time.Unix(0, lastActivity.Load()))
+			if idle >= idleTimeout {
+				return true
+			}
+			timer.Reset(idleTimeout - idle)
+		}
+	}
+}
+
+// workspaceDialer dials the workspace end of port-forwards. Unix sockets can't
+// be dialed over the tailnet, so they are dialed through an SSH connection to
+// the agent, which is opened when it is first needed.
+type workspaceDialer struct {
+	mu        sync.Mutex
+	agentID   uuid.UUID
+	conn      *workspacesdk.AgentConn
+	sshClient *gossh.Client
+}
+
+// setConn replaces the connection to the agent, closing the previous one. A
+// nil conn fails dials until it is replaced again, like while a workspace is
+// restarting.
+func (d *workspaceDialer) setConn(agentID uuid.UUID, conn *workspacesdk.AgentConn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closeLocked()
+	d.agentID = agentID
+	d.conn = conn
+}
+
+func (d *workspaceDialer) currentAgentID() uuid.UUID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.agentID
+}
+
+func (d *workspaceDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network != "unix" {
+		d.mu.Lock()
+		conn := d.conn
+		d.mu.Unlock()
+		if conn == nil {
+			return nil, xerrors.New("workspace agent is not connected")
+		}
+		return conn.DialContext(ctx, network, address)
+	}
+	sshClient, err := d.ssh(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to agent ssh: %w", err)
+	}
+	return sshClient.DialContext(ctx, network, address)
+}
+
+func (d *workspaceDialer) ssh(ctx context.Context) (*gossh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sshClient != nil {
+		return d.sshClient, nil
+	}
+	if d.conn == nil {
+		return nil, xerrors.New("workspace agent is not connected")
+	}
+	sshClient, err := d.conn.SSHClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.sshClient = sshClient
+	go func() {
+		// Reconnect on the next dial if the connection is lost.
+		_ = sshClient.Wait()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.sshClient == sshClient {
+			d.sshClient = nil
+		}
+	}()
+	return sshClient, nil
+}
+
+// Close closes the connection to the agent.
+func (d *workspaceDialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closeLocked()
+}
+
+func (d *workspaceDialer) closeLocked() error {
+	var errs []error
+	if d.sshClient != nil {
+		errs = append(errs, d.sshClient.Close())
+		d.sshClient = nil
+	}
+	if d.conn != nil {
+		errs = append(errs, d.conn.Close())
+		d.conn = nil
+	}
+	d.agentID = uuid.Nil
+	return errors.Join(errs...)
+}
+
 type portForwardSpec struct {
 	network              string // tcp, udp
 	listenHost           netip.Addr
 	listenPort, dialPort uint16
+	// listenSocket and dialSocket are the paths of the local and workspace
+	// Unix sockets of --unix forwards. A socket takes the place of the port
+	// on its end of the forward.
+	listenSocket, dialSocket string
+}
+
+func (s portForwardSpec) listenAddress() (network, address string) {
+	if s.listenSocket != "" {
+		return "unix", s.listenSocket
+	}
+	return s.network, netip.AddrPortFrom(s.listenHost, s.listenPort).String()
+}
+
+func (s portForwardSpec) dialAddress() (network, address string) {
+	if s.dialSocket != "" {
+		return "unix", s.dialSocket
+	}
+	return s.network, fmt.Sprintf("127.0.0.1:%d", s.dialPort)
 }
 
-func parsePortForwards(tcpSpecs, udpSpecs []string) ([]portForwardSpec, error) {
+func parsePortForwards(tcpSpecs, udpSpecs, unixSpecs []string) ([]portForwardSpec, error) {
 	specs := []portForwardSpec{}
 
 	for _, specEntry := range tcpSpecs {
@@ -322,17 +586,40 @@ func parsePortForwards(tcpSpecs, udpSpecs []string) ([]portForwardSpec, error) {
 		}
 	}
 
-	// Check for duplicate entries.
+	for _, spec := range unixSpecs {
+		pfSpec, err := parseUnixForward(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse Unix socket forward specification %q: %w", spec, err)
+		}
+		specs = append(specs, pfSpec)
+	}
+
+	if err := checkDuplicateLocals(specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// checkDuplicateLocals returns an error if two specs listen on the same local
+// address.
+func checkDuplicateLocals(specs []portForwardSpec) error {
 	locals := map[string]struct{}{}
 	for _, spec := range specs {
+		if spec.listenSocket != "" {
+			localStr := "unix:" + spec.listenSocket
+			if _, ok := locals[localStr]; ok {
+				return xerrors.Errorf("local unix socket %q is specified twice", spec.listenSocket)
+			}
+			locals[localStr] = struct{}{}
+			continue
+		}
 		localStr := fmt.Sprintf("%s:%s:%d", spec.network, spec.listenHost, spec.listenPort)
 		if _, ok := locals[localStr]; ok {
-			return nil, xerrors.Errorf("local %s host:%s port:%d is specified twice", spec.network, spec.listenHost, spec.listenPort)
+			return xerrors.Errorf("local %s host:%s port:%d is specified twice", spec.network, spec.listenHost, spec.listenPort)
 		}
 		locals[localStr] = struct{}{}
 	}
-
-	return specs, nil
+	return nil
 }
 
 func parsePort(in string) (uint16, error) {
@@ -407,6 +694,52 @@ func parseSrcDestPorts(in string) ([]portForwardSpec, error) {
 	return out, nil
 }
 
+// unixLocalRegexp matches the local end of a Unix socket forward that is a TCP
+// port, optionally with the address to listen on, like 5432, 127.0.0.1:5432
+// or [::1]:5432.
+var unixLocalRegexp = regexp.MustCompile(`^((\[[0-9a-fA-F:]+]|\d+\.\d+\.\d+\.\d+):)?(\d+)$`)
+
+// parseUnixForward parses a forward in the form <local>:<remote>, where each
+// end is either a Unix socket path or a TCP port, and at least one of them is
+// a socket. The remote end is everything after the last colon, so remote
+// socket paths can't contain colons.
+func parseUnixForward(in string) (portForwardSpec, error) {
+	i := strings.LastIndex(in, ":")
+	if i <= 0 || i == len(in)-1 {
+		return portForwardSpec{}, xerrors.Errorf("invalid Unix socket forward %q, expected <local>:<remote>", in)
+	}
+	local, remote := in[:i], in[i+1:]
+
+	spec := portForwardSpec{network: "tcp"}
+	if groups := unixLocalRegexp.FindStringSubmatch(local); groups != nil {
+		if groups[2] != "" {
+			parsedAddr, err := netip.ParseAddr(strings.Trim(groups[2], "[]"))
+			if err != nil {
+				return portForwardSpec{}, xerrors.Errorf("invalid IP address %q", groups[2])
+			}
+			spec.listenHost = parsedAddr
+		}
+		port, err := parsePort(groups[3])
+		if err != nil {
+			return portForwardSpec{}, xerrors.Errorf("parse local port from %q: %w", in, err)
+		}
+		spec.listenPort = port
+	} else {
+		spec.listenSocket = local
+	}
+
+	if port, err := parsePort(remote); err == nil {
+		spec.dialPort = port
+	} else {
+		spec.dialSocket = remote
+	}
+
+	if spec.listenSocket == "" && spec.dialSocket == "" {
+		return portForwardSpec{}, xerrors.Errorf("neither end of %q is a Unix socket, use --tcp to forward TCP ports", in)
+	}
+	return spec, nil
+}
+
 func parsePortRange(s, e string) ([]uint16, error) {
 	start, err := parsePort(s)
 	if err != nil {
@@ -1,17 +1,22 @@
 package cli
 
 import (
+	"context"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
 )
 
 func Test_parsePortForwards(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		tcpSpecs []string
-		udpSpecs []string
+		tcpSpecs  []string
+		udpSpecs  []string
+		unixSpecs []string
 	}
 	tests := []struct {
 		name    string
@@ -29,15 +34,15 @@ func Test_parsePortForwards(t *testing.T) {
 				},
 			},
 			want: []portForwardSpec{
-				{"tcp", noAddr, 8000, 8000},
-				{"tcp", noAddr, 8080, 8081},
-				{"tcp", noAddr, 9000, 9000},
-				{"tcp", noAddr, 9001, 9001},
-				{"tcp", noAddr, 9002, 9002},
-				{"tcp", noAddr, 9003, 9005},
-				{"tcp", noAddr, 9004, 9006},
-				{"tcp", noAddr, 10000, 10000},
-				{"tcp", noAddr, 4444, 4444},
+				{"tcp", noAddr, 8000, 8000, "", ""},
+				{"tcp", noAddr, 8080, 8081, "", ""},
+				{"tcp", noAddr, 9000, 9000, "", ""},
+				{"tcp", noAddr, 9001, 9001, "", ""},
+				{"tcp", noAddr, 9002, 9002, "", ""},
+				{"tcp", noAddr, 9003, 9005, "", ""},
+				{"tcp", noAddr, 9004, 9006, "", ""},
+				{"tcp", noAddr, 10000, 10000, "", ""},
+				{"tcp", noAddr, 4444, 4444, "", ""},
 			},
 		},
 		{
@@ -46,7 +51,7 @@ func Test_parsePortForwards(t *testing.T) {
 				tcpSpecs: []string{"127.0.0.1:8080:8081"},
 			},
 			want: []portForwardSpec{
-				{"tcp", ipv4Loopback, 8080, 8081},
+				{"tcp", ipv4Loopback, 8080, 8081, "", ""},
 			},
 		},
 		{
@@ -55,7 +60,7 @@ func Test_parsePortForwards(t *testing.T) {
 				tcpSpecs: []string{"[::1]:8080:8081"},
 			},
 			want: []portForwardSpec{
-				{"tcp", ipv6Loopback, 8080, 8081},
+				{"tcp", ipv6Loopback, 8080, 8081, "", ""},
 			},
 		},
 		{
@@ -64,9 +69,9 @@ func Test_parsePortForwards(t *testing.T) {
 				udpSpecs: []string{"8000,8080-8081"},
 			},
 			want: []portForwardSpec{
-				{"udp", noAddr, 8000, 8000},
-				{"udp", noAddr, 8080, 8080},
-				{"udp", noAddr, 8081, 8081},
+				{"udp", noAddr, 8000, 8000, "", ""},
+				{"udp", noAddr, 8080, 8080, "", ""},
+				{"udp", noAddr, 8081, 8081, "", ""},
 			},
 		},
 		{
@@ -75,7 +80,7 @@ func Test_parsePortForwards(t *testing.T) {
 				udpSpecs: []string{"127.0.0.1:8080:8081"},
 			},
 			want: []portForwardSpec{
-				{"udp", ipv4Loopback, 8080, 8081},
+				{"udp", ipv4Loopback, 8080, 8081, "", ""},
 			},
 		},
 		{
@@ -84,9 +89,51 @@ func Test_parsePortForwards(t *testing.T) {
 				udpSpecs: []string{"[::1]:8080:8081"},
 			},
 			want: []portForwardSpec{
-				{"udp", ipv6Loopback, 8080, 8081},
+				{"udp", ipv6Loopback, 8080, 8081, "", ""},
+			},
+		},
+		{
+			name: "Unix socket to socket",
+			args: args{
+				unixSpecs: []string{"./docker.sock:/var/run/docker.sock"},
+			},
+			want: []portForwardSpec{
+				{"tcp", noAddr, 0, 0, "./docker.sock", "/var/run/docker.sock"},
+			},
+		},
+		{
+			name: "Unix TCP to socket",
+			args: args{
+				unixSpecs: []string{"5432:/var/run/postgresql/.s.PGSQL.5432", "[::1]:5433:/tmp/pg.sock"},
+			},
+			want: []portForwardSpec{
+				{"tcp", noAddr, 5432, 0, "", "/var/run/postgresql/.s.PGSQL.5432"},
+				{"tcp", ipv6Loopback, 5433, 0, "", "/tmp/pg.sock"},
+			},
+		},
+		{
+			name: "Unix socket to TCP",
+			args: args{
+				unixSpecs: []string{"/tmp/app.sock:8080"},
+			},
+			want: []portForwardSpec{
+				{"tcp", noAddr, 0, 8080, "/tmp/app.sock", ""},
 			},
 		},
+		{
+			name: "Unix without a socket",
+			args: args{
+				unixSpecs: []string{"8080:8080"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Unix duplicate local socket",
+			args: args{
+				unixSpecs: []string{"/tmp/a.sock:/tmp/b.sock", "/tmp/a.sock:/tmp/c.sock"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Bad port range",
 			args: args{
@@ -107,7 +154,7 @@ func Test_parsePortForwards(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := parsePortForwards(tt.args.tcpSpecs, tt.args.udpSpecs)
+			got, err := parsePortForwards(tt.args.tcpSpecs, tt.args.udpSpecs, tt.args.unixSpecs)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parsePortForwards() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -116,3 +163,116 @@ func Test_parsePortForwards(t *testing.T) {
 		})
 	}
 }
+
+func Test_forwardUDP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("IdleTimeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		local, _ := net.Pipe()
+		remote, _ := net.Pipe()
+		require.True(t, forwardUDP(ctx, local, remote, testutil.IntervalFast))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(testutil.Context(t, testutil.WaitShort))
+		cancel()
+		local, _ := net.Pipe()
+		remote, _ := net.Pipe()
+		require.False(t, forwardUDP(ctx, local, remote, testutil.WaitLong))
+	})
+}
+
+func Test_parsePortForwardProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		profile string
+		want    [][]portForwardSpec
+		wantErr string
+	}{
+		{
+			name: "Multiple workspaces",
+			profile: `
+forwards:
+  - workspace: alice/dev
+    agent: main
+    tcp: ["5432", "8080:3000"]
+  - workspace: staging
+    udp: ["5353:53"]
+    unix: ["./docker.sock:/var/run/docker.sock"]
+`,
+			want: [][]portForwardSpec{
+				{
+					{"tcp", noAddr, 5432, 5432, "", ""},
+					{"tcp", noAddr, 8080, 3000, "", ""},
+				},
+				{
+					{"udp", noAddr, 5353, 53, "", ""},
+					{"tcp", noAddr, 0, 0, "./docker.sock", "/var/run/docker.sock"},
+				},
+			},
+		},
+		{
+			name:    "No forwards",
+			profile: `forwards: []`,
+			wantErr: "no forwards",
+		},
+		{
+			name: "Missing workspace",
+			profile: `
+forwards:
+  - tcp: ["5432"]
+`,
+			wantErr: "workspace is required",
+		},
+		{
+			name: "Entry without ports",
+			profile: `
+forwards:
+  - workspace: dev
+`,
+			wantErr: "no port-forwards requested",
+		},
+		{
+			name: "Unknown field",
+			profile: `
+forwards:
+  - workspace: dev
+    ports: ["5432"]
+`,
+			wantErr: "field ports not found",
+		},
+		{
+			name: "Duplicate local across workspaces",
+			profile: `
+forwards:
+  - workspace: dev
+    tcp: ["5432"]
+  - workspace: staging
+    tcp: ["5432"]
+`,
+			wantErr: "specified twice",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parsePortForwardProfile([]byte(tt.profile))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Forwards, len(tt.want))
+			for i, entry := range got.Forwards {
+				require.Equal(t, tt.want[i], entry.specs)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -380,6 +383,152 @@ func TestPortForward(t *testing.T) {
 	})
 }
 
+func TestPortForward_Unix(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix socket forwarding is not supported on Windows")
+	}
+
+	var (
+		client, db         = coderdtest.NewWithDatabase(t, nil)
+		admin              = coderdtest.CreateFirstUser(t, client)
+		member, memberUser = coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace          = runAgent(t, client, memberUser.ID, db)
+	)
+
+	cases := []struct {
+		name string
+		// flag has one format arg (string) for the remote socket path.
+		flag  string
+		local addr
+	}{
+		{
+			name:  "SocketToSocket",
+			flag:  "--unix=/tmp/local.sock:%v",
+			local: addr{"unix", "/tmp/local.sock"},
+		},
+		{
+			name:  "TCPToSocket",
+			flag:  "--unix=5432:%v",
+			local: addr{"tcp", "127.0.0.1:5432"},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			remoteSock := filepath.Join(tempDirUnixSocket(t), "remote.sock")
+			l, err := net.Listen("unix", remoteSock)
+			require.NoError(t, err, "create Unix listener")
+			setupTestListener(t, l)
+
+			inv, root := clitest.New(t, "-v", "port-forward", workspace.Name, fmt.Sprintf(c.flag, remoteSock))
+			clitest.SetupConfig(t, member, root)
+			pty := ptytest.New(t).Attach(inv)
+			inv.Stderr = pty.Output()
+
+			iNet := newInProcNet()
+			inv.Net = iNet
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+			errC := make(chan error)
+			go func() {
+				errC <- inv.WithContext(ctx).Run()
+			}()
+			pty.ExpectMatchContext(ctx, "Ready!")
+
+			// Open two connections so both share the SSH connection to the
+			// agent.
+			dialCtx, dialCtxCancel := context.WithTimeout(ctx, testutil.WaitShort)
+			defer dialCtxCancel()
+			c1, err := iNet.dial(dialCtx, c.local)
+			require.NoError(t, err, "open connection 1 to 'local' listener")
+			defer c1.Close()
+			c2, err := iNet.dial(dialCtx, c.local)
+			require.NoError(t, err, "open connection 2 to 'local' listener")
+			defer c2.Close()
+			testDial(t, c2)
+			testDial(t, c1)
+
+			cancel()
+			err = <-errC
+			require.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+func TestPortForward_Profile(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db         = coderdtest.NewWithDatabase(t, nil)
+		admin              = coderdtest.CreateFirstUser(t, client)
+		member, memberUser = coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+		workspace1         = runAgent(t, client, memberUser.ID, db)
+		workspace2         = runAgent(t, client, memberUser.ID, db)
+	)
+
+	newListener := func() string {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err, "create TCP listener")
+		return setupTestListener(t, l)
+	}
+	profile := fmt.Sprintf(`
+forwards:
+  - workspace: %s
+    tcp: ["127.0.0.1:5555:%s"]
+  - workspace: %s
+    tcp: ["127.0.0.1:6666:%s"]
+`, workspace1.Name, newListener(), workspace2.Name, newListener())
+	profilePath := filepath.Join(t.TempDir(), "profile.yaml")
+	require.NoError(t, os.WriteFile(profilePath, []byte(profile), 0o600))
+
+	inv, root := clitest.New(t, "-v", "port-forward", "--profile", profilePath)
+	clitest.SetupConfig(t, member, root)
+	pty := ptytest.New(t).Attach(inv)
+	inv.Stderr = pty.Output()
+
+	iNet := newInProcNet()
+	inv.Net = iNet
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	errC := make(chan error)
+	go func() {
+		errC <- inv.WithContext(ctx).Run()
+	}()
+	pty.ExpectMatchContext(ctx, "Ready!")
+
+	// Both workspaces are reached over the same connection.
+	dialCtx, dialCtxCancel := context.WithTimeout(ctx, testutil.WaitShort)
+	defer dialCtxCancel()
+	c1, err := iNet.dial(dialCtx, addr{"tcp", "127.0.0.1:5555"})
+	require.NoError(t, err, "open connection to workspace 1")
+	defer c1.Close()
+	c2, err := iNet.dial(dialCtx, addr{"tcp", "127.0.0.1:6666"})
+	require.NoError(t, err, "open connection to workspace 2")
+	defer c2.Close()
+	testDial(t, c2)
+	testDial(t, c1)
+
+	cancel()
+	err = <-errC
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPortForward_ProfileWithWorkspace(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	inv, root := clitest.New(t, "port-forward", "blah", "--profile", "dev.yaml")
+	clitest.SetupConfig(t, member, root)
+
+	err := inv.Run()
+	require.ErrorContains(t, err, "can't be used with --profile")
+}
+
 // runAgent creates a fake workspace and starts an agent locally for that
 // workspace. The agent will be cleaned up on test completion.
 // nolint:unused
@@ -435,6 +584,9 @@ func setupTestListener(t *testing.T, l net.Listener) string {
 	}()
 
 	addr := l.Addr().String()
+	if l.Addr().Network() == "unix" {
+		return addr
+	}
 	_, port, err := net.SplitHostPort(addr)
 	require.NoErrorf(t, err, "split non-Unix listen path %q", addr)
 	addr = port
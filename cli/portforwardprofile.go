@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/retry"
+	"github.com/coder/serpent"
+)
+
+// portForwardProfile is the file read by `coder port-forward --profile`. It
+// lists forwards to one or more workspaces, for example:
+//
+//	forwards:
+//	  - workspace: alice/dev
+//	    agent: main
+//	    tcp: ["5432", "8080:3000"]
+//	  - workspace: staging
+//	    unix: ["./docker.sock:/var/run/docker.sock"]
+type portForwardProfile struct {
+	Forwards []portForwardProfileEntry `yaml:"forwards"`
+}
+
+// portForwardProfileEntry is the forwards to a single workspace agent. The
+// forwards use the same syntax as the --tcp, --udp and --unix flags.
+type portForwardProfileEntry struct {
+	// Workspace is the workspace to forward to, as [owner/]workspace[.agent].
+	Workspace string `yaml:"workspace"`
+	// Agent is the name of the agent to forward to, if the workspace has more
+	// than one.
+	Agent string   `yaml:"agent"`
+	TCP   []string `yaml:"tcp"`
+	UDP   []string `yaml:"udp"`
+	Unix  []string `yaml:"unix"`
+
+	specs []portForwardSpec
+}
+
+// input returns the workspace and agent in the form accepted by
+// getWorkspaceAndAgent.
+func (e portForwardProfileEntry) input() string {
+	if e.Agent == "" {
+		return e.Workspace
+	}
+	return e.Workspace + "." + e.Agent
+}
+
+func readPortForwardProfile(path string) (portForwardProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return portForwardProfile{}, xerrors.Errorf("read port-forward profile: %w", err)
+	}
+	profile, err := parsePortForwardProfile(data)
+	if err != nil {
+		return portForwardProfile{}, xerrors.Errorf("parse port-forward profile %q: %w", path, err)
+	}
+	return profile, nil
+}
+
+func parsePortForwardProfile(data []byte) (portForwardProfile, error) {
+	var profile portForwardProfile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(&profile)
+	if err != nil {
+		return portForwardProfile{}, err
+	}
+	if len(profile.Forwards) == 0 {
+		return portForwardProfile{}, xerrors.New("no forwards in profile")
+	}
+
+	var all []portForwardSpec
+	for i := range profile.Forwards {
+		entry := &profile.Forwards[i]
+		if entry.Workspace == "" {
+			return portForwardProfile{}, xerrors.Errorf("forward %d: workspace is required", i+1)
+		}
+		specs, err := parsePortForwards(entry.TCP, entry.UDP, entry.Unix)
+		if err != nil {
+			return portForwardProfile{}, xerrors.Errorf("forward %d (%s): %w", i+1, entry.input(), err)
+		}
+		if len(specs) == 0 {
+			return portForwardProfile{}, xerrors.Errorf("forward %d (%s): no port-forwards requested", i+1, entry.input())
+		}
+		entry.specs = specs
+		all = append(all, specs...)
+	}
+	// Each entry is checked on its own above, but the local addresses have to
+	// be unique across all of them.
+	if err := checkDuplicateLocals(all); err != nil {
+		return portForwardProfile{}, err
+	}
+	return profile, nil
+}
+
+// portForwardTarget is a workspace agent and the forwards to it.
+type portForwardTarget struct {
+	name      string
+	workspace codersdk.Workspace
+	agent     codersdk.WorkspaceAgent
+	specs     []portForwardSpec
+	dialer    *workspaceDialer
+}
+
+// resolvePortForwardTarget finds the workspace agent of a profile entry,
+// starting the workspace if allowed, and waits for the agent to be ready.
+func resolvePortForwardTarget(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, entry portForwardProfileEntry, autostart bool, docsURL string) (*portForwardTarget, error) {
+	workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, autostart, entry.input())
+	if err != nil {
+		return nil, err
+	}
+	if workspace.LatestBuild.Transition != codersdk.WorkspaceTransitionStart {
+		return nil, xerrors.New("workspace must be in start transition to port-forward")
+	}
+	if workspace.LatestBuild.Job.CompletedAt == nil {
+		err = cliui.WorkspaceBuild(ctx, inv.Stderr, client, workspace.LatestBuild.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+		Fetch:   client.WorkspaceAgent,
+		Wait:    false,
+		DocsURL: docsURL,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("await agent: %w", err)
+	}
+
+	return &portForwardTarget{
+		name:      entry.input(),
+		workspace: workspace,
+		agent:     workspaceAgent,
+		specs:     entry.specs,
+	}, nil
+}
+
+// watch follows the target's workspace until the context is canceled,
+// reconnecting to its agent when the workspace is restarted and printing
+// changes in the status of the forwards.
+func (t *portForwardTarget) watch(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, conn *workspacesdk.MultiAgentConn, logger slog.Logger) {
+	logger = logger.With(slog.F("workspace", t.name))
+	status := "connected"
+	for r := retry.New(time.Second, 15*time.Second); r.Wait(ctx); {
+		updates, err := client.WatchWorkspace(ctx, t.workspace.ID)
+		if err != nil {
+			logger.Warn(ctx, "failed to watch workspace", slog.Error(err))
+			continue
+		}
+		for workspace := range updates {
+			r.Reset()
+			newStatus := t.update(ctx, conn, workspace, logger)
+			if newStatus != status && ctx.Err() == nil {
+				status = newStatus
+				_, _ = fmt.Fprintf(inv.Stderr, "%s: %s\n", t.name, status)
+			}
+		}
+	}
+}
+
+// update reconnects to the target's agent if it has been replaced by a new
+// build of the workspace, and returns the status of the forwards.
+func (t *portForwardTarget) update(ctx context.Context, conn *workspacesdk.MultiAgentConn, workspace codersdk.Workspace, logger slog.Logger) string {
+	build := workspace.LatestBuild
+	if build.Transition != codersdk.WorkspaceTransitionStart || build.Status != codersdk.WorkspaceStatusRunning {
+		// The agent is going away, or has gone away. Forwards fail until
+		// the workspace is started again.
+		t.dialer.setConn(uuid.Nil, nil)
+		return fmt.Sprintf("workspace is %s, waiting for it to start", build.Status)
+	}
+
+	// The agent is looked up by the name it was first resolved to, since a
+	// workspace with several agents would otherwise pick one at random.
+	agent, err := getWorkspaceAgent(workspace, t.agent.Name)
+	if err != nil {
+		t.dialer.setConn(uuid.Nil, nil)
+		return err.Error()
+	}
+	if agent.ID == t.dialer.currentAgentID() {
+		// Tailnet recovers from the agent briefly disconnecting by itself.
+		if agent.Status != codersdk.WorkspaceAgentConnected {
+			return fmt.Sprintf("agent is %s", agent.Status)
+		}
+		return "connected"
+	}
+	if agent.Status != codersdk.WorkspaceAgentConnected {
+		t.dialer.setConn(uuid.Nil, nil)
+		return fmt.Sprintf("agent is %s", agent.Status)
+	}
+
+	logger.Debug(ctx, "connecting to new workspace agent", slog.F("agent_id", agent.ID))
+	dialCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	agentConn, err := conn.AgentConn(dialCtx, agent.ID)
+	if err != nil {
+		return fmt.Sprintf("failed to connect to agent: %s", err)
+	}
+	t.agent = agent
+	t.dialer.setConn(agent.ID, agentConn)
+	return "connected"
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+const (
+	// primaryProxy is the name of the region served by the main Coder
+	// deployment rather than a workspace proxy.
+	primaryProxy = "primary"
+	// autoProxy selects the region with the lowest latency.
+	autoProxy = "auto"
+
+	// latencyProbeTimeout bounds how long commands wait for regions to
+	// respond before picking from those that have.
+	latencyProbeTimeout = 3 * time.Second
+	// latencyProbeCount is the number of requests made to each region. The
+	// first one includes connection setup, which the dashboard excludes with
+	// the Resource Timing API, so the fastest request is used.
+	latencyProbeCount = 3
+	// regionCacheTTL is how long a region selected by latency is reused,
+	// e.g. by ssh ProxyCommand runs that would otherwise probe regions on
+	// every connection.
+	regionCacheTTL = 10 * time.Minute
+)
+
+func (r *RootCmd) proxies() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "proxies",
+		Short: "Inspect workspace proxy regions",
+		Long: "Workspace proxies serve apps and relay connections to workspaces from other regions. " +
+			"Commands that connect to workspaces pick the region with the lowest latency unless --proxy is set.\n" + FormatExamples(
+			Example{
+				Description: "Show the latency to each region",
+				Command:     "coder proxies latency",
+			},
+			Example{
+				Description: "Connect through a specific region",
+				Command:     "coder ssh --proxy sydney <workspace>",
+			},
+		),
+		Aliases: []string{"proxy"},
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.proxiesLatency(),
+		},
+	}
+	return cmd
+}
+
+// regionLatencyRow is the type provided to the OutputFormatter.
+type regionLatencyRow struct {
+	// For JSON format:
+	codersdk.Region `table:"-"`
+	LatencyMS       *float64 `json:"latency_ms" table:"-"`
+	Error           string   `json:"error,omitempty" table:"-"`
+
+	// For table format:
+	Name        string `json:"-" table:"name,default_sort"`
+	DisplayName string `json:"-" table:"display name"`
+	Healthy     bool   `json:"-" table:"healthy"`
+	Latency     string `json:"-" table:"latency"`
+	Selected    bool   `json:"selected" table:"selected"`
+}
+
+func (r *RootCmd) proxiesLatency() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]regionLatencyRow{}, []string{"name", "display name", "healthy", "latency", "selected"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "latency",
+		Short: "Measure the latency to each workspace proxy region, and show which one would be used.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			regions, err := client.Regions(ctx)
+			if err != nil {
+				return xerrors.Errorf("fetch regions: %w", err)
+			}
+
+			probeCtx, cancel := context.WithTimeout(ctx, latencyProbeTimeout)
+			defer cancel()
+			latencies := probeRegionLatencies(probeCtx, client.HTTPClient, regions)
+			selected := fastestRegion(regions, latencies)
+			// Commands that connect to workspaces use the region shown here
+			// until the cache expires.
+			_ = writeCachedRegion(r.createConfig().ProxyRegion(), client.URL.String(), selected)
+
+			rows := make([]regionLatencyRow, 0, len(regions))
+			for _, region := range regions {
+				row := regionLatencyRow{
+					Region:      region,
+					Name:        region.Name,
+					DisplayName: region.DisplayName,
+					Healthy:     region.Healthy,
+					Latency:     "-",
+					Selected:    region.ID == selected.ID,
+				}
+				if result, ok := latencies[region.ID]; ok {
+					if result.err != nil {
+						row.Error = result.err.Error()
+						row.Latency = "unreachable"
+					} else {
+						ms := float64(result.latency) / float64(time.Millisecond)
+						row.LatencyMS = &ms
+						row.Latency = result.latency.Round(time.Millisecond).String()
+					}
+				}
+				rows = append(rows, row)
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// proxyOption is the --proxy flag of commands that connect to workspaces.
+func proxyOption(src *string) serpent.Option {
+	return serpent.Option{
+		Flag:        "proxy",
+		Env:         "CODER_PROXY",
+		Description: `Workspace proxy region to use for app URLs and relayed connections. "auto" picks the region with the lowest latency and "primary" uses the main Coder deployment.`,
+		Default:     autoProxy,
+		Value:       serpent.StringOf(src),
+	}
+}
+
+// selectRegion returns the region named by proxy, or with "auto" the healthy
+// region with the lowest latency. Regions are only probed if there is more
+// than one to choose from.
+func selectRegion(ctx context.Context, client *codersdk.Client, proxy string) (codersdk.Region, error) {
+	regions, err := client.Regions(ctx)
+	if err != nil {
+		return codersdk.Region{}, xerrors.Errorf("fetch regions: %w", err)
+	}
+
+	if proxy != autoProxy {
+		idx := slices.IndexFunc(regions, func(r codersdk.Region) bool {
+			return strings.EqualFold(r.Name, proxy)
+		})
+		if idx == -1 {
+			names := make([]string, len(regions))
+			for i, r := range regions {
+				names[i] = r.Name
+			}
+			return codersdk.Region{}, xerrors.Errorf("region not found: %q, available regions are %s", proxy, strings.Join(names, ", "))
+		}
+		return regions[idx], nil
+	}
+
+	candidates := slices.DeleteFunc(slices.Clone(regions), func(r codersdk.Region) bool {
+		return !r.Healthy || r.PathAppURL == ""
+	})
+	if len(candidates) <= 1 {
+		return primaryRegion(regions), nil
+	}
+	probeCtx, cancel := context.WithTimeout(ctx, latencyProbeTimeout)
+	defer cancel()
+	return fastestRegion(regions, probeRegionLatencies(probeCtx, client.HTTPClient, candidates)), nil
+}
+
+// selectRegionOrPrimary is selectRegion for commands that only use the region
+// to prefer a DERP relay. With "auto" the selection is best-effort: a region
+// selected within regionCacheTTL is reused from cache, and if no region can be
+// selected, a warning is printed and the primary region is used. A region
+// named explicitly must exist.
+func selectRegionOrPrimary(ctx context.Context, inv *serpent.Invocation, client *codersdk.Client, cache config.File, proxy string) (codersdk.Region, error) {
+	if proxy != autoProxy {
+		return selectRegion(ctx, client, proxy)
+	}
+	if region, ok := readCachedRegion(cache, client.URL.String()); ok {
+		return region, nil
+	}
+	region, err := selectRegion(ctx, client, proxy)
+	if err != nil {
+		cliui.Warnf(inv.Stderr, "Failed to select proxy region, using the primary region: %s", err)
+		return codersdk.Region{Name: primaryProxy}, nil
+	}
+	// The cache only saves time, so failing to write it is not an error.
+	_ = writeCachedRegion(cache, client.URL.String(), region)
+	return region, nil
+}
+
+// cachedRegion is the content of the proxy region cache file.
+type cachedRegion struct {
+	URL        string          `json:"url"`
+	SelectedAt time.Time       `json:"selected_at"`
+	Region     codersdk.Region `json:"region"`
+}
+
+// readCachedRegion returns the region cached for the deployment at url if it
+// was selected within regionCacheTTL.
+func readCachedRegion(cache config.File, url string) (codersdk.Region, bool) {
+	raw, err := cache.Read()
+	if err != nil {
+		return codersdk.Region{}, false
+	}
+	var cached cachedRegion
+	if err := json.Unmarshal([]byte(raw), &cached); err != nil {
+		return codersdk.Region{}, false
+	}
+	if cached.URL != url || time.Since(cached.SelectedAt) > regionCacheTTL {
+		return codersdk.Region{}, false
+	}
+	return cached.Region, true
+}
+
+func writeCachedRegion(cache config.File, url string, region codersdk.Region) error {
+	raw, err := json.Marshal(cachedRegion{
+		URL:        url,
+		SelectedAt: time.Now(),
+		Region:     region,
+	})
+	if err != nil {
+		return err
+	}
+	return cache.Write(string(raw))
+}
+
+// preferredDERPProxy returns the proxy whose DERP relay connections to
+// workspaces should prefer, given the --proxy flag and the region selected
+// with it. If the primary deployment was picked automatically, tailnet is left
+// to choose a relay by itself.
+func preferredDERPProxy(proxy string, region codersdk.Region) string {
+	if proxy == autoProxy && region.Name == primaryProxy {
+		return ""
+	}
+	return region.Name
+}
+
+// primaryRegion returns the region of the main Coder deployment.
+func primaryRegion(regions []codersdk.Region) codersdk.Region {
+	idx := slices.IndexFunc(regions, func(r codersdk.Region) bool {
+		return r.Name == primaryProxy
+	})
+	if idx == -1 {
+		return codersdk.Region{Name: primaryProxy}
+	}
+	return regions[idx]
+}
+
+type regionLatency struct {
+	latency time.Duration
+	err     error
+}
+
+// fastestRegion returns the healthy region with the lowest latency, or the
+// primary region if none could be reached.
+func fastestRegion(regions []codersdk.Region, latencies map[uuid.UUID]regionLatency) codersdk.Region {
+	best := primaryRegion(regions)
+	var bestLatency time.Duration
+	for _, region := range regions {
+		result, ok := latencies[region.ID]
+		if !ok || result.err != nil || !region.Healthy {
+			continue
+		}
+		if bestLatency == 0 || result.latency < bestLatency {
+			best = region
+			bestLatency = result.latency
+		}
+	}
+	return best
+}
+
+// probeRegionLatencies measures the latency to the latency-check endpoint of
+// each region concurrently, which is what the dashboard uses to pick a
+// region. Regions without a URL are skipped.
+func probeRegionLatencies(ctx context.Context, httpClient *http.Client, regions []codersdk.Region) map[uuid.UUID]regionLatency {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		latencies = make(map[uuid.UUID]regionLatency, len(regions))
+	)
+	for _, region := range regions {
+		if region.PathAppURL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(region codersdk.Region) {
+			defer wg.Done()
+			latency, err := probeRegionLatency(ctx, httpClient, region)
+			mu.Lock()
+			defer mu.Unlock()
+			latencies[region.ID] = regionLatency{latency: latency, err: err}
+		}(region)
+	}
+	wg.Wait()
+	return latencies
+}
+
+func probeRegionLatency(ctx context.Context, httpClient *http.Client, region codersdk.Region) (time.Duration, error) {
+	u, err := url.Parse(region.PathAppURL)
+	if err != nil {
+		return 0, xerrors.Errorf("parse region URL: %w", err)
+	}
+	u.Path = "/latency-check"
+	u.RawQuery = ""
+
+	var best time.Duration
+	for i := 0; i < latencyProbeCount; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return 0, err
+		}
+		start := time.Now()
+		res, err := httpClient.Do(req)
+		if err != nil {
+			if best > 0 {
+				// Keep what was measured before the deadline.
+				return best, nil
+			}
+			return 0, err
+		}
+		latency := time.Since(start)
+		_ = res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return 0, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+		}
+		if best == 0 || latency < best {
+			best = latency
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/config"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func Test_selectRegion(t *testing.T) {
+	t.Parallel()
+
+	// newRegionServer serves a latency-check endpoint that responds after
+	// delay.
+	newRegionServer := func(t *testing.T, delay time.Duration) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/latency-check" {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			time.Sleep(delay)
+			rw.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	newClient := func(t *testing.T, regions []codersdk.Region) *codersdk.Client {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v2/regions", r.URL.Path)
+			httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.RegionsResponse[codersdk.Region]{Regions: regions})
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return codersdk.New(u)
+	}
+
+	t.Run("AutoFastest", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: newRegionServer(t, 200*time.Millisecond)},
+			{ID: uuid.New(), Name: "sydney", Healthy: true, PathAppURL: newRegionServer(t, 0)},
+		})
+		region, err := selectRegion(ctx, client, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "sydney", region.Name)
+		require.Equal(t, "sydney", preferredDERPProxy(autoProxy, region))
+	})
+
+	t.Run("AutoSkipsUnhealthy", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: newRegionServer(t, 200*time.Millisecond)},
+			{ID: uuid.New(), Name: "sydney", Healthy: false, PathAppURL: newRegionServer(t, 0)},
+			{ID: uuid.New(), Name: "london", Healthy: true, PathAppURL: "http://127.0.0.1:1"},
+		})
+		region, err := selectRegion(ctx, client, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "primary", region.Name)
+		// The primary deployment was picked automatically, so the DERP
+		// relay is left to tailnet.
+		require.Empty(t, preferredDERPProxy(autoProxy, region))
+	})
+
+	t.Run("AutoSingleRegion", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		probe := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			t.Error("the only region should not be probed")
+			rw.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(probe.Close)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: probe.URL},
+		})
+		region, err := selectRegion(ctx, client, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "primary", region.Name)
+	})
+
+	t.Run("FallbackToPrimary", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			httpapi.InternalServerError(rw, nil)
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+
+		var stderr bytes.Buffer
+		inv := &serpent.Invocation{Stderr: &stderr}
+		cache := config.Root(t.TempDir()).ProxyRegion()
+		region, err := selectRegionOrPrimary(ctx, inv, codersdk.New(u), cache, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "primary", region.Name)
+		require.Empty(t, preferredDERPProxy(autoProxy, region))
+		require.Contains(t, stderr.String(), "using the primary region")
+		// The fallback is not cached.
+		require.False(t, cache.Exists())
+
+		// A region named explicitly is not a best-effort selection.
+		_, err = selectRegionOrPrimary(ctx, inv, codersdk.New(u), cache, "sydney")
+		require.Error(t, err)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var probes atomic.Int64
+		probe := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			probes.Add(1)
+			rw.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(probe.Close)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: newRegionServer(t, 200*time.Millisecond)},
+			{ID: uuid.New(), Name: "sydney", Healthy: true, PathAppURL: probe.URL},
+		})
+		inv := &serpent.Invocation{Stderr: io.Discard}
+		cache := config.Root(t.TempDir()).ProxyRegion()
+
+		region, err := selectRegionOrPrimary(ctx, inv, client, cache, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "sydney", region.Name)
+		probed := probes.Load()
+		require.Positive(t, probed)
+
+		// The selection is reused without probing again.
+		region, err = selectRegionOrPrimary(ctx, inv, client, cache, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "sydney", region.Name)
+		require.Equal(t, probed, probes.Load())
+
+		// An expired selection is not.
+		raw, err := json.Marshal(cachedRegion{
+			URL:        client.URL.String(),
+			SelectedAt: time.Now().Add(-regionCacheTTL - time.Minute),
+			Region:     codersdk.Region{Name: "london"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, cache.Write(string(raw)))
+		region, err = selectRegionOrPrimary(ctx, inv, client, cache, autoProxy)
+		require.NoError(t, err)
+		require.Equal(t, "sydney", region.Name)
+		require.Greater(t, probes.Load(), probed)
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: "https://coder.example.com"},
+			{ID: uuid.New(), Name: "sydney", Healthy: true, PathAppURL: "https://sydney.example.com"},
+		})
+		region, err := selectRegion(ctx, client, "primary")
+		require.NoError(t, err)
+		require.Equal(t, "primary", region.Name)
+		require.Equal(t, "primary", preferredDERPProxy("primary", region))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := newClient(t, []codersdk.Region{
+			{ID: uuid.New(), Name: "primary", Healthy: true, PathAppURL: "https://coder.example.com"},
+		})
+		_, err := selectRegion(ctx, client, "sydney")
+		require.ErrorContains(t, err, "region not found")
+	})
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/serpent"
+)
+
+type processRow struct {
+	PID       int32     `json:"pid" table:"pid"`
+	PPID      int32     `json:"ppid" table:"ppid"`
+	User      string    `json:"user" table:"user"`
+	CPU       string    `json:"cpu" table:"cpu %"`
+	Memory    string    `json:"memory" table:"memory"`
+	StartedAt time.Time `json:"started_at" table:"started at,default_sort"`
+	Owner     string    `json:"owner" table:"owner"`
+	Command   string    `json:"command" table:"command"`
+}
+
+func (r *RootCmd) ps() *serpent.Command {
+	var (
+		appearanceConfig codersdk.AppearanceConfig
+		ownedOnly        bool
+		formatter        = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(
+				cliui.TableFormat([]processRow{}, []string{"pid", "user", "cpu %", "memory", "owner", "command"}),
+				func(data any) (any, error) {
+					procs, ok := data.([]workspacesdk.Process)
+					if !ok {
+						return nil, xerrors.Errorf("expected type %T, got %T", []workspacesdk.Process{}, data)
+					}
+					rows := make([]processRow, 0, len(procs))
+					for _, proc := range procs {
+						command := proc.Cmdline
+						if command == "" {
+							// Kernel threads and zombies have no command line.
+							command = "[" + proc.Name + "]"
+						}
+						rows = append(rows, processRow{
+							PID:       proc.PID,
+							PPID:      proc.PPID,
+							User:      proc.Username,
+							CPU:       fmt.Sprintf("%.1f", proc.CPUPercent),
+							Memory:    humanize.IBytes(proc.MemoryRSS),
+							StartedAt: proc.CreatedAt,
+							Owner:     formatProcessOwner(proc.Owner),
+							Command:   command,
+						})
+					}
+					return rows, nil
+				},
+			),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "ps <workspace>",
+		Short:       "List the processes running in a workspace",
+		Long: "The owner of a process is the session of the agent that started it, or one of its ancestors: " +
+			"an SSH session, a web terminal, an agent script or an app.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "List the processes started by sessions of the agent",
+					Command:     "coder ps my-workspace --owned",
+				},
+				Example{
+					Description: "List the processes of a specific agent as JSON",
+					Command:     "coder ps my-workspace.main -o json",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+			initAppearance(client, &appearanceConfig),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			conn, err := r.dialWorkspaceAgent(ctx, inv, client, appearanceConfig, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			res, err := conn.ListProcesses(ctx)
+			if err != nil {
+				return xerrors.Errorf("list processes: %w", err)
+			}
+			procs := res.Processes
+			if ownedOnly {
+				owned := make([]workspacesdk.Process, 0, len(procs))
+				for _, proc := range procs {
+					if proc.Owner != nil {
+						owned = append(owned, proc)
+					}
+				}
+				procs = owned
+			}
+
+			out, err := formatter.Format(ctx, procs)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "owned",
+			Description: "Only list processes that were started by a session of the agent.",
+			Value:       serpent.BoolOf(&ownedOnly),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// formatProcessOwner formats the owner of a process for a table, e.g.
+// "script: Startup Script".
+func formatProcessOwner(owner *workspacesdk.ProcessOwner) string {
+	if owner == nil {
+		return ""
+	}
+	var typ string
+	switch owner.Type {
+	case workspacesdk.ProcessOwnerTypeSSH:
+		typ = "ssh"
+	case workspacesdk.ProcessOwnerTypeReconnectingPTY:
+		typ = "terminal"
+	case workspacesdk.ProcessOwnerTypeScript:
+		typ = "script"
+	case workspacesdk.ProcessOwnerTypeApp:
+		typ = "app"
+	default:
+		typ = string(owner.Type)
+	}
+	name := strings.TrimSpace(strings.SplitN(owner.Name, "\n", 2)[0])
+	if name == "" || name == typ {
+		return typ
+	}
+	return typ + ": " + name
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentrecording"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) recordings() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "recordings { list | play | export }",
+		Short: "List and replay recorded SSH and web terminal sessions",
+		Long: "Sessions are recorded in workspaces of templates with session recording enabled. " +
+			"Recordings can be read by users that can read the audit logs.",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.recordingsList(),
+			r.recordingsPlay(),
+			r.recordingsExport(),
+		},
+	}
+	return cmd
+}
+
+type recordingListRow struct {
+	ID             uuid.UUID     `json:"id" table:"id"`
+	StartedAt      time.Time     `json:"started_at" table:"started at,default_sort"`
+	Duration       time.Duration `json:"duration" table:"duration"`
+	Workspace      string        `json:"workspace" table:"workspace"`
+	Agent          string        `json:"agent" table:"agent"`
+	ConnectionType string        `json:"connection_type" table:"type"`
+	ConnectionID   uuid.UUID     `json:"connection_id" table:"connection id"`
+	Size           int64         `json:"size" table:"size"`
+}
+
+func (r *RootCmd) recordingsList() *serpent.Command {
+	var (
+		workspaceName string
+		connectionID  string
+		limit         int64
+		formatter     = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(
+				cliui.TableFormat([]recordingListRow{}, []string{"id", "started at", "duration", "workspace", "agent", "type", "connection id"}),
+				func(data any) (any, error) {
+					recordings, ok := data.([]codersdk.WorkspaceSessionRecording)
+					if !ok {
+						return nil, xerrors.Errorf("expected type %T, got %T", []codersdk.WorkspaceSessionRecording{}, data)
+					}
+					rows := make([]recordingListRow, 0, len(recordings))
+					for _, recording := range recordings {
+						rows = append(rows, recordingListRow{
+							ID:             recording.ID,
+							StartedAt:      recording.StartedAt,
+							Duration:       recording.EndedAt.Sub(recording.StartedAt).Round(time.Second),
+							Workspace:      recording.WorkspaceName,
+							Agent:          recording.AgentName,
+							ConnectionType: recording.ConnectionType,
+							ConnectionID:   recording.ConnectionID,
+							Size:           recording.Size,
+						})
+					}
+					return rows, nil
+				},
+			),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List session recordings, most recent first",
+		Long: FormatExamples(
+			Example{
+				Description: "List the recordings of a workspace",
+				Command:     "coder recordings list --workspace alice/dev",
+			},
+			Example{
+				Description: "Find the recording of a connection from the audit logs",
+				Command:     "coder recordings list --connection-id <request-id>",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			req := codersdk.WorkspaceSessionRecordingsRequest{
+				Pagination: codersdk.Pagination{Limit: int(limit)},
+			}
+			if workspaceName != "" {
+				workspace, err := namedWorkspace(ctx, client, workspaceName)
+				if err != nil {
+					return xerrors.Errorf("get workspace: %w", err)
+				}
+				req.WorkspaceID = workspace.ID
+			}
+			if connectionID != "" {
+				id, err := uuid.Parse(connectionID)
+				if err != nil {
+					return xerrors.Errorf("parse connection id: %w", err)
+				}
+				req.ConnectionID = id
+			}
+
+			recordings, err := client.WorkspaceSessionRecordings(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("list session recordings: %w", err)
+			}
+			if len(recordings) == 0 && formatter.FormatID() != cliui.JSONFormat().ID() {
+				cliui.Info(inv.Stdout, "No session recordings found.")
+				return nil
+			}
+			out, err := formatter.Format(ctx, recordings)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "workspace",
+			Description: "Only list recordings of this workspace.",
+			Value:       serpent.StringOf(&workspaceName),
+		},
+		{
+			Flag:        "connection-id",
+			Description: "Only list the recording of the connection with this ID, the request ID of the connection audit logs.",
+			Value:       serpent.StringOf(&connectionID),
+		},
+		{
+			Flag:          "limit",
+			FlagShorthand: "n",
+			Description:   "The maximum number of recordings to list.",
+			Default:       "25",
+			Value:         serpent.Int64Of(&limit),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) recordingsPlay() *serpent.Command {
+	var (
+		speed         float64
+		idleTimeLimit time.Duration
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "play <id>",
+		Short: "Replay a session recording in the terminal",
+		Long: "Replays the output of a session with its original timing. " +
+			"Use a terminal of at least the size of the recorded session.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			if speed <= 0 {
+				return xerrors.New("speed must be greater than zero")
+			}
+			id, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("parse recording id: %w", err)
+			}
+
+			cast, err := client.WorkspaceSessionRecordingCast(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("get session recording: %w", err)
+			}
+			defer cast.Close()
+			dec, _, err := agentrecording.NewDecoder(cast)
+			if err != nil {
+				return xerrors.Errorf("read session recording: %w", err)
+			}
+
+			var last time.Duration
+			for {
+				event, err := dec.Next()
+				if xerrors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return xerrors.Errorf("read session recording: %w", err)
+				}
+				if event.Type != agentrecording.EventTypeOutput {
+					continue
+				}
+
+				delay := event.Time - last
+				last = event.Time
+				if idleTimeLimit > 0 && delay > idleTimeLimit {
+					delay = idleTimeLimit
+				}
+				delay = time.Duration(float64(delay) / speed)
+				if delay > 0 {
+					timer := time.NewTimer(delay)
+					select {
+					case <-ctx.Done():
+						timer.Stop()
+						return ctx.Err()
+					case <-timer.C:
+					}
+				}
+				_, err = io.WriteString(inv.Stdout, event.Data)
+				if err != nil {
+					return err
+				}
+			}
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "speed",
+			Description: "Playback speed, 2 replays the session twice as fast.",
+			Default:     "1",
+			Value:       serpent.Float64Of(&speed),
+		},
+		{
+			Flag:        "idle-time-limit",
+			Description: "Limit pauses in the replay to this duration, 0 keeps the original pauses.",
+			Default:     "2s",
+			Value:       serpent.DurationOf(&idleTimeLimit),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) recordingsExport() *serpent.Command {
+	var outputPath string
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "export <id>",
+		Short: "Export a session recording in the asciicast v2 format",
+		Long: FormatExamples(
+			Example{
+				Description: "Export a recording and replay it with asciinema",
+				Command:     "coder recordings export <id> -o session.cast && asciinema play session.cast",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			id, err := uuid.Parse(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("parse recording id: %w", err)
+			}
+			cast, err := client.WorkspaceSessionRecordingCast(inv.Context(), id)
+			if err != nil {
+				return xerrors.Errorf("get session recording: %w", err)
+			}
+			defer cast.Close()
+
+			out := inv.Stdout
+			if outputPath != "" && outputPath != "-" {
+				f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+				if err != nil {
+					return xerrors.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+			_, err = io.Copy(out, cast)
+			if err != nil {
+				return xerrors.Errorf("write session recording: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "The file to write the recording to, defaults to stdout.",
+			Value:         serpent.StringOf(&outputPath),
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRecordings(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	auditor, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleAuditor())
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.AsSystemRestricted(ctx), r.Workspace.ID) //nolint:gocritic // Test setup.
+	require.NoError(t, err)
+
+	data := `{"version":2,"width":80,"height":24}` + "\n" +
+		`[0.5,"o","$ "]` + "\n" +
+		`[1.0,"i","ls\r"]` + "\n" +
+		`[1.5,"o","ls\r\nREADME.md\r\n"]` + "\n"
+	recording := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID: r.Workspace.ID,
+		AgentID:     agents[0].ID,
+	}, []byte(data))
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "recordings", "list", "--connection-id", recording.ConnectionID.String(), "--output", "json")
+		clitest.SetupConfig(t, auditor, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var recordings []codersdk.WorkspaceSessionRecording
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &recordings))
+		require.Len(t, recordings, 1)
+		require.Equal(t, recording.ID, recordings[0].ID)
+		require.Equal(t, r.Workspace.Name, recordings[0].WorkspaceName)
+	})
+
+	t.Run("Play", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "recordings", "play", recording.ID.String(), "--speed", "100")
+		clitest.SetupConfig(t, auditor, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		// Only the output is replayed.
+		require.Equal(t, "$ ls\r\nREADME.md\r\n", buf.String())
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		path := filepath.Join(t.TempDir(), "session.cast")
+		inv, root := clitest.New(t, "recordings", "export", recording.ID.String(), "-o", path)
+		clitest.SetupConfig(t, auditor, root)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, data, string(got))
+	})
+}
@@ -96,7 +96,9 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		r.notifications(),
 		r.organizations(),
 		r.portForward(),
+		r.proxies(),
 		r.publickey(),
+		r.recordings(),
 		r.resetPassword(),
 		r.state(),
 		r.templates(),
@@ -107,16 +109,22 @@ func (r *RootCmd) CoreSubcommands() []*serpent.Command {
 		// Workspace Commands
 		r.autoupdate(),
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.expose(),
 		r.favorite(),
+		r.kill(),
 		r.list(),
 		r.open(),
 		r.ping(),
+		r.ps(),
 		r.rename(),
 		r.restart(),
 		r.schedules(),
+		r.sharing(),
 		r.show(),
+		r.snapshot(),
 		r.speedtest(),
 		r.ssh(),
 		r.start(),
@@ -769,7 +777,23 @@ func namedWorkspace(ctx context.Context, client *codersdk.Client, identifier str
 	if err != nil {
 		return codersdk.Workspace{}, err
 	}
-	return client.WorkspaceByOwnerAndName(ctx, owner, name, codersdk.WorkspaceOptions{})
+	ws, err := client.WorkspaceByOwnerAndName(ctx, owner, name, codersdk.WorkspaceOptions{})
+	var apiErr *codersdk.Error
+	if err == nil || owner == codersdk.Me || !errors.As(err, &apiErr) || apiErr.StatusCode() != http.StatusNotFound {
+		return ws, err
+	}
+	// Users a workspace is shared with may not be allowed to read its owner,
+	// so fall back to searching the workspaces they can see.
+	res, listErr := client.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: owner, Name: name})
+	if listErr != nil {
+		return ws, err
+	}
+	for _, ws := range res.Workspaces {
+		if strings.EqualFold(ws.OwnerName, owner) && ws.Name == name {
+			return ws, nil
+		}
+	}
+	return ws, err
 }
 
 func initAppearance(client *codersdk.Client, outConfig *codersdk.AppearanceConfig) serpent.MiddlewareFunc {
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) sharing() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "sharing { add | remove | list } <workspace>",
+		Short:       "Share a workspace with other users and groups",
+		Long: "Users and groups a workspace is shared with can connect to it " +
+			"with the \"use\" role, or manage it like the owner with the \"admin\" role.",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.sharingAdd(),
+			r.sharingRemove(),
+			r.sharingList(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) sharingAdd() *serpent.Command {
+	var (
+		users  []string
+		groups []string
+		role   string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "add <workspace>",
+		Short: "Share a workspace with users or groups",
+		Long: FormatExamples(
+			Example{
+				Description: "Let alice and bob connect to your workspace",
+				Command:     "coder sharing add my-workspace --user alice,bob",
+			},
+			Example{
+				Description: "Give the platform group full control of a workspace",
+				Command:     "coder sharing add my-workspace --group platform --role admin",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if len(users) == 0 && len(groups) == 0 {
+				return xerrors.New("at least one of --user or --group must be specified")
+			}
+			ws, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			req, err := sharingRequest(inv.Context(), client, ws, users, groups, codersdk.WorkspaceRole(role))
+			if err != nil {
+				return err
+			}
+			err = client.UpdateWorkspaceACL(inv.Context(), ws.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update workspace ACL: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q shared with %s.\n", ws.Name, sharingTargets(users, groups))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Usernames of members of the workspace organization to share the workspace with.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Names of groups in the workspace organization to share the workspace with.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+		{
+			Flag:        "role",
+			Description: "The role to grant. \"use\" allows connecting to the workspace, its apps and ports. \"admin\" also allows managing the workspace.",
+			Default:     string(codersdk.WorkspaceRoleUse),
+			Value:       serpent.EnumOf(&role, string(codersdk.WorkspaceRoleUse), string(codersdk.WorkspaceRoleAdmin)),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) sharingRemove() *serpent.Command {
+	var (
+		users  []string
+		groups []string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "remove <workspace>",
+		Short: "Stop sharing a workspace with users or groups",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			if len(users) == 0 && len(groups) == 0 {
+				return xerrors.New("at least one of --user or --group must be specified")
+			}
+			ws, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			req, err := sharingRequest(inv.Context(), client, ws, users, groups, codersdk.WorkspaceRoleDeleted)
+			if err != nil {
+				return err
+			}
+			err = client.UpdateWorkspaceACL(inv.Context(), ws.ID, req)
+			if err != nil {
+				return xerrors.Errorf("update workspace ACL: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Workspace %q is no longer shared with %s.\n", ws.Name, sharingTargets(users, groups))
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Usernames to stop sharing the workspace with.",
+			Value:       serpent.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Names of groups to stop sharing the workspace with.",
+			Value:       serpent.StringArrayOf(&groups),
+		},
+	}
+	return cmd
+}
+
+type sharingListRow struct {
+	Type string                 `json:"type" table:"type"`
+	Name string                 `json:"name" table:"name,default_sort"`
+	Role codersdk.WorkspaceRole `json:"role" table:"role"`
+}
+
+func (r *RootCmd) sharingList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]sharingListRow{}, []string{"type", "name", "role"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the users and groups a workspace is shared with",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ws, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			acl, err := client.WorkspaceACL(inv.Context(), ws.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace ACL: %w", err)
+			}
+
+			rows := make([]sharingListRow, 0, len(acl.Users)+len(acl.Groups))
+			for _, user := range acl.Users {
+				rows = append(rows, sharingListRow{Type: "user", Name: user.Username, Role: user.Role})
+			}
+			for _, group := range acl.Groups {
+				rows = append(rows, sharingListRow{Type: "group", Name: group.Name, Role: group.Role})
+			}
+			if len(rows) == 0 {
+				cliui.Infof(inv.Stdout, "Workspace %q is not shared with anyone.", ws.Name)
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// sharingRequest resolves user and group names to the IDs the ACL is keyed
+// by.
+func sharingRequest(ctx context.Context, client *codersdk.Client, ws codersdk.Workspace, users, groups []string, role codersdk.WorkspaceRole) (codersdk.UpdateWorkspaceACL, error) {
+	req := codersdk.UpdateWorkspaceACL{
+		UserRoles:  map[string]codersdk.WorkspaceRole{},
+		GroupRoles: map[string]codersdk.WorkspaceRole{},
+	}
+	available, err := client.WorkspaceACLAvailable(ctx, ws.ID)
+	if err != nil {
+		return req, xerrors.Errorf("get available users and groups: %w", err)
+	}
+	for _, name := range users {
+		idx := slices.IndexFunc(available.Users, func(u codersdk.ReducedUser) bool {
+			return strings.EqualFold(u.Username, name)
+		})
+		if idx < 0 {
+			return req, xerrors.Errorf("user %q is not a member of the workspace organization", name)
+		}
+		req.UserRoles[available.Users[idx].ID.String()] = role
+	}
+	for _, name := range groups {
+		idx := slices.IndexFunc(available.Groups, func(g codersdk.Group) bool {
+			return g.Name == name
+		})
+		if idx < 0 {
+			return req, xerrors.Errorf("group %q does not exist in the workspace organization", name)
+		}
+		req.GroupRoles[available.Groups[idx].ID.String()] = role
+	}
+	return req, nil
+}
+
+func sharingTargets(users, groups []string) string {
+	targets := make([]string, 0, len(users)+len(groups))
+	for _, user := range users {
+		targets = append(targets, fmt.Sprintf("user %q", user))
+	}
+	for _, group := range groups {
+		targets = append(targets, fmt.Sprintf("group %q", group))
+	}
+	return strings.Join(targets, ", ")
+}
@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSharing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		client, db           = coderdtest.NewWithDatabase(t, nil)
+		owner                = coderdtest.CreateFirstUser(t, client)
+		memberClient, member = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		sharedClient, shared = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ws                   = dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{OwnerID: member.ID, OrganizationID: owner.OrganizationID}).Do()
+	)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "sharing", "add", ws.Workspace.Name, "--user", shared.Username)
+	clitest.SetupConfig(t, memberClient, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), shared.Username)
+
+	// The shared user can now see the workspace.
+	_, err = sharedClient.Workspace(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "sharing", "list", ws.Workspace.Name)
+	clitest.SetupConfig(t, memberClient, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), shared.Username)
+	require.Contains(t, buf.String(), string(codersdk.WorkspaceRoleUse))
+
+	// The shared user can address the workspace by its owner.
+	inv, root = clitest.New(t, "sharing", "list", member.Username+"/"+ws.Workspace.Name)
+	clitest.SetupConfig(t, sharedClient, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), shared.Username)
+
+	inv, root = clitest.New(t, "sharing", "remove", ws.Workspace.Name, "--user", shared.Username)
+	clitest.SetupConfig(t, memberClient, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	acl, err := memberClient.WorkspaceACL(ctx, ws.Workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, acl.Users)
+	_, err = sharedClient.Workspace(ctx, ws.Workspace.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) snapshot() *serpent.Command {
+	cmd := &serpent.Command{
+		Annotations: workspaceCommand,
+		Use:         "snapshot { create | list | restore | delete } <workspace>",
+		Short:       "Snapshot a workspace and roll it back to a previous build",
+		Long: "Snapshots record the Terraform state, parameters and template version " +
+			"of a workspace build. Restoring a snapshot starts a new build from it.",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.snapshotCreate(),
+			r.snapshotList(),
+			r.snapshotRestore(),
+			r.snapshotDelete(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) snapshotCreate() *serpent.Command {
+	var buildNumber int64
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "create <workspace> <name>",
+		Short: "Snapshot a build of a workspace",
+		Long: FormatExamples(
+			Example{
+				Description: "Snapshot a workspace before upgrading its template version",
+				Command:     "coder snapshot create my-workspace before-upgrade",
+			},
+			Example{
+				Description: "Snapshot an earlier build of a workspace",
+				Command:     "coder snapshot create my-workspace known-good --build 3",
+			},
+		),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			snapshot, err := client.CreateWorkspaceSnapshot(inv.Context(), workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+				Name:        inv.Args[1],
+				BuildNumber: int32(buildNumber),
+			})
+			if err != nil {
+				return xerrors.Errorf("create snapshot: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Created snapshot %s of build %d of workspace %s (template version %s).\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, snapshot.Name),
+				snapshot.BuildNumber,
+				pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+				snapshot.TemplateVersionName,
+			)
+			return nil
+		},
+	}
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "build",
+			Description: "The build number to snapshot. Defaults to the latest build.",
+			Value:       serpent.Int64Of(&buildNumber),
+		},
+	}
+	return cmd
+}
+
+type snapshotListRow struct {
+	Name            string    `json:"name" table:"name,default_sort"`
+	CreatedAt       time.Time `json:"created_at" table:"created at"`
+	CreatedBy       string    `json:"created_by" table:"created by"`
+	BuildNumber     int32     `json:"build_number" table:"build"`
+	TemplateVersion string    `json:"template_version" table:"template version"`
+}
+
+func (r *RootCmd) snapshotList() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(
+			cliui.TableFormat([]snapshotListRow{}, []string{"name", "created at", "created by", "build", "template version"}),
+			func(data any) (any, error) {
+				snapshots, ok := data.([]codersdk.WorkspaceSnapshot)
+				if !ok {
+					return nil, xerrors.Errorf("expected type %T, got %T", []codersdk.WorkspaceSnapshot{}, data)
+				}
+				rows := make([]snapshotListRow, 0, len(snapshots))
+				for _, snapshot := range snapshots {
+					rows = append(rows, snapshotListRow{
+						Name:            snapshot.Name,
+						CreatedAt:       snapshot.CreatedAt,
+						CreatedBy:       snapshot.CreatedBy.Username,
+						BuildNumber:     snapshot.BuildNumber,
+						TemplateVersion: snapshot.TemplateVersionName,
+					})
+				}
+				return rows, nil
+			},
+		),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the snapshots of a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			snapshots, err := client.WorkspaceSnapshots(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list snapshots: %w", err)
+			}
+			if len(snapshots) == 0 && formatter.FormatID() != cliui.JSONFormat().ID() {
+				cliui.Infof(inv.Stdout, "Workspace %q has no snapshots.", workspace.Name)
+				return nil
+			}
+
+			out, err := formatter.Format(inv.Context(), snapshots)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) snapshotRestore() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "restore <workspace> <name>",
+		Short: "Roll a workspace back to a snapshot",
+		Long: "Starts a new build of the workspace with the Terraform state, parameters " +
+			"and template version of the snapshot. A running workspace is stopped first.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: serpent.OptionSet{cliui.SkipPromptOption()},
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			out := inv.Stdout
+
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Restore workspace %s to snapshot %s?", workspace.Name, inv.Args[1]),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			if workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionStart &&
+				workspace.LatestBuild.Job.Status == codersdk.ProvisionerJobSucceeded {
+				build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+					Transition: codersdk.WorkspaceTransitionStop,
+				})
+				if err != nil {
+					return xerrors.Errorf("stop workspace: %w", err)
+				}
+				err = cliui.WorkspaceBuild(ctx, out, client, build.ID)
+				if err != nil {
+					return err
+				}
+			}
+
+			build, err := client.RestoreWorkspaceSnapshot(ctx, workspace.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("restore snapshot: %w", err)
+			}
+			err = cliui.WorkspaceBuild(ctx, out, client, build.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(out,
+				"\nThe %s workspace has been restored to snapshot %s at %s!\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[1]),
+				cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) snapshotDelete() *serpent.Command {
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "delete <workspace> <name>",
+		Short: "Delete a snapshot of a workspace",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			err = client.DeleteWorkspaceSnapshot(inv.Context(), workspace.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("delete snapshot: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted snapshot %s of workspace %s.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[1]),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
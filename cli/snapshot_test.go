@@ -0,0 +1,80 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "snapshot", "create", workspace.Name, "before-upgrade")
+	clitest.SetupConfig(t, member, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "before-upgrade")
+
+	// Upgrade the workspace to a new template version.
+	version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+		req.TemplateID = template.ID
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+	coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, version2.ID)
+	build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		TemplateVersionID: version2.ID,
+		Transition:        codersdk.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+	inv, root = clitest.New(t, "snapshot", "list", workspace.Name)
+	clitest.SetupConfig(t, member, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "before-upgrade")
+	require.Contains(t, buf.String(), version.Name)
+
+	// Restoring stops the running workspace first. Only template admins
+	// may restore a snapshot.
+	inv, root = clitest.New(t, "snapshot", "restore", memberUser.Username+"/"+workspace.Name, "before-upgrade", "--yes")
+	clitest.SetupConfig(t, client, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "has been restored")
+
+	workspace, err = member.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.Equal(t, version.ID, workspace.LatestBuild.TemplateVersionID)
+
+	inv, root = clitest.New(t, "snapshot", "delete", workspace.Name, "before-upgrade")
+	clitest.SetupConfig(t, member, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	snapshots, err := member.WorkspaceSnapshots(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
@@ -79,6 +79,7 @@ func (r *RootCmd) ssh() *serpent.Command {
 		env                 []string
 		usageApp            string
 		disableAutostart    bool
+		proxyArg            string
 		appearanceConfig    codersdk.AppearanceConfig
 		networkInfoDir      string
 		networkInfoInterval time.Duration
@@ -334,11 +335,17 @@ func (r *RootCmd) ssh() *serpent.Command {
 			if r.disableDirect {
 				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
 			}
+			region, err := selectRegionOrPrimary(ctx, inv, client, r.createConfig().ProxyRegion(), proxyArg)
+			if err != nil {
+				return err
+			}
+			logger.Debug(ctx, "selected proxy region", slog.F("region", region.Name))
 			conn, err := wsClient.
 				DialAgent(ctx, workspaceAgent.ID, &workspacesdk.DialAgentOptions{
 					Logger:          logger,
 					BlockEndpoints:  r.disableDirect,
 					EnableTelemetry: !r.disableNetworkTelemetry,
+					PreferredProxy:  preferredDERPProxy(proxyArg, region),
 				})
 			if err != nil {
 				return xerrors.Errorf("dial agent: %w", err)
@@ -729,6 +736,7 @@ func (r *RootCmd) ssh() *serpent.Command {
 			Hidden:      true,
 		},
 		sshDisableAutostartOption(serpent.BoolOf(&disableAutostart)),
+		proxyOption(&proxyArg),
 	}
 	return cmd
 }
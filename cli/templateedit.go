@@ -35,6 +35,8 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		sessionRecording               bool
+		requireUserActivity            bool
 		orgContext                     = NewOrganizationContext()
 	)
 	client := new(codersdk.Client)
@@ -167,6 +169,16 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				disableEveryoneGroup = disableEveryone
 			}
 
+			var recordSessions *bool
+			if userSetOption(inv, "session-recording") {
+				recordSessions = &sessionRecording
+			}
+
+			var activityBumpRequiresUserActivity *bool
+			if userSetOption(inv, "activity-bump-requires-user-activity") {
+				activityBumpRequiresUserActivity = &requireUserActivity
+			}
+
 			req := codersdk.UpdateTemplateMeta{
 				Name:               name,
 				DisplayName:        displayName,
@@ -181,15 +193,17 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 				AutostartRequirement: &codersdk.TemplateAutostartRequirement{
 					DaysOfWeek: autostartRequirementDaysOfWeek,
 				},
-				FailureTTLMillis:               failureTTL.Milliseconds(),
-				TimeTilDormantMillis:           dormancyThreshold.Milliseconds(),
-				TimeTilDormantAutoDeleteMillis: dormancyAutoDeletion.Milliseconds(),
-				AllowUserCancelWorkspaceJobs:   allowUserCancelWorkspaceJobs,
-				AllowUserAutostart:             allowUserAutostart,
-				AllowUserAutostop:              allowUserAutostop,
-				RequireActiveVersion:           requireActiveVersion,
-				DeprecationMessage:             deprecated,
-				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				FailureTTLMillis:                 failureTTL.Milliseconds(),
+				TimeTilDormantMillis:             dormancyThreshold.Milliseconds(),
+				TimeTilDormantAutoDeleteMillis:   dormancyAutoDeletion.Milliseconds(),
+				AllowUserCancelWorkspaceJobs:     allowUserCancelWorkspaceJobs,
+				AllowUserAutostart:               allowUserAutostart,
+				AllowUserAutostop:                allowUserAutostop,
+				RequireActiveVersion:             requireActiveVersion,
+				DeprecationMessage:               deprecated,
+				DisableEveryoneGroupAccess:       disableEveryoneGroup,
+				SessionRecording:                 recordSessions,
+				ActivityBumpRequiresUserActivity: activityBumpRequiresUserActivity,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -238,6 +252,13 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Description: "Edit the template activity bump - workspaces created from this template will have their shutdown time bumped by this value when activity is detected. Maps to \"Activity bump\" in the UI.",
 			Value:       serpent.DurationOf(&activityBump),
 		},
+		{
+			Flag: "activity-bump-requires-user-activity",
+			Description: "Only bump the shutdown time of workspaces created from this template when the user is active, i.e. types into " +
+				"a terminal or runs a command in the foreground of one, instead of whenever a connection is open.",
+			Value:   serpent.BoolOf(&requireUserActivity),
+			Default: "false",
+		},
 		{
 			Flag:        "autostart-requirement-weekdays",
 			Description: "Edit the template autostart requirement weekdays - workspaces created from this template can only autostart on the given weekdays. To unset this value for the template (and allow autostart on all days), pass 'all'.",
@@ -302,6 +323,13 @@ func (r *RootCmd) templateEdit() *serpent.Command {
 			Value:   serpent.BoolOf(&disableEveryone),
 			Default: "false",
 		},
+		{
+			Flag: "session-recording",
+			Description: "Record interactive SSH and web terminal sessions in workspaces created from this template. " +
+				"Recordings can be replayed by auditors with \"coder recordings\".",
+			Value:   serpent.BoolOf(&sessionRecording),
+			Default: "false",
+		},
 		cliui.SkipPromptOption(),
 	}
 	orgContext.AttachOptions(cmd)
@@ -838,6 +838,75 @@ func TestTemplateEdit(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, "appears to be an AGPL deployment")
 	})
+	t.Run("SessionRecording", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		require.False(t, template.SessionRecording)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--session-recording", "-y")
+		//nolint
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.True(t, updated.SessionRecording)
+		// Other settings are left unchanged.
+		require.Equal(t, template.DefaultTTLMillis, updated.DefaultTTLMillis)
+
+		// Editing other settings doesn't turn recording off.
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--description", "recorded", "-y")
+		//nolint
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.True(t, updated.SessionRecording)
+		require.Equal(t, "recorded", updated.Description)
+	})
+	t.Run("ActivityBumpRequiresUserActivity", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		require.False(t, template.ActivityBumpRequiresUserActivity)
+
+		inv, root := clitest.New(t, "templates", "edit", template.Name, "--activity-bump-requires-user-activity", "-y")
+		//nolint
+		clitest.SetupConfig(t, client, root)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.True(t, updated.ActivityBumpRequiresUserActivity)
+		require.Equal(t, template.ActivityBumpMillis, updated.ActivityBumpMillis)
+
+		inv, root = clitest.New(t, "templates", "edit", template.Name, "--activity-bump-requires-user-activity=false", "-y")
+		//nolint
+		clitest.SetupConfig(t, client, root)
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		updated, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.False(t, updated.ActivityBumpRequiresUserActivity)
+	})
 	t.Run("DefaultValues", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
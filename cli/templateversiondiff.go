@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+	"github.com/coder/serpent"
+)
+
+func (r *RootCmd) templateVersionsDiff() *serpent.Command {
+	formatter := cliui.NewOutputFormatter(
+		&templateVersionDiffFormat{},
+		&templateVersionDiffFormat{color: true},
+		cliui.JSONFormat(),
+	)
+	orgContext := NewOrganizationContext()
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "diff <template> <from-version> <to-version>",
+		Short: "Show the changes between two versions of a template",
+		Long: "Shows a unified diff of the template files, and the rich parameters, " +
+			"variables, presets and workspace tags that were added, removed or modified.\n\n" +
+			FormatExamples(
+				Example{
+					Description: "Review the changes in a pushed version before promoting it",
+					Command:     "coder templates versions diff my-template v1 v2 --output color",
+				},
+			),
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(3),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+			organization, err := orgContext.Selected(inv, client)
+			if err != nil {
+				return err
+			}
+			template, err := client.TemplateByName(ctx, organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			from, err := client.TemplateVersionByName(ctx, template.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("get template version %q: %w", inv.Args[1], err)
+			}
+			to, err := client.TemplateVersionByName(ctx, template.ID, inv.Args[2])
+			if err != nil {
+				return xerrors.Errorf("get template version %q: %w", inv.Args[2], err)
+			}
+
+			diff, err := client.TemplateVersionDiff(ctx, from.ID, to.ID)
+			if err != nil {
+				return xerrors.Errorf("diff template versions: %w", err)
+			}
+			if diff.Empty() && formatter.FormatID() != cliui.JSONFormat().ID() {
+				cliui.Infof(inv.Stdout, "There are no changes between %q and %q.", from.Name, to.Name)
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, diff)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	orgContext.AttachOptions(cmd)
+	return cmd
+}
+
+// templateVersionDiffFormat renders a template version diff as text, the way
+// git shows file changes.
+type templateVersionDiffFormat struct {
+	// color forces ANSI colors, even if the output isn't a terminal.
+	color bool
+}
+
+var _ cliui.OutputFormat = &templateVersionDiffFormat{}
+
+// ID implements OutputFormat.
+func (f *templateVersionDiffFormat) ID() string {
+	if f.color {
+		return "color"
+	}
+	return "plain"
+}
+
+// AttachOptions implements OutputFormat.
+func (*templateVersionDiffFormat) AttachOptions(_ *serpent.OptionSet) {}
+
+// Format implements OutputFormat.
+func (f *templateVersionDiffFormat) Format(_ context.Context, data any) (string, error) {
+	diff, ok := data.(codersdk.TemplateVersionDiff)
+	if !ok {
+		return "", xerrors.Errorf("expected type %T, got %T", diff, data)
+	}
+
+	style := func(formatter pretty.Formatter) func(string) string {
+		return func(s string) string {
+			if !f.color {
+				return s
+			}
+			return pretty.Sprint(formatter, s)
+		}
+	}
+	var (
+		header   = style(pretty.Bold())
+		hunk     = style(pretty.FgColor(termenv.ANSICyan))
+		added    = style(pretty.FgColor(termenv.ANSIGreen))
+		removed  = style(pretty.FgColor(termenv.ANSIRed))
+		modified = style(pretty.FgColor(termenv.ANSIYellow))
+	)
+
+	var sb strings.Builder
+	for _, file := range diff.Files {
+		if file.Binary {
+			_, _ = fmt.Fprintf(&sb, "Binary file %s %s\n", file.Path, file.Status)
+			continue
+		}
+		for _, line := range strings.SplitAfter(file.Diff, "\n") {
+			text := strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"):
+				text = header(text)
+			case strings.HasPrefix(text, "@@"):
+				text = hunk(text)
+			case strings.HasPrefix(text, "+"):
+				text = added(text)
+			case strings.HasPrefix(text, "-"):
+				text = removed(text)
+			}
+			sb.WriteString(text)
+			if strings.HasSuffix(line, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	for _, section := range []struct {
+		title string
+		items []codersdk.TemplateVersionItemDiff
+	}{
+		{"Rich parameters", diff.RichParameters},
+		{"Variables", diff.Variables},
+		{"Presets", diff.Presets},
+		{"Workspace tags", diff.WorkspaceTags},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(&sb, "%s:\n", section.title)
+		for _, item := range section.items {
+			switch item.Status {
+			case codersdk.TemplateVersionDiffStatusAdded:
+				_, _ = fmt.Fprintf(&sb, "  %s\n", added("+ "+item.Name))
+			case codersdk.TemplateVersionDiffStatusRemoved:
+				_, _ = fmt.Fprintf(&sb, "  %s\n", removed("- "+item.Name))
+			default:
+				_, _ = fmt.Fprintf(&sb, "  %s\n", modified("~ "+item.Name))
+				for _, change := range item.Changes {
+					_, _ = fmt.Fprintf(&sb, "      %s: %s -> %s\n", change.Field,
+						removed(fmt.Sprintf("%q", change.Old)), added(fmt.Sprintf("%q", change.New)))
+				}
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
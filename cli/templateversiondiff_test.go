@@ -0,0 +1,115 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionsDiff(t *testing.T) {
+	t.Parallel()
+
+	responses := func(mainTF string, parameter *proto.RichParameter) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: []*proto.RichParameter{parameter},
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+			ExtraFiles:     map[string][]byte{"main.tf": []byte(mainTF)},
+		}
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.ScopedRoleOrgTemplateAdmin(owner.OrganizationID))
+	version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(
+		"locals {\n  image = \"ubuntu:22.04\"\n}\n",
+		&proto.RichParameter{Name: "region", Type: "string", DefaultValue: "us"},
+	))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+	version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(
+		"locals {\n  image = \"ubuntu:24.04\"\n}\n",
+		&proto.RichParameter{Name: "region", Type: "string", DefaultValue: "eu"},
+	), func(req *codersdk.CreateTemplateVersionRequest) {
+		req.TemplateID = template.ID
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+
+	t.Run("Plain", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "versions", "diff", template.Name, version1.Name, version2.Name)
+		clitest.SetupConfig(t, templateAdmin, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		out := buf.String()
+		require.Contains(t, out, "--- a/main.tf\n+++ b/main.tf\n")
+		require.Contains(t, out, "-  image = \"ubuntu:22.04\"\n+  image = \"ubuntu:24.04\"\n")
+		require.Contains(t, out, "Rich parameters:\n  ~ region\n      default_value: \"us\" -> \"eu\"\n")
+		require.NotContains(t, out, "\x1b[")
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "versions", "diff", template.Name, version1.Name, version2.Name, "--output", "color")
+		clitest.SetupConfig(t, templateAdmin, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "\x1b[")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "versions", "diff", template.Name, version1.Name, version2.Name, "--output", "json")
+		clitest.SetupConfig(t, templateAdmin, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var diff codersdk.TemplateVersionDiff
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &diff))
+		require.Equal(t, version1.ID, diff.FromTemplateVersionID)
+		require.Equal(t, version2.ID, diff.ToTemplateVersionID)
+		require.Len(t, diff.RichParameters, 1)
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "templates", "versions", "diff", template.Name, version2.Name, version2.Name)
+		clitest.SetupConfig(t, templateAdmin, root)
+		var buf bytes.Buffer
+		inv.Stdout = &buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "There are no changes")
+	})
+}
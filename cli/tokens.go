@@ -23,6 +23,10 @@ func (r *RootCmd) tokens() *serpent.Command {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			Example{
+				Description: "Create a token that can only start and stop the workspaces of a template",
+				Command:     "coder tokens create --scope workspace:read --scope workspace:start --scope workspace:stop --allow template:<template-id>",
+			},
 			Example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -50,6 +54,8 @@ func (r *RootCmd) createToken() *serpent.Command {
 		tokenLifetime string
 		name          string
 		user          string
+		scopes        []string
+		allowList     []string
 	)
 	client := new(codersdk.Client)
 	cmd := &serpent.Command{
@@ -87,8 +93,10 @@ func (r *RootCmd) createToken() *serpent.Command {
 			}
 
 			res, err := client.CreateToken(inv.Context(), userID, codersdk.CreateTokenRequest{
-				Lifetime:  parsedLifetime,
-				TokenName: name,
+				Lifetime:         parsedLifetime,
+				TokenName:        name,
+				ScopePermissions: scopes,
+				ScopeAllowList:   allowList,
 			})
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
@@ -121,6 +129,18 @@ func (r *RootCmd) createToken() *serpent.Command {
 			Description:   "Specify the user to create the token for (Only works if logged in user is admin).",
 			Value:         serpent.StringOf(&user),
 		},
+		{
+			Flag:        "scope",
+			Env:         "CODER_TOKEN_SCOPE",
+			Description: "Limit the token to a resource:action pair, such as workspace:read or template:push. Can be specified multiple times.",
+			Value:       serpent.StringArrayOf(&scopes),
+		},
+		{
+			Flag:        "allow",
+			Env:         "CODER_TOKEN_ALLOW",
+			Description: "Limit the token to a resource:id pair, such as template:<id>. Allowing a template also allows the user's workspaces built from it. Can be specified multiple times.",
+			Value:       serpent.StringArrayOf(&allowList),
+		},
 	}
 
 	return cmd
@@ -138,6 +158,8 @@ type tokenListRow struct {
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
 	Owner     string    `json:"-" table:"owner"`
+	Scopes    string    `json:"-" table:"scopes"`
+	AllowList string    `json:"-" table:"allow list"`
 }
 
 func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
@@ -149,6 +171,8 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
 		Owner:     token.Username,
+		Scopes:    strings.Join(token.ScopePermissions, ","),
+		AllowList: strings.Join(token.ScopeAllowList, ","),
 	}
 }
 
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -157,3 +161,50 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensScoped(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	version := coderdtest.CreateTemplateVersion(t, templateAdmin, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, templateAdmin, version.ID)
+	template := coderdtest.CreateTemplate(t, templateAdmin, owner.OrganizationID, version.ID)
+
+	inv, root := clitest.New(t, "tokens", "create", "--name", "ci", "--scope", "template:push", "--allow", "template:"+template.ID.String())
+	clitest.SetupConfig(t, templateAdmin, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	scoped := codersdk.New(client.URL)
+	scoped.SetSessionToken(strings.TrimSpace(buf.String()))
+
+	inv, root = clitest.New(t, "tokens", "ls", "-c", "name,scopes,allow list")
+	clitest.SetupConfig(t, templateAdmin, root)
+	buf = new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "template:push")
+	require.Contains(t, buf.String(), "template:"+template.ID.String())
+
+	// The token can push the template it is allowed to.
+	source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	inv, root = clitest.New(t, "templates", "push", template.Name, "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--yes")
+	clitest.SetupConfig(t, scoped, root)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	// The token cannot be used for anything else.
+	_, err = scoped.CreateUserWorkspace(ctx, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "not-allowed",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,27 @@
+//go:build linux
+
+package cli
+
+import (
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/vpn"
+)
+
+// vpnDaemonRPCPipe opens the RPC pipe from the file descriptors inherited
+// from the parent process. The descriptors are duplicated so that closing the
+// pipe doesn't close descriptors that the caller may still own, e.g. when the
+// command is run in-process.
+func vpnDaemonRPCPipe(readFd, writeFd uintptr) (vpn.BidirectionalPipe, error) {
+	dupReadFd, err := unix.Dup(int(readFd))
+	if err != nil {
+		return vpn.BidirectionalPipe{}, xerrors.Errorf("dup rpc-read-handle: %w", err)
+	}
+	dupWriteFd, err := unix.Dup(int(writeFd))
+	if err != nil {
+		_ = unix.Close(dupReadFd)
+		return vpn.BidirectionalPipe{}, xerrors.Errorf("dup rpc-write-handle: %w", err)
+	}
+	return vpn.NewBidirectionalPipe(uintptr(dupReadFd), uintptr(dupWriteFd))
+}
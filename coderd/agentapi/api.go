@@ -51,6 +51,7 @@ type API struct {
 	*LogsAPI
 	*ScriptsAPI
 	*AuditAPI
+	*SessionRecordingsAPI
 	*tailnet.DRPCService
 
 	mu sync.Mutex
@@ -154,6 +155,7 @@ func New(opts Options) *API {
 
 	api.AppsAPI = &AppsAPI{
 		AgentFn:                  api.agent,
+		AppHostname:              opts.AppHostname,
 		Database:                 opts.Database,
 		Log:                      opts.Log,
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
@@ -185,6 +187,13 @@ func New(opts Options) *API {
 		Log:      opts.Log,
 	}
 
+	api.SessionRecordingsAPI = &SessionRecordingsAPI{
+		AgentFn:     api.agent,
+		WorkspaceID: opts.WorkspaceID,
+		Database:    opts.Database,
+		Log:         opts.Log,
+	}
+
 	api.DRPCService = &tailnet.DRPCService{
 		CoordPtr:                opts.TailnetCoordinator,
 		Logger:                  opts.Log,
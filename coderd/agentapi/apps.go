@@ -2,18 +2,29 @@ package agentapi
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	"cdr.dev/slog"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/wspubsub"
 )
 
+// devcontainerAppSlugPrefix is the prefix of the slugs of the apps that are
+// registered for the labeled ports of devcontainers.
+const devcontainerAppSlugPrefix = "devcontainer-port-"
+
 type AppsAPI struct {
 	AgentFn                  func(context.Context) (database.WorkspaceAgent, error)
+	AppHostname              string
 	Database                 database.Store
 	Log                      slog.Logger
 	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent, wspubsub.WorkspaceEventKind) error
@@ -104,3 +115,106 @@ func (a *AppsAPI) BatchUpdateAppHealths(ctx context.Context, req *agentproto.Bat
 	}
 	return &agentproto.BatchUpdateAppHealthResponse{}, nil
 }
+
+// UpdateDevcontainerApps makes the devcontainer apps of the agent match the
+// reported ports: apps are registered for new ports, and removed for ports
+// that are no longer forwarded or have changed.
+func (a *AppsAPI) UpdateDevcontainerApps(ctx context.Context, req *agentproto.UpdateDevcontainerAppsRequest) (*emptypb.Empty, error) {
+	workspaceAgent, err := a.AgentFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The agent forwards a port to one devcontainer only, so the port
+	// identifies the app.
+	want := make(map[string]database.InsertWorkspaceAppParams, len(req.GetPorts()))
+	for _, port := range req.GetPorts() {
+		if port.GetLabel() == "" || port.GetPort() == 0 || port.GetPort() > 65535 {
+			continue
+		}
+		scheme := "http"
+		if port.GetProtocol() == "https" {
+			scheme = "https"
+		}
+		displayName := port.GetLabel()
+		if runes := []rune(displayName); len(runes) > 64 {
+			displayName = string(runes[:64])
+		}
+		slug := fmt.Sprintf("%s%d", devcontainerAppSlugPrefix, port.GetPort())
+		want[slug] = database.InsertWorkspaceAppParams{
+			ID:          uuid.New(),
+			AgentID:     workspaceAgent.ID,
+			Slug:        slug,
+			DisplayName: displayName,
+			Url: sql.NullString{
+				String: fmt.Sprintf("%s://localhost:%d", scheme, port.GetPort()),
+				Valid:  true,
+			},
+			// Development servers usually expect to be served at the root
+			// path, which only subdomain apps are.
+			Subdomain:    a.AppHostname != "",
+			SharingLevel: database.AppSharingLevelOwner,
+			Health:       database.WorkspaceAppHealthDisabled,
+			OpenIn:       database.WorkspaceAppOpenInSlimWindow,
+		}
+	}
+
+	apps, err := a.Database.GetWorkspaceAppsByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace apps by agent ID %q: %w", workspaceAgent.ID, err)
+	}
+	var stale []uuid.UUID
+	for _, app := range apps {
+		if !strings.HasPrefix(app.Slug, devcontainerAppSlugPrefix) {
+			continue
+		}
+		// The apps of the template are created with the agent, before it
+		// first connects. They are left as is, even if their slug collides.
+		if !workspaceAgent.FirstConnectedAt.Valid || app.CreatedAt.Before(workspaceAgent.FirstConnectedAt.Time) {
+			delete(want, app.Slug)
+			continue
+		}
+		params, ok := want[app.Slug]
+		if ok && params.DisplayName == app.DisplayName && params.Url == app.Url && params.Subdomain == app.Subdomain {
+			delete(want, app.Slug)
+			continue
+		}
+		stale = append(stale, app.ID)
+	}
+	if len(stale) == 0 && len(want) == 0 {
+		return &emptypb.Empty{}, nil
+	}
+
+	err = a.Database.InTx(func(tx database.Store) error {
+		// nolint:gocritic // This is a system restricted operation.
+		ctx := dbauthz.AsSystemRestricted(ctx)
+		if len(stale) > 0 {
+			if err := tx.DeleteWorkspaceAppsByIDs(ctx, stale); err != nil {
+				return xerrors.Errorf("delete stale devcontainer apps: %w", err)
+			}
+		}
+		for _, params := range want {
+			params.CreatedAt = dbtime.Now()
+			if _, err := tx.InsertWorkspaceApp(ctx, params); err != nil {
+				return xerrors.Errorf("insert devcontainer app %q: %w", params.Slug, err)
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.Log.Debug(ctx, "updated devcontainer apps",
+		slog.F("agent_id", workspaceAgent.ID.String()),
+		slog.F("removed", len(stale)),
+		slog.F("added", len(want)),
+	)
+
+	if a.PublishWorkspaceUpdateFn != nil {
+		err = a.PublishWorkspaceUpdateFn(ctx, &workspaceAgent, wspubsub.WorkspaceEventKindAgentAppsUpdate)
+		if err != nil {
+			return nil, xerrors.Errorf("publish workspace update: %w", err)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -47,8 +47,10 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		scripts       []database.WorkspaceAgentScript
 		metadata      []database.WorkspaceAgentMetadatum
 		workspace     database.Workspace
+		template      database.Template
 		owner         database.User
 		devcontainers []database.WorkspaceAgentDevcontainer
+		portPolicy    *agentproto.PortForwardingPolicy
 	)
 
 	var eg errgroup.Group
@@ -80,6 +82,11 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		if err != nil {
 			return xerrors.Errorf("getting workspace owner by id: %w", err)
 		}
+		// nolint:gocritic // The agent may not be able to read the template.
+		template, err = a.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("getting template by id: %w", err)
+		}
 		return err
 	})
 	eg.Go(func() (err error) {
@@ -89,6 +96,17 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		}
 		return nil
 	})
+	eg.Go(func() (err error) {
+		policy, err := a.Database.GetWorkspaceAgentPortForwardingPolicy(ctx, workspaceAgent.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		portPolicy = dbAgentPortForwardingPolicyToProto(policy)
+		return nil
+	})
 	err = eg.Wait()
 	if err != nil {
 		return nil, xerrors.Errorf("fetching workspace agent data: %w", err)
@@ -133,6 +151,8 @@ func (a *ManifestAPI) GetManifest(ctx context.Context, _ *agentproto.GetManifest
 		MotdPath:                 workspaceAgent.MOTDFile,
 		DisableDirectConnections: a.DisableDirectConnections,
 		DerpForceWebsockets:      a.DerpForceWebSockets,
+		SessionRecording:         template.SessionRecording,
+		PortForwardingPolicy:     portPolicy,
 
 		DerpMap:       tailnet.DERPMapToProto(a.DerpMapFn()),
 		Scripts:       dbAgentScriptsToProto(scripts),
@@ -173,6 +193,15 @@ func dbAgentMetadatumToProtoDescription(metadatum database.WorkspaceAgentMetadat
 	}
 }
 
+func dbAgentPortForwardingPolicyToProto(policy database.WorkspaceAgentPortForwardingPolicy) *agentproto.PortForwardingPolicy {
+	return &agentproto.PortForwardingPolicy{
+		AllowedPorts:       policy.AllowedPorts,
+		DeniedPorts:        policy.DeniedPorts,
+		AllowedUnixSockets: policy.AllowedUnixSockets,
+		DeniedUnixSockets:  policy.DeniedUnixSockets,
+	}
+}
+
 func dbAgentScriptsToProto(scripts []database.WorkspaceAgentScript) []*agentproto.WorkspaceAgentScript {
 	ret := make([]*agentproto.WorkspaceAgentScript, len(scripts))
 	for i, script := range scripts {
@@ -192,6 +221,22 @@ func dbAgentScriptToProto(script database.WorkspaceAgentScript) *agentproto.Work
 		RunOnStop:        script.RunOnStop,
 		StartBlocksLogin: script.StartBlocksLogin,
 		Timeout:          durationpb.New(time.Duration(script.TimeoutSeconds) * time.Second),
+		DisplayName:      script.DisplayName,
+		DependsOn:        script.DependsOn,
+		ReadinessProbe:   dbAgentScriptReadinessProbeToProto(script),
+	}
+}
+
+func dbAgentScriptReadinessProbeToProto(script database.WorkspaceAgentScript) *agentproto.WorkspaceAgentScript_ReadinessProbe {
+	if script.ReadinessProbeCommand == "" && script.ReadinessProbeTCP == "" && script.ReadinessProbeHTTP == "" {
+		return nil
+	}
+	return &agentproto.WorkspaceAgentScript_ReadinessProbe{
+		Command:  script.ReadinessProbeCommand,
+		Tcp:      script.ReadinessProbeTCP,
+		Http:     script.ReadinessProbeHTTP,
+		Interval: durationpb.New(time.Duration(script.ReadinessProbeIntervalSeconds) * time.Second),
+		Timeout:  durationpb.New(time.Duration(script.ReadinessProbeTimeoutSeconds) * time.Second),
 	}
 }
 
@@ -45,10 +45,15 @@ func TestGetManifest(t *testing.T) {
 			ID:       uuid.New(),
 			Username: "cool-user",
 		}
+		template = database.Template{
+			ID:               uuid.New(),
+			SessionRecording: true,
+		}
 		workspace = database.Workspace{
-			ID:      uuid.New(),
-			OwnerID: owner.ID,
-			Name:    "cool-workspace",
+			ID:         uuid.New(),
+			OwnerID:    owner.ID,
+			TemplateID: template.ID,
+			Name:       "cool-workspace",
 		}
 		agent = database.WorkspaceAgent{
 			ID:   uuid.New(),
@@ -118,6 +123,7 @@ func TestGetManifest(t *testing.T) {
 				RunOnStart:       true,
 				RunOnStop:        false,
 				TimeoutSeconds:   60,
+				DisplayName:      "cool script 1",
 			},
 			{
 				ID:               uuid.New(),
@@ -130,6 +136,12 @@ func TestGetManifest(t *testing.T) {
 				RunOnStart:       false,
 				RunOnStop:        true,
 				TimeoutSeconds:   30,
+				DisplayName:      "cool script 2",
+				DependsOn:        []string{"cool script 1"},
+
+				ReadinessProbeTCP:             "localhost:8080",
+				ReadinessProbeIntervalSeconds: 1,
+				ReadinessProbeTimeoutSeconds:  120,
 			},
 		}
 		metadata = []database.WorkspaceAgentMetadatum{
@@ -171,6 +183,14 @@ func TestGetManifest(t *testing.T) {
 				ConfigPath:       "/another/cool/folder/.devcontainer/devcontainer.json",
 			},
 		}
+		portForwardingPolicy = database.WorkspaceAgentPortForwardingPolicy{
+			AgentID:            agent.ID,
+			AllowedPorts:       []string{"3000-3999"},
+			DeniedPorts:        []string{"3306"},
+			AllowedUnixSockets: []string{},
+			DeniedUnixSockets:  []string{"/var/run/docker.sock"},
+			CreatedAt:          someTime,
+		}
 		derpMapFn = func() *tailcfg.DERPMap {
 			return &tailcfg.DERPMap{
 				Regions: map[int]*tailcfg.DERPRegion{
@@ -253,6 +273,7 @@ func TestGetManifest(t *testing.T) {
 				RunOnStop:        scripts[0].RunOnStop,
 				StartBlocksLogin: scripts[0].StartBlocksLogin,
 				Timeout:          durationpb.New(time.Duration(scripts[0].TimeoutSeconds) * time.Second),
+				DisplayName:      scripts[0].DisplayName,
 			},
 			{
 				Id:               scripts[1].ID[:],
@@ -264,6 +285,13 @@ func TestGetManifest(t *testing.T) {
 				RunOnStop:        scripts[1].RunOnStop,
 				StartBlocksLogin: scripts[1].StartBlocksLogin,
 				Timeout:          durationpb.New(time.Duration(scripts[1].TimeoutSeconds) * time.Second),
+				DisplayName:      scripts[1].DisplayName,
+				DependsOn:        scripts[1].DependsOn,
+				ReadinessProbe: &agentproto.WorkspaceAgentScript_ReadinessProbe{
+					Tcp:      "localhost:8080",
+					Interval: durationpb.New(time.Second),
+					Timeout:  durationpb.New(2 * time.Minute),
+				},
 			},
 		}
 		protoMetadata = []*agentproto.WorkspaceAgentMetadata_Description{
@@ -328,8 +356,10 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
+		mDB.EXPECT().GetWorkspaceAgentPortForwardingPolicy(gomock.Any(), agent.ID).Return(portForwardingPolicy, nil)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().GetUserByID(gomock.Any(), workspace.OwnerID).Return(owner, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -347,6 +377,13 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecording:         true,
+			PortForwardingPolicy: &agentproto.PortForwardingPolicy{
+				AllowedPorts:       portForwardingPolicy.AllowedPorts,
+				DeniedPorts:        portForwardingPolicy.DeniedPorts,
+				AllowedUnixSockets: portForwardingPolicy.AllowedUnixSockets,
+				DeniedUnixSockets:  portForwardingPolicy.DeniedUnixSockets,
+			},
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
@@ -395,8 +432,10 @@ func TestGetManifest(t *testing.T) {
 			Keys:             nil, // all
 		}).Return(metadata, nil)
 		mDB.EXPECT().GetWorkspaceAgentDevcontainersByAgentID(gomock.Any(), agent.ID).Return(devcontainers, nil)
+		mDB.EXPECT().GetWorkspaceAgentPortForwardingPolicy(gomock.Any(), agent.ID).Return(database.WorkspaceAgentPortForwardingPolicy{}, sql.ErrNoRows)
 		mDB.EXPECT().GetWorkspaceByID(gomock.Any(), workspace.ID).Return(workspace, nil)
 		mDB.EXPECT().GetUserByID(gomock.Any(), workspace.OwnerID).Return(owner, nil)
+		mDB.EXPECT().GetTemplateByID(gomock.Any(), template.ID).Return(template, nil)
 
 		got, err := api.GetManifest(context.Background(), &agentproto.GetManifestRequest{})
 		require.NoError(t, err)
@@ -414,6 +453,7 @@ func TestGetManifest(t *testing.T) {
 			MotdPath:                 agent.MOTDFile,
 			DisableDirectConnections: true,
 			DerpForceWebsockets:      true,
+			SessionRecording:         true,
 			// tailnet.DERPMapToProto() is extensively tested elsewhere, so it's
 			// not necessary to manually recreate a big DERP map here like we
 			// did for apps and metadata.
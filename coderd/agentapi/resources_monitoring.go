@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -46,7 +47,12 @@ func (a *ResourcesMonitoringAPI) GetResourcesMonitoringConfiguration(ctx context
 		return nil, xerrors.Errorf("failed to fetch volume resource monitors: %w", err)
 	}
 
-	return &proto.GetResourcesMonitoringConfigurationResponse{
+	monitors, err := a.Database.FetchResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fetch resource monitors: %w", err)
+	}
+
+	res := &proto.GetResourcesMonitoringConfigurationResponse{
 		Config: &proto.GetResourcesMonitoringConfigurationResponse_Config{
 			CollectionIntervalSeconds: int32(a.Config.CollectionInterval.Seconds()),
 			NumDatapoints:             a.Config.NumDatapoints,
@@ -71,7 +77,21 @@ func (a *ResourcesMonitoringAPI) GetResourcesMonitoringConfiguration(ctx context
 
 			return volumes
 		}(),
-	}, nil
+	}
+	for _, monitor := range monitors {
+		switch monitor.Type {
+		case database.WorkspaceAgentResourceMonitorTypeCPU:
+			res.Cpu = &proto.GetResourcesMonitoringConfigurationResponse_CPU{Enabled: monitor.Enabled}
+		case database.WorkspaceAgentResourceMonitorTypeNetwork:
+			res.Network = &proto.GetResourcesMonitoringConfigurationResponse_Network{Enabled: monitor.Enabled}
+		case database.WorkspaceAgentResourceMonitorTypeFileDescriptors:
+			res.FileDescriptors = &proto.GetResourcesMonitoringConfigurationResponse_FileDescriptors{Enabled: monitor.Enabled}
+		case database.WorkspaceAgentResourceMonitorTypeProcesses:
+			res.Processes = &proto.GetResourcesMonitoringConfigurationResponse_Processes{Enabled: monitor.Enabled}
+		}
+	}
+
+	return res, nil
 }
 
 func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Context, req *proto.PushResourcesMonitoringUsageRequest) (*proto.PushResourcesMonitoringUsageResponse, error) {
@@ -85,6 +105,10 @@ func (a *ResourcesMonitoringAPI) PushResourcesMonitoringUsage(ctx context.Contex
 		err = errors.Join(err, xerrors.Errorf("monitor volume: %w", volumeErr))
 	}
 
+	if resourcesErr := a.monitorResources(ctx, req.Datapoints); resourcesErr != nil {
+		err = errors.Join(err, xerrors.Errorf("monitor resources: %w", resourcesErr))
+	}
+
 	return &proto.PushResourcesMonitoringUsageResponse{}, err
 }
 
@@ -260,3 +284,110 @@ func (a *ResourcesMonitoringAPI) monitorVolumes(ctx context.Context, datapoints
 
 	return nil
 }
+
+// monitorResources handles the monitors that have a single threshold per agent,
+// all monitors that enter an alert state are reported in one notification.
+func (a *ResourcesMonitoringAPI) monitorResources(ctx context.Context, datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint) error {
+	monitors, err := a.Database.FetchResourceMonitorsByAgentID(ctx, a.AgentID)
+	if err != nil {
+		return xerrors.Errorf("fetch resource monitors: %w", err)
+	}
+
+	highUsageMonitors := make([]map[string]any, 0)
+
+	for _, monitor := range monitors {
+		if !monitor.Enabled {
+			continue
+		}
+
+		var (
+			usageStates []resourcesmonitor.State
+			resource    string
+			threshold   = fmt.Sprintf("%d%%", monitor.Threshold)
+		)
+		switch monitor.Type {
+		case database.WorkspaceAgentResourceMonitorTypeCPU:
+			usageStates = resourcesmonitor.CalculateCPUUsageStates(monitor, usages(datapoints, (*proto.PushResourcesMonitoringUsageRequest_Datapoint).GetCpu))
+			resource = "CPU usage"
+		case database.WorkspaceAgentResourceMonitorTypeNetwork:
+			usageStates = resourcesmonitor.CalculateNetworkUsageStates(monitor, usages(datapoints, (*proto.PushResourcesMonitoringUsageRequest_Datapoint).GetNetwork))
+			resource = "Network throughput"
+			threshold = fmt.Sprintf("%d Mbit/s", monitor.Threshold)
+		case database.WorkspaceAgentResourceMonitorTypeFileDescriptors:
+			usageStates = resourcesmonitor.CalculateFileDescriptorsUsageStates(monitor, usages(datapoints, (*proto.PushResourcesMonitoringUsageRequest_Datapoint).GetFileDescriptors))
+			resource = "File descriptor usage"
+		case database.WorkspaceAgentResourceMonitorTypeProcesses:
+			usageStates = resourcesmonitor.CalculateProcessesUsageStates(monitor, usages(datapoints, (*proto.PushResourcesMonitoringUsageRequest_Datapoint).GetProcesses))
+			resource = "Process count"
+			threshold = strconv.FormatInt(int64(monitor.Threshold), 10)
+		default:
+			continue
+		}
+
+		oldState := monitor.State
+		newState := resourcesmonitor.NextState(a.Config, oldState, usageStates)
+
+		debouncedUntil, shouldNotify := monitor.Debounce(a.Debounce, a.Clock.Now(), oldState, newState)
+
+		if shouldNotify {
+			highUsageMonitors = append(highUsageMonitors, map[string]any{
+				"resource":  resource,
+				"threshold": threshold,
+			})
+		}
+
+		//nolint:gocritic // We need to be able to update the resource monitor here.
+		if err := a.Database.UpdateResourceMonitor(dbauthz.AsResourceMonitor(ctx), database.UpdateResourceMonitorParams{
+			AgentID:        a.AgentID,
+			Type:           monitor.Type,
+			State:          newState,
+			UpdatedAt:      dbtime.Time(a.Clock.Now()),
+			DebouncedUntil: dbtime.Time(debouncedUntil),
+		}); err != nil {
+			return xerrors.Errorf("update workspace monitor: %w", err)
+		}
+	}
+
+	if len(highUsageMonitors) == 0 {
+		return nil
+	}
+
+	workspace, err := a.Database.GetWorkspaceByID(ctx, a.WorkspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace by id: %w", err)
+	}
+
+	if _, err := a.NotificationsEnqueuer.EnqueueWithData(
+		// nolint:gocritic // We need to be able to send the notification.
+		dbauthz.AsNotifier(ctx),
+		workspace.OwnerID,
+		notifications.TemplateWorkspaceHighResourceUsage,
+		map[string]string{
+			"workspace": workspace.Name,
+		},
+		map[string]any{
+			"monitors": highUsageMonitors,
+			// See monitorMemory, the timestamp circumvents the
+			// deduplication of notifications within a day.
+			"timestamp": a.Clock.Now(),
+		},
+		"workspace-monitor-resources",
+		workspace.ID,
+		workspace.OwnerID,
+		workspace.OrganizationID,
+	); err != nil {
+		return xerrors.Errorf("notify workspace high resource usage: %w", err)
+	}
+
+	return nil
+}
+
+// usages returns the usage of a single resource in each datapoint, it is nil
+// for datapoints where the agent failed to collect it.
+func usages[T any](datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint, get func(*proto.PushResourcesMonitoringUsageRequest_Datapoint) *T) []*T {
+	usages := make([]*T, 0, len(datapoints))
+	for _, datapoint := range datapoints {
+		usages = append(usages, get(datapoint))
+	}
+	return usages
+}
@@ -942,3 +942,194 @@ func requireVolumeData(t *testing.T, notif *notificationstest.FakeNotification)
 
 	return volumesData.([]map[string]any)
 }
+
+func TestResourceMonitor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		monitorType   database.WorkspaceAgentResourceMonitorType
+		threshold     int32
+		datapoint     *agentproto.PushResourcesMonitoringUsageRequest_Datapoint
+		expectState   database.WorkspaceAgentMonitorState
+		expectMonitor map[string]any
+	}{
+		{
+			name:        "CPU/OK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeCPU,
+			threshold:   90,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Cpu: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{Used: 1500, Total: 2000},
+			},
+			expectState: database.WorkspaceAgentMonitorStateOK,
+		},
+		{
+			name:        "CPU/NOK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeCPU,
+			threshold:   90,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Cpu: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{Used: 1900, Total: 2000},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "CPU usage", "threshold": "90%"},
+		},
+		{
+			name:        "Network/OK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeNetwork,
+			threshold:   100,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				// 96 Mbit/s.
+				Network: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage{ReceivedBytesPerSecond: 12_000_000, TransmittedBytesPerSecond: 1_000},
+			},
+			expectState: database.WorkspaceAgentMonitorStateOK,
+		},
+		{
+			name:        "Network/NOK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeNetwork,
+			threshold:   100,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				// 104 Mbit/s.
+				Network: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage{ReceivedBytesPerSecond: 1_000, TransmittedBytesPerSecond: 13_000_000},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "Network throughput", "threshold": "100 Mbit/s"},
+		},
+		{
+			name:        "FileDescriptors/NOK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeFileDescriptors,
+			threshold:   80,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				FileDescriptors: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_FileDescriptorsUsage{Used: 900, Total: 1000},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "File descriptor usage", "threshold": "80%"},
+		},
+		{
+			name:        "Processes/OK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeProcesses,
+			threshold:   4000,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				// The threshold is a count, 3500 of 4096 would be over 80%.
+				Processes: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{Used: 3500, Total: 4096},
+			},
+			expectState: database.WorkspaceAgentMonitorStateOK,
+		},
+		{
+			name:        "Processes/NOK",
+			monitorType: database.WorkspaceAgentResourceMonitorTypeProcesses,
+			threshold:   3000,
+			datapoint: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+				Processes: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{Used: 3500},
+			},
+			expectState:   database.WorkspaceAgentMonitorStateNOK,
+			expectMonitor: map[string]any{"resource": "Process count", "threshold": "3000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			api, user, clock, notifyEnq := resourceMonitorAPI(t)
+			api.Config.Alert.ConsecutiveNOKsPercent = 100
+
+			// Given: A monitor in an OK state
+			dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+				AgentID:   api.AgentID,
+				Type:      tt.monitorType,
+				State:     database.WorkspaceAgentMonitorStateOK,
+				Threshold: tt.threshold,
+			})
+
+			// When: The agent pushes a datapoint
+			tt.datapoint.CollectedAt = timestamppb.New(clock.Now())
+			_, err := api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+				Datapoints: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint{tt.datapoint},
+			})
+			require.NoError(t, err)
+
+			// Then: The monitor is in the expected state, and a
+			// notification is sent when it moved into an alert state.
+			monitors, err := api.Database.FetchResourceMonitorsByAgentID(context.Background(), api.AgentID)
+			require.NoError(t, err)
+			require.Len(t, monitors, 1)
+			require.Equal(t, tt.expectState, monitors[0].State)
+
+			sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceHighResourceUsage))
+			if tt.expectMonitor == nil {
+				require.Len(t, sent, 0)
+				return
+			}
+			require.Len(t, sent, 1)
+			require.Equal(t, user.ID, sent[0].UserID)
+			require.Equal(t, []map[string]any{tt.expectMonitor}, requireMonitorData(t, sent[0]))
+		})
+	}
+}
+
+func TestResourceMonitorMultiple(t *testing.T) {
+	t.Parallel()
+
+	api, _, clock, notifyEnq := resourceMonitorAPI(t)
+	api.Config.Alert.ConsecutiveNOKsPercent = 100
+
+	// Given: CPU and process monitors in an OK state, and a disabled
+	// file descriptor monitor.
+	dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+		AgentID:   api.AgentID,
+		Type:      database.WorkspaceAgentResourceMonitorTypeCPU,
+		State:     database.WorkspaceAgentMonitorStateOK,
+		Threshold: 80,
+	})
+	dbgen.WorkspaceAgentResourceMonitor(t, api.Database, database.WorkspaceAgentResourceMonitor{
+		AgentID:   api.AgentID,
+		Type:      database.WorkspaceAgentResourceMonitorTypeProcesses,
+		State:     database.WorkspaceAgentMonitorStateOK,
+		Threshold: 80,
+	})
+	_, err := api.Database.InsertResourceMonitor(context.Background(), database.InsertResourceMonitorParams{
+		AgentID:   api.AgentID,
+		Type:      database.WorkspaceAgentResourceMonitorTypeFileDescriptors,
+		Enabled:   false,
+		State:     database.WorkspaceAgentMonitorStateOK,
+		Threshold: 80,
+	})
+	require.NoError(t, err)
+
+	config, err := api.GetResourcesMonitoringConfiguration(context.Background(), &agentproto.GetResourcesMonitoringConfigurationRequest{})
+	require.NoError(t, err)
+	require.True(t, config.GetCpu().GetEnabled())
+	require.True(t, config.GetProcesses().GetEnabled())
+	require.False(t, config.GetFileDescriptors().GetEnabled())
+	require.Nil(t, config.Network)
+
+	// When: all of them exceed their threshold
+	_, err = api.PushResourcesMonitoringUsage(context.Background(), &agentproto.PushResourcesMonitoringUsageRequest{
+		Datapoints: []*agentproto.PushResourcesMonitoringUsageRequest_Datapoint{
+			{
+				CollectedAt:     timestamppb.New(clock.Now()),
+				Cpu:             &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage{Used: 1000, Total: 1000},
+				Processes:       &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage{Used: 100, Total: 100},
+				FileDescriptors: &agentproto.PushResourcesMonitoringUsageRequest_Datapoint_FileDescriptorsUsage{Used: 100, Total: 100},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Then: We expect a single notification about the enabled monitors
+	sent := notifyEnq.Sent(notificationstest.WithTemplateID(notifications.TemplateWorkspaceHighResourceUsage))
+	require.Len(t, sent, 1)
+
+	monitors := requireMonitorData(t, sent[0])
+	require.Len(t, monitors, 2)
+	require.ElementsMatch(t, []any{"CPU usage", "Process count"}, []any{monitors[0]["resource"], monitors[1]["resource"]})
+}
+
+func requireMonitorData(t *testing.T, notif *notificationstest.FakeNotification) []map[string]any {
+	t.Helper()
+
+	monitorsData := notif.Data["monitors"]
+	require.IsType(t, []map[string]any{}, monitorsData)
+
+	return monitorsData.([]map[string]any)
+}
@@ -88,6 +88,84 @@ func CalculateVolumeUsageStates(
 	return states
 }
 
+func CalculateCPUUsageStates(
+	monitor database.WorkspaceAgentResourceMonitor,
+	datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage,
+) []State {
+	return calculateUsageStates(datapoints, func(datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint_CPUUsage) State {
+		return percentState(monitor.Threshold, datapoint.Used, datapoint.Total)
+	})
+}
+
+// CalculateNetworkUsageStates compares the throughput in either direction to
+// the threshold of the monitor, which is in Mbit/s.
+func CalculateNetworkUsageStates(
+	monitor database.WorkspaceAgentResourceMonitor,
+	datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage,
+) []State {
+	return calculateUsageStates(datapoints, func(datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint_NetworkUsage) State {
+		bytesPerSecond := max(datapoint.ReceivedBytesPerSecond, datapoint.TransmittedBytesPerSecond)
+		if bytesPerSecond*8 < int64(monitor.Threshold)*1_000_000 {
+			return StateOK
+		}
+		return StateNOK
+	})
+}
+
+func CalculateFileDescriptorsUsageStates(
+	monitor database.WorkspaceAgentResourceMonitor,
+	datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint_FileDescriptorsUsage,
+) []State {
+	return calculateUsageStates(datapoints, func(datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint_FileDescriptorsUsage) State {
+		return percentState(monitor.Threshold, datapoint.Used, datapoint.Total)
+	})
+}
+
+// CalculateProcessesUsageStates compares the number of processes to the
+// threshold of the monitor, which is an absolute count and not a percentage
+// of the process limit.
+func CalculateProcessesUsageStates(
+	monitor database.WorkspaceAgentResourceMonitor,
+	datapoints []*proto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage,
+) []State {
+	return calculateUsageStates(datapoints, func(datapoint *proto.PushResourcesMonitoringUsageRequest_Datapoint_ProcessesUsage) State {
+		if datapoint.Used < int64(monitor.Threshold) {
+			return StateOK
+		}
+		return StateNOK
+	})
+}
+
+// calculateUsageStates returns the state of each datapoint, datapoints that
+// failed to be collected are nil and have an unknown state.
+func calculateUsageStates[T any](datapoints []*T, state func(datapoint *T) State) []State {
+	states := make([]State, 0, len(datapoints))
+
+	for _, datapoint := range datapoints {
+		if datapoint == nil {
+			states = append(states, StateUnknown)
+			continue
+		}
+
+		states = append(states, state(datapoint))
+	}
+
+	return states
+}
+
+func percentState(threshold int32, used, total int64) State {
+	// A missing total cannot be compared to the threshold.
+	if total <= 0 {
+		return StateUnknown
+	}
+
+	percent := int32(float64(used) / float64(total) * 100)
+	if percent < threshold {
+		return StateOK
+	}
+	return StateNOK
+}
+
 func NextState(c Config, oldState database.WorkspaceAgentMonitorState, states []State) database.WorkspaceAgentMonitorState {
 	// If there are enough consecutive NOK states, we should be in an
 	// alert state.
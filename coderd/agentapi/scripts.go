@@ -45,12 +45,20 @@ func (s *ScriptsAPI) ScriptCompleted(ctx context.Context, req *agentproto.Worksp
 		status = database.WorkspaceAgentScriptTimingStatusPipesLeftOpen
 	}
 
+	// Agents before API v2.5 don't report when the script was queued, they
+	// start scripts immediately.
+	queuedAt := req.Timing.Start.AsTime()
+	if req.Timing.Queued != nil {
+		queuedAt = req.Timing.Queued.AsTime()
+	}
+
 	//nolint:gocritic // We need permissions to write to the DB here and we are in the context of the agent.
 	ctx = dbauthz.AsProvisionerd(ctx)
 	_, err = s.Database.InsertWorkspaceAgentScriptTimings(ctx, database.InsertWorkspaceAgentScriptTimingsParams{
 		ScriptID:  scriptID,
 		Stage:     stage,
 		Status:    status,
+		QueuedAt:  queuedAt,
 		StartedAt: req.Timing.Start.AsTime(),
 		EndedAt:   req.Timing.End.AsTime(),
 		ExitCode:  req.Timing.ExitCode,
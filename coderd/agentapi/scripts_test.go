@@ -73,17 +73,35 @@ func TestScriptCompleted(t *testing.T) {
 				ExitCode: 1,
 			},
 		},
+		{
+			scriptID: uuid.New(),
+			timing: &agentproto.Timing{
+				Stage:    agentproto.Timing_START,
+				Queued:   timestamppb.New(dbtime.Now()),
+				Start:    timestamppb.New(dbtime.Now().Add(time.Second)),
+				End:      timestamppb.New(dbtime.Now().Add(2 * time.Second)),
+				Status:   agentproto.Timing_OK,
+				ExitCode: 0,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		// Setup the script ID
 		tt.timing.ScriptId = tt.scriptID[:]
 
+		// Scripts that did not wait are queued when they start.
+		queuedAt := tt.timing.Start.AsTime()
+		if tt.timing.Queued != nil {
+			queuedAt = tt.timing.Queued.AsTime()
+		}
+
 		mDB := dbmock.NewMockStore(gomock.NewController(t))
 		mDB.EXPECT().InsertWorkspaceAgentScriptTimings(gomock.Any(), database.InsertWorkspaceAgentScriptTimingsParams{
 			ScriptID:  tt.scriptID,
 			Stage:     protoScriptTimingStageToDatabase(tt.timing.Stage),
 			Status:    protoScriptTimingStatusToDatabase(tt.timing.Status),
+			QueuedAt:  queuedAt,
 			StartedAt: tt.timing.Start.AsTime(),
 			EndedAt:   tt.timing.End.AsTime(),
 			ExitCode:  tt.timing.ExitCode,
@@ -0,0 +1,113 @@
+package agentapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"cdr.dev/slog"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+type SessionRecordingsAPI struct {
+	AgentFn     func(context.Context) (database.WorkspaceAgent, error)
+	WorkspaceID uuid.UUID
+	Database    database.Store
+	Log         slog.Logger
+}
+
+// UploadSessionRecording stores a chunk of a session recording. An upload
+// with offset zero starts the recording over, subsequent chunks are
+// appended and must continue where the previous chunk ended.
+func (a *SessionRecordingsAPI) UploadSessionRecording(ctx context.Context, req *agentproto.UploadSessionRecordingRequest) (*emptypb.Empty, error) {
+	recordingID, err := uuid.FromBytes(req.GetId())
+	if err != nil {
+		return nil, xerrors.Errorf("recording id from bytes: %w", err)
+	}
+	if req.GetOffset() < 0 {
+		return nil, xerrors.Errorf("invalid offset %d", req.GetOffset())
+	}
+
+	workspaceAgent, err := a.AgentFn(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get agent: %w", err)
+	}
+
+	if req.GetOffset() > 0 {
+		err := insertSessionRecordingChunk(ctx, a.Database, recordingID, workspaceAgent.ID, req.GetOffset(), req.GetData())
+		if err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	}
+
+	connectionID, err := uuid.FromBytes(req.GetConnectionId())
+	if err != nil {
+		return nil, xerrors.Errorf("connection id from bytes: %w", err)
+	}
+	connectionType, err := agentsdk.ConnectionTypeFromProto(req.GetConnectionType())
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.Database.InTx(func(tx database.Store) error {
+		n, err := tx.UpsertWorkspaceSessionRecording(ctx, database.UpsertWorkspaceSessionRecordingParams{
+			ID:             recordingID,
+			WorkspaceID:    a.WorkspaceID,
+			AgentID:        workspaceAgent.ID,
+			ConnectionID:   connectionID,
+			ConnectionType: string(connectionType),
+			StartedAt:      dbtime.Time(req.GetStartedAt().AsTime()),
+			EndedAt:        dbtime.Time(req.GetEndedAt().AsTime()),
+			CreatedAt:      dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert session recording: %w", err)
+		}
+		if n == 0 {
+			return xerrors.Errorf("session recording %s belongs to another agent", recordingID)
+		}
+		err = tx.DeleteWorkspaceSessionRecordingChunks(ctx, database.DeleteWorkspaceSessionRecordingChunksParams{
+			RecordingID: recordingID,
+			AgentID:     workspaceAgent.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete session recording chunks: %w", err)
+		}
+		return insertSessionRecordingChunk(ctx, tx, recordingID, workspaceAgent.ID, 0, req.GetData())
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.Log.Debug(ctx, "session recording upload started",
+		slog.F("recording_id", recordingID),
+		slog.F("connection_id", connectionID),
+		slog.F("connection_type", connectionType),
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+// insertSessionRecordingChunk stores a chunk of a recording as its own row.
+// Chunks are never rewritten, so the cost of an upload does not grow with the
+// size of the recording.
+func insertSessionRecordingChunk(ctx context.Context, db database.Store, recordingID, agentID uuid.UUID, offset int64, data []byte) error {
+	n, err := db.InsertWorkspaceSessionRecordingChunk(ctx, database.InsertWorkspaceSessionRecordingChunkParams{
+		Data:        data,
+		RecordingID: recordingID,
+		AgentID:     agentID,
+		OffsetBytes: offset,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert session recording chunk: %w", err)
+	}
+	if n == 0 {
+		return xerrors.Errorf("session recording %s not found at offset %d", recordingID, offset)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package agentapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	agentproto "github.com/coder/coder/v2/agent/proto"
+	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmock"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestUploadSessionRecording(t *testing.T) {
+	t.Parallel()
+
+	var (
+		workspaceID  = uuid.New()
+		agent        = database.WorkspaceAgent{ID: uuid.New()}
+		recordingID  = uuid.New()
+		connectionID = uuid.New()
+		startedAt    = dbtime.Now().Add(-time.Minute)
+		endedAt      = dbtime.Now()
+	)
+
+	newAPI := func(t *testing.T, db database.Store) *agentapi.SessionRecordingsAPI {
+		return &agentapi.SessionRecordingsAPI{
+			AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+				return agent, nil
+			},
+			WorkspaceID: workspaceID,
+			Database:    db,
+			Log:         slogtest.Make(t, nil),
+		}
+	}
+
+	t.Run("FirstChunk", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+		mDB.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(f func(database.Store) error, _ *database.TxOptions) error {
+			return f(mDB)
+		})
+		mDB.EXPECT().UpsertWorkspaceSessionRecording(gomock.Any(), gomock.Cond(func(arg database.UpsertWorkspaceSessionRecordingParams) bool {
+			return arg.ID == recordingID &&
+				arg.WorkspaceID == workspaceID &&
+				arg.AgentID == agent.ID &&
+				arg.ConnectionID == connectionID &&
+				arg.ConnectionType == string(agentsdk.ConnectionTypeSSH) &&
+				arg.StartedAt.Equal(startedAt) &&
+				arg.EndedAt.Equal(endedAt)
+		})).Return(int64(1), nil)
+		mDB.EXPECT().DeleteWorkspaceSessionRecordingChunks(gomock.Any(), database.DeleteWorkspaceSessionRecordingChunksParams{
+			RecordingID: recordingID,
+			AgentID:     agent.ID,
+		}).Return(nil)
+		mDB.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), database.InsertWorkspaceSessionRecordingChunkParams{
+			Data:        []byte("header\n"),
+			RecordingID: recordingID,
+			AgentID:     agent.ID,
+			OffsetBytes: 0,
+		}).Return(int64(1), nil)
+
+		_, err := newAPI(t, mDB).UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:             recordingID[:],
+			ConnectionId:   connectionID[:],
+			ConnectionType: agentproto.Connection_SSH,
+			StartedAt:      timestamppb.New(startedAt),
+			EndedAt:        timestamppb.New(endedAt),
+			Offset:         0,
+			Data:           []byte("header\n"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("NextChunk", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+		mDB.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), database.InsertWorkspaceSessionRecordingChunkParams{
+			Data:        []byte("event\n"),
+			RecordingID: recordingID,
+			AgentID:     agent.ID,
+			OffsetBytes: 7,
+		}).Return(int64(1), nil)
+
+		_, err := newAPI(t, mDB).UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:             recordingID[:],
+			ConnectionId:   connectionID[:],
+			ConnectionType: agentproto.Connection_SSH,
+			Offset:         7,
+			Data:           []byte("event\n"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OffsetMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+		mDB.EXPECT().InsertWorkspaceSessionRecordingChunk(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+		_, err := newAPI(t, mDB).UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:     recordingID[:],
+			Offset: 1024,
+			Data:   []byte("event\n"),
+		})
+		require.ErrorContains(t, err, "not found at offset 1024")
+	})
+
+	t.Run("OtherAgent", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+		mDB.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(f func(database.Store) error, _ *database.TxOptions) error {
+			return f(mDB)
+		})
+		mDB.EXPECT().UpsertWorkspaceSessionRecording(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+		_, err := newAPI(t, mDB).UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:             recordingID[:],
+			ConnectionId:   connectionID[:],
+			ConnectionType: agentproto.Connection_SSH,
+			Offset:         0,
+			Data:           []byte("header\n"),
+		})
+		require.ErrorContains(t, err, "belongs to another agent")
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		t.Parallel()
+
+		mDB := dbmock.NewMockStore(gomock.NewController(t))
+
+		_, err := newAPI(t, mDB).UploadSessionRecording(context.Background(), &agentproto.UploadSessionRecordingRequest{
+			Id:   []byte("invalid"),
+			Data: []byte("header\n"),
+		})
+		require.ErrorContains(t, err, "recording id from bytes")
+	})
+}
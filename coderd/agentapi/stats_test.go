@@ -541,6 +541,90 @@ func TestUpdateStates(t *testing.T) {
 		}
 		require.True(t, updateAgentMetricsFnCalled)
 	})
+	t.Run("UserActivity", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tt := range []struct {
+			name         string
+			lastActivity time.Duration
+			userActivity bool
+		}{
+			{name: "Recent", lastActivity: 5 * time.Second, userActivity: true},
+			{name: "Stale", lastActivity: time.Hour, userActivity: false},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				var (
+					now = dbtime.Now()
+					dbM = dbmock.NewMockStore(gomock.NewController(t))
+
+					templateScheduleStore = schedule.MockTemplateScheduleStore{
+						GetFn: func(context.Context, database.Store, uuid.UUID) (schedule.TemplateScheduleOptions, error) {
+							panic("should not be called")
+						},
+						SetFn: func(context.Context, database.Store, database.Template, schedule.TemplateScheduleOptions) (database.Template, error) {
+							panic("not implemented")
+						},
+					}
+					tickCh  = make(chan time.Time)
+					flushCh = make(chan int, 1)
+					wut     = workspacestats.NewTracker(dbM,
+						workspacestats.TrackerWithTickFlush(tickCh, flushCh),
+					)
+
+					req = &agentproto.UpdateStatsRequest{
+						Stats: &agentproto.Stats{
+							ConnectionsByProto:    map[string]int64{"tcp": 1},
+							ConnectionCount:       1,
+							TimeSinceLastActivity: durationpb.New(tt.lastActivity),
+						},
+					}
+				)
+				api := agentapi.StatsAPI{
+					AgentFn: func(context.Context) (database.WorkspaceAgent, error) {
+						return agent, nil
+					},
+					Database: dbM,
+					StatsReporter: workspacestats.NewReporter(workspacestats.ReporterOptions{
+						Database:              dbM,
+						Pubsub:                pubsub.NewInMemory(),
+						StatsBatcher:          &workspacestatstest.StatsBatcher{},
+						UsageTracker:          wut,
+						TemplateScheduleStore: templateScheduleStorePtr(templateScheduleStore),
+						UserActivityWindow:    time.Minute,
+					}),
+					AgentStatsRefreshInterval: 30 * time.Second,
+					TimeNowFn: func() time.Time {
+						return now
+					},
+				}
+				defer wut.Close()
+
+				dbM.EXPECT().GetWorkspaceByAgentID(gomock.Any(), agent.ID).Return(workspace, nil)
+
+				// Templates that require user activity are only bumped if the
+				// agent saw the user within the window.
+				dbM.EXPECT().ActivityBumpWorkspace(gomock.Any(), database.ActivityBumpWorkspaceParams{
+					WorkspaceID:   workspace.ID,
+					NextAutostart: time.Time{}.UTC(),
+					UserActivity:  tt.userActivity,
+				}).Return(nil)
+
+				dbM.EXPECT().BatchUpdateWorkspaceLastUsedAt(gomock.Any(), database.BatchUpdateWorkspaceLastUsedAtParams{
+					IDs:        []uuid.UUID{workspace.ID},
+					LastUsedAt: now,
+				}).Return(nil)
+
+				_, err := api.UpdateStats(context.Background(), req)
+				require.NoError(t, err)
+
+				tickCh <- now
+				count := <-flushCh
+				require.Equal(t, 1, count, "expected one flush with one id")
+			})
+		}
+	})
 }
 
 func templateScheduleStorePtr(store schedule.TemplateScheduleStore) *atomic.Pointer[schedule.TemplateScheduleStore] {
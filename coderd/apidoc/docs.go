@@ -248,6 +248,157 @@ const docTemplate = `{
                 }
             }
         },
+        "/audit/chain": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log chain",
+                "operationId": "get-audit-log-chain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID, omit for deployment-wide audit logs",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return entries after this sequence",
+                        "name": "after_sequence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AuditLogChainEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/chains": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log chains",
+                "operationId": "get-audit-log-chains",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AuditLogChain"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/checkpoints": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log checkpoints",
+                "operationId": "get-audit-log-checkpoints",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID, omit for deployment-wide audit logs",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return checkpoints after this sequence",
+                        "name": "after_sequence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AuditLogCheckpoint"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/checkpoints/public-key": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log checkpoint public key",
+                "operationId": "get-audit-log-checkpoint-public-key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuditLogPublicKey"
+                        }
+                    }
+                }
+            }
+        },
         "/audit/testgenerate": {
             "post": {
                 "security": [
@@ -4102,6 +4253,86 @@ const docTemplate = `{
                 }
             }
         },
+        "/organizations/{organization}/settings/scim/deprovisioning": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get SCIM deprovisioning settings by organization",
+                "operationId": "get-scim-deprovisioning-settings-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.SCIMDeprovisioningSettings"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update SCIM deprovisioning settings by organization",
+                "operationId": "update-scim-deprovisioning-settings-by-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.SCIMDeprovisioningSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.SCIMDeprovisioningSettings"
+                        }
+                    }
+                }
+            }
+        },
         "/organizations/{organization}/templates": {
             "get": {
                 "security": [
@@ -4498,24 +4729,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/scim/v2/ServiceProviderConfig": {
-            "get": {
-                "produces": [
-                    "application/scim+json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "SCIM 2.0: Service Provider Config",
-                "operationId": "scim-get-service-provider-config",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
-            }
-        },
-        "/scim/v2/Users": {
+        "/scim/v2/Groups": {
             "get": {
                 "security": [
                     {
@@ -4528,11 +4742,34 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
+                "summary": "SCIM 2.0: Get groups",
+                "operationId": "scim-get-groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter, only the displayName eq operator is supported",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ListResponse"
+                        }
                     }
                 }
             },
@@ -4543,35 +4780,35 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "SCIM 2.0: Create new group",
+                "operationId": "scim-create-new-group",
                 "parameters": [
                     {
-                        "description": "New user",
+                        "description": "New group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
+        "/scim/v2/Groups/{id}": {
             "get": {
                 "security": [
                     {
@@ -4584,23 +4821,242 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "SCIM 2.0: Get group by ID",
+                "operationId": "scim-get-group-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
-                    }
-                }
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMGroup"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Replace group",
+                "operationId": "scim-replace-group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replace group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMGroup"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMGroup"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Delete group",
+                "operationId": "scim-delete-group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Update group",
+                "operationId": "scim-update-group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch group request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.PatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMGroup"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/ServiceProviderConfig": {
+            "get": {
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Service Provider Config",
+                "operationId": "scim-get-service-provider-config",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
+                "parameters": [
+                    {
+                        "description": "New user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Authorization": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
             },
             "put": {
                 "security": [
@@ -4687,6 +5143,129 @@ const docTemplate = `{
                 }
             }
         },
+        "/sessionrecordings": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get session recordings",
+                "operationId": "get-session-recordings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Connection ID, the request ID of the connection audit logs",
+                        "name": "connection_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceSessionRecording"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessionrecordings/{sessionrecording}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get session recording by ID",
+                "operationId": "get-session-recording-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Session recording ID",
+                        "name": "sessionrecording",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceSessionRecording"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessionrecordings/{sessionrecording}/cast": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/x-asciicast"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get session recording contents",
+                "operationId": "get-session-recording-contents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Session recording ID",
+                        "name": "sessionrecording",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
         "/settings/idpsync/available-fields": {
             "get": {
                 "security": [
@@ -5537,8 +6116,43 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Archive template version",
-                "operationId": "archive-template-version",
+                "summary": "Archive template version",
+                "operationId": "archive-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/templateversions/{templateversion}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Cancel template version by ID",
+                "operationId": "cancel-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5559,8 +6173,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/templateversions/{templateversion}/cancel": {
-            "patch": {
+        "/templateversions/{templateversion}/diff/{othertemplateversion}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5572,23 +6186,31 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Cancel template version by ID",
-                "operationId": "cancel-template-version-by-id",
+                "summary": "Get diff between template versions",
+                "operationId": "get-diff-between-template-versions",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
+                        "description": "Template version ID to diff from",
                         "name": "templateversion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID to diff to",
+                        "name": "othertemplateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TemplateVersionDiff"
                         }
                     }
                 }
@@ -9679,6 +10301,115 @@ const docTemplate = `{
                 }
             }
         },
+        "/workspaces/{workspace}/acl": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace ACL",
+                "operationId": "get-workspace-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceACL"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace ACL",
+                "operationId": "update-workspace-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update workspace ACL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceACL"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/acl/available": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace available acl users/groups",
+                "operationId": "get-workspace-available-acl-usersgroups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ACLAvailable"
+                        }
+                    }
+                }
+            }
+        },
         "/workspaces/{workspace}/autostart": {
             "put": {
                 "security": [
@@ -10165,10 +10896,172 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ResolveAutostartResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace snapshots",
+                "operationId": "get-workspace-snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceSnapshot"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Create workspace snapshot",
+                "operationId": "create-workspace-snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace snapshot request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceSnapshotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceSnapshot"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/snapshots/{snapshot}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Delete workspace snapshot",
+                "operationId": "delete-workspace-snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot name",
+                        "name": "snapshot",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/snapshots/{snapshot}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Starts a new build of the workspace from the provisioner state,\nparameters and template version recorded in the snapshot. The\nlatest build of the workspace must have stopped or deleted it.\nRestoring requires permission to update the template, like\nany other build with custom state.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Restore workspace snapshot",
+                "operationId": "restore-workspace-snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot name",
+                        "name": "snapshot",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ResolveAutostartResponse"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
                         }
                     }
                 }
@@ -10720,6 +11613,67 @@ const docTemplate = `{
                 "ToolInvocationStateResult"
             ]
         },
+        "coderd.SCIMGroup": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroupMember"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/coderd.SCIMGroupMeta"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMGroupMember": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Value is the ID of the user.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "coderd.SCIMGroupMeta": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "lastModified": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                }
+            }
+        },
         "coderd.SCIMUser": {
             "type": "object",
             "properties": {
@@ -10899,6 +11853,20 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scope_allow_list": {
+                    "description": "ScopeAllowList are the resource:id pairs the key is limited to. An\nempty list allows every resource.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope_permissions": {
+                    "description": "ScopePermissions are the resource:action pairs the key is limited to.\nAn empty list applies no limit beyond the scope.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 },
@@ -10969,6 +11937,11 @@ const docTemplate = `{
                 "exit_code": {
                     "type": "integer"
                 },
+                "queued_at": {
+                    "description": "QueuedAt is when the script was due to run. The script waited for the\nscripts it depends on between QueuedAt and StartedAt.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "stage": {
                     "$ref": "#/definitions/codersdk.TimingStage"
                 },
@@ -11211,6 +12184,143 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AuditLogChain": {
+            "type": "object",
+            "properties": {
+                "organization_deleted": {
+                    "type": "boolean"
+                },
+                "organization_id": {
+                    "description": "OrganizationID is the nil UUID for the deployment-wide chain.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_name": {
+                    "description": "OrganizationName is empty if the organization no longer exists.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AuditLogChainEntry": {
+            "type": "object",
+            "properties": {
+                "audit_log_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "prev_hash": {
+                    "description": "PrevHash and Hash are hex encoded SHA-256 hashes.",
+                    "type": "string"
+                },
+                "record": {
+                    "description": "Record is omitted if the audit log no longer exists.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AuditLogChainRecord"
+                        }
+                    ]
+                },
+                "sequence": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.AuditLogChainRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/codersdk.AuditAction"
+                },
+                "additional_fields": {
+                    "type": "object"
+                },
+                "diff": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "request_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource_icon": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource_target": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.ResourceType"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.AuditLogCheckpoint": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "hash": {
+                    "description": "Hash is the hex encoded hash of the chain entry.",
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "signature": {
+                    "description": "Signature is a hex encoded ed25519 signature over the message returned\nby AuditLogCheckpointMessage.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AuditLogPublicKey": {
+            "type": "object",
+            "properties": {
+                "public_key": {
+                    "description": "PublicKey is a hex encoded ed25519 public key.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AuditLogResponse": {
             "type": "object",
             "properties": {
@@ -11225,6 +12335,102 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AuditLoggingConfig": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "$ref": "#/definitions/codersdk.AuditLoggingFileConfig"
+                },
+                "http": {
+                    "$ref": "#/definitions/codersdk.AuditLoggingHTTPConfig"
+                },
+                "signing_key_file": {
+                    "description": "The PEM encoded ed25519 private key that checkpoints are signed with.",
+                    "type": "string"
+                },
+                "syslog": {
+                    "$ref": "#/definitions/codersdk.AuditLoggingSyslogConfig"
+                }
+            }
+        },
+        "codersdk.AuditLoggingFileConfig": {
+            "type": "object",
+            "properties": {
+                "max_backups": {
+                    "description": "The maximum number of rotated files to retain.",
+                    "type": "integer"
+                },
+                "max_size": {
+                    "description": "The maximum size of the file, in megabytes, before it is rotated.",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "The file to which audit logs are written.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AuditLoggingHTTPConfig": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "description": "The maximum number of audit logs to send in a single request.",
+                    "type": "integer"
+                },
+                "flush_interval": {
+                    "description": "How long to wait for a batch to fill up before sending it anyway.",
+                    "type": "integer"
+                },
+                "headers": {
+                    "description": "Additional headers to send with each request, in \"Name: value\" form.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "queue_dir": {
+                    "description": "The directory in which audit logs are queued until they are sent.",
+                    "type": "string"
+                },
+                "queue_max_size": {
+                    "description": "The maximum size of the queue, in megabytes.",
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "The URL to which batches of audit logs will be sent with an HTTP POST request.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/serpent.URL"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.AuditLoggingSyslogConfig": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The address of the syslog server, in host:port form.",
+                    "type": "string"
+                },
+                "tls": {
+                    "description": "Whether to connect to the syslog server using TLS.",
+                    "type": "boolean"
+                },
+                "tls_ca_file": {
+                    "description": "The CA certificate used to verify the syslog server's certificate.",
+                    "type": "string"
+                },
+                "tls_cert_file": {
+                    "description": "The client certificate used to authenticate with the syslog server.",
+                    "type": "string"
+                },
+                "tls_key_file": {
+                    "description": "The client private key used to authenticate with the syslog server.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AuthMethod": {
             "type": "object",
             "properties": {
@@ -11902,6 +13108,20 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "scope_allow_list": {
+                    "description": "ScopeAllowList limits the token to resource:id pairs, such as\ntemplate:\u003cid\u003e. Allowing a template also allows the user's workspaces\nbuilt from it.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope_permissions": {
+                    "description": "ScopePermissions limits the token to resource:action pairs, such as\nworkspace:read or template:push. They can only be used with the all\nscope.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "token_name": {
                     "type": "string"
                 }
@@ -12075,6 +13295,21 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWorkspaceSnapshotRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "build_number": {
+                    "description": "BuildNumber is the build to take the snapshot from. If omitted, the\nlatest build of the workspace is used.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CryptoKey": {
             "type": "object",
             "properties": {
@@ -12338,6 +13573,9 @@ const docTemplate = `{
                 "allow_workspace_renames": {
                     "type": "boolean"
                 },
+                "audit_logging": {
+                    "$ref": "#/definitions/codersdk.AuditLoggingConfig"
+                },
                 "autobuild_poll_interval": {
                     "type": "integer"
                 },
@@ -13385,6 +14623,9 @@ const docTemplate = `{
         "codersdk.NotificationPreference": {
             "type": "object",
             "properties": {
+                "digest": {
+                    "type": "boolean"
+                },
                 "disabled": {
                     "type": "boolean"
                 },
@@ -13392,6 +14633,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "method": {
+                    "description": "Method overrides the template-level or deployment-level delivery method for this user.",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string",
                     "format": "date-time"
@@ -13407,6 +14652,9 @@ const docTemplate = `{
                 "body_template": {
                     "type": "string"
                 },
+                "digestible": {
+                    "type": "boolean"
+                },
                 "enabled_by_default": {
                     "type": "boolean"
                 },
@@ -13434,6 +14682,10 @@ const docTemplate = `{
         "codersdk.NotificationsConfig": {
             "type": "object",
             "properties": {
+                "digest_interval": {
+                    "description": "How often to send users a digest of the notifications they have chosen to batch.",
+                    "type": "integer"
+                },
                 "dispatch_timeout": {
                     "description": "How long to wait while a notification is being sent before giving up.",
                     "type": "integer"
@@ -13471,13 +14723,21 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "method": {
-                    "description": "Which delivery method to use (available options: 'smtp', 'webhook').",
+                    "description": "Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').",
                     "type": "string"
                 },
                 "retry_interval": {
                     "description": "The minimum time between retries.",
                     "type": "integer"
                 },
+                "slack": {
+                    "description": "Slack settings.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NotificationsSlackConfig"
+                        }
+                    ]
+                },
                 "sync_buffer_size": {
                     "description": "The notifications system buffers message updates in memory to ease pressure on the database.\nThis option controls how many updates are kept in memory. The lower this value the\nlower the change of state inconsistency in a non-graceful shutdown - but it also increases load on the\ndatabase. It is recommended to keep this option at its default value.",
                     "type": "integer"
@@ -13486,6 +14746,14 @@ const docTemplate = `{
                     "description": "The notifications system buffers message updates in memory to ease pressure on the database.\nThis option controls how often it synchronizes its state with the database. The shorter this value the\nlower the change of state inconsistency in a non-graceful shutdown - but it also increases load on the\ndatabase. It is recommended to keep this option at its default value.",
                     "type": "integer"
                 },
+                "teams": {
+                    "description": "Microsoft Teams settings.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NotificationsTeamsConfig"
+                        }
+                    ]
+                },
                 "webhook": {
                     "description": "Webhook settings.",
                     "allOf": [
@@ -13599,6 +14867,48 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.NotificationsSlackConfig": {
+            "type": "object",
+            "properties": {
+                "api_url": {
+                    "description": "The base URL of the Slack Web API.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/serpent.URL"
+                        }
+                    ]
+                },
+                "bot_token": {
+                    "description": "The bot token used to deliver messages directly to users via the Slack Web API.",
+                    "type": "string"
+                },
+                "channel": {
+                    "description": "The channel to post to when a bot token is used and the recipient cannot be found in Slack.",
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "description": "The incoming webhook URL to which messages will be posted.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/serpent.URL"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.NotificationsTeamsConfig": {
+            "type": "object",
+            "properties": {
+                "webhook_url": {
+                    "description": "The URL of the Teams workflow or incoming webhook to which Adaptive Cards will be posted.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/serpent.URL"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.NotificationsWebhookConfig": {
             "type": "object",
             "properties": {
@@ -15170,7 +16480,10 @@ const docTemplate = `{
                 "idp_sync_settings_group",
                 "idp_sync_settings_role",
                 "workspace_agent",
-                "workspace_app"
+                "workspace_app",
+                "workspace_agent_port_share",
+                "scim_group",
+                "workspace_snapshot"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -15196,7 +16509,10 @@ const docTemplate = `{
                 "ResourceTypeIdpSyncSettingsGroup",
                 "ResourceTypeIdpSyncSettingsRole",
                 "ResourceTypeWorkspaceAgent",
-                "ResourceTypeWorkspaceApp"
+                "ResourceTypeWorkspaceApp",
+                "ResourceTypeWorkspaceAgentPortShare",
+                "ResourceTypeSCIMGroup",
+                "ResourceTypeWorkspaceSnapshot"
             ]
         },
         "codersdk.Response": {
@@ -15272,6 +16588,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SCIMDeprovisioningSettings": {
+            "type": "object",
+            "properties": {
+                "delete_after_days": {
+                    "description": "DeleteAfterDays deletes dormant workspaces the given number of days\nafter the user was deactivated. Zero disables deletion. Requires\nMarkDormant, activating a workspace cancels its deletion.",
+                    "type": "integer"
+                },
+                "mark_dormant": {
+                    "description": "MarkDormant marks the workspaces of the user as dormant.",
+                    "type": "boolean"
+                },
+                "stop_workspaces": {
+                    "description": "StopWorkspaces stops every running workspace of the user immediately.",
+                    "type": "boolean"
+                },
+                "transfer_owner_id": {
+                    "description": "TransferOwnerID transfers the workspaces of the user to the given\norganization member. If nil, the workspaces keep their owner.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.SSHConfig": {
             "type": "object",
             "properties": {
@@ -15473,6 +16811,10 @@ const docTemplate = `{
                 "activity_bump_ms": {
                     "type": "integer"
                 },
+                "activity_bump_requires_user_activity": {
+                    "description": "ActivityBumpRequiresUserActivity only bumps the deadline of workspaces\ncreated from this template on user activity seen by the agent, e.g.\ntyping into a terminal, instead of on any open connection.",
+                    "type": "boolean"
+                },
                 "allow_user_autostart": {
                     "description": "AllowUserAutostart and AllowUserAutostop are enterprise-only. Their\nvalues are only used if your license is entitled to use the advanced\ntemplate scheduling feature.",
                     "type": "boolean"
@@ -15564,6 +16906,10 @@ const docTemplate = `{
                     "description": "RequireActiveVersion mandates that workspaces are built with the active\ntemplate version.",
                     "type": "boolean"
                 },
+                "session_recording": {
+                    "description": "SessionRecording enables the recording of interactive SSH and terminal\nsessions in workspaces created from this template.",
+                    "type": "boolean"
+                },
                 "time_til_dormant_autodelete_ms": {
                     "type": "integer"
                 },
@@ -15991,6 +17337,62 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionDiff": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionFileDiff"
+                    }
+                },
+                "from_template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "presets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionItemDiff"
+                    }
+                },
+                "rich_parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionItemDiff"
+                    }
+                },
+                "to_template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionItemDiff"
+                    }
+                },
+                "workspace_tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionItemDiff"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateVersionDiffStatus": {
+            "type": "string",
+            "enum": [
+                "added",
+                "removed",
+                "modified"
+            ],
+            "x-enum-varnames": [
+                "TemplateVersionDiffStatusAdded",
+                "TemplateVersionDiffStatusRemoved",
+                "TemplateVersionDiffStatusModified"
+            ]
+        },
         "codersdk.TemplateVersionExternalAuth": {
             "type": "object",
             "properties": {
@@ -16017,6 +17419,75 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionFieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TemplateVersionFileDiff": {
+            "type": "object",
+            "properties": {
+                "binary": {
+                    "description": "Binary is true if either side of the file is not text, in which case\nDiff is empty.",
+                    "type": "boolean"
+                },
+                "diff": {
+                    "description": "Diff is a unified diff of the file contents.",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "added",
+                        "removed",
+                        "modified"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionDiffStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.TemplateVersionItemDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changes lists the fields of a modified item that differ.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionFieldChange"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "added",
+                        "removed",
+                        "modified"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionDiffStatus"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.TemplateVersionParameter": {
             "type": "object",
             "properties": {
@@ -16349,11 +17820,25 @@ const docTemplate = `{
         "codersdk.UpdateUserNotificationPreferences": {
             "type": "object",
             "properties": {
+                "template_digest_map": {
+                    "description": "TemplateDigestMap sets whether each template is batched into a periodic digest instead of being delivered\nimmediately. Only digestible templates may be batched.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
                 "template_disabled_map": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "boolean"
                     }
+                },
+                "template_method_map": {
+                    "description": "TemplateMethodMap sets the delivery method for each template. An empty method removes the override.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -16397,6 +17882,31 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceACL": {
+            "type": "object",
+            "properties": {
+                "group_roles": {
+                    "description": "GroupRoles is a mapping of group id to role. An empty role removes the\ngroup from the ACL.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.WorkspaceRole"
+                    },
+                    "example": {
+                        "8bd26b20-f3e8-48be-a903-46bb920cf671": "use"
+                    }
+                },
+                "user_roles": {
+                    "description": "UserRoles is a mapping of user id to role. An empty role removes the\nuser from the ACL.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.WorkspaceRole"
+                    },
+                    "example": {
+                        "4df59e74-c027-470b-ab4d-cbba8963a5e9": "use"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutomaticUpdatesRequest": {
             "type": "object",
             "properties": {
@@ -16453,6 +17963,11 @@ const docTemplate = `{
                 "agent_name": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "description": "ExpiresAt is the time after which the port is no longer shared. If\nunset, the share never expires.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "port": {
                     "type": "integer"
                 },
@@ -16976,6 +18491,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceACL": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceGroup"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceUser"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceAgent": {
             "type": "object",
             "properties": {
@@ -17188,13 +18720,75 @@ const docTemplate = `{
                     "description": "HostPort is the port number *outside* the container.",
                     "type": "integer"
                 },
-                "network": {
-                    "description": "Network is the network protocol used by the port (tcp, udp, etc).",
+                "network": {
+                    "description": "Network is the network protocol used by the port (tcp, udp, etc).",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "Port is the port number *inside* the container.",
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentDevcontainer": {
+            "type": "object",
+            "properties": {
+                "config_path": {
+                    "type": "string"
+                },
+                "container": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentContainer"
+                },
+                "dirty": {
+                    "type": "boolean"
+                },
+                "extensions": {
+                    "description": "Extensions are the VS Code extensions in the customizations of the\ndevcontainer configuration.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "forwarded_ports": {
+                    "description": "ForwardedPorts are the forwardPorts of the devcontainer\nconfiguration. Labeled ports are registered as workspace apps.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentDevcontainerPort"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "running": {
+                    "description": "Additional runtime fields.",
+                    "type": "boolean"
+                },
+                "workspace_folder": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentDevcontainerPort": {
+            "type": "object",
+            "properties": {
+                "forwarded": {
+                    "description": "Forwarded is true when the port is reachable on the loopback\ninterface of the workspace. Ports that are in use in the workspace\naren't forwarded.",
+                    "type": "boolean"
+                },
+                "label": {
+                    "description": "Label is the label of the port in portsAttributes, if any.",
                     "type": "string"
                 },
                 "port": {
-                    "description": "Port is the port number *inside* the container.",
                     "type": "integer"
+                },
+                "protocol": {
+                    "description": "Protocol is the protocol of the port in portsAttributes, \"http\" or\n\"https\", if any.",
+                    "type": "string"
                 }
             }
         },
@@ -17248,6 +18842,13 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.WorkspaceAgentContainer"
                     }
                 },
+                "devcontainers": {
+                    "description": "Devcontainers is a list of the devcontainers known to the workspace\nagent, see WorkspaceAgentDevcontainersResponse.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentDevcontainer"
+                    }
+                },
                 "warnings": {
                     "description": "Warnings is a list of warnings that may have occurred during the\nprocess of listing containers. This should not include fatal errors.",
                     "type": "array",
@@ -17336,6 +18937,10 @@ const docTemplate = `{
                 "agent_name": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "port": {
                     "type": "integer"
                 },
@@ -17409,6 +19014,13 @@ const docTemplate = `{
                 "cron": {
                     "type": "string"
                 },
+                "depends_on": {
+                    "description": "DependsOn are the display names of the scripts that must be ready\nbefore this script is started.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "display_name": {
                     "type": "string"
                 },
@@ -17423,6 +19035,9 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "readiness_probe": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentScriptReadinessProbe"
+                },
                 "run_on_start": {
                     "type": "boolean"
                 },
@@ -17440,6 +19055,29 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentScriptReadinessProbe": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "Command is ready once it exits successfully.",
+                    "type": "string"
+                },
+                "http": {
+                    "description": "HTTP is a URL that is ready once it responds with a status below 400.",
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "tcp": {
+                    "description": "TCP is an address, e.g. localhost:8080, that is ready once it accepts\nconnections.",
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentStartupScriptBehavior": {
             "type": "string",
             "enum": [
@@ -17833,6 +19471,61 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceGroup": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ReducedUser"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_display_name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_name": {
+                    "type": "string"
+                },
+                "quota_allowance": {
+                    "type": "integer"
+                },
+                "role": {
+                    "enum": [
+                        "admin",
+                        "use"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceRole"
+                        }
+                    ]
+                },
+                "source": {
+                    "$ref": "#/definitions/codersdk.GroupSource"
+                },
+                "total_member_count": {
+                    "description": "How many members are in this group. Shows the total count,\neven if the user is not authorized to read group member details.\nMay be greater than ` + "`" + `len(Group.Members)` + "`" + `.",
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.WorkspaceHealth": {
             "type": "object",
             "properties": {
@@ -18009,6 +19702,108 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceRole": {
+            "type": "string",
+            "enum": [
+                "admin",
+                "use",
+                ""
+            ],
+            "x-enum-varnames": [
+                "WorkspaceRoleAdmin",
+                "WorkspaceRoleUse",
+                "WorkspaceRoleDeleted"
+            ]
+        },
+        "codersdk.WorkspaceSessionRecording": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "connection_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "connection_type": {
+                    "type": "string"
+                },
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "size": {
+                    "description": "Size is the size of the recording in bytes.",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                },
+                "workspace_owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceSnapshot": {
+            "type": "object",
+            "properties": {
+                "build_number": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "$ref": "#/definitions/codersdk.MinimalUser"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_version_name": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceStatus": {
             "type": "string",
             "enum": [
@@ -18049,6 +19844,37 @@ const docTemplate = `{
                 "WorkspaceTransitionDelete"
             ]
         },
+        "codersdk.WorkspaceUser": {
+            "type": "object",
+            "required": [
+                "id",
+                "username"
+            ],
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "role": {
+                    "enum": [
+                        "admin",
+                        "use"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceRole"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspacesResponse": {
             "type": "object",
             "properties": {
@@ -18760,6 +20586,59 @@ const docTemplate = `{
         "regexp.Regexp": {
             "type": "object"
         },
+        "scim.ListResponse": {
+            "type": "object",
+            "properties": {
+                "Resources": {},
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
+                    "type": "integer"
+                }
+            }
+        },
+        "scim.PatchOperation": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "description": "Op is one of \"add\", \"remove\" or \"replace\". Identity providers are not\nconsistent with casing, so it should be compared case-insensitively.",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
+        "scim.PatchRequest": {
+            "type": "object",
+            "properties": {
+                "Operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.PatchOperation"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "serpent.Annotations": {
             "type": "object",
             "additionalProperties": {
@@ -19199,6 +21078,10 @@ const docTemplate = `{
                 "slug_or_port": {
                     "type": "string"
                 },
+                "user_activity": {
+                    "description": "UserActivity is true when the report includes requests proxied to the\napp. They're real user activity, as opposed to a connection that is\nkept open, e.g. by a forgotten browser tab.",
+                    "type": "boolean"
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/codersdk"
@@ -56,10 +57,43 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
 
+	perms, err := rbac.ParseScopePermissions(createToken.ScopePermissions...)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid scope permissions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	allowList, err := rbac.ParseScopeAllowList(createToken.ScopeAllowList...)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid scope allow list.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, element := range allowList {
+		if _, err := uuid.Parse(element.ID); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid scope allow list.",
+				Detail:  fmt.Sprintf("allow list entry %q: id must be a UUID", element.String()),
+			})
+			return
+		}
+	}
+	if _, err := rbac.CustomScope(rbac.ScopeName(scope), perms, nil); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid scope permissions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	tokenName := namesgenerator.GetRandomName(1)
 
 	if len(createToken.TokenName) != 0 {
@@ -72,6 +106,10 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		DefaultLifetime: api.DeploymentValues.Sessions.DefaultTokenDuration.Value(),
 		Scope:           scope,
 		TokenName:       tokenName,
+		// The original strings are stored so that shorthands like
+		// template:push are listed as the user wrote them.
+		ScopePermissions: createToken.ScopePermissions,
+		ScopeAllowList:   createToken.ScopeAllowList,
 	}
 
 	if createToken.Lifetime != 0 {
@@ -28,6 +28,10 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// ScopePermissions and ScopeAllowList further limit the scope, see
+	// rbac.CustomScope.
+	ScopePermissions []string
+	ScopeAllowList   []string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -72,6 +76,15 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
+	scopePermissions := params.ScopePermissions
+	if scopePermissions == nil {
+		scopePermissions = []string{}
+	}
+	scopeAllowList := params.ScopeAllowList
+	if scopeAllowList == nil {
+		scopeAllowList = []string{}
+	}
+
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
 	return database.InsertAPIKeyParams{
@@ -87,13 +100,15 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:        params.ExpiresAt.UTC(),
+		CreatedAt:        dbtime.Now(),
+		UpdatedAt:        dbtime.Now(),
+		HashedSecret:     hashed[:],
+		LoginType:        params.LoginType,
+		Scope:            scope,
+		TokenName:        params.TokenName,
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   scopeAllowList,
 	}, token, nil
 }
 
@@ -85,6 +85,96 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenScopePermissions(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+	other := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+	otherWorkspace := coderdtest.CreateWorkspace(t, client, other.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, otherWorkspace.LatestBuild.ID)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	memberWorkspace := coderdtest.CreateWorkspace(t, memberClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, memberClient, memberWorkspace.LatestBuild.ID)
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for _, req := range []codersdk.CreateTokenRequest{
+			{ScopePermissions: []string{"workspace"}},
+			{ScopePermissions: []string{"workspace:push"}},
+			{ScopePermissions: []string{"workspace:read"}, Scope: codersdk.APIKeyScopeApplicationConnect},
+			{ScopeAllowList: []string{"template:not-a-uuid"}},
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+
+	t.Run("ReadOnly", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName:        "read-only",
+			ScopePermissions: []string{"workspace:read", "template:read"},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		_, err = scoped.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = scoped.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.Error(t, err)
+		err = scoped.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{Name: "renamed"})
+		require.Error(t, err)
+
+		keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		for _, key := range keys {
+			if key.TokenName == "read-only" {
+				require.Equal(t, []string{"workspace:read", "template:read"}, key.ScopePermissions)
+				require.Empty(t, key.ScopeAllowList)
+			}
+		}
+	})
+
+	t.Run("AllowTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName:        "allow-template",
+			ScopePermissions: []string{"workspace:read", "template:read"},
+			ScopeAllowList:   []string{"template:" + template.ID.String()},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		// The user's workspaces of the template are allowed, others are not.
+		_, err = scoped.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, otherWorkspace.ID)
+		require.Error(t, err)
+		_, err = scoped.Workspace(ctx, memberWorkspace.ID)
+		require.Error(t, err)
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
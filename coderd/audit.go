@@ -2,7 +2,9 @@ package coderd
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -89,6 +91,179 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// @Summary Get audit log chain
+// @ID get-audit-log-chain
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param organization_id query string false "Organization ID, omit for deployment-wide audit logs" format(uuid)
+// @Param after_sequence query int false "Only return entries after this sequence"
+// @Param limit query int false "Page limit"
+// @Success 200 {array} codersdk.AuditLogChainEntry
+// @Router /audit/chain [get]
+func (api *API) auditLogChain(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, afterSequence, limit, ok := parseAuditLogChainQuery(rw, r)
+	if !ok {
+		return
+	}
+
+	entries, err := api.Database.GetAuditLogChainEntries(ctx, database.GetAuditLogChainEntriesParams{
+		OrganizationID: organizationID,
+		AfterSequence:  afterSequence,
+		LimitOpt:       limit,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.AuditLogID)
+	}
+	logs, err := api.Database.GetAuditLogsByIDs(ctx, ids)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	logsByID := make(map[uuid.UUID]database.AuditLog, len(logs))
+	for _, alog := range logs {
+		logsByID[alog.ID] = alog
+	}
+
+	res := make([]codersdk.AuditLogChainEntry, 0, len(entries))
+	for _, entry := range entries {
+		var alog *database.AuditLog
+		if found, ok := logsByID[entry.AuditLogID]; ok {
+			alog = &found
+		}
+		res = append(res, db2sdk.AuditLogChainEntry(entry, alog))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get audit log chains
+// @ID get-audit-log-chains
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Success 200 {array} codersdk.AuditLogChain
+// @Router /audit/chains [get]
+func (api *API) auditLogChains(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	chains, err := api.Database.GetAuditLogChains(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.AuditLogChain, 0, len(chains))
+	for _, chain := range chains {
+		res = append(res, codersdk.AuditLogChain{
+			OrganizationID:      chain.OrganizationID,
+			OrganizationName:    chain.OrganizationName,
+			OrganizationDeleted: chain.OrganizationDeleted,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get audit log checkpoints
+// @ID get-audit-log-checkpoints
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param organization_id query string false "Organization ID, omit for deployment-wide audit logs" format(uuid)
+// @Param after_sequence query int false "Only return checkpoints after this sequence"
+// @Param limit query int false "Page limit"
+// @Success 200 {array} codersdk.AuditLogCheckpoint
+// @Router /audit/checkpoints [get]
+func (api *API) auditLogCheckpoints(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	organizationID, afterSequence, limit, ok := parseAuditLogChainQuery(rw, r)
+	if !ok {
+		return
+	}
+
+	checkpoints, err := api.Database.GetAuditLogCheckpoints(ctx, database.GetAuditLogCheckpointsParams{
+		OrganizationID: organizationID,
+		AfterSequence:  afterSequence,
+		LimitOpt:       limit,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.AuditLogCheckpoint, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		res = append(res, db2sdk.AuditLogCheckpoint(checkpoint))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get audit log checkpoint public key
+// @ID get-audit-log-checkpoint-public-key
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Success 200 {object} codersdk.AuditLogPublicKey
+// @Router /audit/checkpoints/public-key [get]
+func (api *API) auditLogPublicKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if api.AuditLogSigningKey == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Audit log checkpoints are not signed by this deployment.",
+			Detail:  "Configure a signing key with --audit-logging-signing-key-file.",
+		})
+		return
+	}
+	publicKey, ok := api.AuditLogSigningKey.Public().(ed25519.PublicKey)
+	if !ok {
+		httpapi.InternalServerError(rw, xerrors.New("audit log signing key is not ed25519"))
+		return
+	}
+
+	// The public key is not sensitive, and verifiers need it to check
+	// checkpoints without trusting the deployment to do so.
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AuditLogPublicKey{
+		PublicKey: hex.EncodeToString(publicKey),
+	})
+}
+
+func parseAuditLogChainQuery(rw http.ResponseWriter, r *http.Request) (organizationID uuid.UUID, afterSequence int64, limit int32, ok bool) {
+	queryParams := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	organizationID = parser.UUID(queryParams, uuid.Nil, "organization_id")
+	afterSequence = parser.Int64(queryParams, 0, "after_sequence")
+	limit = parser.PositiveInt32(queryParams, 0, "limit")
+	parser.ErrorExcessParams(queryParams)
+	if len(parser.Errors) > 0 {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return uuid.Nil, 0, 0, false
+	}
+	return organizationID, afterSequence, limit, true
+}
+
 // @Summary Generate fake audit log
 // @ID generate-fake-audit-log
 // @Security CoderSessionToken
@@ -392,6 +567,16 @@ func (api *API) auditLogIsResourceDeleted(ctx context.Context, alog database.Get
 			api.Logger.Error(ctx, "unable to fetch workspace", slog.Error(err))
 		}
 		return workspace.Deleted
+	case database.ResourceTypeWorkspaceAgentPortShare:
+		// Port shares use their workspace as the resource ID.
+		workspace, err := api.Database.GetWorkspaceByID(ctx, alog.AuditLog.ResourceID)
+		if err != nil {
+			if xerrors.Is(err, sql.ErrNoRows) {
+				return true
+			}
+			api.Logger.Error(ctx, "unable to fetch workspace", slog.Error(err))
+		}
+		return workspace.Deleted
 	case database.ResourceTypeOauth2ProviderApp:
 		_, err := api.Database.GetOAuth2ProviderAppByID(ctx, alog.AuditLog.ResourceID)
 		if xerrors.Is(err, sql.ErrNoRows) {
@@ -408,6 +593,22 @@ func (api *API) auditLogIsResourceDeleted(ctx context.Context, alog database.Get
 			api.Logger.Error(ctx, "unable to fetch oauth2 app secret", slog.Error(err))
 		}
 		return false
+	case database.ResourceTypeScimGroup:
+		_, err := api.Database.GetSCIMGroupByID(ctx, alog.AuditLog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch scim group", slog.Error(err))
+		}
+		return false
+	case database.ResourceTypeWorkspaceSnapshot:
+		_, err := api.Database.GetWorkspaceSnapshotByID(ctx, alog.AuditLog.ResourceID)
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return true
+		} else if err != nil {
+			api.Logger.Error(ctx, "unable to fetch workspace snapshot", slog.Error(err))
+		}
+		return false
 	default:
 		return false
 	}
@@ -474,6 +675,22 @@ func (api *API) auditLogResourceLink(ctx context.Context, alog database.GetAudit
 		}
 		return fmt.Sprintf("/@%s/%s", workspace.OwnerUsername, workspace.Name)
 
+	case database.ResourceTypeWorkspaceAgentPortShare:
+		if additionalFields.WorkspaceOwner != "" && additionalFields.WorkspaceName != "" {
+			return fmt.Sprintf("/@%s/%s", additionalFields.WorkspaceOwner, additionalFields.WorkspaceName)
+		}
+		workspace, getWorkspaceErr := api.Database.GetWorkspaceByID(ctx, alog.AuditLog.ResourceID)
+		if getWorkspaceErr != nil {
+			return ""
+		}
+		return fmt.Sprintf("/@%s/%s", workspace.OwnerUsername, workspace.Name)
+
+	case database.ResourceTypeWorkspaceSnapshot:
+		if additionalFields.WorkspaceOwner != "" && additionalFields.WorkspaceName != "" {
+			return fmt.Sprintf("/@%s/%s", additionalFields.WorkspaceOwner, additionalFields.WorkspaceName)
+		}
+		return ""
+
 	case database.ResourceTypeOauth2ProviderApp:
 		return fmt.Sprintf("/deployment/oauth2-provider/apps/%s", alog.AuditLog.ResourceID)
 
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ParseSigningKey parses the PEM encoded PKCS #8 ed25519 private key that
+// checkpoints of the audit log chains are signed with, such as one generated
+// with `openssl genpkey -algorithm ed25519`. The key is kept outside of the
+// database, so that someone who can rewrite the audit logs cannot also sign
+// checkpoints for them. It must never be rotated while the audit logs its
+// checkpoints cover are kept.
+func ParseSigningKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, xerrors.New("audit log signing key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.Errorf("parse audit log signing key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, xerrors.Errorf("audit log signing key must be an ed25519 key, got %T", key)
+	}
+	return edKey, nil
+}
+
+// SignCheckpoint signs the hash of the entry with the given sequence in the
+// audit log chain of an organization.
+func SignCheckpoint(key ed25519.PrivateKey, organizationID uuid.UUID, sequence int64, hash []byte) []byte {
+	return ed25519.Sign(key, codersdk.AuditLogCheckpointMessage(organizationID, sequence, hash))
+}
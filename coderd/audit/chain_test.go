@@ -0,0 +1,48 @@
+package audit_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+)
+
+func TestParseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	encode := func(t *testing.T, key any) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		parsed, err := audit.ParseSigningKey(encode(t, key))
+		require.NoError(t, err)
+		require.True(t, key.Equal(parsed))
+	})
+
+	t.Run("NotPEM", func(t *testing.T) {
+		t.Parallel()
+		_, err := audit.ParseSigningKey([]byte("not a key"))
+		require.ErrorContains(t, err, "not PEM encoded")
+	})
+
+	t.Run("NotEd25519", func(t *testing.T) {
+		t.Parallel()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		_, err = audit.ParseSigningKey(encode(t, key))
+		require.ErrorContains(t, err, "must be an ed25519 key")
+	})
+}
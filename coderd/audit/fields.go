@@ -11,6 +11,7 @@ type BackgroundSubsystem string
 
 const (
 	BackgroundSubsystemDormancy BackgroundSubsystem = "dormancy"
+	BackgroundSubsystemSCIM     BackgroundSubsystem = "scim"
 )
 
 func BackgroundTaskFields(subsystem BackgroundSubsystem) map[string]string {
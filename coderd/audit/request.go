@@ -135,6 +135,12 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.WorkspaceApp:
 		return typed.Slug
+	case database.WorkspaceAgentPortShare:
+		return fmt.Sprintf("%s:%d", typed.AgentName, typed.Port)
+	case database.SCIMGroup:
+		return typed.DisplayName
+	case database.WorkspaceSnapshot:
+		return typed.Name
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceTarget", tgt))
 	}
@@ -198,6 +204,14 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 		return typed.ID
 	case database.WorkspaceApp:
 		return typed.ID
+	case database.WorkspaceAgentPortShare:
+		// Port shares are identified by their agent name and port, so the
+		// workspace stands in for them.
+		return typed.WorkspaceID
+	case database.SCIMGroup:
+		return typed.ID
+	case database.WorkspaceSnapshot:
+		return typed.ID
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceID", tgt))
 	}
@@ -253,6 +267,12 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeWorkspaceAgent
 	case database.WorkspaceApp:
 		return database.ResourceTypeWorkspaceApp
+	case database.WorkspaceAgentPortShare:
+		return database.ResourceTypeWorkspaceAgentPortShare
+	case database.SCIMGroup:
+		return database.ResourceTypeScimGroup
+	case database.WorkspaceSnapshot:
+		return database.ResourceTypeWorkspaceSnapshot
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceType", typed))
 	}
@@ -310,6 +330,13 @@ func ResourceRequiresOrgID[T Auditable]() bool {
 		return true
 	case database.WorkspaceApp:
 		return true
+	case database.WorkspaceAgentPortShare:
+		return true
+	case database.SCIMGroup:
+		// SCIM groups are deployment-wide.
+		return false
+	case database.WorkspaceSnapshot:
+		return true
 	default:
 		panic(fmt.Sprintf("unknown resource %T for ResourceRequiresOrgID", tgt))
 	}
@@ -412,6 +439,9 @@ func InitRequest[T Auditable](w http.ResponseWriter, p *RequestParams) (*Request
 			userID = key.UserID
 		case req.UserID != uuid.Nil:
 			userID = req.UserID
+		case either(req.Old, req.New, ResourceType[T], req.params.Action) == database.ResourceTypeScimGroup:
+			// SCIM groups are changed by the identity provider, so there is
+			// no user to attribute the change to.
 		default:
 			// if we do not have a user associated with the audit action
 			// we do not want to audit
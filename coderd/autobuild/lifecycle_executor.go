@@ -454,7 +454,7 @@ func getNextTransition(
 		// make it dormant.
 		return "", database.BuildReasonDormancy, nil
 
-	case isEligibleForDelete(ws, templateSchedule, latestBuild, latestJob, currentTick):
+	case isEligibleForDelete(ws, latestBuild, latestJob, currentTick):
 		return database.WorkspaceTransitionDelete, database.BuildReasonAutodelete, nil
 	default:
 		return "", "", xerrors.Errorf("last transition not valid for autostart or autostop")
@@ -532,11 +532,11 @@ func isEligibleForDormantStop(ws database.Workspace, templateSchedule schedule.T
 		currentTick.Sub(ws.LastUsedAt) > templateSchedule.TimeTilDormant
 }
 
-func isEligibleForDelete(ws database.Workspace, templateSchedule schedule.TemplateScheduleOptions, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, currentTick time.Time) bool {
+func isEligibleForDelete(ws database.Workspace, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, currentTick time.Time) bool {
+	// deleting_at is only set when the template's time_til_dormant_autodelete
+	// or SCIM deprovisioning schedules the deletion of a dormant workspace.
 	eligible := ws.DormantAt.Valid && ws.DeletingAt.Valid &&
-		// Dormant workspaces should only be deleted if a time_til_dormant_autodelete value is specified.
-		templateSchedule.TimeTilDormantAutoDelete > 0 &&
-		// The workspace must breach the time_til_dormant_autodelete value.
+		// The workspace must breach its scheduled deletion time.
 		currentTick.After(ws.DeletingAt.Time)
 
 	// If the last delete job failed we should wait 24 hours before trying again.
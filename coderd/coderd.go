@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
@@ -207,6 +208,10 @@ type Options struct {
 	// related to OAuth. This is a symmetric secret key using hmac to sign payloads.
 	// So this secret should **never** be exposed to the client.
 	OAuthSigningKey [32]byte
+	// AuditLogSigningKey signs checkpoints of the audit log chains. It is
+	// loaded from outside of the database, and checkpoints are only made if
+	// it is set.
+	AuditLogSigningKey ed25519.PrivateKey
 
 	// APIRateLimit is the minutely throughput rate limit per user or ip.
 	// Setting a rate limit <0 will disable the rate limiter across the entire
@@ -742,7 +747,10 @@ func New(options *Options) *API {
 		StatsBatcher:          options.StatsBatcher,
 		UsageTracker:          options.WorkspaceUsageTracker,
 		UpdateAgentMetricsFn:  options.UpdateAgentMetrics,
-		AppStatBatchSize:      workspaceapps.DefaultStatsDBReporterBatchSize,
+		// Agents report stats at the refresh interval, so activity since
+		// the previous report is within it. The slack covers late reports.
+		UserActivityWindow: 2 * options.AgentStatsRefreshInterval,
+		AppStatBatchSize:   workspaceapps.DefaultStatsDBReporterBatchSize,
 	})
 	workspaceAppsLogger := options.Logger.Named("workspaceapps")
 	if options.WorkspaceAppsStatsCollectorOptions.Logger == nil {
@@ -998,8 +1006,20 @@ func New(options *Options) *API {
 			)
 
 			r.Get("/", api.auditLogs)
+			r.Get("/chain", api.auditLogChain)
+			r.Get("/chains", api.auditLogChains)
+			r.Get("/checkpoints", api.auditLogCheckpoints)
+			r.Get("/checkpoints/public-key", api.auditLogPublicKey)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
+		r.Route("/sessionrecordings", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.sessionRecordings)
+			r.Route("/{sessionrecording}", func(r chi.Router) {
+				r.Get("/", api.sessionRecording)
+				r.Get("/cast", api.sessionRecordingCast)
+			})
+		})
 		r.Route("/files", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1141,6 +1161,7 @@ func New(options *Options) *API {
 			r.Get("/external-auth", api.templateVersionExternalAuth)
 			r.Get("/variables", api.templateVersionVariables)
 			r.Get("/presets", api.templateVersionPresets)
+			r.Get("/diff/{othertemplateversion}", api.templateVersionDiff)
 			r.Get("/resources", api.templateVersionResources)
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
@@ -1368,6 +1389,19 @@ func New(options *Options) *API {
 					r.Delete("/", api.deleteWorkspaceAgentPortShare)
 				})
 				r.Get("/timings", api.workspaceTimings)
+				r.Route("/acl", func(r chi.Router) {
+					r.Get("/", api.workspaceACL)
+					r.Patch("/", api.patchWorkspaceACL)
+					r.Get("/available", api.workspaceACLAvailable)
+				})
+				r.Route("/snapshots", func(r chi.Router) {
+					r.Get("/", api.workspaceSnapshots)
+					r.Post("/", api.postWorkspaceSnapshot)
+					r.Route("/{snapshot}", func(r chi.Router) {
+						r.Delete("/", api.deleteWorkspaceSnapshot)
+						r.Post("/restore", api.postRestoreWorkspaceSnapshot)
+					})
+				})
 			})
 		})
 		r.Route("/workspacebuilds/{workspacebuild}", func(r chi.Router) {
@@ -2,6 +2,7 @@
 package db2sdk
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -20,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/policy"
 	"github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -729,6 +731,36 @@ func TemplateRoleActions(role codersdk.TemplateRole) []policy.Action {
 	return []policy.Action{}
 }
 
+func WorkspaceRoleActions(role codersdk.WorkspaceRole) []policy.Action {
+	switch role {
+	case codersdk.WorkspaceRoleAdmin:
+		// Actions are listed explicitly so that actions added to workspaces
+		// later are not granted to shared users without a decision.
+		return []policy.Action{
+			policy.ActionRead,
+			policy.ActionUpdate,
+			policy.ActionDelete,
+			policy.ActionWorkspaceStart,
+			policy.ActionWorkspaceStop,
+			policy.ActionSSH,
+			policy.ActionApplicationConnect,
+		}
+	case codersdk.WorkspaceRoleUse:
+		return []policy.Action{policy.ActionRead, policy.ActionSSH, policy.ActionApplicationConnect}
+	}
+	return []policy.Action{}
+}
+
+func WorkspaceRoleFromActions(actions []policy.Action) codersdk.WorkspaceRole {
+	switch {
+	case slice.SameElements(actions, WorkspaceRoleActions(codersdk.WorkspaceRoleUse)):
+		return codersdk.WorkspaceRoleUse
+	case slice.SameElements(actions, WorkspaceRoleActions(codersdk.WorkspaceRoleAdmin)):
+		return codersdk.WorkspaceRoleAdmin
+	}
+	return ""
+}
+
 func AuditActionFromAgentProtoConnectionAction(action agentproto.Connection_Action) (database.AuditAction, error) {
 	switch action {
 	case agentproto.Connection_CONNECT:
@@ -764,3 +796,55 @@ func Chat(chat database.Chat) codersdk.Chat {
 func Chats(chats []database.Chat) []codersdk.Chat {
 	return List(chats, Chat)
 }
+
+// AuditLogChainRecord converts an audit log into the record that is hashed
+// into the audit log chain.
+func AuditLogChainRecord(alog database.AuditLog) codersdk.AuditLogChainRecord {
+	record := codersdk.AuditLogChainRecord{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     codersdk.ResourceType(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           codersdk.AuditAction(alog.Action),
+		Diff:             alog.Diff,
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: alog.AdditionalFields,
+		RequestID:        alog.RequestID,
+	}
+	if alog.Ip.Valid {
+		record.IP = alog.Ip.IPNet.IP.String()
+	}
+	return record
+}
+
+// AuditLogChainEntry converts an entry of the audit log chain. The audit log
+// may be nil if it no longer exists.
+func AuditLogChainEntry(entry database.AuditLogChain, alog *database.AuditLog) codersdk.AuditLogChainEntry {
+	sdkEntry := codersdk.AuditLogChainEntry{
+		OrganizationID: entry.OrganizationID,
+		Sequence:       entry.Sequence,
+		AuditLogID:     entry.AuditLogID,
+		PrevHash:       hex.EncodeToString(entry.PrevHash),
+		Hash:           hex.EncodeToString(entry.Hash),
+	}
+	if alog != nil {
+		record := AuditLogChainRecord(*alog)
+		sdkEntry.Record = &record
+	}
+	return sdkEntry
+}
+
+func AuditLogCheckpoint(checkpoint database.AuditLogCheckpoint) codersdk.AuditLogCheckpoint {
+	return codersdk.AuditLogCheckpoint{
+		OrganizationID: checkpoint.OrganizationID,
+		Sequence:       checkpoint.Sequence,
+		Hash:           hex.EncodeToString(checkpoint.Hash),
+		Signature:      hex.EncodeToString(checkpoint.Signature),
+		CreatedAt:      checkpoint.CreatedAt,
+	}
+}
@@ -1331,6 +1331,13 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteNotificationDigestEntries(ctx context.Context, ids []uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceNotificationMessage); err != nil {
+		return err
+	}
+	return q.db.DeleteNotificationDigestEntries(ctx, ids)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceOauth2App); err != nil {
 		return err
@@ -1432,6 +1439,20 @@ func (q *querier) DeleteRuntimeConfig(ctx context.Context, key string) error {
 	return q.db.DeleteRuntimeConfig(ctx, key)
 }
 
+func (q *querier) DeleteSCIMGroupByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteSCIMGroupByID(ctx, id)
+}
+
+func (q *querier) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteSCIMGroupMembers(ctx, arg)
+}
+
 func (q *querier) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return database.DeleteTailnetAgentRow{}, err
@@ -1508,6 +1529,46 @@ func (q *querier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Context,
 	return q.db.DeleteWorkspaceAgentPortSharesByTemplate(ctx, templateID)
 }
 
+func (q *querier) DeleteWorkspaceAppsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if err := q.authorizeContext(ctx, policy.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceAppsByIDs(ctx, ids)
+}
+
+func (q *querier) DeleteWorkspaceSessionRecordingChunks(ctx context.Context, arg database.DeleteWorkspaceSessionRecordingChunksParams) error {
+	w, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	// Recordings are uploaded by the workspace agent, which is akin to
+	// updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceSessionRecordingChunks(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	w, err := q.db.GetWorkspaceByID(ctx, snapshot.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a snapshot is more akin to updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceSnapshotByID(ctx, id)
+}
+
 func (q *querier) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	if !testing.Testing() {
 		return xerrors.Errorf("DisableForeignKeysAndTriggers is only allowed in tests")
@@ -1561,6 +1622,29 @@ func (q *querier) FetchNewMessageMetadata(ctx context.Context, arg database.Fetc
 	return q.db.FetchNewMessageMetadata(ctx, arg)
 }
 
+func (q *querier) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.FetchResourceMonitorsByAgentID(ctx, agentID)
+}
+
+func (q *querier) FetchResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentResourceMonitor, error) {
+	// See FetchMemoryResourceMonitorsUpdatedAfter.
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return nil, err
+	}
+
+	return q.db.FetchResourceMonitorsUpdatedAfter(ctx, updatedAt)
+}
+
 func (q *querier) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
 	if err != nil {
@@ -1666,6 +1750,48 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+// auditLogChainObject is the object to authorize against when reading the
+// audit log chain of an organization. Deployment-wide audit logs are chained
+// under the nil UUID and, like the audit logs themselves, are not in any
+// organization.
+func auditLogChainObject(organizationID uuid.UUID) rbac.Object {
+	if organizationID == uuid.Nil {
+		return rbac.ResourceAuditLog
+	}
+	return rbac.ResourceAuditLog.InOrg(organizationID)
+}
+
+func (q *querier) GetAuditLogChainEntries(ctx context.Context, arg database.GetAuditLogChainEntriesParams) ([]database.AuditLogChain, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, auditLogChainObject(arg.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogChainEntries(ctx, arg)
+}
+
+func (q *querier) GetAuditLogChainHead(ctx context.Context, organizationID uuid.UUID) (database.AuditLogChain, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, auditLogChainObject(organizationID)); err != nil {
+		return database.AuditLogChain{}, err
+	}
+	return q.db.GetAuditLogChainHead(ctx, organizationID)
+}
+
+func (q *querier) GetAuditLogChains(ctx context.Context) ([]database.GetAuditLogChainsRow, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, func(ctx context.Context, _ interface{}) ([]database.GetAuditLogChainsRow, error) {
+		return q.db.GetAuditLogChains(ctx)
+	})(ctx, nil)
+}
+
+func (q *querier) GetAuditLogCheckpoints(ctx context.Context, arg database.GetAuditLogCheckpointsParams) ([]database.AuditLogCheckpoint, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, auditLogChainObject(arg.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogCheckpoints(ctx, arg)
+}
+
+func (q *querier) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetAuditLogsByIDs)(ctx, ids)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// Shortcut if the user is an owner. The SQL filter is noticeable,
 	// and this is an easy win for owners. Which is the common case.
@@ -1884,6 +2010,10 @@ func (q *querier) GetGroupMembersByGroupID(ctx context.Context, arg database.Get
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetGroupMembersByGroupID)(ctx, arg)
 }
 
+func (q *querier) GetGroupMembersByGroupIDs(ctx context.Context, arg database.GetGroupMembersByGroupIDsParams) ([]database.GroupMember, error) {
+	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.GetGroupMembersByGroupIDs)(ctx, arg)
+}
+
 func (q *querier) GetGroupMembersCountByGroupID(ctx context.Context, arg database.GetGroupMembersCountByGroupIDParams) (int64, error) {
 	if _, err := q.GetGroupByID(ctx, arg.GroupID); err != nil { // AuthZ check
 		return 0, err
@@ -1935,6 +2065,13 @@ func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	return q.db.GetLastUpdateCheck(ctx)
 }
 
+func (q *querier) GetLatestAuditLogCheckpoint(ctx context.Context, organizationID uuid.UUID) (database.AuditLogCheckpoint, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, auditLogChainObject(organizationID)); err != nil {
+		return database.AuditLogCheckpoint{}, err
+	}
+	return q.db.GetLatestAuditLogCheckpoint(ctx, organizationID)
+}
+
 func (q *querier) GetLatestCryptoKeyByFeature(ctx context.Context, feature database.CryptoKeyFeature) (database.CryptoKey, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceCryptoKey); err != nil {
 		return database.CryptoKey{}, err
@@ -1992,6 +2129,13 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNotificationDigestEntriesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationDigestEntry, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationDigestEntriesByUserID(ctx, userID)
+}
+
 func (q *querier) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
 		return nil, err
@@ -2406,6 +2550,34 @@ func (q *querier) GetRuntimeConfig(ctx context.Context, key string) (string, err
 	return q.db.GetRuntimeConfig(ctx, key)
 }
 
+func (q *querier) GetSCIMGroupByID(ctx context.Context, id uuid.UUID) (database.SCIMGroup, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.SCIMGroup{}, err
+	}
+	return q.db.GetSCIMGroupByID(ctx, id)
+}
+
+func (q *querier) GetSCIMGroupMembers(ctx context.Context, scimGroupIds []uuid.UUID) ([]database.GetSCIMGroupMembersRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMGroupMembers(ctx, scimGroupIds)
+}
+
+func (q *querier) GetSCIMGroups(ctx context.Context, displayName string) ([]database.SCIMGroup, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMGroups(ctx, displayName)
+}
+
+func (q *querier) GetSCIMGroupsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]database.GetSCIMGroupsByUserIDsRow, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSCIMGroupsByUserIDs(ctx, userIds)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -2762,6 +2934,13 @@ func (q *querier) GetUserCount(ctx context.Context, includeSystem bool) (int64,
 	return q.db.GetUserCount(ctx, includeSystem)
 }
 
+func (q *querier) GetUserIDsWithDueNotificationDigests(ctx context.Context, createdBefore time.Time) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceNotificationMessage); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserIDsWithDueNotificationDigests(ctx, createdBefore)
+}
+
 func (q *querier) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
 	if err := q.authorizeContext(ctx, policy.ActionViewInsights, rbac.ResourceTemplate); err != nil {
@@ -2967,6 +3146,20 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, arg database.Ge
 	return q.db.GetWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentPortForwardingPolicy(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return database.WorkspaceAgentPortForwardingPolicy{}, err
+	}
+
+	err = q.authorizeContext(ctx, policy.ActionRead, workspace)
+	if err != nil {
+		return database.WorkspaceAgentPortForwardingPolicy{}, err
+	}
+
+	return q.db.GetWorkspaceAgentPortForwardingPolicy(ctx, agentID)
+}
+
 func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -3166,6 +3359,13 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx context.Context, arg database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3294,6 +3494,58 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceSessionRecordingByIDRow, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceSessionRecordingByID)(ctx, id)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	// Reading the contents of a recording requires reading the recording.
+	if _, err := q.GetWorkspaceSessionRecordingByID(ctx, arg.RecordingID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingChunks(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	// Shortcut if the user can read all audit logs, the SQL filter is not
+	// needed then.
+	err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceAuditLog)
+	if err == nil {
+		return q.db.GetWorkspaceSessionRecordings(ctx, arg)
+	}
+
+	prep, err := prepareSQLFilter(ctx, q.auth, policy.ActionRead, rbac.ResourceAuditLog.Type)
+	if err != nil {
+		return nil, xerrors.Errorf("(dev error) prepare sql filter: %w", err)
+	}
+	return q.db.GetAuthorizedWorkspaceSessionRecordings(ctx, arg, prep)
+}
+
+func (q *querier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	if _, err := q.GetWorkspaceByID(ctx, snapshot.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (q *querier) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return q.db.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIDs []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, policy.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -3343,6 +3595,14 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertAuditLogChainEntry(ctx context.Context, arg database.InsertAuditLogChainEntryParams) (database.AuditLogChain, error) {
+	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLogChainEntry)(ctx, arg)
+}
+
+func (q *querier) InsertAuditLogCheckpoint(ctx context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLogCheckpoint)(ctx, arg)
+}
+
 func (q *querier) InsertChat(ctx context.Context, arg database.InsertChatParams) (database.Chat, error) {
 	return insert(q.log, q.auth, rbac.ResourceChat.WithOwner(arg.OwnerID.String()), q.db.InsertChat)(ctx, arg)
 }
@@ -3460,6 +3720,13 @@ func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMi
 	return q.db.InsertMissingGroups(ctx, arg)
 }
 
+func (q *querier) InsertNotificationDigestEntry(ctx context.Context, arg database.InsertNotificationDigestEntryParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceNotificationMessage); err != nil {
+		return err
+	}
+	return q.db.InsertNotificationDigestEntry(ctx, arg)
+}
+
 func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceOauth2App); err != nil {
 		return database.OAuth2ProviderApp{}, err
@@ -3568,6 +3835,28 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return database.WorkspaceAgentResourceMonitor{}, err
+	}
+
+	return q.db.InsertResourceMonitor(ctx, arg)
+}
+
+func (q *querier) InsertSCIMGroup(ctx context.Context, arg database.InsertSCIMGroupParams) (database.SCIMGroup, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.SCIMGroup{}, err
+	}
+	return q.db.InsertSCIMGroup(ctx, arg)
+}
+
+func (q *querier) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertSCIMGroupMembers(ctx, arg)
+}
+
 func (q *querier) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3733,6 +4022,13 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentPortForwardingPolicy(ctx context.Context, arg database.InsertWorkspaceAgentPortForwardingPolicyParams) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentPortForwardingPolicy{}, err
+	}
+	return q.db.InsertWorkspaceAgentPortForwardingPolicy(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	if err := q.authorizeContext(ctx, policy.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.WorkspaceAgentScriptTiming{}, err
@@ -3862,6 +4158,36 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	w, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Recordings are uploaded by the workspace agent, which is akin to
+	// updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return 0, err
+	}
+
+	return q.db.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	// Snapshots hold the provisioner state of the workspace, so creating one
+	// is akin to updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	return q.db.InsertWorkspaceSnapshot(ctx, arg)
+}
+
 func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	return fetchWithPostFilter(q.auth, policy.ActionRead, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
 }
@@ -4266,6 +4592,21 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceWorkspaceAgentResourceMonitor); err != nil {
+		return err
+	}
+
+	return q.db.UpdateResourceMonitor(ctx, arg)
+}
+
+func (q *querier) UpdateSCIMGroupByID(ctx context.Context, arg database.UpdateSCIMGroupByIDParams) (database.SCIMGroup, error) {
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.SCIMGroup{}, err
+	}
+	return q.db.UpdateSCIMGroupByID(ctx, arg)
+}
+
 func (q *querier) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -4567,6 +4908,15 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	// Sharing a workspace requires the same permission as changing its
+	// settings, so users granted "use" access cannot re-share it.
+	return fetchAndExec(q.log, q.auth, policy.ActionUpdate, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, policy.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -4714,6 +5064,17 @@ func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.U
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceDeletedByID)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDeletingAtParams) (database.WorkspaceTable, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDeletingAtParams) (database.WorkspaceTable, error) {
+		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+		if err != nil {
+			return database.WorkspaceTable{}, err
+		}
+		return w.WorkspaceTable(), nil
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceDeletingAt)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -4739,6 +5100,17 @@ func (q *querier) UpdateWorkspaceNextStartAt(ctx context.Context, arg database.U
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceNextStartAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+		w, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+		if err != nil {
+			return database.WorkspaceTable{}, err
+		}
+		return w.WorkspaceTable(), nil
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceOwnerByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -4968,6 +5340,21 @@ func (q *querier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg databa
 	return q.db.UpsertWorkspaceAppAuditSession(ctx, arg)
 }
 
+func (q *querier) UpsertWorkspaceSessionRecording(ctx context.Context, arg database.UpsertWorkspaceSessionRecordingParams) (int64, error) {
+	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Recordings are uploaded by the workspace agent, which is akin to
+	// updating the workspace.
+	if err := q.authorizeContext(ctx, policy.ActionUpdate, w); err != nil {
+		return 0, err
+	}
+
+	return q.db.UpsertWorkspaceSessionRecording(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
@@ -5016,3 +5403,7 @@ func (q *querier) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersP
 func (q *querier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams, _ rbac.PreparedAuthorized) ([]database.GetAuditLogsOffsetRow, error) {
 	return q.GetAuditLogsOffset(ctx, arg)
 }
+
+func (q *querier) GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams, _ rbac.PreparedAuthorized) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	return q.GetWorkspaceSessionRecordings(ctx, arg)
+}
@@ -327,6 +327,87 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			LimitOpt: 10,
 		}, emptyPreparedAuthorized{}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
 	}))
+	s.Run("GetAuditLogsByIDs", s.Subtest(func(db database.Store, check *expects) {
+		alog := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args([]uuid.UUID{alog.ID}).Asserts(alog, policy.ActionRead).Returns([]database.AuditLog{alog})
+	}))
+	s.Run("InsertAuditLogChainEntry", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertAuditLogChainEntryParams{
+			OrganizationID: uuid.New(),
+			Sequence:       1,
+			AuditLogID:     uuid.New(),
+			PrevHash:       make([]byte, 32),
+			Hash:           make([]byte, 32),
+		}).Asserts(rbac.ResourceAuditLog, policy.ActionCreate)
+	}))
+	s.Run("GetAuditLogChainHead", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		entry, err := db.InsertAuditLogChainEntry(context.Background(), database.InsertAuditLogChainEntryParams{
+			OrganizationID: orgID,
+			Sequence:       1,
+			AuditLogID:     uuid.New(),
+			PrevHash:       make([]byte, 32),
+			Hash:           make([]byte, 32),
+		})
+		require.NoError(s.T(), err)
+		check.Args(orgID).Asserts(rbac.ResourceAuditLog.InOrg(orgID), policy.ActionRead).Returns(entry)
+	}))
+	s.Run("GetAuditLogChainEntries", s.Subtest(func(db database.Store, check *expects) {
+		entry, err := db.InsertAuditLogChainEntry(context.Background(), database.InsertAuditLogChainEntryParams{
+			OrganizationID: uuid.Nil,
+			Sequence:       1,
+			AuditLogID:     uuid.New(),
+			PrevHash:       make([]byte, 32),
+			Hash:           make([]byte, 32),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetAuditLogChainEntriesParams{}).Asserts(rbac.ResourceAuditLog, policy.ActionRead).Returns([]database.AuditLogChain{entry})
+	}))
+	s.Run("InsertAuditLogCheckpoint", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertAuditLogCheckpointParams{
+			OrganizationID: uuid.New(),
+			Sequence:       1,
+			Hash:           make([]byte, 32),
+			Signature:      make([]byte, 64),
+			CreatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceAuditLog, policy.ActionCreate)
+	}))
+	s.Run("GetLatestAuditLogCheckpoint", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		checkpoint, err := db.InsertAuditLogCheckpoint(context.Background(), database.InsertAuditLogCheckpointParams{
+			OrganizationID: orgID,
+			Sequence:       1,
+			Hash:           make([]byte, 32),
+			Signature:      make([]byte, 64),
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(orgID).Asserts(rbac.ResourceAuditLog.InOrg(orgID), policy.ActionRead).Returns(checkpoint)
+	}))
+	s.Run("GetAuditLogChains", s.Subtest(func(db database.Store, check *expects) {
+		orgID := uuid.New()
+		_, err := db.InsertAuditLogCheckpoint(context.Background(), database.InsertAuditLogCheckpointParams{
+			OrganizationID: orgID,
+			Sequence:       1,
+			Hash:           make([]byte, 32),
+			Signature:      make([]byte, 64),
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		chain := database.GetAuditLogChainsRow{OrganizationID: orgID}
+		check.Args().Asserts(chain, policy.ActionRead).Returns([]database.GetAuditLogChainsRow{chain})
+	}))
+	s.Run("GetAuditLogCheckpoints", s.Subtest(func(db database.Store, check *expects) {
+		checkpoint, err := db.InsertAuditLogCheckpoint(context.Background(), database.InsertAuditLogCheckpointParams{
+			OrganizationID: uuid.Nil,
+			Sequence:       1,
+			Hash:           make([]byte, 32),
+			Signature:      make([]byte, 64),
+			CreatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetAuditLogCheckpointsParams{}).Asserts(rbac.ResourceAuditLog, policy.ActionRead).Returns([]database.AuditLogCheckpoint{checkpoint})
+	}))
 }
 
 func (s *MethodTestSuite) TestFile() {
@@ -400,6 +481,16 @@ func (s *MethodTestSuite) TestGroup() {
 			IncludeSystem: false,
 		}).Asserts(gm, policy.ActionRead)
 	}))
+	s.Run("GetGroupMembersByGroupIDs", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		g := dbgen.Group(s.T(), db, database.Group{})
+		u := dbgen.User(s.T(), db, database.User{})
+		gm := dbgen.GroupMember(s.T(), db, database.GroupMemberTable{GroupID: g.ID, UserID: u.ID})
+		check.Args(database.GetGroupMembersByGroupIDsParams{
+			GroupIds:      []uuid.UUID{g.ID},
+			IncludeSystem: false,
+		}).Asserts(gm, policy.ActionRead)
+	}))
 	s.Run("GetGroupMembersCountByGroupID", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		g := dbgen.Group(s.T(), db, database.Group{})
@@ -2107,6 +2198,44 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Keys:             []string{"test"},
 		}).Asserts(w, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentPortForwardingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       w.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: b.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		pfp, err := db.InsertWorkspaceAgentPortForwardingPolicy(context.Background(), database.InsertWorkspaceAgentPortForwardingPolicyParams{
+			AgentID:            agt.ID,
+			AllowedPorts:       []string{"3000-3999"},
+			DeniedPorts:        []string{},
+			AllowedUnixSockets: []string{},
+			DeniedUnixSockets:  []string{},
+			CreatedAt:          dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(pfp)
+	}))
 	s.Run("GetWorkspaceAgentByInstanceID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2896,6 +3025,28 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, policy.ActionUpdate).Returns(expected)
 	}))
+	s.Run("UpdateWorkspaceDeletingAt", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		w, err := db.UpdateWorkspaceDormantDeletingAt(context.Background(), database.UpdateWorkspaceDormantDeletingAtParams{
+			ID:        w.ID,
+			DormantAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceDeletingAtParams{
+			ID:         w.ID,
+			DeletingAt: sql.NullTime{Time: dbtime.Now().Add(time.Hour), Valid: true},
+		}).Asserts(w, policy.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceDormantDeletingAt", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -2912,6 +3063,39 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, policy.ActionUpdate)
 	}))
+	s.Run("UpdateWorkspaceOwnerByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.UpdateWorkspaceOwnerByIDParams{
+			ID:      w.ID,
+			OwnerID: dbgen.User(s.T(), db, database.User{}).ID,
+		}).Asserts(w, policy.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: o.ID,
+			CreatedBy:      u.ID,
+		})
+		w := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			TemplateID:     tpl.ID,
+			OrganizationID: o.ID,
+			OwnerID:        u.ID,
+		})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID: w.ID,
+		}).Asserts(w, policy.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceAutomaticUpdates", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
@@ -3346,6 +3530,239 @@ func (s *MethodTestSuite) TestWorkspacePortSharing() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWorkspaceSnapshots() {
+	s.Run("InsertWorkspaceSnapshot", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		check.Args(database.InsertWorkspaceSnapshotParams{
+			ID:                uuid.New(),
+			WorkspaceID:       ws.ID,
+			Name:              "snapshot",
+			CreatedBy:         u.ID,
+			TemplateVersionID: tv.ID,
+		}).Asserts(ws, policy.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{
+			WorkspaceID:       ws.ID,
+			CreatedBy:         u.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(snapshot.ID).Asserts(ws, policy.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotByWorkspaceIDAndName", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{
+			WorkspaceID:       ws.ID,
+			CreatedBy:         u.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams{
+			WorkspaceID: ws.ID,
+			Name:        snapshot.Name,
+		}).Asserts(ws, policy.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{
+			WorkspaceID:       ws.ID,
+			CreatedBy:         u.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(ws.ID).Asserts(ws, policy.ActionRead).Returns([]database.WorkspaceSnapshot{snapshot})
+	}))
+	s.Run("DeleteWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		snapshot := dbgen.WorkspaceSnapshot(s.T(), db, database.WorkspaceSnapshot{
+			WorkspaceID:       ws.ID,
+			CreatedBy:         u.ID,
+			TemplateVersionID: tv.ID,
+		})
+		check.Args(snapshot.ID).Asserts(ws, policy.ActionUpdate).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceSessionRecordings() {
+	setup := func(db database.Store) (database.WorkspaceTable, database.WorkspaceAgent) {
+		u := dbgen.User(s.T(), db, database.User{})
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		tpl := dbgen.Template(s.T(), db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			OrganizationID: org.ID,
+			CreatedBy:      u.ID,
+		})
+		ws := dbgen.Workspace(s.T(), db, database.WorkspaceTable{
+			OwnerID:        u.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type:           database.ProvisionerJobTypeWorkspaceBuild,
+			OrganizationID: org.ID,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{
+			JobID:             j.ID,
+			WorkspaceID:       ws.ID,
+			TemplateVersionID: tv.ID,
+		})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: j.ID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		return ws, agt
+	}
+	s.Run("UpsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		check.Args(database.UpsertWorkspaceSessionRecordingParams{
+			ID:             uuid.New(),
+			WorkspaceID:    ws.ID,
+			AgentID:        agt.ID,
+			ConnectionID:   uuid.New(),
+			ConnectionType: "SSH",
+		}).Asserts(ws, policy.ActionUpdate).Returns(int64(1))
+	}))
+	s.Run("DeleteWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(database.DeleteWorkspaceSessionRecordingChunksParams{
+			RecordingID: recording.ID,
+			AgentID:     agt.ID,
+		}).Asserts(ws, policy.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceSessionRecordingChunk", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(database.InsertWorkspaceSessionRecordingChunkParams{
+			Data:        []byte("[0,\"o\",\"$ \"]\n"),
+			RecordingID: recording.ID,
+			AgentID:     agt.ID,
+			OffsetBytes: recording.Size,
+		}).Asserts(ws, policy.ActionUpdate).Returns(int64(1))
+	}))
+	s.Run("GetWorkspaceSessionRecordingChunks", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(database.GetWorkspaceSessionRecordingChunksParams{
+			RecordingID: recording.ID,
+			LimitOpt:    8,
+		}).Asserts(rbac.ResourceAuditLog.WithID(recording.ID).InOrg(ws.OrganizationID), policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		recording := dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(recording.ID).Asserts(rbac.ResourceAuditLog.WithID(recording.ID).InOrg(ws.OrganizationID), policy.ActionRead)
+	}))
+	s.Run("GetWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		_ = dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(database.GetWorkspaceSessionRecordingsParams{
+			WorkspaceID: ws.ID,
+		}).Asserts(rbac.ResourceAuditLog, policy.ActionRead).WithNotAuthorized("nil")
+	}))
+	s.Run("GetAuthorizedWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		ws, agt := setup(db)
+		_ = dbgen.WorkspaceSessionRecording(s.T(), db, database.WorkspaceSessionRecording{
+			WorkspaceID: ws.ID,
+			AgentID:     agt.ID,
+		})
+		check.Args(database.GetWorkspaceSessionRecordingsParams{
+			WorkspaceID: ws.ID,
+		}, emptyPreparedAuthorized{}).Asserts(rbac.ResourceAuditLog, policy.ActionRead)
+	}))
+}
+
 func (s *MethodTestSuite) TestProvisionerKeys() {
 	s.Run("InsertProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
 		org := dbgen.Organization(s.T(), db, database.Organization{})
@@ -4023,6 +4440,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			APIKeyScope: database.AgentKeyScopeEnumAll,
 		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("DeleteWorkspaceAppsByIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
 	s.Run("InsertWorkspaceApp", s.Subtest(func(db database.Store, check *expects) {
 		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
 		check.Args(database.InsertWorkspaceAppParams{
@@ -4172,6 +4592,16 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Status:   database.WorkspaceAgentScriptTimingStatusOk,
 		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentPortForwardingPolicy", s.Subtest(func(db database.Store, check *expects) {
+		dbtestutil.DisableForeignKeysAndTriggers(s.T(), db)
+		check.Args(database.InsertWorkspaceAgentPortForwardingPolicyParams{
+			AgentID:            uuid.New(),
+			AllowedPorts:       []string{},
+			DeniedPorts:        []string{},
+			AllowedUnixSockets: []string{},
+			DeniedUnixSockets:  []string{},
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
 	s.Run("InsertWorkspaceAgentScripts", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentScriptsParams{}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
 	}))
@@ -4307,6 +4737,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceUniqueOwnerCountByTemplateIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
+	s.Run("GetWorkspaceIDsByOwnerIDAndTemplateIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams{
+			OwnerID:     uuid.New(),
+			TemplateIDs: []uuid.UUID{uuid.New()},
+		}).Asserts(rbac.ResourceSystem, policy.ActionRead)
+	}))
 	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, policy.ActionRead)
 	}))
@@ -4414,6 +4850,7 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 				ScriptID:           timing.ScriptID,
 				ExitCode:           timing.ExitCode,
 				Status:             timing.Status,
+				QueuedAt:           timing.QueuedAt,
 				DisplayName:        script.DisplayName,
 				WorkspaceAgentID:   agent.ID,
 				WorkspaceAgentName: agent.Name,
@@ -4481,6 +4918,88 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	}))
 }
 
+func (s *MethodTestSuite) TestSCIMGroups() {
+	insertGroup := func(db database.Store) database.SCIMGroup {
+		group, err := db.InsertSCIMGroup(context.Background(), database.InsertSCIMGroupParams{
+			ID:          uuid.New(),
+			DisplayName: "Engineering",
+			CreatedAt:   dbtime.Now(),
+			UpdatedAt:   dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		return group
+	}
+	s.Run("InsertSCIMGroup", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertSCIMGroupParams{
+			ID:          uuid.New(),
+			DisplayName: "Engineering",
+			CreatedAt:   dbtime.Now(),
+			UpdatedAt:   dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionCreate)
+	}))
+	s.Run("GetSCIMGroupByID", s.Subtest(func(db database.Store, check *expects) {
+		group := insertGroup(db)
+		check.Args(group.ID).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns(group)
+	}))
+	s.Run("GetSCIMGroups", s.Subtest(func(db database.Store, check *expects) {
+		group := insertGroup(db)
+		check.Args(group.DisplayName).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.SCIMGroup{group})
+	}))
+	s.Run("GetSCIMGroupsByUserIDs", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		group := insertGroup(db)
+		require.NoError(s.T(), db.InsertSCIMGroupMembers(context.Background(), database.InsertSCIMGroupMembersParams{
+			SCIMGroupID: group.ID,
+			UserIds:     []uuid.UUID{user.ID},
+		}))
+		check.Args([]uuid.UUID{user.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.GetSCIMGroupsByUserIDsRow{{
+			UserID:      user.ID,
+			DisplayName: group.DisplayName,
+		}})
+	}))
+	s.Run("UpdateSCIMGroupByID", s.Subtest(func(db database.Store, check *expects) {
+		group := insertGroup(db)
+		check.Args(database.UpdateSCIMGroupByIDParams{
+			ID:          group.ID,
+			DisplayName: "Operations",
+			UpdatedAt:   dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteSCIMGroupByID", s.Subtest(func(db database.Store, check *expects) {
+		group := insertGroup(db)
+		check.Args(group.ID).Asserts(rbac.ResourceSystem, policy.ActionDelete)
+	}))
+	s.Run("GetSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		group := insertGroup(db)
+		require.NoError(s.T(), db.InsertSCIMGroupMembers(context.Background(), database.InsertSCIMGroupMembersParams{
+			SCIMGroupID: group.ID,
+			UserIds:     []uuid.UUID{user.ID},
+		}))
+		check.Args([]uuid.UUID{group.ID}).Asserts(rbac.ResourceSystem, policy.ActionRead).Returns([]database.GetSCIMGroupMembersRow{{
+			SCIMGroupID: group.ID,
+			UserID:      user.ID,
+			Username:    user.Username,
+		}})
+	}))
+	s.Run("InsertSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		group := insertGroup(db)
+		check.Args(database.InsertSCIMGroupMembersParams{
+			SCIMGroupID: group.ID,
+			UserIds:     []uuid.UUID{user.ID},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+	s.Run("DeleteSCIMGroupMembers", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		group := insertGroup(db)
+		check.Args(database.DeleteSCIMGroupMembersParams{
+			SCIMGroupID: group.ID,
+			UserIds:     []uuid.UUID{user.ID},
+		}).Asserts(rbac.ResourceSystem, policy.ActionUpdate)
+	}))
+}
+
 func (s *MethodTestSuite) TestNotifications() {
 	// System functions
 	s.Run("AcquireNotificationMessages", s.Subtest(func(_ database.Store, check *expects) {
@@ -4516,6 +5035,29 @@ func (s *MethodTestSuite) TestNotifications() {
 		}).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
 	}))
 
+	// notification digests
+	s.Run("InsertNotificationDigestEntry", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertNotificationDigestEntryParams{
+			ID:                     uuid.New(),
+			UserID:                 u.ID,
+			NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+			Title:                  "Workspace marked as dormant",
+			Payload:                []byte("{}"),
+			CreatedAt:              dbtime.Now(),
+		}).Asserts(rbac.ResourceNotificationMessage, policy.ActionCreate)
+	}))
+	s.Run("GetUserIDsWithDueNotificationDigests", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
+	}))
+	s.Run("GetNotificationDigestEntriesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceNotificationMessage, policy.ActionRead)
+	}))
+	s.Run("DeleteNotificationDigestEntries", s.Subtest(func(_ database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceNotificationMessage, policy.ActionDelete)
+	}))
+
 	// webpush subscriptions
 	s.Run("GetWebpushSubscriptionsByUserID", s.Subtest(func(db database.Store, check *expects) {
 		user := dbgen.User(s.T(), db, database.User{})
@@ -4583,6 +5125,8 @@ func (s *MethodTestSuite) TestNotifications() {
 			UserID:                  user.ID,
 			NotificationTemplateIds: []uuid.UUID{notifications.TemplateWorkspaceAutoUpdated, notifications.TemplateWorkspaceDeleted},
 			Disableds:               []bool{true, false},
+			Methods:                 []string{"", string(database.NotificationMethodWebpush)},
+			Digests:                 []bool{true, false},
 		}).Asserts(rbac.ResourceNotificationPreference.WithOwner(user.ID.String()), policy.ActionUpdate)
 	}))
 
@@ -5239,6 +5783,16 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionCreate)
 	}))
 
+	s.Run("InsertResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
+		agt, _ := createAgent(s.T(), db)
+
+		check.Args(database.InsertResourceMonitorParams{
+			AgentID: agt.ID,
+			Type:    database.WorkspaceAgentResourceMonitorTypeCPU,
+			State:   database.WorkspaceAgentMonitorStateOK,
+		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionCreate)
+	}))
+
 	s.Run("UpdateMemoryResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
 		agt, _ := createAgent(s.T(), db)
 
@@ -5257,6 +5811,16 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
 	}))
 
+	s.Run("UpdateResourceMonitor", s.Subtest(func(db database.Store, check *expects) {
+		agt, _ := createAgent(s.T(), db)
+
+		check.Args(database.UpdateResourceMonitorParams{
+			AgentID: agt.ID,
+			Type:    database.WorkspaceAgentResourceMonitorTypeCPU,
+			State:   database.WorkspaceAgentMonitorStateOK,
+		}).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionUpdate)
+	}))
+
 	s.Run("FetchMemoryResourceMonitorsUpdatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionRead)
 	}))
@@ -5265,6 +5829,10 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 		check.Args(dbtime.Now()).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionRead)
 	}))
 
+	s.Run("FetchResourceMonitorsUpdatedAfter", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceWorkspaceAgentResourceMonitor, policy.ActionRead)
+	}))
+
 	s.Run("FetchMemoryResourceMonitorsByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		agt, w := createAgent(s.T(), db)
 
@@ -5297,6 +5865,23 @@ func (s *MethodTestSuite) TestResourcesMonitor() {
 
 		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(monitors)
 	}))
+
+	s.Run("FetchResourceMonitorsByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		agt, w := createAgent(s.T(), db)
+
+		dbgen.WorkspaceAgentResourceMonitor(s.T(), db, database.WorkspaceAgentResourceMonitor{
+			AgentID:   agt.ID,
+			Type:      database.WorkspaceAgentResourceMonitorTypeProcesses,
+			Enabled:   true,
+			Threshold: 80,
+			CreatedAt: dbtime.Now(),
+		})
+
+		monitors, err := db.FetchResourceMonitorsByAgentID(context.Background(), agt.ID)
+		require.NoError(s.T(), err)
+
+		check.Args(agt.ID).Asserts(w, policy.ActionRead).Returns(monitors)
+	}))
 }
 
 func (s *MethodTestSuite) TestResourcesProvisionerdserver() {
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
@@ -174,11 +175,72 @@ func WorkspaceAgentPortShare(t testing.TB, db database.Store, orig database.Work
 		Port:        takeFirst(orig.Port, 8080),
 		ShareLevel:  takeFirst(orig.ShareLevel, database.AppSharingLevelPublic),
 		Protocol:    takeFirst(orig.Protocol, database.PortShareProtocolHttp),
+		ExpiresAt:   orig.ExpiresAt,
 	})
 	require.NoError(t, err, "insert workspace agent")
 	return ps
 }
 
+func WorkspaceSnapshot(t testing.TB, db database.Store, orig database.WorkspaceSnapshot) database.WorkspaceSnapshot {
+	snapshot, err := db.InsertWorkspaceSnapshot(genCtx, database.InsertWorkspaceSnapshotParams{
+		ID:                takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:       takeFirst(orig.WorkspaceID, uuid.New()),
+		Name:              takeFirst(orig.Name, testutil.GetRandomName(t)),
+		CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
+		CreatedBy:         takeFirst(orig.CreatedBy, uuid.New()),
+		BuildNumber:       takeFirst(orig.BuildNumber, 1),
+		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
+		ProvisionerState:  takeFirstSlice(orig.ProvisionerState, []byte{}),
+		RichParameters:    takeFirstSlice(orig.RichParameters, []byte("[]")),
+	})
+	require.NoError(t, err, "insert workspace snapshot")
+	return snapshot
+}
+
+// WorkspaceSessionRecording inserts a recording with the given chunks. If no
+// chunks are given, the recording only contains an asciicast header.
+func WorkspaceSessionRecording(t testing.TB, db database.Store, orig database.WorkspaceSessionRecording, chunks ...[]byte) database.WorkspaceSessionRecording {
+	arg := database.UpsertWorkspaceSessionRecordingParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:    takeFirst(orig.WorkspaceID, uuid.New()),
+		AgentID:        takeFirst(orig.AgentID, uuid.New()),
+		ConnectionID:   takeFirst(orig.ConnectionID, uuid.New()),
+		ConnectionType: takeFirst(orig.ConnectionType, "SSH"),
+		StartedAt:      takeFirst(orig.StartedAt, dbtime.Now()),
+		EndedAt:        takeFirst(orig.EndedAt, dbtime.Now()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+	}
+	_, err := db.UpsertWorkspaceSessionRecording(genCtx, arg)
+	require.NoError(t, err, "insert workspace session recording")
+
+	if len(chunks) == 0 {
+		chunks = [][]byte{[]byte(`{"version":2,"width":80,"height":24}` + "\n")}
+	}
+	var size int64
+	for _, chunk := range chunks {
+		_, err = db.InsertWorkspaceSessionRecordingChunk(genCtx, database.InsertWorkspaceSessionRecordingChunkParams{
+			Data:        chunk,
+			RecordingID: arg.ID,
+			AgentID:     arg.AgentID,
+			OffsetBytes: size,
+		})
+		require.NoError(t, err, "insert workspace session recording chunk")
+		size += int64(len(chunk))
+	}
+
+	return database.WorkspaceSessionRecording{
+		ID:             arg.ID,
+		WorkspaceID:    arg.WorkspaceID,
+		AgentID:        arg.AgentID,
+		ConnectionID:   arg.ConnectionID,
+		ConnectionType: arg.ConnectionType,
+		StartedAt:      arg.StartedAt,
+		EndedAt:        arg.EndedAt,
+		Size:           size,
+		CreatedAt:      arg.CreatedAt,
+	}
+}
+
 func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgent) database.WorkspaceAgent {
 	agt, err := db.InsertWorkspaceAgent(genCtx, database.InsertWorkspaceAgentParams{
 		ID:         takeFirst(orig.ID, uuid.New()),
@@ -219,6 +281,10 @@ func WorkspaceAgent(t testing.TB, db database.Store, orig database.WorkspaceAgen
 }
 
 func WorkspaceAgentScript(t testing.TB, db database.Store, orig database.WorkspaceAgentScript) database.WorkspaceAgentScript {
+	// Each script's dependencies are passed as a text array literal.
+	dependsOnValue, err := pq.StringArray(append([]string{}, orig.DependsOn...)).Value()
+	require.NoError(t, err, "encode depends on")
+	dependsOn, _ := dependsOnValue.(string)
 	scripts, err := db.InsertWorkspaceAgentScripts(genCtx, database.InsertWorkspaceAgentScriptsParams{
 		WorkspaceAgentID: takeFirst(orig.WorkspaceAgentID, uuid.New()),
 		CreatedAt:        takeFirst(orig.CreatedAt, dbtime.Now()),
@@ -232,6 +298,13 @@ func WorkspaceAgentScript(t testing.TB, db database.Store, orig database.Workspa
 		TimeoutSeconds:   []int32{takeFirst(orig.TimeoutSeconds, 0)},
 		DisplayName:      []string{takeFirst(orig.DisplayName, "")},
 		ID:               []uuid.UUID{takeFirst(orig.ID, uuid.New())},
+		DependsOn:        []string{dependsOn},
+
+		ReadinessProbeCommand:         []string{orig.ReadinessProbeCommand},
+		ReadinessProbeTCP:             []string{orig.ReadinessProbeTCP},
+		ReadinessProbeHTTP:            []string{orig.ReadinessProbeHTTP},
+		ReadinessProbeIntervalSeconds: []int32{orig.ReadinessProbeIntervalSeconds},
+		ReadinessProbeTimeoutSeconds:  []int32{orig.ReadinessProbeTimeoutSeconds},
 	})
 	require.NoError(t, err, "insert workspace agent script")
 	require.NotEmpty(t, scripts, "insert workspace agent script returned no scripts")
@@ -259,8 +332,10 @@ func WorkspaceAgentScriptTimings(t testing.TB, db database.Store, scripts []data
 func WorkspaceAgentScriptTiming(t testing.TB, db database.Store, orig database.WorkspaceAgentScriptTiming) database.WorkspaceAgentScriptTiming {
 	// retry a few times in case of a unique constraint violation
 	for i := 0; i < 10; i++ {
+		startedAt := takeFirst(orig.StartedAt, dbtime.Now())
 		timing, err := db.InsertWorkspaceAgentScriptTimings(genCtx, database.InsertWorkspaceAgentScriptTimingsParams{
-			StartedAt: takeFirst(orig.StartedAt, dbtime.Now()),
+			QueuedAt:  takeFirst(orig.QueuedAt, startedAt),
+			StartedAt: startedAt,
 			EndedAt:   takeFirst(orig.EndedAt, dbtime.Now()),
 			Stage:     takeFirst(orig.Stage, database.WorkspaceAgentScriptTimingStageStart),
 			ScriptID:  takeFirst(orig.ScriptID, uuid.New()),
@@ -1160,6 +1235,21 @@ func WorkspaceAgentVolumeResourceMonitor(t testing.TB, db database.Store, seed d
 	return monitor
 }
 
+func WorkspaceAgentResourceMonitor(t testing.TB, db database.Store, seed database.WorkspaceAgentResourceMonitor) database.WorkspaceAgentResourceMonitor {
+	monitor, err := db.InsertResourceMonitor(genCtx, database.InsertResourceMonitorParams{
+		AgentID:        takeFirst(seed.AgentID, uuid.New()),
+		Type:           takeFirst(seed.Type, database.WorkspaceAgentResourceMonitorTypeCPU),
+		Enabled:        takeFirst(seed.Enabled, true),
+		State:          takeFirst(seed.State, database.WorkspaceAgentMonitorStateOK),
+		Threshold:      takeFirst(seed.Threshold, 100),
+		CreatedAt:      takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:      takeFirst(seed.UpdatedAt, dbtime.Now()),
+		DebouncedUntil: takeFirst(seed.DebouncedUntil, time.Time{}),
+	})
+	require.NoError(t, err, "insert workspace agent resource monitor")
+	return monitor
+}
+
 func CustomRole(t testing.TB, db database.Store, seed database.CustomRole) database.CustomRole {
 	role, err := db.InsertCustomRole(genCtx, database.InsertCustomRoleParams{
 		Name:            takeFirst(seed.Name, strings.ToLower(testutil.GetRandomName(t))),
@@ -2,6 +2,7 @@ package dbmem
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -66,6 +67,7 @@ func New() database.Store {
 			groupMembers:                   make([]database.GroupMemberTable, 0),
 			licenses:                       make([]database.License, 0),
 			locks:                          map[int64]struct{}{},
+			notificationDigestEntries:      make([]database.NotificationDigestEntry, 0),
 			notificationMessages:           make([]database.NotificationMessage, 0),
 			notificationPreferences:        make([]database.NotificationPreference, 0),
 			organizationMembers:            make([]database.OrganizationMember, 0),
@@ -215,6 +217,8 @@ type data struct {
 
 	// New tables
 	auditLogs                            []database.AuditLog
+	auditLogChain                        []database.AuditLogChain
+	auditLogCheckpoints                  []database.AuditLogCheckpoint
 	chats                                []database.Chat
 	chatMessages                         []database.ChatMessage
 	cryptoKeys                           []database.CryptoKey
@@ -225,6 +229,7 @@ type data struct {
 	groupMembers                         []database.GroupMemberTable
 	groups                               []database.Group
 	licenses                             []database.License
+	notificationDigestEntries            []database.NotificationDigestEntry
 	notificationMessages                 []database.NotificationMessage
 	notificationPreferences              []database.NotificationPreference
 	notificationReportGeneratorLogs      []database.NotificationReportGeneratorLog
@@ -239,6 +244,8 @@ type data struct {
 	provisionerJobs                      []database.ProvisionerJob
 	provisionerKeys                      []database.ProvisionerKey
 	replicas                             []database.Replica
+	scimGroups                           []database.SCIMGroup
+	scimGroupMembers                     []database.SCIMGroupMember
 	templateVersions                     []database.TemplateVersionTable
 	templateVersionParameters            []database.TemplateVersionParameter
 	templateVersionTerraformValues       []database.TemplateVersionTerraformValue
@@ -253,11 +260,13 @@ type data struct {
 	workspaceAgentLogs                   []database.WorkspaceAgentLog
 	workspaceAgentLogSources             []database.WorkspaceAgentLogSource
 	workspaceAgentPortShares             []database.WorkspaceAgentPortShare
+	workspaceAgentPortForwardingPolicies []database.WorkspaceAgentPortForwardingPolicy
 	workspaceAgentScriptTimings          []database.WorkspaceAgentScriptTiming
 	workspaceAgentScripts                []database.WorkspaceAgentScript
 	workspaceAgentStats                  []database.WorkspaceAgentStat
 	workspaceAgentMemoryResourceMonitors []database.WorkspaceAgentMemoryResourceMonitor
 	workspaceAgentVolumeResourceMonitors []database.WorkspaceAgentVolumeResourceMonitor
+	workspaceAgentResourceMonitors       []database.WorkspaceAgentResourceMonitor
 	workspaceAgentDevcontainers          []database.WorkspaceAgentDevcontainer
 	workspaceApps                        []database.WorkspaceApp
 	workspaceAppStatuses                 []database.WorkspaceAppStatus
@@ -268,6 +277,9 @@ type data struct {
 	workspaceBuildParameters             []database.WorkspaceBuildParameter
 	workspaceResourceMetadata            []database.WorkspaceResourceMetadatum
 	workspaceResources                   []database.WorkspaceResource
+	workspaceSessionRecordings           []database.WorkspaceSessionRecording
+	workspaceSessionRecordingChunks      []database.WorkspaceSessionRecordingChunk
+	workspaceSnapshots                   []database.WorkspaceSnapshot
 	workspaceModules                     []database.WorkspaceModule
 	workspaces                           []database.WorkspaceTable
 	workspaceProxies                     []database.WorkspaceProxy
@@ -509,6 +521,8 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 	rows := make([]database.GetWorkspacesRow, 0, len(workspaces))
 	for _, w := range workspaces {
 		extended := q.extendWorkspace(w)
+		userACL, _ := json.Marshal(w.UserACL)
+		groupACL, _ := json.Marshal(w.GroupACL)
 
 		wr := database.GetWorkspacesRow{
 			ID:                w.ID,
@@ -527,6 +541,8 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			AutomaticUpdates:  w.AutomaticUpdates,
 			Favorite:          w.Favorite,
 			NextStartAt:       w.NextStartAt,
+			UserACL:           userACL,
+			GroupACL:          groupACL,
 
 			OwnerAvatarUrl: extended.OwnerAvatarUrl,
 			OwnerUsername:  extended.OwnerUsername,
@@ -1543,6 +1559,9 @@ func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.Ac
 		if template.ActivityBump == 0 {
 			return nil
 		}
+		if template.ActivityBumpRequiresUserActivity && !arg.UserActivity {
+			return nil
+		}
 		activityBump := time.Duration(template.ActivityBump)
 
 		var ttlDur time.Duration
@@ -1613,7 +1632,7 @@ func (q *FakeQuerier) ArchiveUnusedTemplateVersions(_ context.Context, arg datab
 
 	for _, b := range q.workspaceBuilds {
 		v, ok := latest[b.WorkspaceID]
-		if ok || b.BuildNumber < v.Number {
+		if ok && b.BuildNumber < v.Number {
 			// Not the latest
 			continue
 		}
@@ -2038,6 +2057,16 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteNotificationDigestEntries(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.notificationDigestEntries = slices.DeleteFunc(q.notificationDigestEntries, func(entry database.NotificationDigestEntry) bool {
+		return slices.Contains(ids, entry.ID)
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2435,6 +2464,34 @@ func (q *FakeQuerier) DeleteRuntimeConfig(_ context.Context, key string) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteSCIMGroupByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.scimGroups = slices.DeleteFunc(q.scimGroups, func(group database.SCIMGroup) bool {
+		return group.ID == id
+	})
+	q.scimGroupMembers = slices.DeleteFunc(q.scimGroupMembers, func(member database.SCIMGroupMember) bool {
+		return member.SCIMGroupID == id
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteSCIMGroupMembers(_ context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.scimGroupMembers = slices.DeleteFunc(q.scimGroupMembers, func(member database.SCIMGroupMember) bool {
+		return member.SCIMGroupID == arg.SCIMGroupID && slices.Contains(arg.UserIds, member.UserID)
+	})
+	return nil
+}
+
 func (*FakeQuerier) DeleteTailnetAgent(context.Context, database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	return database.DeleteTailnetAgentRow{}, ErrUnimplemented
 }
@@ -2540,6 +2597,53 @@ func (q *FakeQuerier) DeleteWorkspaceAgentPortSharesByTemplate(_ context.Context
 	return nil
 }
 
+func (q *FakeQuerier) DeleteWorkspaceAppsByIDs(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceAppStatuses = slices.DeleteFunc(q.workspaceAppStatuses, func(status database.WorkspaceAppStatus) bool {
+		return slices.Contains(ids, status.AppID)
+	})
+	q.workspaceApps = slices.DeleteFunc(q.workspaceApps, func(app database.WorkspaceApp) bool {
+		return slices.Contains(ids, app.ID)
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceSessionRecordingChunks(_ context.Context, arg database.DeleteWorkspaceSessionRecordingChunksParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.RecordingID || recording.AgentID != arg.AgentID {
+			continue
+		}
+		q.workspaceSessionRecordingChunks = slices.DeleteFunc(q.workspaceSessionRecordingChunks, func(chunk database.WorkspaceSessionRecordingChunk) bool {
+			return chunk.RecordingID == arg.RecordingID
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == id {
+			q.workspaceSnapshots = append(q.workspaceSnapshots[:i], q.workspaceSnapshots[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
 func (*FakeQuerier) DisableForeignKeysAndTriggers(_ context.Context) error {
 	// This is a no-op in the in-memory database.
 	return nil
@@ -2626,6 +2730,9 @@ func (q *FakeQuerier) FetchNewMessageMetadata(_ context.Context, arg database.Fe
 		return database.FetchNewMessageMetadataRow{}, err
 	}
 
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
 	user, err := q.getUserByIDNoLock(arg.UserID)
 	if err != nil {
 		return database.FetchNewMessageMetadataRow{}, xerrors.Errorf("fetch user: %w", err)
@@ -2642,14 +2749,55 @@ func (q *FakeQuerier) FetchNewMessageMetadata(_ context.Context, arg database.Fe
 		return database.FetchNewMessageMetadataRow{}, err
 	}
 
-	return database.FetchNewMessageMetadataRow{
-		UserEmail:        user.Email,
-		UserName:         userName,
-		UserUsername:     user.Username,
-		NotificationName: "Some notification",
-		Actions:          actions,
-		UserID:           arg.UserID,
-	}, nil
+	row := database.FetchNewMessageMetadataRow{
+		UserEmail:              user.Email,
+		UserName:               userName,
+		UserUsername:           user.Username,
+		NotificationName:       "Some notification",
+		NotificationTemplateID: arg.NotificationTemplateID,
+		TitleTemplate:          "Some notification",
+		Actions:                actions,
+		UserID:                 arg.UserID,
+	}
+
+	// Mimic LEFT JOIN on notification_preferences. Templates are not stored in
+	// dbmem, so every template is considered digestible.
+	for _, np := range q.notificationPreferences {
+		if np.UserID != arg.UserID || np.NotificationTemplateID != arg.NotificationTemplateID {
+			continue
+		}
+		row.UserMethod = np.Method
+		row.Digest = np.Digest && !np.Disabled
+		break
+	}
+
+	return row, nil
+}
+
+func (q *FakeQuerier) FetchResourceMonitorsByAgentID(_ context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	monitors := []database.WorkspaceAgentResourceMonitor{}
+	for _, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID == agentID {
+			monitors = append(monitors, monitor)
+		}
+	}
+	return monitors, nil
+}
+
+func (q *FakeQuerier) FetchResourceMonitorsUpdatedAfter(_ context.Context, updatedAt time.Time) ([]database.WorkspaceAgentResourceMonitor, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	monitors := []database.WorkspaceAgentResourceMonitor{}
+	for _, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.UpdatedAt.After(updatedAt) {
+			monitors = append(monitors, monitor)
+		}
+	}
+	return monitors, nil
 }
 
 func (q *FakeQuerier) FetchVolumesResourceMonitorsByAgentID(_ context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
@@ -2838,6 +2986,129 @@ func (q *FakeQuerier) GetApplicationName(_ context.Context) (string, error) {
 	return q.applicationName, nil
 }
 
+func (q *FakeQuerier) GetAuditLogChainEntries(_ context.Context, arg database.GetAuditLogChainEntriesParams) ([]database.AuditLogChain, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	limit := int(arg.LimitOpt)
+	if limit == 0 {
+		limit = 100
+	}
+	entries := make([]database.AuditLogChain, 0)
+	for _, entry := range q.auditLogChain {
+		if entry.OrganizationID != arg.OrganizationID || entry.Sequence <= arg.AfterSequence {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b database.AuditLogChain) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (q *FakeQuerier) GetAuditLogChainHead(_ context.Context, organizationID uuid.UUID) (database.AuditLogChain, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var head *database.AuditLogChain
+	for i, entry := range q.auditLogChain {
+		if entry.OrganizationID != organizationID {
+			continue
+		}
+		if head == nil || entry.Sequence > head.Sequence {
+			head = &q.auditLogChain[i]
+		}
+	}
+	if head == nil {
+		return database.AuditLogChain{}, sql.ErrNoRows
+	}
+	return *head, nil
+}
+
+func (q *FakeQuerier) GetAuditLogChains(_ context.Context) ([]database.GetAuditLogChainsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	seen := make(map[uuid.UUID]struct{})
+	chains := make([]database.GetAuditLogChainsRow, 0)
+	add := func(organizationID uuid.UUID) {
+		if _, ok := seen[organizationID]; ok {
+			return
+		}
+		seen[organizationID] = struct{}{}
+		chain := database.GetAuditLogChainsRow{OrganizationID: organizationID}
+		for _, org := range q.organizations {
+			if org.ID == organizationID {
+				chain.OrganizationName = org.Name
+				chain.OrganizationDeleted = org.Deleted
+				break
+			}
+		}
+		chains = append(chains, chain)
+	}
+	for _, entry := range q.auditLogChain {
+		add(entry.OrganizationID)
+	}
+	for _, checkpoint := range q.auditLogCheckpoints {
+		add(checkpoint.OrganizationID)
+	}
+	slices.SortFunc(chains, func(a, b database.GetAuditLogChainsRow) int {
+		return slice.Ascending(a.OrganizationID.String(), b.OrganizationID.String())
+	})
+	return chains, nil
+}
+
+func (q *FakeQuerier) GetAuditLogCheckpoints(_ context.Context, arg database.GetAuditLogCheckpointsParams) ([]database.AuditLogCheckpoint, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	limit := int(arg.LimitOpt)
+	if limit == 0 {
+		limit = 100
+	}
+	checkpoints := make([]database.AuditLogCheckpoint, 0)
+	for _, checkpoint := range q.auditLogCheckpoints {
+		if checkpoint.OrganizationID != arg.OrganizationID || checkpoint.Sequence <= arg.AfterSequence {
+			continue
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	slices.SortFunc(checkpoints, func(a, b database.AuditLogCheckpoint) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	if len(checkpoints) > limit {
+		checkpoints = checkpoints[:limit]
+	}
+	return checkpoints, nil
+}
+
+func (q *FakeQuerier) GetAuditLogsByIDs(_ context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	alogs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if slices.Contains(ids, alog.ID) {
+			alogs = append(alogs, alog)
+		}
+	}
+	return alogs, nil
+}
+
 func (q *FakeQuerier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	return q.GetAuthorizedAuditLogsOffset(ctx, arg, nil)
 }
@@ -3618,6 +3889,39 @@ func (q *FakeQuerier) GetGroupMembersByGroupID(ctx context.Context, arg database
 	return groupMembers, nil
 }
 
+func (q *FakeQuerier) GetGroupMembersByGroupIDs(ctx context.Context, arg database.GetGroupMembersByGroupIDsParams) ([]database.GroupMember, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var groupMembers []database.GroupMember
+	for _, groupID := range arg.GroupIds {
+		if q.isEveryoneGroup(groupID) {
+			groupMembers = append(groupMembers, q.getEveryoneGroupMembersNoLock(ctx, groupID)...)
+			continue
+		}
+		for _, member := range q.groupMembers {
+			if member.GroupID != groupID {
+				continue
+			}
+			groupMember, err := q.getGroupMemberNoLock(ctx, member.UserID, member.GroupID)
+			if errors.Is(err, errUserDeleted) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			groupMembers = append(groupMembers, groupMember)
+		}
+	}
+
+	return groupMembers, nil
+}
+
 func (q *FakeQuerier) GetGroupMembersCountByGroupID(ctx context.Context, arg database.GetGroupMembersCountByGroupIDParams) (int64, error) {
 	users, err := q.GetGroupMembersByGroupID(ctx, database.GetGroupMembersByGroupIDParams(arg))
 	if err != nil {
@@ -3761,6 +4065,25 @@ func (q *FakeQuerier) GetLastUpdateCheck(_ context.Context) (string, error) {
 	return string(q.lastUpdateCheck), nil
 }
 
+func (q *FakeQuerier) GetLatestAuditLogCheckpoint(_ context.Context, organizationID uuid.UUID) (database.AuditLogCheckpoint, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var latest *database.AuditLogCheckpoint
+	for i, checkpoint := range q.auditLogCheckpoints {
+		if checkpoint.OrganizationID != organizationID {
+			continue
+		}
+		if latest == nil || checkpoint.Sequence > latest.Sequence {
+			latest = &q.auditLogCheckpoints[i]
+		}
+	}
+	if latest == nil {
+		return database.AuditLogCheckpoint{}, sql.ErrNoRows
+	}
+	return *latest, nil
+}
+
 func (q *FakeQuerier) GetLatestCryptoKeyByFeature(_ context.Context, feature database.CryptoKeyFeature) (database.CryptoKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3897,6 +4220,22 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetNotificationDigestEntriesByUserID(_ context.Context, userID uuid.UUID) ([]database.NotificationDigestEntry, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var out []database.NotificationDigestEntry
+	for _, entry := range q.notificationDigestEntries {
+		if entry.UserID == userID {
+			out = append(out, entry)
+		}
+	}
+	slices.SortFunc(out, func(a, b database.NotificationDigestEntry) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return out, nil
+}
+
 func (q *FakeQuerier) GetNotificationMessagesByStatus(_ context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5051,6 +5390,87 @@ func (q *FakeQuerier) GetRuntimeConfig(_ context.Context, key string) (string, e
 	return val, nil
 }
 
+func (q *FakeQuerier) GetSCIMGroupByID(_ context.Context, id uuid.UUID) (database.SCIMGroup, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, group := range q.scimGroups {
+		if group.ID == id {
+			return group, nil
+		}
+	}
+	return database.SCIMGroup{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetSCIMGroupMembers(_ context.Context, scimGroupIds []uuid.UUID) ([]database.GetSCIMGroupMembersRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetSCIMGroupMembersRow, 0)
+	for _, member := range q.scimGroupMembers {
+		if !slices.Contains(scimGroupIds, member.SCIMGroupID) {
+			continue
+		}
+		user, err := q.getUserByIDNoLock(member.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetSCIMGroupMembersRow{
+			SCIMGroupID: member.SCIMGroupID,
+			UserID:      user.ID,
+			Username:    user.Username,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetSCIMGroupMembersRow) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetSCIMGroups(_ context.Context, displayName string) ([]database.SCIMGroup, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	groups := make([]database.SCIMGroup, 0)
+	for _, group := range q.scimGroups {
+		if displayName != "" && group.DisplayName != displayName {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b database.SCIMGroup) int {
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+	return groups, nil
+}
+
+func (q *FakeQuerier) GetSCIMGroupsByUserIDs(_ context.Context, userIds []uuid.UUID) ([]database.GetSCIMGroupsByUserIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetSCIMGroupsByUserIDsRow, 0)
+	for _, member := range q.scimGroupMembers {
+		if !slices.Contains(userIds, member.UserID) {
+			continue
+		}
+		for _, group := range q.scimGroups {
+			if group.ID == member.SCIMGroupID {
+				rows = append(rows, database.GetSCIMGroupsByUserIDsRow{
+					UserID:      member.UserID,
+					DisplayName: group.DisplayName,
+				})
+			}
+		}
+	}
+	slices.SortFunc(rows, func(a, b database.GetSCIMGroupsByUserIDsRow) int {
+		if c := strings.Compare(a.UserID.String(), b.UserID.String()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+	return rows, nil
+}
+
 func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
 	return nil, ErrUnimplemented
 }
@@ -6546,6 +6966,29 @@ func (q *FakeQuerier) GetUserCount(_ context.Context, includeSystem bool) (int64
 	return existing, nil
 }
 
+func (q *FakeQuerier) GetUserIDsWithDueNotificationDigests(_ context.Context, createdBefore time.Time) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	oldest := make(map[uuid.UUID]time.Time)
+	for _, entry := range q.notificationDigestEntries {
+		if at, ok := oldest[entry.UserID]; !ok || entry.CreatedAt.Before(at) {
+			oldest[entry.UserID] = entry.CreatedAt
+		}
+	}
+
+	var out []uuid.UUID
+	for userID, at := range oldest {
+		if !at.After(createdBefore) {
+			out = append(out, userID)
+		}
+	}
+	slices.SortFunc(out, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+	return out, nil
+}
+
 func (q *FakeQuerier) GetUserLatencyInsights(_ context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7182,6 +7625,18 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, arg database.
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentPortForwardingPolicy(_ context.Context, agentID uuid.UUID) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, policy := range q.workspaceAgentPortForwardingPolicies {
+		if policy.AgentID == agentID {
+			return policy, nil
+		}
+	}
+	return database.WorkspaceAgentPortForwardingPolicy{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7191,7 +7646,11 @@ func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
+	now := dbtime.Now()
 	for _, share := range q.workspaceAgentPortShares {
+		if share.ExpiresAt.Valid && !share.ExpiresAt.Time.After(now) {
+			continue
+		}
 		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
 			return share, nil
 		}
@@ -7266,6 +7725,7 @@ func (q *FakeQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Contex
 
 		rows = append(rows, database.GetWorkspaceAgentScriptTimingsByBuildIDRow{
 			ScriptID:           t.ScriptID,
+			QueuedAt:           t.QueuedAt,
 			StartedAt:          t.StartedAt,
 			EndedAt:            t.EndedAt,
 			ExitCode:           t.ExitCode,
@@ -8045,6 +8505,28 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceIDsByOwnerIDAndTemplateIDs(_ context.Context, arg database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams) ([]uuid.UUID, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	ids := make([]uuid.UUID, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.OwnerID != arg.OwnerID {
+			continue
+		}
+		if !slices.Contains(arg.TemplateIDs, workspace.TemplateID) {
+			continue
+		}
+		ids = append(ids, workspace.ID)
+	}
+	return ids, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceModulesByJobID(_ context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8235,6 +8717,113 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceSessionRecordingByIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID != id {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, recording.WorkspaceID)
+		if err != nil {
+			return database.GetWorkspaceSessionRecordingByIDRow{}, err
+		}
+		return database.GetWorkspaceSessionRecordingByIDRow{
+			ID:             recording.ID,
+			WorkspaceID:    recording.WorkspaceID,
+			AgentID:        recording.AgentID,
+			ConnectionID:   recording.ConnectionID,
+			ConnectionType: recording.ConnectionType,
+			StartedAt:      recording.StartedAt,
+			EndedAt:        recording.EndedAt,
+			Size:           recording.Size,
+			CreatedAt:      recording.CreatedAt,
+			OrganizationID: workspace.OrganizationID,
+		}, nil
+	}
+
+	return database.GetWorkspaceSessionRecordingByIDRow{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingChunks(_ context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	chunks := make([]database.WorkspaceSessionRecordingChunk, 0)
+	for _, chunk := range q.workspaceSessionRecordingChunks {
+		if chunk.RecordingID != arg.RecordingID || chunk.OffsetBytes < arg.OffsetBytes {
+			continue
+		}
+		chunks = append(chunks, chunk)
+	}
+	slices.SortFunc(chunks, func(a, b database.WorkspaceSessionRecordingChunk) int {
+		return cmp.Compare(a.OffsetBytes, b.OffsetBytes)
+	})
+	if int(arg.LimitOpt) < len(chunks) {
+		chunks = chunks[:arg.LimitOpt]
+	}
+	return chunks, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	// A nil auth filter means no auth filter.
+	return q.GetAuthorizedWorkspaceSessionRecordings(ctx, arg, nil)
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotByWorkspaceIDAndName(_ context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == arg.WorkspaceID && snapshot.Name == arg.Name {
+			return snapshot, nil
+		}
+	}
+
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	snapshots := make([]database.WorkspaceSnapshot, 0)
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == workspaceID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	slices.SortFunc(snapshots, func(a, b database.WorkspaceSnapshot) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -8364,8 +8953,7 @@ func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, no
 
 		if workspace.DormantAt.Valid &&
 			workspace.DeletingAt.Valid &&
-			workspace.DeletingAt.Time.Before(now) &&
-			template.TimeTilDormantAutoDelete > 0 {
+			workspace.DeletingAt.Time.Before(now) {
 			if build.Transition == database.WorkspaceTransitionDelete &&
 				job.JobStatus == database.ProvisionerJobStatusFailed {
 				if job.CanceledAt.Valid && now.Sub(job.CanceledAt.Time) <= 24*time.Hour {
@@ -8420,18 +9008,26 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 
 	//nolint:gosimple
 	key := database.APIKey{
-		ID:              arg.ID,
-		LifetimeSeconds: arg.LifetimeSeconds,
-		HashedSecret:    arg.HashedSecret,
-		IPAddress:       arg.IPAddress,
-		UserID:          arg.UserID,
-		ExpiresAt:       arg.ExpiresAt,
-		CreatedAt:       arg.CreatedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		LastUsed:        arg.LastUsed,
-		LoginType:       arg.LoginType,
-		Scope:           arg.Scope,
-		TokenName:       arg.TokenName,
+		ID:               arg.ID,
+		LifetimeSeconds:  arg.LifetimeSeconds,
+		HashedSecret:     arg.HashedSecret,
+		IPAddress:        arg.IPAddress,
+		UserID:           arg.UserID,
+		ExpiresAt:        arg.ExpiresAt,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		LastUsed:         arg.LastUsed,
+		LoginType:        arg.LoginType,
+		Scope:            arg.Scope,
+		TokenName:        arg.TokenName,
+		ScopePermissions: arg.ScopePermissions,
+		ScopeAllowList:   arg.ScopeAllowList,
+	}
+	if key.ScopePermissions == nil {
+		key.ScopePermissions = []string{}
+	}
+	if key.ScopeAllowList == nil {
+		key.ScopeAllowList = []string{}
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -8471,6 +9067,49 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertAuditLogChainEntry(_ context.Context, arg database.InsertAuditLogChainEntryParams) (database.AuditLogChain, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.AuditLogChain{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, entry := range q.auditLogChain {
+		if entry.OrganizationID == arg.OrganizationID && entry.Sequence == arg.Sequence {
+			return database.AuditLogChain{}, newUniqueConstraintError(database.UniqueAuditLogChainPkey)
+		}
+		if entry.AuditLogID == arg.AuditLogID {
+			return database.AuditLogChain{}, newUniqueConstraintError(database.UniqueAuditLogChainAuditLogIDKey)
+		}
+	}
+
+	entry := database.AuditLogChain(arg)
+	q.auditLogChain = append(q.auditLogChain, entry)
+	return entry, nil
+}
+
+func (q *FakeQuerier) InsertAuditLogCheckpoint(_ context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.AuditLogCheckpoint{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, checkpoint := range q.auditLogCheckpoints {
+		if checkpoint.OrganizationID == arg.OrganizationID && checkpoint.Sequence == arg.Sequence {
+			return database.AuditLogCheckpoint{}, newUniqueConstraintError(database.UniqueAuditLogCheckpointsPkey)
+		}
+	}
+
+	checkpoint := database.AuditLogCheckpoint(arg)
+	q.auditLogCheckpoints = append(q.auditLogCheckpoints, checkpoint)
+	return checkpoint, nil
+}
+
 func (q *FakeQuerier) InsertChat(ctx context.Context, arg database.InsertChatParams) (database.Chat, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -8848,6 +9487,26 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 	return newGroups, nil
 }
 
+func (q *FakeQuerier) InsertNotificationDigestEntry(_ context.Context, arg database.InsertNotificationDigestEntryParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.notificationDigestEntries = append(q.notificationDigestEntries, database.NotificationDigestEntry{
+		ID:                     arg.ID,
+		UserID:                 arg.UserID,
+		NotificationTemplateID: arg.NotificationTemplateID,
+		Title:                  arg.Title,
+		Payload:                arg.Payload,
+		CreatedAt:              arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9146,60 +9805,147 @@ func (q *FakeQuerier) InsertProvisionerJobTimings(_ context.Context, arg databas
 		insertedTimings = append(insertedTimings, timing)
 	}
 
-	return insertedTimings, nil
+	return insertedTimings, nil
+}
+
+func (q *FakeQuerier) InsertProvisionerKey(_ context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.ID == arg.ID || (key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name)) {
+			return database.ProvisionerKey{}, newUniqueConstraintError(database.UniqueProvisionerKeysOrganizationIDNameIndex)
+		}
+	}
+
+	//nolint:gosimple
+	provisionerKey := database.ProvisionerKey{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		OrganizationID: arg.OrganizationID,
+		Name:           strings.ToLower(arg.Name),
+		HashedSecret:   arg.HashedSecret,
+		Tags:           arg.Tags,
+	}
+	q.provisionerKeys = append(q.provisionerKeys, provisionerKey)
+
+	return provisionerKey, nil
+}
+
+func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Replica{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	replica := database.Replica{
+		ID:              arg.ID,
+		CreatedAt:       arg.CreatedAt,
+		StartedAt:       arg.StartedAt,
+		UpdatedAt:       arg.UpdatedAt,
+		Hostname:        arg.Hostname,
+		RegionID:        arg.RegionID,
+		RelayAddress:    arg.RelayAddress,
+		Version:         arg.Version,
+		DatabaseLatency: arg.DatabaseLatency,
+		Primary:         arg.Primary,
+	}
+	q.replicas = append(q.replicas, replica)
+	return replica, nil
+}
+
+func (q *FakeQuerier) InsertResourceMonitor(_ context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentResourceMonitor{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID == arg.AgentID && monitor.Type == arg.Type {
+			return database.WorkspaceAgentResourceMonitor{}, errUniqueConstraint
+		}
+	}
+
+	monitor := database.WorkspaceAgentResourceMonitor{
+		AgentID:        arg.AgentID,
+		Type:           arg.Type,
+		Enabled:        arg.Enabled,
+		State:          arg.State,
+		Threshold:      arg.Threshold,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		DebouncedUntil: arg.DebouncedUntil,
+	}
+
+	q.workspaceAgentResourceMonitors = append(q.workspaceAgentResourceMonitors, monitor)
+	return monitor, nil
 }
 
-func (q *FakeQuerier) InsertProvisionerKey(_ context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+func (q *FakeQuerier) InsertSCIMGroup(_ context.Context, arg database.InsertSCIMGroupParams) (database.SCIMGroup, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
-		return database.ProvisionerKey{}, err
+		return database.SCIMGroup{}, err
 	}
 
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for _, key := range q.provisionerKeys {
-		if key.ID == arg.ID || (key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name)) {
-			return database.ProvisionerKey{}, newUniqueConstraintError(database.UniqueProvisionerKeysOrganizationIDNameIndex)
+	for _, group := range q.scimGroups {
+		if group.ID == arg.ID {
+			return database.SCIMGroup{}, newUniqueConstraintError(database.UniqueScimGroupsPkey)
+		}
+		if group.DisplayName == arg.DisplayName {
+			return database.SCIMGroup{}, newUniqueConstraintError(database.UniqueScimGroupsDisplayNameKey)
 		}
 	}
 
-	//nolint:gosimple
-	provisionerKey := database.ProvisionerKey{
-		ID:             arg.ID,
-		CreatedAt:      arg.CreatedAt,
-		OrganizationID: arg.OrganizationID,
-		Name:           strings.ToLower(arg.Name),
-		HashedSecret:   arg.HashedSecret,
-		Tags:           arg.Tags,
+	group := database.SCIMGroup{
+		ID:          arg.ID,
+		ExternalID:  arg.ExternalID,
+		DisplayName: arg.DisplayName,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
 	}
-	q.provisionerKeys = append(q.provisionerKeys, provisionerKey)
-
-	return provisionerKey, nil
+	q.scimGroups = append(q.scimGroups, group)
+	return group, nil
 }
 
-func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
-	if err := validateDatabaseType(arg); err != nil {
-		return database.Replica{}, err
+func (q *FakeQuerier) InsertSCIMGroupMembers(_ context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
 	}
 
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	replica := database.Replica{
-		ID:              arg.ID,
-		CreatedAt:       arg.CreatedAt,
-		StartedAt:       arg.StartedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		Hostname:        arg.Hostname,
-		RegionID:        arg.RegionID,
-		RelayAddress:    arg.RelayAddress,
-		Version:         arg.Version,
-		DatabaseLatency: arg.DatabaseLatency,
-		Primary:         arg.Primary,
+	for _, userID := range arg.UserIds {
+		user, err := q.getUserByIDNoLock(userID)
+		if err != nil || user.Deleted {
+			continue
+		}
+		exists := slices.ContainsFunc(q.scimGroupMembers, func(member database.SCIMGroupMember) bool {
+			return member.SCIMGroupID == arg.SCIMGroupID && member.UserID == userID
+		})
+		if exists {
+			continue
+		}
+		q.scimGroupMembers = append(q.scimGroupMembers, database.SCIMGroupMember{
+			SCIMGroupID: arg.SCIMGroupID,
+			UserID:      userID,
+		})
 	}
-	q.replicas = append(q.replicas, replica)
-	return replica, nil
+	return nil
 }
 
 func (q *FakeQuerier) InsertTelemetryItemIfNotExists(_ context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
@@ -9586,6 +10332,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		LastUsedAt:        arg.LastUsedAt,
 		AutomaticUpdates:  arg.AutomaticUpdates,
 		NextStartAt:       arg.NextStartAt,
+		UserACL:           database.WorkspaceACL{},
+		GroupACL:          database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -9746,6 +10494,33 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentPortForwardingPolicy(_ context.Context, arg database.InsertWorkspaceAgentPortForwardingPolicyParams) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPortForwardingPolicy{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, policy := range q.workspaceAgentPortForwardingPolicies {
+		if policy.AgentID == arg.AgentID {
+			return database.WorkspaceAgentPortForwardingPolicy{}, errUniqueConstraint
+		}
+	}
+
+	policy := database.WorkspaceAgentPortForwardingPolicy{
+		AgentID:            arg.AgentID,
+		AllowedPorts:       arg.AllowedPorts,
+		DeniedPorts:        arg.DeniedPorts,
+		AllowedUnixSockets: arg.AllowedUnixSockets,
+		DeniedUnixSockets:  arg.DeniedUnixSockets,
+		CreatedAt:          arg.CreatedAt,
+	}
+	q.workspaceAgentPortForwardingPolicies = append(q.workspaceAgentPortForwardingPolicies, policy)
+	return policy, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentScriptTimings(_ context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -9772,6 +10547,10 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 
 	scripts := make([]database.WorkspaceAgentScript, 0)
 	for index, source := range arg.LogSourceID {
+		var dependsOn pq.StringArray
+		if err := dependsOn.Scan(arg.DependsOn[index]); err != nil {
+			return nil, xerrors.Errorf("parse depends_on: %w", err)
+		}
 		script := database.WorkspaceAgentScript{
 			LogSourceID:      source,
 			WorkspaceAgentID: arg.WorkspaceAgentID,
@@ -9783,7 +10562,15 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 			RunOnStart:       arg.RunOnStart[index],
 			RunOnStop:        arg.RunOnStop[index],
 			TimeoutSeconds:   arg.TimeoutSeconds[index],
+			DisplayName:      arg.DisplayName[index],
 			CreatedAt:        arg.CreatedAt,
+			DependsOn:        dependsOn,
+
+			ReadinessProbeCommand:         arg.ReadinessProbeCommand[index],
+			ReadinessProbeTCP:             arg.ReadinessProbeTCP[index],
+			ReadinessProbeHTTP:            arg.ReadinessProbeHTTP[index],
+			ReadinessProbeIntervalSeconds: arg.ReadinessProbeIntervalSeconds[index],
+			ReadinessProbeTimeoutSeconds:  arg.ReadinessProbeTimeoutSeconds[index],
 		}
 		scripts = append(scripts, script)
 	}
@@ -10084,6 +10871,65 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSessionRecordingChunk(_ context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.RecordingID || recording.AgentID != arg.AgentID || recording.Size != arg.OffsetBytes {
+			continue
+		}
+		recording.Size += int64(len(arg.Data))
+		q.workspaceSessionRecordings[i] = recording
+		q.workspaceSessionRecordingChunks = append(q.workspaceSessionRecordingChunks, database.WorkspaceSessionRecordingChunk{
+			RecordingID: arg.RecordingID,
+			OffsetBytes: arg.OffsetBytes,
+			Data:        slices.Clone(arg.Data),
+		})
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceSnapshot(_ context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == arg.WorkspaceID && snapshot.Name == arg.Name {
+			return database.WorkspaceSnapshot{}, newUniqueConstraintError(database.UniqueWorkspaceSnapshotsWorkspaceIDNameKey)
+		}
+	}
+
+	richParameters := arg.RichParameters
+	if len(richParameters) == 0 {
+		richParameters = json.RawMessage("[]")
+	}
+	snapshot := database.WorkspaceSnapshot{
+		ID:                arg.ID,
+		WorkspaceID:       arg.WorkspaceID,
+		Name:              arg.Name,
+		CreatedAt:         arg.CreatedAt,
+		CreatedBy:         arg.CreatedBy,
+		BuildNumber:       arg.BuildNumber,
+		TemplateVersionID: arg.TemplateVersionID,
+		ProvisionerState:  arg.ProvisionerState,
+		RichParameters:    richParameters,
+	}
+	q.workspaceSnapshots = append(q.workspaceSnapshots, snapshot)
+	return snapshot, nil
+}
+
 func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -10121,8 +10967,12 @@ func (q *FakeQuerier) ListWorkspaceAgentPortShares(_ context.Context, workspaceI
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	now := dbtime.Now()
 	shares := []database.WorkspaceAgentPortShare{}
 	for _, share := range q.workspaceAgentPortShares {
+		if share.ExpiresAt.Valid && !share.ExpiresAt.Time.After(now) {
+			continue
+		}
 		if share.WorkspaceID == workspaceID {
 			shares = append(shares, share)
 		}
@@ -10979,6 +11829,57 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateResourceMonitor(_ context.Context, arg database.UpdateResourceMonitorParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, monitor := range q.workspaceAgentResourceMonitors {
+		if monitor.AgentID != arg.AgentID || monitor.Type != arg.Type {
+			continue
+		}
+
+		monitor.State = arg.State
+		monitor.UpdatedAt = arg.UpdatedAt
+		monitor.DebouncedUntil = arg.DebouncedUntil
+		q.workspaceAgentResourceMonitors[i] = monitor
+		return nil
+	}
+
+	return nil
+}
+
+func (q *FakeQuerier) UpdateSCIMGroupByID(_ context.Context, arg database.UpdateSCIMGroupByIDParams) (database.SCIMGroup, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.SCIMGroup{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, group := range q.scimGroups {
+		if group.ID != arg.ID && group.DisplayName == arg.DisplayName {
+			return database.SCIMGroup{}, newUniqueConstraintError(database.UniqueScimGroupsDisplayNameKey)
+		}
+	}
+	for i, group := range q.scimGroups {
+		if group.ID != arg.ID {
+			continue
+		}
+		group.ExternalID = arg.ExternalID
+		group.DisplayName = arg.DisplayName
+		group.UpdatedAt = arg.UpdatedAt
+		q.scimGroups[i] = group
+		return group, nil
+	}
+	return database.SCIMGroup{}, sql.ErrNoRows
+}
+
 func (*FakeQuerier) UpdateTailnetPeerStatusByCoordinator(context.Context, database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	return ErrUnimplemented
 }
@@ -11085,6 +11986,8 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.AllowUserCancelWorkspaceJobs = arg.AllowUserCancelWorkspaceJobs
 		tpl.MaxPortSharingLevel = arg.MaxPortSharingLevel
 		tpl.UseClassicParameterFlow = arg.UseClassicParameterFlow
+		tpl.SessionRecording = arg.SessionRecording
+		tpl.ActivityBumpRequiresUserActivity = arg.ActivityBumpRequiresUserActivity
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -11392,7 +12295,16 @@ func (q *FakeQuerier) UpdateUserNotificationPreferences(_ context.Context, arg d
 			found      bool
 			templateID = arg.NotificationTemplateIds[i]
 			disabled   = arg.Disableds[i]
+			method     database.NullNotificationMethod
+			digest     bool
 		)
+		// Mimic UNNEST, which pads the shorter arrays with NULLs.
+		if i < len(arg.Methods) && arg.Methods[i] != "" {
+			method = database.NullNotificationMethod{NotificationMethod: database.NotificationMethod(arg.Methods[i]), Valid: true}
+		}
+		if i < len(arg.Digests) {
+			digest = arg.Digests[i]
+		}
 
 		for j, np := range q.notificationPreferences {
 			if np.UserID != arg.UserID {
@@ -11404,6 +12316,8 @@ func (q *FakeQuerier) UpdateUserNotificationPreferences(_ context.Context, arg d
 			}
 
 			np.Disabled = disabled
+			np.Method = method
+			np.Digest = digest
 			np.UpdatedAt = dbtime.Now()
 			q.notificationPreferences[j] = np
 
@@ -11415,6 +12329,8 @@ func (q *FakeQuerier) UpdateUserNotificationPreferences(_ context.Context, arg d
 		if !found {
 			np := database.NotificationPreference{
 				Disabled:               disabled,
+				Method:                 method,
+				Digest:                 digest,
 				UserID:                 arg.UserID,
 				NotificationTemplateID: templateID,
 				CreatedAt:              dbtime.Now(),
@@ -11637,6 +12553,27 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.WorkspaceTable{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.ID == arg.ID {
+			workspace.GroupACL = arg.GroupACL
+			workspace.UserACL = arg.UserACL
+
+			q.workspaces[i] = workspace
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -11900,6 +12837,25 @@ func (q *FakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceDeletingAt(_ context.Context, arg database.UpdateWorkspaceDeletingAtParams) (database.WorkspaceTable, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceTable{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID || !workspace.DormantAt.Valid {
+			continue
+		}
+		workspace.DeletingAt = arg.DeletingAt
+		q.workspaces[index] = workspace
+		return workspace, nil
+	}
+	return database.WorkspaceTable{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceDormantDeletingAt(_ context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.WorkspaceTable{}, err
@@ -11982,6 +12938,35 @@ func (q *FakeQuerier) UpdateWorkspaceNextStartAt(_ context.Context, arg database
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceOwnerByID(_ context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceTable{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.OwnerID {
+				continue
+			}
+			if strings.EqualFold(other.Name, workspace.Name) {
+				return database.WorkspaceTable{}, newUniqueConstraintError(database.UniqueWorkspacesOwnerIDLowerIndex)
+			}
+		}
+		workspace.OwnerID = arg.OwnerID
+		workspace.UpdatedAt = arg.UpdatedAt
+		q.workspaces[index] = workspace
+		return workspace, nil
+	}
+	return database.WorkspaceTable{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -12947,6 +13932,7 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 		if share.WorkspaceID == arg.WorkspaceID && share.Port == arg.Port && share.AgentName == arg.AgentName {
 			share.ShareLevel = arg.ShareLevel
 			share.Protocol = arg.Protocol
+			share.ExpiresAt = arg.ExpiresAt
 			q.workspaceAgentPortShares[i] = share
 			return share, nil
 		}
@@ -12959,6 +13945,7 @@ func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg datab
 		Port:        arg.Port,
 		ShareLevel:  arg.ShareLevel,
 		Protocol:    arg.Protocol,
+		ExpiresAt:   arg.ExpiresAt,
 	}
 	q.workspaceAgentPortShares = append(q.workspaceAgentPortShares, psl)
 
@@ -13023,6 +14010,42 @@ func (q *FakeQuerier) UpsertWorkspaceAppAuditSession(_ context.Context, arg data
 	return true, nil
 }
 
+func (q *FakeQuerier) UpsertWorkspaceSessionRecording(_ context.Context, arg database.UpsertWorkspaceSessionRecordingParams) (int64, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		if recording.AgentID != arg.AgentID {
+			return 0, nil
+		}
+		recording.StartedAt = arg.StartedAt
+		recording.EndedAt = arg.EndedAt
+		recording.Size = 0
+		q.workspaceSessionRecordings[i] = recording
+		return 1, nil
+	}
+
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, database.WorkspaceSessionRecording{
+		ID:             arg.ID,
+		WorkspaceID:    arg.WorkspaceID,
+		AgentID:        arg.AgentID,
+		ConnectionID:   arg.ConnectionID,
+		ConnectionType: arg.ConnectionType,
+		StartedAt:      arg.StartedAt,
+		EndedAt:        arg.EndedAt,
+		CreatedAt:      arg.CreatedAt,
+	})
+	return 1, nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -13719,3 +14742,75 @@ func (q *FakeQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg data
 
 	return logs, nil
 }
+
+func (q *FakeQuerier) GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call this to match the same function calls as the SQL implementation.
+	if prepared != nil {
+		_, err := prepared.CompileToSQL(ctx, regosql.ConvertConfig{
+			VariableConverter: regosql.SessionRecordingConverter(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceSessionRecordingsRow, 0)
+	for _, recording := range q.workspaceSessionRecordings {
+		if arg.WorkspaceID != uuid.Nil && recording.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if arg.ConnectionID != uuid.Nil && recording.ConnectionID != arg.ConnectionID {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, recording.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		agent, err := q.getWorkspaceAgentByIDNoLock(ctx, recording.AgentID)
+		if err != nil {
+			return nil, err
+		}
+		row := database.GetWorkspaceSessionRecordingsRow{
+			ID:               recording.ID,
+			WorkspaceID:      recording.WorkspaceID,
+			AgentID:          recording.AgentID,
+			ConnectionID:     recording.ConnectionID,
+			ConnectionType:   recording.ConnectionType,
+			StartedAt:        recording.StartedAt,
+			EndedAt:          recording.EndedAt,
+			CreatedAt:        recording.CreatedAt,
+			Size:             recording.Size,
+			OrganizationID:   workspace.OrganizationID,
+			WorkspaceOwnerID: workspace.OwnerID,
+			WorkspaceName:    workspace.Name,
+			AgentName:        agent.Name,
+		}
+		// If the filter exists, ensure the object is authorized.
+		if prepared != nil && prepared.Authorize(ctx, row.RBACObject()) != nil {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceSessionRecordingsRow) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(rows) {
+			return []database.GetWorkspaceSessionRecordingsRow{}, nil
+		}
+		rows = rows[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(rows) {
+		rows = rows[:arg.LimitOpt]
+	}
+	return rows, nil
+}
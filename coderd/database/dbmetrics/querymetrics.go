@@ -312,6 +312,13 @@ func (m queryMetricsStore) DeleteLicense(ctx context.Context, id int32) (int32,
 	return licenseID, err
 }
 
+func (m queryMetricsStore) DeleteNotificationDigestEntries(ctx context.Context, ids []uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteNotificationDigestEntries(ctx, ids)
+	m.queryLatencies.WithLabelValues("DeleteNotificationDigestEntries").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
@@ -403,6 +410,20 @@ func (m queryMetricsStore) DeleteRuntimeConfig(ctx context.Context, key string)
 	return r0
 }
 
+func (m queryMetricsStore) DeleteSCIMGroupByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteSCIMGroupByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteSCIMGroupByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteSCIMGroupMembers(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetAgent(ctx, arg)
@@ -466,6 +487,27 @@ func (m queryMetricsStore) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.
 	return r0
 }
 
+func (m queryMetricsStore) DeleteWorkspaceAppsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceAppsByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAppsByIDs").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceSessionRecordingChunks(ctx context.Context, arg database.DeleteWorkspaceSessionRecordingChunksParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceSessionRecordingChunks(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceSessionRecordingChunks").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DisableForeignKeysAndTriggers(ctx)
@@ -508,6 +550,20 @@ func (m queryMetricsStore) FetchNewMessageMetadata(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m queryMetricsStore) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	start := time.Now()
+	r0, r1 := m.s.FetchResourceMonitorsByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("FetchResourceMonitorsByAgentID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) FetchResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentResourceMonitor, error) {
+	start := time.Now()
+	r0, r1 := m.s.FetchResourceMonitorsUpdatedAfter(ctx, updatedAt)
+	m.queryLatencies.WithLabelValues("FetchResourceMonitorsUpdatedAfter").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	start := time.Now()
 	r0, r1 := m.s.FetchVolumesResourceMonitorsByAgentID(ctx, agentID)
@@ -620,6 +676,41 @@ func (m queryMetricsStore) GetApplicationName(ctx context.Context) (string, erro
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetAuditLogChainEntries(ctx context.Context, arg database.GetAuditLogChainEntriesParams) ([]database.AuditLogChain, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogChainEntries(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogChainEntries").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogChainHead(ctx context.Context, organizationID uuid.UUID) (database.AuditLogChain, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogChainHead(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetAuditLogChainHead").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogChains(ctx context.Context) ([]database.GetAuditLogChainsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogChains(ctx)
+	m.queryLatencies.WithLabelValues("GetAuditLogChains").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogCheckpoints(ctx context.Context, arg database.GetAuditLogCheckpointsParams) ([]database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogCheckpoints(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogCheckpoints").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetAuditLogsByIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
@@ -844,6 +935,13 @@ func (m queryMetricsStore) GetGroupMembersByGroupID(ctx context.Context, arg dat
 	return users, err
 }
 
+func (m queryMetricsStore) GetGroupMembersByGroupIDs(ctx context.Context, arg database.GetGroupMembersByGroupIDsParams) ([]database.GroupMember, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupMembersByGroupIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetGroupMembersByGroupIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetGroupMembersCountByGroupID(ctx context.Context, arg database.GetGroupMembersCountByGroupIDParams) (int64, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetGroupMembersCountByGroupID(ctx, arg)
@@ -893,6 +991,13 @@ func (m queryMetricsStore) GetLastUpdateCheck(ctx context.Context) (string, erro
 	return version, err
 }
 
+func (m queryMetricsStore) GetLatestAuditLogCheckpoint(ctx context.Context, organizationID uuid.UUID) (database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestAuditLogCheckpoint(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetLatestAuditLogCheckpoint").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetLatestCryptoKeyByFeature(ctx context.Context, feature database.CryptoKeyFeature) (database.CryptoKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetLatestCryptoKeyByFeature(ctx, feature)
@@ -949,6 +1054,13 @@ func (m queryMetricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m queryMetricsStore) GetNotificationDigestEntriesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationDigestEntry, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationDigestEntriesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetNotificationDigestEntriesByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetNotificationMessagesByStatus(ctx, arg)
@@ -1299,6 +1411,34 @@ func (m queryMetricsStore) GetRuntimeConfig(ctx context.Context, key string) (st
 	return r0, r1
 }
 
+func (m queryMetricsStore) GetSCIMGroupByID(ctx context.Context, id uuid.UUID) (database.SCIMGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMGroupByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSCIMGroupByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSCIMGroupMembers(ctx context.Context, scimGroupIds []uuid.UUID) ([]database.GetSCIMGroupMembersRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMGroupMembers(ctx, scimGroupIds)
+	m.queryLatencies.WithLabelValues("GetSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSCIMGroups(ctx context.Context, displayName string) ([]database.SCIMGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMGroups(ctx, displayName)
+	m.queryLatencies.WithLabelValues("GetSCIMGroups").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetSCIMGroupsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]database.GetSCIMGroupsByUserIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSCIMGroupsByUserIDs(ctx, userIds)
+	m.queryLatencies.WithLabelValues("GetSCIMGroupsByUserIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetAgents(ctx, id)
@@ -1551,6 +1691,13 @@ func (m queryMetricsStore) GetUserCount(ctx context.Context, includeSystem bool)
 	return count, err
 }
 
+func (m queryMetricsStore) GetUserIDsWithDueNotificationDigests(ctx context.Context, createdBefore time.Time) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserIDsWithDueNotificationDigests(ctx, createdBefore)
+	m.queryLatencies.WithLabelValues("GetUserIDsWithDueNotificationDigests").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserLatencyInsights(ctx, arg)
@@ -1698,6 +1845,13 @@ func (m queryMetricsStore) GetWorkspaceAgentMetadata(ctx context.Context, worksp
 	return metadata, err
 }
 
+func (m queryMetricsStore) GetWorkspaceAgentPortForwardingPolicy(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPortForwardingPolicy(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortForwardingPolicy").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentPortShare(ctx, arg)
@@ -1887,6 +2041,13 @@ func (m queryMetricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, wor
 	return workspace, err
 }
 
+func (m queryMetricsStore) GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx context.Context, arg database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceIDsByOwnerIDAndTemplateIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceModulesByJobID(ctx, jobID)
@@ -1971,6 +2132,48 @@ func (m queryMetricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context
 	return resources, err
 }
 
+func (m queryMetricsStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceSessionRecordingByIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordingChunks(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordingChunks").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSessionRecordings(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSessionRecordings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByWorkspaceIDAndName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotsByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
@@ -2027,6 +2230,20 @@ func (m queryMetricsStore) InsertAuditLog(ctx context.Context, arg database.Inse
 	return log, err
 }
 
+func (m queryMetricsStore) InsertAuditLogChainEntry(ctx context.Context, arg database.InsertAuditLogChainEntryParams) (database.AuditLogChain, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAuditLogChainEntry(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogChainEntry").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertAuditLogCheckpoint(ctx context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertAuditLogCheckpoint(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogCheckpoint").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertChat(ctx context.Context, arg database.InsertChatParams) (database.Chat, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertChat(ctx, arg)
@@ -2139,6 +2356,13 @@ func (m queryMetricsStore) InsertMissingGroups(ctx context.Context, arg database
 	return r0, r1
 }
 
+func (m queryMetricsStore) InsertNotificationDigestEntry(ctx context.Context, arg database.InsertNotificationDigestEntryParams) error {
+	start := time.Now()
+	r0 := m.s.InsertNotificationDigestEntry(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertNotificationDigestEntry").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
@@ -2230,6 +2454,27 @@ func (m queryMetricsStore) InsertReplica(ctx context.Context, arg database.Inser
 	return replica, err
 }
 
+func (m queryMetricsStore) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertResourceMonitor(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertResourceMonitor").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertSCIMGroup(ctx context.Context, arg database.InsertSCIMGroupParams) (database.SCIMGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSCIMGroup(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSCIMGroup").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	start := time.Now()
+	r0 := m.s.InsertSCIMGroupMembers(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSCIMGroupMembers").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	start := time.Now()
 	r0 := m.s.InsertTelemetryItemIfNotExists(ctx, arg)
@@ -2363,6 +2608,13 @@ func (m queryMetricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg
 	return err
 }
 
+func (m queryMetricsStore) InsertWorkspaceAgentPortForwardingPolicy(ctx context.Context, arg database.InsertWorkspaceAgentPortForwardingPolicyParams) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentPortForwardingPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentPortForwardingPolicy").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScriptTimings(ctx, arg)
@@ -2447,6 +2699,20 @@ func (m queryMetricsStore) InsertWorkspaceResourceMetadata(ctx context.Context,
 	return metadata, err
 }
 
+func (m queryMetricsStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSessionRecordingChunk(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSessionRecordingChunk").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m queryMetricsStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSnapshot(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSnapshot").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.ListProvisionerKeysByOrganization(ctx, organizationID)
@@ -2713,6 +2979,20 @@ func (m queryMetricsStore) UpdateReplica(ctx context.Context, arg database.Updat
 	return replica, err
 }
 
+func (m queryMetricsStore) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateResourceMonitor(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateResourceMonitor").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m queryMetricsStore) UpdateSCIMGroupByID(ctx context.Context, arg database.UpdateSCIMGroupByIDParams) (database.SCIMGroup, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateSCIMGroupByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateSCIMGroupByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
@@ -2909,6 +3189,13 @@ func (m queryMetricsStore) UpdateWorkspace(ctx context.Context, arg database.Upd
 	return workspace, err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceACLByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceACLByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m queryMetricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -2993,6 +3280,13 @@ func (m queryMetricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg d
 	return err
 }
 
+func (m queryMetricsStore) UpdateWorkspaceDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDeletingAtParams) (database.WorkspaceTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceDeletingAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceDeletingAt").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	start := time.Now()
 	ws, r0 := m.s.UpdateWorkspaceDormantDeletingAt(ctx, arg)
@@ -3014,6 +3308,13 @@ func (m queryMetricsStore) UpdateWorkspaceNextStartAt(ctx context.Context, arg d
 	return r0
 }
 
+func (m queryMetricsStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceOwnerByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
@@ -3224,6 +3525,13 @@ func (m queryMetricsStore) UpsertWorkspaceAppAuditSession(ctx context.Context, a
 	return r0, r1
 }
 
+func (m queryMetricsStore) UpsertWorkspaceSessionRecording(ctx context.Context, arg database.UpsertWorkspaceSessionRecordingParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceSessionRecording").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m queryMetricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
@@ -3272,3 +3580,10 @@ func (m queryMetricsStore) GetAuthorizedAuditLogsOffset(ctx context.Context, arg
 	m.queryLatencies.WithLabelValues("GetAuthorizedAuditLogsOffset").Observe(time.Since(start).Seconds())
 	return r0, r1
 }
+
+func (m queryMetricsStore) GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuthorizedWorkspaceSessionRecordings(ctx, arg, prepared)
+	m.queryLatencies.WithLabelValues("GetAuthorizedWorkspaceSessionRecordings").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
@@ -504,6 +504,20 @@ func (mr *MockStoreMockRecorder) DeleteLicense(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicense", reflect.TypeOf((*MockStore)(nil).DeleteLicense), ctx, id)
 }
 
+// DeleteNotificationDigestEntries mocks base method.
+func (m *MockStore) DeleteNotificationDigestEntries(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationDigestEntries", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationDigestEntries indicates an expected call of DeleteNotificationDigestEntries.
+func (mr *MockStoreMockRecorder) DeleteNotificationDigestEntries(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationDigestEntries", reflect.TypeOf((*MockStore)(nil).DeleteNotificationDigestEntries), ctx, ids)
+}
+
 // DeleteOAuth2ProviderAppByID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -686,6 +700,34 @@ func (mr *MockStoreMockRecorder) DeleteRuntimeConfig(ctx, key any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuntimeConfig", reflect.TypeOf((*MockStore)(nil).DeleteRuntimeConfig), ctx, key)
 }
 
+// DeleteSCIMGroupByID mocks base method.
+func (m *MockStore) DeleteSCIMGroupByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMGroupByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMGroupByID indicates an expected call of DeleteSCIMGroupByID.
+func (mr *MockStoreMockRecorder) DeleteSCIMGroupByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMGroupByID", reflect.TypeOf((*MockStore)(nil).DeleteSCIMGroupByID), ctx, id)
+}
+
+// DeleteSCIMGroupMembers mocks base method.
+func (m *MockStore) DeleteSCIMGroupMembers(ctx context.Context, arg database.DeleteSCIMGroupMembersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSCIMGroupMembers", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSCIMGroupMembers indicates an expected call of DeleteSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) DeleteSCIMGroupMembers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).DeleteSCIMGroupMembers), ctx, arg)
+}
+
 // DeleteTailnetAgent mocks base method.
 func (m *MockStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	m.ctrl.T.Helper()
@@ -816,6 +858,48 @@ func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortSharesByTemplate(ctx, t
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortSharesByTemplate", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortSharesByTemplate), ctx, templateID)
 }
 
+// DeleteWorkspaceAppsByIDs mocks base method.
+func (m *MockStore) DeleteWorkspaceAppsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAppsByIDs", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAppsByIDs indicates an expected call of DeleteWorkspaceAppsByIDs.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAppsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAppsByIDs", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAppsByIDs), ctx, ids)
+}
+
+// DeleteWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) DeleteWorkspaceSessionRecordingChunks(ctx context.Context, arg database.DeleteWorkspaceSessionRecordingChunksParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceSessionRecordingChunks", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceSessionRecordingChunks indicates an expected call of DeleteWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceSessionRecordingChunks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSessionRecordingChunks), ctx, arg)
+}
+
+// DeleteWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceSnapshotByID", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceSnapshotByID indicates an expected call of DeleteWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceSnapshotByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceSnapshotByID), ctx, id)
+}
+
 // DisableForeignKeysAndTriggers mocks base method.
 func (m *MockStore) DisableForeignKeysAndTriggers(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -903,6 +987,36 @@ func (mr *MockStoreMockRecorder) FetchNewMessageMetadata(ctx, arg any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNewMessageMetadata", reflect.TypeOf((*MockStore)(nil).FetchNewMessageMetadata), ctx, arg)
 }
 
+// FetchResourceMonitorsByAgentID mocks base method.
+func (m *MockStore) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchResourceMonitorsByAgentID", ctx, agentID)
+	ret0, _ := ret[0].([]database.WorkspaceAgentResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchResourceMonitorsByAgentID indicates an expected call of FetchResourceMonitorsByAgentID.
+func (mr *MockStoreMockRecorder) FetchResourceMonitorsByAgentID(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchResourceMonitorsByAgentID", reflect.TypeOf((*MockStore)(nil).FetchResourceMonitorsByAgentID), ctx, agentID)
+}
+
+// FetchResourceMonitorsUpdatedAfter mocks base method.
+func (m *MockStore) FetchResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.WorkspaceAgentResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchResourceMonitorsUpdatedAfter", ctx, updatedAt)
+	ret0, _ := ret[0].([]database.WorkspaceAgentResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchResourceMonitorsUpdatedAfter indicates an expected call of FetchResourceMonitorsUpdatedAfter.
+func (mr *MockStoreMockRecorder) FetchResourceMonitorsUpdatedAfter(ctx, updatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchResourceMonitorsUpdatedAfter", reflect.TypeOf((*MockStore)(nil).FetchResourceMonitorsUpdatedAfter), ctx, updatedAt)
+}
+
 // FetchVolumesResourceMonitorsByAgentID mocks base method.
 func (m *MockStore) FetchVolumesResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceAgentVolumeResourceMonitor, error) {
 	m.ctrl.T.Helper()
@@ -1143,6 +1257,81 @@ func (mr *MockStoreMockRecorder) GetApplicationName(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), ctx)
 }
 
+// GetAuditLogChainEntries mocks base method.
+func (m *MockStore) GetAuditLogChainEntries(ctx context.Context, arg database.GetAuditLogChainEntriesParams) ([]database.AuditLogChain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogChainEntries", ctx, arg)
+	ret0, _ := ret[0].([]database.AuditLogChain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogChainEntries indicates an expected call of GetAuditLogChainEntries.
+func (mr *MockStoreMockRecorder) GetAuditLogChainEntries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogChainEntries", reflect.TypeOf((*MockStore)(nil).GetAuditLogChainEntries), ctx, arg)
+}
+
+// GetAuditLogChainHead mocks base method.
+func (m *MockStore) GetAuditLogChainHead(ctx context.Context, organizationID uuid.UUID) (database.AuditLogChain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogChainHead", ctx, organizationID)
+	ret0, _ := ret[0].(database.AuditLogChain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogChainHead indicates an expected call of GetAuditLogChainHead.
+func (mr *MockStoreMockRecorder) GetAuditLogChainHead(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogChainHead", reflect.TypeOf((*MockStore)(nil).GetAuditLogChainHead), ctx, organizationID)
+}
+
+// GetAuditLogChains mocks base method.
+func (m *MockStore) GetAuditLogChains(ctx context.Context) ([]database.GetAuditLogChainsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogChains", ctx)
+	ret0, _ := ret[0].([]database.GetAuditLogChainsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogChains indicates an expected call of GetAuditLogChains.
+func (mr *MockStoreMockRecorder) GetAuditLogChains(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogChains", reflect.TypeOf((*MockStore)(nil).GetAuditLogChains), ctx)
+}
+
+// GetAuditLogCheckpoints mocks base method.
+func (m *MockStore) GetAuditLogCheckpoints(ctx context.Context, arg database.GetAuditLogCheckpointsParams) ([]database.AuditLogCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogCheckpoints", ctx, arg)
+	ret0, _ := ret[0].([]database.AuditLogCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogCheckpoints indicates an expected call of GetAuditLogCheckpoints.
+func (mr *MockStoreMockRecorder) GetAuditLogCheckpoints(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogCheckpoints", reflect.TypeOf((*MockStore)(nil).GetAuditLogCheckpoints), ctx, arg)
+}
+
+// GetAuditLogsByIDs mocks base method.
+func (m *MockStore) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]database.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsByIDs indicates an expected call of GetAuditLogsByIDs.
+func (mr *MockStoreMockRecorder) GetAuditLogsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsByIDs", reflect.TypeOf((*MockStore)(nil).GetAuditLogsByIDs), ctx, ids)
+}
+
 // GetAuditLogsOffset mocks base method.
 func (m *MockStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	m.ctrl.T.Helper()
@@ -1218,6 +1407,21 @@ func (mr *MockStoreMockRecorder) GetAuthorizedUsers(ctx, arg, prepared any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedUsers", reflect.TypeOf((*MockStore)(nil).GetAuthorizedUsers), ctx, arg, prepared)
 }
 
+// GetAuthorizedWorkspaceSessionRecordings mocks base method.
+func (m *MockStore) GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorizedWorkspaceSessionRecordings", ctx, arg, prepared)
+	ret0, _ := ret[0].([]database.GetWorkspaceSessionRecordingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorizedWorkspaceSessionRecordings indicates an expected call of GetAuthorizedWorkspaceSessionRecordings.
+func (mr *MockStoreMockRecorder) GetAuthorizedWorkspaceSessionRecordings(ctx, arg, prepared any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedWorkspaceSessionRecordings", reflect.TypeOf((*MockStore)(nil).GetAuthorizedWorkspaceSessionRecordings), ctx, arg, prepared)
+}
+
 // GetAuthorizedWorkspaces mocks base method.
 func (m *MockStore) GetAuthorizedWorkspaces(ctx context.Context, arg database.GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspacesRow, error) {
 	m.ctrl.T.Helper()
@@ -1698,6 +1902,21 @@ func (mr *MockStoreMockRecorder) GetGroupMembersByGroupID(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembersByGroupID", reflect.TypeOf((*MockStore)(nil).GetGroupMembersByGroupID), ctx, arg)
 }
 
+// GetGroupMembersByGroupIDs mocks base method.
+func (m *MockStore) GetGroupMembersByGroupIDs(ctx context.Context, arg database.GetGroupMembersByGroupIDsParams) ([]database.GroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupMembersByGroupIDs", ctx, arg)
+	ret0, _ := ret[0].([]database.GroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupMembersByGroupIDs indicates an expected call of GetGroupMembersByGroupIDs.
+func (mr *MockStoreMockRecorder) GetGroupMembersByGroupIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembersByGroupIDs", reflect.TypeOf((*MockStore)(nil).GetGroupMembersByGroupIDs), ctx, arg)
+}
+
 // GetGroupMembersCountByGroupID mocks base method.
 func (m *MockStore) GetGroupMembersCountByGroupID(ctx context.Context, arg database.GetGroupMembersCountByGroupIDParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1803,6 +2022,21 @@ func (mr *MockStoreMockRecorder) GetLastUpdateCheck(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).GetLastUpdateCheck), ctx)
 }
 
+// GetLatestAuditLogCheckpoint mocks base method.
+func (m *MockStore) GetLatestAuditLogCheckpoint(ctx context.Context, organizationID uuid.UUID) (database.AuditLogCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestAuditLogCheckpoint", ctx, organizationID)
+	ret0, _ := ret[0].(database.AuditLogCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestAuditLogCheckpoint indicates an expected call of GetLatestAuditLogCheckpoint.
+func (mr *MockStoreMockRecorder) GetLatestAuditLogCheckpoint(ctx, organizationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAuditLogCheckpoint", reflect.TypeOf((*MockStore)(nil).GetLatestAuditLogCheckpoint), ctx, organizationID)
+}
+
 // GetLatestCryptoKeyByFeature mocks base method.
 func (m *MockStore) GetLatestCryptoKeyByFeature(ctx context.Context, feature database.CryptoKeyFeature) (database.CryptoKey, error) {
 	m.ctrl.T.Helper()
@@ -1923,6 +2157,21 @@ func (mr *MockStoreMockRecorder) GetLogoURL(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), ctx)
 }
 
+// GetNotificationDigestEntriesByUserID mocks base method.
+func (m *MockStore) GetNotificationDigestEntriesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationDigestEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationDigestEntriesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]database.NotificationDigestEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationDigestEntriesByUserID indicates an expected call of GetNotificationDigestEntriesByUserID.
+func (mr *MockStoreMockRecorder) GetNotificationDigestEntriesByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationDigestEntriesByUserID", reflect.TypeOf((*MockStore)(nil).GetNotificationDigestEntriesByUserID), ctx, userID)
+}
+
 // GetNotificationMessagesByStatus mocks base method.
 func (m *MockStore) GetNotificationMessagesByStatus(ctx context.Context, arg database.GetNotificationMessagesByStatusParams) ([]database.NotificationMessage, error) {
 	m.ctrl.T.Helper()
@@ -2673,6 +2922,66 @@ func (mr *MockStoreMockRecorder) GetRuntimeConfig(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeConfig", reflect.TypeOf((*MockStore)(nil).GetRuntimeConfig), ctx, key)
 }
 
+// GetSCIMGroupByID mocks base method.
+func (m *MockStore) GetSCIMGroupByID(ctx context.Context, id uuid.UUID) (database.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMGroupByID", ctx, id)
+	ret0, _ := ret[0].(database.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMGroupByID indicates an expected call of GetSCIMGroupByID.
+func (mr *MockStoreMockRecorder) GetSCIMGroupByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroupByID", reflect.TypeOf((*MockStore)(nil).GetSCIMGroupByID), ctx, id)
+}
+
+// GetSCIMGroupMembers mocks base method.
+func (m *MockStore) GetSCIMGroupMembers(ctx context.Context, scimGroupIds []uuid.UUID) ([]database.GetSCIMGroupMembersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMGroupMembers", ctx, scimGroupIds)
+	ret0, _ := ret[0].([]database.GetSCIMGroupMembersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMGroupMembers indicates an expected call of GetSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) GetSCIMGroupMembers(ctx, scimGroupIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).GetSCIMGroupMembers), ctx, scimGroupIds)
+}
+
+// GetSCIMGroups mocks base method.
+func (m *MockStore) GetSCIMGroups(ctx context.Context, displayName string) ([]database.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMGroups", ctx, displayName)
+	ret0, _ := ret[0].([]database.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMGroups indicates an expected call of GetSCIMGroups.
+func (mr *MockStoreMockRecorder) GetSCIMGroups(ctx, displayName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroups", reflect.TypeOf((*MockStore)(nil).GetSCIMGroups), ctx, displayName)
+}
+
+// GetSCIMGroupsByUserIDs mocks base method.
+func (m *MockStore) GetSCIMGroupsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]database.GetSCIMGroupsByUserIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSCIMGroupsByUserIDs", ctx, userIds)
+	ret0, _ := ret[0].([]database.GetSCIMGroupsByUserIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSCIMGroupsByUserIDs indicates an expected call of GetSCIMGroupsByUserIDs.
+func (mr *MockStoreMockRecorder) GetSCIMGroupsByUserIDs(ctx, userIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSCIMGroupsByUserIDs", reflect.TypeOf((*MockStore)(nil).GetSCIMGroupsByUserIDs), ctx, userIds)
+}
+
 // GetTailnetAgents mocks base method.
 func (m *MockStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -3243,6 +3552,21 @@ func (mr *MockStoreMockRecorder) GetUserCount(ctx, includeSystem any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCount", reflect.TypeOf((*MockStore)(nil).GetUserCount), ctx, includeSystem)
 }
 
+// GetUserIDsWithDueNotificationDigests mocks base method.
+func (m *MockStore) GetUserIDsWithDueNotificationDigests(ctx context.Context, createdBefore time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDsWithDueNotificationDigests", ctx, createdBefore)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDsWithDueNotificationDigests indicates an expected call of GetUserIDsWithDueNotificationDigests.
+func (mr *MockStoreMockRecorder) GetUserIDsWithDueNotificationDigests(ctx, createdBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDsWithDueNotificationDigests", reflect.TypeOf((*MockStore)(nil).GetUserIDsWithDueNotificationDigests), ctx, createdBefore)
+}
+
 // GetUserLatencyInsights mocks base method.
 func (m *MockStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -3558,6 +3882,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadata(ctx, arg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadata), ctx, arg)
 }
 
+// GetWorkspaceAgentPortForwardingPolicy mocks base method.
+func (m *MockStore) GetWorkspaceAgentPortForwardingPolicy(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPortForwardingPolicy", ctx, agentID)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortForwardingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPortForwardingPolicy indicates an expected call of GetWorkspaceAgentPortForwardingPolicy.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortForwardingPolicy(ctx, agentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortForwardingPolicy", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortForwardingPolicy), ctx, agentID)
+}
+
 // GetWorkspaceAgentPortShare mocks base method.
 func (m *MockStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
 	m.ctrl.T.Helper()
@@ -3963,6 +4302,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(ctx, workspaceAppI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), ctx, workspaceAppID)
 }
 
+// GetWorkspaceIDsByOwnerIDAndTemplateIDs mocks base method.
+func (m *MockStore) GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx context.Context, arg database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceIDsByOwnerIDAndTemplateIDs", ctx, arg)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceIDsByOwnerIDAndTemplateIDs indicates an expected call of GetWorkspaceIDsByOwnerIDAndTemplateIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceIDsByOwnerIDAndTemplateIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceIDsByOwnerIDAndTemplateIDs), ctx, arg)
+}
+
 // GetWorkspaceModulesByJobID mocks base method.
 func (m *MockStore) GetWorkspaceModulesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceModule, error) {
 	m.ctrl.T.Helper()
@@ -4143,6 +4497,96 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(ctx, createdA
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), ctx, createdAt)
 }
 
+// GetWorkspaceSessionRecordingByID mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceSessionRecordingByIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingByID", ctx, id)
+	ret0, _ := ret[0].(database.GetWorkspaceSessionRecordingByIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingByID indicates an expected call of GetWorkspaceSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingByID), ctx, id)
+}
+
+// GetWorkspaceSessionRecordingChunks mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg database.GetWorkspaceSessionRecordingChunksParams) ([]database.WorkspaceSessionRecordingChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordingChunks", ctx, arg)
+	ret0, _ := ret[0].([]database.WorkspaceSessionRecordingChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordingChunks indicates an expected call of GetWorkspaceSessionRecordingChunks.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordingChunks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordingChunks", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordingChunks), ctx, arg)
+}
+
+// GetWorkspaceSessionRecordings mocks base method.
+func (m *MockStore) GetWorkspaceSessionRecordings(ctx context.Context, arg database.GetWorkspaceSessionRecordingsParams) ([]database.GetWorkspaceSessionRecordingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSessionRecordings", ctx, arg)
+	ret0, _ := ret[0].([]database.GetWorkspaceSessionRecordingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSessionRecordings indicates an expected call of GetWorkspaceSessionRecordings.
+func (mr *MockStoreMockRecorder) GetWorkspaceSessionRecordings(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSessionRecordings", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSessionRecordings), ctx, arg)
+}
+
+// GetWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByID", ctx, id)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByID indicates an expected call of GetWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByID), ctx, id)
+}
+
+// GetWorkspaceSnapshotByWorkspaceIDAndName mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByWorkspaceIDAndName", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByWorkspaceIDAndName indicates an expected call of GetWorkspaceSnapshotByWorkspaceIDAndName.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByWorkspaceIDAndName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByWorkspaceIDAndName), ctx, arg)
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotsByWorkspaceID", ctx, workspaceID)
+	ret0, _ := ret[0].([]database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID indicates an expected call of GetWorkspaceSnapshotsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotsByWorkspaceID), ctx, workspaceID)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -4277,6 +4721,36 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), ctx, arg)
 }
 
+// InsertAuditLogChainEntry mocks base method.
+func (m *MockStore) InsertAuditLogChainEntry(ctx context.Context, arg database.InsertAuditLogChainEntryParams) (database.AuditLogChain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditLogChainEntry", ctx, arg)
+	ret0, _ := ret[0].(database.AuditLogChain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAuditLogChainEntry indicates an expected call of InsertAuditLogChainEntry.
+func (mr *MockStoreMockRecorder) InsertAuditLogChainEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLogChainEntry", reflect.TypeOf((*MockStore)(nil).InsertAuditLogChainEntry), ctx, arg)
+}
+
+// InsertAuditLogCheckpoint mocks base method.
+func (m *MockStore) InsertAuditLogCheckpoint(ctx context.Context, arg database.InsertAuditLogCheckpointParams) (database.AuditLogCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditLogCheckpoint", ctx, arg)
+	ret0, _ := ret[0].(database.AuditLogCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAuditLogCheckpoint indicates an expected call of InsertAuditLogCheckpoint.
+func (mr *MockStoreMockRecorder) InsertAuditLogCheckpoint(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLogCheckpoint", reflect.TypeOf((*MockStore)(nil).InsertAuditLogCheckpoint), ctx, arg)
+}
+
 // InsertChat mocks base method.
 func (m *MockStore) InsertChat(ctx context.Context, arg database.InsertChatParams) (database.Chat, error) {
 	m.ctrl.T.Helper()
@@ -4513,6 +4987,20 @@ func (mr *MockStoreMockRecorder) InsertMissingGroups(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), ctx, arg)
 }
 
+// InsertNotificationDigestEntry mocks base method.
+func (m *MockStore) InsertNotificationDigestEntry(ctx context.Context, arg database.InsertNotificationDigestEntryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotificationDigestEntry", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertNotificationDigestEntry indicates an expected call of InsertNotificationDigestEntry.
+func (mr *MockStoreMockRecorder) InsertNotificationDigestEntry(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotificationDigestEntry", reflect.TypeOf((*MockStore)(nil).InsertNotificationDigestEntry), ctx, arg)
+}
+
 // InsertOAuth2ProviderApp mocks base method.
 func (m *MockStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
@@ -4708,6 +5196,50 @@ func (mr *MockStoreMockRecorder) InsertReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), ctx, arg)
 }
 
+// InsertResourceMonitor mocks base method.
+func (m *MockStore) InsertResourceMonitor(ctx context.Context, arg database.InsertResourceMonitorParams) (database.WorkspaceAgentResourceMonitor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertResourceMonitor", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentResourceMonitor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertResourceMonitor indicates an expected call of InsertResourceMonitor.
+func (mr *MockStoreMockRecorder) InsertResourceMonitor(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertResourceMonitor", reflect.TypeOf((*MockStore)(nil).InsertResourceMonitor), ctx, arg)
+}
+
+// InsertSCIMGroup mocks base method.
+func (m *MockStore) InsertSCIMGroup(ctx context.Context, arg database.InsertSCIMGroupParams) (database.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSCIMGroup", ctx, arg)
+	ret0, _ := ret[0].(database.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSCIMGroup indicates an expected call of InsertSCIMGroup.
+func (mr *MockStoreMockRecorder) InsertSCIMGroup(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSCIMGroup", reflect.TypeOf((*MockStore)(nil).InsertSCIMGroup), ctx, arg)
+}
+
+// InsertSCIMGroupMembers mocks base method.
+func (m *MockStore) InsertSCIMGroupMembers(ctx context.Context, arg database.InsertSCIMGroupMembersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSCIMGroupMembers", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSCIMGroupMembers indicates an expected call of InsertSCIMGroupMembers.
+func (mr *MockStoreMockRecorder) InsertSCIMGroupMembers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSCIMGroupMembers", reflect.TypeOf((*MockStore)(nil).InsertSCIMGroupMembers), ctx, arg)
+}
+
 // InsertTelemetryItemIfNotExists mocks base method.
 func (m *MockStore) InsertTelemetryItemIfNotExists(ctx context.Context, arg database.InsertTelemetryItemIfNotExistsParams) error {
 	m.ctrl.T.Helper()
@@ -4987,6 +5519,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(ctx, arg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), ctx, arg)
 }
 
+// InsertWorkspaceAgentPortForwardingPolicy mocks base method.
+func (m *MockStore) InsertWorkspaceAgentPortForwardingPolicy(ctx context.Context, arg database.InsertWorkspaceAgentPortForwardingPolicyParams) (database.WorkspaceAgentPortForwardingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentPortForwardingPolicy", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortForwardingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentPortForwardingPolicy indicates an expected call of InsertWorkspaceAgentPortForwardingPolicy.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentPortForwardingPolicy(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentPortForwardingPolicy", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentPortForwardingPolicy), ctx, arg)
+}
+
 // InsertWorkspaceAgentScriptTimings mocks base method.
 func (m *MockStore) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingsParams) (database.WorkspaceAgentScriptTiming, error) {
 	m.ctrl.T.Helper()
@@ -5163,6 +5710,36 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(ctx, arg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), ctx, arg)
 }
 
+// InsertWorkspaceSessionRecordingChunk mocks base method.
+func (m *MockStore) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg database.InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSessionRecordingChunk", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSessionRecordingChunk indicates an expected call of InsertWorkspaceSessionRecordingChunk.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSessionRecordingChunk(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSessionRecordingChunk", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSessionRecordingChunk), ctx, arg)
+}
+
+// InsertWorkspaceSnapshot mocks base method.
+func (m *MockStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSnapshot", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSnapshot indicates an expected call of InsertWorkspaceSnapshot.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSnapshot(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSnapshot", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSnapshot), ctx, arg)
+}
+
 // ListProvisionerKeysByOrganization mocks base method.
 func (m *MockStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
 	m.ctrl.T.Helper()
@@ -5747,6 +6324,35 @@ func (mr *MockStoreMockRecorder) UpdateReplica(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), ctx, arg)
 }
 
+// UpdateResourceMonitor mocks base method.
+func (m *MockStore) UpdateResourceMonitor(ctx context.Context, arg database.UpdateResourceMonitorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResourceMonitor", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateResourceMonitor indicates an expected call of UpdateResourceMonitor.
+func (mr *MockStoreMockRecorder) UpdateResourceMonitor(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResourceMonitor", reflect.TypeOf((*MockStore)(nil).UpdateResourceMonitor), ctx, arg)
+}
+
+// UpdateSCIMGroupByID mocks base method.
+func (m *MockStore) UpdateSCIMGroupByID(ctx context.Context, arg database.UpdateSCIMGroupByIDParams) (database.SCIMGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSCIMGroupByID", ctx, arg)
+	ret0, _ := ret[0].(database.SCIMGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSCIMGroupByID indicates an expected call of UpdateSCIMGroupByID.
+func (mr *MockStoreMockRecorder) UpdateSCIMGroupByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSCIMGroupByID", reflect.TypeOf((*MockStore)(nil).UpdateSCIMGroupByID), ctx, arg)
+}
+
 // UpdateTailnetPeerStatusByCoordinator mocks base method.
 func (m *MockStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
 	m.ctrl.T.Helper()
@@ -6151,6 +6757,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), ctx, arg)
 }
 
+// UpdateWorkspaceACLByID mocks base method.
+func (m *MockStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceACLByID", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceACLByID indicates an expected call of UpdateWorkspaceACLByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceACLByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceACLByID), ctx, arg)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -6319,6 +6939,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceDeletedByID(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDeletedByID), ctx, arg)
 }
 
+// UpdateWorkspaceDeletingAt mocks base method.
+func (m *MockStore) UpdateWorkspaceDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDeletingAtParams) (database.WorkspaceTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceDeletingAt", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceDeletingAt indicates an expected call of UpdateWorkspaceDeletingAt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceDeletingAt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDeletingAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDeletingAt), ctx, arg)
+}
+
 // UpdateWorkspaceDormantDeletingAt mocks base method.
 func (m *MockStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.WorkspaceTable, error) {
 	m.ctrl.T.Helper()
@@ -6362,6 +6997,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceNextStartAt(ctx, arg any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceNextStartAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceNextStartAt), ctx, arg)
 }
 
+// UpdateWorkspaceOwnerByID mocks base method.
+func (m *MockStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.WorkspaceTable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceOwnerByID", ctx, arg)
+	ret0, _ := ret[0].(database.WorkspaceTable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceOwnerByID indicates an expected call of UpdateWorkspaceOwnerByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceOwnerByID(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceOwnerByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceOwnerByID), ctx, arg)
+}
+
 // UpdateWorkspaceProxy mocks base method.
 func (m *MockStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -6792,6 +7442,21 @@ func (mr *MockStoreMockRecorder) UpsertWorkspaceAppAuditSession(ctx, arg any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAppAuditSession", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAppAuditSession), ctx, arg)
 }
 
+// UpsertWorkspaceSessionRecording mocks base method.
+func (m *MockStore) UpsertWorkspaceSessionRecording(ctx context.Context, arg database.UpsertWorkspaceSessionRecordingParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceSessionRecording", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceSessionRecording indicates an expected call of UpsertWorkspaceSessionRecording.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceSessionRecording(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceSessionRecording", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceSessionRecording), ctx, arg)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
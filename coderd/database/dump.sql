@@ -48,7 +48,8 @@ CREATE TYPE build_reason AS ENUM (
     'autostop',
     'dormancy',
     'failedstop',
-    'autodelete'
+    'autodelete',
+    'deprovision'
 );
 
 CREATE TYPE crypto_key_feature AS ENUM (
@@ -119,7 +120,10 @@ CREATE TYPE notification_message_status AS ENUM (
 CREATE TYPE notification_method AS ENUM (
     'smtp',
     'webhook',
-    'inbox'
+    'inbox',
+    'slack',
+    'teams',
+    'webpush'
 );
 
 CREATE TYPE notification_template_kind AS ENUM (
@@ -219,7 +223,10 @@ CREATE TYPE resource_type AS ENUM (
     'idp_sync_settings_group',
     'idp_sync_settings_role',
     'workspace_agent',
-    'workspace_app'
+    'workspace_app',
+    'workspace_agent_port_share',
+    'scim_group',
+    'workspace_snapshot'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -261,6 +268,13 @@ CREATE TYPE workspace_agent_monitor_state AS ENUM (
     'NOK'
 );
 
+CREATE TYPE workspace_agent_resource_monitor_type AS ENUM (
+    'cpu',
+    'network',
+    'file_descriptors',
+    'processes'
+);
+
 CREATE TYPE workspace_agent_script_timing_stage AS ENUM (
     'start',
     'stop',
@@ -737,11 +751,45 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scope_permissions text[] DEFAULT '{}'::text[] NOT NULL,
+    scope_allow_list text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scope_permissions IS 'Resource and action pairs, such as workspace:read, that further limit the scope of the key. Empty means the key is only limited by its scope.';
+
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'Typed resource IDs, such as template:<id>, the key is limited to. Empty allows every resource.';
+
+CREATE TABLE audit_log_chain (
+    organization_id uuid NOT NULL,
+    sequence bigint NOT NULL,
+    audit_log_id uuid NOT NULL,
+    prev_hash bytea NOT NULL,
+    hash bytea NOT NULL
+);
+
+COMMENT ON TABLE audit_log_chain IS 'Links each audit log to the one inserted before it in the same organization, so that modified, deleted or reordered audit logs can be detected.';
+
+COMMENT ON COLUMN audit_log_chain.organization_id IS 'The organization whose chain this entry belongs to. Deployment-wide audit logs use the nil UUID.';
+
+COMMENT ON COLUMN audit_log_chain.prev_hash IS 'The hash of the previous entry in the chain. The first entry uses 32 zero bytes.';
+
+COMMENT ON COLUMN audit_log_chain.hash IS 'SHA-256 of the previous hash, the sequence and the canonical encoding of the audit log.';
+
+CREATE TABLE audit_log_checkpoints (
+    organization_id uuid NOT NULL,
+    sequence bigint NOT NULL,
+    hash bytea NOT NULL,
+    signature bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE audit_log_checkpoints IS 'Periodic signatures of the head of each audit log chain, made with the deployment''s audit log signing key.';
+
+COMMENT ON COLUMN audit_log_checkpoints.signature IS 'An ed25519 signature over the organization, sequence and hash of the chain entry.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -1009,6 +1057,19 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE notification_digest_entries (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    notification_template_id uuid NOT NULL,
+    title text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE notification_digest_entries IS 'Notifications held back from immediate delivery until the next digest is sent to the user.';
+
+COMMENT ON COLUMN notification_digest_entries.title IS 'Plaintext title rendered at enqueue time';
+
 CREATE TABLE notification_messages (
     id uuid NOT NULL,
     notification_template_id uuid NOT NULL,
@@ -1035,9 +1096,15 @@ CREATE TABLE notification_preferences (
     notification_template_id uuid NOT NULL,
     disabled boolean DEFAULT false NOT NULL,
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
-    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    method notification_method,
+    digest boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN notification_preferences.method IS 'NULL defers to the template-level method, and then to the deployment-level method';
+
+COMMENT ON COLUMN notification_preferences.digest IS 'Batch messages into a periodic digest instead of delivering them immediately; only honored for digestible templates';
+
 CREATE TABLE notification_report_generator_logs (
     notification_template_id uuid NOT NULL,
     last_generated_at timestamp with time zone NOT NULL
@@ -1054,13 +1121,16 @@ CREATE TABLE notification_templates (
     "group" text,
     method notification_method,
     kind notification_template_kind DEFAULT 'system'::notification_template_kind NOT NULL,
-    enabled_by_default boolean DEFAULT true NOT NULL
+    enabled_by_default boolean DEFAULT true NOT NULL,
+    digestible boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON TABLE notification_templates IS 'Templates from which to create notification messages.';
 
 COMMENT ON COLUMN notification_templates.method IS 'NULL defers to the deployment-level method';
 
+COMMENT ON COLUMN notification_templates.digestible IS 'Whether users may choose to receive messages of this template in a periodic digest';
+
 CREATE TABLE oauth2_provider_app_codes (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1277,6 +1347,23 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE scim_group_members (
+    scim_group_id uuid NOT NULL,
+    user_id uuid NOT NULL
+);
+
+CREATE TABLE scim_groups (
+    id uuid NOT NULL,
+    external_id text DEFAULT ''::text NOT NULL,
+    display_name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE scim_groups IS 'Groups pushed by an identity provider through SCIM. Members are mapped onto Coder groups using the group sync settings of each organization.';
+
+COMMENT ON COLUMN scim_groups.external_id IS 'The identifier of the group in the identity provider, if it provided one.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value text NOT NULL
@@ -1561,7 +1648,9 @@ CREATE TABLE templates (
     deprecated text DEFAULT ''::text NOT NULL,
     activity_bump bigint DEFAULT '3600000000000'::bigint NOT NULL,
     max_port_sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
-    use_classic_parameter_flow boolean DEFAULT false NOT NULL
+    use_classic_parameter_flow boolean DEFAULT false NOT NULL,
+    session_recording boolean DEFAULT false NOT NULL,
+    activity_bump_requires_user_activity boolean DEFAULT false NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -1584,6 +1673,10 @@ COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the tem
 
 COMMENT ON COLUMN templates.use_classic_parameter_flow IS 'Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.';
 
+COMMENT ON COLUMN templates.session_recording IS 'Record interactive terminal sessions of workspaces created from this template, for auditing.';
+
+COMMENT ON COLUMN templates.activity_bump_requires_user_activity IS 'Only bump the deadline of workspaces created from this template on user activity reported by the agent, instead of on any open connection.';
+
 CREATE VIEW template_with_names AS
  SELECT templates.id,
     templates.created_at,
@@ -1614,6 +1707,8 @@ CREATE VIEW template_with_names AS
     templates.activity_bump,
     templates.max_port_sharing_level,
     templates.use_classic_parameter_flow,
+    templates.session_recording,
+    templates.activity_bump_requires_user_activity,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username,
     COALESCE(organizations.name, ''::text) AS organization_name,
@@ -1740,23 +1835,59 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
 
 COMMENT ON COLUMN workspace_agent_metadata.display_order IS 'Specifies the order in which to display agent metadata in user interfaces.';
 
+CREATE TABLE workspace_agent_port_forwarding_policies (
+    agent_id uuid NOT NULL,
+    allowed_ports text[] DEFAULT '{}'::text[] NOT NULL,
+    denied_ports text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_unix_sockets text[] DEFAULT '{}'::text[] NOT NULL,
+    denied_unix_sockets text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_port_forwarding_policies IS 'Restricts the TCP ports and Unix sockets in the workspace that clients can connect to through the agent.';
+
+COMMENT ON COLUMN workspace_agent_port_forwarding_policies.allowed_ports IS 'Ports or port ranges like 3000-3999. An empty list allows all ports that are not denied.';
+
+COMMENT ON COLUMN workspace_agent_port_forwarding_policies.allowed_unix_sockets IS 'Path patterns of Unix sockets. An empty list allows all sockets that are not denied.';
+
 CREATE TABLE workspace_agent_port_share (
     workspace_id uuid NOT NULL,
     agent_name text NOT NULL,
     port integer NOT NULL,
     share_level app_sharing_level NOT NULL,
-    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL
+    protocol port_share_protocol DEFAULT 'http'::port_share_protocol NOT NULL,
+    expires_at timestamp with time zone
+);
+
+COMMENT ON COLUMN workspace_agent_port_share.expires_at IS 'The time after which the port is no longer shared. Shares without an expiry never expire.';
+
+CREATE TABLE workspace_agent_resource_monitors (
+    agent_id uuid NOT NULL,
+    type workspace_agent_resource_monitor_type NOT NULL,
+    enabled boolean NOT NULL,
+    threshold integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    state workspace_agent_monitor_state DEFAULT 'OK'::workspace_agent_monitor_state NOT NULL,
+    debounced_until timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+COMMENT ON TABLE workspace_agent_resource_monitors IS 'Resource monitors of workspace agents that have a single threshold per agent. Memory and volumes have dedicated tables.';
+
+COMMENT ON COLUMN workspace_agent_resource_monitors.threshold IS 'The threshold in percent of the available resource, except for network monitors where it is the throughput in Mbit/s.';
+
 CREATE TABLE workspace_agent_script_timings (
     script_id uuid NOT NULL,
     started_at timestamp with time zone NOT NULL,
     ended_at timestamp with time zone NOT NULL,
     exit_code integer NOT NULL,
     stage workspace_agent_script_timing_stage NOT NULL,
-    status workspace_agent_script_timing_status NOT NULL
+    status workspace_agent_script_timing_status NOT NULL,
+    queued_at timestamp with time zone NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_script_timings.queued_at IS 'Time the script was due to run. It is before started_at when the script waited for the scripts it depends on.';
+
 CREATE TABLE workspace_agent_scripts (
     workspace_agent_id uuid NOT NULL,
     log_source_id uuid NOT NULL,
@@ -1769,9 +1900,23 @@ CREATE TABLE workspace_agent_scripts (
     run_on_stop boolean NOT NULL,
     timeout_seconds integer NOT NULL,
     display_name text NOT NULL,
-    id uuid DEFAULT gen_random_uuid() NOT NULL
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    depends_on text[] DEFAULT '{}'::text[] NOT NULL,
+    readiness_probe_command text DEFAULT ''::text NOT NULL,
+    readiness_probe_tcp text DEFAULT ''::text NOT NULL,
+    readiness_probe_http text DEFAULT ''::text NOT NULL,
+    readiness_probe_interval_seconds integer DEFAULT 0 NOT NULL,
+    readiness_probe_timeout_seconds integer DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_scripts.depends_on IS 'Display names of the scripts of the same agent that must be ready before this script is started.';
+
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_command IS 'Command that marks the script as ready once it exits successfully. At most one of the readiness probe command, tcp and http is set.';
+
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_tcp IS 'Address that marks the script as ready once it accepts connections.';
+
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_http IS 'URL that marks the script as ready once it responds with a status below 400.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -2055,11 +2200,17 @@ CREATE TABLE workspaces (
     deleting_at timestamp with time zone,
     automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL,
     favorite boolean DEFAULT false NOT NULL,
-    next_start_at timestamp with time zone
+    next_start_at timestamp with time zone,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    group_acl jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN workspaces.favorite IS 'Favorite is true if the workspace owner has favorited the workspace.';
 
+COMMENT ON COLUMN workspaces.user_acl IS 'Users the workspace is shared with, mapped to the actions they may perform.';
+
+COMMENT ON COLUMN workspaces.group_acl IS 'Groups the workspace is shared with, mapped to the actions their members may perform.';
+
 CREATE VIEW workspace_latest_builds AS
  SELECT latest_build.id,
     latest_build.workspace_id,
@@ -2217,6 +2368,50 @@ CREATE SEQUENCE workspace_resource_metadata_id_seq
 
 ALTER SEQUENCE workspace_resource_metadata_id_seq OWNED BY workspace_resource_metadata.id;
 
+CREATE TABLE workspace_session_recording_chunks (
+    recording_id uuid NOT NULL,
+    offset_bytes bigint NOT NULL,
+    data bytea NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'The contents of session recordings, stored as uploaded so that recordings are never rewritten or loaded whole.';
+
+CREATE TABLE workspace_session_recordings (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    connection_id uuid NOT NULL,
+    connection_type text NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone NOT NULL,
+    size bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive terminal sessions in the asciicast v2 format.';
+
+COMMENT ON COLUMN workspace_session_recordings.connection_id IS 'The ID of the recorded connection, it matches the request ID of the connect and disconnect audit logs of the workspace agent.';
+
+COMMENT ON COLUMN workspace_session_recordings.size IS 'The total size of the uploaded chunks in bytes.';
+
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    created_by uuid NOT NULL,
+    build_number integer NOT NULL,
+    template_version_id uuid NOT NULL,
+    provisioner_state bytea NOT NULL,
+    rich_parameters jsonb DEFAULT '[]'::jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Named copies of the provisioner state and parameters of a workspace build, used to roll a workspace back.';
+
+COMMENT ON COLUMN workspace_snapshots.build_number IS 'The build the snapshot was taken from.';
+
+COMMENT ON COLUMN workspace_snapshots.rich_parameters IS 'The build parameters of the snapshotted build as an array of name and value objects.';
+
 CREATE VIEW workspaces_expanded AS
  SELECT workspaces.id,
     workspaces.created_at,
@@ -2234,6 +2429,8 @@ CREATE VIEW workspaces_expanded AS
     workspaces.automatic_updates,
     workspaces.favorite,
     workspaces.next_start_at,
+    workspaces.user_acl,
+    workspaces.group_acl,
     visible_users.avatar_url AS owner_avatar_url,
     visible_users.username AS owner_username,
     organizations.name AS organization_name,
@@ -2271,6 +2468,15 @@ ALTER TABLE ONLY workspace_agent_stats
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY audit_log_chain
+    ADD CONSTRAINT audit_log_chain_audit_log_id_key UNIQUE (audit_log_id);
+
+ALTER TABLE ONLY audit_log_chain
+    ADD CONSTRAINT audit_log_chain_pkey PRIMARY KEY (organization_id, sequence);
+
+ALTER TABLE ONLY audit_log_checkpoints
+    ADD CONSTRAINT audit_log_checkpoints_pkey PRIMARY KEY (organization_id, sequence);
+
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
@@ -2328,6 +2534,9 @@ ALTER TABLE ONLY licenses
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY notification_digest_entries
+    ADD CONSTRAINT notification_digest_entries_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 
@@ -2397,6 +2606,15 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_pkey PRIMARY KEY (scim_group_id, user_id);
+
+ALTER TABLE ONLY scim_groups
+    ADD CONSTRAINT scim_groups_display_name_key UNIQUE (display_name);
+
+ALTER TABLE ONLY scim_groups
+    ADD CONSTRAINT scim_groups_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -2481,12 +2699,18 @@ ALTER TABLE ONLY workspace_agent_memory_resource_monitors
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_port_forwarding_policies
+    ADD CONSTRAINT workspace_agent_port_forwarding_policies_pkey PRIMARY KEY (agent_id);
+
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
 
+ALTER TABLE ONLY workspace_agent_resource_monitors
+    ADD CONSTRAINT workspace_agent_resource_monitors_pkey PRIMARY KEY (agent_id, type);
+
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
 
@@ -2547,6 +2771,18 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (recording_id, offset_bytes);
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_name_key UNIQUE (workspace_id, name);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
@@ -2574,6 +2810,8 @@ CREATE INDEX idx_inbox_notifications_user_id_read_at ON inbox_notifications USIN
 
 CREATE INDEX idx_inbox_notifications_user_id_template_id_targets ON inbox_notifications USING btree (user_id, template_id, targets);
 
+CREATE INDEX idx_notification_digest_entries_user_id_created_at ON notification_digest_entries USING btree (user_id, created_at);
+
 CREATE INDEX idx_notification_messages_status ON notification_messages USING btree (status);
 
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
@@ -2620,6 +2858,8 @@ CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (st
 
 CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
 
+CREATE INDEX scim_group_members_user_id_idx ON scim_group_members USING btree (user_id);
+
 CREATE INDEX template_usage_stats_start_time_idx ON template_usage_stats USING btree (start_time DESC);
 
 COMMENT ON INDEX template_usage_stats_start_time_idx IS 'Index for querying MAX(start_time).';
@@ -2668,6 +2908,10 @@ CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
+CREATE INDEX workspace_session_recordings_connection_id_idx ON workspace_session_recordings USING btree (connection_id);
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
+
 CREATE INDEX workspace_template_id_idx ON workspaces USING btree (template_id) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
@@ -2802,6 +3046,12 @@ ALTER TABLE ONLY jfrog_xray_scans
 ALTER TABLE ONLY jfrog_xray_scans
     ADD CONSTRAINT jfrog_xray_scans_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY notification_digest_entries
+    ADD CONSTRAINT notification_digest_entries_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_digest_entries
+    ADD CONSTRAINT notification_digest_entries_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY notification_messages
     ADD CONSTRAINT notification_messages_notification_template_id_fkey FOREIGN KEY (notification_template_id) REFERENCES notification_templates(id) ON DELETE CASCADE;
 
@@ -2856,6 +3106,12 @@ ALTER TABLE ONLY provisioner_jobs
 ALTER TABLE ONLY provisioner_keys
     ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_scim_group_id_fkey FOREIGN KEY (scim_group_id) REFERENCES scim_groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY scim_group_members
+    ADD CONSTRAINT scim_group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -2940,9 +3196,15 @@ ALTER TABLE ONLY workspace_agent_memory_resource_monitors
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_port_forwarding_policies
+    ADD CONSTRAINT workspace_agent_port_forwarding_policies_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_port_share
     ADD CONSTRAINT workspace_agent_port_share_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_resource_monitors
+    ADD CONSTRAINT workspace_agent_resource_monitors_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_script_timings
     ADD CONSTRAINT workspace_agent_script_timings_script_id_fkey FOREIGN KEY (script_id) REFERENCES workspace_agent_scripts(id) ON DELETE CASCADE;
 
@@ -3009,6 +3271,24 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_session_recording_chunks
+    ADD CONSTRAINT workspace_session_recording_chunks_recording_id_fkey FOREIGN KEY (recording_id) REFERENCES workspace_session_recordings(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_session_recordings
+    ADD CONSTRAINT workspace_session_recordings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
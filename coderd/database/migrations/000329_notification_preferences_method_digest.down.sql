@@ -0,0 +1,12 @@
+DELETE FROM notification_templates WHERE id = 'b7ce8d1a-1d44-4a8d-a43c-e1ed2b13a44a';
+
+DROP TABLE IF EXISTS notification_digest_entries;
+
+ALTER TABLE notification_templates
+	DROP COLUMN IF EXISTS digestible;
+
+ALTER TABLE notification_preferences
+	DROP COLUMN IF EXISTS digest,
+	DROP COLUMN IF EXISTS method;
+
+-- As we can not remove a value from an enum, we leave 'webpush' in place.
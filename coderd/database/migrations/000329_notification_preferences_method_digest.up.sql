@@ -0,0 +1,57 @@
+-- The enum value is not used in this migration, so it is safe to add it inside
+-- the migration's transaction.
+ALTER TYPE notification_method ADD VALUE IF NOT EXISTS 'webpush';
+
+ALTER TABLE notification_preferences
+	ADD COLUMN method notification_method,
+	ADD COLUMN digest boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN notification_preferences.method IS 'NULL defers to the template-level method, and then to the deployment-level method';
+COMMENT ON COLUMN notification_preferences.digest IS 'Batch messages into a periodic digest instead of delivering them immediately; only honored for digestible templates';
+
+ALTER TABLE notification_templates
+	ADD COLUMN digestible boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN notification_templates.digestible IS 'Whether users may choose to receive messages of this template in a periodic digest';
+
+-- Low-priority workspace notifications which users may prefer to receive in bulk.
+UPDATE notification_templates SET digestible = true WHERE id IN (
+	'381df2a9-c0c0-4749-420f-80a9280c66f9', -- Workspace Autobuild Failed
+	'c34a0c09-0704-4cac-bd1c-0c0146811c2b', -- Workspace Updated Automatically
+	'0ea69165-ec14-4314-91f1-69566ac3c5a0', -- Workspace Marked as Dormant
+	'51ce2fdf-c9ca-4be1-8d70-628674f9bc42'  -- Workspace Marked for Deletion
+);
+
+CREATE TABLE notification_digest_entries (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	notification_template_id uuid NOT NULL REFERENCES notification_templates (id) ON DELETE CASCADE,
+	title text NOT NULL,
+	payload jsonb NOT NULL,
+	created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE notification_digest_entries IS 'Notifications held back from immediate delivery until the next digest is sent to the user.';
+COMMENT ON COLUMN notification_digest_entries.title IS 'Plaintext title rendered at enqueue time';
+
+CREATE INDEX idx_notification_digest_entries_user_id_created_at ON notification_digest_entries (user_id, created_at);
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'b7ce8d1a-1d44-4a8d-a43c-e1ed2b13a44a',
+	'Notification Digest',
+	E'Your notification digest',
+	E'Here is what happened since {{.Data.since}}:\n'||
+		E'{{range $entry := .Data.entries}}\n'||
+		E'- **{{$entry.title}}**{{if $entry.url}} ([{{$entry.label}}]({{$entry.url}})){{end}}'||
+		E'{{end}}',
+	'Notification Events',
+	'[
+		{
+			"label": "Manage notification preferences",
+			"url": "{{base_url}}/settings/notifications"
+		}
+	]'::jsonb
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_log_checkpoints;
+DROP TABLE IF EXISTS audit_log_chain;
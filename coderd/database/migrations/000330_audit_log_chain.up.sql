@@ -0,0 +1,30 @@
+-- Audit logs are linked into one hash chain per organization as they are
+-- inserted, so that inserts in different organizations do not contend on a
+-- single chain head. Deployment-wide audit logs use the nil UUID. There is
+-- intentionally no foreign key to audit_logs, so that deleting an audit log
+-- leaves a dangling entry behind which can be detected.
+CREATE TABLE audit_log_chain (
+    organization_id uuid NOT NULL,
+    sequence bigint NOT NULL,
+    audit_log_id uuid NOT NULL UNIQUE,
+    prev_hash bytea NOT NULL,
+    hash bytea NOT NULL,
+    PRIMARY KEY (organization_id, sequence)
+);
+
+COMMENT ON TABLE audit_log_chain IS 'Links each audit log to the one inserted before it in the same organization, so that modified, deleted or reordered audit logs can be detected.';
+COMMENT ON COLUMN audit_log_chain.organization_id IS 'The organization whose chain this entry belongs to. Deployment-wide audit logs use the nil UUID.';
+COMMENT ON COLUMN audit_log_chain.prev_hash IS 'The hash of the previous entry in the chain. The first entry uses 32 zero bytes.';
+COMMENT ON COLUMN audit_log_chain.hash IS 'SHA-256 of the previous hash, the sequence and the canonical encoding of the audit log.';
+
+CREATE TABLE audit_log_checkpoints (
+    organization_id uuid NOT NULL,
+    sequence bigint NOT NULL,
+    hash bytea NOT NULL,
+    signature bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (organization_id, sequence)
+);
+
+COMMENT ON TABLE audit_log_checkpoints IS 'Periodic signatures of the head of each audit log chain, made with the deployment''s audit log signing key.';
+COMMENT ON COLUMN audit_log_checkpoints.signature IS 'An ed25519 signature over the organization, sequence and hash of the chain entry.';
@@ -0,0 +1,18 @@
+CREATE TABLE scim_groups (
+    id uuid NOT NULL PRIMARY KEY,
+    external_id text DEFAULT ''::text NOT NULL,
+    display_name text NOT NULL UNIQUE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE scim_groups IS 'Groups pushed by an identity provider through SCIM. Members are mapped onto Coder groups using the group sync settings of each organization.';
+COMMENT ON COLUMN scim_groups.external_id IS 'The identifier of the group in the identity provider, if it provided one.';
+
+CREATE TABLE scim_group_members (
+    scim_group_id uuid NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (scim_group_id, user_id)
+);
+
+CREATE INDEX scim_group_members_user_id_idx ON scim_group_members USING btree (user_id);
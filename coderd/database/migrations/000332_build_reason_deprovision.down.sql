@@ -0,0 +1 @@
+-- Enum values can't be dropped, so there is nothing to do.
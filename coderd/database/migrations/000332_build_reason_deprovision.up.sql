@@ -0,0 +1,2 @@
+-- Builds started by SCIM deprovisioning when a user is deactivated.
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'deprovision';
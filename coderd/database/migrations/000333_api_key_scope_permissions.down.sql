@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+	DROP COLUMN IF EXISTS scope_permissions,
+	DROP COLUMN IF EXISTS scope_allow_list;
@@ -0,0 +1,6 @@
+ALTER TABLE api_keys
+	ADD COLUMN scope_permissions text[] NOT NULL DEFAULT '{}',
+	ADD COLUMN scope_allow_list text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scope_permissions IS 'Resource and action pairs, such as workspace:read, that further limit the scope of the key. Empty means the key is only limited by its scope.';
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'Typed resource IDs, such as template:<id>, the key is limited to. Empty allows every resource.';
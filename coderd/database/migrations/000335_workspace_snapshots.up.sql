@@ -0,0 +1,16 @@
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL PRIMARY KEY,
+    workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    created_by uuid NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    build_number integer NOT NULL,
+    template_version_id uuid NOT NULL REFERENCES template_versions(id) ON DELETE CASCADE,
+    provisioner_state bytea NOT NULL,
+    rich_parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    UNIQUE (workspace_id, name)
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Named copies of the provisioner state and parameters of a workspace build, used to roll a workspace back.';
+COMMENT ON COLUMN workspace_snapshots.build_number IS 'The build the snapshot was taken from.';
+COMMENT ON COLUMN workspace_snapshots.rich_parameters IS 'The build parameters of the snapshotted build as an array of name and value objects.';
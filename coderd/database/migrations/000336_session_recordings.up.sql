@@ -0,0 +1,56 @@
+ALTER TABLE templates ADD COLUMN session_recording boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.session_recording IS 'Record interactive terminal sessions of workspaces created from this template, for auditing.';
+
+-- Update the template_with_names view by recreating it.
+DROP VIEW template_with_names;
+CREATE VIEW
+	template_with_names
+AS
+SELECT
+	templates.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username,
+	coalesce(organizations.name, '') AS organization_name,
+	coalesce(organizations.display_name, '') AS organization_display_name,
+	coalesce(organizations.icon, '') AS organization_icon
+FROM
+	templates
+		LEFT JOIN
+	visible_users
+	ON
+		templates.created_by = visible_users.id
+		LEFT JOIN
+	organizations
+	ON templates.organization_id = organizations.id
+;
+
+COMMENT ON VIEW template_with_names IS 'Joins in the display name information such as username, avatar, and organization name.';
+
+CREATE TABLE workspace_session_recordings (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	connection_id uuid NOT NULL,
+	connection_type text NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	size bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Recordings of interactive terminal sessions in the asciicast v2 format.';
+COMMENT ON COLUMN workspace_session_recordings.connection_id IS 'The ID of the recorded connection, it matches the request ID of the connect and disconnect audit logs of the workspace agent.';
+COMMENT ON COLUMN workspace_session_recordings.size IS 'The total size of the uploaded chunks in bytes.';
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings (workspace_id);
+CREATE INDEX workspace_session_recordings_connection_id_idx ON workspace_session_recordings (connection_id);
+
+CREATE TABLE workspace_session_recording_chunks (
+	recording_id uuid NOT NULL REFERENCES workspace_session_recordings(id) ON DELETE CASCADE,
+	offset_bytes bigint NOT NULL,
+	data bytea NOT NULL,
+	PRIMARY KEY (recording_id, offset_bytes)
+);
+
+COMMENT ON TABLE workspace_session_recording_chunks IS 'The contents of session recordings, stored as uploaded so that recordings are never rewritten or loaded whole.';
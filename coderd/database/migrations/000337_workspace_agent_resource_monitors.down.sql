@@ -0,0 +1,5 @@
+DELETE FROM notification_templates WHERE id = '6d4f0c7c-4b2e-4a55-9a0f-2f4d1b7e3c91';
+
+DROP TABLE workspace_agent_resource_monitors;
+
+DROP TYPE workspace_agent_resource_monitor_type;
@@ -0,0 +1,45 @@
+CREATE TYPE workspace_agent_resource_monitor_type AS ENUM (
+	'cpu',
+	'network',
+	'file_descriptors',
+	'processes'
+);
+
+CREATE TABLE workspace_agent_resource_monitors (
+	agent_id 	uuid NOT NULL REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	type 		workspace_agent_resource_monitor_type NOT NULL,
+	enabled 	boolean 					NOT NULL,
+	threshold 	integer 					NOT NULL,
+	created_at 	timestamp with time zone 	NOT NULL,
+	updated_at 	timestamp with time zone 	NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	state 		workspace_agent_monitor_state NOT NULL DEFAULT 'OK',
+	debounced_until timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00'::timestamptz,
+	PRIMARY KEY (agent_id, type)
+);
+
+COMMENT ON TABLE workspace_agent_resource_monitors IS 'Resource monitors of workspace agents that have a single threshold per agent. Memory and volumes have dedicated tables.';
+COMMENT ON COLUMN workspace_agent_resource_monitors.threshold IS 'The threshold in percent of the available resource, except for network monitors where it is the throughput in Mbit/s.';
+
+INSERT INTO notification_templates
+	(id, name, title_template, body_template, "group", actions)
+VALUES (
+	'6d4f0c7c-4b2e-4a55-9a0f-2f4d1b7e3c91',
+	'Workspace High Resource Usage',
+	E'Your workspace "{{.Labels.workspace}}" is under high load',
+	E'Hi {{.UserName}},\n\n'||
+		E'{{ if eq (len .Data.monitors) 1 }}{{ $monitor := index .Data.monitors 0 }}'||
+			E'**{{$monitor.resource}}** is over {{$monitor.threshold}} in workspace **{{.Labels.workspace}}**.'||
+		E'{{ else }}'||
+			E'The following resources are under high load in workspace **{{.Labels.workspace}}**\n\n'||
+			E'{{ range $monitor := .Data.monitors }}'||
+				E'- **{{$monitor.resource}}** is over {{$monitor.threshold}}\n'||
+			E'{{ end }}'||
+		E'{{ end }}',
+	'Workspace Events',
+	'[
+		{
+			"label": "View workspace",
+			"url": "{{base_url}}/@{{.UserUsername}}/{{.Labels.workspace}}"
+		}
+	]'::jsonb
+);
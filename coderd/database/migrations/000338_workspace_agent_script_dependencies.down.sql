@@ -0,0 +1,9 @@
+ALTER TABLE workspace_agent_script_timings DROP COLUMN queued_at;
+
+ALTER TABLE workspace_agent_scripts
+	DROP COLUMN depends_on,
+	DROP COLUMN readiness_probe_command,
+	DROP COLUMN readiness_probe_tcp,
+	DROP COLUMN readiness_probe_http,
+	DROP COLUMN readiness_probe_interval_seconds,
+	DROP COLUMN readiness_probe_timeout_seconds;
@@ -0,0 +1,18 @@
+ALTER TABLE workspace_agent_scripts
+	ADD COLUMN depends_on text[] NOT NULL DEFAULT '{}',
+	ADD COLUMN readiness_probe_command text NOT NULL DEFAULT '',
+	ADD COLUMN readiness_probe_tcp text NOT NULL DEFAULT '',
+	ADD COLUMN readiness_probe_http text NOT NULL DEFAULT '',
+	ADD COLUMN readiness_probe_interval_seconds integer NOT NULL DEFAULT 0,
+	ADD COLUMN readiness_probe_timeout_seconds integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_agent_scripts.depends_on IS 'Display names of the scripts of the same agent that must be ready before this script is started.';
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_command IS 'Command that marks the script as ready once it exits successfully. At most one of the readiness probe command, tcp and http is set.';
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_tcp IS 'Address that marks the script as ready once it accepts connections.';
+COMMENT ON COLUMN workspace_agent_scripts.readiness_probe_http IS 'URL that marks the script as ready once it responds with a status below 400.';
+
+ALTER TABLE workspace_agent_script_timings ADD COLUMN queued_at timestamp with time zone;
+UPDATE workspace_agent_script_timings SET queued_at = started_at;
+ALTER TABLE workspace_agent_script_timings ALTER COLUMN queued_at SET NOT NULL;
+
+COMMENT ON COLUMN workspace_agent_script_timings.queued_at IS 'Time the script was due to run. It is before started_at when the script waited for the scripts it depends on.';
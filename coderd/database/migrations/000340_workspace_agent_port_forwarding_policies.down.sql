@@ -0,0 +1 @@
+DROP TABLE workspace_agent_port_forwarding_policies;
@@ -0,0 +1,12 @@
+CREATE TABLE workspace_agent_port_forwarding_policies (
+	agent_id uuid PRIMARY KEY REFERENCES workspace_agents(id) ON DELETE CASCADE,
+	allowed_ports text[] NOT NULL DEFAULT '{}',
+	denied_ports text[] NOT NULL DEFAULT '{}',
+	allowed_unix_sockets text[] NOT NULL DEFAULT '{}',
+	denied_unix_sockets text[] NOT NULL DEFAULT '{}',
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_port_forwarding_policies IS 'Restricts the TCP ports and Unix sockets in the workspace that clients can connect to through the agent.';
+COMMENT ON COLUMN workspace_agent_port_forwarding_policies.allowed_ports IS 'Ports or port ranges like 3000-3999. An empty list allows all ports that are not denied.';
+COMMENT ON COLUMN workspace_agent_port_forwarding_policies.allowed_unix_sockets IS 'Path patterns of Unix sockets. An empty list allows all sockets that are not denied.';
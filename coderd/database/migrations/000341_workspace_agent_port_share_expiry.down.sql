@@ -0,0 +1,2 @@
+-- Enum values can't be dropped, so only the column is removed.
+ALTER TABLE workspace_agent_port_share DROP COLUMN expires_at;
@@ -0,0 +1,6 @@
+ALTER TABLE workspace_agent_port_share ADD COLUMN expires_at timestamp with time zone;
+
+COMMENT ON COLUMN workspace_agent_port_share.expires_at IS 'The time after which the port is no longer shared. Shares without an expiry never expire.';
+
+-- Allow port shares to be audited.
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_agent_port_share';
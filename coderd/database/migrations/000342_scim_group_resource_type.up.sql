@@ -0,0 +1,2 @@
+-- Allow SCIM groups to be audited.
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'scim_group';
@@ -0,0 +1,2 @@
+-- Allow workspace snapshots to be audited.
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_snapshot';
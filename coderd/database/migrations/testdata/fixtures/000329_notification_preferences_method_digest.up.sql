@@ -0,0 +1,9 @@
+INSERT INTO notification_digest_entries (id, user_id, notification_template_id, title, payload, created_at)
+VALUES (
+	gen_random_uuid(),
+	(SELECT id FROM users LIMIT 1),
+	'0ea69165-ec14-4314-91f1-69566ac3c5a0',
+	'Workspace "dev" marked as dormant',
+	'{"version":"1.2","labels":{"name":"dev"}}'::jsonb,
+	NOW()
+);
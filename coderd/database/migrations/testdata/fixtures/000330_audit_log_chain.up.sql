@@ -0,0 +1,17 @@
+INSERT INTO audit_log_chain (organization_id, sequence, audit_log_id, prev_hash, hash)
+VALUES (
+	'00000000-0000-0000-0000-000000000000',
+	1,
+	gen_random_uuid(),
+	'\x0000000000000000000000000000000000000000000000000000000000000000'::bytea,
+	'\x5f2b6ec93b5a1a0d5e6d6f4bd2a3c4e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5'::bytea
+);
+
+INSERT INTO audit_log_checkpoints (organization_id, sequence, hash, signature, created_at)
+VALUES (
+	'00000000-0000-0000-0000-000000000000',
+	1,
+	'\x5f2b6ec93b5a1a0d5e6d6f4bd2a3c4e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5'::bytea,
+	'\x00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000'::bytea,
+	NOW()
+);
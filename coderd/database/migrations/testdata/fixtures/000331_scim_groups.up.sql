@@ -0,0 +1,14 @@
+INSERT INTO scim_groups (id, external_id, display_name, created_at, updated_at)
+VALUES (
+	'8c1f6a36-5b0e-4a0f-9c4e-2f3b1d7e9a10',
+	'00g1a2b3c4d5e6f7g8h9',
+	'Engineering',
+	NOW(),
+	NOW()
+);
+
+INSERT INTO scim_group_members (scim_group_id, user_id)
+SELECT '8c1f6a36-5b0e-4a0f-9c4e-2f3b1d7e9a10', id
+FROM users
+ORDER BY created_at
+LIMIT 1;
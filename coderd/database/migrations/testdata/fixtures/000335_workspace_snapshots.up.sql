@@ -0,0 +1,28 @@
+INSERT INTO workspace_snapshots (
+	id,
+	workspace_id,
+	name,
+	created_at,
+	created_by,
+	build_number,
+	template_version_id,
+	provisioner_state,
+	rich_parameters
+)
+SELECT
+	gen_random_uuid(),
+	workspace_builds.workspace_id,
+	'fixture',
+	NOW(),
+	workspace_builds.initiator_id,
+	workspace_builds.build_number,
+	workspace_builds.template_version_id,
+	'\x'::bytea,
+	'[]'::jsonb
+FROM
+	workspace_builds
+WHERE
+	workspace_builds.workspace_id = '3a9a1feb-e89d-457c-9d53-ac751b198ebe'
+ORDER BY
+	workspace_builds.build_number DESC
+LIMIT 1;
@@ -0,0 +1,35 @@
+WITH recording AS (
+	INSERT INTO workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		connection_id,
+		connection_type,
+		started_at,
+		ended_at,
+		size,
+		created_at
+	) VALUES (
+		gen_random_uuid(),
+		'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+		'7a1ce5f8-8d00-431c-ad1b-97a846512804',
+		gen_random_uuid(),
+		'SSH',
+		NOW() - INTERVAL '1 minute',
+		NOW(),
+		octet_length(convert_to(E'{"version":2,"width":80,"height":24}\n[0.5,"o","$ "]\n', 'UTF8')),
+		NOW()
+	)
+	RETURNING id
+)
+INSERT INTO workspace_session_recording_chunks (
+	recording_id,
+	offset_bytes,
+	data
+)
+SELECT
+	id,
+	0,
+	convert_to(E'{"version":2,"width":80,"height":24}\n[0.5,"o","$ "]\n', 'UTF8')
+FROM
+	recording;
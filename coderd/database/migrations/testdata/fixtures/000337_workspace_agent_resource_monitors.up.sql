@@ -0,0 +1,15 @@
+INSERT INTO
+	workspace_agent_resource_monitors (
+		agent_id,
+		type,
+		enabled,
+		threshold,
+		created_at
+	)
+	VALUES (
+		'45e89705-e09d-4850-bcec-f9a937f5d78d', -- uuid
+		'cpu',
+		true,
+		90,
+		'2024-01-01 00:00:00'
+	);
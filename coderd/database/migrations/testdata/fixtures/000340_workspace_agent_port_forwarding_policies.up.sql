@@ -0,0 +1,17 @@
+INSERT INTO
+	workspace_agent_port_forwarding_policies (
+		agent_id,
+		allowed_ports,
+		denied_ports,
+		allowed_unix_sockets,
+		denied_unix_sockets,
+		created_at
+	)
+	VALUES (
+		'45e89705-e09d-4850-bcec-f9a937f5d78d', -- uuid
+		'{3000-3999,8080}',
+		'{22}',
+		'{}',
+		'{/var/run/docker.sock}',
+		'2024-01-01 00:00:00'
+	);
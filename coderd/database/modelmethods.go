@@ -2,6 +2,7 @@ package database
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
@@ -108,6 +109,13 @@ func (w GetAuditLogsOffsetRow) RBACObject() rbac.Object {
 	return w.AuditLog.RBACObject()
 }
 
+func (c GetAuditLogChainsRow) RBACObject() rbac.Object {
+	if c.OrganizationID == uuid.Nil {
+		return rbac.ResourceAuditLog
+	}
+	return rbac.ResourceAuditLog.InOrg(c.OrganizationID)
+}
+
 func (w AuditLog) RBACObject() rbac.Object {
 	obj := rbac.ResourceAuditLog.WithID(w.ID)
 	if w.OrganizationID != uuid.Nil {
@@ -117,6 +125,17 @@ func (w AuditLog) RBACObject() rbac.Object {
 	return obj
 }
 
+// RBACObject returns the audit log object of the recording's organization.
+// Session recordings are audit records, so only those that can read audit
+// logs can read them.
+func (r GetWorkspaceSessionRecordingsRow) RBACObject() rbac.Object {
+	return rbac.ResourceAuditLog.WithID(r.ID).InOrg(r.OrganizationID)
+}
+
+func (r GetWorkspaceSessionRecordingByIDRow) RBACObject() rbac.Object {
+	return rbac.ResourceAuditLog.WithID(r.ID).InOrg(r.OrganizationID)
+}
+
 func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	switch s {
 	case APIKeyScopeAll:
@@ -222,6 +241,8 @@ func (w Workspace) WorkspaceTable() WorkspaceTable {
 		AutomaticUpdates:  w.AutomaticUpdates,
 		Favorite:          w.Favorite,
 		NextStartAt:       w.NextStartAt,
+		UserACL:           w.UserACL,
+		GroupACL:          w.GroupACL,
 	}
 }
 
@@ -236,7 +257,9 @@ func (w WorkspaceTable) RBACObject() rbac.Object {
 
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w WorkspaceTable) DormantRBAC() rbac.Object {
@@ -461,12 +484,25 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			TemplateIcon:            r.TemplateIcon,
 			TemplateDescription:     r.TemplateDescription,
 			NextStartAt:             r.NextStartAt,
+			UserACL:                 workspaceACLFromJSON(r.UserACL),
+			GroupACL:                workspaceACLFromJSON(r.GroupACL),
 		}
 	}
 
 	return workspaces
 }
 
+// workspaceACLFromJSON parses the ACL columns of queries that select from a
+// CTE, which sqlc can only type as JSON.
+func workspaceACLFromJSON(raw json.RawMessage) WorkspaceACL {
+	acl := WorkspaceACL{}
+	if len(raw) > 0 {
+		// The column is always a valid JSON object.
+		_ = json.Unmarshal(raw, &acl)
+	}
+	return acl
+}
+
 func (g Group) IsEveryone() bool {
 	return g.ID == g.OrganizationID
 }
@@ -569,6 +605,20 @@ func (m WorkspaceAgentVolumeResourceMonitor) Debounce(
 	return m.DebouncedUntil, false
 }
 
+func (m WorkspaceAgentResourceMonitor) Debounce(
+	by time.Duration,
+	now time.Time,
+	oldState, newState WorkspaceAgentMonitorState,
+) (debouncedUntil time.Time, shouldNotify bool) {
+	if now.After(m.DebouncedUntil) &&
+		oldState == WorkspaceAgentMonitorStateOK &&
+		newState == WorkspaceAgentMonitorStateNOK {
+		return now.Add(by), true
+	}
+
+	return m.DebouncedUntil, false
+}
+
 func (c Chat) RBACObject() rbac.Object {
 	return rbac.ResourceChat.WithID(c.ID).
 		WithOwner(c.OwnerID.String())
@@ -50,6 +50,7 @@ type customQuerier interface {
 	workspaceQuerier
 	userQuerier
 	auditLogQuerier
+	sessionRecordingQuerier
 }
 
 type templateQuerier interface {
@@ -118,6 +119,8 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.SessionRecording,
+			&i.ActivityBumpRequiresUserActivity,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
@@ -292,6 +295,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.UserACL,
+			&i.GroupACL,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OrganizationName,
@@ -528,6 +533,65 @@ func (q *sqlQuerier) GetAuthorizedAuditLogsOffset(ctx context.Context, arg GetAu
 	return items, nil
 }
 
+type sessionRecordingQuerier interface {
+	GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg GetWorkspaceSessionRecordingsParams, prepared rbac.PreparedAuthorized) ([]GetWorkspaceSessionRecordingsRow, error)
+}
+
+func (q *sqlQuerier) GetAuthorizedWorkspaceSessionRecordings(ctx context.Context, arg GetWorkspaceSessionRecordingsParams, prepared rbac.PreparedAuthorized) ([]GetWorkspaceSessionRecordingsRow, error) {
+	authorizedFilter, err := prepared.CompileToSQL(ctx, regosql.ConvertConfig{
+		VariableConverter: regosql.SessionRecordingConverter(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("compile authorized filter: %w", err)
+	}
+
+	filtered, err := insertAuthorizedFilter(getWorkspaceSessionRecordings, fmt.Sprintf(" AND %s", authorizedFilter))
+	if err != nil {
+		return nil, xerrors.Errorf("insert authorized filter: %w", err)
+	}
+
+	query := fmt.Sprintf("-- name: GetAuthorizedWorkspaceSessionRecordings :many\n%s", filtered)
+	rows, err := q.db.QueryContext(ctx, query,
+		arg.WorkspaceID,
+		arg.ConnectionID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceSessionRecordingsRow
+	for rows.Next() {
+		var i GetWorkspaceSessionRecordingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.ConnectionID,
+			&i.ConnectionType,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.CreatedAt,
+			&i.Size,
+			&i.OrganizationID,
+			&i.WorkspaceOwnerID,
+			&i.WorkspaceName,
+			&i.AgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func insertAuthorizedFilter(query string, replaceWith string) (string, error) {
 	if !strings.Contains(query, authorizedQueryPlaceholder) {
 		return "", xerrors.Errorf("query does not contain authorized replace string, this is not an authorized query")
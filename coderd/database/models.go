@@ -345,12 +345,13 @@ func AllAutomaticUpdatesValues() []AutomaticUpdates {
 type BuildReason string
 
 const (
-	BuildReasonInitiator  BuildReason = "initiator"
-	BuildReasonAutostart  BuildReason = "autostart"
-	BuildReasonAutostop   BuildReason = "autostop"
-	BuildReasonDormancy   BuildReason = "dormancy"
-	BuildReasonFailedstop BuildReason = "failedstop"
-	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonInitiator   BuildReason = "initiator"
+	BuildReasonAutostart   BuildReason = "autostart"
+	BuildReasonAutostop    BuildReason = "autostop"
+	BuildReasonDormancy    BuildReason = "dormancy"
+	BuildReasonFailedstop  BuildReason = "failedstop"
+	BuildReasonAutodelete  BuildReason = "autodelete"
+	BuildReasonDeprovision BuildReason = "deprovision"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -395,7 +396,8 @@ func (e BuildReason) Valid() bool {
 		BuildReasonAutostop,
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
-		BuildReasonAutodelete:
+		BuildReasonAutodelete,
+		BuildReasonDeprovision:
 		return true
 	}
 	return false
@@ -409,6 +411,7 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
 		BuildReasonAutodelete,
+		BuildReasonDeprovision,
 	}
 }
 
@@ -937,6 +940,9 @@ const (
 	NotificationMethodSmtp    NotificationMethod = "smtp"
 	NotificationMethodWebhook NotificationMethod = "webhook"
 	NotificationMethodInbox   NotificationMethod = "inbox"
+	NotificationMethodSlack   NotificationMethod = "slack"
+	NotificationMethodTeams   NotificationMethod = "teams"
+	NotificationMethodWebpush NotificationMethod = "webpush"
 )
 
 func (e *NotificationMethod) Scan(src interface{}) error {
@@ -978,7 +984,10 @@ func (e NotificationMethod) Valid() bool {
 	switch e {
 	case NotificationMethodSmtp,
 		NotificationMethodWebhook,
-		NotificationMethodInbox:
+		NotificationMethodInbox,
+		NotificationMethodSlack,
+		NotificationMethodTeams,
+		NotificationMethodWebpush:
 		return true
 	}
 	return false
@@ -989,6 +998,9 @@ func AllNotificationMethodValues() []NotificationMethod {
 		NotificationMethodSmtp,
 		NotificationMethodWebhook,
 		NotificationMethodInbox,
+		NotificationMethodSlack,
+		NotificationMethodTeams,
+		NotificationMethodWebpush,
 	}
 }
 
@@ -1744,6 +1756,9 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeWorkspaceAgentPortShare     ResourceType = "workspace_agent_port_share"
+	ResourceTypeScimGroup                   ResourceType = "scim_group"
+	ResourceTypeWorkspaceSnapshot           ResourceType = "workspace_snapshot"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1806,7 +1821,10 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeIdpSyncSettingsGroup,
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
-		ResourceTypeWorkspaceApp:
+		ResourceTypeWorkspaceApp,
+		ResourceTypeWorkspaceAgentPortShare,
+		ResourceTypeScimGroup,
+		ResourceTypeWorkspaceSnapshot:
 		return true
 	}
 	return false
@@ -1838,6 +1856,9 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeIdpSyncSettingsRole,
 		ResourceTypeWorkspaceAgent,
 		ResourceTypeWorkspaceApp,
+		ResourceTypeWorkspaceAgentPortShare,
+		ResourceTypeScimGroup,
+		ResourceTypeWorkspaceSnapshot,
 	}
 }
 
@@ -2156,6 +2177,70 @@ func AllWorkspaceAgentMonitorStateValues() []WorkspaceAgentMonitorState {
 	}
 }
 
+type WorkspaceAgentResourceMonitorType string
+
+const (
+	WorkspaceAgentResourceMonitorTypeCPU             WorkspaceAgentResourceMonitorType = "cpu"
+	WorkspaceAgentResourceMonitorTypeNetwork         WorkspaceAgentResourceMonitorType = "network"
+	WorkspaceAgentResourceMonitorTypeFileDescriptors WorkspaceAgentResourceMonitorType = "file_descriptors"
+	WorkspaceAgentResourceMonitorTypeProcesses       WorkspaceAgentResourceMonitorType = "processes"
+)
+
+func (e *WorkspaceAgentResourceMonitorType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentResourceMonitorType(s)
+	case string:
+		*e = WorkspaceAgentResourceMonitorType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentResourceMonitorType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentResourceMonitorType struct {
+	WorkspaceAgentResourceMonitorType WorkspaceAgentResourceMonitorType `json:"workspace_agent_resource_monitor_type"`
+	Valid                             bool                              `json:"valid"` // Valid is true if WorkspaceAgentResourceMonitorType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentResourceMonitorType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentResourceMonitorType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentResourceMonitorType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentResourceMonitorType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentResourceMonitorType), nil
+}
+
+func (e WorkspaceAgentResourceMonitorType) Valid() bool {
+	switch e {
+	case WorkspaceAgentResourceMonitorTypeCPU,
+		WorkspaceAgentResourceMonitorTypeNetwork,
+		WorkspaceAgentResourceMonitorTypeFileDescriptors,
+		WorkspaceAgentResourceMonitorTypeProcesses:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentResourceMonitorTypeValues() []WorkspaceAgentResourceMonitorType {
+	return []WorkspaceAgentResourceMonitorType{
+		WorkspaceAgentResourceMonitorTypeCPU,
+		WorkspaceAgentResourceMonitorTypeNetwork,
+		WorkspaceAgentResourceMonitorTypeFileDescriptors,
+		WorkspaceAgentResourceMonitorTypeProcesses,
+	}
+}
+
 // What stage the script was ran in.
 type WorkspaceAgentScriptTimingStage string
 
@@ -2608,6 +2693,10 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Resource and action pairs, such as workspace:read, that further limit the scope of the key. Empty means the key is only limited by its scope.
+	ScopePermissions []string `db:"scope_permissions" json:"scope_permissions"`
+	// Typed resource IDs, such as template:<id>, the key is limited to. Empty allows every resource.
+	ScopeAllowList []string `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 type AuditLog struct {
@@ -2628,6 +2717,28 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Links each audit log to the one inserted before it in the same organization, so that modified, deleted or reordered audit logs can be detected.
+type AuditLogChain struct {
+	// The organization whose chain this entry belongs to. Deployment-wide audit logs use the nil UUID.
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Sequence       int64     `db:"sequence" json:"sequence"`
+	AuditLogID     uuid.UUID `db:"audit_log_id" json:"audit_log_id"`
+	// The hash of the previous entry in the chain. The first entry uses 32 zero bytes.
+	PrevHash []byte `db:"prev_hash" json:"prev_hash"`
+	// SHA-256 of the previous hash, the sequence and the canonical encoding of the audit log.
+	Hash []byte `db:"hash" json:"hash"`
+}
+
+// Periodic signatures of the head of each audit log chain, made with the deployment's audit log signing key.
+type AuditLogCheckpoint struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Sequence       int64     `db:"sequence" json:"sequence"`
+	Hash           []byte    `db:"hash" json:"hash"`
+	// An ed25519 signature over the organization, sequence and hash of the chain entry.
+	Signature []byte    `db:"signature" json:"signature"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 type Chat struct {
 	ID        uuid.UUID `db:"id" json:"id"`
 	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
@@ -2788,6 +2899,17 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Notifications held back from immediate delivery until the next digest is sent to the user.
+type NotificationDigestEntry struct {
+	ID                     uuid.UUID `db:"id" json:"id"`
+	UserID                 uuid.UUID `db:"user_id" json:"user_id"`
+	NotificationTemplateID uuid.UUID `db:"notification_template_id" json:"notification_template_id"`
+	// Plaintext title rendered at enqueue time
+	Title     string          `db:"title" json:"title"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
 type NotificationMessage struct {
 	ID                     uuid.UUID                 `db:"id" json:"id"`
 	NotificationTemplateID uuid.UUID                 `db:"notification_template_id" json:"notification_template_id"`
@@ -2814,6 +2936,10 @@ type NotificationPreference struct {
 	Disabled               bool      `db:"disabled" json:"disabled"`
 	CreatedAt              time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
+	// NULL defers to the template-level method, and then to the deployment-level method
+	Method NullNotificationMethod `db:"method" json:"method"`
+	// Batch messages into a periodic digest instead of delivering them immediately; only honored for digestible templates
+	Digest bool `db:"digest" json:"digest"`
 }
 
 // Log of generated reports for users.
@@ -2834,6 +2960,8 @@ type NotificationTemplate struct {
 	Method           NullNotificationMethod   `db:"method" json:"method"`
 	Kind             NotificationTemplateKind `db:"kind" json:"kind"`
 	EnabledByDefault bool                     `db:"enabled_by_default" json:"enabled_by_default"`
+	// Whether users may choose to receive messages of this template in a periodic digest
+	Digestible bool `db:"digestible" json:"digestible"`
 }
 
 // A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
@@ -3030,6 +3158,21 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Groups pushed by an identity provider through SCIM. Members are mapped onto Coder groups using the group sync settings of each organization.
+type SCIMGroup struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The identifier of the group in the identity provider, if it provided one.
+	ExternalID  string    `db:"external_id" json:"external_id"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type SCIMGroupMember struct {
+	SCIMGroupID uuid.UUID `db:"scim_group_id" json:"scim_group_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -3086,40 +3229,42 @@ type TelemetryItem struct {
 
 // Joins in the display name information such as username, avatar, and organization name.
 type Template struct {
-	ID                            uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                     time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                     time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID                uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted                       bool            `db:"deleted" json:"deleted"`
-	Name                          string          `db:"name" json:"name"`
-	Provisioner                   ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID               uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                   string          `db:"description" json:"description"`
-	DefaultTTL                    int64           `db:"default_ttl" json:"default_ttl"`
-	CreatedBy                     uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                          string          `db:"icon" json:"icon"`
-	UserACL                       TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                      TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                   string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs  bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	AllowUserAutostart            bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
-	FailureTTL                    int64           `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64           `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16           `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
-	Deprecated                    string          `db:"deprecated" json:"deprecated"`
-	ActivityBump                  int64           `db:"activity_bump" json:"activity_bump"`
-	MaxPortSharingLevel           AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow       bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
-	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
-	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
-	OrganizationName              string          `db:"organization_name" json:"organization_name"`
-	OrganizationDisplayName       string          `db:"organization_display_name" json:"organization_display_name"`
-	OrganizationIcon              string          `db:"organization_icon" json:"organization_icon"`
+	ID                               uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                        time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID                   uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Deleted                          bool            `db:"deleted" json:"deleted"`
+	Name                             string          `db:"name" json:"name"`
+	Provisioner                      ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID                  uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description                      string          `db:"description" json:"description"`
+	DefaultTTL                       int64           `db:"default_ttl" json:"default_ttl"`
+	CreatedBy                        uuid.UUID       `db:"created_by" json:"created_by"`
+	Icon                             string          `db:"icon" json:"icon"`
+	UserACL                          TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL                         TemplateACL     `db:"group_acl" json:"group_acl"`
+	DisplayName                      string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs     bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	AllowUserAutostart               bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop                bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
+	FailureTTL                       int64           `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                   int64           `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete         int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	AutostopRequirementDaysOfWeek    int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks         int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek         int16           `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	RequireActiveVersion             bool            `db:"require_active_version" json:"require_active_version"`
+	Deprecated                       string          `db:"deprecated" json:"deprecated"`
+	ActivityBump                     int64           `db:"activity_bump" json:"activity_bump"`
+	MaxPortSharingLevel              AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow          bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	SessionRecording                 bool            `db:"session_recording" json:"session_recording"`
+	ActivityBumpRequiresUserActivity bool            `db:"activity_bump_requires_user_activity" json:"activity_bump_requires_user_activity"`
+	CreatedByAvatarURL               string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+	CreatedByUsername                string          `db:"created_by_username" json:"created_by_username"`
+	OrganizationName                 string          `db:"organization_name" json:"organization_name"`
+	OrganizationDisplayName          string          `db:"organization_display_name" json:"organization_display_name"`
+	OrganizationIcon                 string          `db:"organization_icon" json:"organization_icon"`
 }
 
 type TemplateTable struct {
@@ -3162,6 +3307,10 @@ type TemplateTable struct {
 	MaxPortSharingLevel AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
 	// Determines whether to default to the dynamic parameter creation flow for this template or continue using the legacy classic parameter creation flow.This is a template wide setting, the template admin can revert to the classic flow if there are any issues. An escape hatch is required, as workspace creation is a core workflow and cannot break. This column will be removed when the dynamic parameter creation flow is stable.
 	UseClassicParameterFlow bool `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	// Record interactive terminal sessions of workspaces created from this template, for auditing.
+	SessionRecording bool `db:"session_recording" json:"session_recording"`
+	// Only bump the deadline of workspaces created from this template on user activity reported by the agent, instead of on any open connection.
+	ActivityBumpRequiresUserActivity bool `db:"activity_bump_requires_user_activity" json:"activity_bump_requires_user_activity"`
 }
 
 // Records aggregated usage statistics for templates/users. All usage is rounded up to the nearest minute.
@@ -3411,6 +3560,8 @@ type Workspace struct {
 	AutomaticUpdates        AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool             `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime     `db:"next_start_at" json:"next_start_at"`
+	UserACL                 WorkspaceACL     `db:"user_acl" json:"user_acl"`
+	GroupACL                WorkspaceACL     `db:"group_acl" json:"group_acl"`
 	OwnerAvatarUrl          string           `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string           `db:"owner_username" json:"owner_username"`
 	OrganizationName        string           `db:"organization_name" json:"organization_name"`
@@ -3528,12 +3679,39 @@ type WorkspaceAgentMetadatum struct {
 	DisplayOrder int32 `db:"display_order" json:"display_order"`
 }
 
+// Restricts the TCP ports and Unix sockets in the workspace that clients can connect to through the agent.
+type WorkspaceAgentPortForwardingPolicy struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	// Ports or port ranges like 3000-3999. An empty list allows all ports that are not denied.
+	AllowedPorts []string `db:"allowed_ports" json:"allowed_ports"`
+	DeniedPorts  []string `db:"denied_ports" json:"denied_ports"`
+	// Path patterns of Unix sockets. An empty list allows all sockets that are not denied.
+	AllowedUnixSockets []string  `db:"allowed_unix_sockets" json:"allowed_unix_sockets"`
+	DeniedUnixSockets  []string  `db:"denied_unix_sockets" json:"denied_unix_sockets"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceAgentPortShare struct {
 	WorkspaceID uuid.UUID         `db:"workspace_id" json:"workspace_id"`
 	AgentName   string            `db:"agent_name" json:"agent_name"`
 	Port        int32             `db:"port" json:"port"`
 	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+	// The time after which the port is no longer shared. Shares without an expiry never expire.
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
+// Resource monitors of workspace agents that have a single threshold per agent. Memory and volumes have dedicated tables.
+type WorkspaceAgentResourceMonitor struct {
+	AgentID uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type    WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	Enabled bool                              `db:"enabled" json:"enabled"`
+	// The threshold in percent of the available resource, except for network monitors where it is the throughput in Mbit/s.
+	Threshold      int32                      `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState `db:"state" json:"state"`
+	DebouncedUntil time.Time                  `db:"debounced_until" json:"debounced_until"`
 }
 
 type WorkspaceAgentScript struct {
@@ -3549,6 +3727,16 @@ type WorkspaceAgentScript struct {
 	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
 	DisplayName      string    `db:"display_name" json:"display_name"`
 	ID               uuid.UUID `db:"id" json:"id"`
+	// Display names of the scripts of the same agent that must be ready before this script is started.
+	DependsOn []string `db:"depends_on" json:"depends_on"`
+	// Command that marks the script as ready once it exits successfully. At most one of the readiness probe command, tcp and http is set.
+	ReadinessProbeCommand string `db:"readiness_probe_command" json:"readiness_probe_command"`
+	// Address that marks the script as ready once it accepts connections.
+	ReadinessProbeTCP string `db:"readiness_probe_tcp" json:"readiness_probe_tcp"`
+	// URL that marks the script as ready once it responds with a status below 400.
+	ReadinessProbeHTTP            string `db:"readiness_probe_http" json:"readiness_probe_http"`
+	ReadinessProbeIntervalSeconds int32  `db:"readiness_probe_interval_seconds" json:"readiness_probe_interval_seconds"`
+	ReadinessProbeTimeoutSeconds  int32  `db:"readiness_probe_timeout_seconds" json:"readiness_probe_timeout_seconds"`
 }
 
 type WorkspaceAgentScriptTiming struct {
@@ -3558,6 +3746,8 @@ type WorkspaceAgentScriptTiming struct {
 	ExitCode  int32                            `db:"exit_code" json:"exit_code"`
 	Stage     WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
 	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	// Time the script was due to run. It is before started_at when the script waited for the scripts it depends on.
+	QueuedAt time.Time `db:"queued_at" json:"queued_at"`
 }
 
 type WorkspaceAgentStat struct {
@@ -3805,6 +3995,43 @@ type WorkspaceResourceMetadatum struct {
 	ID                  int64          `db:"id" json:"id"`
 }
 
+// Recordings of interactive terminal sessions in the asciicast v2 format.
+type WorkspaceSessionRecording struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	// The ID of the recorded connection, it matches the request ID of the connect and disconnect audit logs of the workspace agent.
+	ConnectionID   uuid.UUID `db:"connection_id" json:"connection_id"`
+	ConnectionType string    `db:"connection_type" json:"connection_type"`
+	StartedAt      time.Time `db:"started_at" json:"started_at"`
+	EndedAt        time.Time `db:"ended_at" json:"ended_at"`
+	// The total size of the uploaded chunks in bytes.
+	Size      int64     `db:"size" json:"size"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// The contents of session recordings, stored as uploaded so that recordings are never rewritten or loaded whole.
+type WorkspaceSessionRecordingChunk struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	OffsetBytes int64     `db:"offset_bytes" json:"offset_bytes"`
+	Data        []byte    `db:"data" json:"data"`
+}
+
+// Named copies of the provisioner state and parameters of a workspace build, used to roll a workspace back.
+type WorkspaceSnapshot struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+	// The build the snapshot was taken from.
+	BuildNumber       int32     `db:"build_number" json:"build_number"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	ProvisionerState  []byte    `db:"provisioner_state" json:"provisioner_state"`
+	// The build parameters of the snapshotted build as an array of name and value objects.
+	RichParameters json.RawMessage `db:"rich_parameters" json:"rich_parameters"`
+}
+
 type WorkspaceTable struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -3823,4 +4050,8 @@ type WorkspaceTable struct {
 	// Favorite is true if the workspace owner has favorited the workspace.
 	Favorite    bool         `db:"favorite" json:"favorite"`
 	NextStartAt sql.NullTime `db:"next_start_at" json:"next_start_at"`
+	// Users the workspace is shared with, mapped to the actions they may perform.
+	UserACL WorkspaceACL `db:"user_acl" json:"user_acl"`
+	// Groups the workspace is shared with, mapped to the actions their members may perform.
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
 }
@@ -24,6 +24,7 @@ WITH latest AS (
 		workspace_builds.transition AS build_transition,
 		provisioner_jobs.completed_at::timestamp with time zone AS job_completed_at,
 		templates.activity_bump AS activity_bump,
+		templates.activity_bump_requires_user_activity AS activity_bump_requires_user_activity,
 		(
 			CASE
 				-- If the extension would push us over the next_autostart
@@ -35,16 +36,16 @@ WITH latest AS (
 				-- Sadly we can't define ` + "`" + `activity_bump_interval` + "`" + ` above since
 				-- it won't be available for this CASE statement, so we have to
 				-- copy the cast twice.
-				WHEN NOW() + (templates.activity_bump / 1000 / 1000 / 1000 || ' seconds')::interval > $1 :: timestamptz
+				WHEN NOW() + (templates.activity_bump / 1000 / 1000 / 1000 || ' seconds')::interval > $2 :: timestamptz
 				    -- If the autostart is behind now(), then the
 					-- autostart schedule is either the 0 time and not provided,
 					-- or it was the autostart in the past, which is no longer
 					-- relevant. If autostart is > 0 and in the past, then
 					-- that is a mistake by the caller.
-					AND $1 > NOW()
+					AND $2 > NOW()
 					THEN
 					-- Extend to the autostart, then add the activity bump
-					(($1 :: timestamptz) - NOW()) + CASE
+					(($2 :: timestamptz) - NOW()) + CASE
 						WHEN templates.allow_user_autostop
 					    	THEN (workspaces.ttl / 1000 / 1000 / 1000 || ' seconds')::interval
 							ELSE (templates.default_ttl / 1000 / 1000 / 1000 || ' seconds')::interval
@@ -62,7 +63,7 @@ WITH latest AS (
 		ON workspaces.id = workspace_builds.workspace_id
 	JOIN templates
 		ON templates.id = workspaces.template_id
-	WHERE workspace_builds.workspace_id = $2::uuid
+	WHERE workspace_builds.workspace_id = $3::uuid
 	ORDER BY workspace_builds.build_number DESC
 	LIMIT 1
 )
@@ -80,6 +81,7 @@ FROM latest l
 WHERE wb.id = l.build_id
 AND l.job_completed_at IS NOT NULL
 AND l.activity_bump > 0
+AND (NOT l.activity_bump_requires_user_activity OR $1::boolean)
 AND l.build_transition = 'start'
 AND l.ttl_interval > '0 seconds'::interval
 AND l.build_deadline != '0001-01-01 00:00:00+00'
@@ -87,6 +89,7 @@ AND l.build_deadline - (l.ttl_interval * 0.95) < NOW()
 `
 
 type ActivityBumpWorkspaceParams struct {
+	UserActivity  bool      `db:"user_activity" json:"user_activity"`
 	NextAutostart time.Time `db:"next_autostart" json:"next_autostart"`
 	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
 }
@@ -98,12 +101,17 @@ type ActivityBumpWorkspaceParams struct {
 //
 // Max deadline is respected, and the deadline will never be bumped past it.
 // The deadline will never decrease.
+//
+// If the template requires user activity, the deadline is only bumped when
+// user_activity is set, i.e. the agent saw the user type into a terminal or
+// run a command in the foreground.
 // We only bump if the template has an activity bump duration set.
+// We only bump without user activity if the template doesn't require it.
 // We only bump if the raw interval is positive and non-zero.
 // We only bump if workspace shutdown is manual.
 // We only bump when 5% of the deadline has elapsed.
 func (q *sqlQuerier) ActivityBumpWorkspace(ctx context.Context, arg ActivityBumpWorkspaceParams) error {
-	_, err := q.db.ExecContext(ctx, activityBumpWorkspace, arg.NextAutostart, arg.WorkspaceID)
+	_, err := q.db.ExecContext(ctx, activityBumpWorkspace, arg.UserActivity, arg.NextAutostart, arg.WorkspaceID)
 	return err
 }
 
@@ -146,7 +154,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -171,13 +179,15 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -210,12 +220,14 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -240,6 +252,8 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -255,7 +269,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -285,6 +299,8 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -300,7 +316,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -325,6 +341,8 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.ScopePermissions),
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -353,7 +371,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_permissions,
+		scope_allow_list
 	)
 VALUES
 	($1,
@@ -362,22 +382,27 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+	 -- Keys created without a custom scope are not limited further.
+	 COALESCE($13::text[], '{}'::text[]),
+	 COALESCE($14::text[], '{}'::text[])) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID               string      `db:"id" json:"id"`
+	LifetimeSeconds  int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret     []byte      `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress        pqtype.Inet `db:"ip_address" json:"ip_address"`
+	UserID           uuid.UUID   `db:"user_id" json:"user_id"`
+	LastUsed         time.Time   `db:"last_used" json:"last_used"`
+	ExpiresAt        time.Time   `db:"expires_at" json:"expires_at"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time   `db:"updated_at" json:"updated_at"`
+	LoginType        LoginType   `db:"login_type" json:"login_type"`
+	Scope            APIKeyScope `db:"scope" json:"scope"`
+	TokenName        string      `db:"token_name" json:"token_name"`
+	ScopePermissions []string    `db:"scope_permissions" json:"scope_permissions"`
+	ScopeAllowList   []string    `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -394,6 +419,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		pq.Array(arg.ScopePermissions),
+		pq.Array(arg.ScopeAllowList),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -409,6 +436,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.ScopePermissions),
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
@@ -441,6 +470,212 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const getAuditLogChainEntries = `-- name: GetAuditLogChainEntries :many
+SELECT
+	organization_id, sequence, audit_log_id, prev_hash, hash
+FROM
+	audit_log_chain
+WHERE
+	organization_id = $1
+	AND sequence > $2 :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	COALESCE(NULLIF($3 :: int, 0), 100)
+`
+
+type GetAuditLogChainEntriesParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	AfterSequence  int64     `db:"after_sequence" json:"after_sequence"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetAuditLogChainEntries(ctx context.Context, arg GetAuditLogChainEntriesParams) ([]AuditLogChain, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogChainEntries, arg.OrganizationID, arg.AfterSequence, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLogChain
+	for rows.Next() {
+		var i AuditLogChain
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.Sequence,
+			&i.AuditLogID,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditLogChainHead = `-- name: GetAuditLogChainHead :one
+SELECT organization_id, sequence, audit_log_id, prev_hash, hash FROM audit_log_chain WHERE organization_id = $1 ORDER BY sequence DESC LIMIT 1
+`
+
+func (q *sqlQuerier) GetAuditLogChainHead(ctx context.Context, organizationID uuid.UUID) (AuditLogChain, error) {
+	row := q.db.QueryRowContext(ctx, getAuditLogChainHead, organizationID)
+	var i AuditLogChain
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Sequence,
+		&i.AuditLogID,
+		&i.PrevHash,
+		&i.Hash,
+	)
+	return i, err
+}
+
+const getAuditLogChains = `-- name: GetAuditLogChains :many
+SELECT
+	chains.organization_id,
+	COALESCE(organizations.name, '') :: text AS organization_name,
+	COALESCE(organizations.deleted, false) :: boolean AS organization_deleted
+FROM
+	(
+		SELECT organization_id FROM audit_log_chain
+		UNION
+		SELECT organization_id FROM audit_log_checkpoints
+	) AS chains
+LEFT JOIN
+	organizations ON organizations.id = chains.organization_id
+ORDER BY
+	chains.organization_id ASC
+`
+
+type GetAuditLogChainsRow struct {
+	OrganizationID      uuid.UUID `db:"organization_id" json:"organization_id"`
+	OrganizationName    string    `db:"organization_name" json:"organization_name"`
+	OrganizationDeleted bool      `db:"organization_deleted" json:"organization_deleted"`
+}
+
+// Returns every audit log chain, including the chains of deleted
+// organizations, since their audit logs are kept.
+func (q *sqlQuerier) GetAuditLogChains(ctx context.Context) ([]GetAuditLogChainsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogChains)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuditLogChainsRow
+	for rows.Next() {
+		var i GetAuditLogChainsRow
+		if err := rows.Scan(&i.OrganizationID, &i.OrganizationName, &i.OrganizationDeleted); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditLogCheckpoints = `-- name: GetAuditLogCheckpoints :many
+SELECT
+	organization_id, sequence, hash, signature, created_at
+FROM
+	audit_log_checkpoints
+WHERE
+	organization_id = $1
+	AND sequence > $2 :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	COALESCE(NULLIF($3 :: int, 0), 100)
+`
+
+type GetAuditLogCheckpointsParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	AfterSequence  int64     `db:"after_sequence" json:"after_sequence"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetAuditLogCheckpoints(ctx context.Context, arg GetAuditLogCheckpointsParams) ([]AuditLogCheckpoint, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogCheckpoints, arg.OrganizationID, arg.AfterSequence, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLogCheckpoint
+	for rows.Next() {
+		var i AuditLogCheckpoint
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.Sequence,
+			&i.Hash,
+			&i.Signature,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditLogsByIDs = `-- name: GetAuditLogsByIDs :many
+SELECT id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon FROM audit_logs WHERE id = ANY($1 :: uuid[])
+`
+
+func (q *sqlQuerier) GetAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+			&i.AdditionalFields,
+			&i.RequestID,
+			&i.ResourceIcon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
@@ -686,6 +921,23 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 	return items, nil
 }
 
+const getLatestAuditLogCheckpoint = `-- name: GetLatestAuditLogCheckpoint :one
+SELECT organization_id, sequence, hash, signature, created_at FROM audit_log_checkpoints WHERE organization_id = $1 ORDER BY sequence DESC LIMIT 1
+`
+
+func (q *sqlQuerier) GetLatestAuditLogCheckpoint(ctx context.Context, organizationID uuid.UUID) (AuditLogCheckpoint, error) {
+	row := q.db.QueryRowContext(ctx, getLatestAuditLogCheckpoint, organizationID)
+	var i AuditLogCheckpoint
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Sequence,
+		&i.Hash,
+		&i.Signature,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const insertAuditLog = `-- name: InsertAuditLog :one
 INSERT INTO
 	audit_logs (
@@ -766,6 +1018,74 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const insertAuditLogChainEntry = `-- name: InsertAuditLogChainEntry :one
+INSERT INTO
+	audit_log_chain (organization_id, sequence, audit_log_id, prev_hash, hash)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING organization_id, sequence, audit_log_id, prev_hash, hash
+`
+
+type InsertAuditLogChainEntryParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Sequence       int64     `db:"sequence" json:"sequence"`
+	AuditLogID     uuid.UUID `db:"audit_log_id" json:"audit_log_id"`
+	PrevHash       []byte    `db:"prev_hash" json:"prev_hash"`
+	Hash           []byte    `db:"hash" json:"hash"`
+}
+
+func (q *sqlQuerier) InsertAuditLogChainEntry(ctx context.Context, arg InsertAuditLogChainEntryParams) (AuditLogChain, error) {
+	row := q.db.QueryRowContext(ctx, insertAuditLogChainEntry,
+		arg.OrganizationID,
+		arg.Sequence,
+		arg.AuditLogID,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	var i AuditLogChain
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Sequence,
+		&i.AuditLogID,
+		&i.PrevHash,
+		&i.Hash,
+	)
+	return i, err
+}
+
+const insertAuditLogCheckpoint = `-- name: InsertAuditLogCheckpoint :one
+INSERT INTO
+	audit_log_checkpoints (organization_id, sequence, hash, signature, created_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING organization_id, sequence, hash, signature, created_at
+`
+
+type InsertAuditLogCheckpointParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Sequence       int64     `db:"sequence" json:"sequence"`
+	Hash           []byte    `db:"hash" json:"hash"`
+	Signature      []byte    `db:"signature" json:"signature"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertAuditLogCheckpoint(ctx context.Context, arg InsertAuditLogCheckpointParams) (AuditLogCheckpoint, error) {
+	row := q.db.QueryRowContext(ctx, insertAuditLogCheckpoint,
+		arg.OrganizationID,
+		arg.Sequence,
+		arg.Hash,
+		arg.Signature,
+		arg.CreatedAt,
+	)
+	var i AuditLogCheckpoint
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Sequence,
+		&i.Hash,
+		&i.Signature,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const deleteChat = `-- name: DeleteChat :exec
 DELETE FROM chats WHERE id = $1
 `
@@ -1915,6 +2235,66 @@ func (q *sqlQuerier) GetGroupMembersByGroupID(ctx context.Context, arg GetGroupM
 	return items, nil
 }
 
+const getGroupMembersByGroupIDs = `-- name: GetGroupMembersByGroupIDs :many
+SELECT user_id, user_email, user_username, user_hashed_password, user_created_at, user_updated_at, user_status, user_rbac_roles, user_login_type, user_avatar_url, user_deleted, user_last_seen_at, user_quiet_hours_schedule, user_name, user_github_com_user_id, user_is_system, organization_id, group_name, group_id
+FROM group_members_expanded
+WHERE group_id = ANY($1 :: uuid[])
+  -- Filter by system type
+  AND CASE
+      WHEN $2::bool THEN TRUE
+      ELSE
+        user_is_system = false
+      END
+`
+
+type GetGroupMembersByGroupIDsParams struct {
+	GroupIds      []uuid.UUID `db:"group_ids" json:"group_ids"`
+	IncludeSystem bool        `db:"include_system" json:"include_system"`
+}
+
+func (q *sqlQuerier) GetGroupMembersByGroupIDs(ctx context.Context, arg GetGroupMembersByGroupIDsParams) ([]GroupMember, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembersByGroupIDs, pq.Array(arg.GroupIds), arg.IncludeSystem)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupMember
+	for rows.Next() {
+		var i GroupMember
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserEmail,
+			&i.UserUsername,
+			&i.UserHashedPassword,
+			&i.UserCreatedAt,
+			&i.UserUpdatedAt,
+			&i.UserStatus,
+			pq.Array(&i.UserRbacRoles),
+			&i.UserLoginType,
+			&i.UserAvatarUrl,
+			&i.UserDeleted,
+			&i.UserLastSeenAt,
+			&i.UserQuietHoursSchedule,
+			&i.UserName,
+			&i.UserGithubComUserID,
+			&i.UserIsSystem,
+			&i.OrganizationID,
+			&i.GroupName,
+			&i.GroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getGroupMembersCountByGroupID = `-- name: GetGroupMembersCountByGroupID :one
 SELECT COUNT(*)
 FROM group_members_expanded
@@ -4157,6 +4537,16 @@ func (q *sqlQuerier) DeleteAllWebpushSubscriptions(ctx context.Context) error {
 	return err
 }
 
+const deleteNotificationDigestEntries = `-- name: DeleteNotificationDigestEntries :exec
+DELETE FROM notification_digest_entries
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *sqlQuerier) DeleteNotificationDigestEntries(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteNotificationDigestEntries, pq.Array(ids))
+	return err
+}
+
 const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
 DELETE
 FROM notification_messages
@@ -4235,16 +4625,22 @@ func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg Enqueue
 }
 
 const fetchNewMessageMetadata = `-- name: FetchNewMessageMetadata :one
-SELECT nt.name                                                    AS notification_name,
-       nt.id                                                      AS notification_template_id,
-       nt.actions                                                 AS actions,
-       nt.method                                                  AS custom_method,
-       u.id                                                       AS user_id,
-       u.email                                                    AS user_email,
-       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
-       u.username                                                 AS user_username
-FROM notification_templates nt,
-     users u
+SELECT nt.name                                                                       AS notification_name,
+       nt.id                                                                         AS notification_template_id,
+       nt.actions                                                                    AS actions,
+       nt.method                                                                     AS custom_method,
+       nt.title_template                                                             AS title_template,
+       np.method                                                                     AS user_method,
+       -- digests are only honored for templates which allow them, and never for disabled notifications
+       COALESCE(nt.digestible AND np.digest AND NOT np.disabled, false)::bool        AS digest,
+       u.id                                                                          AS user_id,
+       u.email                                                                       AS user_email,
+       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text                    AS user_name,
+       u.username                                                                    AS user_username
+FROM notification_templates nt
+         CROSS JOIN users u
+         LEFT JOIN notification_preferences np
+                   ON (np.notification_template_id = nt.id AND np.user_id = u.id)
 WHERE nt.id = $1
   AND u.id = $2
 `
@@ -4259,6 +4655,9 @@ type FetchNewMessageMetadataRow struct {
 	NotificationTemplateID uuid.UUID              `db:"notification_template_id" json:"notification_template_id"`
 	Actions                []byte                 `db:"actions" json:"actions"`
 	CustomMethod           NullNotificationMethod `db:"custom_method" json:"custom_method"`
+	TitleTemplate          string                 `db:"title_template" json:"title_template"`
+	UserMethod             NullNotificationMethod `db:"user_method" json:"user_method"`
+	Digest                 bool                   `db:"digest" json:"digest"`
 	UserID                 uuid.UUID              `db:"user_id" json:"user_id"`
 	UserEmail              string                 `db:"user_email" json:"user_email"`
 	UserName               string                 `db:"user_name" json:"user_name"`
@@ -4274,6 +4673,9 @@ func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMe
 		&i.NotificationTemplateID,
 		&i.Actions,
 		&i.CustomMethod,
+		&i.TitleTemplate,
+		&i.UserMethod,
+		&i.Digest,
 		&i.UserID,
 		&i.UserEmail,
 		&i.UserName,
@@ -4282,14 +4684,51 @@ func (q *sqlQuerier) FetchNewMessageMetadata(ctx context.Context, arg FetchNewMe
 	return i, err
 }
 
-const getNotificationMessagesByStatus = `-- name: GetNotificationMessagesByStatus :many
-SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash
-FROM notification_messages
-WHERE status = $1
-LIMIT $2::int
+const getNotificationDigestEntriesByUserID = `-- name: GetNotificationDigestEntriesByUserID :many
+SELECT id, user_id, notification_template_id, title, payload, created_at
+FROM notification_digest_entries
+WHERE user_id = $1::uuid
+ORDER BY created_at ASC
 `
 
-type GetNotificationMessagesByStatusParams struct {
+func (q *sqlQuerier) GetNotificationDigestEntriesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationDigestEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationDigestEntriesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationDigestEntry
+	for rows.Next() {
+		var i NotificationDigestEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.NotificationTemplateID,
+			&i.Title,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationMessagesByStatus = `-- name: GetNotificationMessagesByStatus :many
+SELECT id, notification_template_id, user_id, method, status, status_reason, created_by, payload, attempt_count, targets, created_at, updated_at, leased_until, next_retry_after, queued_seconds, dedupe_hash
+FROM notification_messages
+WHERE status = $1
+LIMIT $2::int
+`
+
+type GetNotificationMessagesByStatusParams struct {
 	Status NotificationMessageStatus `db:"status" json:"status"`
 	Limit  int32                     `db:"limit" json:"limit"`
 }
@@ -4352,7 +4791,7 @@ func (q *sqlQuerier) GetNotificationReportGeneratorLogByTemplate(ctx context.Con
 }
 
 const getNotificationTemplateByID = `-- name: GetNotificationTemplateByID :one
-SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, digestible
 FROM notification_templates
 WHERE id = $1::uuid
 `
@@ -4370,12 +4809,13 @@ func (q *sqlQuerier) GetNotificationTemplateByID(ctx context.Context, id uuid.UU
 		&i.Method,
 		&i.Kind,
 		&i.EnabledByDefault,
+		&i.Digestible,
 	)
 	return i, err
 }
 
 const getNotificationTemplatesByKind = `-- name: GetNotificationTemplatesByKind :many
-SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+SELECT id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, digestible
 FROM notification_templates
 WHERE kind = $1::notification_template_kind
 ORDER BY name ASC
@@ -4400,6 +4840,7 @@ func (q *sqlQuerier) GetNotificationTemplatesByKind(ctx context.Context, kind No
 			&i.Method,
 			&i.Kind,
 			&i.EnabledByDefault,
+			&i.Digestible,
 		); err != nil {
 			return nil, err
 		}
@@ -4414,8 +4855,41 @@ func (q *sqlQuerier) GetNotificationTemplatesByKind(ctx context.Context, kind No
 	return items, nil
 }
 
+const getUserIDsWithDueNotificationDigests = `-- name: GetUserIDsWithDueNotificationDigests :many
+SELECT user_id
+FROM notification_digest_entries
+GROUP BY user_id
+HAVING MIN(created_at) <= $1::timestamptz
+ORDER BY user_id
+`
+
+// Returns the users whose oldest pending digest entry was created at or before the given time,
+// i.e. those whose digest interval has elapsed.
+func (q *sqlQuerier) GetUserIDsWithDueNotificationDigests(ctx context.Context, createdBefore time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getUserIDsWithDueNotificationDigests, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserNotificationPreferences = `-- name: GetUserNotificationPreferences :many
-SELECT user_id, notification_template_id, disabled, created_at, updated_at
+SELECT user_id, notification_template_id, disabled, created_at, updated_at, method, digest
 FROM notification_preferences
 WHERE user_id = $1::uuid
 `
@@ -4435,6 +4909,8 @@ func (q *sqlQuerier) GetUserNotificationPreferences(ctx context.Context, userID
 			&i.Disabled,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Method,
+			&i.Digest,
 		); err != nil {
 			return nil, err
 		}
@@ -4485,6 +4961,32 @@ func (q *sqlQuerier) GetWebpushSubscriptionsByUserID(ctx context.Context, userID
 	return items, nil
 }
 
+const insertNotificationDigestEntry = `-- name: InsertNotificationDigestEntry :exec
+INSERT INTO notification_digest_entries (id, user_id, notification_template_id, title, payload, created_at)
+VALUES ($1, $2, $3, $4, $5::jsonb, $6)
+`
+
+type InsertNotificationDigestEntryParams struct {
+	ID                     uuid.UUID       `db:"id" json:"id"`
+	UserID                 uuid.UUID       `db:"user_id" json:"user_id"`
+	NotificationTemplateID uuid.UUID       `db:"notification_template_id" json:"notification_template_id"`
+	Title                  string          `db:"title" json:"title"`
+	Payload                json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt              time.Time       `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertNotificationDigestEntry(ctx context.Context, arg InsertNotificationDigestEntryParams) error {
+	_, err := q.db.ExecContext(ctx, insertNotificationDigestEntry,
+		arg.ID,
+		arg.UserID,
+		arg.NotificationTemplateID,
+		arg.Title,
+		arg.Payload,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const insertWebpushSubscription = `-- name: InsertWebpushSubscription :one
 INSERT INTO webpush_subscriptions (user_id, created_at, endpoint, endpoint_p256dh_key, endpoint_auth_key)
 VALUES ($1, $2, $3, $4, $5)
@@ -4523,7 +5025,7 @@ const updateNotificationTemplateMethodByID = `-- name: UpdateNotificationTemplat
 UPDATE notification_templates
 SET method = $1::notification_method
 WHERE id = $2::uuid
-RETURNING id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default
+RETURNING id, name, title_template, body_template, actions, "group", method, kind, enabled_by_default, digestible
 `
 
 type UpdateNotificationTemplateMethodByIDParams struct {
@@ -4544,18 +5046,27 @@ func (q *sqlQuerier) UpdateNotificationTemplateMethodByID(ctx context.Context, a
 		&i.Method,
 		&i.Kind,
 		&i.EnabledByDefault,
+		&i.Digestible,
 	)
 	return i, err
 }
 
 const updateUserNotificationPreferences = `-- name: UpdateUserNotificationPreferences :execrows
 INSERT
-INTO notification_preferences (user_id, notification_template_id, disabled)
-SELECT $1::uuid, new_values.notification_template_id, new_values.disabled
+INTO notification_preferences (user_id, notification_template_id, disabled, method, digest)
+SELECT $1::uuid,
+       new_values.notification_template_id,
+       new_values.disabled,
+       NULLIF(new_values.method, '')::notification_method,
+       COALESCE(new_values.digest, false)
 FROM (SELECT UNNEST($2::uuid[]) AS notification_template_id,
-             UNNEST($3::bool[])                 AS disabled) AS new_values
+             UNNEST($3::bool[])                 AS disabled,
+             UNNEST($4::text[])                   AS method,
+             UNNEST($5::bool[])                   AS digest) AS new_values
 ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
+        method     = EXCLUDED.method,
+        digest     = EXCLUDED.digest,
         updated_at = CURRENT_TIMESTAMP
 `
 
@@ -4563,10 +5074,19 @@ type UpdateUserNotificationPreferencesParams struct {
 	UserID                  uuid.UUID   `db:"user_id" json:"user_id"`
 	NotificationTemplateIds []uuid.UUID `db:"notification_template_ids" json:"notification_template_ids"`
 	Disableds               []bool      `db:"disableds" json:"disableds"`
+	Methods                 []string    `db:"methods" json:"methods"`
+	Digests                 []bool      `db:"digests" json:"digests"`
 }
 
+// An empty or omitted method defers to the template-level or deployment-level method.
 func (q *sqlQuerier) UpdateUserNotificationPreferences(ctx context.Context, arg UpdateUserNotificationPreferencesParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, updateUserNotificationPreferences, arg.UserID, pq.Array(arg.NotificationTemplateIds), pq.Array(arg.Disableds))
+	result, err := q.db.ExecContext(ctx, updateUserNotificationPreferences,
+		arg.UserID,
+		pq.Array(arg.NotificationTemplateIds),
+		pq.Array(arg.Disableds),
+		pq.Array(arg.Methods),
+		pq.Array(arg.Digests),
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -9160,6 +9680,275 @@ func (q *sqlQuerier) UpdateCustomRole(ctx context.Context, arg UpdateCustomRoleP
 	return i, err
 }
 
+const deleteSCIMGroupByID = `-- name: DeleteSCIMGroupByID :exec
+DELETE FROM scim_groups WHERE id = $1
+`
+
+func (q *sqlQuerier) DeleteSCIMGroupByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSCIMGroupByID, id)
+	return err
+}
+
+const deleteSCIMGroupMembers = `-- name: DeleteSCIMGroupMembers :exec
+DELETE FROM
+	scim_group_members
+WHERE
+	scim_group_id = $1
+	AND user_id = ANY($2 :: uuid[])
+`
+
+type DeleteSCIMGroupMembersParams struct {
+	SCIMGroupID uuid.UUID   `db:"scim_group_id" json:"scim_group_id"`
+	UserIds     []uuid.UUID `db:"user_ids" json:"user_ids"`
+}
+
+func (q *sqlQuerier) DeleteSCIMGroupMembers(ctx context.Context, arg DeleteSCIMGroupMembersParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSCIMGroupMembers, arg.SCIMGroupID, pq.Array(arg.UserIds))
+	return err
+}
+
+const getSCIMGroupByID = `-- name: GetSCIMGroupByID :one
+SELECT id, external_id, display_name, created_at, updated_at FROM scim_groups WHERE id = $1
+`
+
+func (q *sqlQuerier) GetSCIMGroupByID(ctx context.Context, id uuid.UUID) (SCIMGroup, error) {
+	row := q.db.QueryRowContext(ctx, getSCIMGroupByID, id)
+	var i SCIMGroup
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DisplayName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getSCIMGroupMembers = `-- name: GetSCIMGroupMembers :many
+SELECT
+	scim_group_members.scim_group_id,
+	users.id AS user_id,
+	users.username
+FROM
+	scim_group_members
+	JOIN users ON users.id = scim_group_members.user_id
+WHERE
+	scim_group_members.scim_group_id = ANY($1 :: uuid[])
+ORDER BY
+	users.username ASC
+`
+
+type GetSCIMGroupMembersRow struct {
+	SCIMGroupID uuid.UUID `db:"scim_group_id" json:"scim_group_id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	Username    string    `db:"username" json:"username"`
+}
+
+func (q *sqlQuerier) GetSCIMGroupMembers(ctx context.Context, scimGroupIds []uuid.UUID) ([]GetSCIMGroupMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMGroupMembers, pq.Array(scimGroupIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSCIMGroupMembersRow
+	for rows.Next() {
+		var i GetSCIMGroupMembersRow
+		if err := rows.Scan(&i.SCIMGroupID, &i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSCIMGroups = `-- name: GetSCIMGroups :many
+SELECT
+	id, external_id, display_name, created_at, updated_at
+FROM
+	scim_groups
+WHERE
+	CASE
+		WHEN $1 :: text != '' THEN display_name = $1
+		ELSE true
+	END
+ORDER BY
+	display_name ASC
+`
+
+func (q *sqlQuerier) GetSCIMGroups(ctx context.Context, displayName string) ([]SCIMGroup, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMGroups, displayName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SCIMGroup
+	for rows.Next() {
+		var i SCIMGroup
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.DisplayName,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSCIMGroupsByUserIDs = `-- name: GetSCIMGroupsByUserIDs :many
+SELECT
+	scim_group_members.user_id,
+	scim_groups.display_name
+FROM
+	scim_groups
+	JOIN scim_group_members ON scim_group_members.scim_group_id = scim_groups.id
+WHERE
+	scim_group_members.user_id = ANY($1 :: uuid[])
+ORDER BY
+	scim_group_members.user_id ASC,
+	scim_groups.display_name ASC
+`
+
+type GetSCIMGroupsByUserIDsRow struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+}
+
+func (q *sqlQuerier) GetSCIMGroupsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]GetSCIMGroupsByUserIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSCIMGroupsByUserIDs, pq.Array(userIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSCIMGroupsByUserIDsRow
+	for rows.Next() {
+		var i GetSCIMGroupsByUserIDsRow
+		if err := rows.Scan(&i.UserID, &i.DisplayName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSCIMGroup = `-- name: InsertSCIMGroup :one
+INSERT INTO
+	scim_groups (id, external_id, display_name, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, external_id, display_name, created_at, updated_at
+`
+
+type InsertSCIMGroupParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	ExternalID  string    `db:"external_id" json:"external_id"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) InsertSCIMGroup(ctx context.Context, arg InsertSCIMGroupParams) (SCIMGroup, error) {
+	row := q.db.QueryRowContext(ctx, insertSCIMGroup,
+		arg.ID,
+		arg.ExternalID,
+		arg.DisplayName,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i SCIMGroup
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DisplayName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertSCIMGroupMembers = `-- name: InsertSCIMGroupMembers :exec
+INSERT INTO
+	scim_group_members (scim_group_id, user_id)
+SELECT
+	$1,
+	users.id
+FROM
+	users
+WHERE
+	users.id = ANY($2 :: uuid[])
+	AND users.deleted = false
+ON CONFLICT DO NOTHING
+`
+
+type InsertSCIMGroupMembersParams struct {
+	SCIMGroupID uuid.UUID   `db:"scim_group_id" json:"scim_group_id"`
+	UserIds     []uuid.UUID `db:"user_ids" json:"user_ids"`
+}
+
+// InsertSCIMGroupMembers adds users to a SCIM group. Users that are already
+// members, or that do not exist, are skipped.
+func (q *sqlQuerier) InsertSCIMGroupMembers(ctx context.Context, arg InsertSCIMGroupMembersParams) error {
+	_, err := q.db.ExecContext(ctx, insertSCIMGroupMembers, arg.SCIMGroupID, pq.Array(arg.UserIds))
+	return err
+}
+
+const updateSCIMGroupByID = `-- name: UpdateSCIMGroupByID :one
+UPDATE
+	scim_groups
+SET
+	external_id = $2,
+	display_name = $3,
+	updated_at = $4
+WHERE
+	id = $1
+RETURNING id, external_id, display_name, created_at, updated_at
+`
+
+type UpdateSCIMGroupByIDParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	ExternalID  string    `db:"external_id" json:"external_id"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateSCIMGroupByID(ctx context.Context, arg UpdateSCIMGroupByIDParams) (SCIMGroup, error) {
+	row := q.db.QueryRowContext(ctx, updateSCIMGroupByID,
+		arg.ID,
+		arg.ExternalID,
+		arg.DisplayName,
+		arg.UpdatedAt,
+	)
+	var i SCIMGroup
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DisplayName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const deleteRuntimeConfig = `-- name: DeleteRuntimeConfig :exec
 DELETE FROM site_configs
 WHERE site_configs.key = $1
@@ -10427,7 +11216,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording, activity_bump_requires_user_activity, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names
 WHERE
@@ -10469,6 +11258,8 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.SessionRecording,
+		&i.ActivityBumpRequiresUserActivity,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.OrganizationName,
@@ -10480,7 +11271,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording, activity_bump_requires_user_activity, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -10530,6 +11321,8 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.ActivityBump,
 		&i.MaxPortSharingLevel,
 		&i.UseClassicParameterFlow,
+		&i.SessionRecording,
+		&i.ActivityBumpRequiresUserActivity,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 		&i.OrganizationName,
@@ -10540,7 +11333,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording, activity_bump_requires_user_activity, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon FROM template_with_names AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -10583,6 +11376,8 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.SessionRecording,
+			&i.ActivityBumpRequiresUserActivity,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
@@ -10604,7 +11399,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording, activity_bump_requires_user_activity, created_by_avatar_url, created_by_username, organization_name, organization_display_name, organization_icon
 FROM
 	template_with_names AS templates
 WHERE
@@ -10705,6 +11500,8 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.ActivityBump,
 			&i.MaxPortSharingLevel,
 			&i.UseClassicParameterFlow,
+			&i.SessionRecording,
+			&i.ActivityBumpRequiresUserActivity,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 			&i.OrganizationName,
@@ -10882,22 +11679,26 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	session_recording = $11,
+	activity_bump_requires_user_activity = $12
 WHERE
 	id = $1
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	Description                  string          `db:"description" json:"description"`
-	Name                         string          `db:"name" json:"name"`
-	Icon                         string          `db:"icon" json:"icon"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	MaxPortSharingLevel          AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
-	UseClassicParameterFlow      bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	ID                               uuid.UUID       `db:"id" json:"id"`
+	UpdatedAt                        time.Time       `db:"updated_at" json:"updated_at"`
+	Description                      string          `db:"description" json:"description"`
+	Name                             string          `db:"name" json:"name"`
+	Icon                             string          `db:"icon" json:"icon"`
+	DisplayName                      string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs     bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	GroupACL                         TemplateACL     `db:"group_acl" json:"group_acl"`
+	MaxPortSharingLevel              AppSharingLevel `db:"max_port_sharing_level" json:"max_port_sharing_level"`
+	UseClassicParameterFlow          bool            `db:"use_classic_parameter_flow" json:"use_classic_parameter_flow"`
+	SessionRecording                 bool            `db:"session_recording" json:"session_recording"`
+	ActivityBumpRequiresUserActivity bool            `db:"activity_bump_requires_user_activity" json:"activity_bump_requires_user_activity"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -10912,6 +11713,8 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.GroupACL,
 		arg.MaxPortSharingLevel,
 		arg.UseClassicParameterFlow,
+		arg.SessionRecording,
+		arg.ActivityBumpRequiresUserActivity,
 	)
 	return err
 }
@@ -13407,9 +14210,76 @@ func (q *sqlQuerier) InsertWorkspaceAgentDevcontainers(ctx context.Context, arg
 	return items, nil
 }
 
-const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
-DELETE FROM
-	workspace_agent_port_share
+const getWorkspaceAgentPortForwardingPolicy = `-- name: GetWorkspaceAgentPortForwardingPolicy :one
+SELECT
+	agent_id, allowed_ports, denied_ports, allowed_unix_sockets, denied_unix_sockets, created_at
+FROM
+	workspace_agent_port_forwarding_policies
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentPortForwardingPolicy(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentPortForwardingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortForwardingPolicy, agentID)
+	var i WorkspaceAgentPortForwardingPolicy
+	err := row.Scan(
+		&i.AgentID,
+		pq.Array(&i.AllowedPorts),
+		pq.Array(&i.DeniedPorts),
+		pq.Array(&i.AllowedUnixSockets),
+		pq.Array(&i.DeniedUnixSockets),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceAgentPortForwardingPolicy = `-- name: InsertWorkspaceAgentPortForwardingPolicy :one
+INSERT INTO
+	workspace_agent_port_forwarding_policies (
+		agent_id,
+		allowed_ports,
+		denied_ports,
+		allowed_unix_sockets,
+		denied_unix_sockets,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING agent_id, allowed_ports, denied_ports, allowed_unix_sockets, denied_unix_sockets, created_at
+`
+
+type InsertWorkspaceAgentPortForwardingPolicyParams struct {
+	AgentID            uuid.UUID `db:"agent_id" json:"agent_id"`
+	AllowedPorts       []string  `db:"allowed_ports" json:"allowed_ports"`
+	DeniedPorts        []string  `db:"denied_ports" json:"denied_ports"`
+	AllowedUnixSockets []string  `db:"allowed_unix_sockets" json:"allowed_unix_sockets"`
+	DeniedUnixSockets  []string  `db:"denied_unix_sockets" json:"denied_unix_sockets"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentPortForwardingPolicy(ctx context.Context, arg InsertWorkspaceAgentPortForwardingPolicyParams) (WorkspaceAgentPortForwardingPolicy, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentPortForwardingPolicy,
+		arg.AgentID,
+		pq.Array(arg.AllowedPorts),
+		pq.Array(arg.DeniedPorts),
+		pq.Array(arg.AllowedUnixSockets),
+		pq.Array(arg.DeniedUnixSockets),
+		arg.CreatedAt,
+	)
+	var i WorkspaceAgentPortForwardingPolicy
+	err := row.Scan(
+		&i.AgentID,
+		pq.Array(&i.AllowedPorts),
+		pq.Array(&i.DeniedPorts),
+		pq.Array(&i.AllowedUnixSockets),
+		pq.Array(&i.DeniedUnixSockets),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_share
 WHERE
 	workspace_id = $1
 	AND agent_name = $2
@@ -13448,13 +14318,16 @@ func (q *sqlQuerier) DeleteWorkspaceAgentPortSharesByTemplate(ctx context.Contex
 
 const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
 SELECT
-	workspace_id, agent_name, port, share_level, protocol
+	workspace_id, agent_name, port, share_level, protocol, expires_at
 FROM
 	workspace_agent_port_share
 WHERE
 	workspace_id = $1
 	AND agent_name = $2
 	AND port = $3
+	-- Expired shares are kept until they are deleted or replaced, but no
+	-- longer grant access.
+	AND (expires_at IS NULL OR expires_at > NOW())
 `
 
 type GetWorkspaceAgentPortShareParams struct {
@@ -13472,17 +14345,19 @@ func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWork
 		&i.Port,
 		&i.ShareLevel,
 		&i.Protocol,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
 const listWorkspaceAgentPortShares = `-- name: ListWorkspaceAgentPortShares :many
 SELECT
-	workspace_id, agent_name, port, share_level, protocol
+	workspace_id, agent_name, port, share_level, protocol, expires_at
 FROM
 	workspace_agent_port_share
 WHERE
 	workspace_id = $1
+	AND (expires_at IS NULL OR expires_at > NOW())
 `
 
 func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
@@ -13500,6 +14375,7 @@ func (q *sqlQuerier) ListWorkspaceAgentPortShares(ctx context.Context, workspace
 			&i.Port,
 			&i.ShareLevel,
 			&i.Protocol,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -13543,14 +14419,16 @@ INSERT INTO
 		agent_name,
 		port,
 		share_level,
-		protocol
+		protocol,
+		expires_at
 	)
 VALUES (
 	$1,
 	$2,
 	$3,
 	$4,
-	$5
+	$5,
+	$6
 )
 ON CONFLICT (
 	workspace_id,
@@ -13559,8 +14437,9 @@ ON CONFLICT (
 )
 DO UPDATE SET
 	share_level = $4,
-	protocol = $5
-RETURNING workspace_id, agent_name, port, share_level, protocol
+	protocol = $5,
+	expires_at = $6
+RETURNING workspace_id, agent_name, port, share_level, protocol, expires_at
 `
 
 type UpsertWorkspaceAgentPortShareParams struct {
@@ -13569,6 +14448,7 @@ type UpsertWorkspaceAgentPortShareParams struct {
 	Port        int32             `db:"port" json:"port"`
 	ShareLevel  AppSharingLevel   `db:"share_level" json:"share_level"`
 	Protocol    PortShareProtocol `db:"protocol" json:"protocol"`
+	ExpiresAt   sql.NullTime      `db:"expires_at" json:"expires_at"`
 }
 
 func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
@@ -13578,6 +14458,7 @@ func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg Upse
 		arg.Port,
 		arg.ShareLevel,
 		arg.Protocol,
+		arg.ExpiresAt,
 	)
 	var i WorkspaceAgentPortShare
 	err := row.Scan(
@@ -13586,6 +14467,7 @@ func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg Upse
 		&i.Port,
 		&i.ShareLevel,
 		&i.Protocol,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
@@ -13654,6 +14536,88 @@ func (q *sqlQuerier) FetchMemoryResourceMonitorsUpdatedAfter(ctx context.Context
 	return items, nil
 }
 
+const fetchResourceMonitorsByAgentID = `-- name: FetchResourceMonitorsByAgentID :many
+SELECT
+	agent_id, type, enabled, threshold, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_resource_monitors
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) FetchResourceMonitorsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceAgentResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchResourceMonitorsByAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Type,
+			&i.Enabled,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchResourceMonitorsUpdatedAfter = `-- name: FetchResourceMonitorsUpdatedAfter :many
+SELECT
+	agent_id, type, enabled, threshold, created_at, updated_at, state, debounced_until
+FROM
+	workspace_agent_resource_monitors
+WHERE
+	updated_at > $1
+`
+
+func (q *sqlQuerier) FetchResourceMonitorsUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]WorkspaceAgentResourceMonitor, error) {
+	rows, err := q.db.QueryContext(ctx, fetchResourceMonitorsUpdatedAfter, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentResourceMonitor
+	for rows.Next() {
+		var i WorkspaceAgentResourceMonitor
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Type,
+			&i.Enabled,
+			&i.Threshold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.State,
+			&i.DebouncedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchVolumesResourceMonitorsByAgentID = `-- name: FetchVolumesResourceMonitorsByAgentID :many
 SELECT
 	agent_id, enabled, threshold, path, created_at, updated_at, state, debounced_until
@@ -13784,6 +14748,58 @@ func (q *sqlQuerier) InsertMemoryResourceMonitor(ctx context.Context, arg Insert
 	return i, err
 }
 
+const insertResourceMonitor = `-- name: InsertResourceMonitor :one
+INSERT INTO
+	workspace_agent_resource_monitors (
+		agent_id,
+		type,
+		enabled,
+		state,
+		threshold,
+		created_at,
+		updated_at,
+		debounced_until
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING agent_id, type, enabled, threshold, created_at, updated_at, state, debounced_until
+`
+
+type InsertResourceMonitorParams struct {
+	AgentID        uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type           WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	Enabled        bool                              `db:"enabled" json:"enabled"`
+	State          WorkspaceAgentMonitorState        `db:"state" json:"state"`
+	Threshold      int32                             `db:"threshold" json:"threshold"`
+	CreatedAt      time.Time                         `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time                         `db:"updated_at" json:"updated_at"`
+	DebouncedUntil time.Time                         `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) InsertResourceMonitor(ctx context.Context, arg InsertResourceMonitorParams) (WorkspaceAgentResourceMonitor, error) {
+	row := q.db.QueryRowContext(ctx, insertResourceMonitor,
+		arg.AgentID,
+		arg.Type,
+		arg.Enabled,
+		arg.State,
+		arg.Threshold,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DebouncedUntil,
+	)
+	var i WorkspaceAgentResourceMonitor
+	err := row.Scan(
+		&i.AgentID,
+		&i.Type,
+		&i.Enabled,
+		&i.Threshold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.State,
+		&i.DebouncedUntil,
+	)
+	return i, err
+}
+
 const insertVolumeResourceMonitor = `-- name: InsertVolumeResourceMonitor :one
 INSERT INTO
 	workspace_agent_volume_resource_monitors (
@@ -13863,6 +14879,35 @@ func (q *sqlQuerier) UpdateMemoryResourceMonitor(ctx context.Context, arg Update
 	return err
 }
 
+const updateResourceMonitor = `-- name: UpdateResourceMonitor :exec
+UPDATE workspace_agent_resource_monitors
+SET
+	updated_at = $3,
+	state = $4,
+	debounced_until = $5
+WHERE
+	agent_id = $1 AND type = $2
+`
+
+type UpdateResourceMonitorParams struct {
+	AgentID        uuid.UUID                         `db:"agent_id" json:"agent_id"`
+	Type           WorkspaceAgentResourceMonitorType `db:"type" json:"type"`
+	UpdatedAt      time.Time                         `db:"updated_at" json:"updated_at"`
+	State          WorkspaceAgentMonitorState        `db:"state" json:"state"`
+	DebouncedUntil time.Time                         `db:"debounced_until" json:"debounced_until"`
+}
+
+func (q *sqlQuerier) UpdateResourceMonitor(ctx context.Context, arg UpdateResourceMonitorParams) error {
+	_, err := q.db.ExecContext(ctx, updateResourceMonitor,
+		arg.AgentID,
+		arg.Type,
+		arg.UpdatedAt,
+		arg.State,
+		arg.DebouncedUntil,
+	)
+	return err
+}
+
 const updateVolumeResourceMonitor = `-- name: UpdateVolumeResourceMonitor :exec
 UPDATE workspace_agent_volume_resource_monitors
 SET
@@ -13945,7 +14990,7 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context, threshold
 
 const getWorkspaceAgentAndLatestBuildByAuthToken = `-- name: GetWorkspaceAgentAndLatestBuildByAuthToken :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.user_acl, workspaces.group_acl,
 	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version, workspace_agents.display_order, workspace_agents.parent_id, workspace_agents.api_key_scope,
 	workspace_build_with_user.id, workspace_build_with_user.created_at, workspace_build_with_user.updated_at, workspace_build_with_user.workspace_id, workspace_build_with_user.template_version_id, workspace_build_with_user.build_number, workspace_build_with_user.transition, workspace_build_with_user.initiator_id, workspace_build_with_user.provisioner_state, workspace_build_with_user.job_id, workspace_build_with_user.deadline, workspace_build_with_user.reason, workspace_build_with_user.daily_cost, workspace_build_with_user.max_deadline, workspace_build_with_user.template_version_preset_id, workspace_build_with_user.initiator_by_avatar_url, workspace_build_with_user.initiator_by_username
 FROM
@@ -14005,6 +15050,8 @@ func (q *sqlQuerier) GetWorkspaceAgentAndLatestBuildByAuthToken(ctx context.Cont
 		&i.WorkspaceTable.AutomaticUpdates,
 		&i.WorkspaceTable.Favorite,
 		&i.WorkspaceTable.NextStartAt,
+		&i.WorkspaceTable.UserACL,
+		&i.WorkspaceTable.GroupACL,
 		&i.WorkspaceAgent.ID,
 		&i.WorkspaceAgent.CreatedAt,
 		&i.WorkspaceAgent.UpdatedAt,
@@ -14316,7 +15363,7 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorks
 
 const getWorkspaceAgentScriptTimingsByBuildID = `-- name: GetWorkspaceAgentScriptTimingsByBuildID :many
 SELECT
-	DISTINCT ON (workspace_agent_script_timings.script_id) workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status,
+	DISTINCT ON (workspace_agent_script_timings.script_id) workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status, workspace_agent_script_timings.queued_at,
 	workspace_agent_scripts.display_name,
 	workspace_agents.id as workspace_agent_id,
 	workspace_agents.name as workspace_agent_name
@@ -14336,6 +15383,7 @@ type GetWorkspaceAgentScriptTimingsByBuildIDRow struct {
 	ExitCode           int32                            `db:"exit_code" json:"exit_code"`
 	Stage              WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
 	Status             WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	QueuedAt           time.Time                        `db:"queued_at" json:"queued_at"`
 	DisplayName        string                           `db:"display_name" json:"display_name"`
 	WorkspaceAgentID   uuid.UUID                        `db:"workspace_agent_id" json:"workspace_agent_id"`
 	WorkspaceAgentName string                           `db:"workspace_agent_name" json:"workspace_agent_name"`
@@ -14357,6 +15405,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByBuildID(ctx context.Context
 			&i.ExitCode,
 			&i.Stage,
 			&i.Status,
+			&i.QueuedAt,
 			&i.DisplayName,
 			&i.WorkspaceAgentID,
 			&i.WorkspaceAgentName,
@@ -14931,11 +15980,12 @@ INSERT INTO
         ended_at,
         exit_code,
         stage,
-        status
+        status,
+        queued_at
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6)
-RETURNING workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status
+    ($1, $2, $3, $4, $5, $6, $7)
+RETURNING workspace_agent_script_timings.script_id, workspace_agent_script_timings.started_at, workspace_agent_script_timings.ended_at, workspace_agent_script_timings.exit_code, workspace_agent_script_timings.stage, workspace_agent_script_timings.status, workspace_agent_script_timings.queued_at
 `
 
 type InsertWorkspaceAgentScriptTimingsParams struct {
@@ -14945,6 +15995,7 @@ type InsertWorkspaceAgentScriptTimingsParams struct {
 	ExitCode  int32                            `db:"exit_code" json:"exit_code"`
 	Stage     WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
 	Status    WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	QueuedAt  time.Time                        `db:"queued_at" json:"queued_at"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg InsertWorkspaceAgentScriptTimingsParams) (WorkspaceAgentScriptTiming, error) {
@@ -14955,6 +16006,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg
 		arg.ExitCode,
 		arg.Stage,
 		arg.Status,
+		arg.QueuedAt,
 	)
 	var i WorkspaceAgentScriptTiming
 	err := row.Scan(
@@ -14964,6 +16016,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentScriptTimings(ctx context.Context, arg
 		&i.ExitCode,
 		&i.Stage,
 		&i.Status,
+		&i.QueuedAt,
 	)
 	return i, err
 }
@@ -15969,6 +17022,19 @@ func (q *sqlQuerier) UpsertWorkspaceAppAuditSession(ctx context.Context, arg Ups
 	return new_or_stale, err
 }
 
+const deleteWorkspaceAppsByIDs = `-- name: DeleteWorkspaceAppsByIDs :exec
+WITH deleted_statuses AS (
+	DELETE FROM workspace_app_statuses WHERE app_id = ANY($1 :: uuid [ ])
+)
+DELETE FROM workspace_apps WHERE id = ANY($1 :: uuid [ ])
+`
+
+// The statuses of the apps are deleted with them.
+func (q *sqlQuerier) DeleteWorkspaceAppsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAppsByIDs, pq.Array(ids))
+	return err
+}
+
 const getLatestWorkspaceAppStatusesByWorkspaceIDs = `-- name: GetLatestWorkspaceAppStatusesByWorkspaceIDs :many
 SELECT DISTINCT ON (workspace_id)
   id, created_at, agent_id, app_id, workspace_id, state, message, uri
@@ -17883,7 +18949,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -17931,6 +18997,8 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OrganizationName,
@@ -17947,7 +19015,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded
 WHERE
@@ -17976,6 +19044,8 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OrganizationName,
@@ -17992,7 +19062,7 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18028,6 +19098,8 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OrganizationName,
@@ -18044,7 +19116,7 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl, owner_avatar_url, owner_username, organization_name, organization_display_name, organization_icon, organization_description, template_name, template_display_name, template_icon, template_description
 FROM
 	workspaces_expanded as workspaces
 WHERE
@@ -18099,6 +19171,8 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 		&i.OwnerAvatarUrl,
 		&i.OwnerUsername,
 		&i.OrganizationName,
@@ -18113,6 +19187,45 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 	return i, err
 }
 
+const getWorkspaceIDsByOwnerIDAndTemplateIDs = `-- name: GetWorkspaceIDsByOwnerIDAndTemplateIDs :many
+SELECT
+	id
+FROM
+	workspaces
+WHERE
+	owner_id = $1
+	AND template_id = ANY($2 :: uuid[])
+	AND deleted = false
+`
+
+type GetWorkspaceIDsByOwnerIDAndTemplateIDsParams struct {
+	OwnerID     uuid.UUID   `db:"owner_id" json:"owner_id"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+func (q *sqlQuerier) GetWorkspaceIDsByOwnerIDAndTemplateIDs(ctx context.Context, arg GetWorkspaceIDsByOwnerIDAndTemplateIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceIDsByOwnerIDAndTemplateIDs, arg.OwnerID, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceUniqueOwnerCountByTemplateIDs = `-- name: GetWorkspaceUniqueOwnerCountByTemplateIDs :many
 SELECT templates.id AS template_id, COUNT(DISTINCT workspaces.owner_id) AS unique_owners_sum
 FROM templates
@@ -18158,7 +19271,7 @@ SELECT
 ),
 filtered_workspaces AS (
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.user_acl, workspaces.group_acl, workspaces.owner_avatar_url, workspaces.owner_username, workspaces.organization_name, workspaces.organization_display_name, workspaces.organization_icon, workspaces.organization_description, workspaces.template_name, workspaces.template_display_name, workspaces.template_icon, workspaces.template_description,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
 	latest_build.completed_at as latest_build_completed_at,
@@ -18204,7 +19317,7 @@ LEFT JOIN LATERAL (
 ) latest_build ON TRUE
 LEFT JOIN LATERAL (
 	SELECT
-		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow
+		id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, activity_bump, max_port_sharing_level, use_classic_parameter_flow, session_recording, activity_bump_requires_user_activity
 	FROM
 		templates
 	WHERE
@@ -18398,7 +19511,7 @@ WHERE
 	-- @authorize_filter
 ), filtered_workspaces_order AS (
 	SELECT
-		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.owner_avatar_url, fw.owner_username, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status
+		fw.id, fw.created_at, fw.updated_at, fw.owner_id, fw.organization_id, fw.template_id, fw.deleted, fw.name, fw.autostart_schedule, fw.ttl, fw.last_used_at, fw.dormant_at, fw.deleting_at, fw.automatic_updates, fw.favorite, fw.next_start_at, fw.user_acl, fw.group_acl, fw.owner_avatar_url, fw.owner_username, fw.organization_name, fw.organization_display_name, fw.organization_icon, fw.organization_description, fw.template_name, fw.template_display_name, fw.template_icon, fw.template_description, fw.template_version_id, fw.template_version_name, fw.latest_build_completed_at, fw.latest_build_canceled_at, fw.latest_build_error, fw.latest_build_transition, fw.latest_build_status
 	FROM
 		filtered_workspaces fw
 	ORDER BY
@@ -18419,7 +19532,7 @@ WHERE
 		$20
 ), filtered_workspaces_order_with_summary AS (
 	SELECT
-		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.owner_avatar_url, fwo.owner_username, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status
+		fwo.id, fwo.created_at, fwo.updated_at, fwo.owner_id, fwo.organization_id, fwo.template_id, fwo.deleted, fwo.name, fwo.autostart_schedule, fwo.ttl, fwo.last_used_at, fwo.dormant_at, fwo.deleting_at, fwo.automatic_updates, fwo.favorite, fwo.next_start_at, fwo.user_acl, fwo.group_acl, fwo.owner_avatar_url, fwo.owner_username, fwo.organization_name, fwo.organization_display_name, fwo.organization_icon, fwo.organization_description, fwo.template_name, fwo.template_display_name, fwo.template_icon, fwo.template_description, fwo.template_version_id, fwo.template_version_name, fwo.latest_build_completed_at, fwo.latest_build_canceled_at, fwo.latest_build_error, fwo.latest_build_transition, fwo.latest_build_status
 	FROM
 		filtered_workspaces_order fwo
 	-- Return a technical summary row with total count of workspaces.
@@ -18469,7 +19582,7 @@ WHERE
 		filtered_workspaces
 )
 SELECT
-	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.owner_avatar_url, fwos.owner_username, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status,
+	fwos.id, fwos.created_at, fwos.updated_at, fwos.owner_id, fwos.organization_id, fwos.template_id, fwos.deleted, fwos.name, fwos.autostart_schedule, fwos.ttl, fwos.last_used_at, fwos.dormant_at, fwos.deleting_at, fwos.automatic_updates, fwos.favorite, fwos.next_start_at, fwos.user_acl, fwos.group_acl, fwos.owner_avatar_url, fwos.owner_username, fwos.organization_name, fwos.organization_display_name, fwos.organization_icon, fwos.organization_description, fwos.template_name, fwos.template_display_name, fwos.template_icon, fwos.template_description, fwos.template_version_id, fwos.template_version_name, fwos.latest_build_completed_at, fwos.latest_build_canceled_at, fwos.latest_build_error, fwos.latest_build_transition, fwos.latest_build_status,
 	tc.count
 FROM
 	filtered_workspaces_order_with_summary fwos
@@ -18519,6 +19632,8 @@ type GetWorkspacesRow struct {
 	AutomaticUpdates        AutomaticUpdates     `db:"automatic_updates" json:"automatic_updates"`
 	Favorite                bool                 `db:"favorite" json:"favorite"`
 	NextStartAt             sql.NullTime         `db:"next_start_at" json:"next_start_at"`
+	UserACL                 json.RawMessage      `db:"user_acl" json:"user_acl"`
+	GroupACL                json.RawMessage      `db:"group_acl" json:"group_acl"`
 	OwnerAvatarUrl          string               `db:"owner_avatar_url" json:"owner_avatar_url"`
 	OwnerUsername           string               `db:"owner_username" json:"owner_username"`
 	OrganizationName        string               `db:"organization_name" json:"organization_name"`
@@ -18591,6 +19706,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.UserACL,
+			&i.GroupACL,
 			&i.OwnerAvatarUrl,
 			&i.OwnerUsername,
 			&i.OrganizationName,
@@ -18699,7 +19816,7 @@ func (q *sqlQuerier) GetWorkspacesAndAgentsByOwnerID(ctx context.Context, ownerI
 }
 
 const getWorkspacesByTemplateID = `-- name: GetWorkspacesByTemplateID :many
-SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at FROM workspaces WHERE template_id = $1 AND deleted = false
+SELECT id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl FROM workspaces WHERE template_id = $1 AND deleted = false
 `
 
 func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceTable, error) {
@@ -18728,6 +19845,8 @@ func (q *sqlQuerier) GetWorkspacesByTemplateID(ctx context.Context, templateID u
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.UserACL,
+			&i.GroupACL,
 		); err != nil {
 			return nil, err
 		}
@@ -18820,14 +19939,14 @@ WHERE
 
 		-- A workspace may be eligible for deletion if the following are true:
 		--   * The workspace is dormant.
-		--   * The workspace is scheduled to be deleted.
+		--   * The workspace is scheduled to be deleted, either by the template's
+		--     time_til_dormant_autodelete or by SCIM deprovisioning.
 		--   * If there was a prior attempt to delete the workspace that failed:
 		--      * This attempt was at least 24 hours ago.
 		(
 			workspaces.dormant_at IS NOT NULL AND
 			workspaces.deleting_at IS NOT NULL AND
 			workspaces.deleting_at < $1 :: timestamptz AND
-			templates.time_til_dormant_autodelete > 0 AND
 			CASE
 				WHEN (
 					workspace_builds.transition = 'delete'::workspace_transition AND
@@ -18908,7 +20027,7 @@ INSERT INTO
 		next_start_at
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl
 `
 
 type InsertWorkspaceParams struct {
@@ -18959,6 +20078,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -18998,7 +20119,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl
 `
 
 type UpdateWorkspaceParams struct {
@@ -19026,10 +20147,33 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
+const updateWorkspaceACLByID = `-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceACLByIDParams struct {
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
+	UserACL  WorkspaceACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceACLByID, arg.GroupACL, arg.UserACL, arg.ID)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -19089,37 +20233,81 @@ func (q *sqlQuerier) UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateW
 	return err
 }
 
-const updateWorkspaceDormantDeletingAt = `-- name: UpdateWorkspaceDormantDeletingAt :one
+const updateWorkspaceDeletingAt = `-- name: UpdateWorkspaceDeletingAt :one
 UPDATE
-    workspaces
+	workspaces
 SET
-    dormant_at = $2,
-    -- When a workspace is active we want to update the last_used_at to avoid the workspace going
-    -- immediately dormant. If we're transition the workspace to dormant then we leave it alone.
-    last_used_at = CASE WHEN $2::timestamptz IS NULL THEN
-        now() at time zone 'utc'
-    ELSE
-        last_used_at
-    END,
-    -- If dormant_at is null (meaning active) or the template-defined time_til_dormant_autodelete is 0 we should set
-    -- deleting_at to NULL else set it to the dormant_at + time_til_dormant_autodelete duration.
-    deleting_at = CASE WHEN $2::timestamptz IS NULL OR templates.time_til_dormant_autodelete = 0 THEN
-        NULL
-    ELSE
-        $2::timestamptz + (INTERVAL '1 millisecond' * (templates.time_til_dormant_autodelete / 1000000))
-    END
-FROM
-    templates
+	deleting_at = $1
 WHERE
-    workspaces.id = $1
-    AND templates.id = workspaces.template_id
-RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at
+	id = $2
+	AND dormant_at IS NOT NULL
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl
 `
 
-type UpdateWorkspaceDormantDeletingAtParams struct {
-	ID        uuid.UUID    `db:"id" json:"id"`
-	DormantAt sql.NullTime `db:"dormant_at" json:"dormant_at"`
+type UpdateWorkspaceDeletingAtParams struct {
+	DeletingAt sql.NullTime `db:"deleting_at" json:"deleting_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+// UpdateWorkspaceDeletingAt schedules the deletion of a dormant workspace,
+// regardless of the template's dormancy settings.
+func (q *sqlQuerier) UpdateWorkspaceDeletingAt(ctx context.Context, arg UpdateWorkspaceDeletingAtParams) (WorkspaceTable, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceDeletingAt, arg.DeletingAt, arg.ID)
+	var i WorkspaceTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
+	)
+	return i, err
+}
+
+const updateWorkspaceDormantDeletingAt = `-- name: UpdateWorkspaceDormantDeletingAt :one
+UPDATE
+    workspaces
+SET
+    dormant_at = $2,
+    -- When a workspace is active we want to update the last_used_at to avoid the workspace going
+    -- immediately dormant. If we're transition the workspace to dormant then we leave it alone.
+    last_used_at = CASE WHEN $2::timestamptz IS NULL THEN
+        now() at time zone 'utc'
+    ELSE
+        last_used_at
+    END,
+    -- If dormant_at is null (meaning active) or the template-defined time_til_dormant_autodelete is 0 we should set
+    -- deleting_at to NULL else set it to the dormant_at + time_til_dormant_autodelete duration.
+    deleting_at = CASE WHEN $2::timestamptz IS NULL OR templates.time_til_dormant_autodelete = 0 THEN
+        NULL
+    ELSE
+        $2::timestamptz + (INTERVAL '1 millisecond' * (templates.time_til_dormant_autodelete / 1000000))
+    END
+FROM
+    templates
+WHERE
+    workspaces.id = $1
+    AND templates.id = workspaces.template_id
+RETURNING
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.favorite, workspaces.next_start_at, workspaces.user_acl, workspaces.group_acl
+`
+
+type UpdateWorkspaceDormantDeletingAtParams struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	DormantAt sql.NullTime `db:"dormant_at" json:"dormant_at"`
 }
 
 func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg UpdateWorkspaceDormantDeletingAtParams) (WorkspaceTable, error) {
@@ -19142,6 +20330,8 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.AutomaticUpdates,
 		&i.Favorite,
 		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -19184,6 +20374,49 @@ func (q *sqlQuerier) UpdateWorkspaceNextStartAt(ctx context.Context, arg UpdateW
 	return err
 }
 
+const updateWorkspaceOwnerByID = `-- name: UpdateWorkspaceOwnerByID :one
+UPDATE
+	workspaces
+SET
+	owner_id = $1,
+	updated_at = $2
+WHERE
+	id = $3
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl
+`
+
+type UpdateWorkspaceOwnerByIDParams struct {
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) (WorkspaceTable, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceOwnerByID, arg.OwnerID, arg.UpdatedAt, arg.ID)
+	var i WorkspaceTable
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.Favorite,
+		&i.NextStartAt,
+		&i.UserACL,
+		&i.GroupACL,
+	)
+	return i, err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -19216,7 +20449,7 @@ WHERE
     template_id = $3
 AND
     dormant_at IS NOT NULL
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, favorite, next_start_at, user_acl, group_acl
 `
 
 type UpdateWorkspacesDormantDeletingAtByTemplateIDParams struct {
@@ -19251,6 +20484,8 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 			&i.AutomaticUpdates,
 			&i.Favorite,
 			&i.NextStartAt,
+			&i.UserACL,
+			&i.GroupACL,
 		); err != nil {
 			return nil, err
 		}
@@ -19285,7 +20520,7 @@ func (q *sqlQuerier) UpdateWorkspacesTTLByTemplateID(ctx context.Context, arg Up
 }
 
 const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
-SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id, depends_on, readiness_probe_command, readiness_probe_tcp, readiness_probe_http, readiness_probe_interval_seconds, readiness_probe_timeout_seconds FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
@@ -19310,6 +20545,12 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 			&i.TimeoutSeconds,
 			&i.DisplayName,
 			&i.ID,
+			pq.Array(&i.DependsOn),
+			&i.ReadinessProbeCommand,
+			&i.ReadinessProbeTCP,
+			&i.ReadinessProbeHTTP,
+			&i.ReadinessProbeIntervalSeconds,
+			&i.ReadinessProbeTimeoutSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -19326,7 +20567,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 
 const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id, depends_on, readiness_probe_command, readiness_probe_tcp, readiness_probe_http, readiness_probe_interval_seconds, readiness_probe_timeout_seconds)
 SELECT
 	$1 :: uuid AS workspace_agent_id,
 	$2 :: timestamptz AS created_at,
@@ -19339,23 +20580,37 @@ SELECT
 	unnest($9 :: boolean [ ]) AS run_on_stop,
 	unnest($10 :: integer [ ]) AS timeout_seconds,
 	unnest($11 :: text [ ]) AS display_name,
-	unnest($12 :: uuid [ ]) AS id
-RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.display_name, workspace_agent_scripts.id
+	unnest($12 :: uuid [ ]) AS id,
+	-- Each element is a text array literal, e.g. '{"Install tools"}', as
+	-- arrays of arrays with different lengths cannot be passed.
+	unnest($13 :: text [ ]) :: text [ ] AS depends_on,
+	unnest($14 :: text [ ]) AS readiness_probe_command,
+	unnest($15 :: text [ ]) AS readiness_probe_tcp,
+	unnest($16 :: text [ ]) AS readiness_probe_http,
+	unnest($17 :: integer [ ]) AS readiness_probe_interval_seconds,
+	unnest($18 :: integer [ ]) AS readiness_probe_timeout_seconds
+RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.display_name, workspace_agent_scripts.id, workspace_agent_scripts.depends_on, workspace_agent_scripts.readiness_probe_command, workspace_agent_scripts.readiness_probe_tcp, workspace_agent_scripts.readiness_probe_http, workspace_agent_scripts.readiness_probe_interval_seconds, workspace_agent_scripts.readiness_probe_timeout_seconds
 `
 
 type InsertWorkspaceAgentScriptsParams struct {
-	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
-	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
-	LogSourceID      []uuid.UUID `db:"log_source_id" json:"log_source_id"`
-	LogPath          []string    `db:"log_path" json:"log_path"`
-	Script           []string    `db:"script" json:"script"`
-	Cron             []string    `db:"cron" json:"cron"`
-	StartBlocksLogin []bool      `db:"start_blocks_login" json:"start_blocks_login"`
-	RunOnStart       []bool      `db:"run_on_start" json:"run_on_start"`
-	RunOnStop        []bool      `db:"run_on_stop" json:"run_on_stop"`
-	TimeoutSeconds   []int32     `db:"timeout_seconds" json:"timeout_seconds"`
-	DisplayName      []string    `db:"display_name" json:"display_name"`
-	ID               []uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID              uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt                     time.Time   `db:"created_at" json:"created_at"`
+	LogSourceID                   []uuid.UUID `db:"log_source_id" json:"log_source_id"`
+	LogPath                       []string    `db:"log_path" json:"log_path"`
+	Script                        []string    `db:"script" json:"script"`
+	Cron                          []string    `db:"cron" json:"cron"`
+	StartBlocksLogin              []bool      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart                    []bool      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop                     []bool      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds                []int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	DisplayName                   []string    `db:"display_name" json:"display_name"`
+	ID                            []uuid.UUID `db:"id" json:"id"`
+	DependsOn                     []string    `db:"depends_on" json:"depends_on"`
+	ReadinessProbeCommand         []string    `db:"readiness_probe_command" json:"readiness_probe_command"`
+	ReadinessProbeTCP             []string    `db:"readiness_probe_tcp" json:"readiness_probe_tcp"`
+	ReadinessProbeHTTP            []string    `db:"readiness_probe_http" json:"readiness_probe_http"`
+	ReadinessProbeIntervalSeconds []int32     `db:"readiness_probe_interval_seconds" json:"readiness_probe_interval_seconds"`
+	ReadinessProbeTimeoutSeconds  []int32     `db:"readiness_probe_timeout_seconds" json:"readiness_probe_timeout_seconds"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error) {
@@ -19372,6 +20627,12 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 		pq.Array(arg.TimeoutSeconds),
 		pq.Array(arg.DisplayName),
 		pq.Array(arg.ID),
+		pq.Array(arg.DependsOn),
+		pq.Array(arg.ReadinessProbeCommand),
+		pq.Array(arg.ReadinessProbeTCP),
+		pq.Array(arg.ReadinessProbeHTTP),
+		pq.Array(arg.ReadinessProbeIntervalSeconds),
+		pq.Array(arg.ReadinessProbeTimeoutSeconds),
 	)
 	if err != nil {
 		return nil, err
@@ -19393,6 +20654,12 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 			&i.TimeoutSeconds,
 			&i.DisplayName,
 			&i.ID,
+			pq.Array(&i.DependsOn),
+			&i.ReadinessProbeCommand,
+			&i.ReadinessProbeTCP,
+			&i.ReadinessProbeHTTP,
+			&i.ReadinessProbeIntervalSeconds,
+			&i.ReadinessProbeTimeoutSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -19406,3 +20673,483 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const deleteWorkspaceSessionRecordingChunks = `-- name: DeleteWorkspaceSessionRecordingChunks :exec
+DELETE FROM
+	workspace_session_recording_chunks
+USING
+	workspace_session_recordings
+WHERE
+	workspace_session_recording_chunks.recording_id = workspace_session_recordings.id
+	AND workspace_session_recordings.id = $1
+	AND workspace_session_recordings.agent_id = $2
+`
+
+type DeleteWorkspaceSessionRecordingChunksParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+}
+
+func (q *sqlQuerier) DeleteWorkspaceSessionRecordingChunks(ctx context.Context, arg DeleteWorkspaceSessionRecordingChunksParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceSessionRecordingChunks, arg.RecordingID, arg.AgentID)
+	return err
+}
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	workspace_session_recordings.id, workspace_session_recordings.workspace_id, workspace_session_recordings.agent_id, workspace_session_recordings.connection_id, workspace_session_recordings.connection_type, workspace_session_recordings.started_at, workspace_session_recordings.ended_at, workspace_session_recordings.size, workspace_session_recordings.created_at,
+	workspaces.organization_id
+FROM
+	workspace_session_recordings
+	INNER JOIN workspaces ON workspaces.id = workspace_session_recordings.workspace_id
+WHERE
+	workspace_session_recordings.id = $1
+`
+
+type GetWorkspaceSessionRecordingByIDRow struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID        uuid.UUID `db:"agent_id" json:"agent_id"`
+	ConnectionID   uuid.UUID `db:"connection_id" json:"connection_id"`
+	ConnectionType string    `db:"connection_type" json:"connection_type"`
+	StartedAt      time.Time `db:"started_at" json:"started_at"`
+	EndedAt        time.Time `db:"ended_at" json:"ended_at"`
+	Size           int64     `db:"size" json:"size"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (GetWorkspaceSessionRecordingByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i GetWorkspaceSessionRecordingByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.ConnectionID,
+		&i.ConnectionType,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+		&i.CreatedAt,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingChunks = `-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	recording_id, offset_bytes, data
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = $1
+	AND offset_bytes >= $2
+ORDER BY
+	offset_bytes ASC
+LIMIT
+	$3 :: int
+`
+
+type GetWorkspaceSessionRecordingChunksParams struct {
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	OffsetBytes int64     `db:"offset_bytes" json:"offset_bytes"`
+	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingChunks(ctx context.Context, arg GetWorkspaceSessionRecordingChunksParams) ([]WorkspaceSessionRecordingChunk, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingChunks, arg.RecordingID, arg.OffsetBytes, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSessionRecordingChunk
+	for rows.Next() {
+		var i WorkspaceSessionRecordingChunk
+		if err := rows.Scan(&i.RecordingID, &i.OffsetBytes, &i.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordings = `-- name: GetWorkspaceSessionRecordings :many
+SELECT
+	workspace_session_recordings.id,
+	workspace_session_recordings.workspace_id,
+	workspace_session_recordings.agent_id,
+	workspace_session_recordings.connection_id,
+	workspace_session_recordings.connection_type,
+	workspace_session_recordings.started_at,
+	workspace_session_recordings.ended_at,
+	workspace_session_recordings.created_at,
+	workspace_session_recordings.size,
+	workspaces.organization_id,
+	workspaces.owner_id AS workspace_owner_id,
+	workspaces.name AS workspace_name,
+	workspace_agents.name AS agent_name
+FROM
+	workspace_session_recordings
+	INNER JOIN workspaces ON workspaces.id = workspace_session_recordings.workspace_id
+	INNER JOIN workspace_agents ON workspace_agents.id = workspace_session_recordings.agent_id
+WHERE
+	CASE
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.workspace_id = $1
+		ELSE true
+	END
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.connection_id = $2
+		ELSE true
+	END
+	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaceSessionRecordings
+	-- @authorize_filter
+ORDER BY
+	workspace_session_recordings.started_at DESC
+OFFSET $3
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
+`
+
+type GetWorkspaceSessionRecordingsParams struct {
+	WorkspaceID  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	ConnectionID uuid.UUID `db:"connection_id" json:"connection_id"`
+	OffsetOpt    int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt     int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetWorkspaceSessionRecordingsRow struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID          uuid.UUID `db:"agent_id" json:"agent_id"`
+	ConnectionID     uuid.UUID `db:"connection_id" json:"connection_id"`
+	ConnectionType   string    `db:"connection_type" json:"connection_type"`
+	StartedAt        time.Time `db:"started_at" json:"started_at"`
+	EndedAt          time.Time `db:"ended_at" json:"ended_at"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	Size             int64     `db:"size" json:"size"`
+	OrganizationID   uuid.UUID `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID uuid.UUID `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceName    string    `db:"workspace_name" json:"workspace_name"`
+	AgentName        string    `db:"agent_name" json:"agent_name"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordings(ctx context.Context, arg GetWorkspaceSessionRecordingsParams) ([]GetWorkspaceSessionRecordingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordings,
+		arg.WorkspaceID,
+		arg.ConnectionID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceSessionRecordingsRow
+	for rows.Next() {
+		var i GetWorkspaceSessionRecordingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.ConnectionID,
+			&i.ConnectionType,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.CreatedAt,
+			&i.Size,
+			&i.OrganizationID,
+			&i.WorkspaceOwnerID,
+			&i.WorkspaceName,
+			&i.AgentName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSessionRecordingChunk = `-- name: InsertWorkspaceSessionRecordingChunk :execrows
+WITH recording AS (
+	UPDATE
+		workspace_session_recordings
+	SET
+		size = size + octet_length($1 :: bytea)
+	WHERE
+		id = $2
+		AND agent_id = $3
+		AND size = $4 :: bigint
+	RETURNING
+		id
+)
+INSERT INTO
+	workspace_session_recording_chunks (recording_id, offset_bytes, data)
+SELECT
+	recording.id,
+	$4 :: bigint,
+	$1 :: bytea
+FROM
+	recording
+`
+
+type InsertWorkspaceSessionRecordingChunkParams struct {
+	Data        []byte    `db:"data" json:"data"`
+	RecordingID uuid.UUID `db:"recording_id" json:"recording_id"`
+	AgentID     uuid.UUID `db:"agent_id" json:"agent_id"`
+	OffsetBytes int64     `db:"offset_bytes" json:"offset_bytes"`
+}
+
+// Chunks must continue where the previous chunk ended, the recording size is
+// only updated and the chunk only inserted if the offset matches.
+func (q *sqlQuerier) InsertWorkspaceSessionRecordingChunk(ctx context.Context, arg InsertWorkspaceSessionRecordingChunkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertWorkspaceSessionRecordingChunk,
+		arg.Data,
+		arg.RecordingID,
+		arg.AgentID,
+		arg.OffsetBytes,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertWorkspaceSessionRecording = `-- name: UpsertWorkspaceSessionRecording :execrows
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		connection_id,
+		connection_type,
+		started_at,
+		ended_at,
+		size,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, 0, $8)
+ON CONFLICT (id) DO UPDATE SET
+	started_at = EXCLUDED.started_at,
+	ended_at = EXCLUDED.ended_at,
+	size = 0
+WHERE
+	workspace_session_recordings.agent_id = EXCLUDED.agent_id
+`
+
+type UpsertWorkspaceSessionRecordingParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID        uuid.UUID `db:"agent_id" json:"agent_id"`
+	ConnectionID   uuid.UUID `db:"connection_id" json:"connection_id"`
+	ConnectionType string    `db:"connection_type" json:"connection_type"`
+	StartedAt      time.Time `db:"started_at" json:"started_at"`
+	EndedAt        time.Time `db:"ended_at" json:"ended_at"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// Uploads of a recording start over with the first chunk, so an existing
+// recording of the same agent is reset. The chunks of the existing recording
+// must be deleted separately.
+func (q *sqlQuerier) UpsertWorkspaceSessionRecording(ctx context.Context, arg UpsertWorkspaceSessionRecordingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertWorkspaceSessionRecording,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.ConnectionID,
+		arg.ConnectionType,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWorkspaceSnapshotByID = `-- name: DeleteWorkspaceSnapshotByID :exec
+DELETE FROM
+	workspace_snapshots
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceSnapshotByID, id)
+	return err
+}
+
+const getWorkspaceSnapshotByID = `-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	id, workspace_id, name, created_at, created_by, build_number, template_version_id, provisioner_state, rich_parameters
+FROM
+	workspace_snapshots
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByID, id)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.BuildNumber,
+		&i.TemplateVersionID,
+		&i.ProvisionerState,
+		&i.RichParameters,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotByWorkspaceIDAndName = `-- name: GetWorkspaceSnapshotByWorkspaceIDAndName :one
+SELECT
+	id, workspace_id, name, created_at, created_by, build_number, template_version_id, provisioner_state, rich_parameters
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+	AND name = $2
+`
+
+type GetWorkspaceSnapshotByWorkspaceIDAndNameParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByWorkspaceIDAndName(ctx context.Context, arg GetWorkspaceSnapshotByWorkspaceIDAndNameParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByWorkspaceIDAndName, arg.WorkspaceID, arg.Name)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.BuildNumber,
+		&i.TemplateVersionID,
+		&i.ProvisionerState,
+		&i.RichParameters,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotsByWorkspaceID = `-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	id, workspace_id, name, created_at, created_by, build_number, template_version_id, provisioner_state, rich_parameters
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSnapshotsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceSnapshot
+	for rows.Next() {
+		var i WorkspaceSnapshot
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.CreatedBy,
+			&i.BuildNumber,
+			&i.TemplateVersionID,
+			&i.ProvisionerState,
+			&i.RichParameters,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSnapshot = `-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (
+		id,
+		workspace_id,
+		name,
+		created_at,
+		created_by,
+		build_number,
+		template_version_id,
+		provisioner_state,
+		rich_parameters
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, workspace_id, name, created_at, created_by, build_number, template_version_id, provisioner_state, rich_parameters
+`
+
+type InsertWorkspaceSnapshotParams struct {
+	ID                uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID       uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	Name              string          `db:"name" json:"name"`
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`
+	CreatedBy         uuid.UUID       `db:"created_by" json:"created_by"`
+	BuildNumber       int32           `db:"build_number" json:"build_number"`
+	TemplateVersionID uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	ProvisionerState  []byte          `db:"provisioner_state" json:"provisioner_state"`
+	RichParameters    json.RawMessage `db:"rich_parameters" json:"rich_parameters"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSnapshot,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.CreatedAt,
+		arg.CreatedBy,
+		arg.BuildNumber,
+		arg.TemplateVersionID,
+		arg.ProvisionerState,
+		arg.RichParameters,
+	)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.BuildNumber,
+		&i.TemplateVersionID,
+		&i.ProvisionerState,
+		&i.RichParameters,
+	)
+	return i, err
+}
@@ -5,6 +5,10 @@
 --
 -- Max deadline is respected, and the deadline will never be bumped past it.
 -- The deadline will never decrease.
+--
+-- If the template requires user activity, the deadline is only bumped when
+-- user_activity is set, i.e. the agent saw the user type into a terminal or
+-- run a command in the foreground.
 -- name: ActivityBumpWorkspace :exec
 WITH latest AS (
 	SELECT
@@ -14,6 +18,7 @@ WITH latest AS (
 		workspace_builds.transition AS build_transition,
 		provisioner_jobs.completed_at::timestamp with time zone AS job_completed_at,
 		templates.activity_bump AS activity_bump,
+		templates.activity_bump_requires_user_activity AS activity_bump_requires_user_activity,
 		(
 			CASE
 				-- If the extension would push us over the next_autostart
@@ -71,6 +76,8 @@ WHERE wb.id = l.build_id
 AND l.job_completed_at IS NOT NULL
 -- We only bump if the template has an activity bump duration set.
 AND l.activity_bump > 0
+-- We only bump without user activity if the template doesn't require it.
+AND (NOT l.activity_bump_requires_user_activity OR @user_activity::boolean)
 AND l.build_transition = 'start'
 -- We only bump if the raw interval is positive and non-zero.
 AND l.ttl_interval > '0 seconds'::interval
@@ -44,7 +44,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_permissions,
+		scope_allow_list
 	)
 VALUES
 	(@id,
@@ -53,7 +55,10 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name,
+	 -- Keys created without a custom scope are not limited further.
+	 COALESCE(@scope_permissions::text[], '{}'::text[]),
+	 COALESCE(@scope_allow_list::text[], '{}'::text[])) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -156,3 +156,68 @@ INSERT INTO
     )
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING *;
+
+-- name: GetAuditLogsByIDs :many
+SELECT * FROM audit_logs WHERE id = ANY(@ids :: uuid[]);
+
+-- name: GetAuditLogChainHead :one
+SELECT * FROM audit_log_chain WHERE organization_id = @organization_id ORDER BY sequence DESC LIMIT 1;
+
+-- name: InsertAuditLogChainEntry :one
+INSERT INTO
+	audit_log_chain (organization_id, sequence, audit_log_id, prev_hash, hash)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: GetAuditLogChainEntries :many
+SELECT
+	*
+FROM
+	audit_log_chain
+WHERE
+	organization_id = @organization_id
+	AND sequence > @after_sequence :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	COALESCE(NULLIF(@limit_opt :: int, 0), 100);
+
+-- name: GetAuditLogChains :many
+-- Returns every audit log chain, including the chains of deleted
+-- organizations, since their audit logs are kept.
+SELECT
+	chains.organization_id,
+	COALESCE(organizations.name, '') :: text AS organization_name,
+	COALESCE(organizations.deleted, false) :: boolean AS organization_deleted
+FROM
+	(
+		SELECT organization_id FROM audit_log_chain
+		UNION
+		SELECT organization_id FROM audit_log_checkpoints
+	) AS chains
+LEFT JOIN
+	organizations ON organizations.id = chains.organization_id
+ORDER BY
+	chains.organization_id ASC;
+
+-- name: GetLatestAuditLogCheckpoint :one
+SELECT * FROM audit_log_checkpoints WHERE organization_id = @organization_id ORDER BY sequence DESC LIMIT 1;
+
+-- name: InsertAuditLogCheckpoint :one
+INSERT INTO
+	audit_log_checkpoints (organization_id, sequence, hash, signature, created_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: GetAuditLogCheckpoints :many
+SELECT
+	*
+FROM
+	audit_log_checkpoints
+WHERE
+	organization_id = @organization_id
+	AND sequence > @after_sequence :: bigint
+ORDER BY
+	sequence ASC
+LIMIT
+	COALESCE(NULLIF(@limit_opt :: int, 0), 100);
@@ -17,6 +17,17 @@ WHERE group_id = @group_id
         user_is_system = false
       END;
 
+-- name: GetGroupMembersByGroupIDs :many
+SELECT *
+FROM group_members_expanded
+WHERE group_id = ANY(@group_ids :: uuid[])
+  -- Filter by system type
+  AND CASE
+      WHEN @include_system::bool THEN TRUE
+      ELSE
+        user_is_system = false
+      END;
+
 -- name: GetGroupMembersCountByGroupID :one
 -- Returns the total count of members in a group. Shows the total
 -- count even if the caller does not have read access to ResourceGroupMember.
@@ -1,15 +1,21 @@
 -- name: FetchNewMessageMetadata :one
 -- This is used to build up the notification_message's JSON payload.
-SELECT nt.name                                                    AS notification_name,
-       nt.id                                                      AS notification_template_id,
-       nt.actions                                                 AS actions,
-       nt.method                                                  AS custom_method,
-       u.id                                                       AS user_id,
-       u.email                                                    AS user_email,
-       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text AS user_name,
-       u.username                                                 AS user_username
-FROM notification_templates nt,
-     users u
+SELECT nt.name                                                                       AS notification_name,
+       nt.id                                                                         AS notification_template_id,
+       nt.actions                                                                    AS actions,
+       nt.method                                                                     AS custom_method,
+       nt.title_template                                                             AS title_template,
+       np.method                                                                     AS user_method,
+       -- digests are only honored for templates which allow them, and never for disabled notifications
+       COALESCE(nt.digestible AND np.digest AND NOT np.disabled, false)::bool        AS digest,
+       u.id                                                                          AS user_id,
+       u.email                                                                       AS user_email,
+       COALESCE(NULLIF(u.name, ''), NULLIF(u.username, ''))::text                    AS user_name,
+       u.username                                                                    AS user_username
+FROM notification_templates nt
+         CROSS JOIN users u
+         LEFT JOIN notification_preferences np
+                   ON (np.notification_template_id = nt.id AND np.user_id = u.id)
 WHERE nt.id = @notification_template_id
   AND u.id = @user_id;
 
@@ -149,13 +155,22 @@ FROM notification_preferences
 WHERE user_id = @user_id::uuid;
 
 -- name: UpdateUserNotificationPreferences :execrows
+-- An empty or omitted method defers to the template-level or deployment-level method.
 INSERT
-INTO notification_preferences (user_id, notification_template_id, disabled)
-SELECT @user_id::uuid, new_values.notification_template_id, new_values.disabled
+INTO notification_preferences (user_id, notification_template_id, disabled, method, digest)
+SELECT @user_id::uuid,
+       new_values.notification_template_id,
+       new_values.disabled,
+       NULLIF(new_values.method, '')::notification_method,
+       COALESCE(new_values.digest, false)
 FROM (SELECT UNNEST(@notification_template_ids::uuid[]) AS notification_template_id,
-             UNNEST(@disableds::bool[])                 AS disabled) AS new_values
+             UNNEST(@disableds::bool[])                 AS disabled,
+             UNNEST(@methods::text[])                   AS method,
+             UNNEST(@digests::bool[])                   AS digest) AS new_values
 ON CONFLICT (user_id, notification_template_id) DO UPDATE
     SET disabled   = EXCLUDED.disabled,
+        method     = EXCLUDED.method,
+        digest     = EXCLUDED.digest,
         updated_at = CURRENT_TIMESTAMP;
 
 -- name: UpdateNotificationTemplateMethodByID :one
@@ -190,6 +205,29 @@ INSERT INTO notification_report_generator_logs (notification_template_id, last_g
 ON CONFLICT (notification_template_id) DO UPDATE set last_generated_at = EXCLUDED.last_generated_at
 WHERE notification_report_generator_logs.notification_template_id = EXCLUDED.notification_template_id;
 
+-- name: InsertNotificationDigestEntry :exec
+INSERT INTO notification_digest_entries (id, user_id, notification_template_id, title, payload, created_at)
+VALUES (@id, @user_id, @notification_template_id, @title, @payload::jsonb, @created_at);
+
+-- name: GetUserIDsWithDueNotificationDigests :many
+-- Returns the users whose oldest pending digest entry was created at or before the given time,
+-- i.e. those whose digest interval has elapsed.
+SELECT user_id
+FROM notification_digest_entries
+GROUP BY user_id
+HAVING MIN(created_at) <= @created_before::timestamptz
+ORDER BY user_id;
+
+-- name: GetNotificationDigestEntriesByUserID :many
+SELECT *
+FROM notification_digest_entries
+WHERE user_id = @user_id::uuid
+ORDER BY created_at ASC;
+
+-- name: DeleteNotificationDigestEntries :exec
+DELETE FROM notification_digest_entries
+WHERE id = ANY(@ids::uuid[]);
+
 -- name: GetWebpushSubscriptionsByUserID :many
 SELECT *
 FROM webpush_subscriptions
@@ -0,0 +1,83 @@
+-- name: InsertSCIMGroup :one
+INSERT INTO
+	scim_groups (id, external_id, display_name, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: GetSCIMGroupByID :one
+SELECT * FROM scim_groups WHERE id = $1;
+
+-- name: GetSCIMGroups :many
+SELECT
+	*
+FROM
+	scim_groups
+WHERE
+	CASE
+		WHEN @display_name :: text != '' THEN display_name = @display_name
+		ELSE true
+	END
+ORDER BY
+	display_name ASC;
+
+-- name: GetSCIMGroupsByUserIDs :many
+SELECT
+	scim_group_members.user_id,
+	scim_groups.display_name
+FROM
+	scim_groups
+	JOIN scim_group_members ON scim_group_members.scim_group_id = scim_groups.id
+WHERE
+	scim_group_members.user_id = ANY(@user_ids :: uuid[])
+ORDER BY
+	scim_group_members.user_id ASC,
+	scim_groups.display_name ASC;
+
+-- name: UpdateSCIMGroupByID :one
+UPDATE
+	scim_groups
+SET
+	external_id = $2,
+	display_name = $3,
+	updated_at = $4
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteSCIMGroupByID :exec
+DELETE FROM scim_groups WHERE id = $1;
+
+-- name: GetSCIMGroupMembers :many
+SELECT
+	scim_group_members.scim_group_id,
+	users.id AS user_id,
+	users.username
+FROM
+	scim_group_members
+	JOIN users ON users.id = scim_group_members.user_id
+WHERE
+	scim_group_members.scim_group_id = ANY(@scim_group_ids :: uuid[])
+ORDER BY
+	users.username ASC;
+
+-- InsertSCIMGroupMembers adds users to a SCIM group. Users that are already
+-- members, or that do not exist, are skipped.
+-- name: InsertSCIMGroupMembers :exec
+INSERT INTO
+	scim_group_members (scim_group_id, user_id)
+SELECT
+	@scim_group_id,
+	users.id
+FROM
+	users
+WHERE
+	users.id = ANY(@user_ids :: uuid[])
+	AND users.deleted = false
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteSCIMGroupMembers :exec
+DELETE FROM
+	scim_group_members
+WHERE
+	scim_group_id = @scim_group_id
+	AND user_id = ANY(@user_ids :: uuid[]);
@@ -125,7 +125,9 @@ SET
 	allow_user_cancel_workspace_jobs = $7,
 	group_acl = $8,
 	max_port_sharing_level = $9,
-	use_classic_parameter_flow = $10
+	use_classic_parameter_flow = $10,
+	session_recording = $11,
+	activity_bump_requires_user_activity = $12
 WHERE
 	id = $1
 ;
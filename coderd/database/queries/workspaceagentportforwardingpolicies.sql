@@ -0,0 +1,20 @@
+-- name: InsertWorkspaceAgentPortForwardingPolicy :one
+INSERT INTO
+	workspace_agent_port_forwarding_policies (
+		agent_id,
+		allowed_ports,
+		denied_ports,
+		allowed_unix_sockets,
+		denied_unix_sockets,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetWorkspaceAgentPortForwardingPolicy :one
+SELECT
+	*
+FROM
+	workspace_agent_port_forwarding_policies
+WHERE
+	agent_id = $1;
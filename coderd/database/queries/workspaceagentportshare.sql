@@ -6,7 +6,10 @@ FROM
 WHERE
 	workspace_id = $1
 	AND agent_name = $2
-	AND port = $3;
+	AND port = $3
+	-- Expired shares are kept until they are deleted or replaced, but no
+	-- longer grant access.
+	AND (expires_at IS NULL OR expires_at > NOW());
 
 -- name: ListWorkspaceAgentPortShares :many
 SELECT
@@ -14,7 +17,8 @@ SELECT
 FROM
 	workspace_agent_port_share
 WHERE
-	workspace_id = $1;
+	workspace_id = $1
+	AND (expires_at IS NULL OR expires_at > NOW());
 
 -- name: DeleteWorkspaceAgentPortShare :exec
 DELETE FROM
@@ -31,14 +35,16 @@ INSERT INTO
 		agent_name,
 		port,
 		share_level,
-		protocol
+		protocol,
+		expires_at
 	)
 VALUES (
 	$1,
 	$2,
 	$3,
 	$4,
-	$5
+	$5,
+	$6
 )
 ON CONFLICT (
 	workspace_id,
@@ -47,7 +53,8 @@ ON CONFLICT (
 )
 DO UPDATE SET
 	share_level = $4,
-	protocol = $5
+	protocol = $5,
+	expires_at = $6
 RETURNING *;
 
 -- name: ReduceWorkspaceAgentShareLevelToAuthenticatedByTemplate :exec
@@ -76,3 +76,43 @@ SET
 		debounced_until = $5
 WHERE
 		agent_id = $1 AND path = $2;
+
+-- name: FetchResourceMonitorsUpdatedAfter :many
+SELECT
+	*
+FROM
+	workspace_agent_resource_monitors
+WHERE
+	updated_at > $1;
+
+-- name: FetchResourceMonitorsByAgentID :many
+SELECT
+	*
+FROM
+	workspace_agent_resource_monitors
+WHERE
+	agent_id = $1;
+
+-- name: InsertResourceMonitor :one
+INSERT INTO
+	workspace_agent_resource_monitors (
+		agent_id,
+		type,
+		enabled,
+		state,
+		threshold,
+		created_at,
+		updated_at,
+		debounced_until
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateResourceMonitor :exec
+UPDATE workspace_agent_resource_monitors
+SET
+	updated_at = $3,
+	state = $4,
+	debounced_until = $5
+WHERE
+	agent_id = $1 AND type = $2;
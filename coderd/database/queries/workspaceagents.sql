@@ -311,10 +311,11 @@ INSERT INTO
         ended_at,
         exit_code,
         stage,
-        status
+        status,
+        queued_at
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6)
+    ($1, $2, $3, $4, $5, $6, $7)
 RETURNING workspace_agent_script_timings.*;
 
 -- name: GetWorkspaceAgentScriptTimingsByBuildID :many
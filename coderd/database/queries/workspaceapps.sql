@@ -1,3 +1,10 @@
+-- name: DeleteWorkspaceAppsByIDs :exec
+-- The statuses of the apps are deleted with them.
+WITH deleted_statuses AS (
+	DELETE FROM workspace_app_statuses WHERE app_id = ANY(@ids :: uuid [ ])
+)
+DELETE FROM workspace_apps WHERE id = ANY(@ids :: uuid [ ]);
+
 -- name: GetWorkspaceAppsByAgentID :many
 SELECT * FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC;
 
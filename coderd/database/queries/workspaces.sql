@@ -414,6 +414,16 @@ WHERE
 	AND LOWER("name") = LOWER(@name)
 ORDER BY created_at DESC;
 
+-- name: GetWorkspaceIDsByOwnerIDAndTemplateIDs :many
+SELECT
+	id
+FROM
+	workspaces
+WHERE
+	owner_id = @owner_id
+	AND template_id = ANY(@template_ids :: uuid[])
+	AND deleted = false;
+
 -- name: GetWorkspaceUniqueOwnerCountByTemplateIDs :many
 SELECT templates.id AS template_id, COUNT(DISTINCT workspaces.owner_id) AS unique_owners_sum
 FROM templates
@@ -666,14 +676,14 @@ WHERE
 
 		-- A workspace may be eligible for deletion if the following are true:
 		--   * The workspace is dormant.
-		--   * The workspace is scheduled to be deleted.
+		--   * The workspace is scheduled to be deleted, either by the template's
+		--     time_til_dormant_autodelete or by SCIM deprovisioning.
 		--   * If there was a prior attempt to delete the workspace that failed:
 		--      * This attempt was at least 24 hours ago.
 		(
 			workspaces.dormant_at IS NOT NULL AND
 			workspaces.deleting_at IS NOT NULL AND
 			workspaces.deleting_at < @now :: timestamptz AND
-			templates.time_til_dormant_autodelete > 0 AND
 			CASE
 				WHEN (
 					workspace_builds.transition = 'delete'::workspace_transition AND
@@ -708,6 +718,37 @@ WHERE
 		)
 	) AND workspaces.deleted = 'false';
 
+-- name: UpdateWorkspaceOwnerByID :one
+UPDATE
+	workspaces
+SET
+	owner_id = @owner_id,
+	updated_at = @updated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	group_acl = @group_acl,
+	user_acl = @user_acl
+WHERE
+	id = @id;
+
+-- UpdateWorkspaceDeletingAt schedules the deletion of a dormant workspace,
+-- regardless of the template's dormancy settings.
+-- name: UpdateWorkspaceDeletingAt :one
+UPDATE
+	workspaces
+SET
+	deleting_at = @deleting_at
+WHERE
+	id = @id
+	AND dormant_at IS NOT NULL
+RETURNING *;
+
 -- name: UpdateWorkspaceDormantDeletingAt :one
 UPDATE
     workspaces
@@ -1,6 +1,6 @@
 -- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, display_name, id, depends_on, readiness_probe_command, readiness_probe_tcp, readiness_probe_http, readiness_probe_interval_seconds, readiness_probe_timeout_seconds)
 SELECT
 	@workspace_agent_id :: uuid AS workspace_agent_id,
 	@created_at :: timestamptz AS created_at,
@@ -13,7 +13,15 @@ SELECT
 	unnest(@run_on_stop :: boolean [ ]) AS run_on_stop,
 	unnest(@timeout_seconds :: integer [ ]) AS timeout_seconds,
 	unnest(@display_name :: text [ ]) AS display_name,
-	unnest(@id :: uuid [ ]) AS id
+	unnest(@id :: uuid [ ]) AS id,
+	-- Each element is a text array literal, e.g. '{"Install tools"}', as
+	-- arrays of arrays with different lengths cannot be passed.
+	unnest(@depends_on :: text [ ]) :: text [ ] AS depends_on,
+	unnest(@readiness_probe_command :: text [ ]) AS readiness_probe_command,
+	unnest(@readiness_probe_tcp :: text [ ]) AS readiness_probe_tcp,
+	unnest(@readiness_probe_http :: text [ ]) AS readiness_probe_http,
+	unnest(@readiness_probe_interval_seconds :: integer [ ]) AS readiness_probe_interval_seconds,
+	unnest(@readiness_probe_timeout_seconds :: integer [ ]) AS readiness_probe_timeout_seconds
 RETURNING workspace_agent_scripts.*;
 
 -- name: GetWorkspaceAgentScriptsByAgentIDs :many
@@ -0,0 +1,120 @@
+-- name: UpsertWorkspaceSessionRecording :execrows
+-- Uploads of a recording start over with the first chunk, so an existing
+-- recording of the same agent is reset. The chunks of the existing recording
+-- must be deleted separately.
+INSERT INTO
+	workspace_session_recordings (
+		id,
+		workspace_id,
+		agent_id,
+		connection_id,
+		connection_type,
+		started_at,
+		ended_at,
+		size,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, 0, $8)
+ON CONFLICT (id) DO UPDATE SET
+	started_at = EXCLUDED.started_at,
+	ended_at = EXCLUDED.ended_at,
+	size = 0
+WHERE
+	workspace_session_recordings.agent_id = EXCLUDED.agent_id;
+
+-- name: DeleteWorkspaceSessionRecordingChunks :exec
+DELETE FROM
+	workspace_session_recording_chunks
+USING
+	workspace_session_recordings
+WHERE
+	workspace_session_recording_chunks.recording_id = workspace_session_recordings.id
+	AND workspace_session_recordings.id = @recording_id
+	AND workspace_session_recordings.agent_id = @agent_id;
+
+-- name: InsertWorkspaceSessionRecordingChunk :execrows
+-- Chunks must continue where the previous chunk ended, the recording size is
+-- only updated and the chunk only inserted if the offset matches.
+WITH recording AS (
+	UPDATE
+		workspace_session_recordings
+	SET
+		size = size + octet_length(@data :: bytea)
+	WHERE
+		id = @recording_id
+		AND agent_id = @agent_id
+		AND size = @offset_bytes :: bigint
+	RETURNING
+		id
+)
+INSERT INTO
+	workspace_session_recording_chunks (recording_id, offset_bytes, data)
+SELECT
+	recording.id,
+	@offset_bytes :: bigint,
+	@data :: bytea
+FROM
+	recording;
+
+-- name: GetWorkspaceSessionRecordingChunks :many
+SELECT
+	*
+FROM
+	workspace_session_recording_chunks
+WHERE
+	recording_id = @recording_id
+	AND offset_bytes >= @offset_bytes
+ORDER BY
+	offset_bytes ASC
+LIMIT
+	@limit_opt :: int;
+
+-- name: GetWorkspaceSessionRecordings :many
+SELECT
+	workspace_session_recordings.id,
+	workspace_session_recordings.workspace_id,
+	workspace_session_recordings.agent_id,
+	workspace_session_recordings.connection_id,
+	workspace_session_recordings.connection_type,
+	workspace_session_recordings.started_at,
+	workspace_session_recordings.ended_at,
+	workspace_session_recordings.created_at,
+	workspace_session_recordings.size,
+	workspaces.organization_id,
+	workspaces.owner_id AS workspace_owner_id,
+	workspaces.name AS workspace_name,
+	workspace_agents.name AS agent_name
+FROM
+	workspace_session_recordings
+	INNER JOIN workspaces ON workspaces.id = workspace_session_recordings.workspace_id
+	INNER JOIN workspace_agents ON workspace_agents.id = workspace_session_recordings.agent_id
+WHERE
+	CASE
+		WHEN @workspace_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.workspace_id = @workspace_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @connection_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_session_recordings.connection_id = @connection_id
+		ELSE true
+	END
+	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaceSessionRecordings
+	-- @authorize_filter
+ORDER BY
+	workspace_session_recordings.started_at DESC
+OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	workspace_session_recordings.*,
+	workspaces.organization_id
+FROM
+	workspace_session_recordings
+	INNER JOIN workspaces ON workspaces.id = workspace_session_recordings.workspace_id
+WHERE
+	workspace_session_recordings.id = $1;
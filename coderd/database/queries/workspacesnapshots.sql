@@ -0,0 +1,48 @@
+-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (
+		id,
+		workspace_id,
+		name,
+		created_at,
+		created_by,
+		build_number,
+		template_version_id,
+		provisioner_state,
+		rich_parameters
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC;
+
+-- name: GetWorkspaceSnapshotByWorkspaceIDAndName :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	workspace_id = $1
+	AND name = $2;
+
+-- name: DeleteWorkspaceSnapshotByID :exec
+DELETE FROM
+	workspace_snapshots
+WHERE
+	id = $1;
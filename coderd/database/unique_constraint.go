@@ -8,6 +8,9 @@ type UniqueConstraint string
 const (
 	UniqueAgentStatsPkey                                      UniqueConstraint = "agent_stats_pkey"                                                // ALTER TABLE ONLY workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
 	UniqueAPIKeysPkey                                         UniqueConstraint = "api_keys_pkey"                                                   // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
+	UniqueAuditLogChainAuditLogIDKey                          UniqueConstraint = "audit_log_chain_audit_log_id_key"                                // ALTER TABLE ONLY audit_log_chain ADD CONSTRAINT audit_log_chain_audit_log_id_key UNIQUE (audit_log_id);
+	UniqueAuditLogChainPkey                                   UniqueConstraint = "audit_log_chain_pkey"                                            // ALTER TABLE ONLY audit_log_chain ADD CONSTRAINT audit_log_chain_pkey PRIMARY KEY (organization_id, sequence);
+	UniqueAuditLogCheckpointsPkey                             UniqueConstraint = "audit_log_checkpoints_pkey"                                      // ALTER TABLE ONLY audit_log_checkpoints ADD CONSTRAINT audit_log_checkpoints_pkey PRIMARY KEY (organization_id, sequence);
 	UniqueAuditLogsPkey                                       UniqueConstraint = "audit_logs_pkey"                                                 // ALTER TABLE ONLY audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 	UniqueChatMessagesPkey                                    UniqueConstraint = "chat_messages_pkey"                                              // ALTER TABLE ONLY chat_messages ADD CONSTRAINT chat_messages_pkey PRIMARY KEY (id);
 	UniqueChatsPkey                                           UniqueConstraint = "chats_pkey"                                                      // ALTER TABLE ONLY chats ADD CONSTRAINT chats_pkey PRIMARY KEY (id);
@@ -27,6 +30,7 @@ const (
 	UniqueJfrogXrayScansPkey                                  UniqueConstraint = "jfrog_xray_scans_pkey"                                           // ALTER TABLE ONLY jfrog_xray_scans ADD CONSTRAINT jfrog_xray_scans_pkey PRIMARY KEY (agent_id, workspace_id);
 	UniqueLicensesJWTKey                                      UniqueConstraint = "licenses_jwt_key"                                                // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 	UniqueLicensesPkey                                        UniqueConstraint = "licenses_pkey"                                                   // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
+	UniqueNotificationDigestEntriesPkey                       UniqueConstraint = "notification_digest_entries_pkey"                                // ALTER TABLE ONLY notification_digest_entries ADD CONSTRAINT notification_digest_entries_pkey PRIMARY KEY (id);
 	UniqueNotificationMessagesPkey                            UniqueConstraint = "notification_messages_pkey"                                      // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
 	UniqueNotificationPreferencesPkey                         UniqueConstraint = "notification_preferences_pkey"                                   // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, notification_template_id);
 	UniqueNotificationReportGeneratorLogsPkey                 UniqueConstraint = "notification_report_generator_logs_pkey"                         // ALTER TABLE ONLY notification_report_generator_logs ADD CONSTRAINT notification_report_generator_logs_pkey PRIMARY KEY (notification_template_id);
@@ -50,6 +54,9 @@ const (
 	UniqueProvisionerJobLogsPkey                              UniqueConstraint = "provisioner_job_logs_pkey"                                       // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 	UniqueProvisionerJobsPkey                                 UniqueConstraint = "provisioner_jobs_pkey"                                           // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 	UniqueProvisionerKeysPkey                                 UniqueConstraint = "provisioner_keys_pkey"                                           // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+	UniqueScimGroupMembersPkey                                UniqueConstraint = "scim_group_members_pkey"                                         // ALTER TABLE ONLY scim_group_members ADD CONSTRAINT scim_group_members_pkey PRIMARY KEY (scim_group_id, user_id);
+	UniqueScimGroupsDisplayNameKey                            UniqueConstraint = "scim_groups_display_name_key"                                    // ALTER TABLE ONLY scim_groups ADD CONSTRAINT scim_groups_display_name_key UNIQUE (display_name);
+	UniqueScimGroupsPkey                                      UniqueConstraint = "scim_groups_pkey"                                                // ALTER TABLE ONLY scim_groups ADD CONSTRAINT scim_groups_pkey PRIMARY KEY (id);
 	UniqueSiteConfigsKeyKey                                   UniqueConstraint = "site_configs_key_key"                                            // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 	UniqueTailnetAgentsPkey                                   UniqueConstraint = "tailnet_agents_pkey"                                             // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
 	UniqueTailnetClientSubscriptionsPkey                      UniqueConstraint = "tailnet_client_subscriptions_pkey"                               // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_pkey PRIMARY KEY (client_id, coordinator_id, agent_id);
@@ -78,8 +85,10 @@ const (
 	UniqueWorkspaceAgentLogSourcesPkey                        UniqueConstraint = "workspace_agent_log_sources_pkey"                                // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 	UniqueWorkspaceAgentMemoryResourceMonitorsPkey            UniqueConstraint = "workspace_agent_memory_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_memory_resource_monitors ADD CONSTRAINT workspace_agent_memory_resource_monitors_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentMetadataPkey                          UniqueConstraint = "workspace_agent_metadata_pkey"                                   // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
+	UniqueWorkspaceAgentPortForwardingPoliciesPkey            UniqueConstraint = "workspace_agent_port_forwarding_policies_pkey"                   // ALTER TABLE ONLY workspace_agent_port_forwarding_policies ADD CONSTRAINT workspace_agent_port_forwarding_policies_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentPortSharePkey                         UniqueConstraint = "workspace_agent_port_share_pkey"                                 // ALTER TABLE ONLY workspace_agent_port_share ADD CONSTRAINT workspace_agent_port_share_pkey PRIMARY KEY (workspace_id, agent_name, port);
 	UniqueWorkspaceAgentScriptTimingsScriptIDStartedAtKey     UniqueConstraint = "workspace_agent_script_timings_script_id_started_at_key"         // ALTER TABLE ONLY workspace_agent_script_timings ADD CONSTRAINT workspace_agent_script_timings_script_id_started_at_key UNIQUE (script_id, started_at);
+	UniqueWorkspaceAgentResourceMonitorsPkey                  UniqueConstraint = "workspace_agent_resource_monitors_pkey"                          // ALTER TABLE ONLY workspace_agent_resource_monitors ADD CONSTRAINT workspace_agent_resource_monitors_pkey PRIMARY KEY (agent_id, type);
 	UniqueWorkspaceAgentScriptsIDKey                          UniqueConstraint = "workspace_agent_scripts_id_key"                                  // ALTER TABLE ONLY workspace_agent_scripts ADD CONSTRAINT workspace_agent_scripts_id_key UNIQUE (id);
 	UniqueWorkspaceAgentStartupLogsPkey                       UniqueConstraint = "workspace_agent_startup_logs_pkey"                               // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentVolumeResourceMonitorsPkey            UniqueConstraint = "workspace_agent_volume_resource_monitors_pkey"                   // ALTER TABLE ONLY workspace_agent_volume_resource_monitors ADD CONSTRAINT workspace_agent_volume_resource_monitors_pkey PRIMARY KEY (agent_id, path);
@@ -100,6 +109,10 @@ const (
 	UniqueWorkspaceResourceMetadataName                       UniqueConstraint = "workspace_resource_metadata_name"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 	UniqueWorkspaceResourceMetadataPkey                       UniqueConstraint = "workspace_resource_metadata_pkey"                                // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
 	UniqueWorkspaceResourcesPkey                              UniqueConstraint = "workspace_resources_pkey"                                        // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSessionRecordingChunksPkey                 UniqueConstraint = "workspace_session_recording_chunks_pkey"                         // ALTER TABLE ONLY workspace_session_recording_chunks ADD CONSTRAINT workspace_session_recording_chunks_pkey PRIMARY KEY (recording_id, offset_bytes);
+	UniqueWorkspaceSessionRecordingsPkey                      UniqueConstraint = "workspace_session_recordings_pkey"                               // ALTER TABLE ONLY workspace_session_recordings ADD CONSTRAINT workspace_session_recordings_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSnapshotsPkey                              UniqueConstraint = "workspace_snapshots_pkey"                                        // ALTER TABLE ONLY workspace_snapshots ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSnapshotsWorkspaceIDNameKey                UniqueConstraint = "workspace_snapshots_workspace_id_name_key"                       // ALTER TABLE ONLY workspace_snapshots ADD CONSTRAINT workspace_snapshots_workspace_id_name_key UNIQUE (workspace_id, name);
 	UniqueWorkspacesPkey                                      UniqueConstraint = "workspaces_pkey"                                                 // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 	UniqueIndexAPIKeyName                                     UniqueConstraint = "idx_api_key_name"                                                // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
 	UniqueIndexCustomRolesNameLower                           UniqueConstraint = "idx_custom_roles_name_lower"                                     // CREATE UNIQUE INDEX idx_custom_roles_name_lower ON custom_roles USING btree (lower(name));
@@ -407,7 +407,15 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 	// If the key is valid, we also fetch the user roles and status.
 	// The roles are used for RBAC authorize checks, and the status
 	// is to block 'suspended' users from accessing the platform.
-	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, rbac.ScopeName(key.Scope))
+	scope, err := APIKeyScope(ctx, cfg.DB, *key)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding API key scope. %s", err.Error()),
+		})
+	}
+
+	actor, userStatus, err := UserRBACSubject(ctx, cfg.DB, key.UserID, scope)
 	if err != nil {
 		return write(http.StatusUnauthorized, codersdk.Response{
 			Message: internalErrorMessage,
@@ -444,6 +452,62 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 	return key, &actor, true
 }
 
+// APIKeyScope returns the scope an API key is limited to. Keys created with
+// scope permissions or an allow list get a custom scope, all other keys use
+// the builtin scope they were created with.
+func APIKeyScope(ctx context.Context, db database.Store, key database.APIKey) (rbac.ExpandableScope, error) {
+	if len(key.ScopePermissions) == 0 && len(key.ScopeAllowList) == 0 {
+		return rbac.ScopeName(key.Scope), nil
+	}
+
+	perms, err := rbac.ParseScopePermissions(key.ScopePermissions...)
+	if err != nil {
+		return nil, xerrors.Errorf("parse scope permissions: %w", err)
+	}
+	allowList, err := rbac.ParseScopeAllowList(key.ScopeAllowList...)
+	if err != nil {
+		return nil, xerrors.Errorf("parse scope allow list: %w", err)
+	}
+
+	var templateIDs []uuid.UUID
+	for _, element := range allowList {
+		if element.ResourceType != rbac.ResourceTemplate.Type {
+			continue
+		}
+		templateID, err := uuid.Parse(element.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("parse template id %q: %w", element.ID, err)
+		}
+		templateIDs = append(templateIDs, templateID)
+	}
+
+	// Allowing a template also allows the workspaces the key owner created
+	// from it. They are looked up on every request so that new workspaces are
+	// included.
+	if len(templateIDs) > 0 {
+		//nolint:gocritic // The user's roles still apply to the workspaces.
+		workspaceIDs, err := db.GetWorkspaceIDsByOwnerIDAndTemplateIDs(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceIDsByOwnerIDAndTemplateIDsParams{
+			OwnerID:     key.UserID,
+			TemplateIDs: templateIDs,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get template workspaces: %w", err)
+		}
+		for _, workspaceID := range workspaceIDs {
+			allowList = append(allowList, rbac.ScopeAllowListElement{
+				ResourceType: rbac.ResourceWorkspace.Type,
+				ID:           workspaceID.String(),
+			})
+		}
+	}
+
+	scope, err := rbac.CustomScope(rbac.ScopeName(key.Scope), perms, allowList)
+	if err != nil {
+		return nil, xerrors.Errorf("custom scope: %w", err)
+	}
+	return scope, nil
+}
+
 // UserRBACSubject fetches a user's rbac.Subject from the database. It pulls all roles from both
 // site and organization scopes. It also pulls the groups, and the user's status.
 func UserRBACSubject(ctx context.Context, db database.Store, userID uuid.UUID, scope rbac.ExpandableScope) (rbac.Subject, database.UserStatus, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
@@ -23,6 +24,14 @@ type GroupParams struct {
 	// SyncEntitled if false will skip syncing the user's groups
 	SyncEntitled bool
 	MergedClaims jwt.MapClaims
+	// GroupNames if non-nil is used as the list of IDP groups the user is a
+	// member of, instead of reading them from MergedClaims. This is used when
+	// groups are pushed by the IDP, e.g. via SCIM, rather than sent as claims.
+	GroupNames []string
+	// RemovedGroupNames are pushed IDP groups that were just deleted or
+	// renamed. The Coder groups they mapped onto are still treated as managed
+	// by the IDP, so that their former members are removed from them.
+	RemovedGroupNames []string
 }
 
 func (AGPLIDPSync) GroupSyncEntitled() bool {
@@ -103,6 +112,21 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 			userOrgs[g.Group.OrganizationID] = append(userOrgs[g.Group.OrganizationID], g)
 		}
 
+		// Coder groups that pushed groups map onto are managed by the IDP
+		// pushing them alone. Claim sync leaves them alone, and sync from
+		// pushed groups leaves every other group alone, so that the two do
+		// not overwrite each other.
+		scimGroups, err := tx.GetSCIMGroups(ctx, "")
+		if err != nil {
+			return xerrors.Errorf("get scim groups: %w", err)
+		}
+		pushedGroupNames := db2sdk.List(scimGroups, func(g database.SCIMGroup) string {
+			return g.DisplayName
+		})
+		pushedGroupNames = append(pushedGroupNames, params.GroupNames...)
+		pushedGroupNames = append(pushedGroupNames, params.RemovedGroupNames...)
+		pushed := params.GroupNames != nil
+
 		// For each org, we need to fetch the sync settings
 		// This loop also handles any legacy settings for the default
 		// organization.
@@ -131,7 +155,13 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 		groupIDsToRemove := make([]uuid.UUID, 0)
 		// For each org, determine which groups the user should land in
 		for orgID, settings := range orgSettings {
-			if settings.Field == "" {
+			if params.GroupNames != nil {
+				if !settings.Configured() {
+					// No group mapping is configured for this org, so the
+					// pushed groups do not apply to it.
+					continue
+				}
+			} else if settings.Field == "" {
 				// No group sync enabled for this org, so do nothing.
 				// The user can remain in their groups for this org.
 				continue
@@ -139,7 +169,7 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 
 			// expectedGroups is the set of groups the IDP expects the
 			// user to be a member of.
-			expectedGroups, err := settings.ParseClaims(orgID, params.MergedClaims)
+			expectedGroups, err := settings.expectedGroups(orgID, params)
 			if err != nil {
 				s.Logger.Debug(ctx, "failed to parse claims for groups",
 					slog.F("organization_field", s.GroupField),
@@ -174,6 +204,15 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 				return a.Equal(b)
 			})
 
+			managed, err := settings.managedGroupIDs(ctx, tx, orgID, pushedGroupNames)
+			if err != nil {
+				return xerrors.Errorf("get groups managed by pushed groups: %w", err)
+			}
+			inScope := func(id uuid.UUID) bool {
+				_, ok := managed[id]
+				return ok == pushed
+			}
+
 			for _, r := range remove {
 				if r.GroupID == nil {
 					// This should never happen. All group removals come from the
@@ -185,7 +224,9 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 					}
 					return xerrors.Errorf("removal group has nil ID, which should never happen: %s", detail)
 				}
-				groupIDsToRemove = append(groupIDsToRemove, *r.GroupID)
+				if inScope(*r.GroupID) {
+					groupIDsToRemove = append(groupIDsToRemove, *r.GroupID)
+				}
 			}
 
 			// HandleMissingGroups will add the new groups to the org if
@@ -201,7 +242,9 @@ func (s AGPLIDPSync) SyncGroups(ctx context.Context, db database.Store, user dat
 				return xerrors.Errorf("handle missing groups: %w", err)
 			}
 
-			groupIDsToAdd = append(groupIDsToAdd, assignGroups...)
+			groupIDsToAdd = append(groupIDsToAdd, slices.DeleteFunc(assignGroups, func(id uuid.UUID) bool {
+				return !inScope(id)
+			})...)
 		}
 
 		// ApplyGroupDifference will take the total adds and removes, and apply
@@ -324,8 +367,34 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 		return nil, xerrors.Errorf("parse groups field, unexpected type %T: %w", groupsRaw, err)
 	}
 
+	return s.ExpectedGroups(orgID, parsedGroups), nil
+}
+
+// expectedGroups returns the groups the user is expected to be a member of,
+// using the group names pushed by the IDP if set, or the claims otherwise.
+func (s GroupSyncSettings) expectedGroups(orgID uuid.UUID, params GroupParams) ([]ExpectedGroup, error) {
+	if params.GroupNames != nil {
+		return s.ExpectedGroups(orgID, params.GroupNames), nil
+	}
+	return s.ParseClaims(orgID, params.MergedClaims)
+}
+
+// Configured returns true if the settings map IDP groups to Coder groups in
+// any way. Unlike claim based sync, groups pushed by the IDP do not need a
+// claim field to be set.
+func (s GroupSyncSettings) Configured() bool {
+	return s.Field != "" ||
+		len(s.Mapping) > 0 ||
+		len(s.LegacyNameMapping) > 0 ||
+		s.RegexFilter != nil ||
+		s.AutoCreateMissing
+}
+
+// ExpectedGroups applies the group mapping settings to the list of IDP group
+// names, returning the groups the user is expected to be a member of.
+func (s GroupSyncSettings) ExpectedGroups(orgID uuid.UUID, idpGroups []string) []ExpectedGroup {
 	groups := make([]ExpectedGroup, 0)
-	for _, group := range parsedGroups {
+	for _, group := range idpGroups {
 		group := group
 
 		// Legacy group mappings happen before the regex filter.
@@ -353,7 +422,39 @@ func (s GroupSyncSettings) ParseClaims(orgID uuid.UUID, mergedClaims jwt.MapClai
 		groups = append(groups, ExpectedGroup{OrganizationID: orgID, GroupName: &group})
 	}
 
-	return groups, nil
+	return groups
+}
+
+// managedGroupIDs returns the IDs of the Coder groups in an organization that
+// the given IDP groups map onto.
+func (s GroupSyncSettings) managedGroupIDs(ctx context.Context, tx database.Store, orgID uuid.UUID, idpGroups []string) (map[uuid.UUID]struct{}, error) {
+	managed := make(map[uuid.UUID]struct{})
+	if len(idpGroups) == 0 {
+		return managed, nil
+	}
+
+	var names []string
+	for _, expected := range s.ExpectedGroups(orgID, idpGroups) {
+		switch {
+		case expected.GroupID != nil:
+			managed[*expected.GroupID] = struct{}{}
+		case expected.GroupName != nil:
+			names = append(names, *expected.GroupName)
+		}
+	}
+	if len(names) > 0 {
+		groups, err := tx.GetGroups(ctx, database.GetGroupsParams{
+			OrganizationID: orgID,
+			GroupNames:     names,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("get groups by names: %w", err)
+		}
+		for _, g := range groups {
+			managed[g.Group.ID] = struct{}{}
+		}
+	}
+	return managed, nil
 }
 
 // HandleMissingGroups ensures all ExpectedGroups convert to uuids.
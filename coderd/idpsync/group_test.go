@@ -19,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/runtimeconfig"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -432,6 +433,178 @@ func TestSyncDisabled(t *testing.T) {
 	def.Assert(t, orgID, db, user)
 }
 
+// TestSyncGroupNames tests syncing groups pushed by the IDP, rather than
+// read from claims.
+func TestSyncGroupNames(t *testing.T) {
+	t.Parallel()
+
+	if dbtestutil.WillUsePostgres() {
+		t.Skip("Skipping test because it populates a lot of db entries, which is slow on postgres.")
+	}
+
+	db, _ := dbtestutil.NewDB(t)
+	manager := runtimeconfig.NewManager()
+	s := idpsync.NewAGPLSync(slogtest.Make(t, &slogtest.Options{}),
+		manager,
+		idpsync.DeploymentSyncSettings{},
+	)
+
+	ids := coderdtest.NewDeterministicUUIDGenerator()
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	user := dbgen.User(t, db, database.User{})
+
+	// A mapping without a claim field still applies to pushed groups.
+	mapped := orgSetupDefinition{
+		Name: "Mapped",
+		Groups: map[uuid.UUID]bool{
+			ids.ID("foo"): false,
+			ids.ID("bar"): true,
+			ids.ID("baz"): false,
+		},
+		GroupSettings: &codersdk.GroupSyncSettings{
+			Mapping: map[string][]uuid.UUID{
+				"foo": {ids.ID("foo")},
+				"bar": {ids.ID("bar")},
+			},
+		},
+		assertGroups: &orgGroupAssert{
+			ExpectedGroups: []uuid.UUID{
+				ids.ID("foo"),
+			},
+		},
+	}
+	// Orgs without any group sync settings are left alone.
+	unconfigured := orgSetupDefinition{
+		Name: "Unconfigured",
+		Groups: map[uuid.UUID]bool{
+			ids.ID("qux"): true,
+		},
+		assertGroups: &orgGroupAssert{
+			ExpectedGroups: []uuid.UUID{
+				ids.ID("qux"),
+			},
+		},
+	}
+
+	mappedOrgID := uuid.New()
+	unconfiguredOrgID := uuid.New()
+	SetupOrganization(t, s, db, user, mappedOrgID, mapped)
+	SetupOrganization(t, s, db, user, unconfiguredOrgID, unconfigured)
+	insertSCIMGroups(t, db, "foo", "bar")
+
+	err := s.SyncGroups(ctx, db, user, idpsync.GroupParams{
+		SyncEntitled: true,
+		// Claims are ignored when group names are given.
+		MergedClaims: jwt.MapClaims{
+			"groups": []string{"bar"},
+		},
+		GroupNames: []string{"foo"},
+	})
+	require.NoError(t, err)
+
+	mapped.Assert(t, mappedOrgID, db, user)
+	unconfigured.Assert(t, unconfiguredOrgID, db, user)
+
+	// An empty list removes the user from all mapped groups.
+	err = s.SyncGroups(ctx, db, user, idpsync.GroupParams{
+		SyncEntitled: true,
+		GroupNames:   []string{},
+	})
+	require.NoError(t, err)
+
+	mapped.assertGroups.ExpectedGroups = []uuid.UUID{}
+	mapped.Assert(t, mappedOrgID, db, user)
+	unconfigured.Assert(t, unconfiguredOrgID, db, user)
+}
+
+// TestSyncGroupsSCIMAndClaims checks that groups pushed through SCIM and
+// groups from claims do not overwrite each other.
+func TestSyncGroupsSCIMAndClaims(t *testing.T) {
+	t.Parallel()
+
+	if dbtestutil.WillUsePostgres() {
+		t.Skip("Skipping test because it populates a lot of db entries, which is slow on postgres.")
+	}
+
+	db, _ := dbtestutil.NewDB(t)
+	manager := runtimeconfig.NewManager()
+	s := idpsync.NewAGPLSync(slogtest.Make(t, &slogtest.Options{}),
+		manager,
+		idpsync.DeploymentSyncSettings{},
+	)
+
+	ids := coderdtest.NewDeterministicUUIDGenerator()
+	ctx := testutil.Context(t, testutil.WaitSuperLong)
+	user := dbgen.User(t, db, database.User{})
+
+	org := orgSetupDefinition{
+		Name: "Both",
+		Groups: map[uuid.UUID]bool{
+			ids.ID("scim"):  false,
+			ids.ID("oidc"):  false,
+			ids.ID("other"): true,
+		},
+		GroupSettings: &codersdk.GroupSyncSettings{
+			Field: "groups",
+			Mapping: map[string][]uuid.UUID{
+				"scim-group": {ids.ID("scim")},
+				"oidc-group": {ids.ID("oidc")},
+			},
+		},
+		assertGroups: &orgGroupAssert{},
+	}
+	orgID := uuid.New()
+	SetupOrganization(t, s, db, user, orgID, org)
+	insertSCIMGroups(t, db, "scim-group")
+
+	syncSCIM := func(names ...string) {
+		err := s.SyncGroups(ctx, db, user, idpsync.GroupParams{
+			SyncEntitled: true,
+			GroupNames:   append([]string{}, names...),
+		})
+		require.NoError(t, err)
+	}
+	syncClaims := func(names ...string) {
+		err := s.SyncGroups(ctx, db, user, idpsync.GroupParams{
+			SyncEntitled: true,
+			MergedClaims: jwt.MapClaims{"groups": names},
+		})
+		require.NoError(t, err)
+	}
+
+	// SCIM does not remove the user from groups it does not manage.
+	syncSCIM("scim-group")
+	org.assertGroups.ExpectedGroups = []uuid.UUID{ids.ID("scim"), ids.ID("other")}
+	org.Assert(t, orgID, db, user)
+
+	// Claims do not remove the user from groups managed by SCIM.
+	syncClaims("oidc-group")
+	org.assertGroups.ExpectedGroups = []uuid.UUID{ids.ID("scim"), ids.ID("oidc")}
+	org.Assert(t, orgID, db, user)
+
+	// Removing the user from the SCIM group keeps the groups from claims.
+	syncSCIM()
+	org.assertGroups.ExpectedGroups = []uuid.UUID{ids.ID("oidc")}
+	org.Assert(t, orgID, db, user)
+
+	// Claims do not add the user to groups managed by SCIM.
+	syncClaims("oidc-group", "scim-group")
+	org.Assert(t, orgID, db, user)
+}
+
+func insertSCIMGroups(t *testing.T, db database.Store, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		_, err := db.InsertSCIMGroup(context.Background(), database.InsertSCIMGroupParams{
+			ID:          uuid.New(),
+			DisplayName: name,
+			CreatedAt:   dbtime.Now(),
+			UpdatedAt:   dbtime.Now(),
+		})
+		require.NoError(t, err)
+	}
+}
+
 // TestApplyGroupDifference is mainly testing the database functions
 func TestApplyGroupDifference(t *testing.T) {
 	t.Parallel()
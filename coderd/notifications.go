@@ -3,7 +3,9 @@ package coderd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/google/uuid"
 
@@ -271,26 +273,150 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Build query params.
-	input := database.UpdateUserNotificationPreferencesParams{
-		UserID:                  user.ID,
-		NotificationTemplateIds: make([]uuid.UUID, 0, len(prefs.TemplateDisabledMap)),
-		Disableds:               make([]bool, 0, len(prefs.TemplateDisabledMap)),
+	// Preferences are upserted as a whole, so start from the user's existing preferences to avoid resetting the
+	// fields which were not included in the request.
+	existing, err := api.Database.GetUserNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		logger.Error(ctx, "failed to retrieve preferences", slog.Error(err))
+
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve user notifications preferences.",
+			Detail:  err.Error(),
+		})
+		return
 	}
-	for tmplID, disabled := range prefs.TemplateDisabledMap {
+	merged := make(map[uuid.UUID]database.NotificationPreference, len(existing))
+	for _, pref := range existing {
+		merged[pref.NotificationTemplateID] = pref
+	}
+
+	// New preferences are seeded from their template, and only some templates may be batched into a digest.
+	dbTemplates, err := api.Database.GetNotificationTemplatesByKind(ctx, database.NotificationTemplateKindSystem)
+	if err != nil {
+		logger.Error(ctx, "failed to retrieve notification templates", slog.Error(err))
+
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to retrieve notification templates.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	templates := make(map[uuid.UUID]database.NotificationTemplate, len(dbTemplates))
+	for _, tmpl := range dbTemplates {
+		templates[tmpl.ID] = tmpl
+	}
+
+	var (
+		changed    []uuid.UUID
+		validation []codersdk.ValidationError
+	)
+	// parseTemplateID returns the preference to modify for the given template, recording it as changed.
+	parseTemplateID := func(field, tmplID string) (database.NotificationPreference, bool) {
 		id, err := uuid.Parse(tmplID)
 		if err != nil {
 			logger.Warn(ctx, "failed to parse notification template UUID", slog.F("input", tmplID), slog.Error(err))
+			validation = append(validation, codersdk.ValidationError{
+				Field:  field,
+				Detail: fmt.Sprintf("Unable to parse notification template UUID %q: %s", tmplID, err),
+			})
+			return database.NotificationPreference{}, false
+		}
+		pref, ok := merged[id]
+		if !ok {
+			// A template that is disabled by default must stay disabled until the user enables it, even if
+			// only its method or digest is being set.
+			tmpl, ok := templates[id]
+			pref = database.NotificationPreference{NotificationTemplateID: id, Disabled: ok && !tmpl.EnabledByDefault}
+			merged[id] = pref
+		}
+		if !slices.Contains(changed, id) {
+			changed = append(changed, id)
+		}
+		return pref, true
+	}
 
+	for tmplID, disabled := range prefs.TemplateDisabledMap {
+		pref, ok := parseTemplateID("template_disabled_map", tmplID)
+		if !ok {
+			continue
+		}
+		pref.Disabled = disabled
+		merged[pref.NotificationTemplateID] = pref
+	}
+	for tmplID, method := range prefs.TemplateMethodMap {
+		pref, ok := parseTemplateID("template_method_map", tmplID)
+		if !ok {
+			continue
+		}
+		nm := database.NotificationMethod(method)
+		if method != "" && !nm.Valid() {
+			validation = append(validation, codersdk.ValidationError{
+				Field:  "template_method_map",
+				Detail: fmt.Sprintf("%q is not a valid method; %q are the available options", method, database.AllNotificationMethodValues()),
+			})
+			continue
+		}
+		if method != "" && !api.notificationMethodEnabled(nm) {
+			validation = append(validation, codersdk.ValidationError{
+				Field:  "template_method_map",
+				Detail: fmt.Sprintf("%q is not configured on this deployment.", method),
+			})
+			continue
+		}
+		pref.Method = database.NullNotificationMethod{NotificationMethod: nm, Valid: method != ""}
+		merged[pref.NotificationTemplateID] = pref
+	}
+	var digestRequested bool
+	for tmplID, digest := range prefs.TemplateDigestMap {
+		pref, ok := parseTemplateID("template_digest_map", tmplID)
+		if !ok {
+			continue
+		}
+		pref.Digest = digest
+		merged[pref.NotificationTemplateID] = pref
+		digestRequested = digestRequested || digest
+	}
+	if len(validation) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid notification preferences.",
+			Validations: validation,
+		})
+		return
+	}
+
+	// Time-sensitive notifications must be delivered immediately.
+	if digestRequested {
+		for _, id := range changed {
+			if merged[id].Digest && !templates[id].Digestible {
+				validation = append(validation, codersdk.ValidationError{
+					Field:  "template_digest_map",
+					Detail: fmt.Sprintf("Notification template %q cannot be delivered in a digest.", id),
+				})
+			}
+		}
+		if len(validation) > 0 {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-				Message: "Unable to parse notification template UUID.",
-				Detail:  err.Error(),
+				Message:     "Invalid notification preferences.",
+				Validations: validation,
 			})
 			return
 		}
+	}
 
+	// Build query params.
+	input := database.UpdateUserNotificationPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: make([]uuid.UUID, 0, len(changed)),
+		Disableds:               make([]bool, 0, len(changed)),
+		Methods:                 make([]string, 0, len(changed)),
+		Digests:                 make([]bool, 0, len(changed)),
+	}
+	for _, id := range changed {
+		pref := merged[id]
 		input.NotificationTemplateIds = append(input.NotificationTemplateIds, id)
-		input.Disableds = append(input.Disableds, disabled)
+		input.Disableds = append(input.Disableds, pref.Disabled)
+		input.Methods = append(input.Methods, string(pref.Method.NotificationMethod))
+		input.Digests = append(input.Digests, pref.Digest)
 	}
 
 	// Update preferences with params.
@@ -323,6 +449,28 @@ func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.R
 	httpapi.Write(ctx, rw, http.StatusOK, out)
 }
 
+// notificationMethodEnabled returns true if the deployment is configured to deliver notifications with the given
+// method.
+func (api *API) notificationMethodEnabled(method database.NotificationMethod) bool {
+	cfg := api.DeploymentValues.Notifications
+	switch method {
+	case database.NotificationMethodSmtp:
+		return cfg.SMTP.Smarthost != ""
+	case database.NotificationMethodWebhook:
+		return cfg.Webhook.Endpoint.String() != ""
+	case database.NotificationMethodSlack:
+		return cfg.Slack.Enabled()
+	case database.NotificationMethodTeams:
+		return cfg.Teams.Enabled()
+	case database.NotificationMethodInbox:
+		return cfg.Inbox.Enabled.Value()
+	case database.NotificationMethodWebpush:
+		return api.Experiments.Enabled(codersdk.ExperimentWebPush)
+	default:
+		return false
+	}
+}
+
 func convertNotificationTemplates(in []database.NotificationTemplate) (out []codersdk.NotificationTemplate) {
 	for _, tmpl := range in {
 		out = append(out, codersdk.NotificationTemplate{
@@ -335,6 +483,7 @@ func convertNotificationTemplates(in []database.NotificationTemplate) (out []cod
 			Method:           string(tmpl.Method.NotificationMethod),
 			Kind:             string(tmpl.Kind),
 			EnabledByDefault: tmpl.EnabledByDefault,
+			Digestible:       tmpl.Digestible,
 		})
 	}
 
@@ -346,6 +495,8 @@ func convertNotificationPreferences(in []database.NotificationPreference) (out [
 		out = append(out, codersdk.NotificationPreference{
 			NotificationTemplateID: pref.NotificationTemplateID,
 			Disabled:               pref.Disabled,
+			Method:                 string(pref.Method.NotificationMethod),
+			Digest:                 pref.Digest,
 			UpdatedAt:              pref.UpdatedAt,
 		})
 	}
@@ -0,0 +1,277 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// Limits imposed by Slack on Block Kit elements, see https://api.slack.com/reference/block-kit/blocks.
+	slackMaxHeaderLength      = 150
+	slackMaxSectionLength     = 3000
+	slackMaxButtonLabelLength = 75
+	slackMaxButtons           = 25
+)
+
+// slackRetryableErrors are the Web API error codes which indicate a temporary failure.
+var slackRetryableErrors = []string{"ratelimited", "internal_error", "fatal_error", "service_unavailable", "request_timeout"}
+
+// SlackHandler dispatches notification messages to Slack.
+//
+// If a bot token is configured, messages are delivered as direct messages to the Slack user whose email address
+// matches the recipient's. Otherwise, messages are posted to the configured incoming webhook.
+type SlackHandler struct {
+	cfg codersdk.NotificationsSlackConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// SlackMessage describes the payload sent to an incoming webhook or to chat.postMessage.
+type SlackMessage struct {
+	Channel string `json:"channel,omitempty"`
+	// Text is used as the fallback for notifications and screen readers.
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is a Block Kit layout block.
+type SlackBlock struct {
+	Type     string     `json:"type"`
+	Text     *SlackText `json:"text,omitempty"`
+	Elements []any      `json:"elements,omitempty"`
+}
+
+// SlackText is a Block Kit text composition object.
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SlackButton is a Block Kit button element which opens a URL.
+type SlackButton struct {
+	Type     string    `json:"type"`
+	Text     SlackText `json:"text"`
+	URL      string    `json:"url"`
+	ActionID string    `json:"action_id"`
+}
+
+// slackAPIResponse is the envelope returned by every Slack Web API method.
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	User  struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+func NewSlackHandler(cfg codersdk.NotificationsSlackConfig, log slog.Logger) *SlackHandler {
+	return &SlackHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (s *SlackHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if !s.cfg.Enabled() {
+		return nil, xerrors.New("slack webhook URL or bot token not defined")
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+
+	msg := SlackMessage{
+		Text:   titlePlaintext,
+		Blocks: slackBlocks(titlePlaintext, markdown.SlackMrkdwnFromMarkdown(bodyMarkdown), payload.Actions),
+	}
+
+	if s.cfg.BotToken.String() != "" {
+		return s.dispatchDirect(payload, msg), nil
+	}
+
+	// Incoming webhooks always post to the same channel, so mention who the message is for.
+	msg.Blocks = append(msg.Blocks, slackRecipientBlock(payload))
+	return s.dispatchWebhook(msg), nil
+}
+
+// slackBlocks renders the notification as a header, a section containing the body and, if the template defines any
+// actions, a row of buttons.
+func slackBlocks(title, body string, actions []types.TemplateAction) []SlackBlock {
+	blocks := []SlackBlock{
+		{Type: "header", Text: &SlackText{Type: "plain_text", Text: truncate(title, slackMaxHeaderLength)}},
+		{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: truncate(body, slackMaxSectionLength)}},
+	}
+
+	var buttons []any
+	for i, action := range actions {
+		if i == slackMaxButtons {
+			break
+		}
+		buttons = append(buttons, SlackButton{
+			Type:     "button",
+			Text:     SlackText{Type: "plain_text", Text: truncate(action.Label, slackMaxButtonLabelLength)},
+			URL:      action.URL,
+			ActionID: fmt.Sprintf("action-%d", i),
+		})
+	}
+	if len(buttons) > 0 {
+		blocks = append(blocks, SlackBlock{Type: "actions", Elements: buttons})
+	}
+	return blocks
+}
+
+func slackRecipientBlock(payload types.MessagePayload) SlackBlock {
+	name := payload.UserUsername
+	if payload.UserName != "" {
+		name = payload.UserName
+	}
+	return SlackBlock{
+		Type:     "context",
+		Elements: []any{SlackText{Type: "mrkdwn", Text: fmt.Sprintf("Sent to %s", name)}},
+	}
+}
+
+func (s *SlackHandler) dispatchWebhook(msg SlackMessage) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		m, err := json.Marshal(msg)
+		if err != nil {
+			return false, xerrors.Errorf("marshal payload: %v", err)
+		}
+
+		// Outer context has a deadline (see CODER_NOTIFICATIONS_DISPATCH_TIMEOUT).
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL.String(), bytes.NewBuffer(m))
+		if err != nil {
+			return false, xerrors.Errorf("create HTTP request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.cl.Do(req)
+		if err != nil {
+			return requestFailed(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 > 2 {
+			respBody := readResponseSnippet(resp.Body)
+			s.log.Warn(ctx, "unsuccessful delivery", slog.F("status_code", resp.StatusCode),
+				slog.F("response", respBody), slog.F("msg_id", msgID))
+			return retryableStatus(resp.StatusCode), xerrors.Errorf("non-2xx response (%d): %s", resp.StatusCode, respBody)
+		}
+
+		return false, nil
+	}
+}
+
+func (s *SlackHandler) dispatchDirect(payload types.MessagePayload, msg SlackMessage) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		lookup, retryable, err := s.call(ctx, http.MethodGet, "users.lookupByEmail", url.Values{"email": {payload.UserEmail}}, nil)
+		switch {
+		case err != nil:
+			return retryable, xerrors.Errorf("lookup user: %w", err)
+		case lookup.OK:
+			msg.Channel = lookup.User.ID
+		case lookup.Error == "users_not_found" && s.cfg.Channel.String() != "":
+			s.log.Debug(ctx, "recipient not found in slack, posting to fallback channel",
+				slog.F("msg_id", msgID), slog.F("channel", s.cfg.Channel.String()))
+			msg.Channel = s.cfg.Channel.String()
+			msg.Blocks = append(msg.Blocks, slackRecipientBlock(payload))
+		default:
+			return slices.Contains(slackRetryableErrors, lookup.Error), xerrors.Errorf("lookup user: %s", lookup.Error)
+		}
+
+		post, retryable, err := s.call(ctx, http.MethodPost, "chat.postMessage", nil, msg)
+		if err != nil {
+			return retryable, xerrors.Errorf("post message: %w", err)
+		}
+		if !post.OK {
+			s.log.Warn(ctx, "unsuccessful delivery", slog.F("error", post.Error), slog.F("msg_id", msgID))
+			return slices.Contains(slackRetryableErrors, post.Error), xerrors.Errorf("post message: %s", post.Error)
+		}
+
+		return false, nil
+	}
+}
+
+// call invokes the given Slack Web API method. Errors reported by Slack in the response envelope are not returned as
+// errors; callers must inspect the OK field.
+func (s *SlackHandler) call(ctx context.Context, httpMethod, apiMethod string, query url.Values, body any) (resp slackAPIResponse, retryable bool, err error) {
+	endpoint := s.cfg.APIURL.Value().JoinPath(apiMethod)
+	endpoint.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		m, err := json.Marshal(body)
+		if err != nil {
+			return resp, false, xerrors.Errorf("marshal payload: %v", err)
+		}
+		reqBody = bytes.NewBuffer(m)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, httpMethod, endpoint.String(), reqBody)
+	if err != nil {
+		return resp, false, xerrors.Errorf("create HTTP request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.BotToken.String())
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	res, err := s.cl.Do(req)
+	if err != nil {
+		retryable, err = requestFailed(err)
+		return resp, retryable, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 > 2 {
+		return resp, retryableStatus(res.StatusCode), xerrors.Errorf("non-2xx response (%d): %s", res.StatusCode, readResponseSnippet(res.Body))
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return resp, true, xerrors.Errorf("decode response: %w", err)
+	}
+	return resp, false, nil
+}
+
+// requestFailed classifies a failed HTTP request; all such failures are considered temporary.
+func requestFailed(err error) (bool, error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true, xerrors.Errorf("request timeout: %w", err)
+	}
+	return true, xerrors.Errorf("request failed: %w", err)
+}
+
+// retryableStatus reports whether a non-2xx response status may succeed on a later attempt.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code/100 == 5
+}
+
+// readResponseSnippet reads the first 512B of a response body, which hopefully contains useful debug info.
+func readResponseSnippet(r io.Reader) string {
+	b, _ := io.ReadAll(io.LimitReader(r, 512))
+	return string(b)
+}
+
+// truncate shortens s to at most n runes, marking the truncation with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,176 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+
+// TeamsHandler dispatches notification messages as Adaptive Cards to a Microsoft Teams workflow or incoming webhook.
+// The recipient is mentioned by their email address so that Teams routes the notification to them.
+type TeamsHandler struct {
+	cfg codersdk.NotificationsTeamsConfig
+	log slog.Logger
+
+	cl *http.Client
+}
+
+// TeamsMessage describes the payload delivered to the configured Teams webhook.
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is the subset of the Adaptive Card schema used to render notifications,
+// see https://adaptivecards.io/explorer/AdaptiveCard.html.
+type AdaptiveCard struct {
+	Schema  string               `json:"$schema"`
+	Type    string               `json:"type"`
+	Version string               `json:"version"`
+	Body    []AdaptiveCardText   `json:"body"`
+	Actions []AdaptiveCardAction `json:"actions,omitempty"`
+	MSTeams AdaptiveCardMSTeams  `json:"msteams"`
+}
+
+type AdaptiveCardText struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Weight string `json:"weight,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type AdaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type AdaptiveCardMSTeams struct {
+	Width    string               `json:"width"`
+	Entities []AdaptiveCardEntity `json:"entities,omitempty"`
+}
+
+type AdaptiveCardEntity struct {
+	Type      string                `json:"type"`
+	Text      string                `json:"text"`
+	Mentioned AdaptiveCardMentioned `json:"mentioned"`
+}
+
+type AdaptiveCardMentioned struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func NewTeamsHandler(cfg codersdk.NotificationsTeamsConfig, log slog.Logger) *TeamsHandler {
+	return &TeamsHandler{cfg: cfg, log: log, cl: &http.Client{}}
+}
+
+func (t *TeamsHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	if !t.cfg.Enabled() {
+		return nil, xerrors.New("teams webhook URL not defined")
+	}
+
+	titlePlaintext, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+
+	return t.dispatch(TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: adaptiveCardContentType,
+			Content:     adaptiveCard(payload, titlePlaintext, bodyMarkdown),
+		}},
+	}), nil
+}
+
+// adaptiveCard renders the notification as a card with a bold title, the body and one button per template action.
+// TextBlocks support a subset of Markdown, so the body is passed through unchanged.
+func adaptiveCard(payload types.MessagePayload, title, body string) AdaptiveCard {
+	card := AdaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []AdaptiveCardText{
+			{Type: "TextBlock", Text: title, Weight: "Bolder", Size: "Medium", Wrap: true},
+			{Type: "TextBlock", Text: body, Wrap: true},
+		},
+		MSTeams: AdaptiveCardMSTeams{Width: "Full"},
+	}
+
+	for _, action := range payload.Actions {
+		card.Actions = append(card.Actions, AdaptiveCardAction{
+			Type:  "Action.OpenUrl",
+			Title: action.Label,
+			URL:   action.URL,
+		})
+	}
+
+	if payload.UserEmail != "" {
+		name := payload.UserUsername
+		if payload.UserName != "" {
+			name = payload.UserName
+		}
+		mention := fmt.Sprintf("<at>%s</at>", name)
+		card.Body = append(card.Body, AdaptiveCardText{Type: "TextBlock", Text: mention, Size: "Small", Wrap: true})
+		card.MSTeams.Entities = []AdaptiveCardEntity{{
+			Type:      "mention",
+			Text:      mention,
+			Mentioned: AdaptiveCardMentioned{ID: payload.UserEmail, Name: name},
+		}}
+	}
+
+	return card
+}
+
+func (t *TeamsHandler) dispatch(msg TeamsMessage) DeliveryFunc {
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		m, err := json.Marshal(msg)
+		if err != nil {
+			return false, xerrors.Errorf("marshal payload: %v", err)
+		}
+
+		// Outer context has a deadline (see CODER_NOTIFICATIONS_DISPATCH_TIMEOUT).
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.WebhookURL.String(), bytes.NewBuffer(m))
+		if err != nil {
+			return false, xerrors.Errorf("create HTTP request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := t.cl.Do(req)
+		if err != nil {
+			return requestFailed(err)
+		}
+		defer resp.Body.Close()
+
+		// Workflows respond with 202 Accepted, legacy connectors with 200 OK.
+		if resp.StatusCode/100 > 2 {
+			respBody := readResponseSnippet(resp.Body)
+			t.log.Warn(ctx, "unsuccessful delivery", slog.F("status_code", resp.StatusCode),
+				slog.F("response", respBody), slog.F("msg_id", msgID))
+			return retryableStatus(resp.StatusCode), xerrors.Errorf("non-2xx response (%d): %s", resp.StatusCode, respBody)
+		}
+
+		return false, nil
+	}
+}
@@ -0,0 +1,133 @@
+package dispatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	const (
+		titleMarkdown = "Workspace **stopped**"
+		bodyMarkdown  = "Your workspace **dev** was stopped."
+	)
+
+	msgPayload := types.MessagePayload{
+		Version:          "1.2",
+		NotificationName: "Workspace Stopped",
+		UserEmail:        "bob@coder.com",
+		UserUsername:     "bob",
+		Actions: []types.TemplateAction{
+			{Label: "View workspace", URL: "https://coder.example.com/@bob/dev"},
+			{Label: "View template", URL: "https://coder.example.com/templates/docker"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		status int
+
+		expectRetryable bool
+		expectErr       string
+	}{
+		{
+			name:   "Accepted",
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "OK",
+			status: http.StatusOK,
+		},
+		{
+			name:      "BadRequest",
+			status:    http.StatusBadRequest,
+			expectErr: "non-2xx response (400)",
+		},
+		{
+			name:            "TooManyRequests",
+			status:          http.StatusTooManyRequests,
+			expectRetryable: true,
+			expectErr:       "non-2xx response (429)",
+		},
+		{
+			name:            "ServerError",
+			status:          http.StatusBadGateway,
+			expectRetryable: true,
+			expectErr:       "non-2xx response (502)",
+		},
+	}
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+			posted := make(chan dispatch.TeamsMessage, 1)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				var msg dispatch.TeamsMessage
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+				posted <- msg
+				w.WriteHeader(tc.status)
+			}))
+			t.Cleanup(server.Close)
+
+			var cfg codersdk.NotificationsTeamsConfig
+			require.NoError(t, cfg.WebhookURL.Set(server.URL))
+
+			handler := dispatch.NewTeamsHandler(cfg, logger.With(slog.F("test", tc.name)))
+			deliveryFn, err := handler.Dispatcher(msgPayload, titleMarkdown, bodyMarkdown, helpers())
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectRetryable, retryable)
+
+			msg := testutil.TryReceive(ctx, t, posted)
+			require.Equal(t, "message", msg.Type)
+			require.Len(t, msg.Attachments, 1)
+			require.Equal(t, "application/vnd.microsoft.card.adaptive", msg.Attachments[0].ContentType)
+
+			card := msg.Attachments[0].Content
+			require.Equal(t, "AdaptiveCard", card.Type)
+			require.Len(t, card.Body, 3)
+			require.Equal(t, "Workspace stopped", card.Body[0].Text)
+			require.Equal(t, "Bolder", card.Body[0].Weight)
+			require.Equal(t, bodyMarkdown, card.Body[1].Text)
+			require.Equal(t, "<at>bob</at>", card.Body[2].Text)
+
+			require.Len(t, card.Actions, 2)
+			for i, action := range card.Actions {
+				require.Equal(t, "Action.OpenUrl", action.Type)
+				require.Equal(t, msgPayload.Actions[i].Label, action.Title)
+				require.Equal(t, msgPayload.Actions[i].URL, action.URL)
+			}
+
+			require.Len(t, card.MSTeams.Entities, 1)
+			require.Equal(t, "mention", card.MSTeams.Entities[0].Type)
+			require.Equal(t, msgPayload.UserEmail, card.MSTeams.Entities[0].Mentioned.ID)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package dispatch
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// WebpushDispatcher delivers a Web Push message to all of a user's subscriptions.
+// It is satisfied by webpush.Dispatcher.
+type WebpushDispatcher interface {
+	Dispatch(ctx context.Context, userID uuid.UUID, notification codersdk.WebpushMessage) error
+}
+
+// WebpushHandler dispatches notification messages as Web Push notifications to the recipient's browsers.
+type WebpushHandler struct {
+	dispatcher WebpushDispatcher
+	log        slog.Logger
+}
+
+func NewWebpushHandler(dispatcher WebpushDispatcher, log slog.Logger) *WebpushHandler {
+	return &WebpushHandler{dispatcher: dispatcher, log: log}
+}
+
+func (w *WebpushHandler) Dispatcher(payload types.MessagePayload, titleMarkdown, bodyMarkdown string, _ template.FuncMap) (DeliveryFunc, error) {
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return nil, xerrors.Errorf("parse user ID: %w", err)
+	}
+
+	// Push notifications are rendered by the browser as plaintext.
+	title, err := markdown.PlaintextFromMarkdown(titleMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render title: %w", err)
+	}
+	body, err := markdown.PlaintextFromMarkdown(bodyMarkdown)
+	if err != nil {
+		return nil, xerrors.Errorf("render body: %w", err)
+	}
+
+	msg := codersdk.WebpushMessage{
+		Title:   title,
+		Body:    body,
+		Actions: make([]codersdk.WebpushMessageAction, 0, len(payload.Actions)),
+	}
+	for _, action := range payload.Actions {
+		msg.Actions = append(msg.Actions, codersdk.WebpushMessageAction{
+			Label: action.Label,
+			URL:   action.URL,
+		})
+	}
+
+	return func(ctx context.Context, msgID uuid.UUID) (retryable bool, err error) {
+		if err := w.dispatcher.Dispatch(ctx, userID, msg); err != nil {
+			w.log.Warn(ctx, "failed to dispatch web push notification", slog.F("msg_id", msgID), slog.Error(err))
+			// Subscriptions which are no longer valid are removed by the dispatcher, so any failure is likely to
+			// be temporary.
+			return true, xerrors.Errorf("dispatch web push: %w", err)
+		}
+		return false, nil
+	}, nil
+}
@@ -0,0 +1,112 @@
+package dispatch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebpush(t *testing.T) {
+	t.Parallel()
+
+	const (
+		titleMarkdown = "Workspace **dev** stopped"
+		bodyMarkdown  = "Your workspace **dev** was stopped.\n\n[Docs](https://coder.com/docs)"
+	)
+
+	userID := uuid.New()
+	msgPayload := types.MessagePayload{
+		Version:          "1.2",
+		NotificationName: "Workspace Stopped",
+		UserID:           userID.String(),
+		Actions: []types.TemplateAction{
+			{Label: "View workspace", URL: "https://coder.example.com/@bob/dev"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		payload     types.MessagePayload
+		dispatchErr error
+
+		expectDispatcherErr string
+		expectRetryable     bool
+		expectErr           string
+	}{
+		{
+			name:    "OK",
+			payload: msgPayload,
+		},
+		{
+			name:            "DispatchFailed",
+			payload:         msgPayload,
+			dispatchErr:     xerrors.New("push service unavailable"),
+			expectRetryable: true,
+			expectErr:       "push service unavailable",
+		},
+		{
+			name: "InvalidUserID",
+			payload: types.MessagePayload{
+				UserID: "invalid",
+			},
+			expectDispatcherErr: "parse user ID",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitShort)
+			logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+			fake := &fakeWebpushDispatcher{err: tc.dispatchErr}
+
+			handler := dispatch.NewWebpushHandler(fake, logger)
+			deliveryFn, err := handler.Dispatcher(tc.payload, titleMarkdown, bodyMarkdown, helpers())
+			if tc.expectDispatcherErr != "" {
+				require.ErrorContains(t, err, tc.expectDispatcherErr)
+				return
+			}
+			require.NoError(t, err)
+
+			retryable, err := deliveryFn(ctx, uuid.New())
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectRetryable, retryable)
+
+			require.Equal(t, userID, fake.userID)
+			require.Equal(t, "Workspace dev stopped", fake.msg.Title)
+			require.Equal(t, "Your workspace dev was stopped.\n\nDocs (https://coder.com/docs)", fake.msg.Body)
+			require.Equal(t, []codersdk.WebpushMessageAction{
+				{Label: "View workspace", URL: "https://coder.example.com/@bob/dev"},
+			}, fake.msg.Actions)
+		})
+	}
+}
+
+type fakeWebpushDispatcher struct {
+	err error
+
+	userID uuid.UUID
+	msg    codersdk.WebpushMessage
+}
+
+func (f *fakeWebpushDispatcher) Dispatch(_ context.Context, userID uuid.UUID, msg codersdk.WebpushMessage) error {
+	f.userID = userID
+	f.msg = msg
+	return f.err
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/notifications/render"
 	"github.com/coder/coder/v2/coderd/notifications/types"
+	markdown "github.com/coder/coder/v2/coderd/render"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -100,10 +101,15 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 		return nil, xerrors.Errorf("failed encoding input labels: %w", err)
 	}
 
+	// The method chosen by the user for this template takes precedence over the one set on the template, which in turn
+	// takes precedence over the deployment-wide default.
 	methods := []database.NotificationMethod{}
-	if metadata.CustomMethod.Valid {
+	switch {
+	case metadata.UserMethod.Valid:
+		methods = append(methods, metadata.UserMethod.NotificationMethod)
+	case metadata.CustomMethod.Valid:
 		methods = append(methods, metadata.CustomMethod.NotificationMethod)
-	} else if s.defaultEnabled {
+	case s.defaultEnabled:
 		methods = append(methods, s.defaultMethod)
 	}
 
@@ -123,6 +129,18 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 			continue
 		}
 
+		// If the user has opted to receive this notification in a digest, hold it back until the report generator
+		// sends the next digest. The inbox is not subject to digests since it is not intrusive.
+		if metadata.Digest && method != database.NotificationMethodInbox {
+			id, err := s.enqueueDigestEntry(ctx, metadata, payload, input)
+			if err != nil {
+				s.log.Warn(ctx, "failed to enqueue digest entry", slog.F("template_id", templateID), slog.F("user_id", userID), slog.Error(err))
+				return nil, xerrors.Errorf("enqueue digest entry: %w", err)
+			}
+			uuids = append(uuids, id)
+			continue
+		}
+
 		id := uuid.New()
 		err = s.store.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
 			ID:                     id,
@@ -162,6 +180,33 @@ func (s *StoreEnqueuer) EnqueueWithData(ctx context.Context, userID, templateID
 	return uuids, nil
 }
 
+// enqueueDigestEntry stores a message to be included in the user's next notification digest.
+// The title is rendered now so that the digest does not need to know how to render every template.
+func (s *StoreEnqueuer) enqueueDigestEntry(ctx context.Context, metadata database.FetchNewMessageMetadataRow, payload *types.MessagePayload, input []byte) (uuid.UUID, error) {
+	title, err := render.GoTemplate(metadata.TitleTemplate, *payload, s.helpers)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("render title: %w", err)
+	}
+	title, err = markdown.PlaintextFromMarkdown(title)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("render title plaintext: %w", err)
+	}
+
+	id := uuid.New()
+	err = s.store.InsertNotificationDigestEntry(ctx, database.InsertNotificationDigestEntryParams{
+		ID:                     id,
+		UserID:                 metadata.UserID,
+		NotificationTemplateID: metadata.NotificationTemplateID,
+		Title:                  title,
+		Payload:                input,
+		CreatedAt:              dbtime.Time(s.clock.Now().UTC()),
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
 // buildPayload creates the payload that the notification will for variable substitution and/or routing.
 // The payload contains information about the recipient, the event that triggered the notification, and any subsequent
 // actions which can be taken by the recipient.
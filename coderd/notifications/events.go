@@ -18,6 +18,7 @@ var (
 	TemplateWorkspaceManualBuildFailed = uuid.MustParse("2faeee0f-26cb-4e96-821c-85ccb9f71513")
 	TemplateWorkspaceOutOfMemory       = uuid.MustParse("a9d027b4-ac49-4fb1-9f6d-45af15f64e7a")
 	TemplateWorkspaceOutOfDisk         = uuid.MustParse("f047f6a3-5713-40f7-85aa-0394cce9fa3a")
+	TemplateWorkspaceHighResourceUsage = uuid.MustParse("6d4f0c7c-4b2e-4a55-9a0f-2f4d1b7e3c91")
 )
 
 // Account-related events.
@@ -44,5 +45,6 @@ var (
 
 // Notification-related events.
 var (
-	TemplateTestNotification = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateTestNotification   = uuid.MustParse("c425f63e-716a-4bf4-ae24-78348f706c3f")
+	TemplateNotificationDigest = uuid.MustParse("b7ce8d1a-1d44-4a8d-a43c-e1ed2b13a44a")
 )
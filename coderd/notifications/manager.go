@@ -73,6 +73,14 @@ func WithTestClock(clock quartz.Clock) ManagerOption {
 	}
 }
 
+// WithWebpushDispatcher enables the delivery of notifications via Web Push. Without it, messages using the webpush
+// method cannot be delivered.
+func WithWebpushDispatcher(dispatcher dispatch.WebpushDispatcher) ManagerOption {
+	return func(m *Manager) {
+		m.handlers[database.NotificationMethodWebpush] = dispatch.NewWebpushHandler(dispatcher, m.log.Named("dispatcher.webpush"))
+	}
+}
+
 // NewManager instantiates a new Manager instance which coordinates notification enqueuing and delivery.
 //
 // helpers is a map of template helpers which are used to customize notification messages to use global settings like
@@ -127,6 +135,8 @@ func defaultHandlers(cfg codersdk.NotificationsConfig, log slog.Logger, store St
 		database.NotificationMethodSmtp:    dispatch.NewSMTPHandler(cfg.SMTP, log.Named("dispatcher.smtp")),
 		database.NotificationMethodWebhook: dispatch.NewWebhookHandler(cfg.Webhook, log.Named("dispatcher.webhook")),
 		database.NotificationMethodInbox:   dispatch.NewInboxHandler(log.Named("dispatcher.inbox"), store, ps),
+		database.NotificationMethodSlack:   dispatch.NewSlackHandler(cfg.Slack, log.Named("dispatcher.slack")),
+		database.NotificationMethodTeams:   dispatch.NewTeamsHandler(cfg.Teams, log.Named("dispatcher.teams")),
 	}
 }
 
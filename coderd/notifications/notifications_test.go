@@ -1217,6 +1217,54 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "TemplateWorkspaceHighResourceUsage",
+			id:   notifications.TemplateWorkspaceHighResourceUsage,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace": "bobby-workspace",
+				},
+				Data: map[string]any{
+					"monitors": []map[string]any{
+						{
+							"resource":  "CPU usage",
+							"threshold": "90%",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "TemplateWorkspaceHighResourceUsage_MultipleResources",
+			id:   notifications.TemplateWorkspaceHighResourceUsage,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels: map[string]string{
+					"workspace": "bobby-workspace",
+				},
+				Data: map[string]any{
+					"monitors": []map[string]any{
+						{
+							"resource":  "CPU usage",
+							"threshold": "90%",
+						},
+						{
+							"resource":  "Network throughput",
+							"threshold": "100 Mbit/s",
+						},
+						{
+							"resource":  "Process count",
+							"threshold": "500",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "TemplateTestNotification",
 			id:   notifications.TemplateTestNotification,
@@ -1227,6 +1275,31 @@ func TestNotificationTemplates_Golden(t *testing.T) {
 				Labels:       map[string]string{},
 			},
 		},
+		{
+			name: "TemplateNotificationDigest",
+			id:   notifications.TemplateNotificationDigest,
+			payload: types.MessagePayload{
+				UserName:     "Bobby",
+				UserEmail:    "bobby@coder.com",
+				UserUsername: "bobby",
+				Labels:       map[string]string{},
+				Data: map[string]any{
+					"since": "2024-10-10 09:03 UTC",
+					"entries": []map[string]any{
+						{
+							"title": "Workspace \"bobby-workspace\" marked as dormant",
+							"label": "View workspace",
+							"url":   "http://test.com/@bobby/bobby-workspace",
+						},
+						{
+							"title": "Workspace \"bobby-workspace\" updated automatically",
+							"label": "",
+							"url":   "",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "TemplateWorkspaceResourceReplaced",
 			id:   notifications.TemplateWorkspaceResourceReplaced,
@@ -1850,6 +1923,95 @@ func TestCustomNotificationMethod(t *testing.T) {
 	}, testutil.WaitLong, testutil.IntervalFast)
 }
 
+// TestUserNotificationMethod ensures that the method chosen by a user takes precedence over the template's method.
+func TestUserNotificationMethod(t *testing.T) {
+	t.Parallel()
+
+	// nolint:gocritic // Unit test.
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	// GIVEN: an enqueuer & a sample user
+	cfg := defaultNotificationsConfig(database.NotificationMethodSmtp)
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), quartz.NewReal())
+	require.NoError(t, err)
+	user := createSampleUser(t, store)
+
+	// WHEN: the user prefers to receive the "workspace dormant" notification by webhook
+	templateID := notifications.TemplateWorkspaceDormant
+	_, err = store.UpdateUserNotificationPreferences(ctx, database.UpdateUserNotificationPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: []uuid.UUID{templateID},
+		Disableds:               []bool{false},
+		Methods:                 []string{string(database.NotificationMethodWebhook)},
+		Digests:                 []bool{false},
+	})
+	require.NoError(t, err, "failed to set preferences")
+
+	// THEN: the notification should be enqueued for delivery by webhook and inbox
+	msgIDs, err := enq.Enqueue(ctx, user.ID, templateID, map[string]string{}, "test")
+	require.NoError(t, err)
+	require.Len(t, msgIDs, 2)
+
+	msgs, err := store.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusPending,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	methods := []database.NotificationMethod{msgs[0].Method, msgs[1].Method}
+	require.ElementsMatch(t, []database.NotificationMethod{database.NotificationMethodWebhook, database.NotificationMethodInbox}, methods)
+}
+
+// TestDigestedNotification ensures that notifications which a user has chosen to receive in a digest are held back,
+// while still being delivered to their inbox.
+func TestDigestedNotification(t *testing.T) {
+	t.Parallel()
+
+	// nolint:gocritic // Unit test.
+	ctx := dbauthz.AsNotifier(testutil.Context(t, testutil.WaitSuperLong))
+	store, _ := dbtestutil.NewDB(t)
+	logger := testutil.Logger(t)
+
+	// GIVEN: an enqueuer & a sample user
+	cfg := defaultNotificationsConfig(database.NotificationMethodSmtp)
+	enq, err := notifications.NewStoreEnqueuer(cfg, store, defaultHelpers(), logger.Named("enqueuer"), quartz.NewReal())
+	require.NoError(t, err)
+	user := createSampleUser(t, store)
+
+	// WHEN: the user has chosen to receive the "workspace dormant" notification in a digest
+	templateID := notifications.TemplateWorkspaceDormant
+	_, err = store.UpdateUserNotificationPreferences(ctx, database.UpdateUserNotificationPreferencesParams{
+		UserID:                  user.ID,
+		NotificationTemplateIds: []uuid.UUID{templateID},
+		Disableds:               []bool{false},
+		Methods:                 []string{""},
+		Digests:                 []bool{true},
+	})
+	require.NoError(t, err, "failed to set preferences")
+
+	_, err = enq.Enqueue(ctx, user.ID, templateID, map[string]string{"name": "dev"}, "test")
+	require.NoError(t, err)
+
+	// THEN: only the inbox message should be enqueued
+	msgs, err := store.GetNotificationMessagesByStatus(ctx, database.GetNotificationMessagesByStatusParams{
+		Status: database.NotificationMessageStatusPending,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, database.NotificationMethodInbox, msgs[0].Method)
+
+	// THEN: the notification should be held back for the next digest
+	// nolint:gocritic // Unit test.
+	entries, err := store.GetNotificationDigestEntriesByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, templateID, entries[0].NotificationTemplateID)
+	require.NotEmpty(t, entries[0].Title)
+}
+
 func TestNotificationsTemplates(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,109 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/quartz"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+	"github.com/coder/coder/v2/coderd/util/slice"
+)
+
+const digestSinceFormat = "2006-01-02 15:04 MST"
+
+// reportNotificationDigests sends a single digest to every user whose oldest held back notification is older than the
+// digest interval. Notifications are held back by the enqueuer when the user has opted to receive them in a digest.
+func reportNotificationDigests(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, interval time.Duration, clk quartz.Clock) error {
+	now := clk.Now()
+
+	userIDs, err := db.GetUserIDsWithDueNotificationDigests(ctx, dbtime.Time(now.Add(-interval)).UTC())
+	if err != nil {
+		return xerrors.Errorf("unable to fetch users with due notification digests: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			logger.Debug(ctx, "context is canceled, quitting", slog.Error(ctx.Err()))
+			return ctx.Err()
+		}
+
+		entries, err := db.GetNotificationDigestEntriesByUserID(ctx, userID)
+		if err != nil {
+			logger.Error(ctx, "unable to fetch notification digest entries", slog.F("user_id", userID), slog.Error(err))
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		reportData, targets := buildDataForNotificationDigest(ctx, logger, entries)
+		_, err = enqueuer.EnqueueWithData(ctx, userID, notifications.TemplateNotificationDigest,
+			map[string]string{},
+			reportData,
+			"report_generator",
+			targets...,
+		)
+		switch {
+		case err == nil:
+		case xerrors.Is(err, notifications.ErrCannotEnqueueDisabledNotification), xerrors.Is(err, notifications.ErrDuplicate):
+			// The digest will never be delivered, so there is no point in keeping its entries around.
+			logger.Debug(ctx, "notification digest not enqueued, discarding entries", slog.F("user_id", userID), slog.Error(err))
+		default:
+			// Keep the entries around so that the digest is attempted again on the next run.
+			logger.Warn(ctx, "failed to send a notification digest", slog.F("user_id", userID), slog.Error(err))
+			continue
+		}
+
+		ids := make([]uuid.UUID, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		if err := db.DeleteNotificationDigestEntries(ctx, ids); err != nil {
+			return xerrors.Errorf("unable to delete notification digest entries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildDataForNotificationDigest lists the digest entries, oldest first, alongside the first action of the
+// notification they were created from. The targets of all entries are combined.
+func buildDataForNotificationDigest(ctx context.Context, logger slog.Logger, entries []database.NotificationDigestEntry) (map[string]any, []uuid.UUID) {
+	var targets []uuid.UUID
+	items := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		item := map[string]any{
+			"title": entry.Title,
+			"label": "",
+			"url":   "",
+		}
+
+		var payload types.MessagePayload
+		if err := json.Unmarshal(entry.Payload, &payload); err != nil {
+			// The title is all that's needed, so don't let a bad payload hold the whole digest back.
+			logger.Warn(ctx, "unable to parse notification digest entry payload", slog.F("entry_id", entry.ID), slog.Error(err))
+		} else {
+			if len(payload.Actions) > 0 {
+				item["label"] = payload.Actions[0].Label
+				item["url"] = payload.Actions[0].URL
+			}
+			targets = append(targets, payload.Targets...)
+		}
+
+		items = append(items, item)
+	}
+
+	return map[string]any{
+		"since":   entries[0].CreatedAt.UTC().Format(digestSinceFormat),
+		"entries": items,
+	}, slice.Unique(targets)
+}
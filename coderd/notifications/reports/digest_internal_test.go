@@ -0,0 +1,128 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/types"
+)
+
+func TestReportNotificationDigests(t *testing.T) {
+	t.Parallel()
+
+	const interval = 24 * time.Hour
+
+	insertEntry := func(ctx context.Context, t *testing.T, db database.Store, userID uuid.UUID, title string, createdAt time.Time, payload types.MessagePayload) {
+		t.Helper()
+
+		raw, err := json.Marshal(payload)
+		require.NoError(t, err)
+		err = db.InsertNotificationDigestEntry(ctx, database.InsertNotificationDigestEntryParams{
+			ID:                     uuid.New(),
+			UserID:                 userID,
+			NotificationTemplateID: notifications.TemplateWorkspaceDormant,
+			Title:                  title,
+			Payload:                raw,
+			CreatedAt:              createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("NoEntries_NoDigest", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+
+		// When
+		err := reportNotificationDigests(ctx, logger, db, notifEnq, interval, clk)
+
+		// Then
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+	})
+
+	t.Run("EntriesNotDue_NoDigest", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+		user := dbgen.User(t, db, database.User{})
+
+		// Given: an entry which was held back less than an interval ago
+		insertEntry(ctx, t, db, user.ID, "Workspace \"dev\" marked as dormant", clk.Now().Add(-time.Hour), types.MessagePayload{})
+
+		// When
+		err := reportNotificationDigests(ctx, logger, db, notifEnq, interval, clk)
+
+		// Then: nothing is sent and the entry is kept
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+		entries, err := db.GetNotificationDigestEntriesByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("EntriesDue_DigestSent", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup
+		ctx, logger, db, _, notifEnq, clk := setup(t)
+		now := clk.Now()
+		user := dbgen.User(t, db, database.User{})
+		other := dbgen.User(t, db, database.User{})
+		workspaceID := uuid.New()
+
+		// Given: two entries, the oldest of which is due
+		oldest := now.Add(-interval - time.Hour)
+		insertEntry(ctx, t, db, user.ID, "Workspace \"dev\" marked as dormant", oldest, types.MessagePayload{
+			Actions: []types.TemplateAction{{Label: "View workspace", URL: "https://coder.example.com/@bob/dev"}},
+			Targets: []uuid.UUID{workspaceID},
+		})
+		insertEntry(ctx, t, db, user.ID, "Workspace \"prod\" updated automatically", now.Add(-time.Hour), types.MessagePayload{
+			Targets: []uuid.UUID{workspaceID},
+		})
+		// And: another user whose entry is not due yet
+		insertEntry(ctx, t, db, other.ID, "Workspace \"test\" marked as dormant", now.Add(-time.Hour), types.MessagePayload{})
+
+		// When
+		err := reportNotificationDigests(ctx, logger, db, notifEnq, interval, clk)
+
+		// Then: a single digest is sent to the user
+		require.NoError(t, err)
+		sent := notifEnq.Sent()
+		require.Len(t, sent, 1)
+		require.Equal(t, user.ID, sent[0].UserID)
+		require.Equal(t, notifications.TemplateNotificationDigest, sent[0].TemplateID)
+		require.Equal(t, []uuid.UUID{workspaceID}, sent[0].Targets)
+		require.Equal(t, oldest.UTC().Format(digestSinceFormat), sent[0].Data["since"])
+		require.Equal(t, []map[string]any{
+			{"title": "Workspace \"dev\" marked as dormant", "label": "View workspace", "url": "https://coder.example.com/@bob/dev"},
+			{"title": "Workspace \"prod\" updated automatically", "label": "", "url": ""},
+		}, sent[0].Data["entries"])
+
+		// Then: the user's entries are removed, but the other user's are kept
+		entries, err := db.GetNotificationDigestEntriesByUserID(ctx, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+		entries, err = db.GetNotificationDigestEntriesByUserID(ctx, other.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		// When: the job runs again
+		notifEnq.Clear()
+		err = reportNotificationDigests(ctx, logger, db, notifEnq, interval, clk)
+
+		// Then: the digest is not sent twice
+		require.NoError(t, err)
+		require.Empty(t, notifEnq.Sent())
+	})
+}
@@ -26,7 +26,9 @@ const (
 	delay = 15 * time.Minute
 )
 
-func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, clk quartz.Clock) io.Closer {
+// NewReportGenerator starts a background job which periodically sends reports, such as failed workspace builds to
+// template admins, and notification digests to users who have chosen to batch some of their notifications.
+func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Store, enqueuer notifications.Enqueuer, digestInterval time.Duration, clk quartz.Clock) io.Closer {
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
@@ -55,6 +57,11 @@ func NewReportGenerator(ctx context.Context, logger slog.Logger, db database.Sto
 				return xerrors.Errorf("unable to generate reports with failed workspace builds: %w", err)
 			}
 
+			err = reportNotificationDigests(ctx, logger, tx, enqueuer, digestInterval, clk)
+			if err != nil {
+				return xerrors.Errorf("unable to send notification digests: %w", err)
+			}
+
 			logger.Info(ctx, "report generator finished", slog.F("duration", clk.Since(start)))
 
 			return nil
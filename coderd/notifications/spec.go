@@ -27,6 +27,7 @@ type Store interface {
 	GetLogoURL(ctx context.Context) (string, error)
 
 	InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error)
+	InsertNotificationDigestEntry(ctx context.Context, arg database.InsertNotificationDigestEntryParams) error
 }
 
 // Handler is responsible for preparing and delivering a notification by a given method.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/notifications/notificationstest"
 	"github.com/coder/coder/v2/codersdk"
@@ -268,6 +269,170 @@ func TestNotificationPreferences(t *testing.T) {
 		}
 		require.True(t, found, "dormant notification preference was not found")
 	})
+
+	t.Run("Modify method", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		opts := createOpts(t)
+		require.NoError(t, opts.DeploymentValues.Notifications.Webhook.Endpoint.Set("https://example.com/webhook"))
+		api := coderdtest.New(t, opts)
+		firstUser := coderdtest.CreateFirstUser(t, api)
+
+		// Given: a member who prefers to receive dormancy notifications by webhook.
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+		template := notifications.TemplateWorkspaceDormant
+		prefs, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				template.String(): string(database.NotificationMethodWebhook),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.Equal(t, string(database.NotificationMethodWebhook), prefs[0].Method)
+		require.False(t, prefs[0].Disabled)
+
+		// When: only the disabled state is modified.
+		prefs, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDisabledMap: map[string]bool{
+				template.String(): true,
+			},
+		})
+
+		// Then: the method should be retained.
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.Equal(t, string(database.NotificationMethodWebhook), prefs[0].Method)
+		require.True(t, prefs[0].Disabled)
+
+		// When: the method is reset.
+		prefs, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				template.String(): "",
+			},
+		})
+
+		// Then: the method override should be removed.
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.Empty(t, prefs[0].Method)
+		require.True(t, prefs[0].Disabled)
+	})
+
+	t.Run("Invalid method", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: attempting to set an unknown method.
+		_, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceDormant.String(): "carrier-pigeon",
+			},
+		})
+
+		// Then: the request should be rejected.
+		var sdkError *codersdk.Error
+		require.Error(t, err)
+		require.ErrorAsf(t, err, &sdkError, "error should be of type *codersdk.Error")
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+		require.Len(t, sdkError.Validations, 1)
+		require.Equal(t, "template_method_map", sdkError.Validations[0].Field)
+	})
+
+	t.Run("Unconfigured method", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: attempting to set a method which the deployment has not configured.
+		_, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceDormant.String(): string(database.NotificationMethodSlack),
+			},
+		})
+
+		// Then: the request should be rejected.
+		var sdkError *codersdk.Error
+		require.Error(t, err)
+		require.ErrorAsf(t, err, &sdkError, "error should be of type *codersdk.Error")
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+		require.Len(t, sdkError.Validations, 1)
+		require.Equal(t, "template_method_map", sdkError.Validations[0].Field)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		if !dbtestutil.WillUsePostgres() {
+			t.Skip("This test requires postgres; it relies on notification templates which are only created by migrations")
+		}
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		opts := createOpts(t)
+		require.NoError(t, opts.DeploymentValues.Notifications.Webhook.Endpoint.Set("https://example.com/webhook"))
+		api := coderdtest.New(t, opts)
+		firstUser := coderdtest.CreateFirstUser(t, api)
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: setting the method of a template which is disabled by default.
+		prefs, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateMethodMap: map[string]string{
+				notifications.TemplateWorkspaceCreated.String(): string(database.NotificationMethodWebhook),
+			},
+		})
+
+		// Then: the template should remain disabled.
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.Equal(t, string(database.NotificationMethodWebhook), prefs[0].Method)
+		require.True(t, prefs[0].Disabled)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		t.Parallel()
+
+		if !dbtestutil.WillUsePostgres() {
+			t.Skip("This test requires postgres; it relies on notification templates which are only created by migrations")
+		}
+
+		ctx := testutil.Context(t, testutil.WaitSuperLong)
+		api := coderdtest.New(t, createOpts(t))
+		firstUser := coderdtest.CreateFirstUser(t, api)
+		memberClient, member := coderdtest.CreateAnotherUser(t, api, firstUser.OrganizationID)
+
+		// When: opting into a digest for a digestible template.
+		prefs, err := memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDigestMap: map[string]bool{
+				notifications.TemplateWorkspaceDormant.String(): true,
+			},
+		})
+
+		// Then: the preference should be set.
+		require.NoError(t, err)
+		require.Len(t, prefs, 1)
+		require.True(t, prefs[0].Digest)
+
+		// When: opting into a digest for a time-sensitive template.
+		_, err = memberClient.UpdateUserNotificationPreferences(ctx, member.ID, codersdk.UpdateUserNotificationPreferences{
+			TemplateDigestMap: map[string]bool{
+				notifications.TemplateUserAccountCreated.String(): true,
+			},
+		})
+
+		// Then: the request should be rejected.
+		var sdkError *codersdk.Error
+		require.Error(t, err)
+		require.ErrorAsf(t, err, &sdkError, "error should be of type *codersdk.Error")
+		require.Equal(t, http.StatusBadRequest, sdkError.StatusCode())
+		require.Equal(t, "template_digest_map", sdkError.Validations[0].Field)
+	})
 }
 
 func TestNotificationDispatchMethods(t *testing.T) {
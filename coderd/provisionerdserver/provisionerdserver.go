@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sqlc-dev/pqtype"
 	semconv "go.opentelemetry.io/otel/semconv/v1.14.0"
 	"go.opentelemetry.io/otel/trace"
@@ -2221,6 +2222,55 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 					return xerrors.Errorf("failed to insert agent volume resource monitor into db: %w", err)
 				}
 			}
+			for monitorType, monitor := range map[database.WorkspaceAgentResourceMonitorType]*sdkproto.ResourceMonitor{
+				database.WorkspaceAgentResourceMonitorTypeCPU:             prAgent.ResourcesMonitoring.Cpu,
+				database.WorkspaceAgentResourceMonitorTypeNetwork:         prAgent.ResourcesMonitoring.Network,
+				database.WorkspaceAgentResourceMonitorTypeFileDescriptors: prAgent.ResourcesMonitoring.FileDescriptors,
+				database.WorkspaceAgentResourceMonitorTypeProcesses:       prAgent.ResourcesMonitoring.Processes,
+			} {
+				if monitor == nil {
+					continue
+				}
+				_, err = db.InsertResourceMonitor(ctx, database.InsertResourceMonitorParams{
+					AgentID:        agentID,
+					Type:           monitorType,
+					Enabled:        monitor.Enabled,
+					Threshold:      monitor.Threshold,
+					State:          database.WorkspaceAgentMonitorStateOK,
+					CreatedAt:      dbtime.Now(),
+					UpdatedAt:      dbtime.Now(),
+					DebouncedUntil: time.Time{},
+				})
+				if err != nil {
+					return xerrors.Errorf("failed to insert agent %s resource monitor into db: %w", monitorType, err)
+				}
+			}
+		}
+
+		if prAgent.PortForwardingPolicy != nil {
+			policy := agentsdk.PortForwardingPolicy{
+				AllowedPorts:       prAgent.PortForwardingPolicy.AllowedPorts,
+				DeniedPorts:        prAgent.PortForwardingPolicy.DeniedPorts,
+				AllowedUnixSockets: prAgent.PortForwardingPolicy.AllowedUnixSockets,
+				DeniedUnixSockets:  prAgent.PortForwardingPolicy.DeniedUnixSockets,
+			}
+			err = policy.Validate()
+			if err != nil {
+				return xerrors.Errorf("agent %q port forwarding policy: %w", prAgent.Name, err)
+			}
+			_, err = db.InsertWorkspaceAgentPortForwardingPolicy(ctx, database.InsertWorkspaceAgentPortForwardingPolicyParams{
+				AgentID: agentID,
+				// The columns are not nullable, so empty lists must not be
+				// nil.
+				AllowedPorts:       append([]string{}, policy.AllowedPorts...),
+				DeniedPorts:        append([]string{}, policy.DeniedPorts...),
+				AllowedUnixSockets: append([]string{}, policy.AllowedUnixSockets...),
+				DeniedUnixSockets:  append([]string{}, policy.DeniedUnixSockets...),
+				CreatedAt:          dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert agent port forwarding policy: %w", err)
+			}
 		}
 
 		logSourceIDs := make([]uuid.UUID, 0, len(prAgent.Scripts))
@@ -2235,7 +2285,17 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		scriptStartBlocksLogin := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStart := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStop := make([]bool, 0, len(prAgent.Scripts))
-
+		scriptDependsOn := make([]string, 0, len(prAgent.Scripts))
+		scriptReadinessProbeCommand := make([]string, 0, len(prAgent.Scripts))
+		scriptReadinessProbeTCP := make([]string, 0, len(prAgent.Scripts))
+		scriptReadinessProbeHTTP := make([]string, 0, len(prAgent.Scripts))
+		scriptReadinessProbeInterval := make([]int32, 0, len(prAgent.Scripts))
+		scriptReadinessProbeTimeout := make([]int32, 0, len(prAgent.Scripts))
+
+		err = validateScriptDependencies(prAgent.Scripts)
+		if err != nil {
+			return xerrors.Errorf("agent %q: %w", prAgent.Name, err)
+		}
 		for _, script := range prAgent.Scripts {
 			logSourceIDs = append(logSourceIDs, uuid.New())
 			logSourceDisplayNames = append(logSourceDisplayNames, script.DisplayName)
@@ -2249,6 +2309,17 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			scriptStartBlocksLogin = append(scriptStartBlocksLogin, script.StartBlocksLogin)
 			scriptRunOnStart = append(scriptRunOnStart, script.RunOnStart)
 			scriptRunOnStop = append(scriptRunOnStop, script.RunOnStop)
+			dependsOn, err := textArrayLiteral(script.DependsOn)
+			if err != nil {
+				return xerrors.Errorf("encode script %q dependencies: %w", script.DisplayName, err)
+			}
+			scriptDependsOn = append(scriptDependsOn, dependsOn)
+			probe := script.GetReadinessProbe()
+			scriptReadinessProbeCommand = append(scriptReadinessProbeCommand, probe.GetCommand())
+			scriptReadinessProbeTCP = append(scriptReadinessProbeTCP, probe.GetTcp())
+			scriptReadinessProbeHTTP = append(scriptReadinessProbeHTTP, probe.GetHttp())
+			scriptReadinessProbeInterval = append(scriptReadinessProbeInterval, probe.GetIntervalSeconds())
+			scriptReadinessProbeTimeout = append(scriptReadinessProbeTimeout, probe.GetTimeoutSeconds())
 		}
 
 		// Dev Containers require a script and log/source, so we do this before
@@ -2285,6 +2356,12 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				// been enabled.
 				scriptRunOnStart = append(scriptRunOnStart, true)
 				scriptRunOnStop = append(scriptRunOnStop, false)
+				scriptDependsOn = append(scriptDependsOn, "{}")
+				scriptReadinessProbeCommand = append(scriptReadinessProbeCommand, "")
+				scriptReadinessProbeTCP = append(scriptReadinessProbeTCP, "")
+				scriptReadinessProbeHTTP = append(scriptReadinessProbeHTTP, "")
+				scriptReadinessProbeInterval = append(scriptReadinessProbeInterval, 0)
+				scriptReadinessProbeTimeout = append(scriptReadinessProbeTimeout, 0)
 			}
 
 			_, err = db.InsertWorkspaceAgentDevcontainers(ctx, database.InsertWorkspaceAgentDevcontainersParams{
@@ -2324,6 +2401,13 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			RunOnStop:        scriptRunOnStop,
 			DisplayName:      scriptDisplayName,
 			ID:               scriptIDs,
+			DependsOn:        scriptDependsOn,
+
+			ReadinessProbeCommand:         scriptReadinessProbeCommand,
+			ReadinessProbeTCP:             scriptReadinessProbeTCP,
+			ReadinessProbeHTTP:            scriptReadinessProbeHTTP,
+			ReadinessProbeIntervalSeconds: scriptReadinessProbeInterval,
+			ReadinessProbeTimeoutSeconds:  scriptReadinessProbeTimeout,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert agent scripts: %w", err)
@@ -2425,6 +2509,71 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 	return nil
 }
 
+// validateScriptDependencies checks that scripts only depend on other scripts
+// of the same agent, and that the dependencies do not form a cycle.
+func validateScriptDependencies(scripts []*sdkproto.Script) error {
+	dependsOn := make(map[string][]string, len(scripts))
+	for _, script := range scripts {
+		dependsOn[script.DisplayName] = append(dependsOn[script.DisplayName], script.DependsOn...)
+	}
+	for _, script := range scripts {
+		for _, dep := range script.DependsOn {
+			if _, ok := dependsOn[dep]; !ok {
+				return xerrors.Errorf("script %q depends on unknown script %q", script.DisplayName, dep)
+			}
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(scripts))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			var cycle []string
+			for _, n := range append(path[slices.Index(path, name):], name) {
+				cycle = append(cycle, fmt.Sprintf("%q", n))
+			}
+			return xerrors.Errorf("script dependency cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range dependsOn[name] {
+			err := visit(dep, append(path, name))
+			if err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, script := range scripts {
+		err := visit(script.DisplayName, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textArrayLiteral encodes values as a Postgres text array literal. This is
+// how InsertWorkspaceAgentScripts accepts an array for each script.
+func textArrayLiteral(values []string) (string, error) {
+	v, err := pq.StringArray(values).Value()
+	if err != nil {
+		return "", err
+	}
+	literal, ok := v.(string)
+	if !ok {
+		return "{}", nil
+	}
+	return literal, nil
+}
+
 func workspaceSessionTokenName(workspace database.Workspace) string {
 	return fmt.Sprintf("%s_%s_session_token", workspace.OwnerID, workspace.ID)
 }
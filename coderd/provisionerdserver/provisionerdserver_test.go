@@ -2523,6 +2523,14 @@ func TestInsertWorkspaceResource(t *testing.T) {
 							Threshold: 50,
 						},
 					},
+					Cpu: &sdkproto.ResourceMonitor{
+						Enabled:   true,
+						Threshold: 95,
+					},
+					Processes: &sdkproto.ResourceMonitor{
+						Enabled:   false,
+						Threshold: 70,
+					},
 				},
 			}},
 		})
@@ -2546,6 +2554,69 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "/volume1", volMonitors[0].Path)
 		require.Equal(t, int32(50), volMonitors[1].Threshold)
 		require.Equal(t, "/volume2", volMonitors[1].Path)
+
+		monitors, err := db.FetchResourceMonitorsByAgentID(ctx, agent.ID)
+		require.NoError(t, err)
+		require.Len(t, monitors, 2)
+		slices.SortFunc(monitors, func(a, b database.WorkspaceAgentResourceMonitor) int {
+			return strings.Compare(string(a.Type), string(b.Type))
+		})
+		require.Equal(t, database.WorkspaceAgentResourceMonitorTypeCPU, monitors[0].Type)
+		require.True(t, monitors[0].Enabled)
+		require.Equal(t, int32(95), monitors[0].Threshold)
+		require.Equal(t, database.WorkspaceAgentResourceMonitorTypeProcesses, monitors[1].Type)
+		require.False(t, monitors[1].Enabled)
+		require.Equal(t, int32(70), monitors[1].Threshold)
+	})
+
+	t.Run("PortForwardingPolicy", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name:        "dev",
+				DisplayApps: &sdkproto.DisplayApps{},
+				PortForwardingPolicy: &sdkproto.PortForwardingPolicy{
+					AllowedPorts:      []string{"3000-3999", "8080"},
+					DeniedUnixSockets: []string{"/var/run/docker.sock"},
+				},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+
+		policy, err := db.GetWorkspaceAgentPortForwardingPolicy(ctx, agents[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"3000-3999", "8080"}, policy.AllowedPorts)
+		require.Equal(t, []string{}, policy.DeniedPorts)
+		require.Equal(t, []string{}, policy.AllowedUnixSockets)
+		require.Equal(t, []string{"/var/run/docker.sock"}, policy.DeniedUnixSockets)
+	})
+
+	t.Run("InvalidPortForwardingPolicy", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name:        "dev",
+				DisplayApps: &sdkproto.DisplayApps{},
+				PortForwardingPolicy: &sdkproto.PortForwardingPolicy{
+					DeniedPorts: []string{"ssh"},
+				},
+			}},
+		})
+		require.ErrorContains(t, err, "port forwarding policy")
 	})
 
 	t.Run("Devcontainers", func(t *testing.T) {
@@ -2581,6 +2652,82 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		require.Equal(t, "/workspace2", devcontainers[1].WorkspaceFolder)
 		require.Equal(t, "/workspace2/.devcontainer/devcontainer.json", devcontainers[1].ConfigPath)
 	})
+
+	t.Run("ScriptDependencies", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Install tools",
+					Script:      "install.sh",
+					RunOnStart:  true,
+				}, {
+					DisplayName: "Start server",
+					Script:      "serve.sh",
+					RunOnStart:  true,
+					DependsOn:   []string{"Install tools"},
+					ReadinessProbe: &sdkproto.ScriptReadinessProbe{
+						Http:            "http://localhost:8080/healthz",
+						IntervalSeconds: 2,
+						TimeoutSeconds:  60,
+					},
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 2)
+		require.Empty(t, scripts[0].DependsOn)
+		require.Empty(t, scripts[0].ReadinessProbeHTTP)
+		require.Equal(t, []string{"Install tools"}, scripts[1].DependsOn)
+		require.Equal(t, "http://localhost:8080/healthz", scripts[1].ReadinessProbeHTTP)
+		require.EqualValues(t, 2, scripts[1].ReadinessProbeIntervalSeconds)
+		require.EqualValues(t, 60, scripts[1].ReadinessProbeTimeoutSeconds)
+
+		err = insert(dbmem.New(), uuid.New(), &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "a",
+					DependsOn:   []string{"missing"},
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, `script "a" depends on unknown script "missing"`)
+
+		err = insert(dbmem.New(), uuid.New(), &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Name: "dev",
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "a",
+					DependsOn:   []string{"b"},
+				}, {
+					DisplayName: "b",
+					DependsOn:   []string{"c"},
+				}, {
+					DisplayName: "c",
+					DependsOn:   []string{"a"},
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, `script dependency cycle: "a" -> "b" -> "c" -> "a"`)
+	})
 }
 
 func TestNotifications(t *testing.T) {
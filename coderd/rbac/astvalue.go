@@ -186,6 +186,12 @@ func (s Scope) regoValue() ast.Value {
 		ast.StringTerm("allow_list"),
 		ast.NewTerm(regoSliceString(s.AllowIDList...)),
 	)
+	if len(s.AllowListTypes) > 0 {
+		r.Insert(
+			ast.StringTerm("allow_list_types"),
+			ast.NewTerm(regoSliceString(s.AllowListTypes...)),
+		)
+	}
 	return r
 }
 
@@ -227,6 +227,14 @@ scope_allow_list if {
 	input.object.id in input.subject.scope.allow_list
 }
 
+# If the allow_list is limited to some resource types, resources of other types
+# are not limited by it.
+scope_allow_list if {
+	not "*" in input.subject.scope.allow_list
+	count(input.subject.scope.allow_list_types) > 0
+	not input.object.type in input.subject.scope.allow_list_types
+}
+
 # The allow block is quite simple. Any set with `-1` cascades down in levels.
 # Authorization looks for any `allow` statement that is true. Multiple can be true!
 # Note that the absence of `allow` means "unauthorized".
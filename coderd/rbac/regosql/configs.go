@@ -50,6 +50,22 @@ func AuditLogConverter() *sqltypes.VariableConverter {
 	return matcher
 }
 
+// SessionRecordingConverter authorizes session recordings like audit logs.
+// The recording query joins workspaces and agents, so columns are qualified.
+func SessionRecordingConverter() *sqltypes.VariableConverter {
+	matcher := sqltypes.NewVariableConverter().RegisterMatcher(
+		sqltypes.StringVarMatcher("workspace_session_recordings.id :: text", []string{"input", "object", "id"}),
+		sqltypes.StringVarMatcher("workspaces.organization_id :: text", []string{"input", "object", "org_owner"}),
+		// Session recordings have no user owner, only owner by an organization.
+		sqltypes.AlwaysFalse(userOwnerMatcher()),
+	)
+	matcher.RegisterMatcher(
+		sqltypes.AlwaysFalse(groupACLMatcher(matcher)),
+		sqltypes.AlwaysFalse(userACLMatcher(matcher)),
+	)
+	return matcher
+}
+
 func UserConverter() *sqltypes.VariableConverter {
 	matcher := sqltypes.NewVariableConverter().RegisterMatcher(
 		resourceIDMatcher(),
@@ -73,9 +89,11 @@ func WorkspaceConverter() *sqltypes.VariableConverter {
 		sqltypes.StringVarMatcher("workspaces.organization_id :: text", []string{"input", "object", "org_owner"}),
 		userOwnerMatcher(),
 	)
+	// The columns are qualified because workspace queries join other tables
+	// that also carry ACL columns.
 	matcher.RegisterMatcher(
-		sqltypes.AlwaysFalse(groupACLMatcher(matcher)),
-		sqltypes.AlwaysFalse(userACLMatcher(matcher)),
+		ACLGroupMatcher(matcher, "workspaces.group_acl", []string{"input", "object", "acl_group_list"}),
+		ACLGroupMatcher(matcher, "workspaces.user_acl", []string{"input", "object", "acl_user_list"}),
 	)
 
 	return matcher
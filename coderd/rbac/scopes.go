@@ -2,6 +2,8 @@ package rbac
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -127,9 +129,12 @@ func (name ScopeName) Name() RoleIdentifier {
 // reject any resource that is not in the AllowIDList.
 // To not use an AllowIDList to reject authorization, use a wildcard for the
 // AllowIDList. Eg: 'AllowIDList: []string{WildcardSymbol}'
+// If AllowListTypes is set, the AllowIDList only applies to resources of
+// those types.
 type Scope struct {
 	Role
-	AllowIDList []string `json:"allow_list"`
+	AllowIDList    []string `json:"allow_list"`
+	AllowListTypes []string `json:"allow_list_types,omitempty"`
 }
 
 func (s Scope) Expand() (Scope, error) {
@@ -147,3 +152,151 @@ func ExpandScope(scope ScopeName) (Scope, error) {
 	}
 	return role, nil
 }
+
+// ScopePermission is a resource type and action pair that a custom scope is
+// limited to. It is written as "resource:action", for example
+// "workspace:read" or "workspace:*".
+type ScopePermission struct {
+	ResourceType string
+	Action       policy.Action
+}
+
+func (p ScopePermission) String() string {
+	return p.ResourceType + ":" + string(p.Action)
+}
+
+// scopePermissionGroups are shorthands for the permissions a common task
+// needs. They are expanded when parsed, so they never reach the policy.
+var scopePermissionGroups = map[string][]ScopePermission{
+	// Pushing a template uploads its files, creates a template version and
+	// promotes it.
+	"template:push": {
+		{ResourceType: ResourceTemplate.Type, Action: policy.ActionCreate},
+		{ResourceType: ResourceTemplate.Type, Action: policy.ActionRead},
+		{ResourceType: ResourceTemplate.Type, Action: policy.ActionUpdate},
+		{ResourceType: ResourceFile.Type, Action: policy.ActionCreate},
+		{ResourceType: ResourceFile.Type, Action: policy.ActionRead},
+		{ResourceType: ResourceOrganization.Type, Action: policy.ActionRead},
+		{ResourceType: ResourceUser.Type, Action: policy.ActionRead},
+	},
+}
+
+// ParseScopePermissions parses "resource:action" pairs, expanding the
+// shorthands for common tasks. The resource must be a known RBAC resource and
+// the action must apply to it.
+func ParseScopePermissions(perms ...string) ([]ScopePermission, error) {
+	parsed := make([]ScopePermission, 0, len(perms))
+	for _, perm := range perms {
+		if group, ok := scopePermissionGroups[perm]; ok {
+			parsed = append(parsed, group...)
+			continue
+		}
+
+		resourceType, action, ok := strings.Cut(perm, ":")
+		if !ok {
+			return nil, xerrors.Errorf("scope permission %q must be formatted as resource:action", perm)
+		}
+		def, ok := policy.RBACPermissions[resourceType]
+		if !ok || resourceType == policy.WildcardSymbol {
+			return nil, xerrors.Errorf("scope permission %q: unknown resource %q", perm, resourceType)
+		}
+		if _, ok := def.Actions[policy.Action(action)]; !ok && action != policy.WildcardSymbol {
+			return nil, xerrors.Errorf("scope permission %q: resource %q has no action %q", perm, resourceType, action)
+		}
+		parsed = append(parsed, ScopePermission{ResourceType: resourceType, Action: policy.Action(action)})
+	}
+	return parsed, nil
+}
+
+// ScopeAllowListElement is a resource a custom scope is limited to. It is
+// written as "resource:id", for example "template:<uuid>".
+type ScopeAllowListElement struct {
+	ResourceType string
+	ID           string
+}
+
+func (e ScopeAllowListElement) String() string {
+	return e.ResourceType + ":" + e.ID
+}
+
+// ParseScopeAllowList parses "resource:id" pairs.
+func ParseScopeAllowList(elements ...string) ([]ScopeAllowListElement, error) {
+	parsed := make([]ScopeAllowListElement, 0, len(elements))
+	for _, element := range elements {
+		resourceType, id, ok := strings.Cut(element, ":")
+		if !ok || id == "" {
+			return nil, xerrors.Errorf("allow list entry %q must be formatted as resource:id", element)
+		}
+		if _, ok := policy.RBACPermissions[resourceType]; !ok || resourceType == policy.WildcardSymbol {
+			return nil, xerrors.Errorf("allow list entry %q: unknown resource %q", element, resourceType)
+		}
+		parsed = append(parsed, ScopeAllowListElement{ResourceType: resourceType, ID: id})
+	}
+	return parsed, nil
+}
+
+// CustomScope limits a builtin scope to the given permissions and resources.
+// Without permissions the permissions of the builtin scope are kept. The
+// allow list only limits the resource types it names, resources of other
+// types are only limited by the permissions. Templates in the allow list also
+// limit workspaces, so the caller should add the workspaces of the templates.
+// Permissions can only narrow ScopeAll, as a scope is a single role.
+func CustomScope(base ScopeName, perms []ScopePermission, allowList []ScopeAllowListElement) (Scope, error) {
+	scope, err := base.Expand()
+	if err != nil {
+		return Scope{}, err
+	}
+
+	if len(perms) > 0 {
+		if base != ScopeAll {
+			return Scope{}, xerrors.Errorf("scope permissions can only narrow the %q scope, not %q", ScopeAll, base)
+		}
+		site := make(map[string][]policy.Action)
+		for _, perm := range perms {
+			for _, t := range withDormantWorkspaces(perm.ResourceType) {
+				if !slices.Contains(site[t], perm.Action) {
+					site[t] = append(site[t], perm.Action)
+				}
+			}
+		}
+		scope.Role = Role{
+			Identifier:  RoleIdentifier{Name: fmt.Sprintf("Scope_%s", scopeCustom)},
+			DisplayName: "Custom scope",
+			Site:        Permissions(site),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		}
+	}
+
+	if len(allowList) > 0 {
+		scope.AllowIDList = make([]string, 0, len(allowList))
+		scope.AllowListTypes = []string{}
+		for _, element := range allowList {
+			scope.AllowIDList = append(scope.AllowIDList, element.ID)
+			types := withDormantWorkspaces(element.ResourceType)
+			if element.ResourceType == ResourceTemplate.Type {
+				types = append(types, withDormantWorkspaces(ResourceWorkspace.Type)...)
+			}
+			for _, t := range types {
+				if !slices.Contains(scope.AllowListTypes, t) {
+					scope.AllowListTypes = append(scope.AllowListTypes, t)
+				}
+			}
+		}
+	}
+	return scope, nil
+}
+
+// withDormantWorkspaces adds the dormant workspace type to the workspace
+// type. Dormant workspaces are workspaces too, a workspace should not escape
+// a scope by becoming dormant.
+func withDormantWorkspaces(resourceType string) []string {
+	if resourceType == ResourceWorkspace.Type {
+		return []string{resourceType, ResourceWorkspaceDormant.Type}
+	}
+	return []string{resourceType}
+}
+
+// scopeCustom names scopes built by CustomScope. It is not a builtin scope
+// and cannot be expanded by name.
+const scopeCustom ScopeName = "custom"
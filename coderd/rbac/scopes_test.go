@@ -0,0 +1,91 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+)
+
+func TestParseScopePermissions(t *testing.T) {
+	t.Parallel()
+
+	perms, err := rbac.ParseScopePermissions("workspace:read", "workspace:*")
+	require.NoError(t, err)
+	require.Equal(t, []rbac.ScopePermission{
+		{ResourceType: rbac.ResourceWorkspace.Type, Action: policy.ActionRead},
+		{ResourceType: rbac.ResourceWorkspace.Type, Action: policy.WildcardSymbol},
+	}, perms)
+
+	// Shorthands are expanded.
+	perms, err = rbac.ParseScopePermissions("template:push")
+	require.NoError(t, err)
+	require.Contains(t, perms, rbac.ScopePermission{ResourceType: rbac.ResourceFile.Type, Action: policy.ActionCreate})
+
+	for _, invalid := range []string{"workspace", "*:read", "unknown:read", "workspace:push", "file:delete"} {
+		_, err := rbac.ParseScopePermissions(invalid)
+		require.Error(t, err, invalid)
+	}
+
+	allowList, err := rbac.ParseScopeAllowList("template:1234")
+	require.NoError(t, err)
+	require.Equal(t, []rbac.ScopeAllowListElement{{ResourceType: rbac.ResourceTemplate.Type, ID: "1234"}}, allowList)
+
+	for _, invalid := range []string{"template", "template:", "unknown:1234"} {
+		_, err := rbac.ParseScopeAllowList(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCustomScope(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	auth := rbac.NewAuthorizer(prometheus.NewRegistry())
+	userID := uuid.New()
+	orgID := uuid.New()
+	allowed := uuid.New()
+	workspace := func(id uuid.UUID) rbac.Object {
+		return rbac.ResourceWorkspace.WithID(id).InOrg(orgID).WithOwner(userID.String())
+	}
+
+	perms, err := rbac.ParseScopePermissions("workspace:read", "workspace:start", "file:read")
+	require.NoError(t, err)
+	allowList := []rbac.ScopeAllowListElement{{ResourceType: rbac.ResourceWorkspace.Type, ID: allowed.String()}}
+	scope, err := rbac.CustomScope(rbac.ScopeAll, perms, allowList)
+	require.NoError(t, err)
+	subject := rbac.Subject{
+		ID:    userID.String(),
+		Roles: rbac.RoleIdentifiers{rbac.RoleMember(), rbac.ScopedRoleOrgMember(orgID)},
+		Scope: scope,
+	}.WithCachedASTValue()
+
+	require.NoError(t, auth.Authorize(ctx, subject, policy.ActionRead, workspace(allowed)))
+	require.NoError(t, auth.Authorize(ctx, subject, policy.ActionWorkspaceStart, workspace(allowed)))
+	// Actions outside of the scope are rejected.
+	require.Error(t, auth.Authorize(ctx, subject, policy.ActionUpdate, workspace(allowed)))
+	require.Error(t, auth.Authorize(ctx, subject, policy.ActionDelete, workspace(allowed)))
+	// Resources outside of the allow list are rejected.
+	require.Error(t, auth.Authorize(ctx, subject, policy.ActionRead, workspace(uuid.New())))
+	// Resources of other types are not limited by the allow list.
+	require.NoError(t, auth.Authorize(ctx, subject, policy.ActionRead, rbac.ResourceFile.WithID(uuid.New()).WithOwner(userID.String())))
+	// Dormant workspaces do not escape the scope.
+	require.NoError(t, auth.Authorize(ctx, subject, policy.ActionRead,
+		rbac.ResourceWorkspaceDormant.WithID(allowed).InOrg(orgID).WithOwner(userID.String())))
+
+	// Permissions cannot widen a narrower scope.
+	_, err = rbac.CustomScope(rbac.ScopeApplicationConnect, perms, nil)
+	require.Error(t, err)
+
+	// Without permissions the builtin scope is kept.
+	scope, err = rbac.CustomScope(rbac.ScopeApplicationConnect, nil, allowList)
+	require.NoError(t, err)
+	require.Equal(t, "Scope_application_connect", scope.Name().Name)
+	require.Equal(t, []string{allowed.String()}, scope.AllowIDList)
+	require.Equal(t, []string{rbac.ResourceWorkspace.Type, rbac.ResourceWorkspaceDormant.Type}, scope.AllowListTypes)
+}
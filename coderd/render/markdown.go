@@ -2,11 +2,13 @@ package render
 
 import (
 	"bytes"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
 	gomarkdown "github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"golang.org/x/xerrors"
@@ -108,3 +110,99 @@ func HTMLFromMarkdown(markdown string) string {
 	})
 	return string(bytes.TrimSpace(gomarkdown.Render(doc, renderer)))
 }
+
+// slackEscaper escapes the control characters which Slack reserves in mrkdwn text.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SlackMrkdwnFromMarkdown converts Markdown to Slack's mrkdwn dialect, which
+// uses single delimiters for bold and strikethrough and its own link syntax.
+// Images are rendered as links and raw HTML is dropped.
+func SlackMrkdwnFromMarkdown(markdown string) string {
+	p := parser.NewWithExtensions(parser.CommonExtensions)
+	doc := p.Parse([]byte(markdown))
+
+	var (
+		buf       strings.Builder
+		listDepth int
+		ordinals  []int
+	)
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		switch n := node.(type) {
+		case *ast.Text:
+			buf.WriteString(slackEscaper.Replace(string(n.Literal)))
+		case *ast.Strong:
+			buf.WriteString("*")
+		case *ast.Emph:
+			buf.WriteString("_")
+		case *ast.Del:
+			buf.WriteString("~")
+		case *ast.Heading:
+			buf.WriteString("*")
+			if !entering {
+				buf.WriteString("\n\n")
+			}
+		case *ast.Code:
+			buf.WriteString("`" + string(n.Literal) + "`")
+		case *ast.CodeBlock:
+			buf.WriteString("```\n" + strings.TrimSuffix(string(n.Literal), "\n") + "\n```\n\n")
+		case *ast.Link, *ast.Image:
+			var dest []byte
+			if l, ok := n.(*ast.Link); ok {
+				dest = l.Destination
+			} else {
+				dest = n.(*ast.Image).Destination
+			}
+			if entering {
+				buf.WriteString("<" + string(dest) + "|")
+			} else {
+				buf.WriteString(">")
+			}
+		case *ast.Softbreak, *ast.Hardbreak:
+			buf.WriteString("\n")
+		case *ast.HorizontalRule:
+			buf.WriteString("―――\n\n")
+		case *ast.BlockQuote:
+			if entering {
+				buf.WriteString("> ")
+			}
+		case *ast.List:
+			if entering {
+				listDepth++
+				ordinals = append(ordinals, n.Start)
+			} else {
+				listDepth--
+				ordinals = ordinals[:len(ordinals)-1]
+				if listDepth == 0 {
+					buf.WriteString("\n")
+				}
+			}
+		case *ast.ListItem:
+			if entering {
+				buf.WriteString(strings.Repeat("    ", listDepth-1))
+				if n.ListFlags&ast.ListTypeOrdered != 0 {
+					i := len(ordinals) - 1
+					if ordinals[i] == 0 {
+						ordinals[i] = 1
+					}
+					buf.WriteString(strconv.Itoa(ordinals[i]) + ". ")
+					ordinals[i]++
+				} else {
+					buf.WriteString("• ")
+				}
+			}
+		case *ast.Paragraph:
+			if !entering {
+				if _, inList := n.Parent.(*ast.ListItem); inList {
+					buf.WriteString("\n")
+				} else {
+					buf.WriteString("\n\n")
+				}
+			}
+		case *ast.HTMLBlock, *ast.HTMLSpan:
+			return ast.SkipChildren
+		}
+		return ast.GoToNext
+	})
+
+	return strings.TrimSpace(buf.String())
+}
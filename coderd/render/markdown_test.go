@@ -89,3 +89,20 @@ func TestHTML(t *testing.T) {
 		})
 	}
 }
+
+func TestSlackMrkdwn(t *testing.T) {
+	t.Parallel()
+
+	mdDescription := `# Workspace stopped
+Your workspace **my-workspace** was _stopped_ by ~~nobody~~ the [scheduler](https://coder.com/docs).
+
+- Reason: ` + "`autostop`" + `
+- Owner: admin & 1 < 2
+
+1. First
+2. Second`
+
+	expected := "*Workspace stopped*\n\nYour workspace *my-workspace* was _stopped_ by ~nobody~ the <https://coder.com/docs|scheduler>.\n\n• Reason: `autostop`\n• Owner: admin &amp; 1 &lt; 2\n\n1. First\n2. Second"
+
+	require.Equal(t, expected, render.SlackMrkdwnFromMarkdown(mdDescription))
+}
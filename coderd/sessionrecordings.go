@@ -0,0 +1,199 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get session recordings
+// @ID get-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param workspace_id query string false "Workspace ID" format(uuid)
+// @Param connection_id query string false "Connection ID, the request ID of the connection audit logs" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /sessionrecordings [get]
+func (api *API) sessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	queryParams := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	workspaceID := parser.UUID(queryParams, uuid.Nil, "workspace_id")
+	connectionID := parser.UUID(queryParams, uuid.Nil, "connection_id")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceSessionRecordings(ctx, database.GetWorkspaceSessionRecordingsParams{
+		WorkspaceID:  workspaceID,
+		ConnectionID: connectionID,
+		// #nosec G115 - Safe conversion as pagination offset is expected to be within int32 range
+		OffsetOpt: int32(page.Offset),
+		// #nosec G115 - Safe conversion as pagination limit is expected to be within int32 range
+		LimitOpt: int32(page.Limit),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	recordings := make([]codersdk.WorkspaceSessionRecording, 0, len(rows))
+	for _, row := range rows {
+		recordings = append(recordings, codersdk.WorkspaceSessionRecording{
+			ID:               row.ID,
+			OrganizationID:   row.OrganizationID,
+			WorkspaceID:      row.WorkspaceID,
+			WorkspaceName:    row.WorkspaceName,
+			WorkspaceOwnerID: row.WorkspaceOwnerID,
+			AgentID:          row.AgentID,
+			AgentName:        row.AgentName,
+			ConnectionID:     row.ConnectionID,
+			ConnectionType:   row.ConnectionType,
+			StartedAt:        row.StartedAt,
+			EndedAt:          row.EndedAt,
+			Size:             row.Size,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, recordings)
+}
+
+// @Summary Get session recording by ID
+// @ID get-session-recording-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param sessionrecording path string true "Session recording ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceSessionRecording
+// @Router /sessionrecordings/{sessionrecording} [get]
+func (api *API) sessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	recording, ok := api.fetchSessionRecording(rw, r)
+	if !ok {
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(dbauthz.AsSystemRestricted(ctx), recording.WorkspaceID) //nolint:gocritic // Auditors may not be able to read the workspace.
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	agent, err := api.Database.GetWorkspaceAgentByID(dbauthz.AsSystemRestricted(ctx), recording.AgentID) //nolint:gocritic // Auditors may not be able to read the workspace agent.
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceSessionRecording{
+		ID:               recording.ID,
+		OrganizationID:   recording.OrganizationID,
+		WorkspaceID:      recording.WorkspaceID,
+		WorkspaceName:    workspace.Name,
+		WorkspaceOwnerID: workspace.OwnerID,
+		AgentID:          recording.AgentID,
+		AgentName:        agent.Name,
+		ConnectionID:     recording.ConnectionID,
+		ConnectionType:   recording.ConnectionType,
+		StartedAt:        recording.StartedAt,
+		EndedAt:          recording.EndedAt,
+		Size:             recording.Size,
+	})
+}
+
+// @Summary Get session recording contents
+// @ID get-session-recording-contents
+// @Security CoderSessionToken
+// @Produce application/x-asciicast
+// @Tags Audit
+// @Param sessionrecording path string true "Session recording ID" format(uuid)
+// @Success 200
+// @Router /sessionrecordings/{sessionrecording}/cast [get]
+func (api *API) sessionRecordingCast(rw http.ResponseWriter, r *http.Request) {
+	recording, ok := api.fetchSessionRecording(rw, r)
+	if !ok {
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/x-asciicast")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.ID.String()+".cast"))
+	rw.Header().Set("Content-Length", strconv.FormatInt(recording.Size, 10))
+	rw.WriteHeader(http.StatusOK)
+
+	// Stream the chunks in batches so that long recordings are never held in
+	// memory as a whole.
+	ctx := r.Context()
+	var offset int64
+	for offset < recording.Size {
+		chunks, err := api.Database.GetWorkspaceSessionRecordingChunks(ctx, database.GetWorkspaceSessionRecordingChunksParams{
+			RecordingID: recording.ID,
+			OffsetBytes: offset,
+			LimitOpt:    sessionRecordingChunkBatchSize,
+		})
+		if err != nil {
+			api.Logger.Warn(ctx, "get session recording chunks", slog.F("recording_id", recording.ID), slog.Error(err))
+			return
+		}
+		if len(chunks) == 0 {
+			return
+		}
+		for _, chunk := range chunks {
+			// The headers are already written, a gap or a recording that was
+			// restarted during the download can only end the response early.
+			if chunk.OffsetBytes != offset {
+				return
+			}
+			_, err = rw.Write(chunk.Data)
+			if err != nil {
+				return
+			}
+			offset += int64(len(chunk.Data))
+		}
+	}
+}
+
+// sessionRecordingChunkBatchSize is the number of chunks read from the
+// database at once when streaming a recording.
+const sessionRecordingChunkBatchSize = 8
+
+func (api *API) fetchSessionRecording(rw http.ResponseWriter, r *http.Request) (database.GetWorkspaceSessionRecordingByIDRow, bool) {
+	ctx := r.Context()
+	id, ok := httpmw.ParseUUIDParam(rw, r, "sessionrecording")
+	if !ok {
+		return database.GetWorkspaceSessionRecordingByIDRow{}, false
+	}
+
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, id)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.GetWorkspaceSessionRecordingByIDRow{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.GetWorkspaceSessionRecordingByIDRow{}, false
+	}
+	return recording, true
+}
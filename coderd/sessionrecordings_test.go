@@ -0,0 +1,132 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	memberClient, member := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+	auditorClient, _ := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID, rbac.RoleAuditor())
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.AsSystemRestricted(ctx), r.Workspace.ID) //nolint:gocritic // Test setup.
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+
+	header := []byte(`{"version":2,"width":80,"height":24}` + "\n")
+	event := []byte(`[0.5,"o","$ "]` + "\n")
+	data := append(slices.Clone(header), event...)
+	// The older recording is uploaded in two chunks to cover streaming
+	// multiple chunks.
+	older := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID: r.Workspace.ID,
+		AgentID:     agents[0].ID,
+		StartedAt:   dbtime.Now().Add(-2 * time.Hour),
+		EndedAt:     dbtime.Now().Add(-time.Hour),
+	}, header, event)
+	newer := dbgen.WorkspaceSessionRecording(t, db, database.WorkspaceSessionRecording{
+		WorkspaceID:    r.Workspace.ID,
+		AgentID:        agents[0].ID,
+		ConnectionType: "Web Terminal",
+		StartedAt:      dbtime.Now().Add(-time.Minute),
+		EndedAt:        dbtime.Now(),
+	}, data)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		recordings, err := auditorClient.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{
+			WorkspaceID: r.Workspace.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, recordings, 2)
+		require.Equal(t, newer.ID, recordings[0].ID)
+		require.Equal(t, older.ID, recordings[1].ID)
+		require.Equal(t, r.Workspace.Name, recordings[0].WorkspaceName)
+		require.Equal(t, member.ID, recordings[0].WorkspaceOwnerID)
+		require.Equal(t, agents[0].Name, recordings[0].AgentName)
+		require.Equal(t, "Web Terminal", recordings[0].ConnectionType)
+		require.Equal(t, int64(len(data)), recordings[0].Size)
+
+		recordings, err = auditorClient.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{
+			ConnectionID: older.ConnectionID,
+		})
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		require.Equal(t, older.ID, recordings[0].ID)
+
+		recordings, err = auditorClient.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{
+			Pagination: codersdk.Pagination{Limit: 1, Offset: 1},
+		})
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		require.Equal(t, older.ID, recordings[0].ID)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		recording, err := auditorClient.WorkspaceSessionRecording(ctx, older.ID)
+		require.NoError(t, err)
+		require.Equal(t, older.ConnectionID, recording.ConnectionID)
+		require.Equal(t, r.Workspace.Name, recording.WorkspaceName)
+
+		cast, err := auditorClient.WorkspaceSessionRecordingCast(ctx, older.ID)
+		require.NoError(t, err)
+		defer cast.Close()
+		got, err := io.ReadAll(cast)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := auditorClient.WorkspaceSessionRecording(ctx, uuid.New())
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+
+	t.Run("WorkspaceOwnerCannotRead", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		recordings, err := memberClient.WorkspaceSessionRecordings(ctx, codersdk.WorkspaceSessionRecordingsRequest{})
+		require.NoError(t, err)
+		require.Empty(t, recordings)
+
+		_, err = memberClient.WorkspaceSessionRecordingCast(ctx, older.ID)
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+	})
+}
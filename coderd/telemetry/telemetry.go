@@ -646,6 +646,17 @@ func (r *remoteReporter) createSnapshot() (*Snapshot, error) {
 		}
 		return nil
 	})
+	eg.Go(func() error {
+		monitors, err := r.options.Database.FetchResourceMonitorsUpdatedAfter(ctx, createdAfter)
+		if err != nil {
+			return xerrors.Errorf("get resource monitors: %w", err)
+		}
+		snapshot.WorkspaceAgentResourceMonitors = make([]WorkspaceAgentResourceMonitor, 0, len(monitors))
+		for _, monitor := range monitors {
+			snapshot.WorkspaceAgentResourceMonitors = append(snapshot.WorkspaceAgentResourceMonitors, ConvertWorkspaceAgentResourceMonitor(monitor))
+		}
+		return nil
+	})
 	eg.Go(func() error {
 		proxies, err := r.options.Database.GetWorkspaceProxies(ctx)
 		if err != nil {
@@ -808,6 +819,17 @@ func ConvertWorkspaceAgentVolumeResourceMonitor(monitor database.WorkspaceAgentV
 	}
 }
 
+func ConvertWorkspaceAgentResourceMonitor(monitor database.WorkspaceAgentResourceMonitor) WorkspaceAgentResourceMonitor {
+	return WorkspaceAgentResourceMonitor{
+		AgentID:   monitor.AgentID,
+		Type:      string(monitor.Type),
+		Enabled:   monitor.Enabled,
+		Threshold: monitor.Threshold,
+		CreatedAt: monitor.CreatedAt,
+		UpdatedAt: monitor.UpdatedAt,
+	}
+}
+
 // ConvertWorkspaceAgentStat anonymizes a workspace agent stat.
 func ConvertWorkspaceAgentStat(stat database.GetWorkspaceAgentStatsRow) WorkspaceAgentStat {
 	return WorkspaceAgentStat{
@@ -1146,6 +1168,7 @@ type Snapshot struct {
 	WorkspaceResources                   []WorkspaceResource                   `json:"workspace_resources"`
 	WorkspaceAgentMemoryResourceMonitors []WorkspaceAgentMemoryResourceMonitor `json:"workspace_agent_memory_resource_monitors"`
 	WorkspaceAgentVolumeResourceMonitors []WorkspaceAgentVolumeResourceMonitor `json:"workspace_agent_volume_resource_monitors"`
+	WorkspaceAgentResourceMonitors       []WorkspaceAgentResourceMonitor       `json:"workspace_agent_resource_monitors"`
 	WorkspaceModules                     []WorkspaceModule                     `json:"workspace_modules"`
 	Workspaces                           []Workspace                           `json:"workspaces"`
 	NetworkEvents                        []NetworkEvent                        `json:"network_events"`
@@ -1295,6 +1318,15 @@ type WorkspaceAgentVolumeResourceMonitor struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type WorkspaceAgentResourceMonitor struct {
+	AgentID   uuid.UUID `json:"agent_id"`
+	Type      string    `json:"type"`
+	Enabled   bool      `json:"enabled"`
+	Threshold int32     `json:"threshold"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type WorkspaceApp struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
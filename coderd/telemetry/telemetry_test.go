@@ -114,6 +114,7 @@ func TestTelemetry(t *testing.T) {
 		_ = dbgen.WorkspaceModule(t, db, database.WorkspaceModule{})
 		_ = dbgen.WorkspaceAgentMemoryResourceMonitor(t, db, database.WorkspaceAgentMemoryResourceMonitor{})
 		_ = dbgen.WorkspaceAgentVolumeResourceMonitor(t, db, database.WorkspaceAgentVolumeResourceMonitor{})
+		_ = dbgen.WorkspaceAgentResourceMonitor(t, db, database.WorkspaceAgentResourceMonitor{})
 
 		_, snapshot := collectSnapshot(t, db, nil)
 		require.Len(t, snapshot.ProvisionerJobs, 1)
@@ -137,6 +138,7 @@ func TestTelemetry(t *testing.T) {
 		require.Len(t, snapshot.TelemetryItems, 2)
 		require.Len(t, snapshot.WorkspaceAgentMemoryResourceMonitors, 1)
 		require.Len(t, snapshot.WorkspaceAgentVolumeResourceMonitors, 1)
+		require.Len(t, snapshot.WorkspaceAgentResourceMonitors, 1)
 		wsa := snapshot.WorkspaceAgents[0]
 		require.Len(t, wsa.Subsystems, 2)
 		require.Equal(t, string(database.WorkspaceAgentSubsystemEnvbox), wsa.Subsystems[0])
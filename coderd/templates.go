@@ -1,8 +1,8 @@
 package coderd
 
 import (
-	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"database/sql"
 	"errors"
@@ -35,7 +35,6 @@ import (
 	"github.com/coder/coder/v2/coderd/workspacestats"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
-	"compress/gzip"
 )
 
 // Returns a single template.
@@ -737,6 +736,14 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.UseClassicParameterFlow != nil {
 		classicTemplateFlow = *req.UseClassicParameterFlow
 	}
+	sessionRecording := template.SessionRecording
+	if req.SessionRecording != nil {
+		sessionRecording = *req.SessionRecording
+	}
+	activityBumpRequiresUserActivity := template.ActivityBumpRequiresUserActivity
+	if req.ActivityBumpRequiresUserActivity != nil {
+		activityBumpRequiresUserActivity = *req.ActivityBumpRequiresUserActivity
+	}
 
 	var updated database.Template
 	err = api.Database.InTx(func(tx database.Store) error {
@@ -758,6 +765,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RequireActiveVersion == template.RequireActiveVersion &&
 			(deprecationMessage == template.Deprecated) &&
 			(classicTemplateFlow == template.UseClassicParameterFlow) &&
+			sessionRecording == template.SessionRecording &&
+			activityBumpRequiresUserActivity == template.ActivityBumpRequiresUserActivity &&
 			maxPortShareLevel == template.MaxPortSharingLevel {
 			return nil
 		}
@@ -790,16 +799,18 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 
 		var err error
 		err = tx.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
-			ID:                           template.ID,
-			UpdatedAt:                    dbtime.Now(),
-			Name:                         name,
-			DisplayName:                  req.DisplayName,
-			Description:                  req.Description,
-			Icon:                         req.Icon,
-			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
-			GroupACL:                     groupACL,
-			MaxPortSharingLevel:          maxPortShareLevel,
-			UseClassicParameterFlow:      classicTemplateFlow,
+			ID:                               template.ID,
+			UpdatedAt:                        dbtime.Now(),
+			Name:                             name,
+			DisplayName:                      req.DisplayName,
+			Description:                      req.Description,
+			Icon:                             req.Icon,
+			AllowUserCancelWorkspaceJobs:     req.AllowUserCancelWorkspaceJobs,
+			GroupACL:                         groupACL,
+			MaxPortSharingLevel:              maxPortShareLevel,
+			UseClassicParameterFlow:          classicTemplateFlow,
+			SessionRecording:                 sessionRecording,
+			ActivityBumpRequiresUserActivity: activityBumpRequiresUserActivity,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -1078,11 +1089,13 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(template.AutostartAllowedDays()),
 		},
 		// These values depend on entitlements and come from the templateAccessControl
-		RequireActiveVersion:    templateAccessControl.RequireActiveVersion,
-		Deprecated:              templateAccessControl.IsDeprecated(),
-		DeprecationMessage:      templateAccessControl.Deprecated,
-		MaxPortShareLevel:       maxPortShareLevel,
-		UseClassicParameterFlow: template.UseClassicParameterFlow,
+		RequireActiveVersion:             templateAccessControl.RequireActiveVersion,
+		Deprecated:                       templateAccessControl.IsDeprecated(),
+		DeprecationMessage:               templateAccessControl.Deprecated,
+		MaxPortShareLevel:                maxPortShareLevel,
+		UseClassicParameterFlow:          template.UseClassicParameterFlow,
+		SessionRecording:                 template.SessionRecording,
+		ActivityBumpRequiresUserActivity: template.ActivityBumpRequiresUserActivity,
 	}
 }
 
@@ -1117,96 +1130,62 @@ func (api *API) exportTemplate(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	template := httpmw.TemplateParam(r)
 
-	// Get the latest version of the template
-	version, err := api.Database.GetTemplateVersionByTemplateIDAndName(ctx, database.GetTemplateVersionByTemplateIDAndNameParams{
-		TemplateID: template.ID,
-		Name:       template.ActiveVersionID.String(),
-	})
+	version, err := api.Database.GetTemplateVersionByID(ctx, template.ActiveVersionID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to get template version.",
+			Message: "Internal error fetching active template version.",
 			Detail:  err.Error(),
 		})
 		return
 	}
-
-	// Create a buffer to store our archive
-	var buf bytes.Buffer
-
-	// Create gzip writer
-	gw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(gw)
-
-	// Add template files to archive
-	files := []struct {
-		Name    string
-		Content string
-	}{
-		{
-			Name:    "main.tf",
-			Content: version.Provisioner,
-		},
-		{
-			Name:    "README.md",
-			Content: template.Description,
-		},
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
 	}
 
-	for _, file := range files {
-		hdr := &tar.Header{
-			Name:    file.Name,
-			Mode:    0644,
-			Size:    int64(len(file.Content)),
-			ModTime: time.Now(),
-			Format:  tar.FormatPAX,
-		}
-
-		if err := tw.WriteHeader(hdr); err != nil {
-			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-				Message: "Failed to write tar header.",
-				Detail:  err.Error(),
-			})
-			return
-		}
-
-		if _, err := tw.Write([]byte(file.Content)); err != nil {
-			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-				Message: "Failed to write file content.",
-				Detail:  err.Error(),
-			})
-			return
-		}
+	// Reading the source archive requires permission to read the file, which
+	// is usually only granted to template admins.
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
 	}
-
-	// Close tar writer
-	if err := tw.Close(); err != nil {
+	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to close tar writer.",
+			Message: "Internal error fetching template version file.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	// Close gzip writer
-	if err := gw.Close(); err != nil {
+	// Source archives are always stored as tar, even if they were uploaded
+	// as zip, so they only need to be compressed.
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(file.Data)
+	if err == nil {
+		err = gw.Close()
+	}
+	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Failed to close gzip writer.",
+			Message: "Internal error compressing template archive.",
 			Detail:  err.Error(),
 		})
 		return
 	}
 
-	// Set response headers
 	rw.Header().Set("Content-Type", "application/x-gzip")
 	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.tar.gz", template.Name))
 	rw.Header().Set("Content-Length", fmt.Sprintf("%d", buf.Len()))
-
-	// Write the archive to the response
+	rw.WriteHeader(http.StatusOK)
 	if _, err := io.Copy(rw, &buf); err != nil {
 		api.Logger.Error(ctx, "failed to write template archive to response",
 			slog.Error(err),
 			slog.F("template_id", template.ID),
 		)
-		return
 	}
 }
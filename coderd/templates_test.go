@@ -1,7 +1,10 @@
 package coderd_test
 
 import (
+	"compress/gzip"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"sync/atomic"
 	"testing"
@@ -1616,6 +1619,34 @@ func TestDeleteTemplate(t *testing.T) {
 	})
 }
 
+func TestExportTemplate(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/export", template.ID), nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "application/x-gzip", res.Header.Get("Content-Type"))
+
+	gr, err := gzip.NewReader(res.Body)
+	require.NoError(t, err)
+	exported, err := io.ReadAll(gr)
+	require.NoError(t, err)
+
+	// The export is the source archive of the active version.
+	source, _, err := client.Download(ctx, version.Job.FileID)
+	require.NoError(t, err)
+	require.Equal(t, source, exported)
+}
+
 func TestTemplateMetrics(t *testing.T) {
 	t.Parallel()
 
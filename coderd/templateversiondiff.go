@@ -0,0 +1,401 @@
+package coderd
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/pkg/diff"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get diff between template versions
+// @ID get-diff-between-template-versions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID to diff from" format(uuid)
+// @Param othertemplateversion path string true "Template version ID to diff to" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionDiff
+// @Router /templateversions/{templateversion}/diff/{othertemplateversion} [get]
+func (api *API) templateVersionDiff(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	from := httpmw.TemplateVersionParam(r)
+
+	toID, ok := httpmw.ParseUUIDParam(rw, r, "othertemplateversion")
+	if !ok {
+		return
+	}
+	to, err := api.Database.GetTemplateVersionByID(ctx, toID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if from.TemplateID != to.TemplateID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Template versions must belong to the same template.",
+		})
+		return
+	}
+
+	fromContents, ok := api.templateVersionContents(rw, r, from)
+	if !ok {
+		return
+	}
+	toContents, ok := api.templateVersionContents(rw, r, to)
+	if !ok {
+		return
+	}
+
+	files, err := diffTemplateVersionFiles(fromContents.files, toContents.files)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error diffing template version files.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.TemplateVersionDiff{
+		FromTemplateVersionID: from.ID,
+		ToTemplateVersionID:   to.ID,
+		Files:                 files,
+		RichParameters:        diffTemplateVersionItems(fromContents.richParameters, toContents.richParameters),
+		Variables:             redactSensitiveVariableChanges(diffTemplateVersionItems(fromContents.variables, toContents.variables), fromContents.sensitive, toContents.sensitive),
+		Presets:               diffTemplateVersionItems(fromContents.presets, toContents.presets),
+		WorkspaceTags:         diffTemplateVersionItems(fromContents.workspaceTags, toContents.workspaceTags),
+	})
+}
+
+// templateVersionItemFields maps the name of an item (such as a parameter) to
+// its fields and their values.
+type templateVersionItemFields map[string]map[string]string
+
+// templateVersionContents holds the parts of a template version that are
+// compared by templateVersionDiff.
+type templateVersionContents struct {
+	files          map[string][]byte
+	richParameters templateVersionItemFields
+	variables      templateVersionItemFields
+	// sensitive is the set of variable names that are sensitive.
+	sensitive     map[string]bool
+	presets       templateVersionItemFields
+	workspaceTags templateVersionItemFields
+}
+
+func (api *API) templateVersionContents(rw http.ResponseWriter, r *http.Request, version database.TemplateVersion) (templateVersionContents, bool) {
+	ctx := r.Context()
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return templateVersionContents{}, false
+	}
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusTooEarly, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q job has not finished.", version.Name),
+		})
+		return templateVersionContents{}, false
+	}
+
+	// Reading the source archive requires permission to read the file, which
+	// is usually only granted to template admins.
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if httpapi.IsUnauthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return templateVersionContents{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version file.",
+			Detail:  err.Error(),
+		})
+		return templateVersionContents{}, false
+	}
+	files, err := readTarFiles(file.Data)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q has an invalid source archive.", version.Name),
+			Detail:  err.Error(),
+		})
+		return templateVersionContents{}, false
+	}
+
+	contents, err := api.templateVersionItems(ctx, version)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return templateVersionContents{}, false
+	}
+	contents.files = files
+	return contents, true
+}
+
+func (api *API) templateVersionItems(ctx context.Context, version database.TemplateVersion) (templateVersionContents, error) {
+	contents := templateVersionContents{
+		richParameters: templateVersionItemFields{},
+		variables:      templateVersionItemFields{},
+		sensitive:      map[string]bool{},
+		presets:        templateVersionItemFields{},
+		workspaceTags:  templateVersionItemFields{},
+	}
+
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, version.ID)
+	if err != nil {
+		return contents, xerrors.Errorf("get parameters: %w", err)
+	}
+	parameters, err := convertTemplateVersionParameters(dbParameters)
+	if err != nil {
+		return contents, xerrors.Errorf("convert parameters: %w", err)
+	}
+	for _, parameter := range parameters {
+		fields, err := jsonFields(parameter)
+		if err != nil {
+			return contents, xerrors.Errorf("parameter %q: %w", parameter.Name, err)
+		}
+		// The plaintext description is derived from the description.
+		delete(fields, "description_plaintext")
+		contents.richParameters[parameter.Name] = fields
+	}
+
+	variables, err := api.Database.GetTemplateVersionVariables(ctx, version.ID)
+	if err != nil {
+		return contents, xerrors.Errorf("get variables: %w", err)
+	}
+	for _, variable := range variables {
+		contents.variables[variable.Name] = map[string]string{
+			"description":   variable.Description,
+			"type":          variable.Type,
+			"value":         variable.Value,
+			"default_value": variable.DefaultValue,
+			"required":      strconv.FormatBool(variable.Required),
+			"sensitive":     strconv.FormatBool(variable.Sensitive),
+		}
+		if variable.Sensitive {
+			contents.sensitive[variable.Name] = true
+		}
+	}
+
+	presets, err := api.Database.GetPresetsByTemplateVersionID(ctx, version.ID)
+	if err != nil {
+		return contents, xerrors.Errorf("get presets: %w", err)
+	}
+	presetParameters, err := api.Database.GetPresetParametersByTemplateVersionID(ctx, version.ID)
+	if err != nil {
+		return contents, xerrors.Errorf("get preset parameters: %w", err)
+	}
+	for _, preset := range presets {
+		fields := map[string]string{}
+		if preset.DesiredInstances.Valid {
+			fields["desired_instances"] = strconv.Itoa(int(preset.DesiredInstances.Int32))
+		}
+		if preset.InvalidateAfterSecs.Valid {
+			fields["invalidate_after_secs"] = strconv.Itoa(int(preset.InvalidateAfterSecs.Int32))
+		}
+		for _, parameter := range presetParameters {
+			if parameter.TemplateVersionPresetID == preset.ID {
+				fields["parameters."+parameter.Name] = parameter.Value
+			}
+		}
+		contents.presets[preset.Name] = fields
+	}
+
+	tags, err := api.Database.GetTemplateVersionWorkspaceTags(ctx, version.ID)
+	if err != nil {
+		return contents, xerrors.Errorf("get workspace tags: %w", err)
+	}
+	for _, tag := range tags {
+		contents.workspaceTags[tag.Key] = map[string]string{"value": tag.Value}
+	}
+	return contents, nil
+}
+
+// jsonFields flattens the top-level fields of v by their JSON names. String
+// values are used as is, anything else is JSON encoded.
+func jsonFields(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(raw))
+	for name, value := range raw {
+		var s string
+		if json.Unmarshal(value, &s) == nil {
+			fields[name] = s
+			continue
+		}
+		fields[name] = string(value)
+	}
+	return fields, nil
+}
+
+func diffTemplateVersionItems(from, to templateVersionItemFields) []codersdk.TemplateVersionItemDiff {
+	diffs := []codersdk.TemplateVersionItemDiff{}
+	for _, name := range sortedKeys(from, to) {
+		fromFields, inFrom := from[name]
+		toFields, inTo := to[name]
+		switch {
+		case !inFrom:
+			diffs = append(diffs, codersdk.TemplateVersionItemDiff{
+				Name:   name,
+				Status: codersdk.TemplateVersionDiffStatusAdded,
+			})
+		case !inTo:
+			diffs = append(diffs, codersdk.TemplateVersionItemDiff{
+				Name:   name,
+				Status: codersdk.TemplateVersionDiffStatusRemoved,
+			})
+		default:
+			var changes []codersdk.TemplateVersionFieldChange
+			for _, field := range sortedKeys(fromFields, toFields) {
+				if fromFields[field] == toFields[field] {
+					continue
+				}
+				changes = append(changes, codersdk.TemplateVersionFieldChange{
+					Field: field,
+					Old:   fromFields[field],
+					New:   toFields[field],
+				})
+			}
+			if len(changes) > 0 {
+				diffs = append(diffs, codersdk.TemplateVersionItemDiff{
+					Name:    name,
+					Status:  codersdk.TemplateVersionDiffStatusModified,
+					Changes: changes,
+				})
+			}
+		}
+	}
+	return diffs
+}
+
+// redactSensitiveVariableChanges hides the values of variables that are
+// sensitive in either version, while still reporting that they changed.
+func redactSensitiveVariableChanges(diffs []codersdk.TemplateVersionItemDiff, fromSensitive, toSensitive map[string]bool) []codersdk.TemplateVersionItemDiff {
+	for _, d := range diffs {
+		if !fromSensitive[d.Name] && !toSensitive[d.Name] {
+			continue
+		}
+		for i, change := range d.Changes {
+			if change.Field != "value" && change.Field != "default_value" {
+				continue
+			}
+			d.Changes[i].Old = redacted
+			d.Changes[i].New = redacted
+		}
+	}
+	return diffs
+}
+
+func diffTemplateVersionFiles(from, to map[string][]byte) ([]codersdk.TemplateVersionFileDiff, error) {
+	diffs := []codersdk.TemplateVersionFileDiff{}
+	for _, name := range sortedKeys(from, to) {
+		fromData, inFrom := from[name]
+		toData, inTo := to[name]
+		fileDiff := codersdk.TemplateVersionFileDiff{
+			Path:   name,
+			Status: codersdk.TemplateVersionDiffStatusModified,
+		}
+		fromName, toName := "a/"+name, "b/"+name
+		switch {
+		case !inFrom:
+			fileDiff.Status = codersdk.TemplateVersionDiffStatusAdded
+			fromName = "/dev/null"
+		case !inTo:
+			fileDiff.Status = codersdk.TemplateVersionDiffStatusRemoved
+			toName = "/dev/null"
+		case bytes.Equal(fromData, toData):
+			continue
+		}
+
+		if isBinary(fromData) || isBinary(toData) {
+			fileDiff.Binary = true
+			diffs = append(diffs, fileDiff)
+			continue
+		}
+		var buf bytes.Buffer
+		err := diff.Text(fromName, toName, fromData, toData, &buf)
+		if err != nil {
+			return nil, xerrors.Errorf("diff %q: %w", name, err)
+		}
+		fileDiff.Diff = buf.String()
+		diffs = append(diffs, fileDiff)
+	}
+	return diffs, nil
+}
+
+// readTarFiles returns the contents of the regular files in a tar archive by
+// their cleaned path.
+func readTarFiles(data []byte) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	reader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = content
+	}
+}
+
+// isBinary reports whether data doesn't look like text. Like git, it checks for
+// NUL bytes near the start, and additionally requires text to be valid UTF-8.
+func isBinary(data []byte) bool {
+	head := data[:min(len(data), 8000)]
+	return bytes.IndexByte(head, 0) != -1 || !utf8.Valid(data)
+}
+
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,192 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionDiff(t *testing.T) {
+	t.Parallel()
+
+	type versionContents struct {
+		files      map[string][]byte
+		variables  []*proto.TemplateVariable
+		parameters []*proto.RichParameter
+		presets    []*proto.Preset
+		tags       map[string]string
+	}
+	responses := func(c versionContents) *echo.Responses {
+		return &echo.Responses{
+			Parse: []*proto.Response{{
+				Type: &proto.Response_Parse{
+					Parse: &proto.ParseComplete{
+						TemplateVariables: c.variables,
+						WorkspaceTags:     c.tags,
+					},
+				},
+			}},
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: c.parameters,
+						Presets:    c.presets,
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+			ExtraFiles:     c.files,
+		}
+	}
+
+	v1 := versionContents{
+		files: map[string][]byte{
+			"main.tf":   []byte("resource \"null_resource\" \"a\" {}\n"),
+			"README.md": []byte("# Template\n"),
+			"logo.png":  {0x89, 'P', 'N', 'G', 0x00, 0x01},
+		},
+		variables: []*proto.TemplateVariable{
+			{Name: "image", Type: "string", DefaultValue: "ubuntu:22.04"},
+			{Name: "token", Type: "string", DefaultValue: "old-secret", Sensitive: true},
+		},
+		parameters: []*proto.RichParameter{
+			{Name: "region", Type: "string", DefaultValue: "us", Mutable: true},
+			{Name: "size", Type: "number", DefaultValue: "10"},
+		},
+		presets: []*proto.Preset{{
+			Name:       "small",
+			Parameters: []*proto.PresetParameter{{Name: "size", Value: "10"}},
+		}},
+		tags: map[string]string{"cluster": "a"},
+	}
+	v2 := versionContents{
+		files: map[string][]byte{
+			"main.tf":  []byte("resource \"null_resource\" \"a\" {}\nresource \"null_resource\" \"b\" {}\n"),
+			"logo.png": {0x89, 'P', 'N', 'G', 0x00, 0x02},
+			"new.sh":   []byte("echo hello\n"),
+		},
+		variables: []*proto.TemplateVariable{
+			{Name: "image", Type: "string", DefaultValue: "ubuntu:24.04"},
+			{Name: "token", Type: "string", DefaultValue: "new-secret", Sensitive: true},
+		},
+		parameters: []*proto.RichParameter{
+			{Name: "region", Type: "string", DefaultValue: "eu", Mutable: true},
+			{Name: "disk", Type: "number", DefaultValue: "20"},
+		},
+		presets: []*proto.Preset{{
+			Name:       "small",
+			Parameters: []*proto.PresetParameter{{Name: "region", Value: "eu"}},
+		}},
+		tags: map[string]string{"cluster": "a"},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v1))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+		version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v2), func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		diff, err := client.TemplateVersionDiff(ctx, version1.ID, version2.ID)
+		require.NoError(t, err)
+		require.Equal(t, version1.ID, diff.FromTemplateVersionID)
+		require.Equal(t, version2.ID, diff.ToTemplateVersionID)
+
+		files := map[string]codersdk.TemplateVersionFileDiff{}
+		for _, file := range diff.Files {
+			files[file.Path] = file
+		}
+		require.Equal(t, codersdk.TemplateVersionDiffStatusRemoved, files["README.md"].Status)
+		require.Contains(t, files["README.md"].Diff, "-# Template")
+		require.Equal(t, codersdk.TemplateVersionDiffStatusAdded, files["new.sh"].Status)
+		require.Contains(t, files["new.sh"].Diff, "+echo hello")
+		require.Equal(t, codersdk.TemplateVersionDiffStatusModified, files["main.tf"].Status)
+		require.Contains(t, files["main.tf"].Diff, "--- a/main.tf")
+		require.Contains(t, files["main.tf"].Diff, "+resource \"null_resource\" \"b\" {}")
+		require.True(t, files["logo.png"].Binary)
+		require.Empty(t, files["logo.png"].Diff)
+
+		require.Equal(t, []codersdk.TemplateVersionItemDiff{
+			{Name: "disk", Status: codersdk.TemplateVersionDiffStatusAdded},
+			{Name: "region", Status: codersdk.TemplateVersionDiffStatusModified, Changes: []codersdk.TemplateVersionFieldChange{
+				{Field: "default_value", Old: "us", New: "eu"},
+			}},
+			{Name: "size", Status: codersdk.TemplateVersionDiffStatusRemoved},
+		}, diff.RichParameters)
+		require.Equal(t, []codersdk.TemplateVersionItemDiff{
+			{Name: "image", Status: codersdk.TemplateVersionDiffStatusModified, Changes: []codersdk.TemplateVersionFieldChange{
+				{Field: "default_value", Old: "ubuntu:22.04", New: "ubuntu:24.04"},
+			}},
+			{Name: "token", Status: codersdk.TemplateVersionDiffStatusModified, Changes: []codersdk.TemplateVersionFieldChange{
+				{Field: "default_value", Old: "*redacted*", New: "*redacted*"},
+			}},
+		}, diff.Variables)
+		require.Equal(t, []codersdk.TemplateVersionItemDiff{
+			{Name: "small", Status: codersdk.TemplateVersionDiffStatusModified, Changes: []codersdk.TemplateVersionFieldChange{
+				{Field: "parameters.region", Old: "", New: "eu"},
+				{Field: "parameters.size", Old: "10", New: ""},
+			}},
+		}, diff.Presets)
+		require.Empty(t, diff.WorkspaceTags)
+
+		// Diffing a version against itself is empty.
+		diff, err = client.TemplateVersionDiff(ctx, version2.ID, version2.ID)
+		require.NoError(t, err)
+		require.True(t, diff.Empty())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v1))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+		version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v2), func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+
+		// Members can read template versions, but not their source.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.TemplateVersionDiff(ctx, version1.ID, version2.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("DifferentTemplates", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v1))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+		_ = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+		version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, responses(v2))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+		_ = coderdtest.CreateTemplate(t, client, owner.OrganizationID, version2.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.TemplateVersionDiff(ctx, version1.ID, version2.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
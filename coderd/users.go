@@ -1568,15 +1568,17 @@ func userOrganizationIDs(ctx context.Context, api *API, user database.User) ([]u
 
 func convertAPIKey(k database.APIKey) codersdk.APIKey {
 	return codersdk.APIKey{
-		ID:              k.ID,
-		UserID:          k.UserID,
-		LastUsed:        k.LastUsed,
-		ExpiresAt:       k.ExpiresAt,
-		CreatedAt:       k.CreatedAt,
-		UpdatedAt:       k.UpdatedAt,
-		LoginType:       codersdk.LoginType(k.LoginType),
-		Scope:           codersdk.APIKeyScope(k.Scope),
-		LifetimeSeconds: k.LifetimeSeconds,
-		TokenName:       k.TokenName,
+		ID:               k.ID,
+		UserID:           k.UserID,
+		LastUsed:         k.LastUsed,
+		ExpiresAt:        k.ExpiresAt,
+		CreatedAt:        k.CreatedAt,
+		UpdatedAt:        k.UpdatedAt,
+		LoginType:        codersdk.LoginType(k.LoginType),
+		Scope:            codersdk.APIKeyScope(k.Scope),
+		LifetimeSeconds:  k.LifetimeSeconds,
+		TokenName:        k.TokenName,
+		ScopePermissions: k.ScopePermissions,
+		ScopeAllowList:   k.ScopeAllowList,
 	}
 }
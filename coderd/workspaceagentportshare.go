@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"slices"
 
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
@@ -31,6 +33,35 @@ func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Updating an existing share is audited as a write, so look it up before
+	// starting the audit request.
+	action := database.AuditActionCreate
+	existing, err := api.Database.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+	})
+	if err == nil {
+		action = database.AuditActionWrite
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceAgentPortShare](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           action,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: portShareAuditFields(workspace),
+	})
+	defer commitAudit()
+	if action == database.AuditActionWrite {
+		aReq.Old = existing
+	}
+
 	if !req.ShareLevel.ValidPortShareLevel() {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Port sharing level not allowed.",
@@ -63,6 +94,23 @@ func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(dbtime.Now()) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Port share expiry must be in the future.",
+				Validations: []codersdk.ValidationError{
+					{
+						Field:  "expires_at",
+						Detail: "Port share expiry must be in the future.",
+					},
+				},
+			})
+			return
+		}
+		expiresAt = sql.NullTime{Time: dbtime.Time(*req.ExpiresAt), Valid: true}
+	}
+
 	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -103,11 +151,13 @@ func (api *API) postWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Requ
 		Port:        req.Port,
 		ShareLevel:  database.AppSharingLevel(req.ShareLevel),
 		Protocol:    database.PortShareProtocol(req.Protocol),
+		ExpiresAt:   expiresAt,
 	})
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	aReq.New = psl
 
 	httpapi.Write(ctx, rw, http.StatusOK, convertPortShare(psl))
 }
@@ -147,12 +197,23 @@ func (api *API) workspaceAgentPortShares(rw http.ResponseWriter, r *http.Request
 func (api *API) deleteWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspace := httpmw.WorkspaceParam(r)
+	auditor := api.Auditor.Load()
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceAgentPortShare](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionDelete,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: portShareAuditFields(workspace),
+	})
+	defer commitAudit()
+
 	var req codersdk.DeleteWorkspaceAgentPortShareRequest
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
 
-	_, err := api.Database.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+	share, err := api.Database.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
 		WorkspaceID: workspace.ID,
 		AgentName:   req.AgentName,
 		Port:        req.Port,
@@ -168,6 +229,7 @@ func (api *API) deleteWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Re
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	aReq.Old = share
 
 	err = api.Database.DeleteWorkspaceAgentPortShare(ctx, database.DeleteWorkspaceAgentPortShareParams{
 		WorkspaceID: workspace.ID,
@@ -182,6 +244,14 @@ func (api *API) deleteWorkspaceAgentPortShare(rw http.ResponseWriter, r *http.Re
 	rw.WriteHeader(http.StatusOK)
 }
 
+func portShareAuditFields(workspace database.Workspace) audit.AdditionalFields {
+	return audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceOwner: workspace.OwnerUsername,
+		WorkspaceID:    workspace.ID,
+	}
+}
+
 func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.WorkspaceAgentPortShare {
 	converted := []codersdk.WorkspaceAgentPortShare{}
 	for _, share := range shares {
@@ -194,11 +264,15 @@ func convertPortShares(shares []database.WorkspaceAgentPortShare) []codersdk.Wor
 }
 
 func convertPortShare(share database.WorkspaceAgentPortShare) codersdk.WorkspaceAgentPortShare {
-	return codersdk.WorkspaceAgentPortShare{
+	converted := codersdk.WorkspaceAgentPortShare{
 		WorkspaceID: share.WorkspaceID,
 		AgentName:   share.AgentName,
 		Port:        share.Port,
 		ShareLevel:  codersdk.WorkspaceAgentPortShareLevel(share.ShareLevel),
 		Protocol:    codersdk.WorkspaceAgentPortShareProtocol(share.Protocol),
 	}
+	if share.ExpiresAt.Valid {
+		converted.ExpiresAt = &share.ExpiresAt.Time
+	}
+	return converted
 }
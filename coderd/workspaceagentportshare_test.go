@@ -2,14 +2,20 @@ package coderd_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
@@ -218,3 +224,111 @@ func TestDeleteWorkspaceAgentPortShare(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestWorkspaceAgentPortShareExpiry(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	ownerClient, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.As(ctx, coderdtest.AuthzUserSubject(user, owner.OrganizationID)), r.Workspace.ID)
+	require.NoError(t, err)
+
+	// expiry in the past should fail
+	_, err = client.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  agents[0].Name,
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		Protocol:   codersdk.WorkspaceAgentPortShareProtocolHTTP,
+		ExpiresAt:  ptr.Ref(dbtime.Now().Add(-time.Minute)),
+	})
+	require.Error(t, err)
+
+	expiresAt := dbtime.Now().Add(time.Hour)
+	ps, err := client.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  agents[0].Name,
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		Protocol:   codersdk.WorkspaceAgentPortShareProtocolHTTP,
+		ExpiresAt:  &expiresAt,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, ps.ExpiresAt)
+	require.WithinDuration(t, expiresAt, *ps.ExpiresAt, time.Second)
+
+	// Expired shares are neither listed nor used to authorize requests.
+	_ = dbgen.WorkspaceAgentPortShare(t, db, database.WorkspaceAgentPortShare{
+		WorkspaceID: r.Workspace.ID,
+		AgentName:   agents[0].Name,
+		Port:        8081,
+		ExpiresAt:   sql.NullTime{Time: dbtime.Now().Add(-time.Minute), Valid: true},
+	})
+	list, err := client.GetWorkspaceAgentPortShares(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, list.Shares, 1)
+	require.EqualValues(t, 8080, list.Shares[0].Port)
+	_, err = db.GetWorkspaceAgentPortShare(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceAgentPortShareParams{
+		WorkspaceID: r.Workspace.ID,
+		AgentName:   agents[0].Name,
+		Port:        8081,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestWorkspaceAgentPortShareAudit(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	auditor := audit.NewMock()
+	ownerClient, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, ownerClient)
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(dbauthz.As(ctx, coderdtest.AuthzUserSubject(user, owner.OrganizationID)), r.Workspace.ID)
+	require.NoError(t, err)
+
+	for _, level := range []codersdk.WorkspaceAgentPortShareLevel{
+		codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+		codersdk.WorkspaceAgentPortShareLevelPublic,
+	} {
+		_, err = client.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  agents[0].Name,
+			Port:       8080,
+			ShareLevel: level,
+			Protocol:   codersdk.WorkspaceAgentPortShareProtocolHTTP,
+		})
+		require.NoError(t, err)
+	}
+	err = client.DeleteWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+		AgentName: agents[0].Name,
+		Port:      8080,
+	})
+	require.NoError(t, err)
+
+	for _, action := range []database.AuditAction{
+		database.AuditActionCreate,
+		database.AuditActionWrite,
+		database.AuditActionDelete,
+	} {
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:         action,
+			ResourceType:   database.ResourceTypeWorkspaceAgentPortShare,
+			ResourceID:     r.Workspace.ID,
+			ResourceTarget: agents[0].Name + ":8080",
+			OrganizationID: owner.OrganizationID,
+			UserID:         user.ID,
+		}), "missing %s audit log", action)
+	}
+}
@@ -1272,11 +1272,26 @@ func convertScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.Worksp
 			StartBlocksLogin: dbScript.StartBlocksLogin,
 			Timeout:          time.Duration(dbScript.TimeoutSeconds) * time.Second,
 			DisplayName:      dbScript.DisplayName,
+			DependsOn:        dbScript.DependsOn,
+			ReadinessProbe:   convertScriptReadinessProbe(dbScript),
 		})
 	}
 	return scripts
 }
 
+func convertScriptReadinessProbe(dbScript database.WorkspaceAgentScript) *codersdk.WorkspaceAgentScriptReadinessProbe {
+	if dbScript.ReadinessProbeCommand == "" && dbScript.ReadinessProbeTCP == "" && dbScript.ReadinessProbeHTTP == "" {
+		return nil
+	}
+	return &codersdk.WorkspaceAgentScriptReadinessProbe{
+		Command:  dbScript.ReadinessProbeCommand,
+		TCP:      dbScript.ReadinessProbeTCP,
+		HTTP:     dbScript.ReadinessProbeHTTP,
+		Interval: time.Duration(dbScript.ReadinessProbeIntervalSeconds) * time.Second,
+		Timeout:  time.Duration(dbScript.ReadinessProbeTimeoutSeconds) * time.Second,
+	}
+}
+
 // @Summary Watch for workspace agent metadata updates
 // @ID watch-for-workspace-agent-metadata-updates
 // @Security CoderSessionToken
@@ -1486,6 +1486,91 @@ func TestWorkspaceAgentPostLogSource(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentUpdateDevcontainerApps(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(a []*proto.Agent) []*proto.Agent {
+		a[0].Apps = []*proto.App{
+			{
+				Slug:        "devcontainer-port-8080",
+				DisplayName: "Template app",
+			},
+		}
+		return a
+	}).Do()
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(r.AgentToken)
+	aAPI, _, err := agentClient.ConnectRPC25(ctx)
+	require.NoError(t, err)
+	defer func() {
+		_ = aAPI.DRPCConn().Close()
+	}()
+
+	getApps := func() map[string]codersdk.WorkspaceApp {
+		workspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		agent, err := client.WorkspaceAgent(ctx, workspace.LatestBuild.Resources[0].Agents[0].ID)
+		require.NoError(t, err)
+		apps := make(map[string]codersdk.WorkspaceApp, len(agent.Apps))
+		for _, app := range agent.Apps {
+			apps[app.Slug] = app
+		}
+		return apps
+	}
+
+	req := &agentproto.UpdateDevcontainerAppsRequest{
+		Ports: []*agentproto.UpdateDevcontainerAppsRequest_Port{
+			{Devcontainer: "project", Port: 3000, Label: "Web"},
+			{Devcontainer: "project", Port: 8443, Label: "API", Protocol: "https"},
+			// The slug of the port is taken by an app of the template.
+			{Devcontainer: "project", Port: 8080, Label: "Other"},
+		},
+	}
+	_, err = aAPI.UpdateDevcontainerApps(ctx, req)
+	require.NoError(t, err)
+	// Sending the same ports again is a no-op.
+	_, err = aAPI.UpdateDevcontainerApps(ctx, req)
+	require.NoError(t, err)
+
+	apps := getApps()
+	require.Len(t, apps, 3)
+	assert.Equal(t, "Template app", apps["devcontainer-port-8080"].DisplayName)
+	assert.Equal(t, "Web", apps["devcontainer-port-3000"].DisplayName)
+	assert.Equal(t, "http://localhost:3000", apps["devcontainer-port-3000"].URL)
+	assert.Equal(t, "API", apps["devcontainer-port-8443"].DisplayName)
+	assert.Equal(t, "https://localhost:8443", apps["devcontainer-port-8443"].URL)
+	assert.Equal(t, codersdk.WorkspaceAppSharingLevelOwner, apps["devcontainer-port-8443"].SharingLevel)
+
+	// Ports that are gone are removed, and changed ports are replaced.
+	_, err = aAPI.UpdateDevcontainerApps(ctx, &agentproto.UpdateDevcontainerAppsRequest{
+		Ports: []*agentproto.UpdateDevcontainerAppsRequest_Port{
+			{Devcontainer: "project", Port: 3000, Label: "Frontend"},
+		},
+	})
+	require.NoError(t, err)
+
+	apps = getApps()
+	require.Len(t, apps, 2)
+	assert.Equal(t, "Template app", apps["devcontainer-port-8080"].DisplayName)
+	assert.Equal(t, "Frontend", apps["devcontainer-port-3000"].DisplayName)
+
+	// The template apps are kept when all ports are gone.
+	_, err = aAPI.UpdateDevcontainerApps(ctx, &agentproto.UpdateDevcontainerAppsRequest{})
+	require.NoError(t, err)
+
+	apps = getApps()
+	require.Len(t, apps, 1)
+	assert.Equal(t, "Template app", apps["devcontainer-port-8080"].DisplayName)
+}
+
 func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	t.Parallel()
 
@@ -2464,7 +2549,7 @@ func requireGetManifest(ctx context.Context, t testing.TB, aAPI agentproto.DRPCA
 }
 
 func postStartup(ctx context.Context, t testing.TB, client agent.Client, startup *agentproto.Startup) error {
-	aAPI, _, err := client.ConnectRPC24(ctx)
+	aAPI, _, err := client.ConnectRPC25(ctx)
 	require.NoError(t, err)
 	defer func() {
 		cErr := aAPI.DRPCConn().Close()
@@ -1783,37 +1783,53 @@ func Run(t *testing.T, appHostIsPrimary bool, factory DeploymentFactory) {
 	t.Run("ReportStats", func(t *testing.T) {
 		t.Parallel()
 
-		reporter := &fakeStatsReporter{}
-		appDetails := setupProxyTest(t, &DeploymentOptions{
-			StatsCollectorOptions: workspaceapps.StatsCollectorOptions{
-				Reporter:       reporter,
-				ReportInterval: time.Hour,
-				RollupWindow:   time.Minute,
-			},
-		})
+		for _, tt := range []struct {
+			name         string
+			fetchMode    string
+			userActivity bool
+		}{
+			{name: "Navigate", fetchMode: "navigate", userActivity: true},
+			{name: "Background", fetchMode: "cors", userActivity: false},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
 
-		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
-		defer cancel()
+				reporter := &fakeStatsReporter{}
+				appDetails := setupProxyTest(t, &DeploymentOptions{
+					StatsCollectorOptions: workspaceapps.StatsCollectorOptions{
+						Reporter:       reporter,
+						ReportInterval: time.Hour,
+						RollupWindow:   time.Minute,
+					},
+				})
 
-		u := appDetails.PathAppURL(appDetails.Apps.Owner)
-		resp, err := requestWithRetries(ctx, t, appDetails.AppClient(t), http.MethodGet, u.String(), nil)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		_, err = io.Copy(io.Discard, resp.Body)
-		require.NoError(t, err)
-		require.Equal(t, http.StatusOK, resp.StatusCode)
+				ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+				defer cancel()
 
-		var stats []workspaceapps.StatsReport
-		require.Eventually(t, func() bool {
-			// Keep flushing until we get a non-empty stats report.
-			appDetails.FlushStats()
-			stats = reporter.stats()
-			return len(stats) > 0
-		}, testutil.WaitLong, testutil.IntervalFast, "stats not reported")
-
-		assert.Equal(t, workspaceapps.AccessMethodPath, stats[0].AccessMethod)
-		assert.Equal(t, "test-app-owner", stats[0].SlugOrPort)
-		assert.Equal(t, 1, stats[0].Requests)
+				u := appDetails.PathAppURL(appDetails.Apps.Owner)
+				resp, err := requestWithRetries(ctx, t, appDetails.AppClient(t), http.MethodGet, u.String(), nil, func(r *http.Request) {
+					r.Header.Set("Sec-Fetch-Mode", tt.fetchMode)
+				})
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				_, err = io.Copy(io.Discard, resp.Body)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var stats []workspaceapps.StatsReport
+				require.Eventually(t, func() bool {
+					// Keep flushing until we get a non-empty stats report.
+					appDetails.FlushStats()
+					stats = reporter.stats()
+					return len(stats) > 0
+				}, testutil.WaitLong, testutil.IntervalFast, "stats not reported")
+
+				assert.Equal(t, workspaceapps.AccessMethodPath, stats[0].AccessMethod)
+				assert.Equal(t, "test-app-owner", stats[0].SlugOrPort)
+				assert.Equal(t, 1, stats[0].Requests)
+				assert.Equal(t, tt.userActivity, stats[0].UserActivity)
+			})
+		}
 	})
 
 	t.Run("WorkspaceOffline", func(t *testing.T) {
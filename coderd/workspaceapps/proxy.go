@@ -600,6 +600,7 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	tracing.EndHTTPSpan(r, http.StatusOK, trace.SpanFromContext(ctx))
 
 	report := newStatsReportFromSignedToken(appToken)
+	report.UserActivity = isUserInitiated(r)
 	defer func() {
 		// We must use defer here because ServeHTTP may panic.
 		report.SessionEndedAt = dbtime.Now()
@@ -657,6 +658,7 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	container := parser.String(values, "", "container")
 	containerUser := parser.String(values, "", "container_user")
 	backendType := parser.String(values, "", "backend_type")
+	readOnly := parser.Boolean(values, false, "read_only")
 	if len(parser.Errors) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid query parameters.",
@@ -699,6 +701,7 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		arp.Container = container
 		arp.ContainerUser = containerUser
 		arp.BackendType = backendType
+		arp.ReadOnly = readOnly
 	})
 	if err != nil {
 		log.Debug(ctx, "dial reconnecting pty server in workspace agent", slog.Error(err))
@@ -725,6 +728,15 @@ func (s *Server) collectStats(stats StatsReport) {
 	}
 }
 
+// isUserInitiated returns true if r is a document navigation or a WebSocket
+// upgrade. Other requests, e.g. to fetch resources or to poll for updates,
+// are made by the page without the user's involvement. Clients that don't
+// send Fetch Metadata headers can't be told apart, so their requests are
+// not user initiated either.
+func isUserInitiated(r *http.Request) bool {
+	return r.Header.Get("Sec-Fetch-Mode") == "navigate" || httpapi.IsWebsocketUpgrade(r)
+}
+
 // wsNetConn wraps net.Conn created by websocket.NetConn(). Cancel func
 // is called if a read or write error is encountered.
 type wsNetConn struct {
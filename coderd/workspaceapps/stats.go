@@ -31,6 +31,11 @@ type StatsReport struct {
 	SessionStartedAt time.Time    `json:"session_started_at"`
 	SessionEndedAt   time.Time    `json:"session_ended_at"` // Updated periodically while app is in use active and when the last connection is closed.
 	Requests         int          `json:"requests"`
+	// UserActivity is true when the report includes requests to the app
+	// that the user initiated, see isUserInitiated. They're real user
+	// activity, as opposed to requests made by a forgotten browser tab,
+	// e.g. to poll for updates.
+	UserActivity bool `json:"user_activity"`
 
 	rolledUp bool // Indicates if this report has been rolled up.
 }
@@ -178,6 +183,8 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 		if group[0].rolledUp {
 			rolledUp = group[0]
 			group = group[1:]
+			// Only activity since the last report is reported again.
+			rolledUp.UserActivity = false
 		} else {
 			rolledUp = &StatsReport{
 				UserID:           g.UserID,
@@ -200,6 +207,7 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 					rolledUp.SessionID = stat.SessionID // Borrow the first session ID, useful in tests.
 				}
 				rolledUp.Requests += stat.Requests
+				rolledUp.UserActivity = rolledUp.UserActivity || stat.UserActivity
 				rollupChanged = true
 				continue
 			}
@@ -220,6 +228,8 @@ func (sc *StatsCollector) rollup(now time.Time) []StatsReport {
 			}
 			report = append(report, r) // Report it (ended or incomplete).
 			if stat.SessionEndedAt.IsZero() {
+				// The activity is reported once, not on every update.
+				stat.UserActivity = false
 				newGroup = append(newGroup, stat) // Keep it for future updates.
 			}
 		}
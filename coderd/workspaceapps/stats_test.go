@@ -83,6 +83,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   end,
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 			want: []workspaceapps.StatsReport{
@@ -91,6 +92,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow),
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 		},
@@ -106,6 +108,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   end,
 					Requests:         1,
+					UserActivity:     true,
 				},
 				{
 					AccessMethod:     workspaceapps.AccessMethodTerminal,
@@ -123,6 +126,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow),
 					Requests:         1,
+					UserActivity:     true,
 				},
 				{
 					AccessMethod:     workspaceapps.AccessMethodTerminal,
@@ -143,8 +147,10 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   end,
 					Requests:         1,
+					UserActivity:     true,
 				},
 				{
+					// A request that the page made in the background.
 					SessionID:        uuid.New(),
 					SessionStartedAt: start,
 					SessionEndedAt:   end,
@@ -157,6 +163,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow),
 					Requests:         2,
+					UserActivity:     true,
 				},
 			},
 		},
@@ -169,6 +176,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionID:        rollupUUID,
 					SessionStartedAt: start,
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 			want: []workspaceapps.StatsReport{
@@ -177,6 +185,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow + time.Second),
 					Requests:         1,
+					UserActivity:     true,
 				},
 				{
 					SessionID:        rollupUUID,
@@ -239,6 +248,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(10 * time.Second),
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 			want: []workspaceapps.StatsReport{
@@ -247,6 +257,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow),
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 		},
@@ -265,6 +276,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow * 2),
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 			want: []workspaceapps.StatsReport{
@@ -273,6 +285,7 @@ func TestStatsCollector(t *testing.T) {
 					SessionStartedAt: start,
 					SessionEndedAt:   start.Add(rollupWindow * 2),
 					Requests:         1,
+					UserActivity:     true,
 				},
 			},
 		},
@@ -340,6 +353,7 @@ func TestStatsCollector(t *testing.T) {
 				assert.Equal(t, want.SessionStartedAt, got.SessionStartedAt, "session started at; i = %d", i)
 				assert.Equal(t, want.SessionEndedAt, got.SessionEndedAt, "session ended at; i = %d", i)
 				assert.Equal(t, want.Requests, got.Requests, "requests; i = %d", i)
+				assert.Equal(t, want.UserActivity, got.UserActivity, "user activity; i = %d", i)
 			}
 		})
 	}
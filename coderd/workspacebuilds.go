@@ -1170,6 +1170,7 @@ func (api *API) buildTimings(ctx context.Context, build database.WorkspaceBuild)
 	}
 	for _, t := range agentScriptTimings {
 		res.AgentScriptTimings = append(res.AgentScriptTimings, codersdk.AgentScriptTiming{
+			QueuedAt:           t.QueuedAt,
 			StartedAt:          t.StartedAt,
 			EndedAt:            t.EndedAt,
 			ExitCode:           t.ExitCode,
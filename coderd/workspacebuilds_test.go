@@ -1676,6 +1676,7 @@ func TestWorkspaceBuildTimings(t *testing.T) {
 			require.Equal(t, genTiming.ExitCode, timingRes.ExitCode)
 			require.Equal(t, string(genTiming.Status), timingRes.Status)
 			require.Equal(t, string(genTiming.Stage), string(timingRes.Stage))
+			require.Equal(t, genTiming.QueuedAt.UnixMilli(), timingRes.QueuedAt.UnixMilli())
 			require.Equal(t, genTiming.StartedAt.UnixMilli(), timingRes.StartedAt.UnixMilli())
 			require.Equal(t, genTiming.EndedAt.UnixMilli(), timingRes.EndedAt.UnixMilli())
 			require.Equal(t, agent.ID.String(), timingRes.WorkspaceAgentID)
@@ -0,0 +1,344 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace ACL
+// @ID get-workspace-acl
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	// Anyone who can read the workspace can see who it is shared with, but
+	// they might not be able to read the users and groups themselves.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	userIDs := make([]uuid.UUID, 0, len(workspace.UserACL))
+	for id := range workspace.UserACL {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, uid)
+	}
+	users := []database.User{}
+	if len(userIDs) > 0 {
+		var err error
+		users, err = api.Database.GetUsersByIDs(sysCtx, userIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+	slices.SortFunc(users, func(a, b database.User) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	acl := codersdk.WorkspaceACL{
+		Users:  make([]codersdk.WorkspaceUser, 0, len(users)),
+		Groups: make([]codersdk.WorkspaceGroup, 0, len(workspace.GroupACL)),
+	}
+	for _, user := range users {
+		acl.Users = append(acl.Users, codersdk.WorkspaceUser{
+			MinimalUser: codersdk.MinimalUser{
+				ID:        user.ID,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
+			},
+			Role: db2sdk.WorkspaceRoleFromActions(workspace.UserACL[user.ID.String()]),
+		})
+	}
+
+	groupIDs := make([]uuid.UUID, 0, len(workspace.GroupACL))
+	for id := range workspace.GroupACL {
+		gid, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		groupIDs = append(groupIDs, gid)
+	}
+	if len(groupIDs) > 0 {
+		groups, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+			OrganizationID: workspace.OrganizationID,
+			GroupIds:       groupIDs,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		slices.SortFunc(groups, func(a, b database.GetGroupsRow) int {
+			return strings.Compare(a.Group.Name, b.Group.Name)
+		})
+		groupMembers, err := api.Database.GetGroupMembersByGroupIDs(sysCtx, database.GetGroupMembersByGroupIDsParams{
+			GroupIds:      groupIDs,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		membersByGroup := make(map[uuid.UUID][]database.GroupMember, len(groups))
+		for _, member := range groupMembers {
+			membersByGroup[member.GroupID] = append(membersByGroup[member.GroupID], member)
+		}
+		for _, group := range groups {
+			members := membersByGroup[group.Group.ID]
+			acl.Groups = append(acl.Groups, codersdk.WorkspaceGroup{
+				Group: db2sdk.Group(group, members, len(members)),
+				Role:  db2sdk.WorkspaceRoleFromActions(workspace.GroupACL[group.Group.ID.String()]),
+			})
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, acl)
+}
+
+// @Summary Get workspace available acl users/groups
+// @ID get-workspace-available-acl-usersgroups
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.ACLAvailable
+// @Router /workspaces/{workspace}/acl/available [get]
+func (api *API) workspaceACLAvailable(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	// Requires update permission on the workspace to list the users and
+	// groups it can be shared with.
+	if !api.Authorize(r, policy.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	// Workspaces can only be shared within their organization, and the
+	// caller might not be able to read its members and groups.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	members, err := api.Database.OrganizationMembers(sysCtx, database.OrganizationMembersParams{
+		OrganizationID: workspace.OrganizationID,
+		IncludeSystem:  false,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		if member.OrganizationMember.UserID == workspace.OwnerID {
+			continue
+		}
+		userIDs = append(userIDs, member.OrganizationMember.UserID)
+	}
+	users := []database.User{}
+	if len(userIDs) > 0 {
+		users, err = api.Database.GetUsersByIDs(sysCtx, userIDs)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+
+	groups, err := api.Database.GetGroups(sysCtx, database.GetGroupsParams{
+		OrganizationID: workspace.OrganizationID,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	sdkGroups := make([]codersdk.Group, 0, len(groups))
+	for _, group := range groups {
+		members, err := api.Database.GetGroupMembersByGroupID(sysCtx, database.GetGroupMembersByGroupIDParams{
+			GroupID:       group.Group.ID,
+			IncludeSystem: false,
+		})
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		sdkGroups = append(sdkGroups, db2sdk.Group(group, members, len(members)))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ACLAvailable{
+		Users:  db2sdk.ReducedUsers(users),
+		Groups: sdkGroups,
+	})
+}
+
+// @Summary Update workspace ACL
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 204
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceTable](rw, &audit.RequestParams{
+			Audit:          *auditor,
+			Log:            api.Logger,
+			Request:        r,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace.WorkspaceTable()
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	validErrs := validateWorkspaceACLRoles(ctx, api.Database, workspace, req.UserRoles, "user_roles", true)
+	validErrs = append(validErrs,
+		validateWorkspaceACLRoles(ctx, api.Database, workspace, req.GroupRoles, "group_roles", false)...)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		if workspace.UserACL == nil {
+			workspace.UserACL = database.WorkspaceACL{}
+		}
+		if workspace.GroupACL == nil {
+			workspace.GroupACL = database.WorkspaceACL{}
+		}
+
+		for id, role := range req.UserRoles {
+			// An empty role implies deletion.
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.UserACL, id)
+				continue
+			}
+			workspace.UserACL[id] = db2sdk.WorkspaceRoleActions(role)
+		}
+		for id, role := range req.GroupRoles {
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.GroupACL, id)
+				continue
+			}
+			workspace.GroupACL[id] = db2sdk.WorkspaceRoleActions(role)
+		}
+
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:       workspace.ID,
+			UserACL:  workspace.UserACL,
+			GroupACL: workspace.GroupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = workspace.WorkspaceTable()
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func validateWorkspaceACLRoles(ctx context.Context, db database.Store, workspace database.Workspace, roles map[string]codersdk.WorkspaceRole, field string, isUser bool) []codersdk.ValidationError {
+	// Validate requires full read access to users and groups.
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var validErrs []codersdk.ValidationError
+	for k, v := range roles {
+		if v != codersdk.WorkspaceRoleDeleted && len(db2sdk.WorkspaceRoleActions(v)) == 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("role %q is not a valid workspace role", v)})
+			continue
+		}
+
+		id, err := uuid.Parse(k)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("ID %q must be a valid UUID.", k)})
+			continue
+		}
+		// Removing an entry must always be possible, even if the user or
+		// group no longer exists.
+		if v == codersdk.WorkspaceRoleDeleted {
+			continue
+		}
+
+		if isUser {
+			if id == workspace.OwnerID {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: "The workspace owner cannot be added to the workspace ACL."})
+				continue
+			}
+			members, err := db.OrganizationMembers(ctx, database.OrganizationMembersParams{
+				OrganizationID: workspace.OrganizationID,
+				UserID:         id,
+			})
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find user with ID %q: %v", k, err.Error())})
+				continue
+			}
+			if len(members) == 0 {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("User %q is not a member of the workspace organization.", k)})
+				continue
+			}
+		} else {
+			group, err := db.GetGroupByID(ctx, id)
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find group with ID %q: %v", k, err.Error())})
+				continue
+			}
+			if group.OrganizationID != workspace.OrganizationID {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Group %q does not belong to the workspace organization.", group.Name)})
+				continue
+			}
+		}
+	}
+
+	return validErrs
+}
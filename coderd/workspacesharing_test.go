@@ -0,0 +1,202 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/workspacesdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ShareWithUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ownerClient, owner := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		sharedClient, shared := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: first.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do().Workspace
+
+		_, err := sharedClient.Workspace(ctx, ws.ID)
+		requireACLStatusCode(t, err, http.StatusNotFound)
+
+		// Other members of the organization can be shared with.
+		available, err := ownerClient.WorkspaceACLAvailable(ctx, ws.ID)
+		require.NoError(t, err)
+		availableIDs := make([]uuid.UUID, 0, len(available.Users))
+		for _, user := range available.Users {
+			availableIDs = append(availableIDs, user.ID)
+		}
+		require.Contains(t, availableIDs, shared.ID)
+		require.NotContains(t, availableIDs, owner.ID)
+
+		err = ownerClient.UpdateWorkspaceACL(ctx, ws.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shared.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := ownerClient.WorkspaceACL(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, shared.ID, acl.Users[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleUse, acl.Users[0].Role)
+		require.Empty(t, acl.Groups)
+
+		// The shared user can see the workspace, but not manage it.
+		_, err = sharedClient.Workspace(ctx, ws.ID)
+		require.NoError(t, err)
+		res, err := sharedClient.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, ws.ID, res.Workspaces[0].ID)
+		err = sharedClient.UpdateWorkspaceACL(ctx, ws.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shared.ID.String(): codersdk.WorkspaceRoleAdmin,
+			},
+		})
+		requireACLStatusCode(t, err, http.StatusForbidden)
+
+		// Removing the user revokes access.
+		err = ownerClient.UpdateWorkspaceACL(ctx, ws.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shared.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		_, err = sharedClient.Workspace(ctx, ws.ID)
+		requireACLStatusCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("ShareWithGroup", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ownerClient, owner := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		sharedClient, shared := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: first.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do().Workspace
+
+		// The "Everyone" group shares the ID of its organization.
+		err := ownerClient.UpdateWorkspaceACL(ctx, ws.ID, codersdk.UpdateWorkspaceACL{
+			GroupRoles: map[string]codersdk.WorkspaceRole{
+				first.OrganizationID.String(): codersdk.WorkspaceRoleAdmin,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := ownerClient.WorkspaceACL(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Groups, 1)
+		require.Equal(t, first.OrganizationID, acl.Groups[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleAdmin, acl.Groups[0].Role)
+		require.True(t, slices.ContainsFunc(acl.Groups[0].Members, func(member codersdk.ReducedUser) bool {
+			return member.ID == shared.ID
+		}))
+
+		// Admins of a workspace can manage it.
+		err = sharedClient.UpdateWorkspaceAutomaticUpdates(ctx, ws.ID, codersdk.UpdateWorkspaceAutomaticUpdatesRequest{
+			AutomaticUpdates: codersdk.AutomaticUpdatesAlways,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ownerClient, owner := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		_, other := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: first.OrganizationID,
+			OwnerID:        owner.ID,
+		}).Do().Workspace
+
+		for _, req := range []codersdk.UpdateWorkspaceACL{
+			{UserRoles: map[string]codersdk.WorkspaceRole{other.ID.String(): "owner"}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{"not-a-uuid": codersdk.WorkspaceRoleUse}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{first.UserID.String() + "0": codersdk.WorkspaceRoleUse}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{owner.ID.String(): codersdk.WorkspaceRoleUse}},
+			{GroupRoles: map[string]codersdk.WorkspaceRole{other.ID.String(): codersdk.WorkspaceRoleUse}},
+		} {
+			err := ownerClient.UpdateWorkspaceACL(ctx, ws.ID, req)
+			requireACLStatusCode(t, err, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("SharedUserConnects", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ownerClient, owner := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		sharedClient, shared := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: first.OrganizationID,
+			OwnerID:        owner.ID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, ownerClient, r.Workspace.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := ownerClient.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shared.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		conn, err := func() (*workspacesdk.AgentConn, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+			return workspacesdk.New(sharedClient).
+				DialAgent(ctx, resources[0].Agents[0].ID, &workspacesdk.DialAgentOptions{
+					Logger: testutil.Logger(t).Named("client"),
+				})
+		}()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		output, err := session.CombinedOutput("echo test")
+		require.NoError(t, err)
+		require.Equal(t, "test", strings.TrimSpace(string(output)))
+	})
+}
+
+func requireACLStatusCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, code, apiErr.StatusCode())
+}
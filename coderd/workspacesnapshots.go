@@ -0,0 +1,512 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/coderd/wspubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Create workspace snapshot
+// @ID create-workspace-snapshot
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceSnapshotRequest true "Create workspace snapshot request"
+// @Success 201 {object} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [post]
+func (api *API) postWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+		auditor   = api.Auditor.Load()
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceSnapshot](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionCreate,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: workspaceSnapshotAuditFields(workspace),
+	})
+	defer commitAudit()
+
+	var req codersdk.CreateWorkspaceSnapshotRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var (
+		build database.WorkspaceBuild
+		err   error
+	)
+	if req.BuildNumber > 0 {
+		build, err = api.Database.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+			WorkspaceID: workspace.ID,
+			BuildNumber: req.BuildNumber,
+		})
+	} else {
+		build, err = api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Workspace build %d does not exist.", req.BuildNumber),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if build.Transition == database.WorkspaceTransitionDelete {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Cannot snapshot a build that deleted the workspace.",
+		})
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, build.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Only successful builds can be snapshotted, build %d is %s.", build.BuildNumber, job.JobStatus),
+		})
+		return
+	}
+
+	params, err := api.Database.GetWorkspaceBuildParameters(ctx, build.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	richParameters, err := json.Marshal(db2sdk.WorkspaceBuildParameters(params))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	snapshot, err := api.Database.InsertWorkspaceSnapshot(ctx, database.InsertWorkspaceSnapshotParams{
+		ID:                uuid.New(),
+		WorkspaceID:       workspace.ID,
+		Name:              req.Name,
+		CreatedAt:         dbtime.Now(),
+		CreatedBy:         apiKey.UserID,
+		BuildNumber:       build.BuildNumber,
+		TemplateVersionID: build.TemplateVersionID,
+		ProvisionerState:  build.ProvisionerState,
+		RichParameters:    richParameters,
+	})
+	if database.IsUniqueViolation(err, database.UniqueWorkspaceSnapshotsWorkspaceIDNameKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = snapshot
+
+	apiSnapshots, err := api.convertWorkspaceSnapshots(ctx, []database.WorkspaceSnapshot{snapshot})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiSnapshots[0])
+}
+
+// @Summary Get workspace snapshots
+// @ID get-workspace-snapshots
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [get]
+func (api *API) workspaceSnapshots(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	snapshots, err := api.Database.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiSnapshots, err := api.convertWorkspaceSnapshots(ctx, snapshots)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, apiSnapshots)
+}
+
+// @Summary Delete workspace snapshot
+// @ID delete-workspace-snapshot
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot name"
+// @Success 204
+// @Router /workspaces/{workspace}/snapshots/{snapshot} [delete]
+func (api *API) deleteWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		auditor   = api.Auditor.Load()
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceSnapshot](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionDelete,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: workspaceSnapshotAuditFields(workspace),
+	})
+	defer commitAudit()
+
+	snapshot, ok := api.workspaceSnapshotParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = snapshot
+
+	err := api.Database.DeleteWorkspaceSnapshotByID(ctx, snapshot.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Restore workspace snapshot
+// @Description Starts a new build of the workspace from the provisioner state,
+// @Description parameters and template version recorded in the snapshot. The
+// @Description latest build of the workspace must have stopped or deleted it.
+// @Description Restoring requires permission to update the template, like
+// @Description any other build with custom state.
+// @ID restore-workspace-snapshot
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot name"
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/snapshots/{snapshot}/restore [post]
+func (api *API) postRestoreWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+		auditor   = api.Auditor.Load()
+	)
+
+	aReq, commitAudit := audit.InitRequest[database.WorkspaceSnapshot](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionStart,
+		OrganizationID:   workspace.OrganizationID,
+		AdditionalFields: workspaceSnapshotAuditFields(workspace),
+	})
+	defer commitAudit()
+
+	snapshot, ok := api.workspaceSnapshotParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = snapshot
+	aReq.New = snapshot
+
+	templateVersion, err := api.Database.GetTemplateVersionByID(ctx, snapshot.TemplateVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if templateVersion.Archived {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q of snapshot %q has been archived and cannot be restored.", templateVersion.Name, snapshot.Name),
+			Detail:  "Unarchive the template version to restore this snapshot.",
+		})
+		return
+	}
+
+	var parameters []codersdk.WorkspaceBuildParameter
+	if err := json.Unmarshal(snapshot.RichParameters, &parameters); err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("unmarshal snapshot parameters: %w", err))
+		return
+	}
+
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+		Initiator(apiKey.UserID).
+		VersionID(snapshot.TemplateVersionID).
+		RichParameterValues(parameters).
+		State(snapshot.ProvisionerState).
+		DeploymentValues(api.Options.DeploymentValues)
+
+	var (
+		workspaceBuild     *database.WorkspaceBuild
+		provisionerJob     *database.ProvisionerJob
+		provisionerDaemons []database.GetEligibleProvisionerDaemonsByProvisionerJobIDsRow
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		latestJob, err := tx.GetProvisionerJobByID(ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest provisioner job: %w", err)
+		}
+		// Restoring over a workspace that is running, or that is being or
+		// failed to be stopped, would orphan the resources it created since
+		// the snapshot was taken.
+		if latestBuild.Transition == database.WorkspaceTransitionStart ||
+			latestJob.JobStatus != database.ProvisionerJobStatusSucceeded {
+			return wsbuilder.BuildError{
+				Status:  http.StatusConflict,
+				Message: "The workspace must be stopped before a snapshot can be restored.",
+				Wrapped: xerrors.Errorf("latest build %s is %s", latestBuild.Transition, latestJob.JobStatus),
+			}
+		}
+
+		workspaceBuild, provisionerJob, provisionerDaemons, err = builder.Build(
+			ctx,
+			tx,
+			func(action policy.Action, object rbac.Objecter) bool {
+				return api.Authorize(r, action, object)
+			},
+			audit.WorkspaceBuildBaggageFromRequest(r),
+		)
+		return err
+	}, nil)
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusForbidden
+		}
+
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+
+		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error restoring workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if provisionerJob != nil {
+		if err := provisionerjobs.PostJob(api.Pubsub, *provisionerJob); err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+			QueuePosition:  0,
+		},
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAppStatus{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		database.TemplateVersion{},
+		provisionerDaemons,
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.OwnerID, wspubsub.WorkspaceEvent{
+		Kind:        wspubsub.WorkspaceEventKindStateChange,
+		WorkspaceID: workspace.ID,
+	})
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
+// workspaceSnapshotParam fetches the snapshot named in the URL, writing an
+// error response if it does not exist.
+func (api *API) workspaceSnapshotParam(rw http.ResponseWriter, r *http.Request) (database.WorkspaceSnapshot, bool) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		name      = chi.URLParam(r, "snapshot")
+	)
+
+	snapshot, err := api.Database.GetWorkspaceSnapshotByWorkspaceIDAndName(ctx, database.GetWorkspaceSnapshotByWorkspaceIDAndNameParams{
+		WorkspaceID: workspace.ID,
+		Name:        name,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Snapshot %q does not exist.", name),
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+func workspaceSnapshotAuditFields(workspace database.Workspace) audit.AdditionalFields {
+	return audit.AdditionalFields{
+		WorkspaceName:  workspace.Name,
+		WorkspaceOwner: workspace.OwnerUsername,
+		WorkspaceID:    workspace.ID,
+	}
+}
+
+func (api *API) convertWorkspaceSnapshots(ctx context.Context, snapshots []database.WorkspaceSnapshot) ([]codersdk.WorkspaceSnapshot, error) {
+	// Snapshots may have been taken by users the caller cannot read, such as
+	// admins the workspace is shared with.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	userIDs := make([]uuid.UUID, 0, len(snapshots))
+	versionIDs := make([]uuid.UUID, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		userIDs = append(userIDs, snapshot.CreatedBy)
+		versionIDs = append(versionIDs, snapshot.TemplateVersionID)
+	}
+	users := map[uuid.UUID]database.User{}
+	if len(userIDs) > 0 {
+		dbUsers, err := api.Database.GetUsersByIDs(sysCtx, userIDs)
+		if err != nil {
+			return nil, xerrors.Errorf("get users: %w", err)
+		}
+		for _, user := range dbUsers {
+			users[user.ID] = user
+		}
+	}
+	versions := map[uuid.UUID]database.TemplateVersion{}
+	if len(versionIDs) > 0 {
+		dbVersions, err := api.Database.GetTemplateVersionsByIDs(sysCtx, versionIDs)
+		if err != nil {
+			return nil, xerrors.Errorf("get template versions: %w", err)
+		}
+		for _, version := range dbVersions {
+			versions[version.ID] = version
+		}
+	}
+
+	apiSnapshots := make([]codersdk.WorkspaceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		var parameters []codersdk.WorkspaceBuildParameter
+		if err := json.Unmarshal(snapshot.RichParameters, &parameters); err != nil {
+			return nil, xerrors.Errorf("unmarshal snapshot parameters: %w", err)
+		}
+		if parameters == nil {
+			parameters = []codersdk.WorkspaceBuildParameter{}
+		}
+		user := users[snapshot.CreatedBy]
+		apiSnapshots = append(apiSnapshots, codersdk.WorkspaceSnapshot{
+			ID:          snapshot.ID,
+			WorkspaceID: snapshot.WorkspaceID,
+			Name:        snapshot.Name,
+			CreatedAt:   snapshot.CreatedAt,
+			CreatedBy: codersdk.MinimalUser{
+				ID:        user.ID,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
+			},
+			BuildNumber:         snapshot.BuildNumber,
+			TemplateVersionID:   snapshot.TemplateVersionID,
+			TemplateVersionName: versions[snapshot.TemplateVersionID].Name,
+			Parameters:          parameters,
+		})
+	}
+	return apiSnapshots, nil
+}
@@ -0,0 +1,233 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	const paramName = "region"
+	snapshotResponses := func(state string) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: []*proto.RichParameter{
+							{Name: paramName, Type: "string", Mutable: true, DefaultValue: "us"},
+						},
+					},
+				},
+			}},
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						State: []byte(state),
+					},
+				},
+			}},
+		}
+	}
+
+	t.Run("CreateAndRestore", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("v1 state"))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID, func(req *codersdk.CreateWorkspaceRequest) {
+			req.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: paramName, Value: "eu"}}
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		snapshot, err := member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-upgrade",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "before-upgrade", snapshot.Name)
+		require.Equal(t, int32(1), snapshot.BuildNumber)
+		require.Equal(t, version.ID, snapshot.TemplateVersionID)
+		require.Equal(t, version.Name, snapshot.TemplateVersionName)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: paramName, Value: "eu"}}, snapshot.Parameters)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionCreate,
+			ResourceType: database.ResourceTypeWorkspaceSnapshot,
+			ResourceID:   snapshot.ID,
+		}))
+
+		// Snapshot names are unique per workspace.
+		_, err = member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-upgrade",
+		})
+		requireACLStatusCode(t, err, http.StatusConflict)
+
+		snapshots, err := member.WorkspaceSnapshots(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, snapshot.ID, snapshots[0].ID)
+
+		// Upgrade the workspace to a new version with different parameters.
+		version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("v2 state"), func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+		coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, version2.ID)
+		build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID:   version2.ID,
+			Transition:          codersdk.WorkspaceTransitionStart,
+			RichParameterValues: []codersdk.WorkspaceBuildParameter{{Name: paramName, Value: "ap"}},
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+		// A running workspace can't be restored.
+		_, err = member.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+		requireACLStatusCode(t, err, http.StatusConflict)
+
+		build = coderdtest.CreateWorkspaceBuild(t, member, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+		// Stop the provisioner so the restored build keeps the state it was
+		// created with.
+		_ = closeDaemon.Close()
+
+		// Restoring provides custom state, so only template admins may do
+		// it.
+		_, err = member.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+		requireACLStatusCode(t, err, http.StatusForbidden)
+
+		build, err = client.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionStart,
+			ResourceType: database.ResourceTypeWorkspaceSnapshot,
+			ResourceID:   snapshot.ID,
+			StatusCode:   http.StatusCreated,
+		}))
+		require.Equal(t, codersdk.WorkspaceTransitionStart, build.Transition)
+		require.Equal(t, version.ID, build.TemplateVersionID)
+
+		state, err := client.WorkspaceBuildState(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, []byte("v1 state"), state)
+
+		params, err := member.WorkspaceBuildParameters(ctx, build.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: paramName, Value: "eu"}}, params)
+	})
+
+	t.Run("PendingStop", func(t *testing.T) {
+		t.Parallel()
+
+		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("v1 state"))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		snapshot, err := member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "v1",
+		})
+		require.NoError(t, err)
+
+		// Without a provisioner the stop build stays pending, so the
+		// workspace may still be running.
+		_ = closeDaemon.Close()
+		_ = coderdtest.CreateWorkspaceBuild(t, member, workspace, database.WorkspaceTransitionStop)
+
+		_, err = member.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+		requireACLStatusCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("ArchivedVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("v1 state"))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		snapshot, err := member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "v1",
+		})
+		require.NoError(t, err)
+
+		version2 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("v2 state"), func(req *codersdk.CreateTemplateVersionRequest) {
+			req.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version2.ID)
+		coderdtest.UpdateActiveTemplateVersion(t, client, template.ID, version2.ID)
+		// Stopping the workspace with the new version leaves the old one
+		// unused, so it can be archived.
+		build := coderdtest.CreateWorkspaceBuild(t, member, workspace, database.WorkspaceTransitionStop, func(req *codersdk.CreateWorkspaceBuildRequest) {
+			req.TemplateVersionID = version2.ID
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+		err = client.SetArchiveTemplateVersion(ctx, version.ID, true)
+		require.NoError(t, err)
+
+		_, err = member.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.Name)
+		requireACLStatusCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, snapshotResponses("state"))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "snapshot",
+		})
+		require.NoError(t, err)
+
+		err = member.DeleteWorkspaceSnapshot(ctx, workspace.ID, "snapshot")
+		require.NoError(t, err)
+		snapshots, err := member.WorkspaceSnapshots(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+
+		err = member.DeleteWorkspaceSnapshot(ctx, workspace.ID, "snapshot")
+		requireACLStatusCode(t, err, http.StatusNotFound)
+		_, err = member.RestoreWorkspaceSnapshot(ctx, workspace.ID, "snapshot")
+		requireACLStatusCode(t, err, http.StatusNotFound)
+	})
+}
@@ -36,7 +36,11 @@ import (
 // A way to avoid this is to configure the max deadline to something that will not
 // span more than 1 day. This will force the workspace to restart and reset the deadline
 // each morning when it autostarts.
-func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID, nextAutostart time.Time) {
+//
+// userActivity is whether the agent saw real user activity, see
+// agentactivity. Workspaces of templates that require it are only bumped
+// when it's set.
+func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID, nextAutostart time.Time, userActivity bool) {
 	// We set a short timeout so if the app is under load, these
 	// low priority operations fail first.
 	ctx, cancel := context.WithTimeout(ctx, time.Second*15)
@@ -44,6 +48,7 @@ func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Sto
 	err := db.ActivityBumpWorkspace(ctx, database.ActivityBumpWorkspaceParams{
 		NextAutostart: nextAutostart.UTC(),
 		WorkspaceID:   workspaceID,
+		UserActivity:  userActivity,
 	})
 	if err != nil {
 		if !xerrors.Is(err, context.Canceled) && !database.IsQueryCanceledError(err) {
@@ -57,5 +62,6 @@ func ActivityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Sto
 
 	log.Debug(ctx, "bumped deadline from activity",
 		slog.F("workspace_id", workspaceID),
+		slog.F("user_activity", userActivity),
 	)
 }
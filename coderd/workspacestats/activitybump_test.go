@@ -42,6 +42,8 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 		templateTTL                   time.Duration
 		templateActivityBump          time.Duration
 		templateDisallowsUserAutostop bool
+		templateRequiresUserActivity  bool
+		userActivity                  bool
 		expectedBump                  time.Duration
 		// If the tests get queued, we need to be able to set the next autostart
 		// based on the actual time the unit test is running.
@@ -157,6 +159,27 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 			templateActivityBump: -1, // negative values get changed to 0 in the test
 			expectedBump:         0,
 		},
+		{
+			// Open connections don't bump if the template requires user
+			// activity.
+			name:                         "TemplateRequiresUserActivity",
+			transition:                   database.WorkspaceTransitionStart,
+			jobCompletedAt:               sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+			buildDeadlineOffset:          ptr.Ref(-30 * time.Minute),
+			workspaceTTL:                 8 * time.Hour,
+			templateRequiresUserActivity: true,
+			expectedBump:                 0,
+		},
+		{
+			name:                         "TemplateRequiresUserActivityWithActivity",
+			transition:                   database.WorkspaceTransitionStart,
+			jobCompletedAt:               sql.NullTime{Valid: true, Time: dbtime.Now().Add(-30 * time.Minute)},
+			buildDeadlineOffset:          ptr.Ref(-30 * time.Minute),
+			workspaceTTL:                 8 * time.Hour,
+			templateRequiresUserActivity: true,
+			userActivity:                 true,
+			expectedBump:                 time.Hour,
+		},
 	} {
 		tt := tt
 		for _, tz := range timezones {
@@ -220,6 +243,16 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 					DefaultTTL:        int64(tt.templateTTL),
 					ActivityBump:      int64(activityBump),
 				}), "unexpected error updating template schedule")
+				if tt.templateRequiresUserActivity {
+					require.NoError(t, db.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
+						ID:                               template.ID,
+						UpdatedAt:                        dbtime.Now(),
+						Name:                             template.Name,
+						GroupACL:                         template.GroupACL,
+						MaxPortSharingLevel:              template.MaxPortSharingLevel,
+						ActivityBumpRequiresUserActivity: true,
+					}), "unexpected error updating template meta")
+				}
 
 				var buildNumber int32 = 1
 				// Insert a number of previous workspace builds.
@@ -271,7 +304,7 @@ func Test_ActivityBumpWorkspace(t *testing.T) {
 
 				// Bump duration is measured from the time of the bump, so we measure from here.
 				start := dbtime.Now()
-				workspacestats.ActivityBumpWorkspace(ctx, log, db, bld.WorkspaceID, nextAutostart(start))
+				workspacestats.ActivityBumpWorkspace(ctx, log, db, bld.WorkspaceID, nextAutostart(start), tt.userActivity)
 				end := dbtime.Now()
 
 				// Validate our state after bump
@@ -30,15 +30,25 @@ type ReporterOptions struct {
 	StatsBatcher          Batcher
 	UsageTracker          *UsageTracker
 	UpdateAgentMetricsFn  func(ctx context.Context, labels prometheusmetrics.AgentMetricLabels, metrics []*agentproto.Stats_Metric)
+	// UserActivityWindow is how recent the last user activity reported by
+	// an agent must be to count as activity. It should cover the interval
+	// at which agents report stats. Defaults to DefaultUserActivityWindow.
+	UserActivityWindow time.Duration
 
 	AppStatBatchSize int
 }
 
+// DefaultUserActivityWindow is the default ReporterOptions.UserActivityWindow.
+const DefaultUserActivityWindow = time.Minute
+
 type Reporter struct {
 	opts ReporterOptions
 }
 
 func NewReporter(opts ReporterOptions) *Reporter {
+	if opts.UserActivityWindow == 0 {
+		opts.UserActivityWindow = DefaultUserActivityWindow
+	}
 	return &Reporter{opts: opts}
 }
 
@@ -116,9 +126,65 @@ func (r *Reporter) ReportAppStats(ctx context.Context, stats []workspaceapps.Sta
 		return xerrors.Errorf("insert workspace app stats failed: %w", err)
 	}
 
+	// User initiated requests to apps are user activity, so they bump the
+	// deadline even if the template requires user activity, see
+	// StatsReport.UserActivity.
+	var activeWorkspaceIDs []uuid.UUID
+	for _, stat := range stats {
+		if stat.UserActivity {
+			activeWorkspaceIDs = append(activeWorkspaceIDs, stat.WorkspaceID)
+		}
+	}
+	if len(activeWorkspaceIDs) == 0 {
+		return nil
+	}
+	rows, err := r.opts.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		WorkspaceIds: slice.Unique(activeWorkspaceIDs),
+	})
+	if err != nil {
+		r.opts.Logger.Warn(ctx, "failed to get workspaces to bump activity",
+			slog.F("workspace_ids", activeWorkspaceIDs),
+			slog.Error(err),
+		)
+		return nil
+	}
+	now := dbtime.Now()
+	for _, workspace := range database.ConvertWorkspaceRows(rows) {
+		ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, r.nextAutostart(ctx, now, workspace), true)
+	}
+
 	return nil
 }
 
+// nextAutostart returns the next autostart of the workspace after now, or
+// the zero time if it has none or the template schedule fails to load.
+func (r *Reporter) nextAutostart(ctx context.Context, now time.Time, workspace database.Workspace) time.Time {
+	if workspace.AutostartSchedule.String == "" {
+		return time.Time{}
+	}
+	templateSchedule, err := (*(r.opts.TemplateScheduleStore.Load())).Get(ctx, r.opts.Database, workspace.TemplateID)
+	// If the template schedule fails to load, just default to bumping
+	// without the next transition and log it.
+	switch {
+	case err == nil:
+		next, allowed := schedule.NextAutostart(now, workspace.AutostartSchedule.String, templateSchedule)
+		if allowed {
+			return next
+		}
+	case database.IsQueryCanceledError(err):
+		r.opts.Logger.Debug(ctx, "query canceled while loading template schedule",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("template_id", workspace.TemplateID))
+	default:
+		r.opts.Logger.Error(ctx, "failed to load template schedule bumping activity, defaulting to bumping by 60min",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("template_id", workspace.TemplateID),
+			slog.Error(err),
+		)
+	}
+	return time.Time{}
+}
+
 // nolint:revive // usage is a control flag while we have the experiment
 func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspace database.Workspace, workspaceAgent database.WorkspaceAgent, templateName string, stats *agentproto.Stats, usage bool) error {
 	// update agent stats
@@ -150,32 +216,16 @@ func (r *Reporter) ReportAgentStats(ctx context.Context, now time.Time, workspac
 	}
 
 	// check next autostart
-	var nextAutostart time.Time
-	if workspace.AutostartSchedule.String != "" {
-		templateSchedule, err := (*(r.opts.TemplateScheduleStore.Load())).Get(ctx, r.opts.Database, workspace.TemplateID)
-		// If the template schedule fails to load, just default to bumping
-		// without the next transition and log it.
-		switch {
-		case err == nil:
-			next, allowed := schedule.NextAutostart(now, workspace.AutostartSchedule.String, templateSchedule)
-			if allowed {
-				nextAutostart = next
-			}
-		case database.IsQueryCanceledError(err):
-			r.opts.Logger.Debug(ctx, "query canceled while loading template schedule",
-				slog.F("workspace_id", workspace.ID),
-				slog.F("template_id", workspace.TemplateID))
-		default:
-			r.opts.Logger.Error(ctx, "failed to load template schedule bumping activity, defaulting to bumping by 60min",
-				slog.F("workspace_id", workspace.ID),
-				slog.F("template_id", workspace.TemplateID),
-				slog.Error(err),
-			)
-		}
-	}
+	nextAutostart := r.nextAutostart(ctx, now, workspace)
+
+	// Templates may require user activity, as opposed to open connections,
+	// to bump the deadline. Agents report the time since the last activity
+	// rather than its time, so their clock doesn't matter. Agents that don't
+	// report it have none.
+	userActivity := stats.TimeSinceLastActivity != nil && stats.TimeSinceLastActivity.AsDuration() <= r.opts.UserActivityWindow
 
 	// bump workspace activity
-	ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, nextAutostart)
+	ActivityBumpWorkspace(ctx, r.opts.Logger.Named("activity_bump"), r.opts.Database, workspace.ID, nextAutostart, userActivity)
 
 	// bump workspace last_used_at
 	r.opts.UsageTracker.Add(workspace.ID)
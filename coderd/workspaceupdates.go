@@ -305,7 +305,8 @@ func (r *rbacAuthorizer) AuthorizeTunnel(ctx context.Context, agentID uuid.UUID)
 	if err != nil {
 		return xerrors.Errorf("get workspace by agent ID: %w", err)
 	}
-	// Authorizes against `ActionSSH`
+	// Authorizes against `ActionSSH`. The object carries the workspace ACL,
+	// so users and groups the workspace is shared with are allowed too.
 	return r.sshPrep.Authorize(ctx, ws.RBACObject())
 }
 
@@ -56,6 +56,7 @@ const (
 	WorkspaceEventKindAgentLogsOverflow     WorkspaceEventKind = "agt_logs_overflow"
 	WorkspaceEventKindAgentTimeout          WorkspaceEventKind = "agt_timeout"
 	WorkspaceEventKindAgentAppStatusUpdate  WorkspaceEventKind = "agt_app_status_update"
+	WorkspaceEventKindAgentAppsUpdate       WorkspaceEventKind = "agt_apps_update"
 )
 
 func (w *WorkspaceEvent) Validate() error {
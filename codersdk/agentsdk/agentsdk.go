@@ -47,6 +47,7 @@ const (
 	ConnectionTypeVSCode          ConnectionType = "VS Code"
 	ConnectionTypeJetBrains       ConnectionType = "JetBrains"
 	ConnectionTypeReconnectingPTY ConnectionType = "Web Terminal"
+	ConnectionTypePortForwarding  ConnectionType = "Port Forwarding"
 )
 
 // New returns a client that is used to interact with the
@@ -125,6 +126,11 @@ type Manifest struct {
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
 	Devcontainers            []codersdk.WorkspaceAgentDevcontainer        `json:"devcontainers"`
+	// SessionRecording is true if interactive sessions must be recorded.
+	SessionRecording bool `json:"session_recording"`
+	// PortForwardingPolicy restricts the ports and Unix sockets clients can
+	// connect to. All are allowed if it is nil.
+	PortForwardingPolicy *PortForwardingPolicy `json:"port_forwarding_policy,omitempty"`
 }
 
 type LogSource struct {
@@ -257,6 +263,18 @@ func (c *Client) ConnectRPC24(ctx context.Context) (
 	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
 }
 
+// ConnectRPC25 returns a dRPC client to the Agent API v2.5.  It is useful when you want to be
+// maximally compatible with Coderd Release Versions from 2.xx+
+func (c *Client) ConnectRPC25(ctx context.Context) (
+	proto.DRPCAgentClient25, tailnetproto.DRPCTailnetClient25, error,
+) {
+	conn, err := c.connectRPCVersion(ctx, apiversion.New(2, 5))
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewDRPCAgentClient(conn), tailnetproto.NewDRPCTailnetClient(conn), nil
+}
+
 // ConnectRPC connects to the workspace agent API and tailnet API
 func (c *Client) ConnectRPC(ctx context.Context) (drpc.Conn, error) {
 	return c.connectRPCVersion(ctx, proto.CurrentVersion)
@@ -53,6 +53,8 @@ func ManifestFromProto(manifest *proto.Manifest) (Manifest, error) {
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		Metadata:                 MetadataDescriptionsFromProto(manifest.Metadata),
 		Devcontainers:            devcontainers,
+		SessionRecording:         manifest.SessionRecording,
+		PortForwardingPolicy:     PortForwardingPolicyFromProto(manifest.PortForwardingPolicy),
 	}, nil
 }
 
@@ -80,6 +82,8 @@ func ProtoFromManifest(manifest Manifest) (*proto.Manifest, error) {
 		Apps:                     apps,
 		Metadata:                 ProtoFromMetadataDescriptions(manifest.Metadata),
 		Devcontainers:            ProtoFromDevcontainers(manifest.Devcontainers),
+		SessionRecording:         manifest.SessionRecording,
+		PortForwardingPolicy:     ProtoFromPortForwardingPolicy(manifest.PortForwardingPolicy),
 	}, nil
 }
 
@@ -186,9 +190,24 @@ func AgentScriptFromProto(protoScript *proto.WorkspaceAgentScript) (codersdk.Wor
 		StartBlocksLogin: protoScript.StartBlocksLogin,
 		Timeout:          protoScript.Timeout.AsDuration(),
 		DisplayName:      protoScript.DisplayName,
+		DependsOn:        protoScript.DependsOn,
+		ReadinessProbe:   AgentScriptReadinessProbeFromProto(protoScript.ReadinessProbe),
 	}, nil
 }
 
+func AgentScriptReadinessProbeFromProto(p *proto.WorkspaceAgentScript_ReadinessProbe) *codersdk.WorkspaceAgentScriptReadinessProbe {
+	if p == nil {
+		return nil
+	}
+	return &codersdk.WorkspaceAgentScriptReadinessProbe{
+		Command:  p.Command,
+		TCP:      p.Tcp,
+		HTTP:     p.Http,
+		Interval: p.Interval.AsDuration(),
+		Timeout:  p.Timeout.AsDuration(),
+	}
+}
+
 func ProtoFromScript(s codersdk.WorkspaceAgentScript) *proto.WorkspaceAgentScript {
 	return &proto.WorkspaceAgentScript{
 		Id:               s.ID[:],
@@ -201,6 +220,21 @@ func ProtoFromScript(s codersdk.WorkspaceAgentScript) *proto.WorkspaceAgentScrip
 		StartBlocksLogin: s.StartBlocksLogin,
 		Timeout:          durationpb.New(s.Timeout),
 		DisplayName:      s.DisplayName,
+		DependsOn:        s.DependsOn,
+		ReadinessProbe:   ProtoFromScriptReadinessProbe(s.ReadinessProbe),
+	}
+}
+
+func ProtoFromScriptReadinessProbe(p *codersdk.WorkspaceAgentScriptReadinessProbe) *proto.WorkspaceAgentScript_ReadinessProbe {
+	if p == nil {
+		return nil
+	}
+	return &proto.WorkspaceAgentScript_ReadinessProbe{
+		Command:  p.Command,
+		Tcp:      p.TCP,
+		Http:     p.HTTP,
+		Interval: durationpb.New(p.Interval),
+		Timeout:  durationpb.New(p.Timeout),
 	}
 }
 
@@ -410,6 +444,8 @@ func ConnectionTypeFromProto(typ proto.Connection_Type) (ConnectionType, error)
 		return ConnectionTypeJetBrains, nil
 	case proto.Connection_RECONNECTING_PTY:
 		return ConnectionTypeReconnectingPTY, nil
+	case proto.Connection_PORT_FORWARDING:
+		return ConnectionTypePortForwarding, nil
 	default:
 		return "", xerrors.Errorf("unknown connection type %q", typ)
 	}
@@ -427,6 +463,8 @@ func ProtoFromConnectionType(typ ConnectionType) (proto.Connection_Type, error)
 		return proto.Connection_JETBRAINS, nil
 	case ConnectionTypeReconnectingPTY:
 		return proto.Connection_RECONNECTING_PTY, nil
+	case ConnectionTypePortForwarding:
+		return proto.Connection_PORT_FORWARDING, nil
 	default:
 		return 0, xerrors.Errorf("unknown connection type %q", typ)
 	}
@@ -128,6 +128,12 @@ func TestManifest(t *testing.T) {
 				StartBlocksLogin: true,
 				Timeout:          time.Second * 4,
 				DisplayName:      "bar",
+				DependsOn:        []string{"foo"},
+				ReadinessProbe: &codersdk.WorkspaceAgentScriptReadinessProbe{
+					TCP:      "localhost:8080",
+					Interval: time.Second,
+					Timeout:  time.Minute,
+				},
 			},
 		},
 		Devcontainers: []codersdk.WorkspaceAgentDevcontainer{
@@ -137,6 +143,13 @@ func TestManifest(t *testing.T) {
 				ConfigPath:      "/home/coder/coder/.devcontainer/devcontainer.json",
 			},
 		},
+		SessionRecording: true,
+		PortForwardingPolicy: &agentsdk.PortForwardingPolicy{
+			AllowedPorts:       []string{"3000-3999", "8080"},
+			DeniedPorts:        []string{"3306"},
+			AllowedUnixSockets: []string{"/run/user/*/*.sock"},
+			DeniedUnixSockets:  []string{"/var/run/docker.sock"},
+		},
 	}
 	p, err := agentsdk.ProtoFromManifest(manifest)
 	require.NoError(t, err)
@@ -160,6 +173,8 @@ func TestManifest(t *testing.T) {
 	require.Equal(t, manifest.Metadata, back.Metadata)
 	require.Equal(t, manifest.Scripts, back.Scripts)
 	require.Equal(t, manifest.Devcontainers, back.Devcontainers)
+	require.Equal(t, manifest.SessionRecording, back.SessionRecording)
+	require.Equal(t, manifest.PortForwardingPolicy, back.PortForwardingPolicy)
 }
 
 func TestSubsystems(t *testing.T) {
@@ -0,0 +1,176 @@
+package agentsdk
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/proto"
+)
+
+// PortForwardingPolicy restricts the TCP ports and Unix sockets in the
+// workspace that clients can connect to through the agent, whether by
+// forwarding them over SSH or by dialing them over the tailnet.
+//
+// Ports are single numbers like "8080" or inclusive ranges like "3000-3999".
+// Unix sockets are path patterns as understood by path.Match. They are matched
+// against absolute socket paths with symlinks resolved. Denied entries take
+// precedence over allowed ones, and an empty allow list allows everything that
+// is not denied.
+type PortForwardingPolicy struct {
+	AllowedPorts       []string `json:"allowed_ports"`
+	DeniedPorts        []string `json:"denied_ports"`
+	AllowedUnixSockets []string `json:"allowed_unix_sockets"`
+	DeniedUnixSockets  []string `json:"denied_unix_sockets"`
+}
+
+// Validate returns an error if any of the entries in the policy cannot be
+// parsed.
+func (p PortForwardingPolicy) Validate() error {
+	for _, ports := range [][]string{p.AllowedPorts, p.DeniedPorts} {
+		for _, s := range ports {
+			if _, err := ParsePortRange(s); err != nil {
+				return err
+			}
+		}
+	}
+	for _, patterns := range [][]string{p.AllowedUnixSockets, p.DeniedUnixSockets} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return xerrors.Errorf("invalid unix socket pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AllowsPort returns whether clients may connect to the given TCP port. A nil
+// policy allows every port.
+func (p *PortForwardingPolicy) AllowsPort(port uint16) bool {
+	if p == nil {
+		return true
+	}
+	if matchesPort(p.DeniedPorts, port) {
+		return false
+	}
+	return len(p.AllowedPorts) == 0 || matchesPort(p.AllowedPorts, port)
+}
+
+// AllowsUnixSocket returns whether clients may connect to the Unix socket at
+// the given path. A nil policy allows every socket, any other policy rejects
+// relative paths since they depend on the working directory of the agent.
+//
+// Symlinks in the path are resolved so that a link cannot be used to reach a
+// denied socket or to escape the allowed ones. The path is denied if either
+// the path itself or its target matches a denied pattern, and only allowed if
+// the target matches an allowed pattern.
+func (p *PortForwardingPolicy) AllowsUnixSocket(socketPath string) bool {
+	if p == nil {
+		return true
+	}
+	if !filepath.IsAbs(socketPath) {
+		return false
+	}
+	socketPath = filepath.Clean(socketPath)
+	resolved, err := filepath.EvalSymlinks(socketPath)
+	if err != nil {
+		// The socket doesn't exist (yet), so connecting to it fails
+		// regardless of the policy.
+		resolved = socketPath
+	}
+	if matchesUnixSocket(p.DeniedUnixSockets, socketPath) || matchesUnixSocket(p.DeniedUnixSockets, resolved) {
+		return false
+	}
+	return len(p.AllowedUnixSockets) == 0 || matchesUnixSocket(p.AllowedUnixSockets, resolved)
+}
+
+func matchesPort(ranges []string, port uint16) bool {
+	for _, s := range ranges {
+		// Entries are validated before they are stored, so any that fail
+		// to parse here never match.
+		r, err := ParsePortRange(s)
+		if err == nil && r.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesUnixSocket(patterns []string, socketPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, socketPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PortRange is an inclusive range of TCP ports.
+type PortRange struct {
+	Start uint16
+	End   uint16
+}
+
+// ParsePortRange parses a single port like "8080" or an inclusive range like
+// "3000-3999".
+func ParsePortRange(s string) (PortRange, error) {
+	start, end, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	startPort, err := parsePort(start)
+	if err != nil {
+		return PortRange{}, xerrors.Errorf("invalid port range %q: %w", s, err)
+	}
+	if !isRange {
+		return PortRange{Start: startPort, End: startPort}, nil
+	}
+	endPort, err := parsePort(end)
+	if err != nil {
+		return PortRange{}, xerrors.Errorf("invalid port range %q: %w", s, err)
+	}
+	if endPort < startPort {
+		return PortRange{}, xerrors.Errorf("invalid port range %q: end is before start", s)
+	}
+	return PortRange{Start: startPort, End: endPort}, nil
+}
+
+func parsePort(s string) (uint16, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(s), 10, 16)
+	if err != nil {
+		return 0, xerrors.Errorf("parse port %q: %w", s, err)
+	}
+	if port == 0 {
+		return 0, xerrors.New("port must be greater than 0")
+	}
+	return uint16(port), nil
+}
+
+// Contains returns whether the port is in the range.
+func (r PortRange) Contains(port uint16) bool {
+	return port >= r.Start && port <= r.End
+}
+
+func PortForwardingPolicyFromProto(policy *proto.PortForwardingPolicy) *PortForwardingPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &PortForwardingPolicy{
+		AllowedPorts:       policy.GetAllowedPorts(),
+		DeniedPorts:        policy.GetDeniedPorts(),
+		AllowedUnixSockets: policy.GetAllowedUnixSockets(),
+		DeniedUnixSockets:  policy.GetDeniedUnixSockets(),
+	}
+}
+
+func ProtoFromPortForwardingPolicy(policy *PortForwardingPolicy) *proto.PortForwardingPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &proto.PortForwardingPolicy{
+		AllowedPorts:       policy.AllowedPorts,
+		DeniedPorts:        policy.DeniedPorts,
+		AllowedUnixSockets: policy.AllowedUnixSockets,
+		DeniedUnixSockets:  policy.DeniedUnixSockets,
+	}
+}
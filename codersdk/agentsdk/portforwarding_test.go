@@ -0,0 +1,152 @@
+package agentsdk_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestParsePortRange(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input string
+		want  agentsdk.PortRange
+		err   bool
+	}{
+		{input: "8080", want: agentsdk.PortRange{Start: 8080, End: 8080}},
+		{input: "3000-3999", want: agentsdk.PortRange{Start: 3000, End: 3999}},
+		{input: " 1 - 65535 ", want: agentsdk.PortRange{Start: 1, End: 65535}},
+		{input: "", err: true},
+		{input: "0", err: true},
+		{input: "65536", err: true},
+		{input: "http", err: true},
+		{input: "3999-3000", err: true},
+		{input: "3000-", err: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+			got, err := agentsdk.ParsePortRange(tc.input)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPortForwardingPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+		var policy *agentsdk.PortForwardingPolicy
+		require.True(t, policy.AllowsPort(22))
+		require.True(t, policy.AllowsUnixSocket("/var/run/docker.sock"))
+	})
+
+	t.Run("Ports", func(t *testing.T) {
+		t.Parallel()
+		policy := &agentsdk.PortForwardingPolicy{
+			AllowedPorts: []string{"3000-3999", "8080"},
+			DeniedPorts:  []string{"3306"},
+		}
+		require.True(t, policy.AllowsPort(3000))
+		require.True(t, policy.AllowsPort(3999))
+		require.True(t, policy.AllowsPort(8080))
+		require.False(t, policy.AllowsPort(22))
+		require.False(t, policy.AllowsPort(4000))
+
+		policy.DeniedPorts = []string{"3500-3600"}
+		require.False(t, policy.AllowsPort(3500))
+		require.True(t, policy.AllowsPort(3499))
+	})
+
+	t.Run("DenyOnly", func(t *testing.T) {
+		t.Parallel()
+		policy := &agentsdk.PortForwardingPolicy{
+			DeniedPorts:       []string{"22", "5432"},
+			DeniedUnixSockets: []string{"/var/run/docker.sock"},
+		}
+		require.False(t, policy.AllowsPort(22))
+		require.False(t, policy.AllowsPort(5432))
+		require.True(t, policy.AllowsPort(8080))
+		require.False(t, policy.AllowsUnixSocket("/var/run/docker.sock"))
+		require.False(t, policy.AllowsUnixSocket("/var/run/../run/docker.sock"))
+		require.True(t, policy.AllowsUnixSocket("/tmp/app.sock"))
+	})
+
+	t.Run("UnixSockets", func(t *testing.T) {
+		t.Parallel()
+		policy := &agentsdk.PortForwardingPolicy{
+			AllowedUnixSockets: []string{"/run/user/*/*.sock"},
+		}
+		require.True(t, policy.AllowsUnixSocket("/run/user/1000/app.sock"))
+		require.False(t, policy.AllowsUnixSocket("/run/user/1000/nested/app.sock"))
+		require.False(t, policy.AllowsUnixSocket("/tmp/app.sock"))
+	})
+
+	t.Run("RelativeUnixSocket", func(t *testing.T) {
+		t.Parallel()
+		policy := &agentsdk.PortForwardingPolicy{}
+		require.True(t, policy.AllowsUnixSocket("/tmp/app.sock"))
+		require.False(t, policy.AllowsUnixSocket("app.sock"))
+		require.False(t, policy.AllowsUnixSocket("../var/run/docker.sock"))
+	})
+
+	t.Run("UnixSocketSymlink", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("symlinks require elevated privileges on Windows")
+		}
+		dir, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		denied := filepath.Join(dir, "denied")
+		allowed := filepath.Join(dir, "allowed")
+		require.NoError(t, os.Mkdir(denied, 0o700))
+		require.NoError(t, os.Mkdir(allowed, 0o700))
+		target := filepath.Join(denied, "app.sock")
+		require.NoError(t, os.WriteFile(target, nil, 0o600))
+		link := filepath.Join(allowed, "app.sock")
+		require.NoError(t, os.Symlink(target, link))
+
+		policy := &agentsdk.PortForwardingPolicy{
+			DeniedUnixSockets: []string{filepath.Join(denied, "*")},
+		}
+		require.False(t, policy.AllowsUnixSocket(link))
+
+		policy = &agentsdk.PortForwardingPolicy{
+			AllowedUnixSockets: []string{filepath.Join(allowed, "*")},
+		}
+		require.False(t, policy.AllowsUnixSocket(link))
+		require.True(t, policy.AllowsUnixSocket(filepath.Join(allowed, "missing.sock")))
+
+		policy = &agentsdk.PortForwardingPolicy{
+			DeniedUnixSockets: []string{link},
+		}
+		require.False(t, policy.AllowsUnixSocket(link))
+		require.True(t, policy.AllowsUnixSocket(target))
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, agentsdk.PortForwardingPolicy{
+			AllowedPorts:       []string{"1-1024"},
+			DeniedUnixSockets:  []string{"/var/run/*.sock"},
+			AllowedUnixSockets: []string{},
+		}.Validate())
+		require.Error(t, agentsdk.PortForwardingPolicy{
+			DeniedPorts: []string{"ssh"},
+		}.Validate())
+		require.Error(t, agentsdk.PortForwardingPolicy{
+			AllowedUnixSockets: []string{"/tmp/[.sock"},
+		}.Validate())
+	})
+}
@@ -22,6 +22,12 @@ type APIKey struct {
 	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// ScopePermissions are the resource:action pairs the key is limited to.
+	// An empty list applies no limit beyond the scope.
+	ScopePermissions []string `json:"scope_permissions"`
+	// ScopeAllowList are the resource:id pairs the key is limited to. An
+	// empty list allows every resource.
+	ScopeAllowList []string `json:"scope_allow_list"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -54,6 +60,14 @@ type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
 	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
 	TokenName string        `json:"token_name"`
+	// ScopePermissions limits the token to resource:action pairs, such as
+	// workspace:read or template:push. They can only be used with the all
+	// scope.
+	ScopePermissions []string `json:"scope_permissions,omitempty"`
+	// ScopeAllowList limits the token to resource:id pairs, such as
+	// template:<id>. Allowing a template also allows the user's workspaces
+	// built from it.
+	ScopeAllowList []string `json:"scope_allow_list,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -39,6 +39,9 @@ const (
 	ResourceTypeIdpSyncSettingsRole         ResourceType = "idp_sync_settings_role"
 	ResourceTypeWorkspaceAgent              ResourceType = "workspace_agent"
 	ResourceTypeWorkspaceApp                ResourceType = "workspace_app"
+	ResourceTypeWorkspaceAgentPortShare     ResourceType = "workspace_agent_port_share"
+	ResourceTypeSCIMGroup                   ResourceType = "scim_group"
+	ResourceTypeWorkspaceSnapshot           ResourceType = "workspace_snapshot"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -93,6 +96,12 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace agent"
 	case ResourceTypeWorkspaceApp:
 		return "workspace app"
+	case ResourceTypeWorkspaceAgentPortShare:
+		return "port share"
+	case ResourceTypeSCIMGroup:
+		return "scim group"
+	case ResourceTypeWorkspaceSnapshot:
+		return "workspace snapshot"
 	default:
 		return "unknown"
 	}
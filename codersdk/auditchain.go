@@ -0,0 +1,287 @@
+package codersdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// AuditLogChainRecord is the part of an audit log that is covered by its
+// hash in the audit log chain.
+type AuditLogChainRecord struct {
+	ID               uuid.UUID       `json:"id" format:"uuid"`
+	Time             time.Time       `json:"time" format:"date-time"`
+	UserID           uuid.UUID       `json:"user_id" format:"uuid"`
+	OrganizationID   uuid.UUID       `json:"organization_id" format:"uuid"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     ResourceType    `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id" format:"uuid"`
+	ResourceTarget   string          `json:"resource_target"`
+	ResourceIcon     string          `json:"resource_icon"`
+	Action           AuditAction     `json:"action"`
+	Diff             json.RawMessage `json:"diff" swaggertype:"object"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields" swaggertype:"object"`
+	RequestID        uuid.UUID       `json:"request_id" format:"uuid"`
+}
+
+// AuditLogChain identifies the audit log chain of an organization, or of
+// deployment-wide audit logs.
+type AuditLogChain struct {
+	// OrganizationID is the nil UUID for the deployment-wide chain.
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// OrganizationName is empty if the organization no longer exists.
+	OrganizationName    string `json:"organization_name"`
+	OrganizationDeleted bool   `json:"organization_deleted"`
+}
+
+// AuditLogChainEntry links an audit log to the one that was recorded before
+// it in the same organization.
+type AuditLogChainEntry struct {
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	Sequence       int64     `json:"sequence"`
+	AuditLogID     uuid.UUID `json:"audit_log_id" format:"uuid"`
+	// PrevHash and Hash are hex encoded SHA-256 hashes.
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+	// Record is omitted if the audit log no longer exists.
+	Record *AuditLogChainRecord `json:"record,omitempty"`
+}
+
+// AuditLogCheckpoint is a signature over the hash of an entry in the audit
+// log chain, made periodically by the deployment.
+type AuditLogCheckpoint struct {
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	Sequence       int64     `json:"sequence"`
+	// Hash is the hex encoded hash of the chain entry.
+	Hash string `json:"hash"`
+	// Signature is a hex encoded ed25519 signature over the message returned
+	// by AuditLogCheckpointMessage.
+	Signature string    `json:"signature"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+// AuditLogPublicKey is the key that checkpoints of the audit log chain are
+// signed with.
+type AuditLogPublicKey struct {
+	// PublicKey is a hex encoded ed25519 public key.
+	PublicKey string `json:"public_key"`
+}
+
+type AuditLogChainRequest struct {
+	// OrganizationID selects the chain of an organization. The nil UUID
+	// selects the chain of deployment-wide audit logs.
+	OrganizationID uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
+	// AfterSequence only returns items with a greater sequence.
+	AfterSequence int64 `json:"after_sequence,omitempty"`
+	// Limit is the maximum number of items to return. Defaults to 100.
+	Limit int `json:"limit,omitempty"`
+}
+
+func (r AuditLogChainRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if r.OrganizationID != uuid.Nil {
+			q.Set("organization_id", r.OrganizationID.String())
+		}
+		if r.AfterSequence > 0 {
+			q.Set("after_sequence", strconv.FormatInt(r.AfterSequence, 10))
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// AuditLogChain returns entries of an audit log chain, ordered by sequence.
+func (c *Client) AuditLogChain(ctx context.Context, req AuditLogChainRequest) ([]AuditLogChainEntry, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/chain", nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var entries []AuditLogChainEntry
+	return entries, json.NewDecoder(res.Body).Decode(&entries)
+}
+
+// AuditLogChains returns every audit log chain the user can read, including
+// the chains of deleted organizations.
+func (c *Client) AuditLogChains(ctx context.Context) ([]AuditLogChain, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/chains", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var chains []AuditLogChain
+	return chains, json.NewDecoder(res.Body).Decode(&chains)
+}
+
+// AuditLogCheckpoints returns checkpoints of an audit log chain, ordered by
+// sequence.
+func (c *Client) AuditLogCheckpoints(ctx context.Context, req AuditLogChainRequest) ([]AuditLogCheckpoint, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/checkpoints", nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var checkpoints []AuditLogCheckpoint
+	return checkpoints, json.NewDecoder(res.Body).Decode(&checkpoints)
+}
+
+// AuditLogPublicKey returns the key that checkpoints are signed with. A
+// verifier should pin this key out of band, since a compromised deployment
+// could otherwise serve a key of its choosing. It fails with a 404 if the
+// deployment has no signing key configured.
+func (c *Client) AuditLogPublicKey(ctx context.Context) (AuditLogPublicKey, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/checkpoints/public-key", nil)
+	if err != nil {
+		return AuditLogPublicKey{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return AuditLogPublicKey{}, ReadBodyAsError(res)
+	}
+
+	var key AuditLogPublicKey
+	return key, json.NewDecoder(res.Body).Decode(&key)
+}
+
+// auditLogCheckpointDomain separates checkpoint signatures from any other
+// use of the signing key.
+const auditLogCheckpointDomain = "coder-audit-log-checkpoint-v1"
+
+// AuditLogCheckpointMessage returns the message that is signed for a
+// checkpoint of the given chain entry.
+func AuditLogCheckpointMessage(organizationID uuid.UUID, sequence int64, hash []byte) []byte {
+	msg := make([]byte, 0, len(auditLogCheckpointDomain)+len(organizationID)+8+len(hash))
+	msg = append(msg, auditLogCheckpointDomain...)
+	msg = append(msg, organizationID[:]...)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(sequence)) //nolint:gosec // Sequences are positive.
+	msg = append(msg, hash...)
+	return msg
+}
+
+// ParseAuditLogPublicKey decodes an ed25519 public key that is either hex
+// encoded, or PEM encoded as produced by `openssl pkey -pubout`.
+func ParseAuditLogPublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, xerrors.Errorf("parse public key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, xerrors.Errorf("public key must be an ed25519 key, got %T", key)
+		}
+		return edKey, nil
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, xerrors.Errorf("decode public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, xerrors.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyAuditLogCheckpoint checks that the signature of a checkpoint was made
+// with the given key over the organization, sequence and hash stored
+// alongside it.
+func VerifyAuditLogCheckpoint(publicKey ed25519.PublicKey, checkpoint AuditLogCheckpoint) error {
+	hash, err := hex.DecodeString(checkpoint.Hash)
+	if err != nil {
+		return xerrors.Errorf("decode hash: %w", err)
+	}
+	signature, err := hex.DecodeString(checkpoint.Signature)
+	if err != nil {
+		return xerrors.Errorf("decode signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, AuditLogCheckpointMessage(checkpoint.OrganizationID, checkpoint.Sequence, hash), signature) {
+		return xerrors.New("signature does not match")
+	}
+	return nil
+}
+
+// AuditLogChainGenesisHash is the previous hash of the first entry in the
+// audit log chain.
+var AuditLogChainGenesisHash = make([]byte, sha256.Size)
+
+// AuditLogChainHash computes the hash of an entry in the audit log chain. It
+// covers the hash of the previous entry, the sequence of the entry and a
+// canonical encoding of the audit log, so that changing, removing or
+// reordering audit logs breaks the chain.
+func AuditLogChainHash(prevHash []byte, sequence int64, record AuditLogChainRecord) ([]byte, error) {
+	var err error
+	// The database stores timestamps with microsecond precision, and
+	// normalizes JSON, so both are normalized before hashing.
+	record.Time = record.Time.UTC().Round(time.Microsecond)
+	record.Diff, err = canonicalJSON(record.Diff)
+	if err != nil {
+		return nil, xerrors.Errorf("canonicalize diff: %w", err)
+	}
+	record.AdditionalFields, err = canonicalJSON(record.AdditionalFields)
+	if err != nil {
+		return nil, xerrors.Errorf("canonicalize additional fields: %w", err)
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal record: %w", err)
+	}
+
+	h := sha256.New()
+	_, _ = h.Write(prevHash)
+	_ = binary.Write(h, binary.BigEndian, sequence)
+	_, _ = h.Write(encoded)
+	return h.Sum(nil), nil
+}
+
+// canonicalJSON re-encodes JSON with sorted object keys and no insignificant
+// whitespace.
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, xerrors.New("unexpected data after JSON value")
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,96 @@
+package codersdk_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestAuditLogChainHash(t *testing.T) {
+	t.Parallel()
+
+	record := codersdk.AuditLogChainRecord{
+		ID:               uuid.New(),
+		Time:             time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC),
+		UserID:           uuid.New(),
+		OrganizationID:   uuid.New(),
+		IP:               "127.0.0.1",
+		ResourceType:     codersdk.ResourceTypeWorkspace,
+		ResourceID:       uuid.New(),
+		ResourceTarget:   "dev",
+		Action:           codersdk.AuditActionStart,
+		Diff:             json.RawMessage(`{"name": {"old": "a", "new": "b"}}`),
+		StatusCode:       200,
+		AdditionalFields: json.RawMessage(`{"b": 1, "a": [1, 2]}`),
+		RequestID:        uuid.New(),
+	}
+	hash, err := codersdk.AuditLogChainHash(codersdk.AuditLogChainGenesisHash, 1, record)
+	require.NoError(t, err)
+
+	// Representations which the database considers equal hash the same.
+	equivalent := record
+	equivalent.Time = record.Time.Round(time.Microsecond).In(time.FixedZone("EST", -5*60*60))
+	equivalent.Diff = json.RawMessage(`{"name":{"new":"b","old":"a"}}`)
+	equivalent.AdditionalFields = json.RawMessage(`{"a":[1,2],"b":1}`)
+	got, err := codersdk.AuditLogChainHash(codersdk.AuditLogChainGenesisHash, 1, equivalent)
+	require.NoError(t, err)
+	require.Equal(t, hash, got)
+
+	// Changing the record, its position or its predecessor changes the hash.
+	modified := record
+	modified.ResourceTarget = "prod"
+	got, err = codersdk.AuditLogChainHash(codersdk.AuditLogChainGenesisHash, 1, modified)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, got)
+
+	got, err = codersdk.AuditLogChainHash(codersdk.AuditLogChainGenesisHash, 2, record)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, got)
+
+	got, err = codersdk.AuditLogChainHash(hash, 1, record)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, got)
+}
+
+func TestVerifyAuditLogCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	orgID := uuid.New()
+	hash := make([]byte, 32)
+	hash[0] = 1
+	checkpoint := codersdk.AuditLogCheckpoint{
+		OrganizationID: orgID,
+		Sequence:       7,
+		Hash:           hex.EncodeToString(hash),
+		Signature:      hex.EncodeToString(ed25519.Sign(priv, codersdk.AuditLogCheckpointMessage(orgID, 7, hash))),
+	}
+	require.NoError(t, codersdk.VerifyAuditLogCheckpoint(pub, checkpoint))
+
+	parsed, err := codersdk.ParseAuditLogPublicKey(hex.EncodeToString(pub))
+	require.NoError(t, err)
+	require.Equal(t, pub, parsed)
+
+	// The signature covers the sequence and the organization, so a checkpoint
+	// cannot be moved within a chain or to another chain.
+	moved := checkpoint
+	moved.Sequence = 8
+	require.Error(t, codersdk.VerifyAuditLogCheckpoint(pub, moved))
+	moved = checkpoint
+	moved.OrganizationID = uuid.New()
+	require.Error(t, codersdk.VerifyAuditLogCheckpoint(pub, moved))
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	require.Error(t, codersdk.VerifyAuditLogCheckpoint(otherPub, checkpoint))
+}
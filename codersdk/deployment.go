@@ -399,6 +399,7 @@ type DeploymentValues struct {
 	AdditionalCSPPolicy             serpent.StringArray                  `json:"additional_csp_policy,omitempty" typescript:",notnull"`
 	WorkspaceHostnameSuffix         serpent.String                       `json:"workspace_hostname_suffix,omitempty" typescript:",notnull"`
 	Prebuilds                       PrebuildsConfig                      `json:"workspace_prebuilds,omitempty" typescript:",notnull"`
+	AuditLogging                    AuditLoggingConfig                   `json:"audit_logging,omitempty" typescript:",notnull"`
 
 	Config      serpent.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig serpent.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -720,21 +721,27 @@ type NotificationsConfig struct {
 	// How often to query the database for queued notifications.
 	FetchInterval serpent.Duration `json:"fetch_interval"`
 
-	// Which delivery method to use (available options: 'smtp', 'webhook').
+	// Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').
 	Method serpent.String `json:"method"`
 	// How long to wait while a notification is being sent before giving up.
 	DispatchTimeout serpent.Duration `json:"dispatch_timeout"`
+	// How often to send users a digest of the notifications they have chosen to batch.
+	DigestInterval serpent.Duration `json:"digest_interval"`
 	// SMTP settings.
 	SMTP NotificationsEmailConfig `json:"email" typescript:",notnull"`
 	// Webhook settings.
 	Webhook NotificationsWebhookConfig `json:"webhook" typescript:",notnull"`
+	// Slack settings.
+	Slack NotificationsSlackConfig `json:"slack" typescript:",notnull"`
+	// Microsoft Teams settings.
+	Teams NotificationsTeamsConfig `json:"teams" typescript:",notnull"`
 	// Inbox settings.
 	Inbox NotificationsInboxConfig `json:"inbox" typescript:",notnull"`
 }
 
-// Are either of the notification methods enabled?
+// Are any of the notification methods enabled?
 func (n *NotificationsConfig) Enabled() bool {
-	return n.SMTP.Smarthost != "" || n.Webhook.Endpoint != serpent.URL{}
+	return n.SMTP.Smarthost != "" || n.Webhook.Endpoint != serpent.URL{} || n.Slack.Enabled() || n.Teams.Enabled()
 }
 
 type NotificationsInboxConfig struct {
@@ -796,6 +803,32 @@ type NotificationsWebhookConfig struct {
 	Endpoint serpent.URL `json:"endpoint" typescript:",notnull"`
 }
 
+type NotificationsSlackConfig struct {
+	// The incoming webhook URL to which messages will be posted.
+	WebhookURL serpent.URL `json:"webhook_url" typescript:",notnull"`
+	// The bot token used to deliver messages directly to users via the Slack Web API.
+	BotToken serpent.String `json:"bot_token" typescript:",notnull"`
+	// The channel to post to when a bot token is used and the recipient cannot be found in Slack.
+	Channel serpent.String `json:"channel" typescript:",notnull"`
+	// The base URL of the Slack Web API.
+	APIURL serpent.URL `json:"api_url" typescript:",notnull"`
+}
+
+// Enabled returns true if either delivery mechanism is configured.
+func (c *NotificationsSlackConfig) Enabled() bool {
+	return c.BotToken != "" || c.WebhookURL.String() != ""
+}
+
+type NotificationsTeamsConfig struct {
+	// The URL of the Teams workflow or incoming webhook to which Adaptive Cards will be posted.
+	WebhookURL serpent.URL `json:"webhook_url" typescript:",notnull"`
+}
+
+// Enabled returns true if a Teams webhook is configured.
+func (c *NotificationsTeamsConfig) Enabled() bool {
+	return c.WebhookURL.String() != ""
+}
+
 type PrebuildsConfig struct {
 	// ReconciliationInterval defines how often the workspace prebuilds state should be reconciled.
 	ReconciliationInterval serpent.Duration `json:"reconciliation_interval" typescript:",notnull"`
@@ -809,6 +842,70 @@ type PrebuildsConfig struct {
 	ReconciliationBackoffLookback serpent.Duration `json:"reconciliation_backoff_lookback" typescript:",notnull"`
 }
 
+// AuditLoggingConfig configures how audit logs are checkpointed, and the
+// external systems they are streamed to in addition to being stored in the
+// database.
+type AuditLoggingConfig struct {
+	// The PEM encoded ed25519 private key that checkpoints are signed with.
+	SigningKeyFile serpent.String `json:"signing_key_file" typescript:",notnull"`
+
+	Syslog AuditLoggingSyslogConfig `json:"syslog" typescript:",notnull"`
+	HTTP   AuditLoggingHTTPConfig   `json:"http" typescript:",notnull"`
+	File   AuditLoggingFileConfig   `json:"file" typescript:",notnull"`
+}
+
+type AuditLoggingSyslogConfig struct {
+	// The address of the syslog server, in host:port form.
+	Address serpent.String `json:"address" typescript:",notnull"`
+	// Whether to connect to the syslog server using TLS.
+	TLS serpent.Bool `json:"tls" typescript:",notnull"`
+	// The CA certificate used to verify the syslog server's certificate.
+	TLSCAFile serpent.String `json:"tls_ca_file" typescript:",notnull"`
+	// The client certificate used to authenticate with the syslog server.
+	TLSCertFile serpent.String `json:"tls_cert_file" typescript:",notnull"`
+	// The client private key used to authenticate with the syslog server.
+	TLSKeyFile serpent.String `json:"tls_key_file" typescript:",notnull"`
+}
+
+// Enabled returns true if audit logs should be streamed to a syslog server.
+func (c *AuditLoggingSyslogConfig) Enabled() bool {
+	return c.Address != ""
+}
+
+type AuditLoggingHTTPConfig struct {
+	// The URL to which batches of audit logs will be sent with an HTTP POST request.
+	URL serpent.URL `json:"url" typescript:",notnull"`
+	// Additional headers to send with each request, in "Name: value" form.
+	Headers serpent.StringArray `json:"headers" typescript:",notnull"`
+	// The maximum number of audit logs to send in a single request.
+	BatchSize serpent.Int64 `json:"batch_size" typescript:",notnull"`
+	// How long to wait for a batch to fill up before sending it anyway.
+	FlushInterval serpent.Duration `json:"flush_interval" typescript:",notnull"`
+	// The directory in which audit logs are queued until they are sent.
+	QueueDir serpent.String `json:"queue_dir" typescript:",notnull"`
+	// The maximum size of the queue, in megabytes.
+	QueueMaxSize serpent.Int64 `json:"queue_max_size" typescript:",notnull"`
+}
+
+// Enabled returns true if audit logs should be streamed to an HTTP endpoint.
+func (c *AuditLoggingHTTPConfig) Enabled() bool {
+	return c.URL.String() != ""
+}
+
+type AuditLoggingFileConfig struct {
+	// The file to which audit logs are written.
+	Path serpent.String `json:"path" typescript:",notnull"`
+	// The maximum size of the file, in megabytes, before it is rotated.
+	MaxSize serpent.Int64 `json:"max_size" typescript:",notnull"`
+	// The maximum number of rotated files to retain.
+	MaxBackups serpent.Int64 `json:"max_backups" typescript:",notnull"`
+}
+
+// Enabled returns true if audit logs should be written to a file.
+func (c *AuditLoggingFileConfig) Enabled() bool {
+	return c.Path != ""
+}
+
 const (
 	annotationFormatDuration = "format_duration"
 	annotationEnterpriseKey  = "enterprise"
@@ -1039,6 +1136,16 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Parent: &deploymentGroupNotifications,
 			YAML:   "webhook",
 		}
+		deploymentGroupNotificationsSlack = serpent.Group{
+			Name:   "Slack",
+			Parent: &deploymentGroupNotifications,
+			YAML:   "slack",
+		}
+		deploymentGroupNotificationsTeams = serpent.Group{
+			Name:   "Microsoft Teams",
+			Parent: &deploymentGroupNotifications,
+			YAML:   "teams",
+		}
 		deploymentGroupPrebuilds = serpent.Group{
 			Name:        "Workspace Prebuilds",
 			YAML:        "workspace_prebuilds",
@@ -1049,6 +1156,29 @@ func (c *DeploymentValues) Options() serpent.OptionSet {
 			Parent: &deploymentGroupNotifications,
 			YAML:   "inbox",
 		}
+		deploymentGroupAuditLogging = serpent.Group{
+			Name:        "Audit Logging",
+			YAML:        "auditLogging",
+			Description: "Sign checkpoints of the tamper-evident audit log chains, and stream audit logs to external systems, such as a SIEM, in addition to storing them in the database. Audit logs are exported as JSON objects, one per line.",
+		}
+		deploymentGroupAuditLoggingSyslog = serpent.Group{
+			Name:        "Syslog",
+			Parent:      &deploymentGroupAuditLogging,
+			Description: "Stream audit logs to a syslog server over TCP, formatted according to RFC 5424.",
+			YAML:        "syslog",
+		}
+		deploymentGroupAuditLoggingHTTP = serpent.Group{
+			Name:        "HTTP",
+			Parent:      &deploymentGroupAuditLogging,
+			Description: "Send batches of audit logs to an HTTP endpoint. Audit logs are queued on disk until they have been delivered.",
+			YAML:        "http",
+		}
+		deploymentGroupAuditLoggingFile = serpent.Group{
+			Name:        "File",
+			Parent:      &deploymentGroupAuditLogging,
+			Description: "Write audit logs to a file, which is rotated once it reaches a given size.",
+			YAML:        "file",
+		}
 	)
 
 	httpAddress := serpent.Option{
@@ -2780,7 +2910,7 @@ Write out the current server config as YAML to stdout.`,
 		// Notifications Options
 		{
 			Name:        "Notifications: Method",
-			Description: "Which delivery method to use (available options: 'smtp', 'webhook').",
+			Description: "Which delivery method to use (available options: 'smtp', 'webhook', 'slack', 'teams').",
 			Flag:        "notifications-method",
 			Env:         "CODER_NOTIFICATIONS_METHOD",
 			Value:       &c.Notifications.Method,
@@ -2799,6 +2929,18 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "dispatchTimeout",
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		{
+			Name: "Notifications: Digest Interval",
+			Description: "How often to send users a digest of the notifications they have chosen to batch. " +
+				"A digest is sent once this much time has passed since its oldest notification was held back.",
+			Flag:        "notifications-digest-interval",
+			Env:         "CODER_NOTIFICATIONS_DIGEST_INTERVAL",
+			Value:       &c.Notifications.DigestInterval,
+			Default:     (time.Hour * 24).String(),
+			Group:       &deploymentGroupNotifications,
+			YAML:        "digestInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Notifications: Email: From Address",
 			Description: "The sender's address to use.",
@@ -2948,6 +3090,52 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupNotificationsWebhook,
 			YAML:        "endpoint",
 		},
+		{
+			Name:        "Notifications: Slack: Webhook URL",
+			Description: "The Slack incoming webhook URL to which notifications are posted. Ignored if a bot token is set.",
+			Flag:        "notifications-slack-webhook-url",
+			Env:         "CODER_NOTIFICATIONS_SLACK_WEBHOOK_URL",
+			Value:       &c.Notifications.Slack.WebhookURL,
+			Group:       &deploymentGroupNotificationsSlack,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Notifications: Slack: Bot Token",
+			Description: "The Slack bot token used to send notifications directly to users, matched by email address. Requires the chat:write and users:read.email scopes.",
+			Flag:        "notifications-slack-bot-token",
+			Env:         "CODER_NOTIFICATIONS_SLACK_BOT_TOKEN",
+			Value:       &c.Notifications.Slack.BotToken,
+			Group:       &deploymentGroupNotificationsSlack,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Notifications: Slack: Channel",
+			Description: "The Slack channel to post to when the recipient cannot be found in Slack. Only used with a bot token; if unset, such notifications fail.",
+			Flag:        "notifications-slack-channel",
+			Env:         "CODER_NOTIFICATIONS_SLACK_CHANNEL",
+			Value:       &c.Notifications.Slack.Channel,
+			Group:       &deploymentGroupNotificationsSlack,
+			YAML:        "channel",
+		},
+		{
+			Name:        "Notifications: Slack: API URL",
+			Description: "The base URL of the Slack Web API. Change this for GovSlack or other dedicated Slack instances.",
+			Flag:        "notifications-slack-api-url",
+			Env:         "CODER_NOTIFICATIONS_SLACK_API_URL",
+			Value:       &c.Notifications.Slack.APIURL,
+			Default:     "https://slack.com/api/",
+			Group:       &deploymentGroupNotificationsSlack,
+			YAML:        "apiURL",
+		},
+		{
+			Name:        "Notifications: Microsoft Teams: Webhook URL",
+			Description: "The Microsoft Teams workflow or incoming webhook URL to which Adaptive Card notifications are posted.",
+			Flag:        "notifications-teams-webhook-url",
+			Env:         "CODER_NOTIFICATIONS_TEAMS_WEBHOOK_URL",
+			Value:       &c.Notifications.Teams.WebhookURL,
+			Group:       &deploymentGroupNotificationsTeams,
+			Annotations: serpent.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
 		{
 			Name:        "Notifications: Inbox: Enabled",
 			Description: "Enable Coder Inbox.",
@@ -3086,6 +3274,161 @@ Write out the current server config as YAML to stdout.`,
 			Annotations: serpent.Annotations{}.Mark(annotationFormatDuration, "true"),
 			Hidden:      true,
 		},
+		// Audit Logging Options
+		{
+			Name:        "Audit Logging: Signing Key File",
+			Description: "The PEM encoded ed25519 private key that checkpoints of the tamper-evident audit log chains are signed with, such as one generated with `openssl genpkey -algorithm ed25519`. Checkpoints are only made if a key is set. The key must not be rotated while the audit logs it covers are kept.",
+			Flag:        "audit-logging-signing-key-file",
+			Env:         "CODER_AUDIT_LOGGING_SIGNING_KEY_FILE",
+			Value:       &c.AuditLogging.SigningKeyFile,
+			Group:       &deploymentGroupAuditLogging,
+			YAML:        "signingKeyFile",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: Syslog Address",
+			Description: "The address of a syslog server to stream audit logs to, in host:port form.",
+			Flag:        "audit-logging-syslog-address",
+			Env:         "CODER_AUDIT_LOGGING_SYSLOG_ADDRESS",
+			Value:       &c.AuditLogging.Syslog.Address,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "address",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: Syslog TLS",
+			Description: "Connect to the syslog server using TLS.",
+			Flag:        "audit-logging-syslog-tls",
+			Env:         "CODER_AUDIT_LOGGING_SYSLOG_TLS",
+			Value:       &c.AuditLogging.Syslog.TLS,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "tls",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: Syslog TLS CA File",
+			Description: "The CA certificate used to verify the syslog server's certificate. The system certificate pool is used if unset.",
+			Flag:        "audit-logging-syslog-tls-ca-file",
+			Env:         "CODER_AUDIT_LOGGING_SYSLOG_TLS_CA_FILE",
+			Value:       &c.AuditLogging.Syslog.TLSCAFile,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "tlsCAFile",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: Syslog TLS Certificate File",
+			Description: "The client certificate used to authenticate with the syslog server.",
+			Flag:        "audit-logging-syslog-tls-cert-file",
+			Env:         "CODER_AUDIT_LOGGING_SYSLOG_TLS_CERT_FILE",
+			Value:       &c.AuditLogging.Syslog.TLSCertFile,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "tlsCertFile",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: Syslog TLS Key File",
+			Description: "The client private key used to authenticate with the syslog server.",
+			Flag:        "audit-logging-syslog-tls-key-file",
+			Env:         "CODER_AUDIT_LOGGING_SYSLOG_TLS_KEY_FILE",
+			Value:       &c.AuditLogging.Syslog.TLSKeyFile,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "tlsKeyFile",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP URL",
+			Description: "The URL to send batches of audit logs to with an HTTP POST request. Each request body contains up to a batch size of audit logs, encoded as newline-delimited JSON.",
+			Flag:        "audit-logging-http-url",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_URL",
+			Value:       &c.AuditLogging.HTTP.URL,
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			YAML:        "url",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP Headers",
+			Description: "Additional headers to send with each request, in 'Name: value' form. Use this to authenticate with the endpoint.",
+			Flag:        "audit-logging-http-headers",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_HEADERS",
+			Value:       &c.AuditLogging.HTTP.Headers,
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP Batch Size",
+			Description: "The maximum number of audit logs to send in a single request.",
+			Flag:        "audit-logging-http-batch-size",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_BATCH_SIZE",
+			Value:       &c.AuditLogging.HTTP.BatchSize,
+			Default:     "100",
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			YAML:        "batchSize",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP Flush Interval",
+			Description: "How long to wait for a batch to fill up before sending it anyway.",
+			Flag:        "audit-logging-http-flush-interval",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_FLUSH_INTERVAL",
+			Value:       &c.AuditLogging.HTTP.FlushInterval,
+			Default:     (5 * time.Second).String(),
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			YAML:        "flushInterval",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP Queue Directory",
+			Description: "The directory in which audit logs are queued until they have been delivered. Defaults to a directory within the cache directory.",
+			Flag:        "audit-logging-http-queue-dir",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_QUEUE_DIR",
+			Value:       &c.AuditLogging.HTTP.QueueDir,
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			YAML:        "queueDir",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: HTTP Queue Max Size",
+			Description: "The maximum size of the queue, in megabytes. Audit logs cannot be exported while the queue is full.",
+			Flag:        "audit-logging-http-queue-max-size",
+			Env:         "CODER_AUDIT_LOGGING_HTTP_QUEUE_MAX_SIZE",
+			Value:       &c.AuditLogging.HTTP.QueueMaxSize,
+			Default:     "100",
+			Group:       &deploymentGroupAuditLoggingHTTP,
+			YAML:        "queueMaxSize",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: File Path",
+			Description: "The file to write audit logs to.",
+			Flag:        "audit-logging-file-path",
+			Env:         "CODER_AUDIT_LOGGING_FILE_PATH",
+			Value:       &c.AuditLogging.File.Path,
+			Group:       &deploymentGroupAuditLoggingFile,
+			YAML:        "path",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: File Max Size",
+			Description: "The maximum size of the file, in megabytes, before it is rotated.",
+			Flag:        "audit-logging-file-max-size",
+			Env:         "CODER_AUDIT_LOGGING_FILE_MAX_SIZE",
+			Value:       &c.AuditLogging.File.MaxSize,
+			Default:     "100",
+			Group:       &deploymentGroupAuditLoggingFile,
+			YAML:        "maxSize",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Audit Logging: File Max Backups",
+			Description: "The maximum number of rotated files to retain. Set to 0 to retain all of them.",
+			Flag:        "audit-logging-file-max-backups",
+			Env:         "CODER_AUDIT_LOGGING_FILE_MAX_BACKUPS",
+			Value:       &c.AuditLogging.File.MaxBackups,
+			Default:     "10",
+			Group:       &deploymentGroupAuditLoggingFile,
+			YAML:        "maxBackups",
+			Annotations: serpent.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
 	}
 
 	return opts
@@ -3148,9 +3491,9 @@ func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
 			continue
 		}
 
-		// This only works with string values for now.
+		// This only works with string and URL values for now.
 		switch v := opt.Value.(type) {
-		case *serpent.String, *serpent.StringArray:
+		case *serpent.String, *serpent.StringArray, *serpent.URL:
 			err := v.Set("")
 			if err != nil {
 				panic(err)
@@ -84,6 +84,18 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"Notifications: Email Auth: Password": {
 			yaml: true,
 		},
+		"Notifications: Slack: Webhook URL": {
+			yaml: true,
+		},
+		"Notifications: Slack: Bot Token": {
+			yaml: true,
+		},
+		"Notifications: Microsoft Teams: Webhook URL": {
+			yaml: true,
+		},
+		"Audit Logging: HTTP Headers": {
+			yaml: true,
+		},
 	}
 
 	set := (&codersdk.DeploymentValues{}).Options()
@@ -616,6 +628,26 @@ func TestNotificationsCanBeDisabled(t *testing.T) {
 			},
 			expectNotificationsEnabled: true,
 		},
+		{
+			name: "SlackBotToken_DeliveryMethodSet",
+			environment: []serpent.EnvVar{
+				{
+					Name:  "CODER_NOTIFICATIONS_SLACK_BOT_TOKEN",
+					Value: "xoxb-test",
+				},
+			},
+			expectNotificationsEnabled: true,
+		},
+		{
+			name: "Teams_DeliveryMethodSet",
+			environment: []serpent.EnvVar{
+				{
+					Name:  "CODER_NOTIFICATIONS_TEAMS_WEBHOOK_URL",
+					Value: "https://example.com/teams",
+				},
+			},
+			expectNotificationsEnabled: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -26,6 +26,7 @@ type NotificationTemplate struct {
 	Method           string    `json:"method"`
 	Kind             string    `json:"kind"`
 	EnabledByDefault bool      `json:"enabled_by_default"`
+	Digestible       bool      `json:"digestible"`
 }
 
 type NotificationMethodsResponse struct {
@@ -36,7 +37,10 @@ type NotificationMethodsResponse struct {
 type NotificationPreference struct {
 	NotificationTemplateID uuid.UUID `json:"id" format:"uuid"`
 	Disabled               bool      `json:"disabled"`
-	UpdatedAt              time.Time `json:"updated_at" format:"date-time"`
+	// Method overrides the template-level or deployment-level delivery method for this user.
+	Method    string    `json:"method,omitempty"`
+	Digest    bool      `json:"digest"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
 }
 
 // GetNotificationsSettings retrieves the notifications settings, which currently just describes whether all
@@ -212,6 +216,11 @@ type UpdateNotificationTemplateMethod struct {
 
 type UpdateUserNotificationPreferences struct {
 	TemplateDisabledMap map[string]bool `json:"template_disabled_map"`
+	// TemplateMethodMap sets the delivery method for each template. An empty method removes the override.
+	TemplateMethodMap map[string]string `json:"template_method_map,omitempty"`
+	// TemplateDigestMap sets whether each template is batched into a periodic digest instead of being delivered
+	// immediately. Only digestible templates may be batched.
+	TemplateDigestMap map[string]bool `json:"template_digest_map,omitempty"`
 }
 
 type WebpushMessageAction struct {
@@ -0,0 +1,55 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// SCIMDeprovisioningSettings controls what happens to the workspaces of a user
+// in the organization when the user is deactivated by the SCIM provider.
+type SCIMDeprovisioningSettings struct {
+	// StopWorkspaces stops every running workspace of the user immediately.
+	StopWorkspaces bool `json:"stop_workspaces"`
+	// TransferOwnerID transfers the workspaces of the user to the given
+	// organization member. If nil, the workspaces keep their owner.
+	TransferOwnerID *uuid.UUID `json:"transfer_owner_id,omitempty" format:"uuid"`
+	// MarkDormant marks the workspaces of the user as dormant.
+	MarkDormant bool `json:"mark_dormant"`
+	// DeleteAfterDays deletes dormant workspaces the given number of days
+	// after the user was deactivated. Zero disables deletion. Requires
+	// MarkDormant, activating a workspace cancels its deletion.
+	DeleteAfterDays int32 `json:"delete_after_days"`
+}
+
+func (c *Client) SCIMDeprovisioningSettings(ctx context.Context, orgID string) (SCIMDeprovisioningSettings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/settings/scim/deprovisioning", orgID), nil)
+	if err != nil {
+		return SCIMDeprovisioningSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return SCIMDeprovisioningSettings{}, ReadBodyAsError(res)
+	}
+	var resp SCIMDeprovisioningSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) PatchSCIMDeprovisioningSettings(ctx context.Context, orgID string, req SCIMDeprovisioningSettings) (SCIMDeprovisioningSettings, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/settings/scim/deprovisioning", orgID), req)
+	if err != nil {
+		return SCIMDeprovisioningSettings{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return SCIMDeprovisioningSettings{}, ReadBodyAsError(res)
+	}
+	var resp SCIMDeprovisioningSettings
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
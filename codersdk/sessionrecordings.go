@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceSessionRecording is a recording of an interactive SSH or web
+// terminal session in a workspace. The connection ID matches the request ID
+// of the connect and disconnect audit logs of the session.
+type WorkspaceSessionRecording struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	OrganizationID   uuid.UUID `json:"organization_id" format:"uuid"`
+	WorkspaceID      uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName    string    `json:"workspace_name"`
+	WorkspaceOwnerID uuid.UUID `json:"workspace_owner_id" format:"uuid"`
+	AgentID          uuid.UUID `json:"agent_id" format:"uuid"`
+	AgentName        string    `json:"agent_name"`
+	ConnectionID     uuid.UUID `json:"connection_id" format:"uuid"`
+	ConnectionType   string    `json:"connection_type"`
+	StartedAt        time.Time `json:"started_at" format:"date-time"`
+	EndedAt          time.Time `json:"ended_at" format:"date-time"`
+	// Size is the size of the recording in bytes.
+	Size int64 `json:"size"`
+}
+
+type WorkspaceSessionRecordingsRequest struct {
+	Pagination
+	// WorkspaceID filters recordings by workspace.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	// ConnectionID filters recordings by the request ID of the connection
+	// audit logs.
+	ConnectionID uuid.UUID `json:"connection_id,omitempty" format:"uuid"`
+}
+
+// WorkspaceSessionRecordings returns session recordings, most recent first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, req WorkspaceSessionRecordingsRequest) ([]WorkspaceSessionRecording, error) {
+	opts := []RequestOption{req.Pagination.asRequestOption()}
+	if req.WorkspaceID != uuid.Nil {
+		opts = append(opts, WithQueryParam("workspace_id", req.WorkspaceID.String()))
+	}
+	if req.ConnectionID != uuid.Nil {
+		opts = append(opts, WithQueryParam("connection_id", req.ConnectionID.String()))
+	}
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/sessionrecordings", nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecording returns a session recording by ID.
+func (c *Client) WorkspaceSessionRecording(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/sessionrecordings/%s", id), nil)
+	if err != nil {
+		return WorkspaceSessionRecording{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceSessionRecording{}, ReadBodyAsError(res)
+	}
+	var recording WorkspaceSessionRecording
+	return recording, json.NewDecoder(res.Body).Decode(&recording)
+}
+
+// WorkspaceSessionRecordingCast returns the contents of a session recording
+// in the asciicast v2 format. The caller must close the returned reader.
+func (c *Client) WorkspaceSessionRecordingCast(ctx context.Context, id uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/sessionrecordings/%s/cast", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
@@ -63,6 +63,13 @@ type Template struct {
 	MaxPortShareLevel    WorkspaceAgentPortShareLevel `json:"max_port_share_level"`
 
 	UseClassicParameterFlow bool `json:"use_classic_parameter_flow"`
+	// SessionRecording enables the recording of interactive SSH and terminal
+	// sessions in workspaces created from this template.
+	SessionRecording bool `json:"session_recording"`
+	// ActivityBumpRequiresUserActivity only bumps the deadline of workspaces
+	// created from this template on user activity seen by the agent, e.g.
+	// typing into a terminal, instead of on any open connection.
+	ActivityBumpRequiresUserActivity bool `json:"activity_bump_requires_user_activity"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -200,7 +207,7 @@ type UpdateTemplateACL struct {
 }
 
 // ACLAvailable is a list of users and groups that can be added to a template
-// ACL.
+// or workspace ACL.
 type ACLAvailable struct {
 	Users  []ReducedUser `json:"users"`
 	Groups []Group       `json:"groups"`
@@ -258,6 +265,14 @@ type UpdateTemplateMeta struct {
 	// made the default.
 	// An "opt-out" is present in case the new feature breaks some existing templates.
 	UseClassicParameterFlow *bool `json:"use_classic_parameter_flow,omitempty"`
+	// SessionRecording enables the recording of interactive SSH and terminal
+	// sessions in workspaces created from this template. If nil, the setting
+	// is left unchanged.
+	SessionRecording *bool `json:"session_recording,omitempty"`
+	// ActivityBumpRequiresUserActivity only bumps the deadline of workspaces
+	// created from this template on user activity seen by the agent. If nil,
+	// the setting is left unchanged.
+	ActivityBumpRequiresUserActivity *bool `json:"activity_bump_requires_user_activity,omitempty"`
 }
 
 type TemplateExample struct {
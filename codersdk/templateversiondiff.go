@@ -0,0 +1,79 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type TemplateVersionDiffStatus string
+
+const (
+	TemplateVersionDiffStatusAdded    TemplateVersionDiffStatus = "added"
+	TemplateVersionDiffStatusRemoved  TemplateVersionDiffStatus = "removed"
+	TemplateVersionDiffStatusModified TemplateVersionDiffStatus = "modified"
+)
+
+// TemplateVersionDiff describes what changed between two versions of a
+// template. Only changed files and items are included.
+type TemplateVersionDiff struct {
+	FromTemplateVersionID uuid.UUID                 `json:"from_template_version_id" format:"uuid"`
+	ToTemplateVersionID   uuid.UUID                 `json:"to_template_version_id" format:"uuid"`
+	Files                 []TemplateVersionFileDiff `json:"files"`
+	RichParameters        []TemplateVersionItemDiff `json:"rich_parameters"`
+	Variables             []TemplateVersionItemDiff `json:"variables"`
+	Presets               []TemplateVersionItemDiff `json:"presets"`
+	WorkspaceTags         []TemplateVersionItemDiff `json:"workspace_tags"`
+}
+
+// Empty returns true if the two template versions are identical.
+func (d TemplateVersionDiff) Empty() bool {
+	return len(d.Files) == 0 && len(d.RichParameters) == 0 && len(d.Variables) == 0 &&
+		len(d.Presets) == 0 && len(d.WorkspaceTags) == 0
+}
+
+// TemplateVersionFileDiff is a changed file in the template source archive.
+type TemplateVersionFileDiff struct {
+	Path   string                    `json:"path"`
+	Status TemplateVersionDiffStatus `json:"status" enums:"added,removed,modified"`
+	// Binary is true if either side of the file is not text, in which case
+	// Diff is empty.
+	Binary bool `json:"binary"`
+	// Diff is a unified diff of the file contents.
+	Diff string `json:"diff,omitempty"`
+}
+
+// TemplateVersionItemDiff is a rich parameter, variable, preset or workspace
+// tag that was added, removed or modified.
+type TemplateVersionItemDiff struct {
+	Name   string                    `json:"name"`
+	Status TemplateVersionDiffStatus `json:"status" enums:"added,removed,modified"`
+	// Changes lists the fields of a modified item that differ.
+	Changes []TemplateVersionFieldChange `json:"changes,omitempty"`
+}
+
+// TemplateVersionFieldChange is a single field that differs between two
+// versions of an item. Values of sensitive variables are redacted.
+type TemplateVersionFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TemplateVersionDiff returns the changes from one template version to
+// another version of the same template.
+func (c *Client) TemplateVersionDiff(ctx context.Context, from, to uuid.UUID) (TemplateVersionDiff, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/diff/%s", from, to), nil)
+	if err != nil {
+		return TemplateVersionDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDiff{}, ReadBodyAsError(res)
+	}
+	var diff TemplateVersionDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -26,6 +27,9 @@ type (
 		Port       int32                           `json:"port"`
 		ShareLevel WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,public"`
 		Protocol   WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+		// ExpiresAt is the time after which the port is no longer shared. If
+		// unset, the share never expires.
+		ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
 	}
 	WorkspaceAgentPortShares struct {
 		Shares []WorkspaceAgentPortShare `json:"shares"`
@@ -36,6 +40,7 @@ type (
 		Port        int32                           `json:"port"`
 		ShareLevel  WorkspaceAgentPortShareLevel    `json:"share_level" enums:"owner,authenticated,public"`
 		Protocol    WorkspaceAgentPortShareProtocol `json:"protocol" enums:"http,https"`
+		ExpiresAt   *time.Time                      `json:"expires_at,omitempty" format:"date-time"`
 	}
 	DeleteWorkspaceAgentPortShareRequest struct {
 		AgentName string `json:"agent_name"`
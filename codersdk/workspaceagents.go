@@ -197,6 +197,25 @@ type WorkspaceAgentScript struct {
 	StartBlocksLogin bool          `json:"start_blocks_login"`
 	Timeout          time.Duration `json:"timeout"`
 	DisplayName      string        `json:"display_name"`
+	// DependsOn are the display names of the scripts that must be ready
+	// before this script is started.
+	DependsOn      []string                            `json:"depends_on"`
+	ReadinessProbe *WorkspaceAgentScriptReadinessProbe `json:"readiness_probe,omitempty"`
+}
+
+// WorkspaceAgentScriptReadinessProbe checks whether a script is ready for the
+// scripts that depend on it. Exactly one of Command, TCP and HTTP is set.
+// Scripts without a probe are ready once they exit successfully.
+type WorkspaceAgentScriptReadinessProbe struct {
+	// Command is ready once it exits successfully.
+	Command string `json:"command,omitempty"`
+	// TCP is an address, e.g. localhost:8080, that is ready once it accepts
+	// connections.
+	TCP string `json:"tcp,omitempty"`
+	// HTTP is a URL that is ready once it responds with a status below 400.
+	HTTP     string        `json:"http,omitempty"`
+	Interval time.Duration `json:"interval"`
+	Timeout  time.Duration `json:"timeout"`
 }
 
 type WorkspaceAgentHealth struct {
@@ -411,6 +430,27 @@ type WorkspaceAgentDevcontainer struct {
 	Running   bool                     `json:"running"`
 	Dirty     bool                     `json:"dirty"`
 	Container *WorkspaceAgentContainer `json:"container,omitempty"`
+	// ForwardedPorts are the forwardPorts of the devcontainer
+	// configuration. Labeled ports are registered as workspace apps.
+	ForwardedPorts []WorkspaceAgentDevcontainerPort `json:"forwarded_ports,omitempty"`
+	// Extensions are the VS Code extensions in the customizations of the
+	// devcontainer configuration.
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// WorkspaceAgentDevcontainerPort is a port of a devcontainer that is
+// forwarded to the workspace.
+type WorkspaceAgentDevcontainerPort struct {
+	Port uint16 `json:"port"`
+	// Label is the label of the port in portsAttributes, if any.
+	Label string `json:"label,omitempty"`
+	// Protocol is the protocol of the port in portsAttributes, "http" or
+	// "https", if any.
+	Protocol string `json:"protocol,omitempty"`
+	// Forwarded is true when the port is reachable on the loopback
+	// interface of the workspace. Ports that are in use in the workspace
+	// aren't forwarded.
+	Forwarded bool `json:"forwarded"`
 }
 
 // WorkspaceAgentContainer describes a devcontainer of some sort
@@ -469,6 +509,9 @@ type WorkspaceAgentContainerPort struct {
 type WorkspaceAgentListContainersResponse struct {
 	// Containers is a list of containers visible to the workspace agent.
 	Containers []WorkspaceAgentContainer `json:"containers"`
+	// Devcontainers is a list of the devcontainers known to the workspace
+	// agent, see WorkspaceAgentDevcontainersResponse.
+	Devcontainers []WorkspaceAgentDevcontainer `json:"devcontainers,omitempty"`
 	// Warnings is a list of warnings that may have occurred during the
 	// process of listing containers. This should not include fatal errors.
 	Warnings []string `json:"warnings,omitempty"`
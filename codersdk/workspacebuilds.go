@@ -204,6 +204,9 @@ type ProvisionerTiming struct {
 }
 
 type AgentScriptTiming struct {
+	// QueuedAt is when the script was due to run. The script waited for the
+	// scripts it depends on between QueuedAt and StartedAt.
+	QueuedAt           time.Time   `json:"queued_at" format:"date-time"`
 	StartedAt          time.Time   `json:"started_at" format:"date-time"`
 	EndedAt            time.Time   `json:"ended_at" format:"date-time"`
 	ExitCode           int32       `json:"exit_code"`
@@ -484,6 +484,88 @@ func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// WorkspaceRole is the access granted to a user or group a workspace is
+// shared with.
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleAdmin grants full control of the workspace, including
+	// sharing it with others.
+	WorkspaceRoleAdmin WorkspaceRole = "admin"
+	// WorkspaceRoleUse allows connecting to the workspace, its apps and
+	// ports.
+	WorkspaceRoleUse     WorkspaceRole = "use"
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+// WorkspaceACL lists the users and groups a workspace is shared with.
+type WorkspaceACL struct {
+	Users  []WorkspaceUser  `json:"users"`
+	Groups []WorkspaceGroup `json:"groups"`
+}
+
+type WorkspaceUser struct {
+	MinimalUser
+	Role WorkspaceRole `json:"role" enums:"admin,use"`
+}
+
+type WorkspaceGroup struct {
+	Group
+	Role WorkspaceRole `json:"role" enums:"admin,use"`
+}
+
+type UpdateWorkspaceACL struct {
+	// UserRoles is a mapping of user id to role. An empty role removes the
+	// user from the ACL.
+	UserRoles map[string]WorkspaceRole `json:"user_roles,omitempty" example:"4df59e74-c027-470b-ab4d-cbba8963a5e9:use"`
+	// GroupRoles is a mapping of group id to role. An empty role removes the
+	// group from the ACL.
+	GroupRoles map[string]WorkspaceRole `json:"group_roles,omitempty" example:"8bd26b20-f3e8-48be-a903-46bb920cf671:use"`
+}
+
+// WorkspaceACL returns the users and groups a workspace is shared with.
+func (c *Client) WorkspaceACL(ctx context.Context, id uuid.UUID) (WorkspaceACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/acl", id), nil)
+	if err != nil {
+		return WorkspaceACL{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// WorkspaceACLAvailable returns the users and groups a workspace can be
+// shared with.
+func (c *Client) WorkspaceACLAvailable(ctx context.Context, id uuid.UUID) (ACLAvailable, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/acl/available", id), nil)
+	if err != nil {
+		return ACLAvailable{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ACLAvailable{}, ReadBodyAsError(res)
+	}
+	var acl ACLAvailable
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateWorkspaceACL grants or revokes access to a workspace for the given
+// users and groups.
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, id uuid.UUID, req UpdateWorkspaceACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/acl", id), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
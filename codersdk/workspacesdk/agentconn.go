@@ -1,6 +1,7 @@
 package workspacesdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
@@ -9,6 +10,8 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
+	"os"
 	"strconv"
 	"time"
 
@@ -102,6 +105,9 @@ type AgentReconnectingPTYInit struct {
 	ContainerUser string
 
 	BackendType string
+	// ReadOnly attaches to an existing session without forwarding input or
+	// resizes from the connection. The session must already exist.
+	ReadOnly bool
 }
 
 // AgentReconnectingPTYInitOption is a functional option for AgentReconnectingPTYInit.
@@ -115,6 +121,14 @@ func AgentReconnectingPTYInitWithContainer(container, containerUser string) Agen
 	}
 }
 
+// AgentReconnectingPTYInitWithReadOnly attaches to an existing reconnecting
+// PTY session as a read-only viewer.
+func AgentReconnectingPTYInitWithReadOnly() AgentReconnectingPTYInitOption {
+	return func(init *AgentReconnectingPTYInit) {
+		init.ReadOnly = true
+	}
+}
+
 // ReconnectingPTYRequest is sent from the client to the server
 // to pipe data to a PTY.
 // @typescript-ignore ReconnectingPTYRequest
@@ -165,6 +179,54 @@ func (c *AgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, w
 	return conn, nil
 }
 
+// ReconnectingPTYSession is a live reconnecting PTY session in the workspace.
+// @typescript-ignore ReconnectingPTYSession
+type ReconnectingPTYSession struct {
+	ID        uuid.UUID `json:"id"`
+	Command   string    `json:"command"`
+	Container string    `json:"container,omitempty"`
+	// Height and Width are the size of the session as last set by a
+	// read-write connection.
+	Height      uint16                      `json:"height"`
+	Width       uint16                      `json:"width"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	Connections []ReconnectingPTYConnection `json:"connections"`
+}
+
+// ReconnectingPTYConnection is a client attached to a reconnecting PTY
+// session.
+// @typescript-ignore ReconnectingPTYConnection
+type ReconnectingPTYConnection struct {
+	ID          string    `json:"id"`
+	RemoteAddr  string    `json:"remote_addr"`
+	ReadOnly    bool      `json:"read_only"`
+	ConnectedAt time.Time `json:"connected_at"`
+}
+
+// ListReconnectingPTYsResponse lists the live reconnecting PTY sessions in
+// the workspace.
+// @typescript-ignore ListReconnectingPTYsResponse
+type ListReconnectingPTYsResponse struct {
+	Sessions []ReconnectingPTYSession `json:"sessions"`
+}
+
+// ListReconnectingPTYs lists the live reconnecting PTY sessions in the
+// workspace.
+func (c *AgentConn) ListReconnectingPTYs(ctx context.Context) (ListReconnectingPTYsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return ListReconnectingPTYsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ListReconnectingPTYsResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp ListReconnectingPTYsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // SSH pipes the SSH protocol over the returned net.Conn.
 // This connects to the built-in SSH server in the workspace agent.
 func (c *AgentConn) SSH(ctx context.Context) (*gonet.TCPConn, error) {
@@ -403,6 +465,251 @@ func (c *AgentConn) RecreateDevcontainer(ctx context.Context, containerIDOrName
 	return nil
 }
 
+// FileInfo describes a file in the workspace.
+// @typescript-ignore FileInfo
+type FileInfo struct {
+	Name string `json:"name"`
+	// e.g. "/home/coder/hello.txt"
+	AbsolutePathString string      `json:"absolute_path_string"`
+	Size               int64       `json:"size"`
+	Mode               os.FileMode `json:"mode"`
+	ModTime            time.Time   `json:"mod_time"`
+	IsDir              bool        `json:"is_dir"`
+}
+
+// MakeDirectoryRequest creates a directory and its missing parents.
+// @typescript-ignore MakeDirectoryRequest
+type MakeDirectoryRequest struct {
+	Path string `json:"path" validate:"required"`
+}
+
+// MoveFileRequest moves or renames a file or a directory.
+// @typescript-ignore MoveFileRequest
+type MoveFileRequest struct {
+	Source      string `json:"source" validate:"required"`
+	Destination string `json:"destination" validate:"required"`
+}
+
+// DeleteFileRequest deletes a file or a directory. Directories that aren't
+// empty are only deleted when Recursive is set.
+// @typescript-ignore DeleteFileRequest
+type DeleteFileRequest struct {
+	Path      string `json:"path" validate:"required"`
+	Recursive bool   `json:"recursive"`
+}
+
+// StatFile returns information about a file in the workspace. Relative paths
+// are relative to the home directory of the agent user.
+func (c *AgentConn) StatFile(ctx context.Context, path string) (FileInfo, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/stat-file?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return FileInfo{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return FileInfo{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp FileInfo
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ReadFile streams the contents of a file in the workspace, starting at
+// offset. At most limit bytes are read, or the rest of the file if limit is
+// zero. The caller must close the returned reader.
+func (c *AgentConn) ReadFile(ctx context.Context, path string, offset, limit int64) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	query := url.Values{"path": {path}}
+	if offset > 0 {
+		query.Set("offset", strconv.FormatInt(offset, 10))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.FormatInt(limit, 10))
+	}
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/read-file?"+query.Encode(), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WriteFile streams the contents of r to a file in the workspace. The file
+// and its missing parent directories are created, and an existing file is
+// truncated.
+func (c *AgentConn) WriteFile(ctx context.Context, path string, r io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/write-file?"+url.Values{"path": {path}}.Encode(), r)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// MakeDirectory creates a directory and its missing parents in the workspace.
+func (c *AgentConn) MakeDirectory(ctx context.Context, req MakeDirectoryRequest) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	return c.filesRequest(ctx, "/api/v0/make-directory", req)
+}
+
+// MoveFile moves or renames a file or a directory in the workspace.
+func (c *AgentConn) MoveFile(ctx context.Context, req MoveFileRequest) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	return c.filesRequest(ctx, "/api/v0/move-file", req)
+}
+
+// DeleteFile deletes a file or a directory in the workspace.
+func (c *AgentConn) DeleteFile(ctx context.Context, req DeleteFileRequest) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	return c.filesRequest(ctx, "/api/v0/delete-file", req)
+}
+
+// DownloadArchive streams a file or a directory in the workspace as a tar
+// archive, with entries rooted at the base name of path. The caller must
+// close the returned reader.
+func (c *AgentConn) DownloadArchive(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/download-archive?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// UploadArchive extracts the tar archive read from r into a directory in the
+// workspace, which is created if it doesn't exist.
+func (c *AgentConn) UploadArchive(ctx context.Context, dir string, r io.Reader) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/upload-archive?"+url.Values{"path": {dir}}.Encode(), r)
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// ProcessOwnerType is the kind of session that started a process.
+// @typescript-ignore ProcessOwnerType
+type ProcessOwnerType string
+
+const (
+	ProcessOwnerTypeSSH             ProcessOwnerType = "ssh"
+	ProcessOwnerTypeReconnectingPTY ProcessOwnerType = "reconnecting_pty"
+	ProcessOwnerTypeScript          ProcessOwnerType = "script"
+	ProcessOwnerTypeApp             ProcessOwnerType = "app"
+)
+
+// ProcessOwner is the session of the agent that started a process, or one of
+// its ancestors.
+// @typescript-ignore ProcessOwner
+type ProcessOwner struct {
+	Type ProcessOwnerType `json:"type"`
+	// ID is the ID of the SSH session or reconnecting PTY, the log source ID
+	// of the script, or the ID of the app.
+	ID string `json:"id"`
+	// Name is the session type of SSH sessions, e.g. "vscode", the command of
+	// reconnecting PTYs, the display name of scripts, or the slug of apps.
+	Name string `json:"name"`
+}
+
+// Process is a process running in the workspace.
+// @typescript-ignore Process
+type Process struct {
+	PID      int32  `json:"pid"`
+	PPID     int32  `json:"ppid"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Cmdline  string `json:"cmdline"`
+	// CPUPercent is the CPU usage of the process measured over a short
+	// interval, like the %CPU column of top. It may exceed 100 on multi-core
+	// systems.
+	CPUPercent    float64   `json:"cpu_percent"`
+	MemoryRSS     uint64    `json:"memory_rss"`
+	MemoryPercent float32   `json:"memory_percent"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Owner is nil for processes that weren't started by the agent, or whose
+	// owner could not be determined.
+	Owner *ProcessOwner `json:"owner,omitempty"`
+}
+
+// ListProcessesResponse lists the processes running in the workspace.
+// @typescript-ignore ListProcessesResponse
+type ListProcessesResponse struct {
+	Processes []Process `json:"processes"`
+}
+
+// SignalProcessRequest sends a signal to a process in the workspace.
+// @typescript-ignore SignalProcessRequest
+type SignalProcessRequest struct {
+	PID int32 `json:"pid" validate:"required"`
+	// Signal is the name of the signal, e.g. "TERM" or "KILL". It defaults to
+	// "TERM".
+	Signal string `json:"signal,omitempty"`
+}
+
+// ListProcesses lists the processes running in the workspace.
+func (c *AgentConn) ListProcesses(ctx context.Context) (ListProcessesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/processes", nil)
+	if err != nil {
+		return ListProcessesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ListProcessesResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp ListProcessesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// SignalProcess sends a signal to a process in the workspace.
+func (c *AgentConn) SignalProcess(ctx context.Context, req SignalProcessRequest) error {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	return c.filesRequest(ctx, "/api/v0/signal-process", req)
+}
+
+// filesRequest posts a JSON request to one of the agent's file or process
+// endpoints that respond with no content.
+func (c *AgentConn) filesRequest(ctx context.Context, path string, req any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return xerrors.Errorf("marshal request: %w", err)
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *AgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -191,6 +192,10 @@ type DialAgentOptions struct {
 	// Whether the client will send network telemetry events.
 	// Enable instead of Disable so it's initialized to false (in tests).
 	EnableTelemetry bool
+	// PreferredProxy is the name of the workspace proxy whose DERP relay
+	// should be used as the home region, or "primary" for the relay embedded
+	// in Coder. Agents homed on other regions are still reached through them.
+	PreferredProxy string
 }
 
 func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *DialAgentOptions) (agentConn *AgentConn, err error) {
@@ -202,6 +207,98 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 	if err != nil {
 		return nil, xerrors.Errorf("get connection info: %w", err)
 	}
+
+	coordinateURL, err := c.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+
+	tn, err := c.dialTailnet(dialCtx, coordinateURL, connInfo, options)
+	if err != nil {
+		return nil, err
+	}
+	tn.coordCtrl.AddDestination(agentID)
+
+	agentConn = NewAgentConn(tn.conn, AgentConnOptions{
+		AgentID:   agentID,
+		CloseFunc: tn.close,
+	})
+
+	if !agentConn.AwaitReachable(dialCtx) {
+		_ = agentConn.Close()
+		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", dialCtx.Err())
+	}
+
+	return agentConn, nil
+}
+
+// DialAgents opens a single tailnet connection that can tunnel to any of the
+// workspace agents the user can access, unlike DialAgent which opens a
+// connection per agent. Connections to individual agents are opened with
+// MultiAgentConn.AgentConn.
+func (c *Client) DialAgents(dialCtx context.Context, options *DialAgentOptions) (*MultiAgentConn, error) {
+	if options == nil {
+		options = &DialAgentOptions{}
+	}
+
+	connInfo, err := c.AgentConnectionInfoGeneric(dialCtx)
+	if err != nil {
+		return nil, xerrors.Errorf("get connection info: %w", err)
+	}
+
+	rpcURL, err := c.client.URL.Parse("/api/v2/tailnet")
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+
+	tn, err := c.dialTailnet(dialCtx, rpcURL, connInfo, options)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiAgentConn{tn: tn}, nil
+}
+
+// MultiAgentConn is a tailnet connection shared by connections to several
+// workspace agents.
+// @typescript-ignore MultiAgentConn
+type MultiAgentConn struct {
+	tn *tailnetConn
+}
+
+// AgentConn opens a tunnel to the agent over the shared tailnet connection and
+// waits for it to become reachable. Closing the returned AgentConn closes the
+// tunnel but leaves the shared connection open.
+func (c *MultiAgentConn) AgentConn(ctx context.Context, agentID uuid.UUID) (*AgentConn, error) {
+	c.tn.coordCtrl.AddDestination(agentID)
+	agentConn := NewAgentConn(c.tn.conn, AgentConnOptions{
+		AgentID: agentID,
+		CloseFunc: func() error {
+			c.tn.coordCtrl.RemoveDestination(agentID)
+			return ErrSkipClose
+		},
+	})
+	if !agentConn.AwaitReachable(ctx) {
+		_ = agentConn.Close()
+		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", ctx.Err())
+	}
+	return agentConn, nil
+}
+
+// Close closes the shared tailnet connection, and with it the connections to
+// all agents opened through it.
+func (c *MultiAgentConn) Close() error {
+	return c.tn.close()
+}
+
+// tailnetConn is a tailnet connection coordinated through the tailnet API at
+// a coderd URL. Tunnels to agents are added with coordCtrl.
+type tailnetConn struct {
+	conn      *tailnet.Conn
+	coordCtrl *tailnet.TunnelSrcCoordController
+	close     func() error
+}
+
+func (c *Client) dialTailnet(dialCtx context.Context, rpcURL *url.URL, connInfo AgentConnectionInfo, options *DialAgentOptions) (_ *tailnetConn, err error) {
 	if connInfo.DisableDirectConnections {
 		options.BlockEndpoints = true
 	}
@@ -222,12 +319,7 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		}
 	}()
 
-	coordinateURL, err := c.client.URL.Parse(fmt.Sprintf("/api/v2/workspaceagents/%s/coordinate", agentID))
-	if err != nil {
-		return nil, xerrors.Errorf("parse url: %w", err)
-	}
-
-	dialer := NewWebsocketDialer(options.Logger, coordinateURL, &websocket.DialOptions{
+	dialer := NewWebsocketDialer(options.Logger, rpcURL, &websocket.DialOptions{
 		HTTPClient: c.client.HTTPClient,
 		HTTPHeader: headers,
 		// Need to disable compression to avoid a data-race.
@@ -250,7 +342,7 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 	}
 	conn, err := tailnet.NewConn(&tailnet.Options{
 		Addresses:           []netip.Prefix{netip.PrefixFrom(ip, 128)},
-		DERPMap:             connInfo.DERPMap,
+		DERPMap:             preferProxyDERPRegion(connInfo.DERPMap, options.PreferredProxy),
 		DERPHeader:          &header,
 		DERPForceWebSockets: connInfo.DERPForceWebSockets,
 		Logger:              options.Logger,
@@ -268,9 +360,12 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		}
 	}()
 	coordCtrl := tailnet.NewTunnelSrcCoordController(options.Logger, conn)
-	coordCtrl.AddDestination(agentID)
 	controller.CoordCtrl = coordCtrl
-	controller.DERPCtrl = tailnet.NewBasicDERPController(options.Logger, conn)
+	var derpMapSetter tailnet.DERPMapSetter = conn
+	if options.PreferredProxy != "" {
+		derpMapSetter = preferProxyDERPMapSetter{setter: conn, proxy: options.PreferredProxy}
+	}
+	controller.DERPCtrl = tailnet.NewBasicDERPController(options.Logger, derpMapSetter)
 	controller.Run(ctx)
 
 	options.Logger.Debug(ctx, "running tailnet API v2+ connector")
@@ -286,21 +381,59 @@ func (c *Client) DialAgent(dialCtx context.Context, agentID uuid.UUID, options *
 		options.Logger.Debug(ctx, "connected to tailnet v2+ API")
 	}
 
-	agentConn = NewAgentConn(conn, AgentConnOptions{
-		AgentID: agentID,
-		CloseFunc: func() error {
+	return &tailnetConn{
+		conn:      conn,
+		coordCtrl: coordCtrl,
+		close: func() error {
 			cancel()
 			<-controller.Closed()
 			return conn.Close()
 		},
-	})
+	}, nil
+}
 
-	if !agentConn.AwaitReachable(dialCtx) {
-		_ = agentConn.Close()
-		return nil, xerrors.Errorf("timed out waiting for agent to become reachable: %w", dialCtx.Err())
+// preferredRegionScore scales the measured latency of the preferred proxy's
+// DERP region. Other regions keep a score of 1, so the preferred region becomes
+// the home region unless it is unreachable or far slower than the others.
+const preferredRegionScore = 0.01
+
+// preferProxyDERPRegion returns a copy of the DERP map that prefers the DERP
+// region of the named workspace proxy as the home region. Proxy regions have
+// the code coder_<name>, and "primary" is the relay embedded in Coder. The map
+// is returned unchanged if the proxy has no DERP region.
+func preferProxyDERPRegion(derpMap *tailcfg.DERPMap, proxy string) *tailcfg.DERPMap {
+	if derpMap == nil || proxy == "" {
+		return derpMap
 	}
+	for regionID, region := range derpMap.Regions {
+		if proxy == "primary" && !region.EmbeddedRelay {
+			continue
+		}
+		if proxy != "primary" && region.RegionCode != "coder_"+strings.ToLower(proxy) {
+			continue
+		}
+		derpMap = derpMap.Clone()
+		if derpMap.HomeParams == nil {
+			derpMap.HomeParams = &tailcfg.DERPHomeParams{}
+		}
+		if derpMap.HomeParams.RegionScore == nil {
+			derpMap.HomeParams.RegionScore = map[int]float64{}
+		}
+		derpMap.HomeParams.RegionScore[regionID] = preferredRegionScore
+		return derpMap
+	}
+	return derpMap
+}
 
-	return agentConn, nil
+// preferProxyDERPMapSetter prefers the DERP region of a workspace proxy in the
+// DERP maps sent by the coordinator.
+type preferProxyDERPMapSetter struct {
+	setter tailnet.DERPMapSetter
+	proxy  string
+}
+
+func (s preferProxyDERPMapSetter) SetDERPMap(derpMap *tailcfg.DERPMap) {
+	s.setter.SetDERPMap(preferProxyDERPRegion(derpMap, s.proxy))
 }
 
 // @typescript-ignore:WorkspaceAgentReconnectingPTYOpts
@@ -330,6 +463,10 @@ type WorkspaceAgentReconnectingPTYOpts struct {
 	// workspace agent will attempt to determine the preferred backend type.
 	// Supported values are "screen" and "buffered".
 	BackendType string
+
+	// ReadOnly attaches to the existing session with the Reconnect ID
+	// without forwarding input or resizes.
+	ReadOnly bool
 }
 
 // AgentReconnectingPTY spawns a PTY that reconnects using the token provided.
@@ -354,6 +491,9 @@ func (c *Client) AgentReconnectingPTY(ctx context.Context, opts WorkspaceAgentRe
 	if opts.BackendType != "" {
 		q.Set("backend_type", opts.BackendType)
 	}
+	if opts.ReadOnly {
+		q.Set("read_only", "true")
+	}
 	// If we're using a signed token, set the query parameter.
 	if opts.SignedToken != "" {
 		q.Set(codersdk.SignedAppTokenQueryParameter, opts.SignedToken)
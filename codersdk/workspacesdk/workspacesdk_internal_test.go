@@ -0,0 +1,48 @@
+package workspacesdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
+)
+
+func TestPreferProxyDERPRegion(t *testing.T) {
+	t.Parallel()
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			999:   {RegionID: 999, RegionCode: "coder", EmbeddedRelay: true},
+			10001: {RegionID: 10001, RegionCode: "coder_sydney"},
+		},
+	}
+
+	t.Run("Proxy", func(t *testing.T) {
+		t.Parallel()
+		got := preferProxyDERPRegion(derpMap, "Sydney")
+		require.Equal(t, map[int]float64{10001: preferredRegionScore}, got.HomeParams.RegionScore)
+		// The original map is left alone, since it may be shared.
+		require.Nil(t, derpMap.HomeParams)
+	})
+
+	t.Run("Primary", func(t *testing.T) {
+		t.Parallel()
+		got := preferProxyDERPRegion(derpMap, "primary")
+		require.Equal(t, map[int]float64{999: preferredRegionScore}, got.HomeParams.RegionScore)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		got := preferProxyDERPRegion(derpMap, "london")
+		require.Same(t, derpMap, got)
+	})
+
+	t.Run("KeepsServerScores", func(t *testing.T) {
+		t.Parallel()
+		withScores := derpMap.Clone()
+		withScores.HomeParams = &tailcfg.DERPHomeParams{RegionScore: map[int]float64{999: 2}}
+		got := preferProxyDERPRegion(withScores, "sydney")
+		require.Equal(t, map[int]float64{999: 2, 10001: preferredRegionScore}, got.HomeParams.RegionScore)
+		require.Equal(t, map[int]float64{999: 2}, withScores.HomeParams.RegionScore)
+	})
+}
@@ -0,0 +1,90 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceSnapshot is a named copy of the provisioner state, parameters and
+// template version of a workspace build. Restoring a snapshot starts a new
+// build from it.
+type WorkspaceSnapshot struct {
+	ID                  uuid.UUID                 `json:"id" format:"uuid"`
+	WorkspaceID         uuid.UUID                 `json:"workspace_id" format:"uuid"`
+	Name                string                    `json:"name"`
+	CreatedAt           time.Time                 `json:"created_at" format:"date-time"`
+	CreatedBy           MinimalUser               `json:"created_by"`
+	BuildNumber         int32                     `json:"build_number"`
+	TemplateVersionID   uuid.UUID                 `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string                    `json:"template_version_name"`
+	Parameters          []WorkspaceBuildParameter `json:"parameters"`
+}
+
+type CreateWorkspaceSnapshotRequest struct {
+	Name string `json:"name" validate:"required,workspace_name"`
+	// BuildNumber is the build to take the snapshot from. If omitted, the
+	// latest build of the workspace is used.
+	BuildNumber int32 `json:"build_number,omitempty"`
+}
+
+// CreateWorkspaceSnapshot records the state of a workspace build as a named
+// snapshot.
+func (c *Client) CreateWorkspaceSnapshot(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceSnapshotRequest) (WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), req)
+	if err != nil {
+		return WorkspaceSnapshot{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSnapshot{}, ReadBodyAsError(res)
+	}
+	var snapshot WorkspaceSnapshot
+	return snapshot, json.NewDecoder(res.Body).Decode(&snapshot)
+}
+
+// WorkspaceSnapshots returns the snapshots of a workspace, newest first.
+func (c *Client) WorkspaceSnapshots(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var snapshots []WorkspaceSnapshot
+	return snapshots, json.NewDecoder(res.Body).Decode(&snapshots)
+}
+
+// DeleteWorkspaceSnapshot deletes a snapshot of a workspace by name.
+func (c *Client) DeleteWorkspaceSnapshot(ctx context.Context, workspaceID uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s", workspaceID, name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// RestoreWorkspaceSnapshot starts a new build of the workspace from the
+// state, parameters and template version of a snapshot.
+func (c *Client) RestoreWorkspaceSnapshot(ctx context.Context, workspaceID uuid.UUID, name string) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s/restore", workspaceID, name), nil)
+	if err != nil {
+		return WorkspaceBuild{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
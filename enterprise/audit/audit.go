@@ -3,6 +3,7 @@ package audit
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -14,6 +15,17 @@ import (
 
 type BackendDetails struct {
 	Actor *Actor
+	// Chain is set once the audit log has been linked into the audit log
+	// chain by a LinkingBackend.
+	Chain *ChainLink
+}
+
+// ChainLink is the position of an audit log in the tamper-evident audit log
+// chain of its organization.
+type ChainLink struct {
+	Sequence int64
+	PrevHash []byte
+	Hash     []byte
 }
 
 type Actor struct {
@@ -30,6 +42,15 @@ type Backend interface {
 	Export(ctx context.Context, alog database.AuditLog, details BackendDetails) error
 }
 
+// LinkingBackend is a Backend that links audit logs into the audit log chain
+// as it stores them. Backends after it receive the resulting link.
+type LinkingBackend interface {
+	Backend
+	// ExportLinked stores an audit log and returns its link in the chain, or
+	// nil if the audit log was not linked.
+	ExportLinked(ctx context.Context, alog database.AuditLog, details BackendDetails) (*ChainLink, error)
+}
+
 func NewAuditor(db database.Store, filter Filter, backends ...Backend) audit.Auditor {
 	return &auditor{
 		db:       db,
@@ -61,22 +82,32 @@ func (a *auditor) Export(ctx context.Context, alog database.AuditLog) error {
 		return err
 	}
 
+	details := BackendDetails{Actor: &Actor{
+		ID:       actor.ID,
+		Email:    actor.Email,
+		Username: actor.Username,
+	}}
+	// A failing backend must not keep the audit log from the others, so
+	// every backend is tried and their errors are returned together.
+	var errs []error
 	for _, backend := range a.backends {
 		if decision&backend.Decision() != backend.Decision() {
 			continue
 		}
 
-		err = backend.Export(ctx, alog, BackendDetails{Actor: &Actor{
-			ID:       actor.ID,
-			Email:    actor.Email,
-			Username: actor.Username,
-		}})
+		if linking, ok := backend.(LinkingBackend); ok {
+			var link *ChainLink
+			link, err = linking.ExportLinked(ctx, alog, details)
+			if link != nil {
+				details.Chain = link
+			}
+		} else {
+			err = backend.Export(ctx, alog, details)
+		}
 		if err != nil {
-			// naively return the first error. should probably make this smarter
-			// by returning multiple errors.
-			return xerrors.Errorf("export audit log to backend: %w", err)
+			errs = append(errs, xerrors.Errorf("export audit log to backend %T: %w", backend, err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
 )
 
 func TestAuditor(t *testing.T) {
@@ -111,6 +113,67 @@ func TestAuditor(t *testing.T) {
 	}
 }
 
+func TestAuditorChainLink(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx      = context.Background()
+		db       = dbmem.New()
+		exporter = &testBackend{decision: audit.FilterDecisionExport}
+		auditor  = audit.NewAuditor(
+			db,
+			audit.FilterFunc(func(_ context.Context, _ database.AuditLog) (audit.FilterDecision, error) {
+				return audit.FilterDecisionStore | audit.FilterDecisionExport, nil
+			}),
+			backends.NewPostgres(db, true),
+			exporter,
+		)
+	)
+
+	// Audit logs of the same organization are linked in the same chain.
+	orgID := uuid.New()
+	for i := 0; i < 2; i++ {
+		alog := audittest.RandomLog()
+		alog.OrganizationID = orgID
+		require.NoError(t, auditor.Export(ctx, alog))
+	}
+
+	require.Len(t, exporter.alogs, 2)
+	first, second := exporter.alogs[0].details.Chain, exporter.alogs[1].details.Chain
+	require.NotNil(t, first)
+	require.NotNil(t, second)
+	require.EqualValues(t, 1, first.Sequence)
+	require.EqualValues(t, 2, second.Sequence)
+	require.Equal(t, first.Hash, second.PrevHash)
+}
+
+func TestAuditorBackendErrors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		firstErr  = xerrors.New("first backend errored")
+		secondErr = xerrors.New("second backend errored")
+		first     = &testBackend{decision: audit.FilterDecisionExport, err: firstErr}
+		second    = &testBackend{decision: audit.FilterDecisionExport, err: secondErr}
+		working   = &testBackend{decision: audit.FilterDecisionExport}
+		auditor   = audit.NewAuditor(
+			dbmem.New(),
+			audit.FilterFunc(func(_ context.Context, _ database.AuditLog) (audit.FilterDecision, error) {
+				return audit.FilterDecisionExport, nil
+			}),
+			first,
+			second,
+			working,
+		)
+	)
+
+	err := auditor.Export(context.Background(), audittest.RandomLog())
+	require.ErrorIs(t, err, firstErr)
+	require.ErrorIs(t, err, secondErr)
+	// The failing backends don't keep the audit log from the others.
+	require.Len(t, working.alogs, 1)
+}
+
 type testBackend struct {
 	decision audit.FilterDecision
 	err      error
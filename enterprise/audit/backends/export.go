@@ -0,0 +1,83 @@
+package backends
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// exportedAuditLog is the JSON representation of an audit log that is sent to
+// external systems. It is intentionally decoupled from database.AuditLog so
+// that schema changes don't silently change the export format.
+type exportedAuditLog struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	UserID           uuid.UUID       `json:"user_id"`
+	Actor            *audit.Actor    `json:"actor,omitempty"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+	// ChainSequence, PrevHash and Hash place the audit log in the audit log
+	// chain, so that receivers can detect missing or modified entries.
+	ChainSequence int64  `json:"chain_sequence,omitempty"`
+	PrevHash      string `json:"prev_hash,omitempty"`
+	Hash          string `json:"hash,omitempty"`
+}
+
+// marshalAuditLog encodes an audit log as a single line of JSON, without a
+// trailing newline.
+func marshalAuditLog(alog database.AuditLog, details audit.BackendDetails) ([]byte, error) {
+	exported := exportedAuditLog{
+		ID:               alog.ID,
+		Time:             alog.Time.UTC(),
+		OrganizationID:   alog.OrganizationID,
+		UserID:           alog.UserID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     string(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		Action:           string(alog.Action),
+		Diff:             rawJSONOrNull(alog.Diff),
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: rawJSONOrNull(alog.AdditionalFields),
+		RequestID:        alog.RequestID,
+	}
+	if alog.Ip.Valid {
+		exported.IP = alog.Ip.IPNet.IP.String()
+	}
+	if details.Actor != nil && details.Actor.ID != uuid.Nil {
+		exported.Actor = details.Actor
+	}
+	if details.Chain != nil {
+		exported.ChainSequence = details.Chain.Sequence
+		exported.PrevHash = hex.EncodeToString(details.Chain.PrevHash)
+		exported.Hash = hex.EncodeToString(details.Chain.Hash)
+	}
+
+	data, err := json.Marshal(exported)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal audit log: %w", err)
+	}
+	return data, nil
+}
+
+func rawJSONOrNull(data []byte) json.RawMessage {
+	if len(data) == 0 || !json.Valid(data) {
+		return json.RawMessage("null")
+	}
+	return data
+}
@@ -0,0 +1,78 @@
+package backends
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// FileBackend writes audit logs to a file as newline-delimited JSON. The file
+// is rotated once it reaches its maximum size.
+type FileBackend struct {
+	mu     sync.Mutex
+	writer *lumberjack.Logger
+	closed bool
+}
+
+var _ audit.Backend = &FileBackend{}
+
+func NewFile(cfg codersdk.AuditLoggingFileConfig) (*FileBackend, error) {
+	if cfg.Path.String() == "" {
+		return nil, xerrors.New("file path must be set")
+	}
+	if cfg.MaxSize.Value() <= 0 {
+		return nil, xerrors.Errorf("file max size must be positive, got %d", cfg.MaxSize.Value())
+	}
+	if cfg.MaxBackups.Value() < 0 {
+		return nil, xerrors.Errorf("file max backups must not be negative, got %d", cfg.MaxBackups.Value())
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path.String()), 0o700); err != nil {
+		return nil, xerrors.Errorf("create audit log directory: %w", err)
+	}
+
+	return &FileBackend{
+		writer: &lumberjack.Logger{
+			Filename:   cfg.Path.String(),
+			MaxSize:    int(cfg.MaxSize.Value()), // MB
+			MaxBackups: int(cfg.MaxBackups.Value()),
+		},
+	}, nil
+}
+
+func (*FileBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *FileBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	data, err := marshalAuditLog(alog, details)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// lumberjack reopens the file on write, even after it has been closed.
+	if b.closed {
+		return xerrors.New("file backend is closed")
+	}
+	if _, err := b.writer.Write(data); err != nil {
+		return xerrors.Errorf("write audit log to file: %w", err)
+	}
+	return nil
+}
+
+func (b *FileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return b.writer.Close()
+}
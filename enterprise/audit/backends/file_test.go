@@ -0,0 +1,143 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path := filepath.Join(t.TempDir(), "audit", "audit.log")
+		backend, err := backends.NewFile(codersdk.AuditLoggingFileConfig{
+			Path:       serpent.String(path),
+			MaxSize:    1,
+			MaxBackups: 1,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		alog := audittest.RandomLog()
+		actor := &audit.Actor{ID: uuid.New(), Username: "bob", Email: "bob@coder.com"}
+		require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{Actor: actor}))
+
+		lines := readLines(t, path)
+		require.Len(t, lines, 1)
+		var got struct {
+			ID           uuid.UUID    `json:"id"`
+			Action       string       `json:"action"`
+			ResourceType string       `json:"resource_type"`
+			UserAgent    string       `json:"user_agent"`
+			Actor        *audit.Actor `json:"actor"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+		require.Equal(t, alog.ID, got.ID)
+		require.Equal(t, string(alog.Action), got.Action)
+		require.Equal(t, string(alog.ResourceType), got.ResourceType)
+		require.Equal(t, alog.UserAgent.String, got.UserAgent)
+		require.Equal(t, actor, got.Actor)
+
+		// Audit logs can't be written once the backend is closed.
+		require.NoError(t, backend.Close())
+		require.Error(t, backend.Export(ctx, alog, audit.BackendDetails{}))
+	})
+
+	t.Run("Rotates", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dir := t.TempDir()
+		path := filepath.Join(dir, "audit.log")
+		backend, err := backends.NewFile(codersdk.AuditLoggingFileConfig{
+			Path:       serpent.String(path),
+			MaxSize:    1,
+			MaxBackups: 1,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		// Write a little over a megabyte of audit logs.
+		var written int64
+		for written < 1024*1024 {
+			require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			if info.Size() < written {
+				break
+			}
+			written = info.Size()
+		}
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2, "expected the file to be rotated")
+	})
+
+	t.Run("RespectsFilterDecision", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		path := filepath.Join(t.TempDir(), "audit.log")
+		backend, err := backends.NewFile(codersdk.AuditLoggingFileConfig{
+			Path:       serpent.String(path),
+			MaxSize:    1,
+			MaxBackups: 1,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		var decision audit.FilterDecision
+		auditor := audit.NewAuditor(dbmem.New(), audit.FilterFunc(func(context.Context, database.AuditLog) (audit.FilterDecision, error) {
+			return decision, nil
+		}), backend)
+
+		// Audit logs which should only be stored are not exported.
+		decision = audit.FilterDecisionStore
+		require.NoError(t, auditor.Export(ctx, audittest.RandomLog()))
+		require.Empty(t, readLines(t, path))
+
+		decision = audit.FilterDecisionStore | audit.FilterDecisionExport
+		require.NoError(t, auditor.Export(ctx, audittest.RandomLog()))
+		require.Len(t, readLines(t, path), 1)
+	})
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
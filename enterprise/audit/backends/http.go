@@ -0,0 +1,221 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/quartz"
+	"github.com/coder/retry"
+)
+
+const httpRequestTimeout = 30 * time.Second
+
+// HTTPBackend sends batches of audit logs to an HTTP endpoint as
+// newline-delimited JSON. Audit logs are queued on disk until they have been
+// delivered, so that they survive restarts and outages of the endpoint.
+// Batches that the endpoint rejects with a status code that isn't worth
+// retrying are logged, counted in Metrics and dropped.
+type HTTPBackend struct {
+	log     slog.Logger
+	metrics *Metrics
+	client  *http.Client
+	url     string
+	headers http.Header
+	clock   quartz.Clock
+	flush   time.Duration
+	queue   *diskQueue
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ audit.Backend = &HTTPBackend{}
+
+// NewHTTP starts sending queued audit logs in the background. Close must be
+// called to stop it.
+func NewHTTP(logger slog.Logger, cfg codersdk.AuditLoggingHTTPConfig, clk quartz.Clock, metrics *Metrics) (*HTTPBackend, error) {
+	if cfg.URL.String() == "" {
+		return nil, xerrors.New("http url must be set")
+	}
+	if cfg.QueueDir.String() == "" {
+		return nil, xerrors.New("http queue directory must be set")
+	}
+	if cfg.BatchSize.Value() <= 0 {
+		return nil, xerrors.Errorf("http batch size must be positive, got %d", cfg.BatchSize.Value())
+	}
+	if cfg.FlushInterval.Value() <= 0 {
+		return nil, xerrors.Errorf("http flush interval must be positive, got %s", cfg.FlushInterval.Value())
+	}
+	if cfg.QueueMaxSize.Value() <= 0 {
+		return nil, xerrors.Errorf("http queue max size must be positive, got %d", cfg.QueueMaxSize.Value())
+	}
+
+	headers := make(http.Header)
+	for _, h := range cfg.Headers.Value() {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, xerrors.Errorf("invalid http header %q, expected 'Name: value'", h)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+
+	queue, err := openDiskQueue(cfg.QueueDir.String(), cfg.QueueMaxSize.Value()*1024*1024, int(cfg.BatchSize.Value()))
+	if err != nil {
+		return nil, xerrors.Errorf("open queue: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &HTTPBackend{
+		log:     logger,
+		metrics: metrics,
+		client:  &http.Client{Timeout: httpRequestTimeout},
+		url:     cfg.URL.String(),
+		headers: headers,
+		clock:   clk,
+		flush:   cfg.FlushInterval.Value(),
+		queue:   queue,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go b.run(ctx)
+
+	return b, nil
+}
+
+func (*HTTPBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *HTTPBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	data, err := marshalAuditLog(alog, details)
+	if err != nil {
+		return err
+	}
+	if err := b.queue.Append(data); err != nil {
+		if xerrors.Is(err, errQueueFull) {
+			b.metrics.drop(BackendHTTP, ReasonQueueFull, 1)
+		}
+		return xerrors.Errorf("queue audit log: %w", err)
+	}
+	return nil
+}
+
+func (b *HTTPBackend) run(ctx context.Context) {
+	defer close(b.done)
+
+	ticker := b.clock.NewTicker(b.flush, "HTTPBackend", "flush")
+	defer ticker.Stop()
+
+	for {
+		for {
+			seg, ok := b.queue.Peek()
+			if !ok {
+				break
+			}
+			if !b.deliver(ctx, seg) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.queue.ready:
+		case <-ticker.C:
+			b.queue.Seal()
+		}
+	}
+}
+
+// deliver sends a segment, retrying until it has been accepted or rejected.
+// It returns false if the context was canceled first.
+func (b *HTTPBackend) deliver(ctx context.Context, seg queueSegment) bool {
+	logger := b.log.With(slog.F("segment", seg.path))
+
+	body, err := os.ReadFile(seg.path)
+	if err != nil {
+		// The segment can't be recovered, so don't let it block the queue.
+		logger.Error(ctx, "failed to read audit log export queue segment, dropping it", slog.Error(err))
+		b.remove(ctx, seg)
+		return true
+	}
+
+	for r := retry.New(250*time.Millisecond, time.Minute); r.Wait(ctx); {
+		retryable, err := b.send(ctx, body)
+		if err == nil {
+			b.remove(ctx, seg)
+			return true
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if !retryable {
+			count := bytes.Count(body, []byte{'\n'})
+			b.metrics.drop(BackendHTTP, ReasonRejected, count)
+			logger.Error(ctx, "audit log batch rejected by http endpoint, dropping it",
+				slog.F("audit_logs", count),
+				slog.Error(err),
+			)
+			b.remove(ctx, seg)
+			return true
+		}
+		logger.Warn(ctx, "failed to send audit log batch, retrying", slog.Error(err))
+	}
+	return false
+}
+
+func (b *HTTPBackend) remove(ctx context.Context, seg queueSegment) {
+	if err := b.queue.Remove(seg); err != nil {
+		b.log.Error(ctx, "failed to remove audit log export queue segment", slog.F("segment", seg.path), slog.Error(err))
+	}
+}
+
+func (b *HTTPBackend) send(ctx context.Context, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return false, xerrors.Errorf("create request: %w", err)
+	}
+	for name, values := range b.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return true, xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	// The response body usually explains why a batch was rejected.
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500,
+		resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests:
+		return true, xerrors.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	default:
+		return false, xerrors.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+}
+
+// Close stops sending audit logs. Audit logs which haven't been delivered yet
+// remain queued on disk and are sent once the backend is started again.
+func (b *HTTPBackend) Close() error {
+	b.cancel()
+	<-b.done
+	return b.queue.Close()
+}
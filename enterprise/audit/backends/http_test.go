@@ -0,0 +1,233 @@
+package backends_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+	"github.com/coder/serpent"
+)
+
+func TestHTTPBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		batches := make(chan []uuid.UUID, 4)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			batches <- readBatch(t, r)
+		}))
+		t.Cleanup(srv.Close)
+
+		clk := quartz.NewMock(t)
+		trap := clk.Trap().NewTicker("HTTPBackend", "flush")
+		defer trap.Close()
+		cfg := httpConfig(t, srv.URL)
+		cfg.Headers = serpent.StringArray{"Authorization: Bearer secret"}
+		backend, err := backends.NewHTTP(slogtest.Make(t, nil), cfg, clk, backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+		trap.MustWait(ctx).Release()
+
+		// A full batch is sent immediately.
+		first, second := audittest.RandomLog(), audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, first, audit.BackendDetails{}))
+		require.NoError(t, backend.Export(ctx, second, audit.BackendDetails{}))
+		require.Equal(t, []uuid.UUID{first.ID, second.ID}, testutil.RequireReceive(ctx, t, batches))
+
+		// A partial batch is sent once the flush interval elapses.
+		third := audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, third, audit.BackendDetails{}))
+		clk.Advance(cfg.FlushInterval.Value()).MustWait(ctx)
+		require.Equal(t, []uuid.UUID{third.ID}, testutil.RequireReceive(ctx, t, batches))
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var attempts atomic.Int64
+		batches := make(chan []uuid.UUID, 4)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			batches <- readBatch(t, r)
+		}))
+		t.Cleanup(srv.Close)
+
+		backend, err := backends.NewHTTP(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), httpConfig(t, srv.URL), quartz.NewMock(t), backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		first, second := audittest.RandomLog(), audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, first, audit.BackendDetails{}))
+		require.NoError(t, backend.Export(ctx, second, audit.BackendDetails{}))
+		require.Equal(t, []uuid.UUID{first.ID, second.ID}, testutil.RequireReceive(ctx, t, batches))
+		require.EqualValues(t, 2, attempts.Load())
+	})
+
+	t.Run("DropsRejectedBatches", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var attempts atomic.Int64
+		batches := make(chan []uuid.UUID, 4)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			batches <- readBatch(t, r)
+		}))
+		t.Cleanup(srv.Close)
+
+		metrics := backends.NewMetrics(prometheus.NewRegistry())
+		backend, err := backends.NewHTTP(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), httpConfig(t, srv.URL), quartz.NewMock(t), metrics)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		alogs := []uuid.UUID{}
+		for range 4 {
+			alog := audittest.RandomLog()
+			alogs = append(alogs, alog.ID)
+			require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{}))
+		}
+		// The first batch is rejected and not sent again.
+		require.Equal(t, alogs[2:], testutil.RequireReceive(ctx, t, batches))
+		require.EqualValues(t, 2, attempts.Load())
+		require.EqualValues(t, 2, promtest.ToFloat64(metrics.Dropped.WithLabelValues(backends.BackendHTTP, backends.ReasonRejected)))
+	})
+
+	t.Run("PersistsAcrossRestarts", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		var available atomic.Bool
+		batches := make(chan []uuid.UUID, 4)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if !available.Load() {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			batches <- readBatch(t, r)
+		}))
+		t.Cleanup(srv.Close)
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		cfg := httpConfig(t, srv.URL)
+		backend, err := backends.NewHTTP(logger, cfg, quartz.NewMock(t), backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+
+		// Given: a full batch and a partial one, neither of which can be delivered.
+		var alogs []uuid.UUID
+		for range 3 {
+			alog := audittest.RandomLog()
+			alogs = append(alogs, alog.ID)
+			require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{}))
+		}
+		require.NoError(t, backend.Close())
+
+		// When: the backend is started again once the endpoint is available.
+		available.Store(true)
+		backend, err = backends.NewHTTP(logger, cfg, quartz.NewMock(t), backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		// Then: both batches are delivered in order.
+		require.Equal(t, alogs[:2], testutil.RequireReceive(ctx, t, batches))
+		require.Equal(t, alogs[2:], testutil.RequireReceive(ctx, t, batches))
+	})
+
+	t.Run("QueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+
+		// Given: a partially written audit log which fills the queue.
+		cfg := httpConfig(t, srv.URL)
+		cfg.QueueMaxSize = 1
+		err := os.WriteFile(filepath.Join(cfg.QueueDir.String(), "00000000000000000001.jsonl"), make([]byte, 1024*1024), 0o600)
+		require.NoError(t, err)
+
+		backend, err := backends.NewHTTP(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), cfg, quartz.NewMock(t), backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		// Then: the partial line is discarded, so there is room for more.
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+
+		// When: the queue is filled up.
+		for {
+			err = backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+			if err != nil {
+				break
+			}
+		}
+		// Then: audit logs are rejected.
+		require.ErrorContains(t, err, "queue is full")
+	})
+}
+
+func httpConfig(t *testing.T, rawURL string) codersdk.AuditLoggingHTTPConfig {
+	t.Helper()
+
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return codersdk.AuditLoggingHTTPConfig{
+		URL:           serpent.URL(*u),
+		BatchSize:     2,
+		FlushInterval: serpent.Duration(time.Minute),
+		QueueDir:      serpent.String(t.TempDir()),
+		QueueMaxSize:  1,
+	}
+}
+
+func readBatch(t *testing.T, r *http.Request) []uuid.UUID {
+	t.Helper()
+
+	var ids []uuid.UUID
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		var alog struct {
+			ID uuid.UUID `json:"id"`
+		}
+		if !json.Valid(scanner.Bytes()) {
+			t.Errorf("invalid audit log %q", scanner.Text())
+			continue
+		}
+		_ = json.Unmarshal(scanner.Bytes(), &alog)
+		ids = append(ids, alog.ID)
+	}
+	return ids
+}
@@ -0,0 +1,47 @@
+package backends
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsNamespace = "coderd"
+	metricsSubsystem = "audit_export"
+
+	LabelBackend = "backend"
+	LabelReason  = "reason"
+
+	BackendSyslog = "syslog"
+	BackendHTTP   = "http"
+
+	// ReasonQueueFull means the audit log was exported faster than the
+	// backend could deliver it.
+	ReasonQueueFull = "queue_full"
+	// ReasonSendFailed means the audit log couldn't be delivered.
+	ReasonSendFailed = "send_failed"
+	// ReasonRejected means the receiver rejected the audit log.
+	ReasonRejected = "rejected"
+	// ReasonStopped means the backend was closed before the audit log was
+	// delivered.
+	ReasonStopped = "stopped"
+)
+
+// Metrics are shared by the export backends that deliver audit logs over the
+// network, which drop audit logs rather than stall audited requests.
+type Metrics struct {
+	Dropped *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	return &Metrics{
+		Dropped: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "dropped_total", Namespace: metricsNamespace, Subsystem: metricsSubsystem,
+			Help: "The number of audit logs that were not exported, aggregated by backend and reason.",
+		}, []string{LabelBackend, LabelReason}),
+	}
+}
+
+func (m *Metrics) drop(backend, reason string, n int) {
+	m.Dropped.WithLabelValues(backend, reason).Add(float64(n))
+}
@@ -2,10 +2,13 @@ package backends
 
 import (
 	"context"
+	"database/sql"
 
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/audit"
 )
 
@@ -30,11 +33,71 @@ func (b *postgresBackend) Decision() audit.FilterDecision {
 	return audit.FilterDecisionExport
 }
 
-func (b *postgresBackend) Export(ctx context.Context, alog database.AuditLog, _ audit.BackendDetails) error {
-	_, err := b.db.InsertAuditLog(ctx, database.InsertAuditLogParams(alog))
-	if err != nil {
-		return xerrors.Errorf("insert audit log: %w", err)
+func (b *postgresBackend) Export(ctx context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	_, err := b.ExportLinked(ctx, alog, details)
+	return err
+}
+
+// ExportLinked inserts the audit log and, when storing to the Coderd
+// database, appends it to the audit log chain of its organization in the same
+// transaction. A lock per chain serializes inserts so that every entry links
+// to the one before it, without serializing inserts across organizations.
+func (b *postgresBackend) ExportLinked(ctx context.Context, alog database.AuditLog, _ audit.BackendDetails) (*audit.ChainLink, error) {
+	if !b.internal {
+		_, err := b.db.InsertAuditLog(ctx, database.InsertAuditLogParams(alog))
+		if err != nil {
+			return nil, xerrors.Errorf("insert audit log: %w", err)
+		}
+		return nil, nil
 	}
 
-	return nil
+	var link *audit.ChainLink
+	err := b.db.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.GenLockID("audit_log_chain:"+alog.OrganizationID.String()))
+		if err != nil {
+			return xerrors.Errorf("acquire audit log chain lock: %w", err)
+		}
+
+		inserted, err := tx.InsertAuditLog(ctx, database.InsertAuditLogParams(alog))
+		if err != nil {
+			return xerrors.Errorf("insert audit log: %w", err)
+		}
+
+		sequence, prevHash := int64(1), codersdk.AuditLogChainGenesisHash
+		head, err := tx.GetAuditLogChainHead(ctx, inserted.OrganizationID)
+		switch {
+		case err == nil:
+			sequence, prevHash = head.Sequence+1, head.Hash
+		case !xerrors.Is(err, sql.ErrNoRows):
+			return xerrors.Errorf("get audit log chain head: %w", err)
+		}
+
+		// Hash the audit log as it was stored, so that verification
+		// against the database reproduces the same hash.
+		hash, err := codersdk.AuditLogChainHash(prevHash, sequence, db2sdk.AuditLogChainRecord(inserted))
+		if err != nil {
+			return xerrors.Errorf("hash audit log: %w", err)
+		}
+		_, err = tx.InsertAuditLogChainEntry(ctx, database.InsertAuditLogChainEntryParams{
+			OrganizationID: inserted.OrganizationID,
+			Sequence:       sequence,
+			AuditLogID:     inserted.ID,
+			PrevHash:       prevHash,
+			Hash:           hash,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert audit log chain entry: %w", err)
+		}
+
+		link = &audit.ChainLink{
+			Sequence: sequence,
+			PrevHash: prevHash,
+			Hash:     hash,
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
 }
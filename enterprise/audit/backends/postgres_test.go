@@ -2,12 +2,16 @@ package backends_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/audit/audittest"
 	"github.com/coder/coder/v2/enterprise/audit/backends"
@@ -37,4 +41,69 @@ func TestPostgresBackend(t *testing.T) {
 		require.Len(t, got, 1)
 		require.Equal(t, alog.ID, got[0].AuditLog.ID)
 	})
+	t.Run("Chain", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			db          = dbmem.New()
+			pgb         = backends.NewPostgres(db, true)
+		)
+		defer cancel()
+
+		linking, ok := pgb.(audit.LinkingBackend)
+		require.True(t, ok)
+
+		orgID := uuid.New()
+		prevHash := codersdk.AuditLogChainGenesisHash
+		for i := int64(1); i <= 3; i++ {
+			alog := audittest.RandomLog()
+			alog.OrganizationID = orgID
+			link, err := linking.ExportLinked(ctx, alog, audit.BackendDetails{})
+			require.NoError(t, err)
+			require.NotNil(t, link)
+			require.Equal(t, i, link.Sequence)
+			require.Equal(t, prevHash, link.PrevHash)
+
+			logs, err := db.GetAuditLogsByIDs(ctx, []uuid.UUID{alog.ID})
+			require.NoError(t, err)
+			require.Len(t, logs, 1)
+			hash, err := codersdk.AuditLogChainHash(prevHash, i, db2sdk.AuditLogChainRecord(logs[0]))
+			require.NoError(t, err)
+			require.Equal(t, hash, link.Hash)
+			prevHash = link.Hash
+		}
+
+		entries, err := db.GetAuditLogChainEntries(ctx, database.GetAuditLogChainEntriesParams{
+			OrganizationID: orgID,
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, prevHash, entries[2].Hash)
+
+		// Audit logs of another organization start a chain of their own.
+		link, err := linking.ExportLinked(ctx, audittest.RandomLog(), audit.BackendDetails{})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, link.Sequence)
+		require.Equal(t, codersdk.AuditLogChainGenesisHash, link.PrevHash)
+	})
+
+	t.Run("ExternalNotLinked", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			db          = dbmem.New()
+			pgb         = backends.NewPostgres(db, false)
+		)
+		defer cancel()
+
+		alog := audittest.RandomLog()
+		link, err := pgb.(audit.LinkingBackend).ExportLinked(ctx, alog, audit.BackendDetails{})
+		require.NoError(t, err)
+		require.Nil(t, link)
+
+		_, err = db.GetAuditLogChainHead(ctx, alog.OrganizationID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
 }
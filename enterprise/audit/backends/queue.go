@@ -0,0 +1,222 @@
+package backends
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+const queueSegmentExt = ".jsonl"
+
+var errQueueFull = xerrors.New("audit log export queue is full")
+
+// queueSegment is a file of newline-delimited audit logs which are sent to
+// the destination together.
+type queueSegment struct {
+	seq  uint64
+	path string
+	size int64
+}
+
+// diskQueue is a bounded, persistent FIFO queue of JSON lines. Lines are
+// appended to an active segment, which is sealed once it holds batchSize lines
+// or when Seal is called. Sealed segments are consumed oldest first.
+//
+// Segments which are left behind when the process exits, including the active
+// one, are picked up again when the queue is next opened.
+type diskQueue struct {
+	dir       string
+	maxBytes  int64
+	batchSize int
+
+	mu          sync.Mutex
+	nextSeq     uint64
+	active      *os.File
+	activeSeg   queueSegment
+	activeCount int
+	sealed      []queueSegment
+	totalBytes  int64
+	closed      bool
+
+	// ready receives a value whenever a segment is sealed.
+	ready chan struct{}
+}
+
+func openDiskQueue(dir string, maxBytes int64, batchSize int) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, xerrors.Errorf("create queue directory: %w", err)
+	}
+
+	q := &diskQueue{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		batchSize: batchSize,
+		ready:     make(chan struct{}, 1),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("read queue directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, queueSegmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, queueSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		seg, err := recoverSegment(filepath.Join(dir, name), seq)
+		if err != nil {
+			return nil, err
+		}
+		if seq >= q.nextSeq {
+			q.nextSeq = seq + 1
+		}
+		if seg.size == 0 {
+			_ = os.Remove(seg.path)
+			continue
+		}
+		q.sealed = append(q.sealed, seg)
+		q.totalBytes += seg.size
+	}
+	sort.Slice(q.sealed, func(i, j int) bool {
+		return q.sealed[i].seq < q.sealed[j].seq
+	})
+	if len(q.sealed) > 0 {
+		q.signal()
+	}
+
+	return q, nil
+}
+
+// recoverSegment truncates a partially written trailing line, which is left
+// behind if the process exits in the middle of a write.
+func recoverSegment(path string, seq uint64) (queueSegment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return queueSegment{}, xerrors.Errorf("read queue segment: %w", err)
+	}
+	size := int64(bytes.LastIndexByte(data, '\n') + 1)
+	if size != int64(len(data)) {
+		if err := os.Truncate(path, size); err != nil {
+			return queueSegment{}, xerrors.Errorf("truncate queue segment: %w", err)
+		}
+	}
+	return queueSegment{seq: seq, path: path, size: size}, nil
+}
+
+// Append adds a line to the queue. errQueueFull is returned if there is no
+// room left for it.
+func (q *diskQueue) Append(line []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return xerrors.New("queue is closed")
+	}
+	n := int64(len(line) + 1)
+	if q.totalBytes+n > q.maxBytes {
+		return errQueueFull
+	}
+
+	if q.active == nil {
+		seg := queueSegment{
+			seq:  q.nextSeq,
+			path: filepath.Join(q.dir, fmt.Sprintf("%020d%s", q.nextSeq, queueSegmentExt)),
+		}
+		f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_EXCL, 0o600)
+		if err != nil {
+			return xerrors.Errorf("create queue segment: %w", err)
+		}
+		q.nextSeq++
+		q.active = f
+		q.activeSeg = seg
+		q.activeCount = 0
+	}
+
+	buf := make([]byte, 0, n)
+	buf = append(buf, line...)
+	buf = append(buf, '\n')
+	if _, err := q.active.Write(buf); err != nil {
+		return xerrors.Errorf("write queue segment: %w", err)
+	}
+	q.activeSeg.size += n
+	q.activeCount++
+	q.totalBytes += n
+
+	if q.activeCount >= q.batchSize {
+		q.sealLocked()
+	}
+	return nil
+}
+
+// Seal seals the active segment, if there is one, so that it can be consumed.
+func (q *diskQueue) Seal() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sealLocked()
+}
+
+func (q *diskQueue) sealLocked() {
+	if q.active == nil {
+		return
+	}
+	_ = q.active.Close()
+	q.active = nil
+	q.sealed = append(q.sealed, q.activeSeg)
+	q.signal()
+}
+
+func (q *diskQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Peek returns the oldest sealed segment.
+func (q *diskQueue) Peek() (queueSegment, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.sealed) == 0 {
+		return queueSegment{}, false
+	}
+	return q.sealed[0], true
+}
+
+// Remove deletes a segment returned by Peek once it has been consumed.
+func (q *diskQueue) Remove(seg queueSegment) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.sealed) == 0 || q.sealed[0].seq != seg.seq {
+		return xerrors.Errorf("segment %d is not at the head of the queue", seg.seq)
+	}
+	if err := os.Remove(seg.path); err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("remove queue segment: %w", err)
+	}
+	q.sealed = q.sealed[1:]
+	q.totalBytes -= seg.size
+	return nil
+}
+
+// Close closes the active segment. Queued lines are kept on disk.
+func (q *diskQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	if q.active == nil {
+		return nil
+	}
+	err := q.active.Close()
+	q.active = nil
+	return err
+}
@@ -0,0 +1,317 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+const (
+	// syslogPriority is the PRI of every message, calculated as
+	// facility*8 + severity. Audit logs use the "log audit" facility (13) and
+	// the "informational" severity (6).
+	syslogPriority = 13*8 + 6
+	syslogAppName  = "coder"
+	syslogMsgID    = "audit"
+
+	syslogDialTimeout  = 10 * time.Second
+	syslogWriteTimeout = 10 * time.Second
+	// syslogQueueSize is the number of messages that are buffered while the
+	// syslog server is slow or unreachable. Further audit logs are dropped.
+	syslogQueueSize = 1024
+	// syslogFlushTimeout limits how long Close waits for queued messages to
+	// be written.
+	syslogFlushTimeout = 5 * time.Second
+)
+
+// SyslogBackend streams audit logs to a syslog server over TCP, optionally
+// secured with TLS. Messages are formatted according to RFC 5424 and framed
+// using octet counting as described in RFC 6587.
+//
+// Messages are written in the background so that audited requests are never
+// blocked by the syslog server. If it can't keep up, audit logs are dropped
+// and counted in Metrics.
+type SyslogBackend struct {
+	log       slog.Logger
+	metrics   *Metrics
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	procID    string
+
+	queue     chan []byte
+	stop      chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+	// ctx is canceled once queued messages should no longer be written.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// conn is only used by the goroutine that writes messages.
+	conn net.Conn
+}
+
+var _ audit.Backend = &SyslogBackend{}
+
+// NewSyslog starts writing exported audit logs in the background. Close must
+// be called to stop it.
+func NewSyslog(logger slog.Logger, cfg codersdk.AuditLoggingSyslogConfig, metrics *Metrics) (*SyslogBackend, error) {
+	if cfg.Address.String() == "" {
+		return nil, xerrors.New("syslog address must be set")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address.String()); err != nil {
+		return nil, xerrors.Errorf("invalid syslog address %q: %w", cfg.Address.String(), err)
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.TLS.Value() {
+		var err error
+		tlsConfig, err = syslogTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &SyslogBackend{
+		log:       logger,
+		metrics:   metrics,
+		address:   cfg.Address.String(),
+		tlsConfig: tlsConfig,
+		hostname:  "-",
+		procID:    strconv.Itoa(os.Getpid()),
+		queue:     make(chan []byte, syslogQueueSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		b.hostname = syslogHeaderField(hostname, 255)
+	}
+	go b.run()
+
+	return b, nil
+}
+
+func syslogTLSConfig(cfg codersdk.AuditLoggingSyslogConfig) (*tls.Config, error) {
+	host, _, _ := net.SplitHostPort(cfg.Address.String())
+	//nolint:gosec // The minimum version is set below.
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: host,
+	}
+
+	if cfg.TLSCAFile.String() != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read syslog TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, xerrors.Errorf("no certificates found in syslog TLS CA file %q", cfg.TLSCAFile.String())
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile.String() != "" || cfg.TLSKeyFile.String() != "" {
+		if cfg.TLSCertFile.String() == "" || cfg.TLSKeyFile.String() == "" {
+			return nil, xerrors.New("both a syslog TLS certificate and key file must be set")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile.String(), cfg.TLSKeyFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("load syslog TLS key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (*SyslogBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+// Export queues the audit log to be written to the syslog server. An error is
+// returned if the queue is full and the audit log was dropped.
+func (b *SyslogBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	data, err := marshalAuditLog(alog, details)
+	if err != nil {
+		return err
+	}
+	msg := b.format(alog.Time, data)
+	frame := []byte(strconv.Itoa(len(msg)) + " " + msg)
+
+	select {
+	case <-b.stop:
+		b.metrics.drop(BackendSyslog, ReasonStopped, 1)
+		return xerrors.New("syslog backend is closed")
+	default:
+	}
+	select {
+	case b.queue <- frame:
+		return nil
+	default:
+		b.metrics.drop(BackendSyslog, ReasonQueueFull, 1)
+		return xerrors.New("syslog export queue is full, dropping audit log")
+	}
+}
+
+func (b *SyslogBackend) run() {
+	defer close(b.done)
+	defer b.closeConn()
+
+	for {
+		select {
+		case <-b.stop:
+			// Write what is left in the queue until Close gives up.
+			for {
+				select {
+				case frame := <-b.queue:
+					b.sendOrDrop(frame)
+				default:
+					return
+				}
+			}
+		case frame := <-b.queue:
+			b.sendOrDrop(frame)
+		}
+	}
+}
+
+func (b *SyslogBackend) sendOrDrop(frame []byte) {
+	err := b.send(frame)
+	switch {
+	case err == nil:
+	case b.ctx.Err() != nil:
+		b.metrics.drop(BackendSyslog, ReasonStopped, 1)
+	default:
+		b.metrics.drop(BackendSyslog, ReasonSendFailed, 1)
+		b.log.Error(b.ctx, "failed to write audit log to syslog, dropping it", slog.Error(err))
+	}
+}
+
+func (b *SyslogBackend) send(frame []byte) error {
+	// A connection that was closed by the server is usually only noticed
+	// once a write fails, so reconnect and try again once.
+	for attempt := 0; ; attempt++ {
+		err := b.write(b.ctx, frame)
+		if err == nil {
+			return nil
+		}
+		b.closeConn()
+		if attempt > 0 || b.ctx.Err() != nil {
+			return xerrors.Errorf("write audit log to syslog: %w", err)
+		}
+		b.log.Debug(b.ctx, "syslog write failed, reconnecting", slog.Error(err))
+	}
+}
+
+// format renders an RFC 5424 message. Structured data is not used, since the
+// JSON encoded audit log is sent as the message.
+func (b *SyslogBackend) format(t time.Time, data []byte) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		syslogPriority,
+		t.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		b.hostname,
+		syslogAppName,
+		b.procID,
+		syslogMsgID,
+		data,
+	)
+}
+
+func (b *SyslogBackend) write(ctx context.Context, frame []byte) error {
+	if b.conn == nil {
+		conn, err := b.dial(ctx)
+		if err != nil {
+			return err
+		}
+		b.conn = conn
+	}
+
+	deadline := time.Now().Add(syslogWriteTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := b.conn.SetWriteDeadline(deadline); err != nil {
+		return xerrors.Errorf("set write deadline: %w", err)
+	}
+	// Interrupt a blocked write once the backend gives up on flushing.
+	conn := b.conn
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetWriteDeadline(time.Now())
+	})
+	defer stop()
+	if _, err := conn.Write(frame); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *SyslogBackend) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if b.tlsConfig != nil {
+		conn, err := (&tls.Dialer{NetDialer: dialer, Config: b.tlsConfig}).DialContext(ctx, "tcp", b.address)
+		if err != nil {
+			return nil, xerrors.Errorf("dial syslog server over TLS: %w", err)
+		}
+		return conn, nil
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", b.address)
+	if err != nil {
+		return nil, xerrors.Errorf("dial syslog server: %w", err)
+	}
+	return conn, nil
+}
+
+func (b *SyslogBackend) closeConn() {
+	if b.conn != nil {
+		_ = b.conn.Close()
+		b.conn = nil
+	}
+}
+
+// Close writes the audit logs that are still queued, giving up after
+// syslogFlushTimeout, and closes the connection to the syslog server.
+func (b *SyslogBackend) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		timer := time.AfterFunc(syslogFlushTimeout, b.cancel)
+		defer timer.Stop()
+		<-b.done
+		b.cancel()
+	})
+	return nil
+}
+
+// syslogHeaderField makes a value safe for use in an RFC 5424 header field,
+// which may only contain printable US-ASCII characters.
+func syslogHeaderField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,208 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/serpent"
+)
+
+// syslogHeader matches the RFC 5424 header of an audit log message.
+var syslogHeader = regexp.MustCompile(`^<110>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}Z \S+ coder \d+ audit - `)
+
+func TestSyslogBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		msgs := serveSyslog(t, ln)
+
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), codersdk.AuditLoggingSyslogConfig{
+			Address: serpent.String(ln.Addr().String()),
+		}, backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		exportAndReceive(t, backend, msgs)
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		cert := testutil.GenerateTLSCertificate(t, "localhost")
+		ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		})
+		require.NoError(t, err)
+		msgs := serveSyslog(t, ln)
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		err = os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600)
+		require.NoError(t, err)
+
+		backend, err := backends.NewSyslog(slogtest.Make(t, nil), codersdk.AuditLoggingSyslogConfig{
+			Address:   serpent.String(ln.Addr().String()),
+			TLS:       true,
+			TLSCAFile: serpent.String(caFile),
+		}, backends.NewMetrics(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		exportAndReceive(t, backend, msgs)
+	})
+
+	t.Run("UntrustedCertificate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{testutil.GenerateTLSCertificate(t, "localhost")},
+		})
+		require.NoError(t, err)
+		_ = serveSyslog(t, ln)
+
+		metrics := backends.NewMetrics(prometheus.NewRegistry())
+		backend, err := backends.NewSyslog(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), codersdk.AuditLoggingSyslogConfig{
+			Address: serpent.String(ln.Addr().String()),
+			TLS:     true,
+		}, metrics)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		// The audit log is written in the background, so the failure
+		// doesn't reach the audited request.
+		err = backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+		require.NoError(t, err)
+		testutil.Eventually(ctx, t, func(context.Context) bool {
+			return promtest.ToFloat64(metrics.Dropped.WithLabelValues(backends.BackendSyslog, backends.ReasonSendFailed)) == 1
+		}, testutil.IntervalFast)
+	})
+
+	t.Run("QueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		// The server accepts connections but never reads, so writes
+		// eventually block and the queue fills up.
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		metrics := backends.NewMetrics(prometheus.NewRegistry())
+		backend, err := backends.NewSyslog(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), codersdk.AuditLoggingSyslogConfig{
+			Address: serpent.String(ln.Addr().String()),
+		}, metrics)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = backend.Close() })
+
+		for {
+			err = backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+			if err != nil {
+				break
+			}
+		}
+		require.ErrorContains(t, err, "queue is full")
+		require.EqualValues(t, 1, promtest.ToFloat64(metrics.Dropped.WithLabelValues(backends.BackendSyslog, backends.ReasonQueueFull)))
+	})
+
+	t.Run("InvalidAddress", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewSyslog(slogtest.Make(t, nil), codersdk.AuditLoggingSyslogConfig{
+			Address: "localhost",
+		}, backends.NewMetrics(prometheus.NewRegistry()))
+		require.ErrorContains(t, err, "invalid syslog address")
+	})
+}
+
+func exportAndReceive(t *testing.T, backend audit.Backend, msgs <-chan string) {
+	t.Helper()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	actor := &audit.Actor{ID: uuid.New(), Username: "bob", Email: "bob@coder.com"}
+	alogs := []database.AuditLog{audittest.RandomLog(), audittest.RandomLog()}
+	for _, alog := range alogs {
+		err := backend.Export(ctx, alog, audit.BackendDetails{Actor: actor})
+		require.NoError(t, err)
+	}
+
+	for _, alog := range alogs {
+		msg := testutil.RequireReceive(ctx, t, msgs)
+		header := syslogHeader.FindString(msg)
+		require.NotEmpty(t, header, "unexpected message %q", msg)
+
+		var got struct {
+			ID    uuid.UUID    `json:"id"`
+			IP    string       `json:"ip"`
+			Actor *audit.Actor `json:"actor"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(msg[len(header):]), &got))
+		require.Equal(t, alog.ID, got.ID)
+		require.Equal(t, "127.0.0.1", got.IP)
+		require.Equal(t, actor, got.Actor)
+	}
+}
+
+// serveSyslog accepts connections on the listener and sends every octet
+// counted message it receives to the returned channel.
+func serveSyslog(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+
+	msgs := make(chan string, 16)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					length, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(length[:len(length)-1])
+					if err != nil {
+						return
+					}
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(r, msg); err != nil {
+						return
+					}
+					msgs <- string(msg)
+				}
+			}()
+		}
+	}()
+	return msgs
+}
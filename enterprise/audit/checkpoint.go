@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/quartz"
+)
+
+// DefaultCheckpointInterval is how often the heads of the audit log chains
+// are signed.
+const DefaultCheckpointInterval = time.Hour
+
+// StartCheckpointer periodically signs the head of each audit log chain with
+// the given key, so that rewriting a chain after a checkpoint can be detected.
+// The returned function stops the checkpointer.
+func StartCheckpointer(ctx context.Context, logger slog.Logger, db database.Store, key ed25519.PrivateKey, clk quartz.Clock, interval time.Duration) func() {
+	logger = logger.Named("audit_checkpointer")
+
+	ctx, cancel := context.WithCancel(ctx)
+	tf := clk.TickerFunc(ctx, interval, func() error {
+		checkpoints, err := CheckpointAll(ctx, db, key, clk.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(ctx, "checkpoint audit log chains", slog.Error(err))
+			}
+			return nil
+		}
+		for _, checkpoint := range checkpoints {
+			logger.Debug(ctx, "checkpointed audit log chain",
+				slog.F("organization_id", checkpoint.OrganizationID),
+				slog.F("sequence", checkpoint.Sequence),
+			)
+		}
+		return nil
+	}, "AuditCheckpointer")
+
+	return func() {
+		cancel()
+		_ = tf.Wait()
+	}
+}
+
+// CheckpointAll signs the head of every audit log chain, including the chains
+// of deleted organizations.
+func CheckpointAll(ctx context.Context, db database.Store, key ed25519.PrivateKey, now time.Time) ([]database.AuditLogCheckpoint, error) {
+	//nolint:gocritic // The checkpointer reads and signs every chain.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	chains, err := db.GetAuditLogChains(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("get audit log chains: %w", err)
+	}
+
+	var checkpoints []database.AuditLogCheckpoint
+	for _, chain := range chains {
+		checkpoint, err := Checkpoint(ctx, db, key, chain.OrganizationID, now)
+		if err != nil {
+			return checkpoints, xerrors.Errorf("checkpoint organization %s: %w", chain.OrganizationID, err)
+		}
+		if checkpoint != nil {
+			checkpoints = append(checkpoints, *checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+// Checkpoint signs the current head of the audit log chain of an
+// organization. It returns nil if the head has already been checkpointed, or
+// the chain is empty.
+func Checkpoint(ctx context.Context, db database.Store, key ed25519.PrivateKey, organizationID uuid.UUID, now time.Time) (*database.AuditLogCheckpoint, error) {
+	//nolint:gocritic // The checkpointer reads and signs the whole chain.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	head, err := db.GetAuditLogChainHead(ctx, organizationID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get audit log chain head: %w", err)
+	}
+
+	latest, err := db.GetLatestAuditLogCheckpoint(ctx, organizationID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get latest audit log checkpoint: %w", err)
+	}
+	if err == nil && latest.Sequence >= head.Sequence {
+		return nil, nil
+	}
+
+	checkpoint, err := db.InsertAuditLogCheckpoint(ctx, database.InsertAuditLogCheckpointParams{
+		OrganizationID: organizationID,
+		Sequence:       head.Sequence,
+		Hash:           head.Hash,
+		Signature:      audit.SignCheckpoint(key, organizationID, head.Sequence, head.Hash),
+		CreatedAt:      dbtime.Time(now),
+	})
+	// Another replica checkpointed the same head.
+	if database.IsUniqueViolation(err, database.UniqueAuditLogCheckpointsPkey) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("insert audit log checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
@@ -0,0 +1,90 @@
+package audit_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestCheckpointer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = testutil.Context(t, testutil.WaitShort)
+		logger = slogtest.Make(t, nil)
+		db     = dbmem.New()
+		clk    = quartz.NewMock(t)
+		pgb    = backends.NewPostgres(db, true)
+		org    = dbgen.Organization(t, db, database.Organization{})
+	)
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	stop := audit.StartCheckpointer(ctx, logger, db, key, clk, audit.DefaultCheckpointInterval)
+	defer stop()
+
+	// An empty chain is not checkpointed.
+	clk.Advance(audit.DefaultCheckpointInterval).MustWait(ctx)
+	checkpoints, err := db.GetAuditLogCheckpoints(ctx, database.GetAuditLogCheckpointsParams{
+		OrganizationID: org.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, checkpoints)
+
+	for i := 0; i < 3; i++ {
+		alog := audittest.RandomLog()
+		alog.OrganizationID = org.ID
+		require.NoError(t, pgb.Export(ctx, alog, audit.BackendDetails{}))
+	}
+	deploymentLog := audittest.RandomLog()
+	deploymentLog.OrganizationID = uuid.Nil
+	require.NoError(t, pgb.Export(ctx, deploymentLog, audit.BackendDetails{}))
+	clk.Advance(audit.DefaultCheckpointInterval).MustWait(ctx)
+
+	checkpoints, err = db.GetAuditLogCheckpoints(ctx, database.GetAuditLogCheckpointsParams{
+		OrganizationID: org.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	require.EqualValues(t, 3, checkpoints[0].Sequence)
+	head, err := db.GetAuditLogChainHead(ctx, org.ID)
+	require.NoError(t, err)
+	require.Equal(t, head.Hash, checkpoints[0].Hash)
+	require.NoError(t, codersdk.VerifyAuditLogCheckpoint(publicKey, db2sdk.AuditLogCheckpoint(checkpoints[0])))
+
+	// Deployment-wide audit logs are checkpointed separately.
+	deploymentCheckpoints, err := db.GetAuditLogCheckpoints(ctx, database.GetAuditLogCheckpointsParams{})
+	require.NoError(t, err)
+	require.Len(t, deploymentCheckpoints, 1)
+	require.EqualValues(t, 1, deploymentCheckpoints[0].Sequence)
+	require.NoError(t, codersdk.VerifyAuditLogCheckpoint(publicKey, db2sdk.AuditLogCheckpoint(deploymentCheckpoints[0])))
+
+	// The head is unchanged, so no new checkpoint is made.
+	clk.Advance(audit.DefaultCheckpointInterval).MustWait(ctx)
+	checkpoints, err = db.GetAuditLogCheckpoints(ctx, database.GetAuditLogCheckpointsParams{
+		OrganizationID: org.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+
+	// A tampered checkpoint fails verification.
+	tampered := db2sdk.AuditLogCheckpoint(checkpoints[0])
+	tampered.Sequence = 2
+	require.Error(t, codersdk.VerifyAuditLogCheckpoint(publicKey, tampered))
+}
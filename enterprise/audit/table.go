@@ -18,17 +18,20 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"WorkspaceAgent":  {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
-	"WorkspaceApp":    {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"GitSSHKey":               {codersdk.AuditActionCreate},
+	"Template":                {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":         {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceBuild":          {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                   {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                  {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                 {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"WorkspaceAgent":          {codersdk.AuditActionConnect, codersdk.AuditActionDisconnect},
+	"WorkspaceApp":            {codersdk.AuditActionOpen, codersdk.AuditActionClose},
+	"WorkspaceAgentPortShare": {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"SCIMGroup":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceSnapshot":       {codersdk.AuditActionCreate, codersdk.AuditActionStart, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -82,40 +85,42 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"public_key":  ActionTrack,  // Public keys are ok to expose in a diff.
 	},
 	&database.Template{}: {
-		"id":                                ActionTrack,
-		"created_at":                        ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
-		"updated_at":                        ActionIgnore, // Changes, but is implicit and not helpful in a diff.
-		"organization_id":                   ActionIgnore, /// Never changes.
-		"organization_name":                 ActionIgnore, // Ignore these changes
-		"organization_display_name":         ActionIgnore, // Ignore these changes
-		"organization_icon":                 ActionIgnore, // Ignore these changes
-		"deleted":                           ActionIgnore, // Changes, but is implicit when a delete event is fired.
-		"name":                              ActionTrack,
-		"display_name":                      ActionTrack,
-		"provisioner":                       ActionTrack,
-		"active_version_id":                 ActionTrack,
-		"description":                       ActionTrack,
-		"icon":                              ActionTrack,
-		"default_ttl":                       ActionTrack,
-		"autostart_block_days_of_week":      ActionTrack,
-		"autostop_requirement_days_of_week": ActionTrack,
-		"autostop_requirement_weeks":        ActionTrack,
-		"created_by":                        ActionTrack,
-		"created_by_username":               ActionIgnore,
-		"created_by_avatar_url":             ActionIgnore,
-		"group_acl":                         ActionTrack,
-		"user_acl":                          ActionTrack,
-		"allow_user_autostart":              ActionTrack,
-		"allow_user_autostop":               ActionTrack,
-		"allow_user_cancel_workspace_jobs":  ActionTrack,
-		"failure_ttl":                       ActionTrack,
-		"time_til_dormant":                  ActionTrack,
-		"time_til_dormant_autodelete":       ActionTrack,
-		"require_active_version":            ActionTrack,
-		"deprecated":                        ActionTrack,
-		"max_port_sharing_level":            ActionTrack,
-		"activity_bump":                     ActionTrack,
-		"use_classic_parameter_flow":        ActionTrack,
+		"id":                                   ActionTrack,
+		"created_at":                           ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":                           ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"organization_id":                      ActionIgnore, /// Never changes.
+		"organization_name":                    ActionIgnore, // Ignore these changes
+		"organization_display_name":            ActionIgnore, // Ignore these changes
+		"organization_icon":                    ActionIgnore, // Ignore these changes
+		"deleted":                              ActionIgnore, // Changes, but is implicit when a delete event is fired.
+		"name":                                 ActionTrack,
+		"display_name":                         ActionTrack,
+		"provisioner":                          ActionTrack,
+		"active_version_id":                    ActionTrack,
+		"description":                          ActionTrack,
+		"icon":                                 ActionTrack,
+		"default_ttl":                          ActionTrack,
+		"autostart_block_days_of_week":         ActionTrack,
+		"autostop_requirement_days_of_week":    ActionTrack,
+		"autostop_requirement_weeks":           ActionTrack,
+		"created_by":                           ActionTrack,
+		"created_by_username":                  ActionIgnore,
+		"created_by_avatar_url":                ActionIgnore,
+		"group_acl":                            ActionTrack,
+		"user_acl":                             ActionTrack,
+		"allow_user_autostart":                 ActionTrack,
+		"allow_user_autostop":                  ActionTrack,
+		"allow_user_cancel_workspace_jobs":     ActionTrack,
+		"failure_ttl":                          ActionTrack,
+		"time_til_dormant":                     ActionTrack,
+		"time_til_dormant_autodelete":          ActionTrack,
+		"require_active_version":               ActionTrack,
+		"deprecated":                           ActionTrack,
+		"max_port_sharing_level":               ActionTrack,
+		"activity_bump":                        ActionTrack,
+		"use_classic_parameter_flow":           ActionTrack,
+		"session_recording":                    ActionTrack,
+		"activity_bump_requires_user_activity": ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -171,6 +176,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"automatic_updates":  ActionTrack,
 		"favorite":           ActionTrack,
 		"next_start_at":      ActionTrack,
+		"user_acl":           ActionTrack,
+		"group_acl":          ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                         ActionIgnore,
@@ -202,18 +209,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                ActionIgnore,
+		"hashed_secret":     ActionIgnore,
+		"user_id":           ActionTrack,
+		"last_used":         ActionTrack,
+		"expires_at":        ActionTrack,
+		"created_at":        ActionTrack,
+		"updated_at":        ActionIgnore,
+		"login_type":        ActionIgnore,
+		"lifetime_seconds":  ActionIgnore,
+		"ip_address":        ActionIgnore,
+		"scope":             ActionIgnore,
+		"token_name":        ActionIgnore,
+		"scope_permissions": ActionTrack,
+		"scope_allow_list":  ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -293,6 +302,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"method":             ActionTrack,
 		"kind":               ActionTrack,
 		"enabled_by_default": ActionTrack,
+		"digestible":         ActionTrack,
 	},
 	&idpsync.OrganizationSyncSettings{}: {
 		"field":          ActionTrack,
@@ -366,6 +376,32 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"hidden":                ActionIgnore,
 		"open_in":               ActionIgnore,
 	},
+	&database.WorkspaceAgentPortShare{}: {
+		"workspace_id": ActionIgnore, // Used as the resource ID.
+		"agent_name":   ActionTrack,
+		"port":         ActionTrack,
+		"share_level":  ActionTrack,
+		"protocol":     ActionTrack,
+		"expires_at":   ActionTrack,
+	},
+	&database.SCIMGroup{}: {
+		"id":           ActionTrack,
+		"external_id":  ActionTrack,
+		"display_name": ActionTrack,
+		"created_at":   ActionIgnore, // Never changes.
+		"updated_at":   ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.WorkspaceSnapshot{}: {
+		"id":                  ActionIgnore,
+		"workspace_id":        ActionTrack,
+		"name":                ActionTrack,
+		"created_at":          ActionIgnore, // Never changes.
+		"created_by":          ActionTrack,
+		"build_number":        ActionTrack,
+		"template_version_id": ActionTrack,
+		"provisioner_state":   ActionIgnore,
+		"rich_parameters":     ActionIgnore, // May contain sensitive values.
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
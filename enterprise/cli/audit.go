@@ -0,0 +1,18 @@
+package cli
+
+import "github.com/coder/serpent"
+
+func (r *RootCmd) audit() *serpent.Command {
+	cmd := &serpent.Command{
+		Use:   "audit",
+		Short: "Manage audit logs",
+		Handler: func(inv *serpent.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*serpent.Command{
+			r.auditVerify(),
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/serpent"
+)
+
+// auditVerifyPageSize is the number of chain entries and checkpoints fetched
+// per request.
+const auditVerifyPageSize = 100
+
+func (r *RootCmd) auditVerify() *serpent.Command {
+	var (
+		encodedPublicKey string
+		publicKeyFile    string
+	)
+	client := new(codersdk.Client)
+	cmd := &serpent.Command{
+		Use:   "verify",
+		Short: "Verify that audit logs have not been modified or removed",
+		Long: "Walks the tamper-evident audit log chain of the deployment and of every " +
+			"organization, recomputing the hash of every audit log and verifying the " +
+			"signed checkpoints against the deployment's public key. Exits with a " +
+			"non-zero status if any problem is found.",
+		Middleware: serpent.Chain(
+			serpent.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *serpent.Invocation) error {
+			ctx := inv.Context()
+
+			if publicKeyFile != "" {
+				data, err := os.ReadFile(publicKeyFile)
+				if err != nil {
+					return xerrors.Errorf("read public key file: %w", err)
+				}
+				encodedPublicKey = string(data)
+			}
+			if encodedPublicKey == "" {
+				key, err := client.AuditLogPublicKey(ctx)
+				if err != nil {
+					return xerrors.Errorf("get audit log public key: %w", err)
+				}
+				encodedPublicKey = key.PublicKey
+				cliui.Warn(inv.Stderr, "Verifying checkpoints with the public key served by the deployment.",
+					"A compromised deployment could serve a key of its choosing. Pin the public key of the signing key file with --public-key-file, or record this key and pass it with --public-key in the future:",
+					encodedPublicKey,
+				)
+			}
+			publicKey, err := codersdk.ParseAuditLogPublicKey(encodedPublicKey)
+			if err != nil {
+				return err
+			}
+
+			// The chains are listed by the deployment rather than derived
+			// from the organizations, so that the chains of deleted
+			// organizations are verified too.
+			sdkChains, err := client.AuditLogChains(ctx)
+			if err != nil {
+				return xerrors.Errorf("get audit log chains: %w", err)
+			}
+			chains := make([]auditLogChain, 0, len(sdkChains))
+			for _, chain := range sdkChains {
+				chains = append(chains, auditLogChain{name: auditLogChainName(chain), organizationID: chain.OrganizationID})
+			}
+
+			var (
+				problems    []string
+				entries     int
+				checkpoints int
+			)
+			for _, chain := range chains {
+				result, err := verifyAuditLogChain(ctx, client, publicKey, chain)
+				if err != nil {
+					return err
+				}
+				problems = append(problems, result.problems...)
+				entries += result.entries
+				checkpoints += result.checkpoints
+			}
+
+			if len(problems) > 0 {
+				for _, problem := range problems {
+					_, _ = fmt.Fprintln(inv.Stdout, problem)
+				}
+				return xerrors.Errorf("found %d problems in the audit log chains", len(problems))
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Verified %s audit log entries and %s checkpoints in %s chains.\n",
+				cliui.Bold(fmt.Sprint(entries)), cliui.Bold(fmt.Sprint(checkpoints)), cliui.Bold(fmt.Sprint(len(chains))))
+			return nil
+		},
+	}
+
+	cmd.Options = serpent.OptionSet{
+		{
+			Flag:        "public-key",
+			Description: "The hex encoded ed25519 public key that checkpoints must be signed with. Defaults to the key served by the deployment, which only detects tampering by someone without access to the deployment's signing key.",
+			Env:         "CODER_AUDIT_PUBLIC_KEY",
+			Value:       serpent.StringOf(&encodedPublicKey),
+		},
+		{
+			Flag:        "public-key-file",
+			Description: "A PEM encoded ed25519 public key that checkpoints must be signed with, such as one exported with `openssl pkey -in signing-key.pem -pubout`. Takes precedence over --public-key.",
+			Env:         "CODER_AUDIT_PUBLIC_KEY_FILE",
+			Value:       serpent.StringOf(&publicKeyFile),
+		},
+	}
+
+	return cmd
+}
+
+type auditLogChain struct {
+	name           string
+	organizationID uuid.UUID
+}
+
+func auditLogChainName(chain codersdk.AuditLogChain) string {
+	switch {
+	case chain.OrganizationID == uuid.Nil:
+		return "deployment"
+	case chain.OrganizationName == "":
+		return "organization " + chain.OrganizationID.String()
+	case chain.OrganizationDeleted:
+		return "deleted organization " + chain.OrganizationName
+	default:
+		return "organization " + chain.OrganizationName
+	}
+}
+
+type auditLogChainResult struct {
+	problems    []string
+	entries     int
+	checkpoints int
+}
+
+// verifyAuditLogChain walks a single audit log chain and checks it against
+// its checkpoints.
+func verifyAuditLogChain(ctx context.Context, client *codersdk.Client, publicKey ed25519.PublicKey, chain auditLogChain) (auditLogChainResult, error) {
+	var result auditLogChainResult
+	report := func(sequence int64, format string, args ...any) {
+		result.problems = append(result.problems, fmt.Sprintf("%s: sequence %d: %s", chain.name, sequence, fmt.Sprintf(format, args...)))
+	}
+
+	checkpoints := map[int64]codersdk.AuditLogCheckpoint{}
+	var lastCheckpoint int64
+	for {
+		page, err := client.AuditLogCheckpoints(ctx, codersdk.AuditLogChainRequest{
+			OrganizationID: chain.organizationID,
+			AfterSequence:  lastCheckpoint,
+			Limit:          auditVerifyPageSize,
+		})
+		if err != nil {
+			return result, xerrors.Errorf("get audit log checkpoints of %s: %w", chain.name, err)
+		}
+		for _, checkpoint := range page {
+			if err := codersdk.VerifyAuditLogCheckpoint(publicKey, checkpoint); err != nil {
+				report(checkpoint.Sequence, "checkpoint has an invalid signature: %s", err)
+			}
+			checkpoints[checkpoint.Sequence] = checkpoint
+			lastCheckpoint = checkpoint.Sequence
+		}
+		if len(page) < auditVerifyPageSize {
+			break
+		}
+	}
+	result.checkpoints = len(checkpoints)
+
+	var (
+		last     int64
+		prevHash = hex.EncodeToString(codersdk.AuditLogChainGenesisHash)
+	)
+	for {
+		page, err := client.AuditLogChain(ctx, codersdk.AuditLogChainRequest{
+			OrganizationID: chain.organizationID,
+			AfterSequence:  last,
+			Limit:          auditVerifyPageSize,
+		})
+		if err != nil {
+			return result, xerrors.Errorf("get audit log chain of %s: %w", chain.name, err)
+		}
+		for _, entry := range page {
+			result.entries++
+			switch {
+			case entry.Sequence != last+1:
+				report(entry.Sequence, "entries %d to %d are missing", last+1, entry.Sequence-1)
+			case entry.PrevHash != prevHash:
+				report(entry.Sequence, "entry does not link to the previous entry")
+			}
+
+			if entry.Record == nil {
+				report(entry.Sequence, "audit log %s is missing", entry.AuditLogID)
+			} else if err := verifyAuditLogChainEntry(entry); err != nil {
+				report(entry.Sequence, "audit log %s was modified: %s", entry.AuditLogID, err)
+			}
+
+			if checkpoint, ok := checkpoints[entry.Sequence]; ok && checkpoint.Hash != entry.Hash {
+				report(entry.Sequence, "entry does not match the signed checkpoint")
+			}
+
+			last = entry.Sequence
+			prevHash = entry.Hash
+		}
+		if len(page) < auditVerifyPageSize {
+			break
+		}
+	}
+
+	// Checkpoints past the end of the chain mean that entries were removed
+	// from the end.
+	if lastCheckpoint > last {
+		report(lastCheckpoint, "entries %d to %d are missing", last+1, lastCheckpoint)
+	}
+	return result, nil
+}
+
+// verifyAuditLogChainEntry recomputes the hash of an entry from its audit log.
+func verifyAuditLogChainEntry(entry codersdk.AuditLogChainEntry) error {
+	prevHash, err := hex.DecodeString(entry.PrevHash)
+	if err != nil {
+		return xerrors.Errorf("decode previous hash: %w", err)
+	}
+	hash, err := codersdk.AuditLogChainHash(prevHash, entry.Sequence, *entry.Record)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(hash) != entry.Hash {
+		return xerrors.New("hash does not match")
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	agplaudit "github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAuditVerify(t *testing.T) {
+	t.Parallel()
+
+	type fixture struct {
+		db        database.Store
+		auditor   agplaudit.Auditor
+		orgID     uuid.UUID
+		key       ed25519.PrivateKey
+		publicKey ed25519.PublicKey
+	}
+
+	setup := func(t *testing.T, ctx context.Context, args ...string) (*bytes.Buffer, func() error, fixture) {
+		db, ps := dbtestutil.NewDB(t)
+		auditor := audit.NewAuditor(db, audit.DefaultFilter, backends.NewPostgres(db, true))
+		publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		client, _, _, owner := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database:           db,
+				Pubsub:             ps,
+				Auditor:            auditor,
+				AuditLogSigningKey: key,
+			},
+		})
+
+		for i := 0; i < 3; i++ {
+			alog := audittest.RandomLog()
+			alog.OrganizationID = owner.OrganizationID
+			require.NoError(t, auditor.Export(ctx, alog))
+		}
+		deploymentLog := audittest.RandomLog()
+		deploymentLog.OrganizationID = uuid.Nil
+		require.NoError(t, auditor.Export(ctx, deploymentLog))
+		_, err = audit.CheckpointAll(ctx, db, key, time.Now())
+		require.NoError(t, err)
+
+		inv, root := newCLI(t, append([]string{"audit", "verify"}, args...)...)
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		return buf, func() error { return inv.WithContext(ctx).Run() }, fixture{
+			db:        db,
+			auditor:   auditor,
+			orgID:     owner.OrganizationID,
+			key:       key,
+			publicKey: publicKey,
+		}
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		buf, run, _ := setup(t, ctx)
+		require.NoError(t, run())
+		// coderd records audit logs of its own while being set up.
+		require.Regexp(t, `Verified \d+ audit log entries and 2 checkpoints in 2 chains`, buf.String())
+	})
+
+	t.Run("DeletedOrganization", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		buf, run, f := setup(t, ctx)
+
+		// The chain of a deleted organization is still verified, and
+		// tampering with it is detected.
+		org := dbgen.Organization(t, f.db, database.Organization{})
+		alog := audittest.RandomLog()
+		alog.OrganizationID = org.ID
+		require.NoError(t, f.auditor.Export(ctx, alog))
+		_, err := audit.CheckpointAll(ctx, f.db, f.key, time.Now())
+		require.NoError(t, err)
+		err = f.db.UpdateOrganizationDeletedByID(ctx, database.UpdateOrganizationDeletedByIDParams{
+			ID:        org.ID,
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, run())
+		require.Regexp(t, `Verified \d+ audit log entries and 3 checkpoints in 3 chains`, buf.String())
+
+		_, err = f.db.InsertAuditLogCheckpoint(ctx, database.InsertAuditLogCheckpointParams{
+			OrganizationID: org.ID,
+			Sequence:       2,
+			Hash:           make([]byte, 32),
+			Signature:      make([]byte, ed25519.SignatureSize),
+			CreatedAt:      time.Now(),
+		})
+		require.NoError(t, err)
+		buf.Reset()
+		require.Error(t, run())
+		require.Contains(t, buf.String(), fmt.Sprintf("deleted organization %s: sequence 2: entries 2 to 2 are missing", org.Name))
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		buf, run, f := setup(t, ctx)
+
+		// Link an audit log into the chain with a hash that does not cover
+		// it, as if it had been changed after it was stored.
+		head, err := f.db.GetAuditLogChainHead(ctx, f.orgID)
+		require.NoError(t, err)
+		alog := audittest.RandomLog()
+		alog.OrganizationID = f.orgID
+		_, err = f.db.InsertAuditLog(ctx, database.InsertAuditLogParams(alog))
+		require.NoError(t, err)
+		_, err = f.db.InsertAuditLogChainEntry(ctx, database.InsertAuditLogChainEntryParams{
+			OrganizationID: f.orgID,
+			Sequence:       head.Sequence + 1,
+			AuditLogID:     alog.ID,
+			PrevHash:       head.Hash,
+			Hash:           head.Hash,
+		})
+		require.NoError(t, err)
+
+		require.ErrorContains(t, run(), "found 1 problems")
+		require.Contains(t, buf.String(), fmt.Sprintf("sequence %d: audit log %s was modified", head.Sequence+1, alog.ID))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		buf, run, f := setup(t, ctx)
+
+		// A checkpoint past the end of the chain means entries were removed.
+		head, err := f.db.GetAuditLogChainHead(ctx, uuid.Nil)
+		require.NoError(t, err)
+		_, err = f.db.InsertAuditLogCheckpoint(ctx, database.InsertAuditLogCheckpointParams{
+			OrganizationID: uuid.Nil,
+			Sequence:       head.Sequence + 2,
+			Hash:           head.Hash,
+			Signature:      make([]byte, ed25519.SignatureSize),
+			CreatedAt:      time.Now(),
+		})
+		require.NoError(t, err)
+
+		require.Error(t, run())
+		require.Contains(t, buf.String(), fmt.Sprintf("deployment: sequence %d: checkpoint has an invalid signature", head.Sequence+2))
+		require.Contains(t, buf.String(), fmt.Sprintf("deployment: sequence %d: entries %d to %d are missing", head.Sequence+2, head.Sequence+1, head.Sequence+2))
+	})
+
+	t.Run("PinnedKeyFile", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The public key can be pinned with a PEM file, as exported by
+		// `openssl pkey -pubout`.
+		keyFile := filepath.Join(t.TempDir(), "audit-public-key.pem")
+		buf, run, f := setup(t, ctx, "--public-key-file", keyFile)
+		der, err := x509.MarshalPKIXPublicKey(f.publicKey)
+		require.NoError(t, err)
+		err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+		require.NoError(t, err)
+		require.NoError(t, run())
+		require.Regexp(t, `Verified \d+ audit log entries and 2 checkpoints in 2 chains`, buf.String())
+	})
+
+	t.Run("PinnedKey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Checkpoints signed by the deployment do not verify against a
+		// different pinned key.
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		buf, run, _ := setup(t, ctx, "--public-key", hex.EncodeToString(otherKey))
+		require.ErrorContains(t, run(), "found 2 problems")
+		require.Contains(t, buf.String(), "checkpoint has an invalid signature")
+	})
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"io"
 	"net/url"
+	"os"
+	"path/filepath"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
@@ -27,6 +29,7 @@ import (
 	"github.com/coder/serpent"
 
 	agplcoderd "github.com/coder/coder/v2/coderd"
+	agplaudit "github.com/coder/coder/v2/coderd/audit"
 )
 
 func (r *RootCmd) Server(_ func()) *serpent.Command {
@@ -75,11 +78,28 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 			options.DERPServer.SetMeshKey(meshKey)
 		}
 
+		if keyFile := options.DeploymentValues.AuditLogging.SigningKeyFile.Value(); keyFile != "" {
+			data, err := os.ReadFile(keyFile)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("read audit logging signing key file: %w", err)
+			}
+			options.AuditLogSigningKey, err = agplaudit.ParseSigningKey(data)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		exportBackends, closeExportBackends, err := auditExportBackends(options)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("configure audit log export: %w", err)
+		}
 		options.Auditor = audit.NewAuditor(
 			options.Database,
 			audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			append([]audit.Backend{
+				backends.NewPostgres(options.Database, true),
+				backends.NewSlog(options.Logger),
+			}, exportBackends...)...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -117,9 +137,12 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 
 		api, err := coderd.New(ctx, o)
 		if err != nil {
+			_ = closeExportBackends.Close()
 			return nil, nil, err
 		}
-		return api.AGPL, api, nil
+		// The API is closed first so that no more audit logs are exported
+		// while the backends shut down.
+		return api.AGPL, closers{api, closeExportBackends}, nil
 	})
 
 	cmd.AddSubcommands(
@@ -127,3 +150,67 @@ func (r *RootCmd) Server(_ func()) *serpent.Command {
 	)
 	return cmd
 }
+
+// auditExportBackends creates the audit backends which stream audit logs to
+// external systems, as configured by the deployment.
+func auditExportBackends(options *agplcoderd.Options) ([]audit.Backend, closers, error) {
+	var (
+		cfg      = options.DeploymentValues.AuditLogging
+		logger   = options.Logger.Named("audit_export")
+		metrics  *backends.Metrics
+		exported []audit.Backend
+		closer   closers
+	)
+	fail := func(err error) ([]audit.Backend, closers, error) {
+		_ = closer.Close()
+		return nil, nil, err
+	}
+
+	if cfg.Syslog.Enabled() || cfg.HTTP.Enabled() {
+		metrics = backends.NewMetrics(options.PrometheusRegistry)
+	}
+
+	if cfg.Syslog.Enabled() {
+		backend, err := backends.NewSyslog(logger.Named("syslog"), cfg.Syslog, metrics)
+		if err != nil {
+			return fail(xerrors.Errorf("syslog: %w", err))
+		}
+		exported = append(exported, backend)
+		closer = append(closer, backend)
+	}
+	if cfg.HTTP.Enabled() {
+		httpCfg := cfg.HTTP
+		if httpCfg.QueueDir == "" {
+			httpCfg.QueueDir = serpent.String(filepath.Join(options.CacheDir, "audit-http-queue"))
+		}
+		backend, err := backends.NewHTTP(logger.Named("http"), httpCfg, quartz.NewReal(), metrics)
+		if err != nil {
+			return fail(xerrors.Errorf("http: %w", err))
+		}
+		exported = append(exported, backend)
+		closer = append(closer, backend)
+	}
+	if cfg.File.Enabled() {
+		backend, err := backends.NewFile(cfg.File)
+		if err != nil {
+			return fail(xerrors.Errorf("file: %w", err))
+		}
+		exported = append(exported, backend)
+		closer = append(closer, backend)
+	}
+
+	return exported, closer, nil
+}
+
+// closers closes each of its elements in order.
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var errs []error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
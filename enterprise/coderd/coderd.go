@@ -44,7 +44,9 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/coderd/dbauthz"
+	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/enterprise/coderd/prebuilds"
 	"github.com/coder/coder/v2/enterprise/coderd/proxyhealth"
@@ -145,6 +147,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		}
 	}()
 
+	if options.AuditLogging && options.AuditLogSigningKey != nil {
+		api.closeAuditCheckpointer = audit.StartCheckpointer(ctx, options.Logger, options.Database,
+			options.AuditLogSigningKey, options.Clock, audit.DefaultCheckpointInterval)
+	}
+
+	api.scimDeprovisioner = dormancy.NewDeprovisioner(options.Logger, options.Clock, options.Database,
+		options.Pubsub, options.RuntimeConfig, &api.AGPL.Auditor)
+
 	api.AGPL.Options.ParseLicenseClaims = func(rawJWT string) (email string, trial bool, err error) {
 		c, err := license.ParseClaims(rawJWT, Keys)
 		if err != nil {
@@ -329,6 +339,12 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 
 				r.Get("/idpsync/available-fields", api.organizationIDPSyncClaimFields)
 				r.Get("/idpsync/field-values", api.organizationIDPSyncClaimFieldValues)
+
+				r.Route("/scim/deprovisioning", func(r chi.Router) {
+					r.Use(api.RequireFeatureMW(codersdk.FeatureSCIM))
+					r.Get("/", api.scimDeprovisioningSettings)
+					r.Patch("/", api.patchSCIMDeprovisioningSettings)
+				})
 			})
 		})
 
@@ -497,6 +513,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Patch("/{id}", api.scimPatchUser)
 				r.Put("/{id}", api.scimPutUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Put("/{id}", api.scimPutGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 			r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 				u := r.URL.String()
 				httpapi.Write(r.Context(), w, http.StatusNotFound, codersdk.Response{
@@ -632,6 +656,16 @@ type API struct {
 
 	licenseMetricsCollector *license.MetricsCollector
 	tailnetService          *tailnet.ClientService
+	// closeAuditCheckpointer stops signing checkpoints of the audit log
+	// chain.
+	closeAuditCheckpointer func()
+	// scimDeprovisioner applies the SCIM deprovisioning policy to the
+	// workspaces of deactivated users.
+	scimDeprovisioner *dormancy.Deprovisioner
+	// scimDeprovisionMu guards adding to scimDeprovisionWG once Close has
+	// started waiting on it.
+	scimDeprovisionMu sync.Mutex
+	scimDeprovisionWG sync.WaitGroup
 }
 
 // writeEntitlementWarningsHeader writes the entitlement warnings to the response header
@@ -662,6 +696,12 @@ func (api *API) Close() error {
 	if api.Options.CheckInactiveUsersCancelFunc != nil {
 		api.Options.CheckInactiveUsersCancelFunc()
 	}
+	if api.closeAuditCheckpointer != nil {
+		api.closeAuditCheckpointer()
+	}
+	api.scimDeprovisionMu.Lock()
+	api.scimDeprovisionWG.Wait()
+	api.scimDeprovisionMu.Unlock()
 
 	return api.AGPL.Close()
 }
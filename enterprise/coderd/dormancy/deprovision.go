@@ -0,0 +1,324 @@
+package dormancy
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/quartz"
+)
+
+// DeprovisionSettings is the runtime configuration form of
+// codersdk.SCIMDeprovisioningSettings.
+type DeprovisionSettings codersdk.SCIMDeprovisioningSettings
+
+func (s *DeprovisionSettings) Set(v string) error {
+	return json.Unmarshal([]byte(v), s)
+}
+
+func (s *DeprovisionSettings) String() string {
+	return runtimeconfig.JSONString(s)
+}
+
+// Enabled returns true if deprovisioning changes any workspace.
+func (s DeprovisionSettings) Enabled() bool {
+	return s.StopWorkspaces || s.TransferOwnerID != nil || s.MarkDormant
+}
+
+var deprovisionSettingsEntry = runtimeconfig.MustNew[*DeprovisionSettings]("scim-deprovisioning-settings")
+
+// Deprovisioner applies the SCIM deprovisioning policy of each organization
+// to the workspaces of deactivated users. Deletion after the grace period is
+// scheduled through the workspace's deleting_at, which the lifecycle executor
+// acts on.
+type Deprovisioner struct {
+	logger  slog.Logger
+	clock   quartz.Clock
+	db      database.Store
+	ps      pubsub.Pubsub
+	manager *runtimeconfig.Manager
+	auditor *atomic.Pointer[audit.Auditor]
+}
+
+func NewDeprovisioner(logger slog.Logger, clk quartz.Clock, db database.Store, ps pubsub.Pubsub, manager *runtimeconfig.Manager, auditor *atomic.Pointer[audit.Auditor]) *Deprovisioner {
+	return &Deprovisioner{
+		logger:  logger.Named("deprovision"),
+		clock:   clk,
+		db:      db,
+		ps:      ps,
+		manager: manager,
+		auditor: auditor,
+	}
+}
+
+// Settings returns the deprovisioning settings of the organization. An
+// organization without settings does not deprovision workspaces.
+func (d *Deprovisioner) Settings(ctx context.Context, orgID uuid.UUID) (*DeprovisionSettings, error) {
+	settings, err := deprovisionSettingsEntry.Resolve(ctx, d.manager.OrganizationResolver(d.db, orgID))
+	if err != nil {
+		if !xerrors.Is(err, runtimeconfig.ErrEntryNotFound) {
+			return nil, xerrors.Errorf("resolve deprovisioning settings: %w", err)
+		}
+		settings = &DeprovisionSettings{}
+	}
+	return settings, nil
+}
+
+func (d *Deprovisioner) UpdateSettings(ctx context.Context, orgID uuid.UUID, settings DeprovisionSettings) error {
+	err := deprovisionSettingsEntry.SetRuntimeValue(ctx, d.manager.OrganizationResolver(d.db, orgID), &settings)
+	if err != nil {
+		return xerrors.Errorf("update deprovisioning settings: %w", err)
+	}
+	return nil
+}
+
+// DeprovisionUser applies the deprovisioning settings of each organization to
+// the workspaces the deactivated user owns in it. Failing to deprovision one
+// workspace does not stop the others from being deprovisioned.
+func (d *Deprovisioner) DeprovisionUser(ctx context.Context, userID uuid.UUID) error {
+	logger := d.logger.With(slog.F("user_id", userID))
+
+	workspaces, err := d.db.GetWorkspaces(ctx, database.GetWorkspacesParams{OwnerID: userID})
+	if err != nil {
+		return xerrors.Errorf("get workspaces: %w", err)
+	}
+
+	settingsByOrg := make(map[uuid.UUID]*DeprovisionSettings)
+	for _, row := range workspaces {
+		settings, ok := settingsByOrg[row.OrganizationID]
+		if !ok {
+			settings, err = d.Settings(ctx, row.OrganizationID)
+			if err != nil {
+				return xerrors.Errorf("get settings for organization %s: %w", row.OrganizationID, err)
+			}
+			settingsByOrg[row.OrganizationID] = settings
+		}
+		if !settings.Enabled() {
+			continue
+		}
+
+		log := logger.With(slog.F("workspace_id", row.ID), slog.F("workspace_name", row.Name))
+		err = d.deprovisionWorkspace(ctx, log, row.ID, *settings)
+		if err != nil {
+			log.Error(ctx, "deprovision workspace", slog.Error(err))
+		}
+	}
+	return nil
+}
+
+func (d *Deprovisioner) deprovisionWorkspace(ctx context.Context, logger slog.Logger, workspaceID uuid.UUID, settings DeprovisionSettings) error {
+	if settings.StopWorkspaces {
+		err := d.stop(ctx, workspaceID)
+		if err != nil {
+			// Keep going, the workspace can still be transferred and marked
+			// dormant while a build is in progress.
+			logger.Warn(ctx, "stop workspace", slog.Error(err))
+		}
+	}
+
+	if settings.TransferOwnerID != nil {
+		err := d.transfer(ctx, logger, workspaceID, *settings.TransferOwnerID)
+		if err != nil {
+			logger.Warn(ctx, "transfer workspace", slog.Error(err))
+		}
+	}
+
+	if !settings.MarkDormant {
+		return nil
+	}
+	ws, err := d.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	workspace := ws.WorkspaceTable()
+	now := dbtime.Time(d.clock.Now())
+	if !workspace.DormantAt.Valid {
+		// This also schedules the deletion of the workspace if the template
+		// deletes dormant workspaces.
+		updated, err := d.db.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+			ID:        workspace.ID,
+			DormantAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("mark workspace dormant: %w", err)
+		}
+		d.audit(ctx, workspace, updated)
+		workspace = updated
+	}
+
+	if settings.DeleteAfterDays == 0 {
+		return nil
+	}
+	deletingAt := now.Add(time.Duration(settings.DeleteAfterDays) * 24 * time.Hour)
+	if workspace.DeletingAt.Valid && !workspace.DeletingAt.Time.After(deletingAt) {
+		// The workspace is already scheduled to be deleted sooner.
+		return nil
+	}
+	updated, err := d.db.UpdateWorkspaceDeletingAt(ctx, database.UpdateWorkspaceDeletingAtParams{
+		ID:         workspace.ID,
+		DeletingAt: sql.NullTime{Time: deletingAt, Valid: true},
+	})
+	if err != nil {
+		return xerrors.Errorf("schedule workspace deletion: %w", err)
+	}
+	d.audit(ctx, workspace, updated)
+	return nil
+}
+
+// transfer moves the workspace to the new owner. The new owner must be an
+// active member of the workspace organization and must not already own a
+// workspace with the same name.
+func (d *Deprovisioner) transfer(ctx context.Context, logger slog.Logger, workspaceID, ownerID uuid.UUID) error {
+	ws, err := d.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	if ws.OwnerID == ownerID {
+		return nil
+	}
+
+	owner, err := d.db.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return xerrors.Errorf("get new owner: %w", err)
+	}
+	if owner.Status == database.UserStatusSuspended || owner.Deleted {
+		return xerrors.Errorf("new owner %q is not active", owner.Username)
+	}
+	members, err := d.db.OrganizationMembers(ctx, database.OrganizationMembersParams{
+		OrganizationID: ws.OrganizationID,
+		UserID:         ownerID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get organization membership: %w", err)
+	}
+	if len(members) == 0 {
+		return xerrors.Errorf("new owner %q is not a member of the workspace organization", owner.Username)
+	}
+
+	updated, err := d.db.UpdateWorkspaceOwnerByID(ctx, database.UpdateWorkspaceOwnerByIDParams{
+		ID:        ws.ID,
+		OwnerID:   ownerID,
+		UpdatedAt: dbtime.Time(d.clock.Now()),
+	})
+	if err != nil {
+		if database.IsUniqueViolation(err, database.UniqueWorkspacesOwnerIDLowerIndex) {
+			return xerrors.Errorf("new owner %q already has a workspace named %q", owner.Username, ws.Name)
+		}
+		return xerrors.Errorf("update workspace owner: %w", err)
+	}
+	logger.Info(ctx, "transferred workspace", slog.F("new_owner", owner.Username))
+	d.audit(ctx, ws.WorkspaceTable(), updated)
+	return nil
+}
+
+// stop stops the workspace, unless its latest build already stopped or
+// deleted it.
+func (d *Deprovisioner) stop(ctx context.Context, workspaceID uuid.UUID) error {
+	var (
+		ws          database.Workspace
+		latestBuild database.WorkspaceBuild
+		build       *database.WorkspaceBuild
+		job         *database.ProvisionerJob
+	)
+	err := d.db.InTx(func(tx database.Store) error {
+		var err error
+		ws, err = tx.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		latestBuild, err = tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, ws.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		if latestBuild.Transition != database.WorkspaceTransitionStart {
+			return nil
+		}
+		latestJob, err := tx.GetProvisionerJobByID(ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest provisioner job: %w", err)
+		}
+
+		builder := wsbuilder.New(ws, database.WorkspaceTransitionStop).
+			SetLastWorkspaceBuildInTx(&latestBuild).
+			SetLastWorkspaceBuildJobInTx(&latestJob).
+			Reason(database.BuildReasonDeprovision)
+		build, job, _, err = builder.Build(ctx, tx, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+		if err != nil {
+			return xerrors.Errorf("stop workspace: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if build == nil {
+		return nil
+	}
+	d.auditBuild(ctx, ws, latestBuild, *build)
+	// The job must be posted after the transaction commits, otherwise
+	// provisionerd may fail to acquire it.
+	err = provisionerjobs.PostJob(d.ps, *job)
+	if err != nil {
+		return xerrors.Errorf("post provisioner job to pubsub: %w", err)
+	}
+	return nil
+}
+
+// auditBuild records that deprovisioning started a build. The outcome of the
+// build is audited by provisionerd when the job completes.
+func (d *Deprovisioner) auditBuild(ctx context.Context, ws database.Workspace, oldBuild, newBuild database.WorkspaceBuild) {
+	fields := audit.BackgroundTaskFields(audit.BackgroundSubsystemSCIM)
+	fields["workspace_name"] = ws.Name
+	fields["workspace_owner"] = ws.OwnerUsername
+	fields["workspace_id"] = ws.ID.String()
+	fields["build_number"] = strconv.FormatInt(int64(newBuild.BuildNumber), 10)
+	fields["build_reason"] = string(newBuild.Reason)
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		d.logger.Error(ctx, "marshal additional fields for deprovisioning audit", slog.Error(err))
+		raw = nil
+	}
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceBuild]{
+		Audit:            *d.auditor.Load(),
+		Log:              d.logger,
+		UserID:           ws.OwnerID,
+		OrganizationID:   ws.OrganizationID,
+		Action:           database.AuditActionStop,
+		Old:              oldBuild,
+		New:              newBuild,
+		Status:           http.StatusOK,
+		AdditionalFields: raw,
+	})
+}
+
+func (d *Deprovisioner) audit(ctx context.Context, oldWorkspace, newWorkspace database.WorkspaceTable) {
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceTable]{
+		Audit:            *d.auditor.Load(),
+		Log:              d.logger,
+		UserID:           newWorkspace.OwnerID,
+		OrganizationID:   newWorkspace.OrganizationID,
+		Action:           database.AuditActionWrite,
+		Old:              oldWorkspace,
+		New:              newWorkspace,
+		Status:           http.StatusOK,
+		AdditionalFields: audit.BackgroundTaskFieldsBytes(ctx, d.logger, audit.BackgroundSubsystemSCIM),
+	})
+}
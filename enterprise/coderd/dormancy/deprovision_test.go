@@ -0,0 +1,171 @@
+package dormancy_test
+
+import (
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/runtimeconfig"
+	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
+	"github.com/coder/coder/v2/testutil"
+	"github.com/coder/quartz"
+)
+
+func TestDeprovisionUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoSettings", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, d, mAudit, _ := setupDeprovisioner(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+		}).Do().Workspace
+
+		require.NoError(t, d.DeprovisionUser(ctx, user.ID))
+
+		build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.WorkspaceTransitionStart, build.Transition)
+		require.Empty(t, mAudit.AuditLogs())
+	})
+
+	t.Run("StopTransferDormant", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, d, mAudit, mClock := setupDeprovisioner(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		admin := dbgen.User(t, db, database.User{})
+		dbgen.OrganizationMember(t, db, database.OrganizationMember{OrganizationID: org.ID, UserID: user.ID})
+		dbgen.OrganizationMember(t, db, database.OrganizationMember{OrganizationID: org.ID, UserID: admin.ID})
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+		}).Do().Workspace
+
+		require.NoError(t, d.UpdateSettings(ctx, org.ID, dormancy.DeprovisionSettings{
+			StopWorkspaces:  true,
+			TransferOwnerID: &admin.ID,
+			MarkDormant:     true,
+			DeleteAfterDays: 7,
+		}))
+		require.NoError(t, d.DeprovisionUser(ctx, user.ID))
+
+		build, err := db.GetLatestWorkspaceBuildByWorkspaceID(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.WorkspaceTransitionStop, build.Transition)
+		require.Equal(t, database.BuildReasonDeprovision, build.Reason)
+
+		got, err := db.GetWorkspaceByID(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Equal(t, admin.ID, got.OwnerID)
+		require.True(t, got.DormantAt.Valid)
+
+		// The deletion is scheduled after the grace period.
+		require.True(t, got.DeletingAt.Valid)
+		require.WithinDuration(t, mClock.Now().Add(7*24*time.Hour), got.DeletingAt.Time, time.Second)
+
+		// The stop build, the transfer, marking the workspace dormant and
+		// scheduling its deletion are audited.
+		logs := mAudit.AuditLogs()
+		require.Len(t, logs, 4)
+		require.Equal(t, database.ResourceTypeWorkspaceBuild, logs[0].ResourceType)
+		require.Equal(t, database.AuditActionStop, logs[0].Action)
+	})
+
+	t.Run("KeepEarlierDeletion", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, d, _, mClock := setupDeprovisioner(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+		}).Do().Workspace
+
+		now := dbtime.Time(mClock.Now())
+		_, err := db.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+			ID:        ws.ID,
+			DormantAt: sql.NullTime{Time: now, Valid: true},
+		})
+		require.NoError(t, err)
+		deletingAt := now.Add(24 * time.Hour)
+		_, err = db.UpdateWorkspaceDeletingAt(ctx, database.UpdateWorkspaceDeletingAtParams{
+			ID:         ws.ID,
+			DeletingAt: sql.NullTime{Time: deletingAt, Valid: true},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, d.UpdateSettings(ctx, org.ID, dormancy.DeprovisionSettings{
+			MarkDormant:     true,
+			DeleteAfterDays: 7,
+		}))
+		require.NoError(t, d.DeprovisionUser(ctx, user.ID))
+
+		// The workspace was already due to be deleted sooner.
+		got, err := db.GetWorkspaceByID(ctx, ws.ID)
+		require.NoError(t, err)
+		require.WithinDuration(t, deletingAt, got.DeletingAt.Time, time.Second)
+	})
+
+	t.Run("TransferToNonMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		db, d, _, _ := setupDeprovisioner(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		user := dbgen.User(t, db, database.User{})
+		outsider := dbgen.User(t, db, database.User{})
+		ws := dbfake.WorkspaceBuild(t, db, database.WorkspaceTable{
+			OrganizationID: org.ID,
+			OwnerID:        user.ID,
+		}).Do().Workspace
+
+		require.NoError(t, d.UpdateSettings(ctx, org.ID, dormancy.DeprovisionSettings{
+			TransferOwnerID: &outsider.ID,
+			MarkDormant:     true,
+		}))
+		require.NoError(t, d.DeprovisionUser(ctx, user.ID))
+
+		// The workspace keeps its owner but the other steps still apply.
+		got, err := db.GetWorkspaceByID(ctx, ws.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, got.OwnerID)
+		require.True(t, got.DormantAt.Valid)
+	})
+}
+
+func setupDeprovisioner(t *testing.T) (database.Store, *dormancy.Deprovisioner, *audit.MockAuditor, *quartz.Mock) {
+	t.Helper()
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	db := dbmem.New()
+	mAudit := audit.NewMock()
+	var auditor atomic.Pointer[audit.Auditor]
+	var a audit.Auditor = mAudit
+	auditor.Store(&a)
+	mClock := quartz.NewMock(t)
+
+	d := dormancy.NewDeprovisioner(logger, mClock, db, pubsub.NewInMemory(), runtimeconfig.NewManager(), &auditor)
+	return db, d, mAudit, mClock
+}
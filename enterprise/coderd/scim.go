@@ -58,7 +58,7 @@ func (api *API) scimServiceProviderConfig(rw http.ResponseWriter, _ *http.Reques
 
 	// providerUpdated is the last time the static provider config was updated.
 	// Increment this time if you make any changes to the provider config.
-	providerUpdated := time.Date(2024, 10, 25, 17, 0, 0, 0, time.UTC)
+	providerUpdated := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
 	var location string
 	locURL, err := api.AccessURL.Parse("/scim/v2/ServiceProviderConfig")
 	if err == nil {
@@ -77,7 +77,8 @@ func (api *API) scimServiceProviderConfig(rw http.ResponseWriter, _ *http.Reques
 			Supported: false,
 		},
 		Filter: scim.FilterSupported{
-			Supported: false,
+			// Only 'displayName eq' on groups is supported.
+			Supported: true,
 		},
 		ChangePassword: scim.Supported{
 			Supported: false,
@@ -404,6 +405,7 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		dbUser = userNew
+		api.scimDeprovisionUser(dbUser)
 	} else {
 		// Do not push an audit log if there is no change.
 		commitAudit(false)
@@ -499,6 +501,7 @@ func (api *API) scimPutUser(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		dbUser = userNew
+		api.scimDeprovisionUser(dbUser)
 	} else {
 		// Do not push an audit log if there is no change.
 		commitAudit(false)
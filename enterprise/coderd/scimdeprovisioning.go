@@ -0,0 +1,158 @@
+package coderd
+
+import (
+	"database/sql"
+	"net/http"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/policy"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
+)
+
+// @Summary Get SCIM deprovisioning settings by organization
+// @ID get-scim-deprovisioning-settings-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.SCIMDeprovisioningSettings
+// @Router /organizations/{organization}/settings/scim/deprovisioning [get]
+func (api *API) scimDeprovisioningSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionRead, rbac.ResourceIdpsyncSettings.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	//nolint:gocritic // Requires system context to read runtime config
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	settings, err := api.scimDeprovisioner.Settings(sysCtx, org.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.SCIMDeprovisioningSettings(*settings))
+}
+
+// @Summary Update SCIM deprovisioning settings by organization
+// @ID update-scim-deprovisioning-settings-by-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Accept json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.SCIMDeprovisioningSettings true "New settings"
+// @Success 200 {object} codersdk.SCIMDeprovisioningSettings
+// @Router /organizations/{organization}/settings/scim/deprovisioning [patch]
+func (api *API) patchSCIMDeprovisioningSettings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	if !api.Authorize(r, policy.ActionUpdate, rbac.ResourceIdpsyncSettings.InOrg(org.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.SCIMDeprovisioningSettings
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	if req.DeleteAfterDays < 0 {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "delete_after_days",
+			Detail: "must not be negative",
+		})
+	}
+	if req.DeleteAfterDays > 0 && !req.MarkDormant {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "delete_after_days",
+			Detail: "requires mark_dormant, only dormant workspaces are deleted",
+		})
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid deprovisioning settings.",
+			Validations: validations,
+		})
+		return
+	}
+
+	//nolint:gocritic // Requires system context to update runtime config
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	if req.TransferOwnerID != nil {
+		members, err := api.Database.OrganizationMembers(sysCtx, database.OrganizationMembersParams{
+			OrganizationID: org.ID,
+			UserID:         *req.TransferOwnerID,
+		})
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if len(members) == 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid deprovisioning settings.",
+				Validations: []codersdk.ValidationError{{
+					Field:  "transfer_owner_id",
+					Detail: "must be a member of the organization",
+				}},
+			})
+			return
+		}
+	}
+
+	err := api.scimDeprovisioner.UpdateSettings(sysCtx, org.ID, dormancy.DeprovisionSettings(req))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	settings, err := api.scimDeprovisioner.Settings(sysCtx, org.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.SCIMDeprovisioningSettings(*settings))
+}
+
+// scimDeprovisionUser applies the deprovisioning settings of the user's
+// organizations to the workspaces of a user suspended by the SCIM provider.
+// Deprovisioning runs in the background so the SCIM request does not wait on
+// every workspace of the user. The suspension stands even if deprovisioning
+// fails.
+func (api *API) scimDeprovisionUser(user database.User) {
+	if user.Status != database.UserStatusSuspended {
+		return
+	}
+
+	api.scimDeprovisionMu.Lock()
+	defer api.scimDeprovisionMu.Unlock()
+	if api.ctx.Err() != nil {
+		// The server is shutting down.
+		return
+	}
+	api.scimDeprovisionWG.Add(1)
+	go func() {
+		defer api.scimDeprovisionWG.Done()
+
+		//nolint:gocritic // needed for SCIM
+		err := api.scimDeprovisioner.DeprovisionUser(dbauthz.AsSystemRestricted(api.ctx), user.ID)
+		if err != nil {
+			api.Logger.Error(api.ctx, "deprovision scim user", slog.F("user_id", user.ID), slog.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,144 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSCIMDeprovisioningSettings(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		SCIMAPIKey: []byte("hi"),
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureSCIM: 1,
+			},
+		},
+	})
+	orgID := owner.OrganizationID.String()
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	settings, err := client.SCIMDeprovisioningSettings(ctx, orgID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.SCIMDeprovisioningSettings{}, settings)
+
+	_, err = memberClient.SCIMDeprovisioningSettings(ctx, orgID)
+	require.Error(t, err)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Only dormant workspaces can be deleted.
+	_, err = client.PatchSCIMDeprovisioningSettings(ctx, orgID, codersdk.SCIMDeprovisioningSettings{
+		DeleteAfterDays: 7,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Workspaces can only be transferred to organization members.
+	_, err = client.PatchSCIMDeprovisioningSettings(ctx, orgID, codersdk.SCIMDeprovisioningSettings{
+		TransferOwnerID: ptr.Ref(uuid.New()),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	expected := codersdk.SCIMDeprovisioningSettings{
+		StopWorkspaces:  true,
+		TransferOwnerID: &owner.UserID,
+		MarkDormant:     true,
+		DeleteAfterDays: 30,
+	}
+	settings, err = client.PatchSCIMDeprovisioningSettings(ctx, orgID, expected)
+	require.NoError(t, err)
+	require.Equal(t, expected, settings)
+
+	settings, err = client.SCIMDeprovisioningSettings(ctx, orgID)
+	require.NoError(t, err)
+	require.Equal(t, expected, settings)
+}
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestSCIMDeprovisionUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	scimAPIKey := []byte("hi")
+	client, owner := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		},
+		SCIMAPIKey: scimAPIKey,
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureSCIM: 1,
+			},
+		},
+	})
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	userClient, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	version := coderdtest.CreateTemplateVersion(t, templateAdmin, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, templateAdmin, version.ID)
+	template := coderdtest.CreateTemplate(t, templateAdmin, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, userClient, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, userClient, workspace.LatestBuild.ID)
+
+	_, err := client.PatchSCIMDeprovisioningSettings(ctx, owner.OrganizationID.String(), codersdk.SCIMDeprovisioningSettings{
+		StopWorkspaces:  true,
+		TransferOwnerID: &owner.UserID,
+		MarkDormant:     true,
+		DeleteAfterDays: 7,
+	})
+	require.NoError(t, err)
+
+	res, err := client.Request(ctx, http.MethodPatch, "/scim/v2/Users/"+user.ID.String(), coderd.SCIMUser{
+		Active: ptr.Ref(false),
+	}, setScimAuth(scimAPIKey))
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Deprovisioning runs in the background.
+	require.Eventually(t, func() bool {
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		if err != nil {
+			return false
+		}
+		return workspace.DeletingAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, owner.UserID, workspace.OwnerID)
+	require.NotNil(t, workspace.DormantAt)
+	require.Equal(t, codersdk.WorkspaceTransitionStop, workspace.LatestBuild.Transition)
+	require.Equal(t, codersdk.BuildReason(database.BuildReasonDeprovision), workspace.LatestBuild.Reason)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	// Suspending the user again does not deprovision twice.
+	res, err = client.Request(ctx, http.MethodPatch, "/scim/v2/Users/"+user.ID.String(), coderd.SCIMUser{
+		Active: ptr.Ref(false),
+	}, setScimAuth(scimAPIKey))
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{WorkspaceID: workspace.ID})
+	require.NoError(t, err)
+	require.Len(t, builds, 2)
+}
@@ -0,0 +1,840 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+// SCIMGroup is a group pushed by the identity provider. SCIM groups are not
+// Coder groups. Membership of a SCIM group is mapped to Coder groups using the
+// group sync settings of each organization, in the same way as a group claim.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	ExternalID  string            `json:"externalId,omitempty"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        SCIMGroupMeta     `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	// Value is the ID of the user.
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+type SCIMGroupMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created" format:"date-time"`
+	LastModified time.Time `json:"lastModified" format:"date-time"`
+	Location     string    `json:"location,omitempty"`
+}
+
+// scimGetGroups lists SCIM groups. Only the 'displayName eq "name"' filter is
+// supported, which identity providers use to check if a group exists.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only the displayName eq operator is supported"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Success 200 {object} scim.ListResponse
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	query := r.URL.Query()
+	displayName, err := parseSCIMGroupFilter(query.Get("filter"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidFilter.Type, err))
+		return
+	}
+	startIndex, err := parseSCIMQueryInt(query.Get("startIndex"), 1)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.Errorf("startIndex: %w", err)))
+		return
+	}
+	count, err := parseSCIMQueryInt(query.Get("count"), -1)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.Errorf("count: %w", err)))
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	groups, err := api.Database.GetSCIMGroups(ctx, displayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("get groups: %w", err)))
+		return
+	}
+
+	total := len(groups)
+	// startIndex is 1-based, and values less than 1 are interpreted as 1.
+	groups = groups[min(max(startIndex, 1)-1, len(groups)):]
+	if count >= 0 && count < len(groups) {
+		groups = groups[:count]
+	}
+
+	resources, err := api.scimGroups(ctx, groups)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", err))
+		return
+	}
+
+	rw.Header().Set("Content-Type", spec.ApplicationScimJson)
+	httpapi.Write(ctx, rw, http.StatusOK, scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   max(startIndex, 1),
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// scimGetGroup returns a SCIM group and its members.
+//
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+
+	api.scimWriteGroup(ctx, rw, http.StatusOK, group)
+}
+
+// scimPostGroup creates a new SCIM group, and syncs the Coder groups of its
+// members.
+//
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.SCIMGroup](rw, &audit.RequestParams{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionCreate,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit()
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+	if sGroup.DisplayName == "" {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.New("displayName is required")))
+		return
+	}
+	members, err := parseSCIMGroupMembers(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", err))
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	err = scimCheckMembers(ctx, api.Database, members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var group database.SCIMGroup
+	err = api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		group, err = tx.InsertSCIMGroup(ctx, database.InsertSCIMGroupParams{
+			ID:          uuid.New(),
+			ExternalID:  sGroup.ExternalID,
+			DisplayName: sGroup.DisplayName,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			err = tx.InsertSCIMGroupMembers(ctx, database.InsertSCIMGroupMembersParams{
+				SCIMGroupID: group.ID,
+				UserIds:     members,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert members: %w", err)
+			}
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err, database.UniqueScimGroupsDisplayNameKey) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("group %q already exists", sGroup.DisplayName)))
+		return
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("create group: %w", err)))
+		return
+	}
+	aReq.New = group
+
+	err = api.scimSyncGroupMembers(ctx, members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", err))
+		return
+	}
+
+	api.scimWriteGroup(ctx, rw, http.StatusCreated, group)
+}
+
+// scimPutGroup replaces the name and members of a SCIM group.
+//
+// @Summary SCIM 2.0: Replace group
+// @ID scim-replace-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMGroup true "Replace group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [put]
+func (api *API) scimPutGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.SCIMGroup](rw, &audit.RequestParams{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit()
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+	if sGroup.DisplayName == "" {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.New("displayName is required")))
+		return
+	}
+	members, err := parseSCIMGroupMembers(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", err))
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = group
+
+	group, ok = api.scimUpdateGroup(ctx, rw, group, scimGroupState{
+		DisplayName: sGroup.DisplayName,
+		ExternalID:  sGroup.ExternalID,
+		Members:     members,
+	})
+	if !ok {
+		return
+	}
+	aReq.New = group
+
+	api.scimWriteGroup(ctx, rw, http.StatusOK, group)
+}
+
+// scimPatchGroup applies a list of operations to a SCIM group. Identity
+// providers use this to add and remove members without sending the full list.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security Authorization
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body scim.PatchRequest true "Patch group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.SCIMGroup](rw, &audit.RequestParams{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit()
+
+	var req scim.PatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidRequest", err))
+		return
+	}
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = group
+
+	rows, err := api.Database.GetSCIMGroupMembers(ctx, []uuid.UUID{group.ID})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("get members: %w", err)))
+		return
+	}
+	state := scimGroupState{
+		DisplayName: group.DisplayName,
+		ExternalID:  group.ExternalID,
+		Members: db2sdk.List(rows, func(row database.GetSCIMGroupMembersRow) uuid.UUID {
+			return row.UserID
+		}),
+	}
+	for _, op := range req.Operations {
+		err = state.apply(op)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	group, ok = api.scimUpdateGroup(ctx, rw, group, state)
+	if !ok {
+		return
+	}
+	aReq.New = group
+
+	api.scimWriteGroup(ctx, rw, http.StatusOK, group)
+}
+
+// scimDeleteGroup deletes a SCIM group, and syncs the Coder groups of its
+// former members.
+//
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security Authorization
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		scimUnauthorized(rw)
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.SCIMGroup](rw, &audit.RequestParams{
+		Audit:            *api.AGPL.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionDelete,
+		AdditionalFields: SCIMAuditAdditionalFields,
+	})
+	defer commitAudit()
+
+	//nolint:gocritic // SCIM operations are a system user
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	group, ok := api.scimGroupParam(ctx, rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = group
+
+	var members []uuid.UUID
+	err := api.Database.InTx(func(tx database.Store) error {
+		rows, err := tx.GetSCIMGroupMembers(ctx, []uuid.UUID{group.ID})
+		if err != nil {
+			return xerrors.Errorf("get members: %w", err)
+		}
+		members = db2sdk.List(rows, func(row database.GetSCIMGroupMembersRow) uuid.UUID {
+			return row.UserID
+		})
+		return tx.DeleteSCIMGroupByID(ctx, group.ID)
+	}, nil)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("delete group: %w", err)))
+		return
+	}
+
+	err = api.scimSyncGroupMembers(ctx, members, group.DisplayName)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", err))
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroupParam fetches the SCIM group from the URL, writing an error if it
+// does not exist.
+func (api *API) scimGroupParam(ctx context.Context, rw http.ResponseWriter, r *http.Request) (database.SCIMGroup, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusNotFound, spec.ErrNotFound.Type, xerrors.Errorf("id must be a uuid: %w", err)))
+		return database.SCIMGroup{}, false
+	}
+	group, err := api.Database.GetSCIMGroupByID(ctx, id)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusNotFound, spec.ErrNotFound.Type, xerrors.Errorf("group %s not found", id)))
+		return database.SCIMGroup{}, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("get group: %w", err)))
+		return database.SCIMGroup{}, false
+	}
+	return group, true
+}
+
+// scimGroupState is the mutable state of a SCIM group.
+type scimGroupState struct {
+	DisplayName string
+	ExternalID  string
+	Members     []uuid.UUID
+}
+
+var scimMemberPathRegex = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+
+// apply applies a single PATCH operation to the state. The returned error is a
+// SCIM error.
+func (s *scimGroupState) apply(op scim.PatchOperation) error {
+	invalid := func(err error) error {
+		return scim.NewHTTPError(http.StatusBadRequest, "invalidValue", err)
+	}
+
+	opName := strings.ToLower(op.Op)
+	path := strings.TrimSpace(op.Path)
+	switch opName {
+	case "add", "replace":
+		if path == "" {
+			// The value is a partial group object.
+			var partial struct {
+				DisplayName *string            `json:"displayName"`
+				ExternalID  *string            `json:"externalId"`
+				Members     *[]SCIMGroupMember `json:"members"`
+			}
+			err := json.Unmarshal(op.Value, &partial)
+			if err != nil {
+				return invalid(xerrors.Errorf("value must be a group object: %w", err))
+			}
+			if partial.DisplayName != nil {
+				s.DisplayName = *partial.DisplayName
+			}
+			if partial.ExternalID != nil {
+				s.ExternalID = *partial.ExternalID
+			}
+			if partial.Members != nil {
+				members, err := parseSCIMGroupMembers(*partial.Members)
+				if err != nil {
+					return invalid(err)
+				}
+				s.setMembers(opName, members)
+			}
+			return nil
+		}
+
+		switch strings.ToLower(path) {
+		case "members":
+			var values []SCIMGroupMember
+			err := json.Unmarshal(op.Value, &values)
+			if err != nil {
+				return invalid(xerrors.Errorf("members value must be a list: %w", err))
+			}
+			members, err := parseSCIMGroupMembers(values)
+			if err != nil {
+				return invalid(err)
+			}
+			s.setMembers(opName, members)
+		case "displayname":
+			err := json.Unmarshal(op.Value, &s.DisplayName)
+			if err != nil {
+				return invalid(xerrors.Errorf("displayName value must be a string: %w", err))
+			}
+		case "externalid":
+			err := json.Unmarshal(op.Value, &s.ExternalID)
+			if err != nil {
+				return invalid(xerrors.Errorf("externalId value must be a string: %w", err))
+			}
+		default:
+			return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("unsupported path %q", op.Path))
+		}
+	case "remove":
+		var remove []uuid.UUID
+		switch {
+		case strings.EqualFold(path, "members"):
+			if len(op.Value) == 0 {
+				// Removing without a value removes all members.
+				s.Members = []uuid.UUID{}
+				return nil
+			}
+			var values []SCIMGroupMember
+			err := json.Unmarshal(op.Value, &values)
+			if err != nil {
+				return invalid(xerrors.Errorf("members value must be a list: %w", err))
+			}
+			remove, err = parseSCIMGroupMembers(values)
+			if err != nil {
+				return invalid(err)
+			}
+		case scimMemberPathRegex.MatchString(path):
+			id, err := uuid.Parse(scimMemberPathRegex.FindStringSubmatch(path)[1])
+			if err != nil {
+				return invalid(xerrors.Errorf("member value must be a uuid: %w", err))
+			}
+			remove = []uuid.UUID{id}
+		default:
+			return scim.NewHTTPError(http.StatusBadRequest, spec.ErrInvalidPath.Type, xerrors.Errorf("unsupported path %q", op.Path))
+		}
+		s.Members = slices.DeleteFunc(s.Members, func(id uuid.UUID) bool {
+			return slices.Contains(remove, id)
+		})
+	default:
+		return scim.NewHTTPError(http.StatusBadRequest, "invalidSyntax", xerrors.Errorf("unsupported op %q", op.Op))
+	}
+	return nil
+}
+
+func (s *scimGroupState) setMembers(op string, members []uuid.UUID) {
+	if op == "replace" {
+		s.Members = members
+		return
+	}
+	for _, id := range members {
+		if !slices.Contains(s.Members, id) {
+			s.Members = append(s.Members, id)
+		}
+	}
+}
+
+// scimUpdateGroup stores the new state of the group, and syncs the Coder
+// groups of all users affected by the change. It writes an error to the
+// response on failure.
+func (api *API) scimUpdateGroup(ctx context.Context, rw http.ResponseWriter, group database.SCIMGroup, state scimGroupState) (database.SCIMGroup, bool) {
+	if state.DisplayName == "" {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.New("displayName is required")))
+		return database.SCIMGroup{}, false
+	}
+
+	var (
+		affected []uuid.UUID
+		removed  []string
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		rows, err := tx.GetSCIMGroupMembers(ctx, []uuid.UUID{group.ID})
+		if err != nil {
+			return xerrors.Errorf("get members: %w", err)
+		}
+		existing := db2sdk.List(rows, func(row database.GetSCIMGroupMembersRow) uuid.UUID {
+			return row.UserID
+		})
+
+		if state.DisplayName != group.DisplayName || state.ExternalID != group.ExternalID {
+			renamed := state.DisplayName != group.DisplayName
+			oldName := group.DisplayName
+			group, err = tx.UpdateSCIMGroupByID(ctx, database.UpdateSCIMGroupByIDParams{
+				ID:          group.ID,
+				ExternalID:  state.ExternalID,
+				DisplayName: state.DisplayName,
+				UpdatedAt:   dbtime.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			// Renaming the group can change the mapped Coder groups of every
+			// member.
+			if renamed {
+				affected = append(affected, existing...)
+				removed = append(removed, oldName)
+			}
+		}
+
+		add := slices.DeleteFunc(slices.Clone(state.Members), func(id uuid.UUID) bool {
+			return slices.Contains(existing, id)
+		})
+		remove := slices.DeleteFunc(slices.Clone(existing), func(id uuid.UUID) bool {
+			return slices.Contains(state.Members, id)
+		})
+		// Only new members are checked, so a group still updates if one of
+		// its existing members has since been deleted.
+		err = scimCheckMembers(ctx, tx, add)
+		if err != nil {
+			return err
+		}
+		if len(add) > 0 {
+			err = tx.InsertSCIMGroupMembers(ctx, database.InsertSCIMGroupMembersParams{
+				SCIMGroupID: group.ID,
+				UserIds:     add,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert members: %w", err)
+			}
+		}
+		if len(remove) > 0 {
+			err = tx.DeleteSCIMGroupMembers(ctx, database.DeleteSCIMGroupMembersParams{
+				SCIMGroupID: group.ID,
+				UserIds:     remove,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete members: %w", err)
+			}
+		}
+		affected = append(affected, add...)
+		affected = append(affected, remove...)
+		return nil
+	}, nil)
+	var scimErr *scim.HTTPError
+	if xerrors.As(err, &scimErr) {
+		_ = handlerutil.WriteError(rw, scimErr)
+		return database.SCIMGroup{}, false
+	}
+	if database.IsUniqueViolation(err, database.UniqueScimGroupsDisplayNameKey) {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusConflict, spec.ErrUniqueness.Type, xerrors.Errorf("group %q already exists", state.DisplayName)))
+		return database.SCIMGroup{}, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("update group: %w", err)))
+		return database.SCIMGroup{}, false
+	}
+
+	err = api.scimSyncGroupMembers(ctx, affected, removed...)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", err))
+		return database.SCIMGroup{}, false
+	}
+	return group, true
+}
+
+// scimSyncGroupMembers syncs the Coder groups of the given users from the
+// SCIM groups they are members of. This applies membership changes without
+// the users having to log in again. removedGroups are the former names of
+// SCIM groups that were just deleted or renamed.
+func (api *API) scimSyncGroupMembers(ctx context.Context, userIDs []uuid.UUID, removedGroups ...string) error {
+	if !api.IDPSync.GroupSyncEntitled() || len(userIDs) == 0 {
+		return nil
+	}
+
+	users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return xerrors.Errorf("get users: %w", err)
+	}
+	rows, err := api.Database.GetSCIMGroupsByUserIDs(ctx, userIDs)
+	if err != nil {
+		return xerrors.Errorf("get scim groups: %w", err)
+	}
+	groupNames := make(map[uuid.UUID][]string, len(users))
+	for _, row := range rows {
+		groupNames[row.UserID] = append(groupNames[row.UserID], row.DisplayName)
+	}
+
+	for _, user := range users {
+		if user.Deleted {
+			continue
+		}
+		names := groupNames[user.ID]
+		if names == nil {
+			// An empty, non-nil list removes the user from all mapped groups.
+			names = []string{}
+		}
+		err = api.IDPSync.SyncGroups(ctx, api.Database, user, idpsync.GroupParams{
+			SyncEntitled:      true,
+			GroupNames:        names,
+			RemovedGroupNames: removedGroups,
+		})
+		if err != nil {
+			return xerrors.Errorf("sync groups for user %s: %w", user.Username, err)
+		}
+		api.Logger.Debug(ctx, "synced groups from scim",
+			slog.F("user_id", user.ID),
+			slog.F("scim_groups", len(names)),
+		)
+	}
+	return nil
+}
+
+// scimGroups converts database groups to SCIM groups, including members.
+func (api *API) scimGroups(ctx context.Context, groups []database.SCIMGroup) ([]SCIMGroup, error) {
+	ids := db2sdk.List(groups, func(g database.SCIMGroup) uuid.UUID {
+		return g.ID
+	})
+	rows, err := api.Database.GetSCIMGroupMembers(ctx, ids)
+	if err != nil {
+		return nil, xerrors.Errorf("get members: %w", err)
+	}
+	members := make(map[uuid.UUID][]SCIMGroupMember)
+	for _, row := range rows {
+		members[row.SCIMGroupID] = append(members[row.SCIMGroupID], SCIMGroupMember{
+			Value:   row.UserID.String(),
+			Display: row.Username,
+		})
+	}
+
+	converted := make([]SCIMGroup, 0, len(groups))
+	for _, g := range groups {
+		var location string
+		locURL, err := api.AccessURL.Parse("/scim/v2/Groups/" + g.ID.String())
+		if err == nil {
+			location = locURL.String()
+		}
+		groupMembers := members[g.ID]
+		if groupMembers == nil {
+			groupMembers = []SCIMGroupMember{}
+		}
+		converted = append(converted, SCIMGroup{
+			Schemas:     []string{scim.SchemaGroup},
+			ID:          g.ID.String(),
+			ExternalID:  g.ExternalID,
+			DisplayName: g.DisplayName,
+			Members:     groupMembers,
+			Meta: SCIMGroupMeta{
+				ResourceType: "Group",
+				Created:      g.CreatedAt,
+				LastModified: g.UpdatedAt,
+				Location:     location,
+			},
+		})
+	}
+	return converted, nil
+}
+
+func (api *API) scimWriteGroup(ctx context.Context, rw http.ResponseWriter, status int, group database.SCIMGroup) {
+	converted, err := api.scimGroups(ctx, []database.SCIMGroup{group})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scim.NewHTTPError(http.StatusInternalServerError, "internalError", err))
+		return
+	}
+	rw.Header().Set("Content-Type", spec.ApplicationScimJson)
+	httpapi.Write(ctx, rw, status, converted[0])
+}
+
+var scimGroupFilterRegex = regexp.MustCompile(`(?i)^\s*displayName\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseSCIMGroupFilter returns the display name to filter by. An empty filter
+// matches all groups.
+func parseSCIMGroupFilter(filter string) (string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil
+	}
+	match := scimGroupFilterRegex.FindStringSubmatch(filter)
+	if match == nil {
+		return "", xerrors.Errorf("unsupported filter %q, only 'displayName eq \"name\"' is supported", filter)
+	}
+	name, err := strconv.Unquote(`"` + match[1] + `"`)
+	if err != nil {
+		return "", xerrors.Errorf("invalid displayName in filter: %w", err)
+	}
+	return name, nil
+}
+
+func parseSCIMQueryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func parseSCIMGroupMembers(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		id, err := uuid.Parse(m.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("member value %q must be a user id: %w", m.Value, err)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// scimCheckMembers returns a SCIM error if any of the IDs is not an existing
+// user. Identity providers only send members they have provisioned, so an
+// unknown ID means the user was never pushed or has been deleted.
+func scimCheckMembers(ctx context.Context, db database.Store, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	users, err := db.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return scim.NewHTTPError(http.StatusInternalServerError, "internalError", xerrors.Errorf("get users: %w", err))
+	}
+	for _, id := range ids {
+		found := slices.ContainsFunc(users, func(u database.User) bool {
+			return u.ID == id && !u.Deleted
+		})
+		if !found {
+			return scim.NewHTTPError(http.StatusBadRequest, "invalidValue", xerrors.Errorf("member %s is not a user", id))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+	"github.com/coder/coder/v2/testutil"
+)
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("noAuth", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: []byte("hi"),
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+
+		res, err := client.Request(ctx, http.MethodGet, "/scim/v2/Groups", nil)
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+
+		eng, err := client.CreateGroup(ctx, owner.OrganizationID, codersdk.CreateGroupRequest{Name: "eng"})
+		require.NoError(t, err)
+		ops, err := client.CreateGroup(ctx, owner.OrganizationID, codersdk.CreateGroupRequest{Name: "ops"})
+		require.NoError(t, err)
+		// No claim field is set, so logins do not change group membership.
+		_, err = client.PatchGroupIDPSyncSettings(ctx, owner.OrganizationID.String(), codersdk.GroupSyncSettings{
+			Mapping: map[string][]uuid.UUID{
+				"Engineering": {eng.ID},
+				"Operations":  {ops.ID},
+			},
+		})
+		require.NoError(t, err)
+
+		_, alice := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, bob := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		request := func(method, path string, body any, status int, out any) {
+			t.Helper()
+			res, err := client.Request(ctx, method, path, body, setScimAuthBearer(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+			if out != nil {
+				require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+			}
+		}
+		requireMembers := func(groupID uuid.UUID, users ...codersdk.User) {
+			t.Helper()
+			group, err := client.Group(ctx, groupID)
+			require.NoError(t, err)
+			expected := make([]uuid.UUID, 0, len(users))
+			for _, u := range users {
+				expected = append(expected, u.ID)
+			}
+			found := make([]uuid.UUID, 0, len(group.Members))
+			for _, m := range group.Members {
+				found = append(found, m.ID)
+			}
+			require.ElementsMatch(t, expected, found)
+		}
+
+		// Creating a group syncs its members.
+		var created coderd.SCIMGroup
+		request(http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Engineering",
+			ExternalID:  "okta-eng",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+		}, http.StatusCreated, &created)
+		require.Equal(t, "Engineering", created.DisplayName)
+		require.Equal(t, "okta-eng", created.ExternalID)
+		require.Equal(t, []coderd.SCIMGroupMember{{Value: alice.ID.String(), Display: alice.Username}}, created.Members)
+		requireMembers(eng.ID, alice)
+
+		// Display names are unique.
+		request(http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "Engineering"}, http.StatusConflict, nil)
+
+		var list scim.ListResponse
+		list.Resources = &[]coderd.SCIMGroup{}
+		request(http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "Engineering"`), nil, http.StatusOK, &list)
+		require.Equal(t, 1, list.TotalResults)
+		require.Equal(t, created.ID, (*list.Resources.(*[]coderd.SCIMGroup))[0].ID)
+		request(http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`displayName eq "Nope"`), nil, http.StatusOK, &list)
+		require.Equal(t, 0, list.TotalResults)
+		request(http.MethodGet, "/scim/v2/Groups?filter="+url.QueryEscape(`externalId eq "okta-eng"`), nil, http.StatusBadRequest, nil)
+
+		groupPath := "/scim/v2/Groups/" + created.ID
+		patch := func(ops ...scim.PatchOperation) {
+			t.Helper()
+			request(http.MethodPatch, groupPath, scim.PatchRequest{
+				Schemas:    []string{scim.SchemaPatchOp},
+				Operations: ops,
+			}, http.StatusOK, nil)
+		}
+
+		// Adding a member applies without a login.
+		patch(scim.PatchOperation{
+			Op:    "Add",
+			Path:  "members",
+			Value: json.RawMessage(`[{"value":"` + bob.ID.String() + `"}]`),
+		})
+		requireMembers(eng.ID, alice, bob)
+
+		patch(scim.PatchOperation{
+			Op:   "remove",
+			Path: `members[value eq "` + alice.ID.String() + `"]`,
+		})
+		requireMembers(eng.ID, bob)
+
+		// Renaming the group moves members to the newly mapped group.
+		patch(scim.PatchOperation{
+			Op:    "replace",
+			Value: json.RawMessage(`{"displayName":"Operations"}`),
+		})
+		requireMembers(eng.ID)
+		requireMembers(ops.ID, bob)
+
+		var got coderd.SCIMGroup
+		request(http.MethodGet, groupPath, nil, http.StatusOK, &got)
+		require.Equal(t, "Operations", got.DisplayName)
+		require.Len(t, got.Members, 1)
+
+		// Replacing the group replaces its members.
+		request(http.MethodPut, groupPath, coderd.SCIMGroup{
+			DisplayName: "Operations",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+		}, http.StatusOK, &got)
+		requireMembers(ops.ID, alice)
+
+		// Deleting the group removes its members from the mapped groups.
+		request(http.MethodDelete, groupPath, nil, http.StatusNoContent, nil)
+		requireMembers(ops.ID)
+		request(http.MethodGet, groupPath, nil, http.StatusNotFound, nil)
+	})
+
+	t.Run("UnmappedGroup", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM:         1,
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		manual, err := client.CreateGroup(ctx, owner.OrganizationID, codersdk.CreateGroupRequest{Name: "manual"})
+		require.NoError(t, err)
+		_, alice := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err = client.PatchGroup(ctx, manual.ID, codersdk.PatchGroupRequest{AddUsers: []string{alice.ID.String()}})
+		require.NoError(t, err)
+
+		// Without group sync settings, SCIM groups do not touch Coder groups.
+		res, err := client.Request(ctx, http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Engineering",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+		}, setScimAuth(scimAPIKey))
+		require.NoError(t, err)
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+
+		group, err := client.Group(ctx, manual.ID)
+		require.NoError(t, err)
+		require.Len(t, group.Members, 1)
+	})
+	t.Run("UnknownMember", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+		_, alice := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		unknown := uuid.New()
+
+		request := func(method, path string, body any, status int, out any) {
+			t.Helper()
+			res, err := client.Request(ctx, method, path, body, setScimAuthBearer(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+			if out != nil {
+				require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+			}
+		}
+
+		// Unknown members are rejected instead of being dropped.
+		request(http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Engineering",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}, {Value: unknown.String()}},
+		}, http.StatusBadRequest, nil)
+
+		var created coderd.SCIMGroup
+		request(http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "Engineering",
+			Members:     []coderd.SCIMGroupMember{{Value: alice.ID.String()}},
+		}, http.StatusCreated, &created)
+
+		groupPath := "/scim/v2/Groups/" + created.ID
+		request(http.MethodPatch, groupPath, scim.PatchRequest{
+			Schemas: []string{scim.SchemaPatchOp},
+			Operations: []scim.PatchOperation{{
+				Op:    "add",
+				Path:  "members",
+				Value: json.RawMessage(`[{"value":"` + unknown.String() + `"}]`),
+			}},
+		}, http.StatusBadRequest, nil)
+		request(http.MethodPut, groupPath, coderd.SCIMGroup{
+			DisplayName: "Engineering",
+			Members:     []coderd.SCIMGroupMember{{Value: unknown.String()}},
+		}, http.StatusBadRequest, nil)
+
+		// The failed requests left the group unchanged.
+		var got coderd.SCIMGroup
+		request(http.MethodGet, groupPath, nil, http.StatusOK, &got)
+		require.Equal(t, []coderd.SCIMGroupMember{{Value: alice.ID.String(), Display: alice.Username}}, got.Members)
+	})
+
+	t.Run("Audit", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		mockAudit := audit.NewMock()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Auditor: mockAudit,
+			},
+			SCIMAPIKey:   scimAPIKey,
+			AuditLogging: true,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureSCIM:     1,
+					codersdk.FeatureAuditLog: 1,
+				},
+			},
+		})
+		mockAudit.ResetLogs()
+
+		request := func(method, path string, body any, status int, out any) {
+			t.Helper()
+			res, err := client.Request(ctx, method, path, body, setScimAuthBearer(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, status, res.StatusCode)
+			if out != nil {
+				require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+			}
+		}
+
+		var created coderd.SCIMGroup
+		request(http.MethodPost, "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "Engineering"}, http.StatusCreated, &created)
+		groupID, err := uuid.Parse(created.ID)
+		require.NoError(t, err)
+		groupPath := "/scim/v2/Groups/" + created.ID
+		request(http.MethodPatch, groupPath, scim.PatchRequest{
+			Schemas: []string{scim.SchemaPatchOp},
+			Operations: []scim.PatchOperation{{
+				Op:    "replace",
+				Path:  "displayName",
+				Value: json.RawMessage(`"Operations"`),
+			}},
+		}, http.StatusOK, nil)
+		request(http.MethodDelete, groupPath, nil, http.StatusNoContent, nil)
+
+		aLogs := mockAudit.AuditLogs()
+		require.Len(t, aLogs, 3)
+		for i, action := range []database.AuditAction{
+			database.AuditActionCreate,
+			database.AuditActionWrite,
+			database.AuditActionDelete,
+		} {
+			require.Equal(t, action, aLogs[i].Action)
+			require.Equal(t, database.ResourceTypeScimGroup, aLogs[i].ResourceType)
+			require.Equal(t, groupID, aLogs[i].ResourceID)
+			af := map[string]string{}
+			require.NoError(t, json.Unmarshal(aLogs[i].AdditionalFields, &af))
+			require.Equal(t, coderd.SCIMAuditAdditionalFields, af)
+		}
+		require.Equal(t, "Operations", aLogs[1].ResourceTarget)
+	})
+}
@@ -59,6 +59,15 @@ type agentAttributes struct {
 	DisplayApps              []agentDisplayAppsAttributes `mapstructure:"display_apps"`
 	Order                    int64                        `mapstructure:"order"`
 	ResourcesMonitoring      []agentResourcesMonitoring   `mapstructure:"resources_monitoring"`
+	PortForwardingPolicy     []agentPortForwardingPolicy  `mapstructure:"port_forwarding_policy"`
+}
+
+// A mapping of attributes on the "port_forwarding_policy" block of an agent.
+type agentPortForwardingPolicy struct {
+	AllowedPorts       []string `mapstructure:"allowed_ports"`
+	DeniedPorts        []string `mapstructure:"denied_ports"`
+	AllowedUnixSockets []string `mapstructure:"allowed_unix_sockets"`
+	DeniedUnixSockets  []string `mapstructure:"denied_unix_sockets"`
 }
 
 type agentDevcontainerAttributes struct {
@@ -68,8 +77,12 @@ type agentDevcontainerAttributes struct {
 }
 
 type agentResourcesMonitoring struct {
-	Memory  []agentMemoryResourceMonitor `mapstructure:"memory"`
-	Volumes []agentVolumeResourceMonitor `mapstructure:"volume"`
+	Memory          []agentMemoryResourceMonitor `mapstructure:"memory"`
+	Volumes         []agentVolumeResourceMonitor `mapstructure:"volume"`
+	CPU             []agentResourceMonitor       `mapstructure:"cpu"`
+	Network         []agentResourceMonitor       `mapstructure:"network"`
+	FileDescriptors []agentResourceMonitor       `mapstructure:"file_descriptors"`
+	Processes       []agentResourceMonitor       `mapstructure:"processes"`
 }
 
 type agentMemoryResourceMonitor struct {
@@ -83,6 +96,11 @@ type agentVolumeResourceMonitor struct {
 	Threshold int32  `mapstructure:"threshold"`
 }
 
+type agentResourceMonitor struct {
+	Enabled   bool  `mapstructure:"enabled"`
+	Threshold int32 `mapstructure:"threshold"`
+}
+
 type agentDisplayAppsAttributes struct {
 	VSCode               bool `mapstructure:"vscode"`
 	VSCodeInsiders       bool `mapstructure:"vscode_insiders"`
@@ -129,6 +147,19 @@ type agentScriptAttributes struct {
 	RunOnStart       bool   `mapstructure:"run_on_start"`
 	RunOnStop        bool   `mapstructure:"run_on_stop"`
 	TimeoutSeconds   int32  `mapstructure:"timeout"`
+	// DependsOnScripts are display names of other scripts of the agent.
+	// Terraform reserves the depends_on argument for resource addresses.
+	DependsOnScripts []string                    `mapstructure:"depends_on_scripts"`
+	ReadinessProbe   []agentScriptReadinessProbe `mapstructure:"readiness_probe"`
+}
+
+// A mapping of attributes on the "readiness_probe" block of a script.
+type agentScriptReadinessProbe struct {
+	Command  string `mapstructure:"command"`
+	TCP      string `mapstructure:"tcp"`
+	HTTP     string `mapstructure:"http"`
+	Interval int32  `mapstructure:"interval"`
+	Timeout  int32  `mapstructure:"timeout"`
 }
 
 // A mapping of attributes on the "healthcheck" resource.
@@ -306,6 +337,21 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 				}
 			}
 
+			for _, resource := range attrs.ResourcesMonitoring {
+				for _, monitor := range resource.CPU {
+					resourcesMonitoring.Cpu = convertResourceMonitor(monitor)
+				}
+				for _, monitor := range resource.Network {
+					resourcesMonitoring.Network = convertResourceMonitor(monitor)
+				}
+				for _, monitor := range resource.FileDescriptors {
+					resourcesMonitoring.FileDescriptors = convertResourceMonitor(monitor)
+				}
+				for _, monitor := range resource.Processes {
+					resourcesMonitoring.Processes = convertResourceMonitor(monitor)
+				}
+			}
+
 			agent := &proto.Agent{
 				Name:                     tfResource.Name,
 				Id:                       attrs.ID,
@@ -321,6 +367,7 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 				DisplayApps:              displayApps,
 				Order:                    attrs.Order,
 				ApiKeyScope:              attrs.APIKeyScope,
+				PortForwardingPolicy:     convertPortForwardingPolicy(attrs.PortForwardingPolicy),
 			}
 			// Support the legacy script attributes in the agent!
 			if attrs.StartupScript != "" {
@@ -593,6 +640,8 @@ func ConvertState(ctx context.Context, modules []*tfjson.StateModule, rawGraph s
 						RunOnStart:       attrs.RunOnStart,
 						RunOnStop:        attrs.RunOnStop,
 						TimeoutSeconds:   attrs.TimeoutSeconds,
+						DependsOn:        attrs.DependsOnScripts,
+						ReadinessProbe:   convertScriptReadinessProbe(attrs.ReadinessProbe),
 					})
 				}
 			}
@@ -976,6 +1025,15 @@ func convertAddressToLabel(address string) string {
 	return cut
 }
 
+// convertResourceMonitor converts a monitor block of the agent's
+// resources_monitoring that only has a threshold, unlike memory and volumes.
+func convertResourceMonitor(monitor agentResourceMonitor) *proto.ResourceMonitor {
+	return &proto.ResourceMonitor{
+		Enabled:   monitor.Enabled,
+		Threshold: monitor.Threshold,
+	}
+}
+
 // convertAddressToModulePath returns the module path from a Terraform address.
 // eg. "module.ec2_dev.ec2_instance.dev[0]" becomes "module.ec2_dev".
 // Empty string is returned for the root module.
@@ -1014,6 +1072,35 @@ func dependsOnAgent(graph *gographviz.Graph, agent *proto.Agent, resourceAgentID
 	return agent.Id == resourceAgentID
 }
 
+// convertPortForwardingPolicy converts the port_forwarding_policy block of an
+// agent, which Terraform stores as a list of at most one element.
+func convertPortForwardingPolicy(policies []agentPortForwardingPolicy) *proto.PortForwardingPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	return &proto.PortForwardingPolicy{
+		AllowedPorts:       policies[0].AllowedPorts,
+		DeniedPorts:        policies[0].DeniedPorts,
+		AllowedUnixSockets: policies[0].AllowedUnixSockets,
+		DeniedUnixSockets:  policies[0].DeniedUnixSockets,
+	}
+}
+
+// convertScriptReadinessProbe converts the readiness_probe block of a script,
+// which Terraform stores as a list of at most one element.
+func convertScriptReadinessProbe(probes []agentScriptReadinessProbe) *proto.ScriptReadinessProbe {
+	if len(probes) == 0 {
+		return nil
+	}
+	return &proto.ScriptReadinessProbe{
+		Command:         probes[0].Command,
+		Tcp:             probes[0].TCP,
+		Http:            probes[0].HTTP,
+		IntervalSeconds: probes[0].Interval,
+		TimeoutSeconds:  probes[0].Timeout,
+	}
+}
+
 type graphResource struct {
 	Label string
 	Depth uint
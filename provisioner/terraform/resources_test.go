@@ -1381,6 +1381,111 @@ func TestInstanceIDAssociation(t *testing.T) {
 	}
 }
 
+// TestConvertStateAgentBlocks covers agent and script blocks that the provider
+// version used to generate the testdata does not declare yet, so the state is
+// built here instead of in a fixture.
+func TestConvertStateAgentBlocks(t *testing.T) {
+	t.Parallel()
+	ctx, logger := ctxAndLogger(t)
+
+	state, err := terraform.ConvertState(ctx, []*tfjson.StateModule{{
+		Resources: []*tfjson.StateResource{{
+			Address: "coder_agent.dev",
+			Type:    "coder_agent",
+			Name:    "dev",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"id":   "agent-id",
+				"os":   "linux",
+				"arch": "amd64",
+				"auth": "token",
+				"port_forwarding_policy": []interface{}{map[string]interface{}{
+					"allowed_ports":       []interface{}{"3000-3999", "8080"},
+					"denied_ports":        []interface{}{"3306"},
+					"denied_unix_sockets": []interface{}{"/var/run/docker.sock"},
+				}},
+				"resources_monitoring": []interface{}{map[string]interface{}{
+					"cpu":       []interface{}{map[string]interface{}{"enabled": true, "threshold": 90}},
+					"processes": []interface{}{map[string]interface{}{"enabled": true, "threshold": 2000}},
+				}},
+			},
+		}, {
+			Address: "coder_script.first",
+			Type:    "coder_script",
+			Name:    "first",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"agent_id":     "agent-id",
+				"display_name": "First",
+				"script":       "echo first",
+				"run_on_start": true,
+				"readiness_probe": []interface{}{map[string]interface{}{
+					"http":     "http://localhost:8080/healthz",
+					"interval": 2,
+					"timeout":  60,
+				}},
+			},
+		}, {
+			Address: "coder_script.second",
+			Type:    "coder_script",
+			Name:    "second",
+			Mode:    tfjson.ManagedResourceMode,
+			AttributeValues: map[string]interface{}{
+				"agent_id":           "agent-id",
+				"display_name":       "Second",
+				"script":             "echo second",
+				"run_on_start":       true,
+				"depends_on_scripts": []interface{}{"First"},
+			},
+		}, {
+			Address:   "null_resource.dev",
+			Type:      "null_resource",
+			Name:      "dev",
+			Mode:      tfjson.ManagedResourceMode,
+			DependsOn: []string{"coder_agent.dev"},
+		}},
+		// This is manually created to join the edges.
+	}}, `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] coder_agent.dev" [label = "coder_agent.dev", shape = "box"]
+		"[root] coder_script.first" [label = "coder_script.first", shape = "box"]
+		"[root] coder_script.second" [label = "coder_script.second", shape = "box"]
+		"[root] null_resource.dev" [label = "null_resource.dev", shape = "box"]
+		"[root] coder_script.first" -> "[root] coder_agent.dev"
+		"[root] coder_script.second" -> "[root] coder_agent.dev"
+		"[root] null_resource.dev" -> "[root] coder_agent.dev"
+	}
+}
+`, logger)
+	require.NoError(t, err)
+	require.Len(t, state.Resources, 1)
+	require.Len(t, state.Resources[0].Agents, 1)
+	agent := state.Resources[0].Agents[0]
+
+	require.Equal(t, []string{"3000-3999", "8080"}, agent.GetPortForwardingPolicy().GetAllowedPorts())
+	require.Equal(t, []string{"3306"}, agent.GetPortForwardingPolicy().GetDeniedPorts())
+	require.Equal(t, []string{"/var/run/docker.sock"}, agent.GetPortForwardingPolicy().GetDeniedUnixSockets())
+
+	monitoring := agent.GetResourcesMonitoring()
+	require.True(t, monitoring.GetCpu().GetEnabled())
+	require.EqualValues(t, 90, monitoring.GetCpu().GetThreshold())
+	require.EqualValues(t, 2000, monitoring.GetProcesses().GetThreshold())
+	require.Nil(t, monitoring.GetNetwork())
+
+	scripts := agent.GetScripts()
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].DisplayName < scripts[j].DisplayName
+	})
+	require.Len(t, scripts, 2)
+	require.Equal(t, "http://localhost:8080/healthz", scripts[0].GetReadinessProbe().GetHttp())
+	require.EqualValues(t, 2, scripts[0].GetReadinessProbe().GetIntervalSeconds())
+	require.EqualValues(t, 60, scripts[0].GetReadinessProbe().GetTimeoutSeconds())
+	require.Equal(t, []string{"First"}, scripts[1].GetDependsOn())
+	require.Nil(t, scripts[1].GetReadinessProbe())
+}
+
 // sortResource ensures resources appear in a consistent ordering
 // to prevent tests from flaking.
 func sortResources(resources []*proto.Resource) {
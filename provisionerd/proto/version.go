@@ -25,9 +25,14 @@ import "github.com/coder/coder/v2/apiversion"
 //   - Add previous parameter values to 'WorkspaceBuild' jobs. Provisioner passes
 //     the previous values for the `terraform apply` to enforce monotonicity
 //     in the terraform provider.
+//
+// API v1.7:
+//   - Add `cpu`, `network`, `file_descriptors` and `processes` monitors to `ResourcesMonitoring`.
+//   - Add `depends_on` and `readiness_probe` fields to `Script`.
+//   - Add `port_forwarding_policy` field to `Agent`.
 const (
 	CurrentMajor = 1
-	CurrentMinor = 6
+	CurrentMinor = 7
 )
 
 // CurrentVersion is the current provisionerd API version.
@@ -180,6 +180,7 @@ export interface Agent {
   resourcesMonitoring: ResourcesMonitoring | undefined;
   devcontainers: Devcontainer[];
   apiKeyScope: string;
+  portForwardingPolicy: PortForwardingPolicy | undefined;
 }
 
 export interface Agent_Metadata {
@@ -196,9 +197,37 @@ export interface Agent_EnvEntry {
   value: string;
 }
 
+/**
+ * PortForwardingPolicy restricts the TCP ports and Unix sockets in the
+ * workspace that clients can connect to through the agent. Ports are numbers
+ * or ranges like "3000-3999" and sockets are path patterns like
+ * "/run/user/*\/app.sock". Denied entries take precedence over allowed ones,
+ * and an empty allow list allows everything that is not denied.
+ */
+export interface PortForwardingPolicy {
+  allowedPorts: string[];
+  deniedPorts: string[];
+  allowedUnixSockets: string[];
+  deniedUnixSockets: string[];
+}
+
 export interface ResourcesMonitoring {
   memory: MemoryResourceMonitor | undefined;
   volumes: VolumeResourceMonitor[];
+  cpu: ResourceMonitor | undefined;
+  network: ResourceMonitor | undefined;
+  fileDescriptors: ResourceMonitor | undefined;
+  processes: ResourceMonitor | undefined;
+}
+
+/**
+ * ResourceMonitor is a monitor with a single threshold per agent. The
+ * threshold is a percentage, except for network monitors where it is the
+ * throughput in Mbit/s.
+ */
+export interface ResourceMonitor {
+  enabled: boolean;
+  threshold: number;
 }
 
 export interface MemoryResourceMonitor {
@@ -236,6 +265,24 @@ export interface Script {
   runOnStop: boolean;
   timeoutSeconds: number;
   logPath: string;
+  /**
+   * depends_on are the display names of the scripts of the same agent that
+   * must be ready before this script is started.
+   */
+  dependsOn: string[];
+  readinessProbe: ScriptReadinessProbe | undefined;
+}
+
+/**
+ * ScriptReadinessProbe is a check that marks a script as ready for the
+ * scripts that depend on it. Exactly one of command, tcp and http is set.
+ */
+export interface ScriptReadinessProbe {
+  command: string;
+  tcp: string;
+  http: string;
+  intervalSeconds: number;
+  timeoutSeconds: number;
 }
 
 export interface Devcontainer {
@@ -714,6 +761,9 @@ export const Agent = {
     if (message.apiKeyScope !== "") {
       writer.uint32(210).string(message.apiKeyScope);
     }
+    if (message.portForwardingPolicy !== undefined) {
+      PortForwardingPolicy.encode(message.portForwardingPolicy, writer.uint32(218).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -754,6 +804,24 @@ export const Agent_EnvEntry = {
   },
 };
 
+export const PortForwardingPolicy = {
+  encode(message: PortForwardingPolicy, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    for (const v of message.allowedPorts) {
+      writer.uint32(10).string(v!);
+    }
+    for (const v of message.deniedPorts) {
+      writer.uint32(18).string(v!);
+    }
+    for (const v of message.allowedUnixSockets) {
+      writer.uint32(26).string(v!);
+    }
+    for (const v of message.deniedUnixSockets) {
+      writer.uint32(34).string(v!);
+    }
+    return writer;
+  },
+};
+
 export const ResourcesMonitoring = {
   encode(message: ResourcesMonitoring, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
     if (message.memory !== undefined) {
@@ -762,6 +830,30 @@ export const ResourcesMonitoring = {
     for (const v of message.volumes) {
       VolumeResourceMonitor.encode(v!, writer.uint32(18).fork()).ldelim();
     }
+    if (message.cpu !== undefined) {
+      ResourceMonitor.encode(message.cpu, writer.uint32(26).fork()).ldelim();
+    }
+    if (message.network !== undefined) {
+      ResourceMonitor.encode(message.network, writer.uint32(34).fork()).ldelim();
+    }
+    if (message.fileDescriptors !== undefined) {
+      ResourceMonitor.encode(message.fileDescriptors, writer.uint32(42).fork()).ldelim();
+    }
+    if (message.processes !== undefined) {
+      ResourceMonitor.encode(message.processes, writer.uint32(50).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const ResourceMonitor = {
+  encode(message: ResourceMonitor, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.enabled === true) {
+      writer.uint32(8).bool(message.enabled);
+    }
+    if (message.threshold !== 0) {
+      writer.uint32(16).int32(message.threshold);
+    }
     return writer;
   },
 };
@@ -855,6 +947,33 @@ export const Script = {
     if (message.logPath !== "") {
       writer.uint32(74).string(message.logPath);
     }
+    for (const v of message.dependsOn) {
+      writer.uint32(82).string(v!);
+    }
+    if (message.readinessProbe !== undefined) {
+      ScriptReadinessProbe.encode(message.readinessProbe, writer.uint32(90).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const ScriptReadinessProbe = {
+  encode(message: ScriptReadinessProbe, writer: _m0.Writer = _m0.Writer.create()): _m0.Writer {
+    if (message.command !== "") {
+      writer.uint32(10).string(message.command);
+    }
+    if (message.tcp !== "") {
+      writer.uint32(18).string(message.tcp);
+    }
+    if (message.http !== "") {
+      writer.uint32(26).string(message.http);
+    }
+    if (message.intervalSeconds !== 0) {
+      writer.uint32(32).int32(message.intervalSeconds);
+    }
+    if (message.timeoutSeconds !== 0) {
+      writer.uint32(40).int32(message.timeoutSeconds);
+    }
     return writer;
   },
 };
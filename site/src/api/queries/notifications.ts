@@ -37,6 +37,7 @@ export const updateUserNotificationPreferences = (
 						({
 							id,
 							disabled,
+							digest: data.template_digest_map?.[id] ?? false,
 							updated_at: new Date().toISOString(),
 						}) satisfies NotificationPreference,
 				),
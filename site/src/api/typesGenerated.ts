@@ -30,6 +30,8 @@ export interface APIKey {
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
 	readonly lifetime_seconds: number;
+	readonly scope_permissions: readonly string[];
+	readonly scope_allow_list: readonly string[];
 }
 
 // From codersdk/apikey.go
@@ -67,6 +69,7 @@ export interface AgentConnectionTiming {
 
 // From codersdk/workspacebuilds.go
 export interface AgentScriptTiming {
+	readonly queued_at: string;
 	readonly started_at: string;
 	readonly ended_at: string;
 	readonly exit_code: number;
@@ -192,12 +195,103 @@ export interface AuditLog {
 	readonly user: User | null;
 }
 
+// From codersdk/auditchain.go
+export interface AuditLogChain {
+	readonly organization_id: string;
+	readonly organization_name: string;
+	readonly organization_deleted: boolean;
+}
+
+// From codersdk/auditchain.go
+export interface AuditLogChainEntry {
+	readonly organization_id: string;
+	readonly sequence: number;
+	readonly audit_log_id: string;
+	readonly prev_hash: string;
+	readonly hash: string;
+	readonly record?: AuditLogChainRecord;
+}
+
+// From codersdk/auditchain.go
+export interface AuditLogChainRecord {
+	readonly id: string;
+	readonly time: string;
+	readonly user_id: string;
+	readonly organization_id: string;
+	readonly ip: string;
+	readonly user_agent: string;
+	readonly resource_type: ResourceType;
+	readonly resource_id: string;
+	readonly resource_target: string;
+	readonly resource_icon: string;
+	readonly action: AuditAction;
+	readonly diff: Record<string, string>;
+	readonly status_code: number;
+	readonly additional_fields: Record<string, string>;
+	readonly request_id: string;
+}
+
+// From codersdk/auditchain.go
+export interface AuditLogChainRequest {
+	readonly organization_id?: string;
+	readonly after_sequence?: number;
+	readonly limit?: number;
+}
+
+// From codersdk/auditchain.go
+export interface AuditLogCheckpoint {
+	readonly organization_id: string;
+	readonly sequence: number;
+	readonly hash: string;
+	readonly signature: string;
+	readonly created_at: string;
+}
+
+// From codersdk/auditchain.go
+export interface AuditLogPublicKey {
+	readonly public_key: string;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
 	readonly audit_logs: readonly AuditLog[];
 	readonly count: number;
 }
 
+// From codersdk/deployment.go
+export interface AuditLoggingConfig {
+	readonly signing_key_file: string;
+	readonly syslog: AuditLoggingSyslogConfig;
+	readonly http: AuditLoggingHTTPConfig;
+	readonly file: AuditLoggingFileConfig;
+}
+
+// From codersdk/deployment.go
+export interface AuditLoggingFileConfig {
+	readonly path: string;
+	readonly max_size: number;
+	readonly max_backups: number;
+}
+
+// From codersdk/deployment.go
+export interface AuditLoggingHTTPConfig {
+	readonly url: string;
+	readonly headers: string;
+	readonly batch_size: number;
+	readonly flush_interval: number;
+	readonly queue_dir: string;
+	readonly queue_max_size: number;
+}
+
+// From codersdk/deployment.go
+export interface AuditLoggingSyslogConfig {
+	readonly address: string;
+	readonly tls: boolean;
+	readonly tls_ca_file: string;
+	readonly tls_cert_file: string;
+	readonly tls_key_file: string;
+}
+
 // From codersdk/audit.go
 export interface AuditLogsRequest extends Pagination {
 	readonly q?: string;
@@ -467,6 +561,8 @@ export interface CreateTokenRequest {
 	readonly lifetime: number;
 	readonly scope: APIKeyScope;
 	readonly token_name: string;
+	readonly scope_permissions?: readonly string[];
+	readonly scope_allow_list?: readonly string[];
 }
 
 // From codersdk/users.go
@@ -512,6 +608,12 @@ export interface CreateWorkspaceRequest {
 	readonly enable_dynamic_parameters?: boolean;
 }
 
+// From codersdk/workspacesnapshots.go
+export interface CreateWorkspaceSnapshotRequest {
+	readonly name: string;
+	readonly build_number?: number;
+}
+
 // From codersdk/deployment.go
 export interface CryptoKey {
 	readonly feature: CryptoKeyFeature;
@@ -735,6 +837,7 @@ export interface DeploymentValues {
 	readonly additional_csp_policy?: string;
 	readonly workspace_hostname_suffix?: string;
 	readonly workspace_prebuilds?: PrebuildsConfig;
+	readonly audit_logging?: AuditLoggingConfig;
 	readonly config?: string;
 	readonly write_config?: boolean;
 	readonly address?: string;
@@ -1387,6 +1490,8 @@ export interface NotificationMethodsResponse {
 export interface NotificationPreference {
 	readonly id: string;
 	readonly disabled: boolean;
+	readonly method?: string;
+	readonly digest: boolean;
 	readonly updated_at: string;
 }
 
@@ -1401,6 +1506,7 @@ export interface NotificationTemplate {
 	readonly method: string;
 	readonly kind: string;
 	readonly enabled_by_default: boolean;
+	readonly digestible: boolean;
 }
 
 // From codersdk/deployment.go
@@ -1414,8 +1520,11 @@ export interface NotificationsConfig {
 	readonly fetch_interval: number;
 	readonly method: string;
 	readonly dispatch_timeout: number;
+	readonly digest_interval: number;
 	readonly email: NotificationsEmailConfig;
 	readonly webhook: NotificationsWebhookConfig;
+	readonly slack: NotificationsSlackConfig;
+	readonly teams: NotificationsTeamsConfig;
 	readonly inbox: NotificationsInboxConfig;
 }
 
@@ -1457,6 +1566,19 @@ export interface NotificationsSettings {
 	readonly notifier_paused: boolean;
 }
 
+// From codersdk/deployment.go
+export interface NotificationsSlackConfig {
+	readonly webhook_url: string;
+	readonly bot_token: string;
+	readonly channel: string;
+	readonly api_url: string;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsTeamsConfig {
+	readonly webhook_url: string;
+}
+
 // From codersdk/deployment.go
 export interface NotificationsWebhookConfig {
 	readonly endpoint: string;
@@ -2272,14 +2394,17 @@ export type ResourceType =
 	| "oauth2_provider_app_secret"
 	| "organization"
 	| "organization_member"
+	| "scim_group"
 	| "template"
 	| "template_version"
 	| "user"
 	| "workspace"
 	| "workspace_agent"
+	| "workspace_agent_port_share"
 	| "workspace_app"
 	| "workspace_build"
-	| "workspace_proxy";
+	| "workspace_proxy"
+	| "workspace_snapshot";
 
 export const ResourceTypes: ResourceType[] = [
 	"api_key",
@@ -2298,14 +2423,17 @@ export const ResourceTypes: ResourceType[] = [
 	"oauth2_provider_app_secret",
 	"organization",
 	"organization_member",
+	"scim_group",
 	"template",
 	"template_version",
 	"user",
 	"workspace",
 	"workspace_agent",
+	"workspace_agent_port_share",
 	"workspace_app",
 	"workspace_build",
 	"workspace_proxy",
+	"workspace_snapshot",
 ];
 
 // From codersdk/client.go
@@ -2365,6 +2493,14 @@ export const RoleTemplateAdmin = "template-admin";
 // From codersdk/rbacroles.go
 export const RoleUserAdmin = "user-admin";
 
+// From codersdk/scim.go
+export interface SCIMDeprovisioningSettings {
+	readonly stop_workspaces: boolean;
+	readonly transfer_owner_id?: string;
+	readonly mark_dormant: boolean;
+	readonly delete_after_days: number;
+}
+
 // From codersdk/deployment.go
 export interface SSHConfig {
 	readonly DeploymentName: string;
@@ -2587,6 +2723,8 @@ export interface Template {
 	readonly require_active_version: boolean;
 	readonly max_port_share_level: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow: boolean;
+	readonly session_recording: boolean;
+	readonly activity_bump_requires_user_activity: boolean;
 }
 
 // From codersdk/templates.go
@@ -2750,6 +2888,22 @@ export interface TemplateVersion {
 	readonly matched_provisioners?: MatchedProvisioners;
 }
 
+// From codersdk/templateversiondiff.go
+export interface TemplateVersionDiff {
+	readonly from_template_version_id: string;
+	readonly to_template_version_id: string;
+	readonly files: readonly TemplateVersionFileDiff[];
+	readonly rich_parameters: readonly TemplateVersionItemDiff[];
+	readonly variables: readonly TemplateVersionItemDiff[];
+	readonly presets: readonly TemplateVersionItemDiff[];
+	readonly workspace_tags: readonly TemplateVersionItemDiff[];
+}
+
+// From codersdk/templateversiondiff.go
+export type TemplateVersionDiffStatus = "added" | "modified" | "removed";
+
+export const TemplateVersionDiffStatuses: TemplateVersionDiffStatus[] = ["added", "modified", "removed"];
+
 // From codersdk/templateversions.go
 export interface TemplateVersionExternalAuth {
 	readonly id: string;
@@ -2761,6 +2915,28 @@ export interface TemplateVersionExternalAuth {
 	readonly optional?: boolean;
 }
 
+// From codersdk/templateversiondiff.go
+export interface TemplateVersionFieldChange {
+	readonly field: string;
+	readonly old: string;
+	readonly new: string;
+}
+
+// From codersdk/templateversiondiff.go
+export interface TemplateVersionFileDiff {
+	readonly path: string;
+	readonly status: TemplateVersionDiffStatus;
+	readonly binary: boolean;
+	readonly diff?: string;
+}
+
+// From codersdk/templateversiondiff.go
+export interface TemplateVersionItemDiff {
+	readonly name: string;
+	readonly status: TemplateVersionDiffStatus;
+	readonly changes?: readonly TemplateVersionFieldChange[];
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameter {
 	readonly name: string;
@@ -2958,6 +3134,8 @@ export interface UpdateTemplateMeta {
 	readonly disable_everyone_group_access: boolean;
 	readonly max_port_share_level?: WorkspaceAgentPortShareLevel;
 	readonly use_classic_parameter_flow?: boolean;
+	readonly session_recording?: boolean;
+	readonly activity_bump_requires_user_activity?: boolean;
 }
 
 // From codersdk/users.go
@@ -2969,6 +3147,8 @@ export interface UpdateUserAppearanceSettingsRequest {
 // From codersdk/notifications.go
 export interface UpdateUserNotificationPreferences {
 	readonly template_disabled_map: Record<string, boolean>;
+	readonly template_method_map?: Record<string, string>;
+	readonly template_digest_map?: Record<string, boolean>;
 }
 
 // From codersdk/users.go
@@ -2988,6 +3168,12 @@ export interface UpdateUserQuietHoursScheduleRequest {
 	readonly schedule: string;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+	readonly user_roles?: Record<string, WorkspaceRole>;
+	readonly group_roles?: Record<string, WorkspaceRole>;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
 	readonly automatic_updates: AutomaticUpdates;
@@ -3030,6 +3216,7 @@ export interface UpsertWorkspaceAgentPortShareRequest {
 	readonly port: number;
 	readonly share_level: WorkspaceAgentPortShareLevel;
 	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly expires_at?: string;
 }
 
 // From codersdk/workspaces.go
@@ -3253,6 +3440,12 @@ export interface Workspace {
 	readonly next_start_at: string | null;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+	readonly users: readonly WorkspaceUser[];
+	readonly groups: readonly WorkspaceGroup[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgent {
 	readonly id: string;
@@ -3320,6 +3513,16 @@ export interface WorkspaceAgentDevcontainer {
 	readonly running: boolean;
 	readonly dirty: boolean;
 	readonly container?: WorkspaceAgentContainer;
+	readonly forwarded_ports?: readonly WorkspaceAgentDevcontainerPort[];
+	readonly extensions?: readonly string[];
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentDevcontainerPort {
+	readonly port: number;
+	readonly label?: string;
+	readonly protocol?: string;
+	readonly forwarded: boolean;
 }
 
 // From codersdk/workspaceagents.go
@@ -3360,6 +3563,7 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentListContainersResponse {
 	readonly containers: readonly WorkspaceAgentContainer[];
+	readonly devcontainers?: readonly WorkspaceAgentDevcontainer[];
 	readonly warnings?: readonly string[];
 }
 
@@ -3423,6 +3627,7 @@ export interface WorkspaceAgentPortShare {
 	readonly port: number;
 	readonly share_level: WorkspaceAgentPortShareLevel;
 	readonly protocol: WorkspaceAgentPortShareProtocol;
+	readonly expires_at?: string;
 }
 
 // From codersdk/workspaceagentportshare.go
@@ -3457,6 +3662,17 @@ export interface WorkspaceAgentScript {
 	readonly start_blocks_login: boolean;
 	readonly timeout: number;
 	readonly display_name: string;
+	readonly depends_on: readonly string[];
+	readonly readiness_probe?: WorkspaceAgentScriptReadinessProbe;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentScriptReadinessProbe {
+	readonly command?: string;
+	readonly tcp?: string;
+	readonly http?: string;
+	readonly interval: number;
+	readonly timeout: number;
 }
 
 // From codersdk/workspaceagents.go
@@ -3617,6 +3833,11 @@ export interface WorkspaceFilter {
 	readonly q?: string;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceGroup extends Group {
+	readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceHealth {
 	readonly healthy: boolean;
@@ -3686,6 +3907,46 @@ export interface WorkspaceResourceMetadata {
 	readonly sensitive: boolean;
 }
 
+// From codersdk/workspaces.go
+export type WorkspaceRole = "admin" | "" | "use";
+
+export const WorkspaceRoles: WorkspaceRole[] = ["admin", "", "use"];
+
+// From codersdk/sessionrecordings.go
+export interface WorkspaceSessionRecording {
+	readonly id: string;
+	readonly organization_id: string;
+	readonly workspace_id: string;
+	readonly workspace_name: string;
+	readonly workspace_owner_id: string;
+	readonly agent_id: string;
+	readonly agent_name: string;
+	readonly connection_id: string;
+	readonly connection_type: string;
+	readonly started_at: string;
+	readonly ended_at: string;
+	readonly size: number;
+}
+
+// From codersdk/sessionrecordings.go
+export interface WorkspaceSessionRecordingsRequest extends Pagination {
+	readonly workspace_id?: string;
+	readonly connection_id?: string;
+}
+
+// From codersdk/workspacesnapshots.go
+export interface WorkspaceSnapshot {
+	readonly id: string;
+	readonly workspace_id: string;
+	readonly name: string;
+	readonly created_at: string;
+	readonly created_by: MinimalUser;
+	readonly build_number: number;
+	readonly template_version_id: string;
+	readonly template_version_name: string;
+	readonly parameters: readonly WorkspaceBuildParameter[];
+}
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
 	| "canceled"
@@ -3721,6 +3982,11 @@ export const WorkspaceTransitions: WorkspaceTransition[] = [
 	"stop",
 ];
 
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends MinimalUser {
+	readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
 	readonly q?: string;
@@ -4,6 +4,7 @@ import {
 	MockWorkspaceAgent,
 	MockWorkspaceAgentContainer,
 	MockWorkspaceAgentContainerPorts,
+	MockWorkspaceAgentDevcontainer,
 } from "testHelpers/entities";
 import { AgentDevcontainerCard } from "./AgentDevcontainerCard";
 
@@ -31,3 +32,17 @@ export const WithPorts: Story = {
 		},
 	},
 };
+
+export const WithDevcontainerConfig: Story = {
+	args: {
+		devcontainer: {
+			...MockWorkspaceAgentDevcontainer,
+			forwarded_ports: [
+				{ port: 3000, label: "Web", forwarded: true },
+				{ port: 8443, label: "API", protocol: "https", forwarded: true },
+				{ port: 5432, forwarded: true },
+			],
+			extensions: ["golang.go", "dbaeumer.vscode-eslint"],
+		},
+	},
+};
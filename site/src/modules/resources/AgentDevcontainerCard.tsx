@@ -2,6 +2,7 @@ import type {
 	Workspace,
 	WorkspaceAgent,
 	WorkspaceAgentContainer,
+	WorkspaceAgentDevcontainer,
 } from "api/typesGenerated";
 import {
 	Tooltip,
@@ -20,6 +21,7 @@ import { VSCodeDevContainerButton } from "./VSCodeDevContainerButton/VSCodeDevCo
 type AgentDevcontainerCardProps = {
 	agent: WorkspaceAgent;
 	container: WorkspaceAgentContainer;
+	devcontainer?: WorkspaceAgentDevcontainer;
 	workspace: Workspace;
 	wildcardHostname: string;
 };
@@ -27,11 +29,13 @@ type AgentDevcontainerCardProps = {
 export const AgentDevcontainerCard: FC<AgentDevcontainerCardProps> = ({
 	agent,
 	container,
+	devcontainer,
 	workspace,
 	wildcardHostname,
 }) => {
 	const folderPath = container.labels["devcontainer.local_folder"];
 	const containerFolder = container.volumes[folderPath];
+	const extensions = devcontainer?.extensions ?? [];
 
 	return (
 		<section
@@ -102,6 +106,12 @@ export const AgentDevcontainerCard: FC<AgentDevcontainerCardProps> = ({
 						);
 					})}
 			</div>
+
+			{extensions.length > 0 && (
+				<p className="m-0 mt-4 text-xs text-content-secondary">
+					VS Code extensions: {extensions.join(", ")}
+				</p>
+			)}
 		</section>
 	);
 };
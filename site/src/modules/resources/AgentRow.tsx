@@ -144,7 +144,7 @@ export const AgentRow: FC<AgentRowProps> = ({
 		setBottomOfLogs(distanceFromBottom < AGENT_LOG_LINE_HEIGHT);
 	}, []);
 
-	const { data: containers } = useQuery({
+	const { data: containersData } = useQuery({
 		queryKey: ["agents", agent.id, "containers"],
 		queryFn: () =>
 			// Only return devcontainers
@@ -153,7 +153,10 @@ export const AgentRow: FC<AgentRowProps> = ({
 				"devcontainer.local_folder=",
 			]),
 		enabled: agent.status === "connected",
-		select: (res) => res.containers.filter((c) => c.status === "running"),
+		select: (res) => ({
+			containers: res.containers.filter((c) => c.status === "running"),
+			devcontainers: res.devcontainers ?? [],
+		}),
 		// TODO: Implement a websocket connection to get updates on containers
 		// without having to poll.
 		refetchInterval: (_, query) => {
@@ -163,6 +166,7 @@ export const AgentRow: FC<AgentRowProps> = ({
 				: 10_000;
 		},
 	});
+	const containers = containersData?.containers;
 
 	return (
 		<Stack
@@ -283,6 +287,9 @@ export const AgentRow: FC<AgentRowProps> = ({
 								<AgentDevcontainerCard
 									key={container.id}
 									container={container}
+									devcontainer={containersData?.devcontainers.find(
+										(dc) => dc.container?.id === container.id,
+									)}
 									workspace={workspace}
 									wildcardHostname={proxy.preferredWildcardHostname}
 									agent={agent}
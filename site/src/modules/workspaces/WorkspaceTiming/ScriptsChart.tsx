@@ -10,7 +10,11 @@ import {
 	ChartSearch,
 	ChartToolbar,
 } from "./Chart/Chart";
-import { Tooltip, TooltipTitle } from "./Chart/Tooltip";
+import {
+	Tooltip,
+	TooltipShortDescription,
+	TooltipTitle,
+} from "./Chart/Tooltip";
 import { XAxis, XAxisRow, XAxisSection } from "./Chart/XAxis";
 import {
 	YAxis,
@@ -34,6 +38,9 @@ type ScriptTiming = {
 	status: string;
 	exitCode: number;
 	range: TimeRange;
+	// waitTime is the time in milliseconds the script waited for the scripts
+	// it depends on before it started.
+	waitTime: number;
 };
 
 export type ScriptsChartProps = {
@@ -105,9 +112,16 @@ export const ScriptsChart: FC<ScriptsChartProps> = ({
 								>
 									<Tooltip
 										title={
-											<TooltipTitle>
-												Script exited with <strong>code {t.exitCode}</strong>
-											</TooltipTitle>
+											<>
+												<TooltipTitle>
+													Script exited with <strong>code {t.exitCode}</strong>
+												</TooltipTitle>
+												{t.waitTime > 0 && (
+													<TooltipShortDescription>
+														Waited {formatTime(t.waitTime)} for dependencies.
+													</TooltipShortDescription>
+												)}
+											</>
 										}
 									>
 										<Bar
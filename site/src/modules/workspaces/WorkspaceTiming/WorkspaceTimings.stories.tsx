@@ -172,6 +172,7 @@ export const MissedAction: Story = {
 				display_name: "Startup Script",
 				ended_at: "2025-03-12T18:16:44.771508Z",
 				exit_code: 0,
+				queued_at: "2025-03-12T18:15:13.847336Z",
 				stage: "start",
 				started_at: "2025-03-12T18:15:13.847336Z",
 				status: "ok",
@@ -191,6 +191,9 @@ export const WorkspaceTimings: FC<WorkspaceTimingsProps> = ({
 													name: t.display_name,
 													status: t.status,
 													exitCode: t.exit_code,
+													waitTime:
+														new Date(t.started_at).getTime() -
+														new Date(t.queued_at).getTime(),
 												};
 											})}
 										stage={view.stage}
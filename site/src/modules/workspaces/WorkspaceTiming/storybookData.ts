@@ -349,6 +349,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 	],
 	agent_script_timings: [
 		{
+			queued_at: "2024-10-14T11:30:56.650536Z",
 			started_at: "2024-10-14T11:30:56.650536Z",
 			ended_at: "2024-10-14T11:31:10.852776Z",
 			exit_code: 0,
@@ -359,6 +360,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650915Z",
 			started_at: "2024-10-14T11:30:56.650915Z",
 			ended_at: "2024-10-14T11:30:56.655558Z",
 			exit_code: 0,
@@ -369,6 +371,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650715Z",
 			started_at: "2024-10-14T11:30:56.650715Z",
 			ended_at: "2024-10-14T11:30:56.657682Z",
 			exit_code: 0,
@@ -379,6 +382,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650512Z",
 			started_at: "2024-10-14T11:30:56.650512Z",
 			ended_at: "2024-10-14T11:30:56.657981Z",
 			exit_code: 0,
@@ -389,6 +393,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650659Z",
 			started_at: "2024-10-14T11:30:56.650659Z",
 			ended_at: "2024-10-14T11:30:57.318177Z",
 			exit_code: 0,
@@ -399,6 +404,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650666Z",
 			started_at: "2024-10-14T11:30:56.650666Z",
 			ended_at: "2024-10-14T11:30:58.350832Z",
 			exit_code: 0,
@@ -409,6 +415,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.652425Z",
 			started_at: "2024-10-14T11:30:56.652425Z",
 			ended_at: "2024-10-14T11:31:26.229407Z",
 			exit_code: 0,
@@ -419,6 +426,7 @@ export const WorkspaceTimingsResponse: WorkspaceBuildTimings = {
 			workspace_agent_name: "dev",
 		},
 		{
+			queued_at: "2024-10-14T11:30:56.650423Z",
 			started_at: "2024-10-14T11:30:56.650423Z",
 			ended_at: "2024-10-14T11:30:56.657224Z",
 			exit_code: 0,
@@ -74,6 +74,8 @@ export const MockToken: TypesGen.APIKeyWithOwner = {
 	scope: "all",
 	lifetime_seconds: 2592000,
 	token_name: "token-one",
+	scope_permissions: [],
+	scope_allow_list: [],
 	username: "admin",
 };
 
@@ -90,6 +92,8 @@ export const MockTokens: TypesGen.APIKeyWithOwner[] = [
 		scope: "all",
 		lifetime_seconds: 2592000,
 		token_name: "token-two",
+		scope_permissions: [],
+		scope_allow_list: [],
 		username: "admin",
 	},
 ];
@@ -825,6 +829,8 @@ export const MockTemplate: TypesGen.Template = {
 	deprecation_message: "",
 	max_port_share_level: "public",
 	use_classic_parameter_flow: false,
+	session_recording: false,
+	activity_bump_requires_user_activity: false,
 };
 
 const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -927,6 +933,7 @@ const MockWorkspaceAgentScript: TypesGen.WorkspaceAgentScript = {
 	start_blocks_login: false,
 	timeout: 0,
 	display_name: "Say Hello",
+	depends_on: [],
 };
 
 export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
@@ -4186,36 +4193,43 @@ export const MockNotificationPreferences: TypesGen.NotificationPreference[] = [
 	{
 		id: "f44d9314-ad03-4bc8-95d0-5cad491da6b6",
 		disabled: false,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "381df2a9-c0c0-4749-420f-80a9280c66f9",
 		disabled: true,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "f517da0b-cdc9-410f-ab89-a86107c420ed",
 		disabled: false,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "c34a0c09-0704-4cac-bd1c-0c0146811c2b",
 		disabled: false,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "0ea69165-ec14-4314-91f1-69566ac3c5a0",
 		disabled: false,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "51ce2fdf-c9ca-4be1-8d70-628674f9bc42",
 		disabled: false,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 	{
 		id: "4e19c0ac-94e1-4532-9515-d1801aa283b2",
 		disabled: true,
+		digest: false,
 		updated_at: "2024-08-06T11:58:37.755053Z",
 	},
 ];
@@ -4233,6 +4247,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "webhook",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: true,
 	},
 	{
 		id: "f517da0b-cdc9-410f-ab89-a86107c420ed",
@@ -4246,6 +4261,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: false,
 	},
 	{
 		id: "f44d9314-ad03-4bc8-95d0-5cad491da6b6",
@@ -4259,6 +4275,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: false,
 	},
 	{
 		id: "4e19c0ac-94e1-4532-9515-d1801aa283b2",
@@ -4272,6 +4289,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: false,
 	},
 	{
 		id: "0ea69165-ec14-4314-91f1-69566ac3c5a0",
@@ -4285,6 +4303,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: true,
 	},
 	{
 		id: "c34a0c09-0704-4cac-bd1c-0c0146811c2b",
@@ -4298,6 +4317,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "smtp",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: true,
 	},
 	{
 		id: "51ce2fdf-c9ca-4be1-8d70-628674f9bc42",
@@ -4311,6 +4331,7 @@ export const MockNotificationTemplates: TypesGen.NotificationTemplate[] = [
 		method: "webhook",
 		kind: "system",
 		enabled_by_default: true,
+		digestible: true,
 	},
 ];
 
@@ -4385,3 +4406,14 @@ export const MockWorkspaceAgentContainer: TypesGen.WorkspaceAgentContainer = {
 		"/mnt/volume1": "/volume1",
 	},
 };
+
+export const MockWorkspaceAgentDevcontainer: TypesGen.WorkspaceAgentDevcontainer =
+	{
+		id: "d1c2b3a4-0000-4000-8000-000000000001",
+		name: "project",
+		workspace_folder: "/home/coder/project",
+		config_path: "/home/coder/project/.devcontainer/devcontainer.json",
+		running: true,
+		dirty: false,
+		container: MockWorkspaceAgentContainer,
+	};
@@ -34,6 +34,7 @@ import (
 	tslogger "tailscale.com/types/logger"
 	"tailscale.com/types/netlogtype"
 	"tailscale.com/types/netmap"
+	"tailscale.com/types/nettype"
 	"tailscale.com/util/dnsname"
 	"tailscale.com/wgengine"
 	"tailscale.com/wgengine/capture"
@@ -123,6 +124,11 @@ type Options struct {
 	// DNSMatchDomain is the DNS suffix to use as a match domain. Only relevant for TUN connections that configure the
 	// OS DNS resolver.
 	DNSMatchDomain string
+	// ForwardFilter is optional. It is called for incoming TCP connections
+	// and UDP flows to ports that the Conn is not listening on, which are
+	// otherwise forwarded to localhost, and rejects them if it returns false.
+	// The network is "tcp" or "udp".
+	ForwardFilter func(network string, src, dst netip.AddrPort) bool
 }
 
 // TelemetrySink allows tailnet.Conn to send network telemetry to the Coder
@@ -325,6 +331,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 		createdAt:       time.Now(),
 		watchCtx:        ctx,
 		watchCancel:     ctxCancel,
+		forwardFilter:   options.ForwardFilter,
 	}
 	defer func() {
 		if err != nil {
@@ -357,6 +364,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	server.magicConn.SetDERPForcedWebsocketCallback(nodeUp.setDERPForcedWebsocket)
 
 	netStack.GetTCPHandlerForFlow = server.forwardTCP
+	netStack.GetUDPHandlerForFlow = server.forwardUDP
 
 	err = netStack.Start(nil)
 	if err != nil {
@@ -440,6 +448,7 @@ type Conn struct {
 	wireguardEngine  wgengine.Engine
 	dnsConfigurator  dns.OSConfigurator
 	listeners        map[listenKey]*listener
+	forwardFilter    func(network string, src, dst netip.AddrPort) bool
 	clientType       proto.TelemetryEvent_ClientType
 	createdAt        time.Time
 
@@ -761,6 +770,12 @@ func (c *Conn) forwardTCP(src, dst netip.AddrPort) (handler func(net.Conn), opts
 	ln, ok := c.listeners[listenKey{"tcp", "", fmt.Sprint(dst.Port())}]
 	c.mutex.Unlock()
 	if !ok {
+		if c.forwardFilter != nil && !c.forwardFilter("tcp", src, dst) {
+			logger.Info(context.Background(), "rejected forwarded connection")
+			// Intercepting the flow without a handler resets the
+			// connection.
+			return nil, nil, true
+		}
 		return nil, nil, false
 	}
 	// See: https://github.com/tailscale/tailscale/blob/c7cea825aea39a00aca71ea02bab7266afc03e7c/wgengine/netstack/netstack.go#L888
@@ -787,6 +802,16 @@ func (c *Conn) forwardTCP(src, dst netip.AddrPort) (handler func(net.Conn), opts
 	}, opts, true
 }
 
+func (c *Conn) forwardUDP(src, dst netip.AddrPort) (handler func(nettype.ConnPacketConn), intercept bool) {
+	if c.forwardFilter != nil && !c.forwardFilter("udp", src, dst) {
+		c.logger.Named("udp").Info(context.Background(), "rejected forwarded flow",
+			slog.F("src", src.String()), slog.F("dst", dst.String()))
+		// Intercepting the flow without a handler drops it.
+		return nil, true
+	}
+	return nil, false
+}
+
 // SetConnStatsCallback sets a callback to be called after maxPeriod or
 // maxConns, whichever comes first. Multiple calls overwrites the callback.
 func (c *Conn) SetConnStatsCallback(maxPeriod time.Duration, maxConns int, dump func(start, end time.Time, virtual, physical map[netlogtype.Connection]netlogtype.Counts)) {
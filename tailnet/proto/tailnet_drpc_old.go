@@ -40,3 +40,9 @@ type DRPCTailnetClient23 interface {
 type DRPCTailnetClient24 interface {
 	DRPCTailnetClient23
 }
+
+// DRPCTailnetClient25 is the Tailnet API at v2.5. It is functionally identical to 2.4, because the
+// change was to the Agent API (UploadSessionRecording and UpdateDevcontainerApps methods).
+type DRPCTailnetClient25 interface {
+	DRPCTailnetClient24
+}
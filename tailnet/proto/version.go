@@ -45,9 +45,24 @@ import (
 //     PushResourcesMonitoringUsage RPCs on the Agent API.
 //   - Added support for reporting connection events for auditing via the
 //     ReportConnection RPC on the Agent API.
+//
+// API v2.5:
+//   - Added support for uploading session recordings via the
+//     UploadSessionRecording RPC on the Agent API.
+//   - Added the session_recording field to the Manifest on the Agent API.
+//   - Added support for registering the labeled ports of devcontainers as
+//     workspace apps via the UpdateDevcontainerApps RPC on the Agent API.
+//   - Added the cpu, network, file_descriptors and processes monitors to
+//     GetResourcesMonitoringConfiguration and PushResourcesMonitoringUsage
+//     on the Agent API.
+//   - Added the depends_on and readiness_probe fields to WorkspaceAgentScript
+//     and the queued field to Timing on the Agent API.
+//   - Added the time_since_last_activity field to Stats on the Agent API.
+//   - Added the port_forwarding_policy field to the Manifest and the
+//     PORT_FORWARDING connection type to ReportConnection on the Agent API.
 const (
 	CurrentMajor = 2
-	CurrentMinor = 4
+	CurrentMinor = 5
 )
 
 var CurrentVersion = apiversion.New(CurrentMajor, CurrentMinor)
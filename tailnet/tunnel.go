@@ -92,6 +92,9 @@ func (a AgentCoordinateeAuth) Authorize(_ context.Context, req *proto.Coordinate
 	return nil
 }
 
+// ClientUserCoordinateeAuth authorizes user clients to open tunnels to
+// agents. Whether a tunnel is allowed is left to the TunnelAuthorizer, so users
+// a workspace is shared with can reach its agents as well as the owner.
 type ClientUserCoordinateeAuth struct {
 	Auth TunnelAuthorizer
 }
@@ -0,0 +1,223 @@
+//go:build linux
+
+package vpn
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/dns"
+	"tailscale.com/util/dnsname"
+
+	"cdr.dev/slog"
+)
+
+const (
+	resolvedObject    = "org.freedesktop.resolve1"
+	resolvedPath      = dbus.ObjectPath("/org/freedesktop/resolve1")
+	resolvedInterface = "org.freedesktop.resolve1.Manager"
+
+	resolvedCallTimeout = 10 * time.Second
+)
+
+// resolvedBus calls methods on the systemd-resolved manager.
+type resolvedBus interface {
+	Call(ctx context.Context, method string, args ...any) error
+	Close() error
+}
+
+type dbusResolvedBus struct {
+	conn *dbus.Conn
+	obj  dbus.BusObject
+}
+
+func (b dbusResolvedBus) Call(ctx context.Context, method string, args ...any) error {
+	return b.obj.CallWithContext(ctx, resolvedInterface+"."+method, 0, args...).Store()
+}
+
+func (b dbusResolvedBus) Close() error {
+	return b.conn.Close()
+}
+
+// resolvedLinkNameserver and resolvedLinkDomain match the DBus signatures of
+// the arguments to SetLinkDNS and SetLinkDomains.
+type resolvedLinkNameserver struct {
+	Family  int32
+	Address []byte
+}
+
+type resolvedLinkDomain struct {
+	Domain      string
+	RoutingOnly bool
+}
+
+// resolvedConfigurator configures the DNS settings of the TUN device with
+// systemd-resolved. Nameservers are only used for the match domains of the
+// config, so queries for other names keep going to the host's resolvers.
+type resolvedConfigurator struct {
+	logger  slog.Logger
+	bus     resolvedBus
+	ifIndex int32
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ dns.OSConfigurator = (*resolvedConfigurator)(nil)
+
+// newResolvedConfigurator connects to systemd-resolved on the system bus to
+// configure the link with the given name.
+func newResolvedConfigurator(logger slog.Logger, linkName string) (*resolvedConfigurator, error) {
+	iface, err := net.InterfaceByName(linkName)
+	if err != nil {
+		return nil, xerrors.Errorf("find interface %q: %w", linkName, err)
+	}
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, xerrors.Errorf("connect to system bus: %w", err)
+	}
+	obj := conn.Object(resolvedObject, resolvedPath)
+	ctx, cancel := context.WithTimeout(context.Background(), resolvedCallTimeout)
+	defer cancel()
+	err = obj.CallWithContext(ctx, "org.freedesktop.DBus.Peer.Ping", 0).Store()
+	if err != nil {
+		_ = conn.Close()
+		return nil, xerrors.Errorf("systemd-resolved is not available: %w", err)
+	}
+	// #nosec G115 - Interface indexes are small positive integers.
+	return newResolvedConfiguratorWithBus(logger, dbusResolvedBus{conn: conn, obj: obj}, int32(iface.Index)), nil
+}
+
+func newResolvedConfiguratorWithBus(logger slog.Logger, bus resolvedBus, ifIndex int32) *resolvedConfigurator {
+	return &resolvedConfigurator{
+		logger:  logger,
+		bus:     bus,
+		ifIndex: ifIndex,
+	}
+}
+
+// SetDNS sets the nameservers and domains of the link. A zero config reverts
+// the link to the defaults of resolved.
+func (r *resolvedConfigurator) SetDNS(cfg dns.OSConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return xerrors.New("configurator is closed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolvedCallTimeout)
+	defer cancel()
+
+	if cfg.IsZero() {
+		return r.revert(ctx)
+	}
+
+	nameservers := make([]resolvedLinkNameserver, 0, len(cfg.Nameservers))
+	for _, addr := range cfg.Nameservers {
+		family := int32(unix.AF_INET6)
+		if addr.Is4() {
+			family = unix.AF_INET
+		}
+		nameservers = append(nameservers, resolvedLinkNameserver{
+			Family:  family,
+			Address: addr.AsSlice(),
+		})
+	}
+	err := r.bus.Call(ctx, "SetLinkDNS", r.ifIndex, nameservers)
+	if err != nil {
+		return xerrors.Errorf("set link dns: %w", err)
+	}
+
+	err = r.bus.Call(ctx, "SetLinkDomains", r.ifIndex, resolvedLinkDomains(cfg))
+	if err != nil {
+		return xerrors.Errorf("set link domains: %w", err)
+	}
+
+	// Only use the link for names outside of the match domains if it was
+	// asked to be the primary resolver.
+	err = r.bus.Call(ctx, "SetLinkDefaultRoute", r.ifIndex, len(cfg.MatchDomains) == 0)
+	if err != nil {
+		// Older versions of resolved lack this method, but behave as if the
+		// link is not a default route when it has routing-only domains.
+		r.logger.Warn(ctx, "failed to set link default route", slog.Error(err))
+	}
+
+	// Best effort: multicast protocols and DNSSEC make no sense for the
+	// tunnel's resolver.
+	for _, method := range []string{"SetLinkLLMNR", "SetLinkMulticastDNS", "SetLinkDNSSEC"} {
+		err = r.bus.Call(ctx, method, r.ifIndex, "no")
+		if err != nil {
+			r.logger.Debug(ctx, "failed to configure link", slog.F("method", method), slog.Error(err))
+		}
+	}
+	err = r.bus.Call(ctx, "FlushCaches")
+	if err != nil {
+		r.logger.Debug(ctx, "failed to flush resolved caches", slog.Error(err))
+	}
+	return nil
+}
+
+func (*resolvedConfigurator) SupportsSplitDNS() bool {
+	return true
+}
+
+func (*resolvedConfigurator) GetBaseConfig() (dns.OSConfig, error) {
+	return dns.OSConfig{}, dns.ErrGetBaseConfigNotSupported
+}
+
+// Close reverts the DNS settings of the link and disconnects from the bus.
+func (r *resolvedConfigurator) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	ctx, cancel := context.WithTimeout(context.Background(), resolvedCallTimeout)
+	defer cancel()
+	err := r.revert(ctx)
+	closeErr := r.bus.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (r *resolvedConfigurator) revert(ctx context.Context) error {
+	err := r.bus.Call(ctx, "RevertLink", r.ifIndex)
+	if err != nil {
+		return xerrors.Errorf("revert link: %w", err)
+	}
+	return nil
+}
+
+// resolvedLinkDomains returns the domains of the link. Search domains are
+// also used for routing by resolved, while match domains are routing-only.
+// If there are no match domains, the root domain routes every query to the
+// link.
+func resolvedLinkDomains(cfg dns.OSConfig) []resolvedLinkDomain {
+	domains := make([]resolvedLinkDomain, 0, len(cfg.SearchDomains)+len(cfg.MatchDomains)+1)
+	seen := make(map[dnsname.FQDN]struct{})
+	for _, domain := range cfg.SearchDomains {
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		seen[domain] = struct{}{}
+		domains = append(domains, resolvedLinkDomain{Domain: domain.WithTrailingDot()})
+	}
+	for _, domain := range cfg.MatchDomains {
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		seen[domain] = struct{}{}
+		domains = append(domains, resolvedLinkDomain{Domain: domain.WithTrailingDot(), RoutingOnly: true})
+	}
+	if len(cfg.MatchDomains) == 0 && len(cfg.Nameservers) > 0 {
+		domains = append(domains, resolvedLinkDomain{Domain: ".", RoutingOnly: true})
+	}
+	return domains
+}
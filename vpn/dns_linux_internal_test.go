@@ -0,0 +1,154 @@
+//go:build linux
+
+package vpn
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/dns"
+	"tailscale.com/util/dnsname"
+
+	"cdr.dev/slog/sloggers/slogtest"
+)
+
+func TestResolvedConfigurator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SplitDNS", func(t *testing.T) {
+		t.Parallel()
+		bus := &fakeResolvedBus{}
+		r := newResolvedConfiguratorWithBus(slogtest.Make(t, nil), bus, 7)
+		require.True(t, r.SupportsSplitDNS())
+
+		err := r.SetDNS(dns.OSConfig{
+			Nameservers:   []netip.Addr{netip.MustParseAddr("fd60:627a:a42b::53"), netip.MustParseAddr("100.100.100.100")},
+			SearchDomains: []dnsname.FQDN{"coder."},
+			MatchDomains:  []dnsname.FQDN{"coder.", "example.coder."},
+		})
+		require.NoError(t, err)
+
+		calls := bus.callsByMethod()
+		require.Equal(t, []any{int32(7), []resolvedLinkNameserver{
+			{Family: unix.AF_INET6, Address: netip.MustParseAddr("fd60:627a:a42b::53").AsSlice()},
+			{Family: unix.AF_INET, Address: []byte{100, 100, 100, 100}},
+		}}, calls["SetLinkDNS"])
+		require.Equal(t, []any{int32(7), []resolvedLinkDomain{
+			{Domain: "coder.", RoutingOnly: false},
+			{Domain: "example.coder.", RoutingOnly: true},
+		}}, calls["SetLinkDomains"])
+		require.Equal(t, []any{int32(7), false}, calls["SetLinkDefaultRoute"])
+		require.Contains(t, calls, "FlushCaches")
+	})
+
+	t.Run("PrimaryResolver", func(t *testing.T) {
+		t.Parallel()
+		bus := &fakeResolvedBus{}
+		r := newResolvedConfiguratorWithBus(slogtest.Make(t, nil), bus, 7)
+
+		err := r.SetDNS(dns.OSConfig{
+			Nameservers: []netip.Addr{netip.MustParseAddr("100.100.100.100")},
+		})
+		require.NoError(t, err)
+
+		calls := bus.callsByMethod()
+		require.Equal(t, []any{int32(7), []resolvedLinkDomain{
+			{Domain: ".", RoutingOnly: true},
+		}}, calls["SetLinkDomains"])
+		require.Equal(t, []any{int32(7), true}, calls["SetLinkDefaultRoute"])
+	})
+
+	t.Run("BestEffortCalls", func(t *testing.T) {
+		t.Parallel()
+		bus := &fakeResolvedBus{fail: map[string]bool{
+			"SetLinkDefaultRoute": true,
+			"SetLinkDNSSEC":       true,
+			"FlushCaches":         true,
+		}}
+		r := newResolvedConfiguratorWithBus(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), bus, 7)
+
+		err := r.SetDNS(dns.OSConfig{
+			Nameservers:  []netip.Addr{netip.MustParseAddr("100.100.100.100")},
+			MatchDomains: []dnsname.FQDN{"coder."},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("RequiredCallFails", func(t *testing.T) {
+		t.Parallel()
+		bus := &fakeResolvedBus{fail: map[string]bool{"SetLinkDomains": true}}
+		r := newResolvedConfiguratorWithBus(slogtest.Make(t, nil), bus, 7)
+
+		err := r.SetDNS(dns.OSConfig{
+			Nameservers:  []netip.Addr{netip.MustParseAddr("100.100.100.100")},
+			MatchDomains: []dnsname.FQDN{"coder."},
+		})
+		require.ErrorContains(t, err, "set link domains")
+	})
+
+	t.Run("RevertAndClose", func(t *testing.T) {
+		t.Parallel()
+		bus := &fakeResolvedBus{}
+		r := newResolvedConfiguratorWithBus(slogtest.Make(t, nil), bus, 7)
+
+		err := r.SetDNS(dns.OSConfig{})
+		require.NoError(t, err)
+		require.Equal(t, []any{int32(7)}, bus.callsByMethod()["RevertLink"])
+
+		require.NoError(t, r.Close())
+		require.True(t, bus.closed)
+		require.Len(t, bus.calls, 2)
+		require.Equal(t, "RevertLink", bus.calls[1].method)
+
+		// Closing again is a no-op, and the configurator can't be used
+		// afterwards.
+		require.NoError(t, r.Close())
+		require.Len(t, bus.calls, 2)
+		require.Error(t, r.SetDNS(dns.OSConfig{}))
+	})
+}
+
+type fakeResolvedCall struct {
+	method string
+	args   []any
+}
+
+type fakeResolvedBus struct {
+	mu     sync.Mutex
+	fail   map[string]bool
+	calls  []fakeResolvedCall
+	closed bool
+}
+
+func (b *fakeResolvedBus) Call(_ context.Context, method string, args ...any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, fakeResolvedCall{method: method, args: args})
+	if b.fail[method] {
+		return xerrors.Errorf("%s failed", method)
+	}
+	return nil
+}
+
+func (b *fakeResolvedBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+// callsByMethod returns the arguments of the last call to each method.
+func (b *fakeResolvedBus) callsByMethod() map[string][]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	calls := make(map[string][]any)
+	for _, call := range b.calls {
+		calls[call.method] = call.args
+	}
+	return calls
+}
@@ -0,0 +1,170 @@
+//go:build linux
+
+package vpn
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"sync"
+
+	"github.com/tailscale/netlink"
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+	"tailscale.com/wgengine/router"
+
+	"cdr.dev/slog"
+)
+
+// netlinkRouter programs the addresses and routes of the TUN device directly
+// with netlink, rather than relaying them to a network extension like the
+// router used on macOS.
+//
+// Routes are added to the main routing table. LocalRoutes are not programmed,
+// as the routes of the networks the host is connected to are more specific
+// than the tunnel's routes and already take precedence over them.
+type netlinkRouter struct {
+	logger slog.Logger
+	nl     *netlink.Handle
+	link   netlink.Link
+
+	mu     sync.Mutex
+	addrs  map[netip.Prefix]struct{}
+	routes map[netip.Prefix]struct{}
+}
+
+var _ router.Router = (*netlinkRouter)(nil)
+
+// newNetlinkRouter returns a router for the link with the given name. The
+// router takes ownership of the netlink handle.
+func newNetlinkRouter(logger slog.Logger, nl *netlink.Handle, linkName string) (*netlinkRouter, error) {
+	link, err := nl.LinkByName(linkName)
+	if err != nil {
+		return nil, xerrors.Errorf("find link %q: %w", linkName, err)
+	}
+	return &netlinkRouter{
+		logger: logger,
+		nl:     nl,
+		link:   link,
+		addrs:  make(map[netip.Prefix]struct{}),
+		routes: make(map[netip.Prefix]struct{}),
+	}, nil
+}
+
+func (r *netlinkRouter) Up() error {
+	err := r.nl.LinkSetUp(r.link)
+	if err != nil {
+		return xerrors.Errorf("set link up: %w", err)
+	}
+	return nil
+}
+
+// Set applies the config to the link, adding any missing addresses and routes
+// and removing the ones that are no longer in the config. A nil config removes
+// everything.
+func (r *netlinkRouter) Set(cfg *router.Config) error {
+	if cfg == nil {
+		cfg = &router.Config{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cfg.NewMTU > 0 {
+		err := r.nl.LinkSetMTU(r.link, cfg.NewMTU)
+		if err != nil {
+			return xerrors.Errorf("set link mtu: %w", err)
+		}
+	}
+
+	wantAddrs := prefixSet(cfg.LocalAddrs)
+	for prefix := range r.addrs {
+		if _, ok := wantAddrs[prefix]; ok {
+			continue
+		}
+		err := r.nl.AddrDel(r.link, netlinkAddr(prefix))
+		if err != nil && !xerrors.Is(err, unix.EADDRNOTAVAIL) {
+			return xerrors.Errorf("delete address %s: %w", prefix, err)
+		}
+		delete(r.addrs, prefix)
+	}
+	for prefix := range wantAddrs {
+		if _, ok := r.addrs[prefix]; ok {
+			continue
+		}
+		err := r.nl.AddrReplace(r.link, netlinkAddr(prefix))
+		if err != nil {
+			return xerrors.Errorf("add address %s: %w", prefix, err)
+		}
+		r.addrs[prefix] = struct{}{}
+	}
+
+	wantRoutes := prefixSet(cfg.Routes)
+	for prefix := range r.routes {
+		if _, ok := wantRoutes[prefix]; ok {
+			continue
+		}
+		err := r.nl.RouteDel(r.netlinkRoute(prefix))
+		if err != nil && !xerrors.Is(err, unix.ESRCH) {
+			return xerrors.Errorf("delete route %s: %w", prefix, err)
+		}
+		delete(r.routes, prefix)
+	}
+	for prefix := range wantRoutes {
+		if _, ok := r.routes[prefix]; ok {
+			continue
+		}
+		err := r.nl.RouteReplace(r.netlinkRoute(prefix))
+		if err != nil {
+			return xerrors.Errorf("add route %s: %w", prefix, err)
+		}
+		r.routes[prefix] = struct{}{}
+	}
+
+	if len(cfg.LocalRoutes) > 0 {
+		r.logger.Debug(context.Background(), "ignoring local routes", slog.F("local_routes", cfg.LocalRoutes))
+	}
+	return nil
+}
+
+// Close removes the addresses and routes added by the router. They would be
+// removed with the TUN device anyway, but the link may outlive the router.
+func (r *netlinkRouter) Close() error {
+	err := r.Set(nil)
+	r.nl.Delete()
+	return err
+}
+
+func (r *netlinkRouter) netlinkRoute(prefix netip.Prefix) *netlink.Route {
+	return &netlink.Route{
+		LinkIndex: r.link.Attrs().Index,
+		Dst:       prefixIPNet(prefix.Masked()),
+		Protocol:  unix.RTPROT_STATIC,
+		Table:     unix.RT_TABLE_MAIN,
+		Type:      unix.RTN_UNICAST,
+	}
+}
+
+func netlinkAddr(prefix netip.Prefix) *netlink.Addr {
+	addr := &netlink.Addr{IPNet: prefixIPNet(prefix)}
+	if prefix.Addr().Is6() {
+		// Duplicate address detection delays the address becoming usable
+		// and is pointless on a point-to-point tunnel.
+		addr.Flags = unix.IFA_F_NODAD
+	}
+	return addr
+}
+
+func prefixIPNet(prefix netip.Prefix) *net.IPNet {
+	return &net.IPNet{
+		IP:   prefix.Addr().AsSlice(),
+		Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+	}
+}
+
+func prefixSet(prefixes []netip.Prefix) map[netip.Prefix]struct{} {
+	set := make(map[netip.Prefix]struct{}, len(prefixes))
+	for _, prefix := range prefixes {
+		set[prefix] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,152 @@
+//go:build linux
+
+package vpn
+
+import (
+	"net/netip"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tailscale/netlink"
+	"github.com/tailscale/wireguard-go/tun"
+	"golang.org/x/sys/unix"
+	"tailscale.com/wgengine/router"
+
+	"cdr.dev/slog/sloggers/slogtest"
+)
+
+func TestNetlinkRouter(t *testing.T) {
+	t.Parallel()
+
+	// The router owns its handle, so the test lists links with another.
+	routerNL, nl := netlinkHandlesInNewNetns(t)
+
+	logger := slogtest.Make(t, nil)
+	r, err := newNetlinkRouter(logger, routerNL, tunName)
+	require.NoError(t, err)
+	link := r.link
+	require.NoError(t, r.Up())
+
+	var (
+		v4Addr  = netip.MustParsePrefix("100.64.0.1/32")
+		v6Addr  = netip.MustParsePrefix("fd60:627a:a42b::1/128")
+		v4Route = netip.MustParsePrefix("100.64.0.0/10")
+		v6Route = netip.MustParsePrefix("fd60:627a:a42b::/48")
+	)
+	err = r.Set(&router.Config{
+		LocalAddrs: []netip.Prefix{v4Addr, v6Addr},
+		Routes:     []netip.Prefix{v4Route, v6Route},
+		NewMTU:     1280,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []netip.Prefix{v4Addr, v6Addr}, linkAddrs(t, nl, link))
+	require.Subset(t, linkRoutes(t, nl, link), []netip.Prefix{v4Route, v6Route})
+	updated, err := nl.LinkByName(tunName)
+	require.NoError(t, err)
+	require.Equal(t, 1280, updated.Attrs().MTU)
+
+	// Setting the same config again is a no-op.
+	err = r.Set(&router.Config{
+		LocalAddrs: []netip.Prefix{v4Addr, v6Addr},
+		Routes:     []netip.Prefix{v4Route, v6Route},
+	})
+	require.NoError(t, err)
+
+	// Removed addresses and routes are deleted.
+	err = r.Set(&router.Config{
+		LocalAddrs: []netip.Prefix{v6Addr},
+		Routes:     []netip.Prefix{v6Route},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []netip.Prefix{v6Addr}, linkAddrs(t, nl, link))
+	routes := linkRoutes(t, nl, link)
+	require.Contains(t, routes, v6Route)
+	require.NotContains(t, routes, v4Route)
+
+	require.NoError(t, r.Close())
+	require.Empty(t, linkAddrs(t, nl, link))
+	require.NotContains(t, linkRoutes(t, nl, link), v6Route)
+}
+
+// netlinkHandlesInNewNetns returns two netlink handles in a new network
+// namespace containing a TUN device named tunName, so that tests can
+// configure it without affecting the host.
+func netlinkHandlesInNewNetns(t *testing.T) (*netlink.Handle, *netlink.Handle) {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("creating a network namespace requires root")
+	}
+
+	type result struct {
+		nls [2]*netlink.Handle
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		// The thread is never unlocked, so it exits with the goroutine
+		// instead of returning to the scheduler in the new namespace.
+		runtime.LockOSThread()
+		err := unix.Unshare(unix.CLONE_NEWNET)
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		dev, err := tun.CreateTUN(tunName, 1500)
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		t.Cleanup(func() { _ = dev.Close() })
+		// Netlink sockets stay in the namespace they were created in.
+		var res result
+		for i := range res.nls {
+			res.nls[i], res.err = netlink.NewHandle()
+			if res.err != nil {
+				break
+			}
+		}
+		resCh <- res
+	}()
+	res := <-resCh
+	if res.err != nil {
+		t.Skipf("create network namespace: %v", res.err)
+	}
+	t.Cleanup(res.nls[1].Delete)
+	return res.nls[0], res.nls[1]
+}
+
+func linkAddrs(t *testing.T, nl *netlink.Handle, link netlink.Link) []netip.Prefix {
+	t.Helper()
+	addrs, err := nl.AddrList(link, netlink.FAMILY_ALL)
+	require.NoError(t, err)
+	prefixes := make([]netip.Prefix, 0, len(addrs))
+	for _, addr := range addrs {
+		ip, ok := netip.AddrFromSlice(addr.IP)
+		require.True(t, ok)
+		if ip.Is6() && ip.IsLinkLocalUnicast() {
+			continue
+		}
+		ones, _ := addr.Mask.Size()
+		prefixes = append(prefixes, netip.PrefixFrom(ip.Unmap(), ones))
+	}
+	return prefixes
+}
+
+func linkRoutes(t *testing.T, nl *netlink.Handle, link netlink.Link) []netip.Prefix {
+	t.Helper()
+	routes, err := nl.RouteList(link, netlink.FAMILY_ALL)
+	require.NoError(t, err)
+	prefixes := make([]netip.Prefix, 0, len(routes))
+	for _, route := range routes {
+		if route.Dst == nil {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(route.Dst.IP)
+		require.True(t, ok)
+		ones, _ := route.Dst.Mask.Size()
+		prefixes = append(prefixes, netip.PrefixFrom(ip.Unmap(), ones))
+	}
+	return prefixes
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package vpn
+
+import (
+	"context"
+
+	"github.com/tailscale/netlink"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/tstun"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+const tunName = "coder0"
+
+// GetNetworkingStack creates a TUN device, and a router and DNS configurator
+// that configure it with netlink and systemd-resolved respectively. The
+// daemon needs CAP_NET_ADMIN to create the device and permission to manage
+// links over DBus, so it's usually run as root.
+func GetNetworkingStack(_ *Tunnel, _ *StartRequest, logger slog.Logger) (_ NetworkStack, err error) {
+	tunDev, name, err := tstun.New(tailnet.Logger(logger.Named("net.tun.device")), tunName)
+	if err != nil {
+		return NetworkStack{}, xerrors.Errorf("create tun device: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tunDev.Close()
+		}
+	}()
+	logger.Info(context.Background(), "tun created", slog.F("name", name))
+
+	nl, err := netlink.NewHandle()
+	if err != nil {
+		return NetworkStack{}, xerrors.Errorf("create netlink handle: %w", err)
+	}
+	coderRouter, err := newNetlinkRouter(logger.Named("net.router"), nl, name)
+	if err != nil {
+		nl.Delete()
+		return NetworkStack{}, xerrors.Errorf("create router: %w", err)
+	}
+
+	dnsConfigurator, err := newResolvedConfigurator(logger.Named("net.dns"), name)
+	if err != nil {
+		_ = coderRouter.Close()
+		return NetworkStack{}, xerrors.Errorf("create dns configurator: %w", err)
+	}
+
+	return NetworkStack{
+		WireguardMonitor: nil, // default is fine
+		TUNDevice:        tunDev,
+		Router:           coderRouter,
+		DNSConfigurator:  dnsConfigurator,
+	}, nil
+}